@@ -1,12 +1,16 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
 
 	"github.com/musher-dev/mush/internal/auth"
+	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/output"
@@ -28,22 +32,61 @@ func newAuthCmd() *cobra.Command {
 }
 
 func newAuthLoginCmd() *cobra.Command {
+	var (
+		storeName string
+		device    bool
+	)
+
 	cmd := &cobra.Command{
 		Use:   "login",
-		Short: "Authenticate with your API key",
+		Short: "Authenticate with your API key or in a browser",
 		Long: `Authenticate with the Musher platform.
 
 Your API key will be stored securely in your system's keyring
 (macOS Keychain, Windows Credential Manager, or Linux Secret Service).
+Where no keyring is available, it is stored in a credentials file
+readable only by you. Use --store keychain to require the keyring, or
+--store file to keep the key in the file; the choice is saved as
+auth.store. Unless auth.store is file, a key found in the credentials
+file is moved to the keyring the next time it is used.
+
+With --device, no API key is needed: mush prints a code and a URL, you
+approve the code in a browser, and mush stores the token the platform
+issues. The token is renewed automatically before it expires.
+
+With --profile, the credentials are kept for that profile alone, so you
+can stay logged in to several workspaces and switch with --profile or
+MUSH_PROFILE. Logging in to a new profile creates it, pinned to the API
+URL in use.
 
 You can also set the MUSHER_API_KEY environment variable.`,
 		Example: `  mush auth login
+  mush auth login --device
+  mush auth login --store file
+  mush auth login --profile staging --api-url https://api.staging.example.com
   mush --api-key sk-... auth login`,
 		Args: noArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := output.FromContext(cmd.Context())
 			prompter := prompt.New(out)
 
+			if !cmd.Flags().Changed("store") {
+				storeName = config.Load().AuthStore()
+			}
+
+			store, err := auth.ParseStore(storeName)
+			if err != nil {
+				return &clierrors.CLIError{
+					Message: fmt.Sprintf("Invalid credential store %q", storeName),
+					Hint:    "Use auto, keychain, or file",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			if device {
+				return loginWithDevice(cmd, out, store)
+			}
+
 			// Check for API key provided via global --api-key flag (injected as env var)
 			envKey := os.Getenv("MUSHER_API_KEY")
 
@@ -53,10 +96,12 @@ You can also set the MUSHER_API_KEY environment variable.`,
 			} else {
 				// Interactive flow: prompt for API key
 				if !prompter.CanPrompt() {
-					return clierrors.CannotPrompt("MUSHER_API_KEY")
+					return clierrors.PromptRequired("an API key", "--api-key", "MUSHER_API_KEY")
 				}
 
-				var err error
+				// Open the API connection while the user pastes their key.
+				cfg := config.Load()
+				client.StartPrewarm(cmd.Context(), cfg.APIURL(), cfg.CACertFile())
 
 				apiKey, err = prompter.Password("Enter your Musher API key")
 				if err != nil {
@@ -86,23 +131,149 @@ You can also set the MUSHER_API_KEY environment variable.`,
 
 			spin.Stop()
 
-			// Store in keyring
 			cfg := config.Load()
-			if err := auth.StoreAPIKey(cfg.APIURL(), apiKey); err != nil {
+
+			source, err := auth.StoreAPIKeyIn(cfg.APIURL(), apiKey, store)
+			if err != nil {
+				if store == auth.StoreKeyring {
+					return clierrors.ConfigFailed("store credentials", err).
+						WithHint("Use --store file where no system keychain is available")
+				}
+
 				return clierrors.ConfigFailed("store credentials", err)
 			}
 
+			if err := saveLoginProfile(cfg); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("store") {
+				if err := cfg.Set("auth.store", string(store)); err != nil {
+					return clierrors.ConfigFailed("save auth.store", err)
+				}
+			}
+
 			out.Success("Authenticated as %s (Organization: %s)", identity.CredentialName, identity.OrganizationName)
+			out.Muted("API key stored in the %s", source)
 
 			return nil
 		},
 	}
 
+	cmd.Flags().StringVar(&storeName, "store", "", "Where to store the API key: auto, keychain, or file (default: auth.store, else auto)")
+	cmd.Flags().BoolVar(&device, "device", false, "Log in by approving a code in a browser instead of entering an API key")
+
 	return cmd
 }
 
+// loginWithDevice runs the OAuth device authorization flow: it shows the
+// code to approve in a browser, waits for approval, and stores the issued
+// token in store.
+func loginWithDevice(cmd *cobra.Command, out *output.Writer, store auth.Store) error {
+	ctx := cmd.Context()
+
+	apiClient, err := newAPIClientWithKey("")
+	if err != nil {
+		return err
+	}
+
+	device, err := apiClient.StartDeviceAuthorization(ctx)
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitNetwork, "Failed to start device login", err).
+			WithHint("Check your network connection, or run 'mush auth login' with an API key")
+	}
+
+	out.Println("To log in, open this URL in a browser:")
+	out.Println()
+
+	if device.VerificationURIComplete != "" {
+		out.Println("  " + device.VerificationURIComplete)
+	} else {
+		out.Println("  " + device.VerificationURI)
+	}
+
+	out.Println()
+	out.Print("and confirm the code: %s\n\n", device.UserCode)
+
+	spin := out.Spinner("Waiting for approval")
+	spin.Start()
+
+	tok, err := apiClient.PollDeviceToken(ctx, device)
+	if err != nil {
+		spin.StopWithFailure("Device login failed")
+
+		switch {
+		case errors.Is(err, client.ErrDeviceAccessDenied):
+			return clierrors.AuthFailed(err)
+		case errors.Is(err, client.ErrDeviceCodeExpired):
+			return clierrors.Wrap(clierrors.ExitAuth, "Device code expired", err).
+				WithHint("Run 'mush auth login --device' again and approve the code sooner")
+		default:
+			return clierrors.Wrap(clierrors.ExitNetwork, "Failed to complete device login", err)
+		}
+	}
+
+	apiClient.SetOAuthToken(tok, nil)
+
+	identity, err := apiClient.ValidateKey(ctx)
+	if err != nil {
+		spin.StopWithFailure("Token rejected")
+		return clierrors.AuthFailed(err)
+	}
+
+	spin.Stop()
+
+	cfg := config.Load()
+
+	source, err := auth.StoreToken(cfg.APIURL(), tok, store)
+	if err != nil {
+		if store == auth.StoreKeyring {
+			return clierrors.ConfigFailed("store credentials", err).
+				WithHint("Use --store file where no system keychain is available")
+		}
+
+		return clierrors.ConfigFailed("store credentials", err)
+	}
+
+	if err := saveLoginProfile(cfg); err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("store") {
+		if err := cfg.Set("auth.store", string(store)); err != nil {
+			return clierrors.ConfigFailed("save auth.store", err)
+		}
+	}
+
+	out.Success("Authenticated as %s (Organization: %s)", identity.CredentialName, identity.OrganizationName)
+	out.Muted("Token stored in the %s", source)
+
+	if os.Getenv("MUSHER_API_KEY") != "" {
+		out.Println()
+		out.Warning("MUSHER_API_KEY environment variable is set and takes precedence over the token")
+	}
+
+	return nil
+}
+
+// saveLoginProfile records a named profile logged into for the first time,
+// pinned to the API URL it logged into, so later commands with the profile
+// use that platform and 'mush config profiles list' shows it.
+func saveLoginProfile(cfg *config.Config) error {
+	if cfg.Profile() == "" || slices.Contains(cfg.Profiles(), cfg.Profile()) {
+		return nil
+	}
+
+	if err := cfg.Set("api.url", cfg.APIURL()); err != nil {
+		return clierrors.ConfigFailed("save profile "+cfg.Profile(), err)
+	}
+
+	return nil
+}
+
 // AuthStatus represents authentication status for JSON output.
 type AuthStatus struct {
+	Profile      string `json:"profile,omitempty"`
 	Source       string `json:"source"`
 	Credential   string `json:"credential"`
 	Organization string `json:"organization"`
@@ -146,8 +317,11 @@ func newAuthStatusCmd() *cobra.Command {
 				traceID = meta.TraceID
 			}
 
+			profile := config.Load().Profile()
+
 			if out.JSON {
 				if err := out.PrintJSON(AuthStatus{
+					Profile:      profile,
 					Source:       string(source),
 					Credential:   identity.CredentialName,
 					Organization: identity.OrganizationName,
@@ -160,6 +334,10 @@ func newAuthStatusCmd() *cobra.Command {
 				return nil
 			}
 
+			if profile != "" {
+				out.Print("Profile:    %s\n", profile)
+			}
+
 			out.Print("Source:     %s\n", source)
 			out.Print("Credential: %s\n", identity.CredentialName)
 			out.Print("Organization: %s\n", identity.OrganizationName)
@@ -0,0 +1,119 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/bench"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+)
+
+func newBenchCmd() *cobra.Command {
+	var (
+		jobs        int
+		harnessType string
+		command     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Benchmark the job engine with synthetic jobs",
+		Long: `Run synthetic jobs through the real job engine against an in-process mock
+platform and report claim latency, throughput, and per-job overhead
+percentiles. Nothing is sent to the Musher platform.
+
+The bash harness runs --command for each job (on Windows it needs bash on
+PATH, such as Git Bash); the noop harness returns immediately, isolating
+engine and API overhead. Compare results across
+releases to catch performance regressions. Exits non-zero if any job fails.`,
+		Example: `  mush bench
+  mush bench --jobs 500 --harness noop
+  mush bench --harness bash --command 'sleep 0.01' --json`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			if jobs <= 0 {
+				return clierrors.New(clierrors.ExitUsage, "--jobs must be a positive number")
+			}
+
+			if !slices.Contains(bench.Harnesses, harnessType) {
+				return clierrors.New(clierrors.ExitUsage, fmt.Sprintf("Unsupported bench harness: %s", harnessType)).
+					WithHint(fmt.Sprintf("Available: %s", joinNames(bench.Harnesses)))
+			}
+
+			spin := out.Spinner(fmt.Sprintf("Running %d %s jobs", jobs, harnessType))
+			spin.Start()
+
+			report, err := bench.Run(cmd.Context(), &bench.Options{Jobs: jobs, Harness: harnessType, Command: command})
+
+			spin.Stop()
+
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Benchmark failed", err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(report); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write benchmark report", err)
+				}
+			} else {
+				printBenchReport(out, report)
+			}
+
+			if report.Failed > 0 {
+				return clierrors.New(clierrors.ExitGeneral, fmt.Sprintf("%d of %d benchmark jobs failed", report.Failed, report.Jobs)).
+					WithHint("Check --command; it must exit 0")
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().IntVar(&jobs, "jobs", 100, "Number of synthetic jobs to run")
+	cmd.Flags().StringVar(&harnessType, "harness", bench.HarnessBash, "Synthetic harness: bash, noop")
+	cmd.Flags().StringVar(&command, "command", "true", "Script each bash job runs")
+
+	return cmd
+}
+
+func printBenchReport(out *output.Writer, report *bench.Report) {
+	out.Print("%d %s jobs in %s (%.1f jobs/s): %d completed, %d failed\n\n",
+		report.Jobs, report.Harness, report.Duration.Round(time.Millisecond), report.Throughput,
+		report.Completed, report.Failed)
+
+	out.Print("%-10s %10s %10s %10s %10s\n", "", "p50", "p90", "p99", "max")
+
+	rows := []struct {
+		label string
+		p     bench.Percentiles
+	}{
+		{label: "claim", p: report.ClaimLatency},
+		{label: "execution", p: report.Execution},
+		{label: "overhead", p: report.Overhead},
+	}
+
+	for _, row := range rows {
+		out.Print("%-10s %10s %10s %10s %10s\n", row.label,
+			formatBenchDuration(row.p.P50), formatBenchDuration(row.p.P90),
+			formatBenchDuration(row.p.P99), formatBenchDuration(row.p.Max))
+	}
+}
+
+// formatBenchDuration rounds to a precision that stays readable from
+// microseconds up to seconds.
+func formatBenchDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	default:
+		return d.Round(time.Microsecond).String()
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !unix && !windows
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+)
+
+func newBenchCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "bench",
+		Short:   "Benchmark the job engine with synthetic jobs",
+		Long:    `The job engine benchmark is currently supported only on Unix-like systems.`,
+		Example: `  mush bench`,
+		Args:    noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return &clierrors.CLIError{
+				Message: "Bench is not supported on this operating system",
+				Hint:    "Run Mush on a Unix-like OS (macOS/Linux) to use 'mush bench'",
+				Code:    clierrors.ExitUsage,
+			}
+		},
+	}
+}
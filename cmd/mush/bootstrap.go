@@ -12,11 +12,13 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/musher-dev/mush/internal/auth"
+	"github.com/musher-dev/mush/internal/bundle"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/observability"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
 	"github.com/musher-dev/mush/internal/tui/nav"
 )
 
@@ -44,11 +46,17 @@ func configureRootRuntime(
 		out.SetNoColor(true)
 	}
 
+	// A profile given with --terminal-profile is applied by the commands
+	// that take it.
+	if profile, err := terminal.ResolveProfile(config.Load().TerminalProfile()); err == nil {
+		applyTerminalColor(out, profile)
+	}
+
 	logCfg := observability.Config{
-		Level:       pickFlagOrEnv(logLevel, "MUSH_LOG_LEVEL", "info"),
+		Level:       pickFlagOrEnv(logLevel, "MUSH_LOG_LEVEL", configuredLogLevel()),
 		Format:      pickFlagOrEnv(logFormat, "MUSH_LOG_FORMAT", "json"),
 		LogFile:     pickFlagOrEnv(logFile, "MUSH_LOG_FILE", ""),
-		StderrMode:  pickFlagOrEnv(logStderr, "MUSH_LOG_STDERR", "auto"),
+		StderrMode:  logStderrMode(cmd, pickFlagOrEnv(logStderr, "MUSH_LOG_STDERR", "auto")),
 		SessionID:   uuid.NewString(),
 		CommandPath: cmd.CommandPath(),
 		Version:     version,
@@ -146,6 +154,29 @@ func pickFlagOrEnv(flagValue, envKey, fallback string) string {
 	return fallback
 }
 
+// logStderrMode resolves the auto --log-stderr mode: a headless worker has no
+// UI, so its logs go to stderr where service managers collect them.
+func logStderrMode(cmd *cobra.Command, mode string) string {
+	if !strings.EqualFold(mode, "auto") {
+		return mode
+	}
+
+	if headless, err := cmd.Flags().GetBool("headless"); err == nil && headless {
+		return "on"
+	}
+
+	return mode
+}
+
+// configuredLogLevel returns the log.level config setting, defaulting to info.
+func configuredLogLevel() string {
+	if level := config.Load().LogLevel(); level != "" {
+		return level
+	}
+
+	return "info"
+}
+
 // experimentalOn returns true if experimental features are enabled via flag, env, or config.
 func experimentalOn() bool {
 	if pickBoolFlagOrEnv(false, "MUSH_EXPERIMENTAL", "MUSH_EXPERIMENTAL") {
@@ -186,6 +217,9 @@ func buildTUIDeps() *nav.Dependencies {
 	httpClient, err := client.NewInstrumentedHTTPClient(cfg.CACertFile())
 	if err == nil {
 		deps.Client = client.NewWithHTTPClient(cfg.APIURL(), apiKey, httpClient)
+		deps.Client.SetResponseCache(bundle.NewETagCache())
+		deps.Client.SetIdentityCache(newIdentityCache(cfg.APIURL()))
+		useStoredToken(deps.Client, cfg.APIURL(), apiKey)
 	}
 
 	if wd, err := os.Getwd(); err == nil {
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
@@ -34,7 +34,11 @@ directory structure.`,
 	cmd.AddCommand(newBundleInstallCmd())
 	cmd.AddCommand(newBundleListCmd())
 	cmd.AddCommand(newBundleInfoCmd())
+	cmd.AddCommand(newBundleLintCmd())
+	cmd.AddCommand(newBundleFixFrontmatterCmd())
 	cmd.AddCommand(newBundleUninstallCmd())
+	cmd.AddCommand(newBundleExportCmd())
+	cmd.AddCommand(newBundleImportCmd())
 
 	return cmd
 }
@@ -316,8 +320,7 @@ Lists the files that will be removed and prompts for confirmation unless
 
 			if !force {
 				if out.NoInput {
-					return clierrors.New(clierrors.ExitUsage, "Cannot confirm uninstall in non-interactive mode").
-						WithHint("Use --force to skip confirmation")
+					return clierrors.ConfirmationRequired("uninstall")
 				}
 
 				prompter := prompt.New(out)
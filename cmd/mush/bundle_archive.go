@@ -0,0 +1,154 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+func newBundleExportCmd() *cobra.Command {
+	var outputPath string
+
+	cmd := &cobra.Command{
+		Use:   "export <namespace/slug>:<version>",
+		Short: "Write a cached bundle to a portable archive",
+		Long: `Write a cached bundle version (manifest, assets, and checksums) to a
+zstd-compressed tar archive for transfer to machines without platform access.
+
+The bundle must already be in the local cache; pull it first with
+'mush bundle load' or 'mush bundle install'. Restore the archive elsewhere
+with 'mush bundle import'.`,
+		Example: `  mush bundle export acme/my-kit:1.2.0
+  mush bundle export acme/my-kit:1.2.0 -o kit.tar.zst`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			ref, err := bundle.ParseRef(strings.TrimSpace(args[0]))
+			if err != nil {
+				return &clierrors.CLIError{
+					Message: err.Error(),
+					Hint:    "Use: mush bundle export <namespace/slug>:<version>",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			if ref.Version == "" {
+				return clierrors.New(clierrors.ExitUsage, "Bundle export requires an explicit version").
+					WithHint("Run 'mush bundle list' to see cached versions")
+			}
+
+			if !bundle.IsCached(ref.Namespace, ref.Slug, ref.Version) {
+				return clierrors.New(clierrors.ExitUsage, fmt.Sprintf("Bundle %s is not cached", ref)).
+					WithHint(fmt.Sprintf("Pull it first with 'mush bundle install %s --harness <type>', or run 'mush bundle list'", ref))
+			}
+
+			if outputPath == "" {
+				outputPath = fmt.Sprintf("%s-%s-%s.tar.zst", ref.Namespace, ref.Slug, ref.Version)
+			}
+
+			info, err := writeBundleArchive(ref, outputPath)
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to export bundle", err)
+			}
+
+			out.Success("Exported %s (%d assets) to %s", ref, info.AssetCount, outputPath)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Archive path (default: <namespace>-<slug>-<version>.tar.zst)")
+
+	return cmd
+}
+
+// writeBundleArchive exports ref to a temporary file beside outputPath and
+// renames it into place, so an interrupted export never leaves a truncated archive.
+func writeBundleArchive(ref bundle.Ref, outputPath string) (*bundle.ArchiveInfo, error) {
+	tmpFile, err := os.CreateTemp(filepath.Dir(outputPath), filepath.Base(outputPath)+".*.tmp")
+	if err != nil {
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "create archive", err)
+	}
+
+	tmp := tmpFile.Name()
+
+	info, err := bundle.ExportArchive(ref, tmpFile)
+	if err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmp)
+
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "write archive", err)
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "close archive", err)
+	}
+
+	if err := os.Rename(tmp, outputPath); err != nil {
+		_ = os.Remove(tmp)
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "move archive into place", err)
+	}
+
+	return info, nil
+}
+
+func newBundleImportCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Add a bundle archive to the local cache",
+		Long: `Verify a bundle archive created by 'mush bundle export' and add it to the
+local bundle cache. Every file is checked against the archive checksums and
+every asset against the bundle manifest before anything is written.
+
+Once imported, the bundle can be loaded or installed without platform access.
+An already cached version is kept unless --force is passed.`,
+		Example: `  mush bundle import kit.tar.zst
+  mush bundle import kit.tar.zst --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			file, err := safeio.Open(args[0])
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitUsage, "Failed to open bundle archive", err)
+			}
+			defer file.Close()
+
+			info, cachePath, err := bundle.ImportArchive(file, force)
+			if err != nil {
+				switch {
+				case errors.Is(err, bundle.ErrAlreadyCached):
+					return clierrors.New(clierrors.ExitUsage, fmt.Sprintf("Bundle %s is already cached", info.Ref())).
+						WithHint("Use --force to replace the cached copy")
+				case errors.Is(err, bundle.ErrArchiveIntegrity):
+					return clierrors.Wrap(clierrors.ExitGeneral, "Bundle archive is corrupt or was modified", err).
+						WithHint("Export the bundle again on the source machine and re-copy the archive")
+				default:
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to import bundle archive", err)
+				}
+			}
+
+			out.Success("Imported %s (%d assets)", info.Ref(), info.AssetCount)
+			out.Muted("Cached at %s", cachePath)
+
+			return nil
+		},
+	}
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Replace an already cached version")
+
+	return cmd
+}
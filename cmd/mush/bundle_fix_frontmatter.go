@@ -0,0 +1,189 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// frontmatterBackupSuffix is appended to a file's name for the copy kept
+// before it is rewritten.
+const frontmatterBackupSuffix = ".bak"
+
+func newBundleFixFrontmatterCmd() *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "fix-frontmatter <path>",
+		Short: "Repair YAML frontmatter in skill and agent files",
+		Long: `Repair the YAML frontmatter of Markdown skill and agent files in place,
+applying the same fix mush makes when loading a bundle: top-level values
+that contain ": " are quoted. Claude tolerates this mistake but strict
+harnesses such as Codex reject the asset.
+
+The path may be a file or a directory, which is searched for .md files.
+Each rewritten file is first copied to <file>.bak, and the changed lines
+are printed as a diff. Files whose frontmatter cannot be repaired
+automatically are listed with an explanation and left untouched.`,
+		Example: `  mush bundle fix-frontmatter ./my-bundle
+  mush bundle fix-frontmatter ./my-bundle/skills/deploy/SKILL.md
+  mush bundle fix-frontmatter ./my-bundle --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			files, err := markdownFiles(args[0])
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitUsage, "Cannot read "+args[0], err)
+			}
+
+			var repaired, unrepairable int
+
+			for _, path := range files {
+				fixed, err := fixFrontmatterFile(out, path, dryRun)
+				if err != nil {
+					var fmErr *bundle.FrontmatterError
+					if !errors.As(err, &fmErr) {
+						return clierrors.Wrap(clierrors.ExitGeneral, "Failed to repair "+path, err)
+					}
+
+					unrepairable++
+
+					out.Failure("%s: %v", path, fmErr.Cause)
+					out.Muted("  %s", fmErr.Hint)
+
+					continue
+				}
+
+				if fixed {
+					repaired++
+				}
+			}
+
+			switch {
+			case repaired == 0 && unrepairable == 0:
+				out.Success("No frontmatter to repair in %d files", len(files))
+			case dryRun:
+				out.Info("%d files would be repaired (dry run, nothing written)", repaired)
+			default:
+				out.Success("Repaired %d files", repaired)
+			}
+
+			if unrepairable > 0 {
+				return &clierrors.CLIError{
+					Message: fmt.Sprintf("%d files have frontmatter that needs fixing by hand", unrepairable),
+					Hint:    "Edit the files listed above, then run 'mush bundle lint' to check the bundle",
+					Code:    clierrors.ExitGeneral,
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show the changes without writing any files")
+
+	return cmd
+}
+
+// fixFrontmatterFile repairs one file, printing the lines it changes. It
+// reports whether the file needed repair; unrepairable frontmatter is
+// returned as a *bundle.FrontmatterError.
+func fixFrontmatterFile(out *output.Writer, path string, dryRun bool) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, clierrors.Wrap(clierrors.ExitGeneral, "stat", err)
+	}
+
+	data, err := safeio.ReadFile(path)
+	if err != nil {
+		return false, clierrors.Wrap(clierrors.ExitGeneral, "read", err)
+	}
+
+	repaired, changes, err := bundle.FixFrontmatter(data)
+	if err != nil {
+		return false, err
+	}
+
+	if len(changes) == 0 {
+		return false, nil
+	}
+
+	if dryRun {
+		out.Info("%s", path)
+	} else {
+		backup := path + frontmatterBackupSuffix
+		if _, statErr := os.Stat(backup); statErr == nil {
+			return false, clierrors.New(clierrors.ExitGeneral, fmt.Sprintf("backup %s already exists; move it away and run again", backup))
+		}
+
+		if err := safeio.WriteFile(backup, data, info.Mode().Perm()); err != nil {
+			return false, clierrors.Wrap(clierrors.ExitGeneral, "write backup", err)
+		}
+
+		if err := safeio.WriteFile(path, repaired, info.Mode().Perm()); err != nil {
+			return false, clierrors.Wrap(clierrors.ExitGeneral, "write", err)
+		}
+
+		out.Success("%s (backup: %s)", path, filepath.Base(backup))
+	}
+
+	for _, change := range changes {
+		out.Print("  -%d: %s\n", change.Line, change.Old)
+		out.Print("  +%d: %s\n", change.Line, change.New)
+	}
+
+	return true, nil
+}
+
+// markdownFiles returns path itself when it is a file, or the .md files
+// under it when it is a directory, skipping hidden directories such as .git.
+func markdownFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "stat", err)
+	}
+
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	var files []string
+
+	err = filepath.WalkDir(path, func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+
+		if d.IsDir() {
+			if p != path && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if strings.EqualFold(filepath.Ext(p), ".md") {
+			files = append(files, p)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "walk "+path, err)
+	}
+
+	return files, nil
+}
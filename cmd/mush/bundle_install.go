@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
@@ -95,7 +95,7 @@ Alternatively, install from a local directory with --dir.`,
 				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to get working directory", err)
 			}
 
-			installedPaths, installErr := bundle.InstallFromCache(workDir, source.CachePath, &source.Resolved.Manifest, mapper, force)
+			installResult, installErr := bundle.InstallFromCache(workDir, source.CachePath, &source.Resolved.Manifest, mapper, force)
 			if installErr != nil {
 				var conflict *bundle.InstallConflictError
 				if errors.As(installErr, &conflict) {
@@ -108,18 +108,19 @@ Alternatively, install from a local directory with --dir.`,
 				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to install bundle assets", installErr)
 			}
 
-			for _, relPath := range installedPaths {
+			for _, relPath := range installResult.Paths {
 				out.Success("Installed: %s", relPath)
 			}
 
 			trackErr := bundle.TrackInstall(workDir, &bundle.InstalledBundle{
-				Namespace: source.Ref.Namespace,
-				Slug:      source.Ref.Slug,
-				Ref:       source.Ref.Namespace + "/" + source.Ref.Slug,
-				Version:   source.Resolved.Version,
-				Harness:   normalized,
-				Assets:    installedPaths,
-				Timestamp: time.Now(),
+				Namespace:     source.Ref.Namespace,
+				Slug:          source.Ref.Slug,
+				Ref:           source.Ref.Namespace + "/" + source.Ref.Slug,
+				Version:       source.Resolved.Version,
+				Harness:       normalized,
+				Assets:        installResult.Paths,
+				MergedConfigs: installResult.MergedConfigs,
+				Timestamp:     time.Now(),
 			})
 			if trackErr != nil {
 				out.Warning("Failed to track installation: %v", trackErr)
@@ -127,7 +128,7 @@ Alternatively, install from a local directory with --dir.`,
 
 			out.Println()
 			out.Success("Installed %d assets from %s v%s", len(source.Resolved.Manifest.Layers), source.Ref.Slug, source.Resolved.Version)
-			logger.Info("bundle install completed", slog.String("bundle.version", source.Resolved.Version), slog.Int("bundle.asset_count", len(installedPaths)))
+			logger.Info("bundle install completed", slog.String("bundle.version", source.Resolved.Version), slog.Int("bundle.asset_count", len(installResult.Paths)))
 
 			return nil
 		},
@@ -0,0 +1,154 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/output"
+)
+
+// bundleLintReport is the --json output of bundle lint.
+type bundleLintReport struct {
+	Bundle   string             `json:"bundle"`
+	Version  string             `json:"version"`
+	Assets   int                `json:"assets"`
+	Errors   int                `json:"errors"`
+	Warnings int                `json:"warnings"`
+	Issues   []bundle.LintIssue `json:"issues"`
+}
+
+func newBundleLintCmd() *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "lint <dir|namespace/slug[:version]>",
+		Short: "Check bundle assets for problems before publishing",
+		Long: `Validate a bundle from a local directory or the registry: skill
+frontmatter, agent definition structure, tool and MCP config syntax, asset
+paths, and whether the manifest matches the files it lists.
+
+Errors are problems that stop a harness from using an asset. Warnings are
+assets some harnesses accept and others reject or ignore, such as skill
+frontmatter that mush repairs when loading but strict harnesses reject.
+
+Exits non-zero when any errors are found, or any warnings with --strict.`,
+		Example: `  mush bundle lint ./my-bundle
+  mush bundle lint acme/my-kit:0.1.0
+  mush bundle lint ./my-bundle --strict --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			logger := observability.FromContext(cmd.Context()).With(
+				slog.String("component", "bundle"),
+				slog.String("event.type", "bundle.lint"),
+			)
+
+			target := strings.TrimSpace(args[0])
+
+			opts := bundleSourceOptions{refArg: target}
+			if info, err := os.Stat(target); err == nil && info.IsDir() {
+				opts = bundleSourceOptions{dirPath: target}
+			}
+
+			source, err := resolveBundleSource(cmd.Context(), out, logger, opts)
+			if err != nil {
+				return err
+			}
+			defer source.Cleanup()
+
+			issues := bundle.Lint(source.CachePath, &source.Resolved.Manifest)
+
+			report := bundleLintReport{
+				Bundle:  source.Ref.Namespace + "/" + source.Ref.Slug,
+				Version: source.Resolved.Version,
+				Assets:  len(source.Resolved.Manifest.Layers),
+				Issues:  issues,
+			}
+
+			for _, issue := range issues {
+				if issue.Severity == bundle.LintError {
+					report.Errors++
+				} else {
+					report.Warnings++
+				}
+			}
+
+			if report.Issues == nil {
+				report.Issues = []bundle.LintIssue{}
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(report); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write lint report", err)
+				}
+			} else {
+				printLintIssues(out, &report)
+			}
+
+			logger.Info("bundle linted",
+				slog.String("bundle.ref", report.Bundle),
+				slog.Int("bundle.asset_count", report.Assets),
+				slog.Int("lint.errors", report.Errors),
+				slog.Int("lint.warnings", report.Warnings),
+			)
+
+			if report.Errors > 0 || (strict && report.Warnings > 0) {
+				return &clierrors.CLIError{
+					Message: fmt.Sprintf("Bundle lint found %s", lintCounts(report.Errors, report.Warnings)),
+					Hint:    "Fix the assets listed above and run 'mush bundle lint' again",
+					Code:    clierrors.ExitGeneral,
+				}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail on warnings as well as errors")
+
+	return cmd
+}
+
+func printLintIssues(out *output.Writer, report *bundleLintReport) {
+	for _, issue := range report.Issues {
+		where := issue.Path
+		if where == "" {
+			where = "manifest"
+		}
+
+		if issue.Severity == bundle.LintError {
+			out.Failure("%s: %s", where, issue.Message)
+		} else {
+			out.Warning("%s: %s", where, issue.Message)
+		}
+	}
+
+	if len(report.Issues) == 0 {
+		out.Success("%s: %d assets, no issues found", report.Bundle, report.Assets)
+		return
+	}
+
+	out.Println()
+	out.Print("%s: %d assets, %s\n", report.Bundle, report.Assets, lintCounts(report.Errors, report.Warnings))
+}
+
+func lintCounts(errs, warnings int) string {
+	count := func(n int, noun string) string {
+		if n == 1 {
+			return "1 " + noun
+		}
+
+		return fmt.Sprintf("%d %ss", n, noun)
+	}
+
+	return count(errs, "error") + ", " + count(warnings, "warning")
+}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
@@ -15,6 +15,7 @@ import (
 	"github.com/musher-dev/mush/internal/client"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 	"github.com/musher-dev/mush/internal/observability"
 	"github.com/musher-dev/mush/internal/output"
 	"github.com/musher-dev/mush/internal/tui/nav"
@@ -24,9 +25,11 @@ func newBundleLoadCmd() *cobra.Command {
 	var (
 		harnessType  string
 		forceSidebar bool
+		termProfile  string
 		dirPath      string
 		useSample    bool
 		cacheOnly    bool
+		extraDirs    []string
 	)
 
 	cmd := &cobra.Command{
@@ -40,13 +43,23 @@ Use --cache to download and cache a bundle without launching a session.
 This is useful for pre-warming the bundle cache in CI or container builds.
 
 Alternatively, load a bundle from a local directory with --dir or use the
-built-in sample bundle with --sample for testing.`,
+built-in sample bundle with --sample for testing.
+
+While the session runs, press F5 to reload: the bundle is read from --dir
+again, or pulled again from the registry, and its assets and tool config are
+refreshed in place. Harnesses that only read them at startup are restarted.
+
+Use --extra-dir to expose more directories, such as a shared team skills
+directory, alongside the bundle. Directories are passed in order after the
+bundle, and when two provide a skill or agent with the same name the earlier
+one wins. Only harnesses that accept repeated --add-dir flags support this.`,
 		Example: `  mush bundle load acme/my-kit
   mush bundle load acme/my-kit:0.1.0
   mush bundle load acme/my-kit --cache
   mush bundle load acme/my-kit --no-tui --harness claude
   mush bundle load --dir ./my-bundle --no-tui --harness claude
-  mush bundle load --sample --no-tui --harness claude`,
+  mush bundle load --sample --no-tui --harness claude
+  mush bundle load acme/my-kit --no-tui --harness claude --extra-dir ~/team-skills`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			hasDir := cmd.Flags().Changed("dir") && dirPath != ""
 			hasSample := cmd.Flags().Changed("sample") && useSample
@@ -84,6 +97,12 @@ built-in sample bundle with --sample for testing.`,
 			noTUI, _ := cmd.Root().PersistentFlags().GetBool("no-tui")
 			useTUI := shouldShowTUI(noTUI, out)
 
+			if !cacheOnly {
+				if err := applyTerminalProfile(out, termProfile); err != nil {
+					return err
+				}
+			}
+
 			if !cacheOnly && !useTUI && harnessType == "" {
 				return &clierrors.CLIError{
 					Message: "Harness type is required in --no-tui mode",
@@ -92,11 +111,13 @@ built-in sample bundle with --sample for testing.`,
 				}
 			}
 
-			source, err := resolveBundleSource(cmd.Context(), out, logger, bundleSourceOptions{
+			sourceOpts := bundleSourceOptions{
 				dirPath:   dirPath,
 				useSample: useSample,
 				refArg:    firstArg(args),
-			})
+			}
+
+			source, err := resolveBundleSource(cmd.Context(), out, logger, sourceOpts)
 			if err != nil {
 				return err
 			}
@@ -114,15 +135,17 @@ built-in sample bundle with --sample for testing.`,
 				return nil
 			}
 
-			return executeBundleLoad(cmd, out, logger, source, harnessType, forceSidebar, useTUI)
+			return executeBundleLoad(cmd, out, logger, source, sourceOpts, harnessType, forceSidebar, useTUI)
 		},
 	}
 
 	cmd.Flags().StringVar(&harnessType, "harness", "", "Harness type to use (required with --no-tui)")
-	cmd.Flags().BoolVar(&forceSidebar, "force-sidebar", false, "Skip terminal probe and force sidebar rendering")
+	cmd.Flags().BoolVar(&forceSidebar, "force-sidebar", false, "Show the sidebar even when the terminal profile hides it")
+	addTerminalProfileFlag(cmd, &termProfile)
 	cmd.Flags().StringVar(&dirPath, "dir", "", "Load bundle from a local directory")
 	cmd.Flags().BoolVar(&useSample, "sample", false, "Load the built-in sample bundle")
 	cmd.Flags().BoolVar(&cacheOnly, "cache", false, "Download and cache the bundle without launching a session")
+	cmd.Flags().StringArrayVar(&extraDirs, "extra-dir", nil, "Additional directory to expose after the bundle (repeatable)")
 	cmd.MarkFlagsMutuallyExclusive("dir", "sample")
 
 	return cmd
@@ -134,6 +157,7 @@ func executeBundleLoad(
 	out *output.Writer,
 	logger *slog.Logger,
 	source *bundleSourceResult,
+	sourceOpts bundleSourceOptions,
 	harnessType string,
 	forceSidebar bool,
 	useTUI bool,
@@ -200,6 +224,10 @@ func executeBundleLoad(
 	}
 	defer session.Cleanup()
 
+	if err := addExtraDirs(cmd, session, spec); err != nil {
+		return err
+	}
+
 	for _, w := range session.Warnings {
 		out.Warning("%s", w)
 	}
@@ -221,7 +249,7 @@ func executeBundleLoad(
 
 	_, apiClient, _, apiErr := tryAPIClient()
 	if apiErr == nil && apiClient != nil && apiClient.IsAuthenticated() {
-		runnerConfig, err = apiClient.GetRunnerConfig(cmd.Context())
+		runnerConfig, err = apiClient.GetRunnerConfig(cmd.Context(), "")
 		if err != nil {
 			out.Warning("Runner config unavailable, continuing without MCP provisioning: %v", err)
 		}
@@ -237,10 +265,18 @@ func executeBundleLoad(
 		BundleName:         source.Ref.Slug,
 		BundleVer:          source.Resolved.Version,
 		BundleDir:          session.BundleDir,
+		BundleExtraDirs:    session.ExtraDirs,
 		BundleWorkDir:      session.WorkingDir,
 		BundleEnv:          session.Env,
 		RunnerConfig:       runnerConfig,
 		BundleSummary:      harness.SummarizeBundleManifest(&source.Resolved.Manifest),
+		BundleReload: (&bundleReloader{
+			source:  sourceOpts,
+			session: session,
+			spec:    spec,
+			mapper:  mapper,
+			logger:  logger,
+		}).Reload,
 	}
 
 	if err := harness.Run(ctx, cfg); err != nil {
@@ -258,6 +294,19 @@ func executeBundleLoad(
 	return nil
 }
 
+// addExtraDirs applies the --extra-dir flags to a prepared load session.
+// Commands without the flag add nothing.
+func addExtraDirs(cmd *cobra.Command, session *bundle.LoadSession, spec *harnesstype.ProviderSpec) error {
+	dirs, _ := cmd.Flags().GetStringArray("extra-dir")
+
+	if err := session.AddExtraDirs(spec, dirs); err != nil {
+		return clierrors.Wrap(clierrors.ExitUsage, "Invalid --extra-dir", err).
+			WithHint("Pass existing directories, and a harness that accepts several (claude, codex, copilot)")
+	}
+
+	return nil
+}
+
 func firstArg(args []string) string {
 	if len(args) == 0 {
 		return ""
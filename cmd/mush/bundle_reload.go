@@ -0,0 +1,79 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/tui/nav"
+)
+
+// bundleReloader refreshes a running bundle load session from its source,
+// so bundle authors can test edits without restarting the harness.
+type bundleReloader struct {
+	source  bundleSourceOptions
+	session *bundle.LoadSession
+	spec    *harnesstype.ProviderSpec
+	mapper  bundle.AssetMapper
+	logger  *slog.Logger
+}
+
+// Reload resolves the bundle again (re-reading a local directory, or pulling
+// the reference so an unpinned one picks up a newly published version) and
+// re-prepares the session's assets and tool config in place.
+func (l *bundleReloader) Reload(ctx context.Context) (*harness.BundleReload, error) {
+	// The harness owns the terminal; pull progress would draw over it.
+	quiet, _ := output.NewCapture()
+
+	source, err := resolveBundleSource(ctx, quiet, l.logger, l.source)
+	if err != nil {
+		return nil, err
+	}
+	defer source.Cleanup()
+
+	if err := l.session.Reload(ctx, source.CachePath, &source.Resolved.Manifest, l.spec, l.mapper); err != nil {
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "refresh bundle assets", err)
+	}
+
+	return &harness.BundleReload{
+		Version:  source.Resolved.Version,
+		Summary:  harness.SummarizeBundleManifest(&source.Resolved.Manifest),
+		Warnings: append([]string(nil), l.session.Warnings...),
+	}, nil
+}
+
+// navBundleSource returns how to resolve a bundle launched from the TUI
+// again: the --dir, --sample, or reference the command was run with, or
+// otherwise the bundle the TUI picked.
+func navBundleSource(cmd *cobra.Command, result *nav.Result) (bundleSourceOptions, bool) {
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		return bundleSourceOptions{dirPath: dir}, true
+	}
+
+	if sample, _ := cmd.Flags().GetBool("sample"); sample {
+		return bundleSourceOptions{useSample: true}, true
+	}
+
+	if ref := cmd.Flags().Arg(0); ref != "" {
+		return bundleSourceOptions{refArg: ref}, true
+	}
+
+	if result.BundleNamespace == "" || result.BundleSlug == "" {
+		return bundleSourceOptions{}, false
+	}
+
+	ref := result.BundleNamespace + "/" + result.BundleSlug
+	if result.BundleVer != "" {
+		ref += ":" + result.BundleVer
+	}
+
+	return bundleSourceOptions{refArg: ref}, true
+}
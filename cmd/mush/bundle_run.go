@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
@@ -183,7 +183,7 @@ func provisionMCPConfig(
 		return "", nil
 	}
 
-	runnerConfig, rcErr := apiClient.GetRunnerConfig(ctx)
+	runnerConfig, rcErr := apiClient.GetRunnerConfig(ctx, "")
 	if rcErr != nil {
 		out.Warning("Runner config unavailable, continuing without MCP provisioning: %v", rcErr)
 		return "", nil
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
@@ -50,6 +50,11 @@ func resolveBundleSource(
 
 		logger.Info("bundle loaded from local directory", slog.String("bundle.dir", opts.dirPath))
 
+		if err := checkBundleManifest(out, logger, resolved); err != nil {
+			cleanup()
+			return nil, err
+		}
+
 		return &bundleSourceResult{
 			Kind:      bundleSourceDir,
 			Resolved:  resolved,
@@ -67,6 +72,11 @@ func resolveBundleSource(
 
 		logger.Info("sample bundle extracted")
 
+		if err := checkBundleManifest(out, logger, resolved); err != nil {
+			cleanup()
+			return nil, err
+		}
+
 		return &bundleSourceResult{
 			Kind:      bundleSourceSample,
 			Resolved:  resolved,
@@ -127,10 +137,14 @@ func resolveBundleSource(
 			}
 		}
 
-		return nil, clierrors.Wrap(clierrors.ExitNetwork, "Failed to pull bundle", err).
+		return nil, clierrors.NetworkFailed("Failed to pull bundle", err).
 			WithHint("Check your network connection and bundle reference.\nSearch for available bundles with 'mush hub search <query>'")
 	}
 
+	if err := checkBundleManifest(out, logger, resolved); err != nil {
+		return nil, err
+	}
+
 	return &bundleSourceResult{
 		Kind:      bundleSourceRemote,
 		Resolved:  resolved,
@@ -139,3 +153,30 @@ func resolveBundleSource(
 		Cleanup:   func() {},
 	}, nil
 }
+
+// checkBundleManifest upgrades the resolved manifest to the schema this build
+// understands, warning about assets it skips, and rejects manifests from a
+// newer schema with a hint to update.
+func checkBundleManifest(out *output.Writer, logger *slog.Logger, resolved *client.BundleResolveResponse) error {
+	warnings, err := bundle.CheckManifest(&resolved.Manifest)
+	if err != nil {
+		var versionErr *bundle.ManifestVersionError
+		if errors.As(err, &versionErr) {
+			return &clierrors.CLIError{
+				Message: fmt.Sprintf("Bundle %s/%s v%s needs a newer version of mush", resolved.Namespace, resolved.Slug, resolved.Version),
+				Hint:    "Run 'mush update' to install the latest release",
+				Cause:   err,
+				Code:    clierrors.ExitGeneral,
+			}
+		}
+
+		return clierrors.Wrap(clierrors.ExitGeneral, "Invalid bundle manifest", err)
+	}
+
+	for _, warning := range warnings {
+		logger.Warn("bundle asset skipped", slog.String("warning", warning))
+		out.Warning("%s", warning)
+	}
+
+	return nil
+}
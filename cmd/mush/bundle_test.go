@@ -16,6 +16,9 @@ import (
 )
 
 func TestBundleLoadRequiresHarness(t *testing.T) {
+	// Loading applies the terminal profile to the process environment.
+	t.Setenv(terminalProfileEnv, "")
+
 	term := &terminal.Info{IsTTY: true}
 	out := output.NewWriter(io.Discard, io.Discard, term)
 	out.NoInput = true
@@ -18,12 +18,17 @@ func newConfigCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage configuration",
-		Long:  `View and modify Mush configuration settings.`,
+		Long: `View and modify Mush configuration settings.
+
+Settings apply to the active profile, selected with --profile or
+MUSH_PROFILE. A named profile's settings override the default profile's,
+so 'mush config set --profile prod api.url ...' changes only prod.`,
 	}
 
 	cmd.AddCommand(newConfigListCmd())
 	cmd.AddCommand(newConfigGetCmd())
 	cmd.AddCommand(newConfigSetCmd())
+	cmd.AddCommand(newConfigProfilesCmd())
 
 	return cmd
 }
@@ -57,6 +62,7 @@ func newConfigListCmd() *cobra.Command {
 
 				out.Print("  api.url       Platform API URL (default: %s)\n", config.DefaultAPIURL)
 				out.Print("  worker.poll_interval   Poll interval (default: %s)\n", config.DefaultPollInterval)
+				out.Print("  worker.poll_interval_max   Idle poll interval ceiling (default: %s)\n", config.DefaultPollIntervalMax)
 				out.Print("  history.enabled   Enable PTY transcript capture (default: true)\n")
 				out.Print("  history.dir       Transcript storage directory (default: %s)\n", historyDir)
 				out.Print("  history.scrollback_lines  In-memory scrollback lines per session (default: 10000)\n")
@@ -111,11 +117,13 @@ func newConfigGetCmd() *cobra.Command {
 
 func newConfigSetCmd() *cobra.Command {
 	return &cobra.Command{
-		Use:     "set <key> <value>",
-		Short:   "Set a configuration value",
-		Long:    `Set a configuration key to the given value. The value is persisted to the config file.`,
-		Example: `  mush config set api.url https://api.example.com`,
-		Args:    cobra.ExactArgs(2),
+		Use:   "set <key> <value>",
+		Short: "Set a configuration value",
+		Long: `Set a configuration key to the given value. The value is persisted to the config file,
+in the active profile when --profile or MUSH_PROFILE names one.`,
+		Example: `  mush config set api.url https://api.example.com
+  mush config set --profile prod api.url https://api.prod.example.com`,
+		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := output.FromContext(cmd.Context())
 			key, value := args[0], args[1]
@@ -137,6 +145,72 @@ func newConfigSetCmd() *cobra.Command {
 	}
 }
 
+// ProfileInfo describes a config profile for JSON output.
+type ProfileInfo struct {
+	Name   string `json:"name"`
+	APIURL string `json:"api_url"`
+	Active bool   `json:"active"`
+}
+
+func newConfigProfilesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage configuration profiles",
+		Long: `Named profiles keep separate settings and credentials, such as one per
+workspace or environment. Select one for a command with --profile or
+MUSH_PROFILE; 'mush auth login --profile <name>' and
+'mush config set --profile <name> ...' create it.`,
+	}
+
+	cmd.AddCommand(newConfigProfilesListCmd())
+
+	return cmd
+}
+
+func newConfigProfilesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List configuration profiles",
+		Long:  `List the default profile and the named profiles in the config file, with the API URL each uses. The active profile is marked with *.`,
+		Example: `  mush config profiles list
+  mush config profiles list --json`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			cfg := config.Load()
+
+			names := append([]string{paths.DefaultProfile}, cfg.Profiles()...)
+			profiles := make([]ProfileInfo, 0, len(names))
+
+			for _, name := range names {
+				profile := config.LoadProfile(name)
+				profiles = append(profiles, ProfileInfo{
+					Name:   name,
+					APIURL: profile.APIURL(),
+					Active: profile.Profile() == cfg.Profile(),
+				})
+			}
+
+			if out.JSON {
+				return out.PrintJSON(map[string]any{"items": profiles})
+			}
+
+			out.Print("  %-20s %s\n", "NAME", "API URL")
+
+			for _, profile := range profiles {
+				marker := " "
+				if profile.Active {
+					marker = "*"
+				}
+
+				out.Print("%s %-20s %s\n", marker, profile.Name, profile.APIURL)
+			}
+
+			return nil
+		},
+	}
+}
+
 func parseConfigValue(key, value string) (interface{}, error) {
 	if key == "keybindings" {
 		return nil, errors.New("set individual keybindings via keybindings.<action>")
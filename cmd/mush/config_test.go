@@ -8,7 +8,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/musher-dev/mush/internal/config"
+	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
 	"github.com/musher-dev/mush/internal/terminal"
 	"github.com/musher-dev/mush/internal/testutil"
 )
@@ -143,3 +146,65 @@ func TestConfigGet_KeybindingsValue(t *testing.T) {
 		t.Fatalf("config get output = %q, want keybindings.status list", buf.String())
 	}
 }
+
+func TestConfigProfilesList(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(t.TempDir(), ".config"))
+	t.Setenv("MUSHER_API_URL", "")
+	t.Setenv(paths.ProfileEnv, "staging")
+
+	out, _ := testWriter()
+	setCmd := newConfigSetCmd()
+	setCmd.SetArgs([]string{"api.url", "https://api.staging.example.dev"})
+	setCmd.SetOut(io.Discard)
+	setCmd.SetErr(io.Discard)
+	setCmd.SetContext(out.WithContext(t.Context()))
+
+	if err := setCmd.Execute(); err != nil {
+		t.Fatalf("config set should succeed: %v", err)
+	}
+
+	t.Setenv(paths.ProfileEnv, "")
+
+	out, buf := testWriter()
+	listCmd := newConfigProfilesListCmd()
+	listCmd.SetArgs([]string{})
+	listCmd.SetOut(io.Discard)
+	listCmd.SetErr(io.Discard)
+	listCmd.SetContext(out.WithContext(t.Context()))
+
+	if err := listCmd.Execute(); err != nil {
+		t.Fatalf("config profiles list should succeed: %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "* default") || !strings.Contains(got, config.DefaultAPIURL) {
+		t.Errorf("profiles list = %q, want the default profile marked active", got)
+	}
+
+	if !strings.Contains(got, "  staging") || !strings.Contains(got, "https://api.staging.example.dev") {
+		t.Errorf("profiles list = %q, want staging with its API URL", got)
+	}
+}
+
+func TestRootCmd_ProfileFlag(t *testing.T) {
+	t.Setenv(paths.ProfileEnv, "")
+
+	root := newRootCmd()
+	root.SetArgs([]string{"--profile", "staging", "version"})
+
+	if err := root.Execute(); err != nil {
+		t.Fatalf("root.Execute() error = %v", err)
+	}
+
+	if got := os.Getenv(paths.ProfileEnv); got != "staging" {
+		t.Fatalf("%s = %q, want staging", paths.ProfileEnv, got)
+	}
+
+	root = newRootCmd()
+	root.SetArgs([]string{"--profile", "a.b", "version"})
+
+	var cliErr *clierrors.CLIError
+	if err := root.Execute(); !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitUsage {
+		t.Fatalf("root.Execute(--profile a.b) error = %v, want an ExitUsage CLIError", err)
+	}
+}
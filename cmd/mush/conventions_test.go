@@ -168,11 +168,15 @@ func TestShortDescriptionsStyle(t *testing.T) {
 func TestDataCommandsSupportJSON(t *testing.T) {
 	// Commands that currently support --json output.
 	jsonSupported := map[string]bool{
-		"mush habitat list": true,
-		"mush history list": true,
-		"mush config list":  true,
-		"mush auth status":  true,
-		"mush version":      true,
+		"mush habitat list":         true,
+		"mush jobs list":            true,
+		"mush history list":         true,
+		"mush config list":          true,
+		"mush config profiles list": true,
+		"mush auth status":          true,
+		"mush worker status":        true,
+		"mush telemetry status":     true,
+		"mush version":              true,
 	}
 
 	// Commands where --json support is intentionally deferred.
@@ -266,6 +270,8 @@ func TestHiddenFlagsAreRegistered(t *testing.T) {
 		"mush --log-file":     true,
 		"mush --log-stderr":   true,
 		"mush --experimental": true,
+
+		"mush worker observe --stream": true,
 	}
 
 	root := newRootCmd()
@@ -17,9 +17,10 @@ Checks performed:
   - Directory structure and permissions
   - Configuration file validity
   - Credential file security
-  - API connectivity and response time
+  - API connectivity and response time, broken down into DNS, connect, and TLS
   - Authentication status
-  - CLI version`,
+  - CLI version
+  - Harness startup from the last worker session (restarts, readiness)`,
 		Example: `  mush doctor`,
 		Args:    noArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -31,8 +32,12 @@ Checks performed:
 
 			// Run diagnostics
 			runner := doctor.New()
+			steps := out.Steps(runner.Len())
+			runner.OnCheck = steps.Step
 			results := runner.Run(cmd.Context())
 
+			steps.Done("")
+
 			// Display results
 			doctor.RenderResults(results, out.Print, out.Success, out.Warning, out.Failure, out.Muted)
 
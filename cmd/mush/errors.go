@@ -69,7 +69,9 @@ func shouldProbeHealth(cliErr *clierrors.CLIError, out *output.Writer) bool {
 		return false
 	}
 
-	if cliErr.Code != clierrors.ExitAuth && cliErr.Code != clierrors.ExitNetwork {
+	switch cliErr.Code {
+	case clierrors.ExitAuth, clierrors.ExitNetwork, clierrors.ExitTempFail:
+	default:
 		return false
 	}
 
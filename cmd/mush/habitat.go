@@ -47,7 +47,7 @@ func newHabitatListCmd() *cobra.Command {
 			if err != nil {
 				spin.Stop()
 
-				return clierrors.Wrap(clierrors.ExitNetwork, "Failed to fetch habitats", err).
+				return clierrors.NetworkFailed("Failed to fetch habitats", err).
 					WithHint("Check your network connection or run 'mush doctor'")
 			}
 
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
+)
+
+// loadCustomHarnesses registers the shell harnesses defined in the config
+// root's harnesses directory. Broken definitions are reported and skipped so
+// they never block a command.
+func loadCustomHarnesses(out *output.Writer) {
+	dir, err := paths.CustomHarnessesDir()
+	if err != nil {
+		return
+	}
+
+	err = harness.LoadCustomHarnesses(dir)
+	if err == nil {
+		return
+	}
+
+	var joined interface{ Unwrap() []error }
+	if !errors.As(err, &joined) {
+		out.Warning("Skipped custom harness: %v", err)
+		return
+	}
+
+	for _, fileErr := range joined.Unwrap() {
+		out.Warning("Skipped custom harness: %v", fileErr)
+	}
+}
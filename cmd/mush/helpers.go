@@ -1,14 +1,28 @@
 package main
 
 import (
+	"log/slog"
+	"os"
+	"runtime"
+
 	"github.com/musher-dev/mush/internal/auth"
+	"github.com/musher-dev/mush/internal/bundle"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
 	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/telemetry"
 )
 
 var apiClientFactory = newAPIClient
 
+// refreshIdentity is set by --refresh-identity to bypass the cached runner
+// identity for this invocation.
+var refreshIdentity bool
+
+// apiURLOverridden is set by --api-url. Configured fallback URLs belong to
+// the configured platform, so they are ignored for an overridden URL.
+var apiURLOverridden bool
+
 // newAPIClient creates an authenticated API client using stored credentials
 // and the configured API URL. Returns a CLIError if not authenticated.
 //
@@ -17,7 +31,7 @@ var apiClientFactory = newAPIClient
 func newAPIClient() (auth.CredentialSource, *client.Client, error) {
 	cfg := config.Load()
 
-	source, apiKey := auth.GetCredentials(cfg.APIURL())
+	source, apiKey := storedCredentials(cfg)
 	if apiKey == "" {
 		return "", nil, clierrors.NotAuthenticated()
 	}
@@ -30,6 +44,40 @@ func newAPIClient() (auth.CredentialSource, *client.Client, error) {
 	return source, apiClient, nil
 }
 
+// storedCredentials returns the API key for the configured API URL and its
+// source. A key found in the credentials file is first moved to the keyring,
+// unless auth.store keeps it in the file.
+func storedCredentials(cfg *config.Config) (auth.CredentialSource, string) {
+	source, apiKey := auth.GetCredentials(cfg.APIURL())
+	if source != auth.SourceFile {
+		return source, apiKey
+	}
+
+	if store, err := auth.ParseStore(cfg.AuthStore()); err != nil || store == auth.StoreFile {
+		return source, apiKey
+	}
+
+	moved, err := auth.MigrateToKeyring(cfg.APIURL())
+	if err != nil {
+		slog.Default().Warn("failed to move API key to keyring",
+			slog.String("component", "auth"),
+			slog.String("event.type", "auth.migrate.error"),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if !moved {
+		return source, apiKey
+	}
+
+	slog.Default().Info("moved API key from credentials file to keyring",
+		slog.String("component", "auth"),
+		slog.String("event.type", "auth.migrate"),
+	)
+
+	return auth.SourceKeyring, apiKey
+}
+
 func newAPIClientWithKey(apiKey string) (*client.Client, error) {
 	cfg := config.Load()
 
@@ -43,7 +91,57 @@ func newAPIClientFromConfig(cfg *config.Config, apiKey string) (*client.Client,
 			WithHint("Set MUSHER_NETWORK_CA_CERT_FILE to a readable PEM bundle, or unset it and retry")
 	}
 
-	return client.NewWithHTTPClient(cfg.APIURL(), apiKey, httpClient), nil
+	apiClient := client.NewWithHTTPClient(cfg.APIURL(), apiKey, httpClient)
+
+	if !apiURLOverridden {
+		apiClient.SetFallbackURLs(cfg.APIFallbackURLs())
+	}
+
+	apiClient.SetRetryPolicy(client.RetryPolicy{
+		MaxAttempts: cfg.APIRetryMaxAttempts(),
+		BaseDelay:   cfg.APIRetryBaseDelay(),
+		MaxDelay:    cfg.APIRetryMaxDelay(),
+	})
+
+	// DO_NOT_TRACK turns capability hints off along with telemetry.
+	if cfg.CapabilityHints() && !telemetry.DoNotTrack() {
+		apiClient.SetCapabilities(client.Capabilities{
+			OS:       runtime.GOOS,
+			Arch:     runtime.GOARCH,
+			Terminal: os.Getenv("TERM"),
+		})
+	}
+
+	apiClient.SetResponseCache(bundle.NewETagCache())
+	apiClient.SetIdentityCache(newIdentityCache(cfg.APIURL()))
+	useStoredToken(apiClient, cfg.APIURL(), apiKey)
+
+	return apiClient, nil
+}
+
+// useStoredToken lets apiClient renew the OAuth token from
+// 'mush auth login --device' when apiKey is its access token. Each renewed
+// token is stored where the old one was.
+func useStoredToken(apiClient *client.Client, apiURL, apiKey string) {
+	if apiKey == "" {
+		return
+	}
+
+	tok, source := auth.LoadToken(apiURL)
+	if tok == nil || tok.AccessToken != apiKey {
+		return
+	}
+
+	store := auth.StoreKeyring
+	if source == auth.SourceFile {
+		store = auth.StoreFile
+	}
+
+	apiClient.SetOAuthToken(tok, func(renewed *client.Token) error {
+		_, err := auth.StoreToken(apiURL, renewed, store)
+
+		return err //nolint:wrapcheck // the client ignores save errors
+	})
 }
 
 var tryAPIClient = newTryAPIClient
@@ -54,7 +152,7 @@ var tryAPIClient = newTryAPIClient
 func newTryAPIClient() (auth.CredentialSource, *client.Client, string, error) {
 	cfg := config.Load()
 
-	source, apiKey := auth.GetCredentials(cfg.APIURL())
+	source, apiKey := storedCredentials(cfg)
 
 	apiClient, err := newAPIClientFromConfig(cfg, apiKey)
 	if err != nil {
@@ -67,3 +165,12 @@ func newTryAPIClient() (auth.CredentialSource, *client.Client, string, error) {
 
 	return source, apiClient, "", nil
 }
+
+// newIdentityCache returns the runner identity cache for apiURL, honoring
+// --refresh-identity.
+func newIdentityCache(apiURL string) *auth.IdentityCache {
+	cache := auth.NewIdentityCache(apiURL)
+	cache.Refresh = refreshIdentity
+
+	return cache
+}
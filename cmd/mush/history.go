@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
@@ -14,23 +17,38 @@ import (
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/config"
 	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/observability"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
 	"github.com/musher-dev/mush/internal/prompt"
+	"github.com/musher-dev/mush/internal/safeio"
 	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/tui/nav"
 )
 
+// replayResetSequence restores the terminal after a replay: it resets
+// attributes, shows the cursor, and moves to a fresh line.
+const replayResetSequence = "\x1b[0m\x1b[?25h\r\n"
+
 func newHistoryCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "history",
 		Short: "Inspect transcript history from PTY sessions",
 		Long: `Inspect and manage transcript history captured during PTY harness sessions.
 
-Transcripts are stored locally and can be listed, viewed, or pruned to free
-disk space.`,
+Transcripts are stored locally and can be listed, viewed, replayed,
+rendered as a report, exported, or pruned to free disk space. The log a
+worker wrote for each job is shown with 'mush history show', and
+'mush history tui' browses every recorded job interactively.`,
 	}
 
 	cmd.AddCommand(newHistoryListCmd())
 	cmd.AddCommand(newHistoryViewCmd())
+	cmd.AddCommand(newHistoryShowCmd())
+	cmd.AddCommand(newHistoryReplayCmd())
+	cmd.AddCommand(newHistoryRenderCmd())
+	cmd.AddCommand(newHistoryExportCmd())
+	cmd.AddCommand(newHistoryTUICmd())
 	cmd.AddCommand(newHistoryPruneCmd())
 
 	return cmd
@@ -81,6 +99,36 @@ func newHistoryListCmd() *cobra.Command {
 	}
 }
 
+// completeSessionIDs completes a transcript session ID from local history,
+// newest first, described by when the session started.
+func completeSessionIDs(_ *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	sessions, err := transcript.ListSessions(config.Load().HistoryDir())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	completions := make([]cobra.Completion, 0, len(sessions))
+
+	for _, session := range sessions {
+		if !strings.HasPrefix(session.SessionID, toComplete) {
+			continue
+		}
+
+		description := "started " + session.StartedAt.Local().Format(time.DateTime)
+		if session.ClosedAt == nil {
+			description += " (open)"
+		}
+
+		completions = append(completions, cobra.CompletionWithDesc(session.SessionID, description))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
 func renderTranscriptEvents(
 	out *output.Writer,
 	events []transcript.Event,
@@ -129,7 +177,8 @@ Use --follow to tail the transcript in real time while a session is active.
 Use --search to filter output to lines matching a substring.`,
 		Example: `  mush history view SESSION_ID
   mush history view SESSION_ID --follow`,
-		Args: cobra.ExactArgs(1),
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			sessionID := args[0]
 			out := output.FromContext(cmd.Context())
@@ -192,6 +241,551 @@ Use --search to filter output to lines matching a substring.`,
 	return cmd
 }
 
+// jobLogHiddenAttrs are the job scope fields every line of a job log
+// carries, left out when showing it.
+var jobLogHiddenAttrs = []string{"job.id", "job.queue_id", "job.harness_type"}
+
+func newHistoryShowCmd() *cobra.Command {
+	var (
+		follow bool
+		level  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "show <job-id>",
+		Short: "Show the log a worker wrote for a job",
+		Long: `Show the structured log records a worker wrote while running a job: its
+lifecycle events, API requests, and executor output, one line per record
+with its time, level, component, message, and fields.
+
+Workers keep each job's log in its own file under logs/jobs in the state
+directory, so a job can be read without the rest of the worker log. A job
+retried on the same worker appends to the file; job.attempt_number tells
+the attempts apart. Use --follow for a job that is still running, and
+--json to print the records as written.`,
+		Example: `  mush history show JOB_ID
+  mush history show JOB_ID --level debug --follow
+  mush history show JOB_ID --json | jq .msg`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeJobLogIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			minLevel, err := observability.ParseLogLevel(level)
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitUsage, "Invalid --level", err).
+					WithHint("Use one of: error, warn, info, debug")
+			}
+
+			dir, err := paths.JobLogsDir()
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to locate job logs", err)
+			}
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			opts := &observability.TailOptions{
+				Lines:  -1,
+				Follow: follow,
+				Filter: observability.LogFilter{MinLevel: minLevel},
+			}
+
+			err = observability.TailLog(ctx, observability.JobLogPath(dir, args[0]), opts, func(rec *observability.LogRecord) error {
+				if out.JSON {
+					out.Print("%s\n", rec.Raw)
+					return nil
+				}
+
+				for _, key := range jobLogHiddenAttrs {
+					delete(rec.Attrs, key)
+				}
+
+				out.Print("%s\n", formatLogRecord(rec))
+
+				return nil
+			})
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return clierrors.Wrap(clierrors.ExitGeneral, "No log for job "+args[0], err).
+						WithHint("Job logs are kept on the worker that ran the job; run 'mush history show' there")
+				}
+
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read the job log", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep printing records as they are written")
+	cmd.Flags().StringVar(&level, "level", "info", "Minimum level to show: error, warn, info, debug")
+
+	return cmd
+}
+
+// completeJobLogIDs completes the IDs of jobs with a log on this machine.
+func completeJobLogIDs(_ *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	dir, err := paths.JobLogsDir()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []cobra.Completion
+
+	for _, entry := range entries {
+		jobID, ok := strings.CutSuffix(entry.Name(), ".jsonl")
+		if !ok || !strings.HasPrefix(jobID, toComplete) {
+			continue
+		}
+
+		info, infoErr := entry.Info()
+		if infoErr != nil {
+			continue
+		}
+
+		completions = append(completions, cobra.CompletionWithDesc(jobID, "last written "+info.ModTime().Local().Format(time.DateTime)))
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+func newHistoryReplayCmd() *cobra.Command {
+	var (
+		jobID    string
+		noTiming bool
+		speed    float64
+		maxIdle  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "replay <session-id>",
+		Short: "Replay a session's terminal output as it was recorded",
+		Long: `Replay the terminal output captured in a transcript session, escape
+sequences included, so the harness's screen is redrawn in your terminal at
+the pace it was recorded.
+
+Pauses longer than --max-idle are shortened to it, and --speed plays the
+session faster or slower. --no-timing writes the whole stream at once, for
+piping to a file or a pager that understands ANSI, such as 'less -R'. Use
+--job to replay only one job's output. Press Ctrl+C to stop.`,
+		Example: `  mush history replay SESSION_ID
+  mush history replay SESSION_ID --job JOB_ID --speed 4
+  mush history replay SESSION_ID --no-timing > session.ansi`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := args[0]
+			out := output.FromContext(cmd.Context())
+
+			if !noTiming && speed <= 0 {
+				return clierrors.New(clierrors.ExitUsage, "--speed must be greater than zero").
+					WithHint("Use --no-timing to write the output without pauses")
+			}
+
+			if maxIdle < 0 {
+				return clierrors.New(clierrors.ExitUsage, "--max-idle must be a positive duration")
+			}
+
+			events, err := transcript.ReadEvents(config.Load().HistoryDir(), sessionID)
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read transcript events", err)
+			}
+
+			if len(events) == 0 {
+				return &clierrors.CLIError{
+					Message: "No transcript session " + sessionID,
+					Hint:    "Run 'mush history list' to see stored sessions",
+					Code:    clierrors.ExitGeneral,
+				}
+			}
+
+			if jobID != "" {
+				if events = transcript.JobEvents(events, jobID); events == nil {
+					return &clierrors.CLIError{
+						Message: fmt.Sprintf("Job %s is not in session %s", jobID, sessionID),
+						Hint:    "Run 'mush history render " + sessionID + "' to see the session's jobs",
+						Code:    clierrors.ExitGeneral,
+					}
+				}
+			}
+
+			opts := transcript.ReplayOptions{Speed: speed, MaxIdle: maxIdle}
+			if noTiming {
+				opts.Speed = 0
+			}
+
+			return replayEvents(cmd.Context(), out, events, opts)
+		},
+	}
+	cmd.Flags().StringVar(&jobID, "job", "", "Replay only this job's output")
+	cmd.Flags().BoolVar(&noTiming, "no-timing", false, "Write the output at once instead of at the recorded pace")
+	cmd.Flags().Float64Var(&speed, "speed", 1, "Playback speed multiplier")
+	cmd.Flags().DurationVar(&maxIdle, "max-idle", transcript.DefaultReplayMaxIdle, "Longest pause between outputs; 0 keeps every pause")
+
+	return cmd
+}
+
+// replayEvents replays events to the terminal until they run out or the
+// user interrupts, then restores the terminal.
+func replayEvents(ctx context.Context, out *output.Writer, events []transcript.Event, opts transcript.ReplayOptions) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	err := transcript.Replay(ctx, out.Raw(), events, opts)
+
+	// The session may have ended mid-redraw, with attributes set or
+	// the cursor hidden.
+	if out.Terminal().IsTTY {
+		_, _ = io.WriteString(out.Raw(), replayResetSequence)
+	}
+
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to replay the session", err)
+	}
+
+	return nil
+}
+
+func newHistoryRenderCmd() *cobra.Command {
+	var (
+		format     string
+		outputPath string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "render <session-id|job-id>",
+		Short: "Render a session or job as a Markdown or HTML report",
+		Long: `Render a readable report of a transcript session, or of one job in it, for
+attaching to a ticket or sharing with someone who doesn't run mush.
+
+For each job the report shows its prompt, result status, duration, usage,
+the tool calls drawn in its terminal output, its final output, and the end
+of its terminal output. A job ID is looked up in the newest session that
+ran it. Sessions recorded before jobs were marked in transcripts are
+reported as a whole.
+
+The report is written to stdout unless --output names a file.`,
+		Example: `  mush history render SESSION_ID
+  mush history render JOB_ID --format html --output report.html`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			dir := config.Load().HistoryDir()
+
+			render := transcript.RenderMarkdown
+
+			switch format {
+			case "md", "markdown":
+			case "html":
+				render = transcript.RenderHTML
+			default:
+				return &clierrors.CLIError{
+					Message: fmt.Sprintf("Invalid --format %q", format),
+					Hint:    "Use md or html",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			report, err := loadHistoryReport(dir, args[0])
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			if err := render(&buf, report); err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to render the report", err)
+			}
+
+			if outputPath == "" {
+				out.Print("%s", buf.String())
+				return nil
+			}
+
+			if err := safeio.WriteFile(outputPath, buf.Bytes(), 0o600); err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write the report", err)
+			}
+
+			out.Success("Wrote %s", outputPath)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "md", "Report format: md or html")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the report to this file instead of stdout")
+
+	return cmd
+}
+
+func newHistoryExportCmd() *cobra.Command {
+	var (
+		format     string
+		jobID      string
+		outputPath string
+		width      int
+		height     int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export <session-id>",
+		Short: "Export a session as plain text, an asciinema cast, or JSON",
+		Long: `Export the terminal output captured in a transcript session, for sharing
+with teammates or attaching to a ticket.
+
+Formats:
+  text       Plain text with escape sequences removed
+  asciinema  An asciinema v2 recording, playable with 'asciinema play'
+             or embedded with the asciinema player
+  json       The session's events as JSON Lines, one event per line
+
+Transcripts do not record the terminal size, so an asciinema recording is
+sized with --width and --height. Use --job to export only one job's output.
+The export is written to stdout unless --output names a file.`,
+		Example: `  mush history export SESSION_ID > session.txt
+  mush history export SESSION_ID --format asciinema --output session.cast
+  mush history export SESSION_ID --job JOB_ID --format json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessionID := args[0]
+			out := output.FromContext(cmd.Context())
+
+			var export func(io.Writer, []transcript.Event) error
+
+			switch format {
+			case "text", "txt":
+				export = transcript.ExportText
+			case "asciinema", "cast":
+				title := "mush session " + sessionID
+				if jobID != "" {
+					title = "mush job " + jobID
+				}
+
+				export = func(w io.Writer, events []transcript.Event) error {
+					return transcript.ExportAsciicast(w, events, transcript.AsciicastOptions{Width: width, Height: height, Title: title})
+				}
+			case "json":
+				export = transcript.ExportJSON
+			default:
+				return &clierrors.CLIError{
+					Message: fmt.Sprintf("Invalid --format %q", format),
+					Hint:    "Use text, asciinema, or json",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			if width <= 0 || height <= 0 {
+				return clierrors.New(clierrors.ExitUsage, "--width and --height must be greater than zero")
+			}
+
+			events, err := transcript.ReadEvents(config.Load().HistoryDir(), sessionID)
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read transcript events", err)
+			}
+
+			if len(events) == 0 {
+				return &clierrors.CLIError{
+					Message: "No transcript session " + sessionID,
+					Hint:    "Run 'mush history list' to see stored sessions",
+					Code:    clierrors.ExitGeneral,
+				}
+			}
+
+			if jobID != "" {
+				if events = transcript.JobEvents(events, jobID); events == nil {
+					return &clierrors.CLIError{
+						Message: fmt.Sprintf("Job %s is not in session %s", jobID, sessionID),
+						Hint:    "Run 'mush history render " + sessionID + "' to see the session's jobs",
+						Code:    clierrors.ExitGeneral,
+					}
+				}
+			}
+
+			var buf bytes.Buffer
+			if err := export(&buf, events); err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to export the session", err)
+			}
+
+			if outputPath == "" {
+				if _, err := out.Raw().Write(buf.Bytes()); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write the export", err)
+				}
+
+				return nil
+			}
+
+			if err := safeio.WriteFile(outputPath, buf.Bytes(), 0o600); err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write the export", err)
+			}
+
+			out.Success("Wrote %s", outputPath)
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "text", "Export format: text, asciinema, or json")
+	cmd.Flags().StringVar(&jobID, "job", "", "Export only this job's output")
+	cmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the export to this file instead of stdout")
+	cmd.Flags().IntVar(&width, "width", transcript.DefaultAsciicastWidth, "Terminal width in columns for an asciinema recording")
+	cmd.Flags().IntVar(&height, "height", transcript.DefaultAsciicastHeight, "Terminal height in rows for an asciinema recording")
+
+	return cmd
+}
+
+// loadHistoryReport builds the report for id, a session ID or, failing
+// that, a job ID recorded in a session.
+func loadHistoryReport(dir, id string) (*transcript.Report, error) {
+	events, err := transcript.ReadEvents(dir, id)
+	if err == nil && len(events) > 0 {
+		return transcript.BuildReport(id, events), nil
+	}
+
+	sessionID, findErr := transcript.FindJobSession(dir, id)
+	if findErr != nil {
+		if errors.Is(findErr, transcript.ErrJobNotFound) {
+			return nil, &clierrors.CLIError{
+				Message: "No transcript session or job " + id,
+				Hint:    "Run 'mush history list' to see stored sessions",
+				Code:    clierrors.ExitGeneral,
+			}
+		}
+
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "Failed to search transcript history", findErr)
+	}
+
+	events, err = transcript.ReadEvents(dir, sessionID)
+	if err != nil {
+		return nil, clierrors.Wrap(clierrors.ExitGeneral, "Failed to read transcript events", err)
+	}
+
+	report := transcript.BuildReport(sessionID, events)
+	report.OnlyJob(id)
+
+	return report, nil
+}
+
+func newHistoryTUICmd() *cobra.Command {
+	var status, queueID, since string
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse recorded jobs interactively",
+		Long: `Browse every job recorded in transcript history, newest first, with a
+preview of each job's result and the end of its output.
+
+Press / to filter by words in the job ID or name, or by status:, queue:, and
+since: terms; Tab steps through the statuses. Enter replays the selected
+job's terminal output, and r requeues a failed job on the platform when you
+are signed in. --status, --queue, and --since set the starting filter.`,
+		Example: `  mush history tui
+  mush history tui --status failed --since 24h
+  mush history tui --queue QUEUE_ID --since 2026-05-01`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			filter, err := historyJobFilter(status, queueID, since)
+			if err != nil {
+				return &clierrors.CLIError{
+					Message: "Invalid job filter: " + err.Error(),
+					Hint:    "Use --status completed|failed|unfinished and --since with a duration such as 24h or a date such as 2026-05-01",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			if out.JSON || out.NoInput || !out.Terminal().IsTTY {
+				return &clierrors.CLIError{
+					Message: "Browsing job history requires a terminal (TTY)",
+					Hint:    "Use 'mush history list' and 'mush history render' in scripts",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			return browseHistoryJobs(cmd.Context(), out, filter)
+		},
+	}
+
+	cmd.Flags().StringVar(&status, "status", "", "Show only jobs with this status: completed, failed, or unfinished")
+	cmd.Flags().StringVar(&queueID, "queue", "", "Show only jobs from this queue")
+	cmd.Flags().StringVar(&since, "since", "", "Show only jobs started within a duration (24h) or on or after a date (2026-05-01)")
+
+	return cmd
+}
+
+// historyJobFilter builds the starting filter for 'mush history tui' from
+// its flags.
+func historyJobFilter(status, queueID, since string) (transcript.JobFilter, error) {
+	var terms []string
+
+	for _, term := range []struct{ key, value string }{
+		{"status", status},
+		{"queue", queueID},
+		{"since", since},
+	} {
+		if value := strings.TrimSpace(term.value); value != "" {
+			terms = append(terms, term.key+":"+value)
+		}
+	}
+
+	return transcript.ParseJobFilter(strings.Join(terms, " "), time.Now())
+}
+
+// browseHistoryJobs runs the job history browser, replaying the jobs chosen
+// in it until the user quits.
+func browseHistoryJobs(ctx context.Context, out *output.Writer, filter transcript.JobFilter) error {
+	dir := config.Load().HistoryDir()
+	seed := &nav.HistorySeed{Dir: dir, Filter: filter}
+
+	for {
+		deps := buildTUIDeps()
+		deps.InitialHistory = seed
+
+		result, err := nav.Run(ctx, deps)
+		if err != nil {
+			return clierrors.Wrap(clierrors.ExitGeneral, "Interactive TUI failed", err)
+		}
+
+		if result.Action != nav.ActionHistoryReplay {
+			return nil
+		}
+
+		events, err := transcript.ReadEvents(dir, result.SessionID)
+		if err != nil {
+			return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read transcript events", err)
+		}
+
+		if events = transcript.JobEvents(events, result.JobID); events == nil {
+			out.Warning("Job %s recorded no terminal output", result.JobID)
+		} else if err := replayEvents(ctx, out, events, transcript.ReplayOptions{
+			Speed:   1,
+			MaxIdle: transcript.DefaultReplayMaxIdle,
+		}); err != nil {
+			return err
+		}
+
+		again, err := prompt.New(out).Confirm("Return to the job history browser?", true)
+		if err != nil {
+			return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read confirmation", err)
+		}
+
+		if !again {
+			return nil
+		}
+
+		seed = &nav.HistorySeed{Dir: dir, Filter: result.HistoryFilter, JobID: result.JobID}
+	}
+}
+
 func newHistoryPruneCmd() *cobra.Command {
 	var (
 		olderThan string
@@ -200,8 +794,9 @@ func newHistoryPruneCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "prune",
-		Short: "Delete transcript sessions older than a duration",
-		Long: `Delete transcript sessions older than the configured retention window.
+		Short: "Delete old transcript sessions and job logs",
+		Long: `Delete transcript sessions and per-job logs older than the configured
+retention window.
 
 The default retention comes from the history.retention config key (default 720h).
 Use --older-than to override. Requires confirmation unless --force is passed.`,
@@ -241,24 +836,35 @@ Use --older-than to override. Requires confirmation unless --force is passed.`,
 				}
 			}
 
-			if count == 0 {
-				out.Muted("No transcript sessions older than %s", window)
+			// Job logs are optional; without a state directory there are none.
+			jobLogDir, _ := paths.JobLogsDir()
+
+			var oldJobLogs []string
+
+			if jobLogDir != "" {
+				oldJobLogs, err = observability.OldJobLogs(jobLogDir, cutoff)
+				if err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to list job logs", err)
+				}
+			}
+
+			if count == 0 && len(oldJobLogs) == 0 {
+				out.Muted("No transcript sessions or job logs older than %s", window)
 				return nil
 			}
 
-			out.Print("Found %d session(s) older than %s\n", count, window)
+			out.Print("Found %d session(s) and %d job log(s) older than %s\n", count, len(oldJobLogs), window)
 
 			// Require confirmation.
 			if !force {
 				if out.NoInput {
-					return clierrors.New(clierrors.ExitUsage, "Cannot confirm prune in non-interactive mode").
-						WithHint("Use --force to skip confirmation")
+					return clierrors.ConfirmationRequired("prune")
 				}
 
 				prompter := prompt.New(out)
 
 				confirmed, promptErr := prompter.Confirm(
-					fmt.Sprintf("Delete %d transcript session(s)?", count),
+					fmt.Sprintf("Delete %d transcript session(s) and %d job log(s)?", count, len(oldJobLogs)),
 					false,
 				)
 				if promptErr != nil {
@@ -276,7 +882,16 @@ Use --older-than to override. Requires confirmation unless --force is passed.`,
 				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to prune transcript sessions", err)
 			}
 
-			out.Success("Removed %d transcript session(s)", removed)
+			var removedLogs int
+
+			if jobLogDir != "" {
+				removedLogs, err = observability.PruneJobLogs(jobLogDir, cutoff)
+				if err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to prune job logs", err)
+				}
+			}
+
+			out.Success("Removed %d transcript session(s) and %d job log(s)", removed, removedLogs)
 
 			return nil
 		},
@@ -2,10 +2,16 @@ package main
 
 import (
 	"bytes"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/observability"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
 	"github.com/musher-dev/mush/internal/terminal"
 	"github.com/musher-dev/mush/internal/transcript"
 )
@@ -38,3 +44,287 @@ func TestRenderTranscriptEventsFiltersAndAdvancesWatermark(t *testing.T) {
 		t.Fatalf("expected matching lines to be printed, got %q", got)
 	}
 }
+
+func TestCompleteSessionIDs(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("MUSHER_HISTORY_DIR", dir)
+
+	for _, id := range []string{"sess-alpha", "sess-beta", "other"} {
+		store, err := transcript.NewStore(transcript.StoreOptions{SessionID: id, Dir: dir})
+		if err != nil {
+			t.Fatalf("NewStore(%q) error = %v", id, err)
+		}
+
+		if id != "sess-beta" {
+			if err := store.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+		} else {
+			t.Cleanup(func() { _ = store.Close() })
+		}
+	}
+
+	completions, directive := completeSessionIDs(newHistoryCmd(), nil, "sess-")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Fatalf("directive = %v, want NoFileComp", directive)
+	}
+
+	if len(completions) != 2 {
+		t.Fatalf("completions = %q, want the two sess- sessions", completions)
+	}
+
+	for _, completion := range completions {
+		id, description, _ := strings.Cut(completion, "\t")
+		if !strings.HasPrefix(id, "sess-") || !strings.HasPrefix(description, "started ") {
+			t.Fatalf("completion = %q, want a sess- ID with its start time", completion)
+		}
+
+		if (id == "sess-beta") != strings.HasSuffix(description, "(open)") {
+			t.Fatalf("completion = %q, want only the open session marked open", completion)
+		}
+	}
+
+	if completions, _ := completeSessionIDs(newHistoryCmd(), []string{"sess-alpha"}, ""); len(completions) != 0 {
+		t.Fatalf("completions after the first argument = %q, want none", completions)
+	}
+}
+
+func TestCompleteJobLogIDs(t *testing.T) {
+	t.Setenv("MUSHER_STATE_HOME", t.TempDir())
+
+	dir, err := paths.JobLogsDir()
+	if err != nil {
+		t.Fatalf("JobLogsDir() error = %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	for _, id := range []string{"job-1", "job-2", "other"} {
+		if err := os.WriteFile(observability.JobLogPath(dir, id), []byte("{}\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile(%q) error = %v", id, err)
+		}
+	}
+
+	jobs := newJobsCmd()
+	commands := map[string]*cobra.Command{}
+
+	for _, name := range []string{"show", "release", "retry"} {
+		cmd, _, err := jobs.Find([]string{name})
+		if err != nil {
+			t.Fatalf("Find(%q) error = %v", name, err)
+		}
+
+		commands["jobs "+name] = cmd
+	}
+
+	history, _, err := newHistoryCmd().Find([]string{"show"})
+	if err != nil {
+		t.Fatalf("Find(show) error = %v", err)
+	}
+
+	commands["history show"] = history
+
+	for name, cmd := range commands {
+		if cmd.ValidArgsFunction == nil {
+			t.Fatalf("%s has no ValidArgsFunction", name)
+		}
+
+		completions, directive := cmd.ValidArgsFunction(cmd, nil, "job-")
+		if directive != cobra.ShellCompDirectiveNoFileComp {
+			t.Fatalf("%s directive = %v, want NoFileComp", name, directive)
+		}
+
+		if len(completions) != 2 {
+			t.Fatalf("%s completions = %q, want the two job- logs", name, completions)
+		}
+
+		for _, completion := range completions {
+			id, description, _ := strings.Cut(completion, "\t")
+			if !strings.HasPrefix(id, "job-") || !strings.HasPrefix(description, "last written ") {
+				t.Fatalf("%s completion = %q, want a job- ID with its last write time", name, completion)
+			}
+		}
+
+		if completions, _ := cmd.ValidArgsFunction(cmd, []string{"job-1"}, ""); len(completions) != 0 {
+			t.Fatalf("%s completions after the first argument = %q, want none", name, completions)
+		}
+	}
+}
+
+func TestHistoryRenderJob(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("MUSHER_HISTORY_DIR", dir)
+
+	store, err := transcript.NewStore(transcript.StoreOptions{SessionID: "sess-1", Dir: dir})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	for _, rec := range []*transcript.JobRecord{
+		{Event: transcript.JobStarted, JobID: "job-1", Prompt: "Say hello"},
+		{Event: transcript.JobFinished, JobID: "job-1", Status: "completed", Output: "hello"},
+		{Event: transcript.JobStarted, JobID: "job-2", Prompt: "Say goodbye"},
+	} {
+		if err := store.AppendJob(rec); err != nil {
+			t.Fatalf("AppendJob() error = %v", err)
+		}
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	out, buf := testWriter()
+	cmd := newHistoryCmd()
+	cmd.SetArgs([]string{"render", "job-1"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("history render error = %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "# Session sess-1\n") || !strings.Contains(got, "## Job job-1\n") || !strings.Contains(got, "```text\nhello\n```") {
+		t.Fatalf("report missing job-1:\n%s", got)
+	}
+
+	if strings.Contains(got, "job-2") {
+		t.Fatalf("report includes another job:\n%s", got)
+	}
+
+	cmd = newHistoryCmd()
+	cmd.SetArgs([]string{"render", "job-3"})
+	cmd.SetContext(out.WithContext(t.Context()))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "No transcript session or job job-3") {
+		t.Fatalf("history render error = %v, want an unknown job error", err)
+	}
+}
+
+func TestHistoryShowJobLog(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+
+	dir, err := paths.JobLogsDir()
+	if err != nil {
+		t.Fatalf("JobLogsDir() error = %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	lines := `{"time":"2026-05-06T07:08:09Z","level":"INFO","msg":"job started","component":"engine","job.id":"job-1","job.attempt_number":2,"event.type":"job.start"}
+{"time":"2026-05-06T07:08:10Z","level":"DEBUG","msg":"heartbeat","component":"engine","job.id":"job-1"}
+`
+	if err := os.WriteFile(observability.JobLogPath(dir, "job-1"), []byte(lines), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, buf := testWriter()
+	cmd := newHistoryCmd()
+	cmd.SetArgs([]string{"show", "job-1"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("history show error = %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "INFO  engine job started event.type=job.start job.attempt_number=2\n") {
+		t.Fatalf("history show = %q, want the job's info record without the job ID", got)
+	}
+
+	if strings.Contains(got, "heartbeat") {
+		t.Fatalf("history show = %q, want debug records left out", got)
+	}
+
+	cmd = newHistoryCmd()
+	cmd.SetArgs([]string{"show", "job-2"})
+	cmd.SetContext(out.WithContext(t.Context()))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "No log for job job-2") {
+		t.Fatalf("history show error = %v, want a missing log error", err)
+	}
+}
+
+func TestHistoryTUIRejectsBadFilterAndNonTTY(t *testing.T) {
+	t.Setenv("MUSHER_HISTORY_DIR", t.TempDir())
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"tui", "--status", "lost"}, want: "Invalid job filter"},
+		{args: []string{"tui", "--since", "yesterday"}, want: "Invalid job filter"},
+		{args: []string{"tui", "--status", "failed"}, want: "requires a terminal"},
+	} {
+		out, _ := testWriter()
+		cmd := newHistoryCmd()
+		cmd.SetArgs(tt.args)
+		cmd.SetContext(out.WithContext(t.Context()))
+		cmd.SilenceErrors = true
+		cmd.SilenceUsage = true
+
+		err := cmd.Execute()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("history %v error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func TestHistoryPruneRemovesOldJobLogs(t *testing.T) {
+	t.Setenv("MUSHER_HISTORY_DIR", t.TempDir())
+	t.Setenv("MUSHER_STATE_HOME", t.TempDir())
+
+	dir, err := paths.JobLogsDir()
+	if err != nil {
+		t.Fatalf("JobLogsDir() error = %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	old := observability.JobLogPath(dir, "job-1")
+	if err := os.WriteFile(old, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	lastWeek := time.Now().Add(-7 * 24 * time.Hour)
+	if err := os.Chtimes(old, lastWeek, lastWeek); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+
+	fresh := observability.JobLogPath(dir, "job-2")
+	if err := os.WriteFile(fresh, []byte("{}\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	out, buf := testWriter()
+	cmd := newHistoryCmd()
+	cmd.SetArgs([]string{"prune", "--older-than", "24h", "--force"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("history prune error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Removed 0 transcript session(s) and 1 job log(s)") {
+		t.Fatalf("history prune output = %q, want one job log removed", buf.String())
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Fatalf("old job log still exists: %v", err)
+	}
+
+	if _, err := os.Stat(fresh); err != nil {
+		t.Fatalf("fresh job log was removed: %v", err)
+	}
+}
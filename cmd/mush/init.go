@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/musher-dev/mush/internal/harness"
 	"github.com/musher-dev/mush/internal/output"
 	"github.com/musher-dev/mush/internal/wizard"
 )
@@ -31,7 +32,8 @@ If credentials already exist, use --force to overwrite them.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			out := output.FromContext(cmd.Context())
 
-			w := wizard.New(out, force, apiKey, habitat)
+			w := wizard.New(out, force, apiKey, pickFlagOrEnv(habitat, "MUSH_HABITAT", ""))
+			w.SetHarnessChoices(harness.AvailableNames())
 
 			return w.Run(cmd.Context())
 		},
@@ -39,7 +41,7 @@ If credentials already exist, use --force to overwrite them.`,
 
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Overwrite existing credentials without prompting")
 	cmd.Flags().StringVar(&apiKey, "api-key", "", "API key to use for non-interactive initialization")
-	cmd.Flags().StringVar(&habitat, "habitat", "", "Habitat slug or ID to select during initialization")
+	cmd.Flags().StringVar(&habitat, "habitat", "", "Habitat slug or ID to select during initialization (env: MUSH_HABITAT)")
 
 	return cmd
 }
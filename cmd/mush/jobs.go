@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/prompt"
+)
+
+func newJobsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "jobs",
+		Short: "Inspect and manage jobs",
+		Long: `Commands for inspecting and managing jobs on the platform.
+
+Jobs are routed to a habitat's queues and claimed by workers. Use these commands
+to see what workers are doing, hand a stuck job back to its queue, or run a
+failed job again.`,
+	}
+
+	cmd.AddCommand(newJobsListCmd())
+	cmd.AddCommand(newJobsShowCmd())
+	cmd.AddCommand(newJobsReleaseCmd())
+	cmd.AddCommand(newJobsRetryCmd())
+
+	return cmd
+}
+
+func newJobsListCmd() *cobra.Command {
+	var opts client.JobListOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List recent jobs",
+		Long:  `List your organization's jobs, newest first, optionally filtered by habitat, queue, or status.`,
+		Example: `  mush jobs list
+  mush jobs list --status failed
+  mush jobs list --queue <queue-id> --limit 50 --json`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			_, apiClient, err := apiClientFactory()
+			if err != nil {
+				return err
+			}
+
+			spin := out.Spinner("Fetching jobs")
+			spin.Start()
+
+			jobs, err := apiClient.ListJobs(cmd.Context(), opts)
+			if err != nil {
+				spin.Stop()
+
+				return clierrors.NetworkFailed("Failed to fetch jobs", err).
+					WithHint("Check your network connection or run 'mush doctor'")
+			}
+
+			spin.StopWithSuccess("Found jobs")
+
+			if out.JSON {
+				if err := out.PrintJSON(map[string]any{"items": jobs}); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			if len(jobs) == 0 {
+				out.Muted("No jobs found.")
+				return nil
+			}
+
+			out.Println()
+
+			out.Print("%-36s %-10s %-7s %-20s %s\n", "ID", "STATUS", "ATTEMPT", "CREATED", "NAME")
+			out.Print("%-36s %-10s %-7s %-20s %s\n", "--", "------", "-------", "-------", "----")
+
+			for i := range jobs {
+				job := &jobs[i]
+
+				out.Print("%-36s %-10s %-7s %-20s %s\n",
+					job.ID,
+					job.Status,
+					fmt.Sprintf("%d/%d", job.AttemptNumber, job.MaxAttempts),
+					job.CreatedAt.Local().Format(time.DateTime),
+					job.GetDisplayName(),
+				)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.HabitatID, "habitat", "", "Only jobs in this habitat (ID)")
+	cmd.Flags().StringVar(&opts.QueueID, "queue", "", "Only jobs in this queue (ID)")
+	cmd.Flags().StringVar(&opts.Status, "status", "", "Only jobs with this status (for example running or failed)")
+	cmd.Flags().IntVar(&opts.Limit, "limit", 20, "Maximum number of jobs to list")
+
+	return cmd
+}
+
+func newJobsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <job-id>",
+		Short: "Show a job's details",
+		Long:  `Show a job's status, attempts, timing, and the error recorded by its last failed attempt.`,
+		Example: `  mush jobs show <job-id>
+  mush jobs show <job-id> --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeJobLogIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			jobID := args[0]
+
+			_, apiClient, err := apiClientFactory()
+			if err != nil {
+				return err
+			}
+
+			job, err := apiClient.GetJob(cmd.Context(), jobID)
+			if err != nil {
+				return jobRequestError(jobID, "Failed to fetch job", err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(job); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			printJob(out, job)
+
+			return nil
+		},
+	}
+}
+
+func newJobsReleaseCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "release <job-id>",
+		Short: "Release a claimed job back to its queue",
+		Long: `Release a claimed job back to its queue so another worker can claim it. The
+worker holding the job loses its claim, so use this for a job whose worker is
+stuck or gone. Requires confirmation unless --force is passed.`,
+		Example: `  mush jobs release <job-id>
+  mush jobs release <job-id> --force`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeJobLogIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			jobID := args[0]
+
+			if !force {
+				if out.NoInput {
+					return clierrors.ConfirmationRequired("release")
+				}
+
+				confirmed, err := prompt.New(out).Confirm(fmt.Sprintf("Release job %s back to its queue?", jobID), false)
+				if err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read confirmation", err)
+				}
+
+				if !confirmed {
+					out.Info("Release canceled")
+					return nil
+				}
+			}
+
+			_, apiClient, err := apiClientFactory()
+			if err != nil {
+				return err
+			}
+
+			if err := apiClient.ReleaseJob(cmd.Context(), jobID); err != nil {
+				return jobRequestError(jobID, "Failed to release job", err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(map[string]any{"id": jobID, "released": true}); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			out.Success("Released job %s back to its queue", jobID)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+func newJobsRetryCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retry <job-id>",
+		Short: "Run a failed job again",
+		Long:  `Queue a failed job for another attempt. The job keeps its ID and counts the new attempt.`,
+		Example: `  mush jobs retry <job-id>
+  mush jobs retry <job-id> --json`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeJobLogIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			jobID := args[0]
+
+			_, apiClient, err := apiClientFactory()
+			if err != nil {
+				return err
+			}
+
+			job, err := apiClient.RetryJob(cmd.Context(), jobID)
+			if err != nil {
+				return jobRequestError(jobID, "Failed to retry job", err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(job); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			out.Success("Queued job %s for attempt %d", job.ID, job.AttemptNumber)
+
+			return nil
+		},
+	}
+}
+
+func printJob(out *output.Writer, job *client.Job) {
+	status := job.Status
+	if job.StatusReason != "" {
+		status += " (" + job.StatusReason + ")"
+	}
+
+	out.Print("Job:       %s\n", job.ID)
+	out.Print("Name:      %s\n", job.GetDisplayName())
+	out.Print("Status:    %s\n", status)
+	out.Print("Attempt:   %d/%d\n", job.AttemptNumber, job.MaxAttempts)
+
+	for _, field := range []struct{ label, value string }{
+		{"Habitat:   ", job.HabitatID},
+		{"Queue:     ", job.QueueID},
+		{"Worker:    ", job.WorkerID},
+	} {
+		if field.value != "" {
+			out.Print("%s%s\n", field.label, field.value)
+		}
+	}
+
+	out.Print("Created:   %s\n", job.CreatedAt.Local().Format(time.DateTime))
+
+	if job.StartedAt != nil {
+		out.Print("Started:   %s\n", job.StartedAt.Local().Format(time.DateTime))
+	}
+
+	if job.CompletedAt != nil {
+		out.Print("Completed: %s\n", job.CompletedAt.Local().Format(time.DateTime))
+	}
+
+	if job.DurationMs != nil {
+		out.Print("Duration:  %s\n", (time.Duration(*job.DurationMs) * time.Millisecond).Round(time.Second))
+	}
+
+	if job.NextRetryAt != nil {
+		out.Print("Next try:  %s\n", job.NextRetryAt.Local().Format(time.DateTime))
+	}
+
+	if job.ErrorCode != "" || job.ErrorMessage != "" {
+		out.Print("Error:     %s: %s\n", job.ErrorCode, job.ErrorMessage)
+	}
+}
+
+// jobRequestError reports a failed request about jobID, pointing at the job
+// rather than the network when the platform answered.
+func jobRequestError(jobID, message string, err error) error {
+	var statusErr *client.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.Status {
+		case http.StatusNotFound:
+			return clierrors.JobNotFound(jobID).WithRequestID(statusErr.RequestID)
+		case http.StatusConflict:
+			return clierrors.Wrap(clierrors.ExitGeneral, message, err).
+				WithHint(fmt.Sprintf("The job's status does not allow this; run 'mush jobs show %s' to check it", jobID))
+		}
+	}
+
+	return clierrors.NetworkFailed(message, err).
+		WithHint("Check your network connection or run 'mush doctor'")
+}
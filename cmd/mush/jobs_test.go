@@ -0,0 +1,143 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
+)
+
+func jobsMockClient(t *testing.T, handle func(r *http.Request) *http.Response) *client.Client {
+	t.Helper()
+
+	hc := &http.Client{Transport: workerRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		if resp := handle(r); resp != nil {
+			return resp, nil
+		}
+
+		t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+
+		return nil, io.EOF
+	})}
+
+	return client.NewWithHTTPClient("https://api.test", "test-key", hc)
+}
+
+func runJobsCmd(t *testing.T, out *output.Writer, cmd *cobra.Command, args ...string) error {
+	t.Helper()
+
+	cmd.SetArgs(args)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	return cmd.Execute()
+}
+
+func TestJobsList_JSON(t *testing.T) {
+	// Status messages go to stderr in JSON mode; only stdout is the result.
+	var buf bytes.Buffer
+
+	out := output.NewWriter(&buf, io.Discard, &terminal.Info{NoColor: true, Width: 80, Height: 24})
+	out.JSON = true
+
+	withMockAPIClient(t, jobsMockClient(t, func(r *http.Request) *http.Response {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/runner/jobs" {
+			return nil
+		}
+
+		if got := r.URL.Query().Get("status"); got != "failed" {
+			t.Fatalf("status filter = %q, want failed", got)
+		}
+
+		return workerJSONResponse(http.StatusOK, `{"data":[{"id":"job-1","status":"failed","attemptNumber":3,"maxAttempts":3}]}`)
+	}))
+
+	if err := runJobsCmd(t, out, newJobsListCmd(), "--status", "failed"); err != nil {
+		t.Fatalf("jobs list should succeed: %v", err)
+	}
+
+	var got struct {
+		Items []client.Job `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("decode output %q: %v", buf.String(), err)
+	}
+
+	if len(got.Items) != 1 || got.Items[0].ID != "job-1" || got.Items[0].Status != "failed" {
+		t.Fatalf("items = %#v, want the failed job", got.Items)
+	}
+}
+
+func TestJobsShow_UnknownJob(t *testing.T) {
+	out, _ := testWriter()
+
+	withMockAPIClient(t, jobsMockClient(t, func(r *http.Request) *http.Response {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/runner/jobs/job-404" {
+			return nil
+		}
+
+		return workerJSONResponse(http.StatusNotFound, `{}`)
+	}))
+
+	err := runJobsCmd(t, out, newJobsShowCmd(), "job-404")
+
+	var cliErr *clierrors.CLIError
+	if !clierrors.As(err, &cliErr) || cliErr.Message != "Job not found: job-404" {
+		t.Fatalf("jobs show error = %v, want job not found", err)
+	}
+}
+
+func TestJobsRelease_Confirmation(t *testing.T) {
+	tests := []struct {
+		name         string
+		args         []string
+		wantReleased bool
+		wantCode     int
+	}{
+		{name: "non-interactive without force", args: []string{"job-1"}, wantCode: clierrors.ExitUsage},
+		{name: "force skips confirmation", args: []string{"job-1", "--force"}, wantReleased: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _ := testWriter()
+			out.NoInput = true
+
+			released := false
+
+			withMockAPIClient(t, jobsMockClient(t, func(r *http.Request) *http.Response {
+				if r.Method != http.MethodPost || r.URL.Path != "/v1/runner/jobs/job-1:release" {
+					return nil
+				}
+
+				released = true
+
+				return workerJSONResponse(http.StatusOK, `{}`)
+			}))
+
+			err := runJobsCmd(t, out, newJobsReleaseCmd(), tt.args...)
+
+			if tt.wantCode != 0 {
+				var cliErr *clierrors.CLIError
+				if !clierrors.As(err, &cliErr) || cliErr.Code != tt.wantCode {
+					t.Fatalf("jobs release error = %v, want exit code %d", err, tt.wantCode)
+				}
+			} else if err != nil {
+				t.Fatalf("jobs release should succeed: %v", err)
+			}
+
+			if released != tt.wantReleased {
+				t.Fatalf("released = %v, want %v", released, tt.wantReleased)
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/buildinfo"
 	"github.com/musher-dev/mush/internal/output"
 )
@@ -26,7 +28,7 @@ func run() (exitCode int) {
 	// Restore cursor visibility on panic to prevent hidden cursor if process crashes during spinner.
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Fprint(os.Stderr, "\033[?25h")
+			fmt.Fprint(os.Stderr, ansi.ShowCursor)
 			panic(r)
 		}
 	}()
@@ -37,9 +39,14 @@ func run() (exitCode int) {
 	out := rootOutputFactory()
 
 	rootCmd := newRootCmd()
-	if err := rootCmd.Execute(); err != nil {
-		return handleError(out, err)
+	started := time.Now()
+
+	executed, err := rootCmd.ExecuteC()
+	if err != nil {
+		exitCode = handleError(out, err)
 	}
 
-	return 0
+	recordUsage(executed, exitCode, time.Since(started))
+
+	return exitCode
 }
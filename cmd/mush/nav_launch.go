@@ -1,10 +1,11 @@
-//go:build unix
+//go:build unix || windows
 
 package main
 
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -16,6 +17,7 @@ import (
 	"github.com/musher-dev/mush/internal/client"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/observability"
 	"github.com/musher-dev/mush/internal/output"
 	"github.com/musher-dev/mush/internal/safeio"
 	"github.com/musher-dev/mush/internal/tui/nav"
@@ -79,6 +81,10 @@ func handleBundleLoadNavResult(cmd *cobra.Command, out *output.Writer, result *n
 
 	defer session.Cleanup()
 
+	if err := addExtraDirs(cmd, session, spec); err != nil {
+		return err
+	}
+
 	for _, w := range session.Warnings {
 		out.Warning("%s", w)
 	}
@@ -95,12 +101,23 @@ func handleBundleLoadNavResult(cmd *cobra.Command, out *output.Writer, result *n
 		BundleName:         resolved.Slug,
 		BundleVer:          resolved.Version,
 		BundleDir:          session.BundleDir,
+		BundleExtraDirs:    session.ExtraDirs,
 		BundleWorkDir:      session.WorkingDir,
 		BundleEnv:          session.Env,
 		RunnerConfig:       runnerConfig,
 		BundleSummary:      harness.SummarizeBundleManifest(&resolved.Manifest),
 	}
 
+	if sourceOpts, ok := navBundleSource(cmd, result); ok {
+		cfg.BundleReload = (&bundleReloader{
+			source:  sourceOpts,
+			session: session,
+			spec:    spec,
+			mapper:  mapper,
+			logger:  observability.FromContext(cmd.Context()).With(slog.String("component", "bundle")),
+		}).Reload
+	}
+
 	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
 	defer stop()
 
@@ -213,24 +230,25 @@ func handleBundleInstallNavResult(_ *cobra.Command, out *output.Writer, result *
 		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to get working directory", err)
 	}
 
-	installed, installErr := bundle.InstallFromCache(workDir, result.CachePath, &resolved.Manifest, mapper, result.Force)
+	installResult, installErr := bundle.InstallFromCache(workDir, result.CachePath, &resolved.Manifest, mapper, result.Force)
 	if installErr != nil {
 		return clierrors.Wrap(clierrors.ExitGeneral, "Bundle install failed", installErr)
 	}
 
-	for _, relPath := range installed {
+	for _, relPath := range installResult.Paths {
 		out.Success("Installed: %s", relPath)
 	}
 
 	ref := result.BundleNamespace + "/" + result.BundleSlug
 
 	trackErr := bundle.TrackInstall(workDir, &bundle.InstalledBundle{
-		Namespace: result.BundleNamespace,
-		Slug:      result.BundleSlug,
-		Ref:       ref,
-		Version:   result.BundleVer,
-		Harness:   normalized,
-		Assets:    installed,
+		Namespace:     result.BundleNamespace,
+		Slug:          result.BundleSlug,
+		Ref:           ref,
+		Version:       result.BundleVer,
+		Harness:       normalized,
+		Assets:        installResult.Paths,
+		MergedConfigs: installResult.MergedConfigs,
 	})
 	if trackErr != nil {
 		out.Warning("Failed to track install: %v", trackErr)
@@ -247,7 +265,7 @@ func loadRunnerConfigIfAvailable(cmd *cobra.Command, out *output.Writer) *client
 		return nil
 	}
 
-	runnerConfig, cfgErr := c.GetRunnerConfig(cmd.Context())
+	runnerConfig, cfgErr := c.GetRunnerConfig(cmd.Context(), "")
 	if cfgErr != nil {
 		out.Warning("Runner config unavailable, continuing without MCP provisioning: %v", cfgErr)
 		return nil
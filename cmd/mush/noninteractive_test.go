@@ -0,0 +1,322 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/update"
+)
+
+// promptMatrixTimeout bounds each command; a command that blocks on stdin
+// instead of failing fast hits it.
+const promptMatrixTimeout = 10 * time.Second
+
+// promptMatrixCase drives a command to one of its interactive prompts with
+// --no-input. The command must fail fast with an error whose message or
+// hint names every non-interactive equivalent.
+type promptMatrixCase struct {
+	name        string
+	args        []string
+	setup       func(t *testing.T)
+	equivalents []string
+}
+
+// promptMatrix lists every interactive prompt in the CLI. Commands that
+// prompt must have an entry here; commands that never prompt belong in
+// promptFreeCommands.
+var promptMatrix = []promptMatrixCase{
+	{
+		name:        "init api key",
+		args:        []string{"init", "--force"},
+		setup:       func(t *testing.T) { t.Setenv("MUSHER_API_KEY", "") },
+		equivalents: []string{"--api-key", "MUSHER_API_KEY"},
+	},
+	{
+		name:        "auth login api key",
+		args:        []string{"auth", "login"},
+		setup:       func(t *testing.T) { t.Setenv("MUSHER_API_KEY", "") },
+		equivalents: []string{"--api-key", "MUSHER_API_KEY"},
+	},
+	{
+		name:        "history prune confirmation",
+		args:        []string{"history", "prune", "--older-than", "1ns"},
+		setup:       setupPromptMatrixHistory,
+		equivalents: []string{"--force"},
+	},
+	{
+		name:        "jobs release confirmation",
+		args:        []string{"jobs", "release", "job-1"},
+		setup:       func(*testing.T) {},
+		equivalents: []string{"--force"},
+	},
+	{
+		name:        "bundle uninstall confirmation",
+		args:        []string{"bundle", "uninstall", "acme/kit", "--harness", "claude"},
+		setup:       setupPromptMatrixBundle,
+		equivalents: []string{"--force"},
+	},
+	{
+		name:        "uninstall confirmation",
+		args:        []string{"uninstall"},
+		setup:       setupPromptMatrixUninstall,
+		equivalents: []string{"--force"},
+	},
+	{
+		name:        "worker start habitat",
+		args:        []string{"worker", "start", "--dry-run"},
+		setup:       setupPromptMatrixWorker,
+		equivalents: []string{"--habitat", "MUSH_HABITAT"},
+	},
+	{
+		name: "worker start queue",
+		args: []string{"worker", "start", "--dry-run"},
+		setup: func(t *testing.T) {
+			setupPromptMatrixWorker(t)
+			t.Setenv("MUSH_HABITAT", "local")
+		},
+		equivalents: []string{"--queue", "MUSH_QUEUE"},
+	},
+}
+
+// promptFreeCommands never prompt, so --no-input changes nothing for them.
+var promptFreeCommands = []string{
+	"mush",
+	"mush auth logout",
+	"mush auth status",
+	"mush bench",
+	"mush bundle export",
+	"mush bundle fix-frontmatter",
+	"mush bundle import",
+	"mush bundle info",
+	"mush bundle install",
+	"mush bundle lint",
+	"mush bundle list",
+	"mush bundle load",
+	"mush bundle run",
+	"mush completion",
+	"mush config get",
+	"mush config list",
+	"mush config profiles list",
+	"mush config set",
+	"mush doctor",
+	"mush experimental",
+	"mush habitat list",
+	"mush history export",
+	"mush history list",
+	"mush history render",
+	"mush history replay",
+	"mush history show",
+	"mush history tui",
+	"mush history view",
+	"mush jobs list",
+	"mush jobs retry",
+	"mush jobs show",
+	"mush paths",
+	"mush telemetry disable",
+	"mush telemetry enable",
+	"mush telemetry status",
+	"mush update",
+	"mush version",
+	"mush worker logs",
+	"mush worker observe",
+	"mush worker reload",
+	"mush worker status",
+	"mush worker stop",
+}
+
+func setupPromptMatrixHistory(t *testing.T) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("MUSHER_HISTORY_DIR", dir)
+	t.Setenv("MUSHER_STATE_HOME", t.TempDir())
+
+	store, err := transcript.NewStore(transcript.StoreOptions{Dir: dir, SessionID: "session-1"})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func setupPromptMatrixBundle(t *testing.T) {
+	t.Helper()
+
+	workDir := t.TempDir()
+	t.Chdir(workDir)
+
+	if err := bundle.TrackInstall(workDir, &bundle.InstalledBundle{
+		Namespace: "acme",
+		Slug:      "kit",
+		Version:   "1.0.0",
+		Harness:   "claude",
+		Assets:    []string{".claude/agents/kit.md"},
+	}); err != nil {
+		t.Fatalf("TrackInstall() error = %v", err)
+	}
+}
+
+func setupPromptMatrixUninstall(t *testing.T) {
+	t.Helper()
+
+	setupUninstallHome(t)
+
+	original := uninstallInstallContext
+	uninstallInstallContext = func() update.InstallContext { return update.InstallContext{} }
+
+	t.Cleanup(func() { uninstallInstallContext = original })
+}
+
+// setupPromptMatrixWorker serves two habitats and two queues so worker start
+// has to choose.
+func setupPromptMatrixWorker(t *testing.T) {
+	t.Helper()
+
+	hc := &http.Client{Transport: workerRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/v1/runner/me":
+			return workerJSONResponse(http.StatusOK, `{"credentialName":"test-key","organizationName":"Test Organization"}`), nil
+		case "/v1/runner/habitats":
+			return workerJSONResponse(http.StatusOK, `{"data":[`+
+				`{"id":"hab-1","slug":"local","name":"Local","status":"online"},`+
+				`{"id":"hab-2","slug":"prod","name":"Prod","status":"online"}]}`), nil
+		case "/v1/runner/queues":
+			return workerJSONResponse(http.StatusOK, `{"data":[`+
+				`{"id":"q-1","slug":"default","name":"Default","status":"active","habitatId":"hab-1"},`+
+				`{"id":"q-2","slug":"batch","name":"Batch","status":"active","habitatId":"hab-1"}]}`), nil
+		default:
+			return workerJSONResponse(http.StatusNotFound, `{}`), nil
+		}
+	})}
+
+	withMockAPIClient(t, client.NewWithHTTPClient("https://api.test", "test-key", hc))
+}
+
+// blockStdin replaces stdin with a pipe that never delivers input, so a
+// command that reads it blocks rather than seeing EOF.
+func blockStdin(t *testing.T) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe() error = %v", err)
+	}
+
+	original := os.Stdin
+	os.Stdin = r
+
+	t.Cleanup(func() {
+		os.Stdin = original
+		_ = w.Close()
+		_ = r.Close()
+	})
+}
+
+func TestNoInput_PromptsNameTheirEquivalents(t *testing.T) {
+	for _, tc := range promptMatrix {
+		t.Run(tc.name, func(t *testing.T) {
+			home := t.TempDir()
+			t.Setenv("HOME", home)
+			t.Setenv("MUSHER_HOME", filepath.Join(home, "musher"))
+			t.Setenv("MUSH_HABITAT", "")
+			t.Setenv("MUSH_QUEUE", "")
+			t.Setenv("MUSHER_UPDATE_DISABLED", "1")
+
+			tc.setup(t)
+			blockStdin(t)
+
+			originalFactory := rootOutputFactory
+			rootOutputFactory = func() *output.Writer {
+				out, _ := output.NewCapture()
+				return out
+			}
+
+			t.Cleanup(func() { rootOutputFactory = originalFactory })
+
+			root := newRootCmd()
+			root.SetArgs(append([]string{"--no-input"}, tc.args...))
+
+			done := make(chan error, 1)
+			go func() { done <- root.Execute() }()
+
+			var err error
+
+			select {
+			case err = <-done:
+			case <-time.After(promptMatrixTimeout):
+				t.Fatalf("mush %s --no-input blocked waiting for input", strings.Join(tc.args, " "))
+			}
+
+			var cliErr *clierrors.CLIError
+			if !errors.As(err, &cliErr) {
+				t.Fatalf("error = %v, want a CLIError", err)
+			}
+
+			cmd, _, findErr := root.Find(tc.args)
+			if findErr != nil {
+				t.Fatalf("Find(%q) error = %v", tc.args, findErr)
+			}
+
+			text := cliErr.Message + "\n" + cliErr.Hint
+
+			for _, equivalent := range tc.equivalents {
+				if !strings.Contains(text, equivalent) {
+					t.Errorf("error %q does not name %s", text, equivalent)
+				}
+
+				if name, ok := strings.CutPrefix(equivalent, "--"); ok && cmd.Flag(name) == nil {
+					t.Errorf("%s has no %s flag", cmd.CommandPath(), equivalent)
+				}
+			}
+		})
+	}
+}
+
+func TestNoInput_EveryCommandIsClassified(t *testing.T) {
+	root := newRootCmd()
+
+	prompting := make(map[string]bool)
+
+	for _, tc := range promptMatrix {
+		cmd, _, err := root.Find(tc.args)
+		if err != nil {
+			t.Fatalf("Find(%q) error = %v", tc.args, err)
+		}
+
+		prompting[cmd.CommandPath()] = true
+	}
+
+	var unclassified []string
+
+	for _, cmd := range collectAllCommands(root) {
+		if !cmd.Runnable() || cmd.Hidden {
+			continue
+		}
+
+		path := cmd.CommandPath()
+		if !prompting[path] && !slices.Contains(promptFreeCommands, path) {
+			unclassified = append(unclassified, path)
+		}
+	}
+
+	if len(unclassified) > 0 {
+		t.Errorf("commands missing from the non-interactive matrix:\n  %s\n\n"+
+			"Add a promptMatrix case for each prompt, or list the command in promptFreeCommands.",
+			strings.Join(unclassified, "\n  "))
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/update"
+)
+
+// releaseNotesFetchTimeout bounds the release notes lookup on the first run
+// of a new version, since it delays the command's exit.
+const releaseNotesFetchTimeout = 3 * time.Second
+
+// printReleaseNotes renders release notes in the terminal, truncated to
+// update.ReleaseNotesMaxLines with a link to the full release page.
+func printReleaseNotes(out *output.Writer, notes update.ReleaseNotes) {
+	url := notes.URL
+	if url == "" {
+		url = update.ReleasePageURL(notes.Version)
+	}
+
+	lines, truncated := update.FormatReleaseNotes(notes.Body, update.ReleaseNotesMaxLines)
+
+	out.Print("\n")
+	out.Info("What's new in v%s", notes.Version)
+
+	for _, line := range lines {
+		out.Print("  %s\n", line)
+	}
+
+	if truncated {
+		out.Muted("  More at %s", url)
+	} else {
+		out.Muted("  %s", url)
+	}
+}
+
+// showNewVersionNotes prints the release notes once, on the first run of a
+// version installed by the background updater or a package manager. Nothing
+// is shown when another process holds the update lock; the notes then appear
+// on a later run.
+func showNewVersionNotes(ctx context.Context, out *output.Writer, currentVersion string) {
+	_ = update.WithAgentLock(func() error {
+		if claimReleaseNotes(currentVersion) {
+			printReleaseNotes(out, fetchReleaseNotes(ctx, currentVersion))
+		}
+
+		return nil
+	})
+}
+
+// claimReleaseNotes records currentVersion as the last version whose notes
+// were shown and reports whether they are still due. The version is recorded
+// before the notes are fetched so a failed lookup is not retried on every
+// command; the first run after release notes were introduced only records it.
+func claimReleaseNotes(currentVersion string) bool {
+	state, err := update.LoadState()
+	if err != nil {
+		return false
+	}
+
+	pending := state.NotesPending(currentVersion)
+	if !pending && state.NotesShownVersion != "" {
+		return false
+	}
+
+	state.NotesShownVersion = currentVersion
+
+	return update.SaveState(state) == nil && pending
+}
+
+// fetchReleaseNotes looks up the notes for version, falling back to a link
+// to the release page when they cannot be fetched in time.
+func fetchReleaseNotes(ctx context.Context, version string) update.ReleaseNotes {
+	ctx, cancel := context.WithTimeout(ctx, releaseNotesFetchTimeout)
+	defer cancel()
+
+	updater, err := update.NewUpdater()
+	if err != nil {
+		return update.ReleaseNotes{Version: version}
+	}
+
+	notes, err := updater.FetchReleaseNotes(ctx, version)
+	if err != nil {
+		return update.ReleaseNotes{Version: version}
+	}
+
+	return notes
+}
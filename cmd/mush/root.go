@@ -7,8 +7,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/musher-dev/mush/internal/config"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
 	"github.com/musher-dev/mush/internal/tui/nav"
 	"github.com/musher-dev/mush/internal/validate"
 )
@@ -26,6 +28,8 @@ func newRootCmd() *cobra.Command {
 		logStderr  string
 		apiURL     string
 		apiKey     string
+		profile    string
+		refreshID  bool
 	)
 
 	out := rootOutputFactory()
@@ -96,6 +100,24 @@ Get started:  mush bundle load`,
 				}
 			}
 
+			if name := pickFlagOrEnv(profile, paths.ProfileEnv, ""); name != "" {
+				if err := config.ValidateProfileName(name); err != nil {
+					return &clierrors.CLIError{
+						Message: fmt.Sprintf("Invalid profile: %v", err),
+						Hint:    "Run 'mush config profiles list' to see the configured profiles",
+						Code:    clierrors.ExitUsage,
+					}
+				}
+
+				if setErr := os.Setenv(paths.ProfileEnv, name); setErr != nil {
+					return &clierrors.CLIError{
+						Message: fmt.Sprintf("Failed to apply profile: %v", setErr),
+						Hint:    "Check your shell environment and try again",
+						Code:    clierrors.ExitUsage,
+					}
+				}
+			}
+
 			if strings.TrimSpace(apiKey) != "" {
 				if setErr := os.Setenv("MUSHER_API_KEY", apiKey); setErr != nil {
 					return &clierrors.CLIError{
@@ -106,6 +128,9 @@ Get started:  mush bundle load`,
 				}
 			}
 
+			refreshIdentity = refreshID
+			apiURLOverridden = strings.TrimSpace(apiURL) != ""
+
 			runtimeState, err := configureRootRuntime(
 				cmd, out, jsonOutput, quiet, noInput, noColor, logLevel, logFormat, logFile, logStderr,
 			)
@@ -113,6 +138,8 @@ Get started:  mush bundle load`,
 				return err
 			}
 
+			loadCustomHarnesses(runtimeState.out)
+
 			if shouldBackgroundCheck(cmd, version, runtimeState.out) {
 				launchDetachedUpdateAgent()
 			}
@@ -121,6 +148,10 @@ Get started:  mush bundle load`,
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, _ []string) error {
 			if shouldShowUpdateNotice(cmd, version, out) {
+				if out.Terminal().IsTTY {
+					showNewVersionNotes(cmd.Context(), out, version)
+				}
+
 				showUpdateNotice(out, version)
 			}
 
@@ -140,6 +171,8 @@ Get started:  mush bundle load`,
 	rootCmd.PersistentFlags().StringVar(&logStderr, "log-stderr", "", "Structured logging to stderr: auto, on, off")
 	rootCmd.PersistentFlags().StringVar(&apiURL, "api-url", "", "Override Musher API URL for this command")
 	rootCmd.PersistentFlags().StringVar(&apiKey, "api-key", "", "API key override (prefer MUSHER_API_KEY env var)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Config profile to use for this command (env: MUSH_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&refreshID, "refresh-identity", false, "Validate the API key instead of using the cached identity")
 
 	_ = rootCmd.PersistentFlags().MarkHidden("log-level")
 	_ = rootCmd.PersistentFlags().MarkHidden("log-format")
@@ -185,10 +218,18 @@ func registerRootCommands(rootCmd *cobra.Command) {
 	workerCmd.GroupID = "advanced"
 	rootCmd.AddCommand(workerCmd)
 
+	benchCmd := newBenchCmd()
+	benchCmd.GroupID = "advanced"
+	rootCmd.AddCommand(benchCmd)
+
 	habitatCmd := newHabitatCmd()
 	habitatCmd.GroupID = "advanced"
 	rootCmd.AddCommand(habitatCmd)
 
+	jobsCmd := newJobsCmd()
+	jobsCmd.GroupID = "advanced"
+	rootCmd.AddCommand(jobsCmd)
+
 	authCmd := newAuthCmd()
 	authCmd.GroupID = "account"
 	rootCmd.AddCommand(authCmd)
@@ -201,6 +242,10 @@ func registerRootCommands(rootCmd *cobra.Command) {
 	historyCmd.GroupID = "account"
 	rootCmd.AddCommand(historyCmd)
 
+	telemetryCmd := newTelemetryCmd()
+	telemetryCmd.GroupID = "account"
+	rootCmd.AddCommand(telemetryCmd)
+
 	initCmd := newInitCmd()
 	initCmd.GroupID = "setup"
 	rootCmd.AddCommand(initCmd)
@@ -228,6 +273,10 @@ func registerRootCommands(rootCmd *cobra.Command) {
 	pathsCmd.GroupID = "setup"
 	rootCmd.AddCommand(pathsCmd)
 
+	uninstallCmd := newUninstallCmd()
+	uninstallCmd.GroupID = "setup"
+	rootCmd.AddCommand(uninstallCmd)
+
 	versionCmd := newVersionCmd()
 	versionCmd.GroupID = "setup"
 	rootCmd.AddCommand(versionCmd)
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/config"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/telemetry"
+)
+
+// usageFlushTimeout bounds sending the daily usage report, since it delays
+// the command's exit.
+const usageFlushTimeout = 2 * time.Second
+
+// telemetryCollected describes every field of a usage report, shown when
+// telemetry is enabled. Keep in sync with docs/telemetry.md.
+var telemetryCollected = []string{
+	"Which mush commands ran (e.g. \"mush bundle load\"), without arguments or flag values",
+	"How many times each ran, their total duration, and counts of failures by class (auth, network, ...)",
+	"The mush version, operating system, and CPU architecture",
+	"A random install ID, deleted by 'mush telemetry disable'",
+}
+
+// TelemetryStatus is the JSON output of `mush telemetry status`.
+type TelemetryStatus struct {
+	Enabled    bool              `json:"enabled"`
+	OptedIn    bool              `json:"opted_in"`
+	DoNotTrack bool              `json:"do_not_track"`
+	Dir        string            `json:"dir"`
+	Pending    *telemetry.Report `json:"pending,omitempty"`
+}
+
+func newTelemetryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Manage anonymous usage telemetry",
+		Long: `Manage opt-in, anonymous usage telemetry.
+
+Telemetry is off unless you enable it. When enabled, mush records which
+commands ran, how they exited, and how long they took, aggregates them
+locally, and sends a report at most once a day. Arguments, flag values,
+prompts, job output, and account details are never recorded.
+Setting DO_NOT_TRACK=1 turns telemetry off regardless of this setting.`,
+	}
+
+	cmd.AddCommand(newTelemetryStatusCmd())
+	cmd.AddCommand(newTelemetryEnableCmd())
+	cmd.AddCommand(newTelemetryDisableCmd())
+
+	return cmd
+}
+
+func newTelemetryStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether telemetry is enabled and what is queued",
+		Long: `Show whether usage telemetry is enabled and the usage queued locally
+since the last report. Use --json to see the exact payload the next report
+would send.`,
+		Example: `  mush telemetry status
+  mush telemetry status --json`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			store, err := telemetry.DefaultStore()
+			if err != nil {
+				return clierrors.ConfigFailed("resolve telemetry directory", err)
+			}
+
+			cfg := config.Load()
+			status := TelemetryStatus{
+				OptedIn:    cfg.TelemetryEnabled(),
+				DoNotTrack: telemetry.DoNotTrack(),
+				Dir:        store.Dir(),
+			}
+			status.Enabled = status.OptedIn && !status.DoNotTrack
+
+			if status.Enabled {
+				pending, pendingErr := store.Pending()
+				if pendingErr != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read the telemetry queue", pendingErr)
+				}
+
+				status.Pending = pending
+			}
+
+			if out.JSON {
+				return out.PrintJSON(status)
+			}
+
+			printTelemetryStatus(out, &status)
+
+			return nil
+		},
+	}
+}
+
+func printTelemetryStatus(out *output.Writer, status *TelemetryStatus) {
+	switch {
+	case status.Enabled:
+		out.Print("Telemetry: enabled\n")
+	case status.OptedIn && status.DoNotTrack:
+		out.Print("Telemetry: disabled by DO_NOT_TRACK\n")
+	default:
+		out.Print("Telemetry: disabled\n")
+	}
+
+	if status.Pending == nil {
+		if !status.OptedIn {
+			out.Muted("Run 'mush telemetry enable' to share anonymous usage")
+		}
+
+		return
+	}
+
+	invocations := 0
+	for _, stats := range status.Pending.Commands {
+		invocations += stats.Count
+	}
+
+	out.Print("Queued:    %d runs of %d commands since %s\n",
+		invocations, len(status.Pending.Commands), status.Pending.PeriodStart.Local().Format(time.RFC3339))
+	out.Print("Next send: after %s\n", status.Pending.PeriodStart.Add(telemetry.FlushInterval).Local().Format(time.RFC3339))
+	out.Print("Data:      %s\n", status.Dir)
+	out.Muted("Run 'mush telemetry status --json' to see the exact payload")
+}
+
+func newTelemetryEnableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "enable",
+		Short:   "Share anonymous usage telemetry",
+		Long:    `Opt in to anonymous usage telemetry and show exactly what is collected.`,
+		Example: `  mush telemetry enable`,
+		Args:    noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			if err := config.Load().Set("telemetry.enabled", true); err != nil {
+				return clierrors.ConfigFailed("enable telemetry", err)
+			}
+
+			out.Success("Telemetry enabled. Thank you!")
+			out.Println()
+			out.Println("Collected, aggregated locally and sent at most once a day:")
+
+			for _, item := range telemetryCollected {
+				out.Print("  - %s\n", item)
+			}
+
+			out.Println()
+			out.Println("Never collected: arguments, flag values, prompts, job output, or account details.")
+
+			if telemetry.DoNotTrack() {
+				out.Warning("DO_NOT_TRACK is set, so nothing is recorded until it is unset")
+			}
+
+			out.Muted("Run 'mush telemetry disable' to opt out and delete local usage data")
+
+			return nil
+		},
+	}
+}
+
+func newTelemetryDisableCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "disable",
+		Short:   "Stop sharing usage telemetry",
+		Long:    `Opt out of usage telemetry and delete the local usage queue and anonymous install ID.`,
+		Example: `  mush telemetry disable`,
+		Args:    noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			if err := config.Load().Set("telemetry.enabled", false); err != nil {
+				return clierrors.ConfigFailed("disable telemetry", err)
+			}
+
+			store, err := telemetry.DefaultStore()
+			if err != nil {
+				return clierrors.ConfigFailed("resolve telemetry directory", err)
+			}
+
+			if err := store.Reset(); err != nil {
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to delete local usage data", err)
+			}
+
+			out.Success("Telemetry disabled. Local usage data deleted.")
+
+			return nil
+		},
+	}
+}
+
+// recordUsage adds the command that just ran to the telemetry queue and sends
+// the aggregated report when one is due. It does nothing unless the user
+// opted in, and never affects the command's outcome.
+func recordUsage(cmd *cobra.Command, exitCode int, elapsed time.Duration) {
+	if cmd == nil || hiddenCommand(cmd) || telemetry.DoNotTrack() {
+		return
+	}
+
+	cfg := config.Load()
+	if !cfg.TelemetryEnabled() {
+		return
+	}
+
+	store, err := telemetry.DefaultStore()
+	if err != nil {
+		return
+	}
+
+	event := telemetry.Event{Command: strings.TrimSpace(cmd.CommandPath()), ExitCode: exitCode, Duration: elapsed}
+	if store.Record(event) != nil || !store.Due() {
+		return
+	}
+
+	apiClient, err := newAPIClientFromConfig(cfg, "")
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), usageFlushTimeout)
+	defer cancel()
+
+	_ = store.Flush(ctx, apiClient.SendUsageReport)
+}
+
+// hiddenCommand reports whether cmd or any parent is hidden, such as shell
+// completion helpers and internal agents, which are not worth recording.
+func hiddenCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden {
+			return true
+		}
+	}
+
+	return false
+}
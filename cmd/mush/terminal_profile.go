@@ -0,0 +1,65 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/config"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
+)
+
+// terminalProfileEnv carries --terminal-profile to the harness runtime,
+// which reads terminal.profile from the config.
+const terminalProfileEnv = "MUSHER_TERMINAL_PROFILE"
+
+// addTerminalProfileFlag registers --terminal-profile on a command that
+// starts the harness TUI.
+func addTerminalProfileFlag(cmd *cobra.Command, name *string) {
+	names := terminal.ProfileNames()
+
+	cmd.Flags().StringVar(name, "terminal-profile", "",
+		"TUI settings for your terminal: "+strings.Join(names[:len(names)-1], ", ")+", or "+names[len(names)-1]+" (overrides terminal.profile)")
+}
+
+// applyTerminalProfile resolves --terminal-profile, or terminal.profile
+// when the flag is empty, and applies the profile for this run.
+func applyTerminalProfile(out *output.Writer, name string) error {
+	source := "--terminal-profile"
+	if name == "" {
+		name = config.Load().TerminalProfile()
+		source = "terminal.profile"
+	}
+
+	profile, err := terminal.ResolveProfile(name)
+	if err != nil {
+		return &clierrors.CLIError{
+			Message: "Invalid " + source + ": " + err.Error(),
+			Hint:    "Run 'mush config set terminal.profile auto' to detect it from the terminal",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	if err := os.Setenv(terminalProfileEnv, profile.Name); err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to apply the terminal profile", err)
+	}
+
+	applyTerminalColor(out, profile)
+
+	return nil
+}
+
+// applyTerminalColor turns colored output off for a profile without color,
+// for mush and, through NO_COLOR, the harness screen and the processes it
+// starts.
+func applyTerminalColor(out *output.Writer, profile terminal.Profile) {
+	if profile.Color {
+		return
+	}
+
+	out.SetNoColor(true)
+	_ = os.Setenv("NO_COLOR", "1")
+}
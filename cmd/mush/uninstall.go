@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/musher-dev/mush/internal/auth"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/prompt"
+	"github.com/musher-dev/mush/internal/safeio"
+	"github.com/musher-dev/mush/internal/update"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+// uninstallInstallContext is swapped in tests so they never delete the test binary.
+var uninstallInstallContext = update.CurrentInstallContext
+
+// uninstallTarget is a file or directory removed by `mush uninstall`.
+type uninstallTarget struct {
+	Label string
+	Path  string
+}
+
+func newUninstallCmd() *cobra.Command {
+	var (
+		keepConfig bool
+		force      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove mush from this machine",
+		Long: `Remove everything install.sh and normal use leave behind: the mush binary,
+installed shell completion scripts, and the state, cache, runtime, data, and
+config directories. Stored credentials, including each profile's, are
+deleted from the keyring.
+
+Workers that were registered from this machine but never deregistered (for
+example after a crash) are deregistered first. Stop running workers before
+uninstalling.
+
+Use --keep-config to keep configuration and stored credentials for a later
+reinstall. Homebrew installs keep the binary; remove it with 'brew uninstall mush'.
+Requires confirmation unless --force is passed.`,
+		Example: `  mush uninstall
+  mush uninstall --keep-config
+  mush uninstall --force`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+			cfg := config.Load()
+			install := uninstallInstallContext()
+
+			regs, err := worker.LingeringRegistrations()
+			if err != nil {
+				out.Warning("Could not read worker registrations: %v", err)
+			}
+
+			apiURLs := uninstallAPIURLs(config.LoadProfile(paths.DefaultProfile).APIURL(), regs)
+			profiles := cfg.Profiles()
+			targets := planUninstall(install, keepConfig)
+
+			if len(regs) == 0 && len(targets) == 0 && keepConfig {
+				out.Muted("Nothing to uninstall")
+				return nil
+			}
+
+			out.Print("This will:\n")
+
+			for _, reg := range regs {
+				out.Print("  deregister worker %s (%s)\n", reg.WorkerID, reg.APIURL)
+			}
+
+			if !keepConfig {
+				for _, apiURL := range apiURLs {
+					out.Print("  delete stored credentials for %s\n", apiURL)
+				}
+
+				for _, name := range profiles {
+					out.Print("  delete stored credentials for profile %s\n", name)
+				}
+			}
+
+			for _, target := range targets {
+				out.Print("  remove %-11s %s\n", target.Label, target.Path)
+			}
+
+			if !force {
+				if out.NoInput {
+					return clierrors.ConfirmationRequired("uninstall")
+				}
+
+				prompter := prompt.New(out)
+
+				confirmed, promptErr := prompter.Confirm("Uninstall mush from this machine?", false)
+				if promptErr != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read confirmation", promptErr)
+				}
+
+				if !confirmed {
+					out.Info("Uninstall canceled")
+					return nil
+				}
+			}
+
+			failures := deregisterLingeringWorkers(out, cfg, regs)
+
+			if !keepConfig {
+				withProfile(paths.DefaultProfile, func() {
+					for _, apiURL := range apiURLs {
+						// DeleteAPIKey fails when nothing was stored, which is fine here.
+						if auth.DeleteAPIKey(apiURL) == nil {
+							out.Success("Deleted stored credentials for %s", apiURL)
+						}
+
+						// The key only decrypts the runner config cache removed below.
+						_ = auth.DeleteRunnerConfigCacheKey(apiURL)
+					}
+				})
+
+				for _, name := range profiles {
+					withProfile(name, func() {
+						apiURL := config.LoadProfile(name).APIURL()
+						if auth.DeleteAPIKey(apiURL) == nil {
+							out.Success("Deleted stored credentials for profile %s", name)
+						}
+
+						_ = auth.DeleteRunnerConfigCacheKey(apiURL)
+					})
+				}
+			}
+
+			for _, target := range targets {
+				if target.Label == "binary" && failures > 0 {
+					out.Warning("Kept %s so 'mush uninstall' can be retried", target.Path)
+					continue
+				}
+
+				if removeErr := os.RemoveAll(target.Path); removeErr != nil {
+					out.Warning("Could not remove %s: %v", target.Path, removeErr)
+
+					failures++
+
+					continue
+				}
+
+				out.Success("Removed %s", target.Path)
+			}
+
+			switch {
+			case install.Source == update.InstallSourceHomebrew:
+				out.Info("Run 'brew uninstall mush' to remove the binary")
+			case !install.ExecPathKnown:
+				out.Warning("Could not locate the mush binary; remove it manually")
+			case install.NeedsElevation:
+				out.Info("Run 'sudo rm %s' to remove the binary", install.ExecPath)
+			}
+
+			if failures > 0 {
+				return clierrors.New(clierrors.ExitGeneral, fmt.Sprintf("Uninstall finished with %d error(s)", failures)).
+					WithHint("Fix the errors above and run 'mush uninstall' again, or remove the paths manually")
+			}
+
+			out.Success("Mush has been uninstalled")
+
+			if os.Getenv("MUSHER_API_KEY") != "" {
+				out.Warning("MUSHER_API_KEY environment variable is still set")
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&keepConfig, "keep-config", false, "Keep configuration and stored credentials")
+	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+
+	return cmd
+}
+
+// deregisterLingeringWorkers deregisters each recorded worker with the
+// credentials stored for its API URL and returns the number of failures.
+func deregisterLingeringWorkers(out *output.Writer, cfg *config.Config, regs []worker.Registration) int {
+	if len(regs) == 0 {
+		return 0
+	}
+
+	httpClient, err := client.NewInstrumentedHTTPClient(cfg.CACertFile())
+	if err != nil {
+		out.Warning("Could not deregister workers: %v", err)
+		return len(regs)
+	}
+
+	var failures int
+
+	for _, reg := range regs {
+		_, apiKey := auth.GetCredentials(reg.APIURL)
+		if apiKey == "" {
+			out.Warning("Skipped worker %s: no credentials for %s", reg.WorkerID, reg.APIURL)
+			_ = worker.ForgetRegistration(reg.WorkerID)
+
+			continue
+		}
+
+		apiClient := client.NewWithHTTPClient(reg.APIURL, apiKey, httpClient)
+		if deregErr := worker.Deregister(apiClient, reg.WorkerID, 0, 0); deregErr != nil {
+			// A worker the platform no longer knows has nothing left to clean up.
+			var statusErr *client.HTTPStatusError
+			if errors.As(deregErr, &statusErr) && statusErr.Status == http.StatusNotFound {
+				_ = worker.ForgetRegistration(reg.WorkerID)
+				continue
+			}
+
+			out.Warning("Could not deregister worker %s: %v", reg.WorkerID, deregErr)
+
+			failures++
+
+			continue
+		}
+
+		out.Success("Deregistered worker %s", reg.WorkerID)
+	}
+
+	return failures
+}
+
+// withProfile runs fn with the named profile selected. Stored credentials
+// are scoped to the profile in MUSH_PROFILE, so deleting another profile's
+// selects it first; the previous selection is restored after.
+func withProfile(name string, fn func()) {
+	previous, set := os.LookupEnv(paths.ProfileEnv)
+
+	_ = os.Setenv(paths.ProfileEnv, name)
+
+	defer func() {
+		if set {
+			_ = os.Setenv(paths.ProfileEnv, previous)
+		} else {
+			_ = os.Unsetenv(paths.ProfileEnv)
+		}
+	}()
+
+	fn()
+}
+
+// uninstallAPIURLs returns the configured API URL plus any other API URL a
+// lingering worker was registered against.
+func uninstallAPIURLs(configured string, regs []worker.Registration) []string {
+	seen := map[string]bool{configured: true}
+	urls := []string{configured}
+
+	for _, reg := range regs {
+		if !seen[reg.APIURL] {
+			seen[reg.APIURL] = true
+			urls = append(urls, reg.APIURL)
+		}
+	}
+
+	return urls
+}
+
+// planUninstall lists the existing paths to remove. The binary comes last
+// so a failure earlier leaves `mush uninstall` available for a retry.
+func planUninstall(install update.InstallContext, keepConfig bool) []uninstallTarget {
+	var targets []uninstallTarget
+
+	for _, path := range installedCompletionScripts() {
+		targets = append(targets, uninstallTarget{Label: "completion", Path: path})
+	}
+
+	roots := []struct {
+		label   string
+		resolve func() (string, error)
+		config  bool
+	}{
+		{label: "state", resolve: paths.StateRoot},
+		{label: "cache", resolve: paths.CacheRoot},
+		{label: "runtime", resolve: paths.RuntimeRoot},
+		{label: "data", resolve: paths.DataRoot, config: true},
+		{label: "config", resolve: paths.ConfigRoot, config: true},
+	}
+
+	for _, root := range roots {
+		if root.config && keepConfig {
+			continue
+		}
+
+		dir, err := root.resolve()
+		if err != nil || !pathExists(dir) {
+			continue
+		}
+
+		targets = append(targets, uninstallTarget{Label: root.label, Path: dir})
+	}
+
+	if install.ExecPathKnown && !install.NeedsElevation && install.Source != update.InstallSourceHomebrew {
+		targets = append(targets, uninstallTarget{Label: "binary", Path: install.ExecPath})
+	}
+
+	return targets
+}
+
+// installedCompletionScripts returns completion scripts for mush found in the
+// locations suggested by `mush completion --help` and the per-user defaults
+// of each shell.
+func installedCompletionScripts() []string {
+	candidates := []string{
+		"/etc/bash_completion.d/mush",
+		"/usr/local/etc/bash_completion.d/mush",
+		"/opt/homebrew/etc/bash_completion.d/mush",
+		"/usr/local/share/zsh/site-functions/_mush",
+		"/opt/homebrew/share/zsh/site-functions/_mush",
+	}
+
+	if home, err := os.UserHomeDir(); err == nil && home != "" {
+		dataHome := os.Getenv("XDG_DATA_HOME")
+		if !filepath.IsAbs(dataHome) {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if !filepath.IsAbs(configHome) {
+			configHome = filepath.Join(home, ".config")
+		}
+
+		candidates = append(candidates,
+			filepath.Join(dataHome, "bash-completion", "completions", "mush"),
+			filepath.Join(home, ".zfunc", "_mush"),
+			filepath.Join(home, ".zsh", "completions", "_mush"),
+			filepath.Join(configHome, "fish", "completions", "mush.fish"),
+		)
+	}
+
+	var found []string
+
+	for _, path := range candidates {
+		// Only remove files that are recognizably ours.
+		data, exists, err := safeio.ReadFileIfExists(path)
+		if err != nil || !exists || !bytes.Contains(data, []byte("mush")) {
+			continue
+		}
+
+		found = append(found, path)
+	}
+
+	return found
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/update"
+)
+
+func setupUninstallHome(t *testing.T) string {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("MUSHER_HOME", filepath.Join(home, "musher"))
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DATA_HOME", "")
+
+	for _, dir := range []string{"config", "data", "state", "cache"} {
+		if err := os.MkdirAll(filepath.Join(home, "musher", dir), 0o700); err != nil {
+			t.Fatalf("mkdir %s: %v", dir, err)
+		}
+	}
+
+	return home
+}
+
+func TestPlanUninstall_ListsExistingPaths(t *testing.T) {
+	home := setupUninstallHome(t)
+
+	fishDir := filepath.Join(home, ".config", "fish", "completions")
+	if err := os.MkdirAll(fishDir, 0o755); err != nil {
+		t.Fatalf("mkdir fish completions: %v", err)
+	}
+
+	fishScript := filepath.Join(fishDir, "mush.fish")
+	if err := os.WriteFile(fishScript, []byte("complete -c mush\n"), 0o600); err != nil {
+		t.Fatalf("write fish completion: %v", err)
+	}
+
+	// A same-named file that is not ours must be left alone.
+	zfunc := filepath.Join(home, ".zfunc")
+	if err := os.MkdirAll(zfunc, 0o755); err != nil {
+		t.Fatalf("mkdir zfunc: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(zfunc, "_mush"), []byte("#compdef other\n"), 0o600); err != nil {
+		t.Fatalf("write zsh completion: %v", err)
+	}
+
+	binary := filepath.Join(home, ".local", "bin", "mush")
+	install := update.InstallContext{ExecPath: binary, Source: update.InstallSourceStandalone, ExecPathKnown: true}
+
+	targets := planUninstall(install, false)
+
+	got := make(map[string]string, len(targets))
+	for _, target := range targets {
+		got[target.Path] = target.Label
+	}
+
+	want := map[string]string{
+		fishScript:                              "completion",
+		filepath.Join(home, "musher", "config"): "config",
+		filepath.Join(home, "musher", "data"):   "data",
+		filepath.Join(home, "musher", "state"):  "state",
+		filepath.Join(home, "musher", "cache"):  "cache",
+		binary:                                  "binary",
+	}
+
+	for path, label := range want {
+		if got[path] != label {
+			t.Errorf("target %s = %q, want %q (targets: %+v)", path, got[path], label, targets)
+		}
+	}
+
+	if _, ok := got[filepath.Join(zfunc, "_mush")]; ok {
+		t.Errorf("planUninstall() included a completion file that is not ours")
+	}
+
+	if last := targets[len(targets)-1]; last.Label != "binary" {
+		t.Errorf("last target = %+v, want the binary", last)
+	}
+}
+
+func TestPlanUninstall_KeepConfigAndHomebrew(t *testing.T) {
+	home := setupUninstallHome(t)
+
+	install := update.InstallContext{
+		ExecPath:      "/opt/homebrew/Cellar/mush/1.0.0/bin/mush",
+		Source:        update.InstallSourceHomebrew,
+		ExecPathKnown: true,
+	}
+
+	for _, target := range planUninstall(install, true) {
+		switch target.Path {
+		case filepath.Join(home, "musher", "config"), filepath.Join(home, "musher", "data"):
+			t.Errorf("--keep-config still removes %s", target.Path)
+		case install.ExecPath:
+			t.Errorf("Homebrew binary should be left to brew, got target %+v", target)
+		}
+	}
+}
+
+func TestUninstallCmd_RequiresForceWithoutInput(t *testing.T) {
+	home := setupUninstallHome(t)
+
+	original := uninstallInstallContext
+	uninstallInstallContext = func() update.InstallContext { return update.InstallContext{} }
+
+	t.Cleanup(func() { uninstallInstallContext = original })
+
+	out, buf := testWriter()
+	out.NoInput = true
+
+	cmd := newUninstallCmd()
+	cmd.SetArgs([]string{})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err == nil {
+		t.Fatal("uninstall without --force in non-interactive mode should fail")
+	}
+
+	if !strings.Contains(buf.String(), filepath.Join(home, "musher", "state")) {
+		t.Errorf("expected the plan to list the state directory, got:\n%s", buf.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(home, "musher", "state")); err != nil {
+		t.Errorf("state directory removed without confirmation: %v", err)
+	}
+}
+
+func TestUninstallCmd_ForceRemovesDirectories(t *testing.T) {
+	home := setupUninstallHome(t)
+
+	original := uninstallInstallContext
+	uninstallInstallContext = func() update.InstallContext { return update.InstallContext{} }
+
+	t.Cleanup(func() { uninstallInstallContext = original })
+
+	out, _ := testWriter()
+	cmd := newUninstallCmd()
+	cmd.SetArgs([]string{"--force", "--keep-config"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("uninstall --force error = %v", err)
+	}
+
+	for _, dir := range []string{"state", "cache"} {
+		if _, err := os.Stat(filepath.Join(home, "musher", dir)); !os.IsNotExist(err) {
+			t.Errorf("%s directory still present (err = %v)", dir, err)
+		}
+	}
+
+	for _, dir := range []string{"config", "data"} {
+		if _, err := os.Stat(filepath.Join(home, "musher", dir)); err != nil {
+			t.Errorf("--keep-config removed %s directory: %v", dir, err)
+		}
+	}
+}
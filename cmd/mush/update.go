@@ -26,6 +26,8 @@ func newUpdateCmd() *cobra.Command {
 
 Downloads the new binary, verifies its checksum, and replaces the current
 executable. If the binary is not writable, sudo is requested automatically.
+The release notes for the installed version are printed afterwards, and once
+on the first run of a version installed in the background.
 
 Set MUSHER_UPDATE_DISABLED=1 to disable update checks.`,
 		Example: `  mush update
@@ -93,7 +95,7 @@ func runUpdate(cmd *cobra.Command, out *output.Writer, targetVersion string, for
 			spin.Stop()
 		}
 
-		cliErr := clierrors.Wrap(clierrors.ExitNetwork, "Failed to check for updates", err)
+		cliErr := clierrors.NetworkFailed("Failed to check for updates", err)
 		if strings.Contains(err.Error(), "403") {
 			cliErr = cliErr.WithHint("Set GITHUB_TOKEN to avoid rate limits")
 		}
@@ -142,23 +144,22 @@ func runUpdate(cmd *cobra.Command, out *output.Writer, targetVersion string, for
 	}
 
 	// Apply update
-	spin = out.Spinner(fmt.Sprintf("Downloading v%s", info.LatestVersion))
-	spin.Start()
+	download := out.Progress(fmt.Sprintf("Downloading v%s", info.LatestVersion), int64(info.Release.AssetByteSize))
+	updater.SetDownloadProgress(download)
 
 	if err := updater.Apply(ctx, info.Release); err != nil {
-		spin.Stop()
+		download.Fail("")
 
 		return clierrors.Wrap(clierrors.ExitGeneral, "Update failed", err).
 			WithHint("Try again or download manually from GitHub Releases")
 	}
 
-	spin.StopWithSuccess(fmt.Sprintf("Updated to v%s", info.LatestVersion))
+	download.Done(fmt.Sprintf("Updated to v%s", info.LatestVersion))
 
-	if info.ReleaseURL != "" {
-		out.Muted("Release notes: %s", info.ReleaseURL)
-	}
+	printReleaseNotes(out, update.NotesFromRelease(info.Release))
 
 	saveCheckState(currentVersion, info.LatestVersion, info.ReleaseURL)
+	_ = update.MarkNotesShown(info.LatestVersion)
 
 	return nil
 }
@@ -196,8 +197,11 @@ func updateToVersion(ctx context.Context, out *output.Writer, updater *update.Up
 
 	if spin != nil {
 		spin.StopWithSuccess(fmt.Sprintf("Installed v%s", release.Version()))
+		printReleaseNotes(out, update.NotesFromRelease(release))
 	}
 
+	_ = update.MarkNotesShown(release.Version())
+
 	return nil
 }
 
@@ -0,0 +1,899 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/musher-dev/mush/internal/bundle"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/resolve"
+	"github.com/musher-dev/mush/internal/tui/nav"
+	"github.com/musher-dev/mush/internal/tui/render"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func newWorkerCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "worker",
+		Short: "Manage the local worker runtime",
+		Long: `Manage the local worker runtime that connects your machine to a habitat
+and processes jobs from the Musher platform.
+
+Use subcommands to start the worker, check on it, watch its terminal, follow
+its log, or stop it.`,
+		Example: `  mush worker start
+  mush worker start --habitat prod --queue jobs
+  mush worker status
+  mush worker observe
+  mush worker logs --follow
+  mush worker stop`,
+		Args: noArgs,
+	}
+
+	cmd.AddCommand(newWorkerStartCmd())
+	cmd.AddCommand(newWorkerStatusCmd())
+	cmd.AddCommand(newWorkerObserveCmd())
+	cmd.AddCommand(newWorkerStopCmd())
+	cmd.AddCommand(newWorkerReloadCmd())
+	cmd.AddCommand(newWorkerLogsCmd())
+
+	return cmd
+}
+
+func newWorkerStartCmd() *cobra.Command {
+	var (
+		dryRun       bool
+		queue        string
+		habitat      string
+		harnessType  string
+		bundleRef    string
+		forceSidebar bool
+		termProfile  string
+		headless     bool
+		detach       bool
+		once         bool
+		maxJobs      int
+		maxDuration  time.Duration
+		idleTimeout  time.Duration
+		rehearse     string
+		metricsAddr  string
+		strict       bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start the worker and begin processing jobs",
+		Long: `Start the worker, connecting your machine to a habitat and processing jobs.
+
+By default Mush runs an interactive terminal UI (watch mode) that lets you
+watch job execution live. With --headless it runs without a terminal, for
+systemd services, containers, and CI runners: there is no UI or prompting,
+progress is reported through structured logs on stderr, and SIGTERM stops
+claiming and lets the job in progress finish before deregistering.
+
+With --detach the worker runs headless in the background, in its own
+session, so it keeps running after the terminal closes. Its process ID is
+recorded under the state directory; follow it with 'mush worker logs
+--follow' and stop it with 'mush worker stop'.
+
+The worker will:
+  1. Connect to the Musher platform
+  2. Register with the selected habitat
+  3. Poll for available jobs
+  4. Execute handlers locally using the appropriate harness (Claude Code, Codex, Cursor Agent, Copilot, Gemini, OpenCode)
+  5. Report results back to the platform
+
+Harness Types:
+  --harness claude  Only handle Claude Code jobs
+  --harness codex   Only handle Codex jobs
+  --harness cursor  Only handle Cursor Agent jobs
+  --harness copilot Only handle GitHub Copilot jobs
+  --harness gemini  Only handle Gemini jobs
+  --harness opencode Only handle OpenCode jobs
+  (default)         Handle the harnesses in worker.harnesses, or all installed
+
+With --once or --max-jobs, the worker exits after processing that many jobs
+and prints a summary, for cron jobs and CI steps that should not keep a
+watch session open. The exit status is non-zero if any job failed: 75
+(EX_TEMPFAIL) when every failed job was requeued, so running again later may
+succeed, and 6 otherwise.
+
+--max-duration and --exit-when-idle wind the worker down after it has run
+that long or gone that long without a job, such as on spot instances. A job
+in progress is always finished and the worker deregistered before exiting.
+
+With --rehearse, Claude jobs are answered by replaying a recorded Claude
+session transcript instead of running claude: each job gets the next
+recorded prompt's output, usage, and completion signal. Use it to test a
+queue's claim, execute, and report path in CI without a Claude install or
+API costs.
+
+With --metrics-addr, or worker.metrics_addr in the config, the worker
+serves Prometheus metrics at /metrics on that address: jobs claimed,
+completed, and failed, heartbeat failures, claim latency, and harness
+process restarts.
+
+With --strict, startup fails instead of warning and starting degraded:
+when a harness in the worker's set is not installed (exit 4), when the
+habitat's runner config cannot be fetched, even with a cached copy (exit 3),
+when the platform reports MCP providers it could not resolve (exit 4), or
+when the job history transcript cannot be opened (exit 4). Use it where a
+silently degraded worker is worse than none.
+
+The watch UI adapts to your terminal through a terminal profile, detected
+from TERM_PROGRAM and TERM unless --terminal-profile or terminal.profile in
+the config names one: vscode leaves out the sidebar and mouse capture, tmux
+leaves the mouse to the multiplexer, and ssh-dumb turns off the sidebar,
+color, and mouse.
+
+Press Ctrl+C once to interrupt Claude; press Ctrl+C again quickly to exit.
+Press Ctrl+Q to exit the watch UI immediately.
+Press F2 to show recent errors and warnings, or F3 to show the loaded
+bundle and MCP servers; Escape closes either list. Press F4 to switch
+logging to debug and back without restarting the worker.`,
+		Example: `  mush worker start
+  mush worker start --habitat prod --queue jobs
+  mush worker start --harness claude
+  mush worker start --bundle acme/my-kit:0.1.0
+  mush worker start --once
+  mush worker start --max-jobs 5
+  mush worker start --max-duration 4h --exit-when-idle 30m
+  mush worker start --headless --habitat prod --queue jobs
+  mush worker start --detach --habitat prod --queue jobs
+  mush worker start --headless --metrics-addr 127.0.0.1:9464
+  mush worker start --headless --once --rehearse testdata/session.jsonl
+  mush worker start --headless --strict --habitat prod --queue jobs
+  mush worker start --dry-run`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			if headless {
+				// Nobody is there to answer a prompt.
+				out.NoInput = true
+			}
+
+			limits, err := newWorkerLimits(once, maxJobs, maxDuration, idleTimeout)
+			if err != nil {
+				return err
+			}
+
+			if detach {
+				return detachWorker(cmd.Context(), out)
+			}
+
+			// A detached worker clears its PID file however it exits.
+			defer func() { _ = worker.RemovePIDFile(os.Getpid()) }()
+
+			// Open the API connection while harnesses are checked, so
+			// registration doesn't pay for DNS and the TLS handshake.
+			loadedCfg := config.Load()
+			client.StartPrewarm(cmd.Context(), loadedCfg.APIURL(), loadedCfg.CACertFile())
+
+			logger := observability.FromContext(cmd.Context()).With(
+				slog.String("component", "worker"),
+				slog.String("event.type", "worker.start"),
+			)
+
+			// Validate harness type if specified.
+			var supportedHarnesses []string
+
+			if rehearse != "" {
+				if err := checkRehearsal(rehearse, harnessType, out); err != nil {
+					return err
+				}
+
+				harnessType = "claude"
+			}
+
+			if harnessType != "" {
+				normalized, err := normalizeHarnessType(harnessType)
+				if err != nil {
+					return err
+				}
+
+				supportedHarnesses = []string{normalized}
+			} else {
+				supportedHarnesses = defaultSupportedHarnesses(loadedCfg.WorkerHarnesses())
+			}
+
+			// Check if required harnesses are available.
+			var unavailable []string
+
+			for _, h := range supportedHarnesses {
+				info, ok := harness.Lookup(h)
+				if !ok {
+					continue
+				}
+
+				if !info.Available() && rehearse == "" {
+					switch {
+					case dryRun && !strict:
+						out.Warning("%s CLI not found (dry-run mode, continuing)", h)
+						out.Println()
+					case len(supportedHarnesses) == 1:
+						// Single harness mode: fail immediately.
+						if h == "claude" {
+							return clierrors.ClaudeNotFound()
+						}
+
+						return clierrors.HarnessNotAvailable(h)
+					default:
+						unavailable = append(unavailable, h)
+					}
+				}
+			}
+
+			if strict && len(unavailable) > 0 {
+				return strictHarnessesError(unavailable)
+			}
+
+			if !dryRun && len(unavailable) > 0 && len(unavailable) < len(supportedHarnesses) {
+				for _, h := range unavailable {
+					out.Warning("%s CLI not found, disabling %s harness", h, h)
+				}
+
+				filtered := supportedHarnesses[:0]
+				for _, h := range supportedHarnesses {
+					if !slices.Contains(unavailable, h) {
+						filtered = append(filtered, h)
+					}
+				}
+
+				supportedHarnesses = filtered
+
+				out.Println()
+			}
+
+			// Get credentials and create client
+			source, c, err := apiClientFactory()
+			if err != nil {
+				return err
+			}
+
+			out.Print("Using credentials from: %s\n", source)
+
+			if caps, ok := c.Capabilities(); ok {
+				caps.Harnesses = supportedHarnesses
+				c.SetCapabilities(caps)
+			}
+
+			// Validate the key and list habitats concurrently; neither
+			// depends on the other.
+			conn, err := connectWorker(cmd.Context(), c, out)
+			if err != nil {
+				return err
+			}
+
+			resolver := resolve.NewResolver(c, out)
+
+			// Resolve habitat ID
+			habitatID, err := selectHabitat(resolver, conn.habitats, pickFlagOrEnv(habitat, "MUSH_HABITAT", ""), out)
+			if err != nil {
+				return err
+			}
+
+			// The habitat's runner config, including habitat-scoped MCP
+			// providers, loads while the queue is resolved and the bundle
+			// downloads.
+			var (
+				runnerConfigLoad  errgroup.Group
+				runnerConfig      *client.RunnerConfigResponse
+				runnerConfigStale bool
+				runnerConfigWarn  string
+				runnerConfigErr   error
+			)
+
+			runnerConfigLoad.Go(func() error {
+				runnerConfig, runnerConfigStale, runnerConfigWarn, runnerConfigErr = loadRunnerConfig(cmd.Context(), c, habitatID, logger)
+				return nil
+			})
+
+			queue, err := resolveQueue(cmd.Context(), resolver, habitatID, pickFlagOrEnv(queue, "MUSH_QUEUE", ""), out)
+			if err != nil {
+				return err
+			}
+
+			queueID := queue.ID
+			bundleSummary := harness.BundleSummary{}
+
+			// The availability check is independent of the bundle pull, so it
+			// runs in the background while the bundle downloads.
+			var (
+				availabilityCheck errgroup.Group
+				availability      *client.InstructionAvailability
+			)
+
+			availabilityCheck.Go(func() error {
+				var checkErr error
+
+				availability, checkErr = checkQueueAvailability(cmd.Context(), c, &queue)
+
+				return checkErr
+			})
+
+			// Install bundle assets if --bundle flag is set.
+			if bundleRef != "" {
+				var bundleErr error
+
+				bundleSummary, bundleErr = resolveBundle(cmd.Context(), c, bundleRef, supportedHarnesses, out)
+				if bundleErr != nil {
+					_ = availabilityCheck.Wait()
+					return bundleErr
+				}
+			}
+
+			if err := availabilityCheck.Wait(); err != nil {
+				return err
+			}
+
+			_ = runnerConfigLoad.Wait()
+
+			if strict && runnerConfigErr != nil {
+				return strictRunnerConfigError(runnerConfigErr)
+			}
+
+			if runnerConfigWarn != "" {
+				out.Warning("%s", runnerConfigWarn)
+			}
+
+			if err := checkRunnerConfigErrors(out, runnerConfig, strict); err != nil {
+				return err
+			}
+
+			if err := checkInstructionMCPProviders(out, availability, runnerConfig, runnerConfigStale, time.Now()); err != nil {
+				return err
+			}
+
+			surface := "watch"
+			if headless {
+				surface = "headless"
+			}
+
+			out.Print("Surface: %s\n", surface)
+			out.Print("Harnesses: %s\n", strings.Join(supportedHarnesses, ", "))
+			out.Print("Queue ID: %s\n", queueID)
+
+			if slices.Contains(supportedHarnesses, "claude") {
+				mcpServers := harness.LoadedMCPServers(runnerConfig, time.Now())
+				logger.Info(
+					"MCP servers evaluated",
+					slog.String("event.type", "mcp.specs.built"),
+					slog.Int("mcp.server_count", len(mcpServers)),
+					slog.Any("mcp.server_names", mcpServers),
+				)
+
+				if len(mcpServers) == 0 {
+					out.Print("MCP servers: none\n")
+				} else {
+					out.Print("MCP servers: %s\n", strings.Join(mcpServers, ", "))
+				}
+			}
+
+			if dryRun {
+				out.Println()
+				out.Success("Dry run mode: connection verified, not claiming jobs")
+
+				return nil
+			}
+
+			if headless {
+				// SIGHUP reloads the config rather than stopping a headless worker.
+				ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+				defer stop()
+
+				summary, err := runHeadless(ctx, c, habitatID, queueID, queue.Slug, supportedHarnesses, runnerConfig, runnerConfigStale, &bundleSummary, limits, rehearse, metricsAddr, strict)
+				if err != nil {
+					logger.Error("headless worker failed", slog.String("event.type", "worker.error"), slog.String("error", err.Error()))
+					return err
+				}
+
+				if limits.bounded() {
+					return reportWorkerSummary(out, &summary)
+				}
+
+				return nil
+			}
+
+			// Watch mode requires a terminal for the harness UI
+			if !out.Terminal().IsTTY {
+				return &clierrors.CLIError{
+					Message: "Watch mode requires a terminal (TTY)",
+					Hint:    "Run this command directly in a terminal, or pass --headless to run without one",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			if err := applyTerminalProfile(out, termProfile); err != nil {
+				return err
+			}
+
+			// Setup graceful shutdown.
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+			defer stop()
+
+			out.Println()
+
+			summary, err := runWatch(ctx, c, habitatID, queueID, queue.Slug, supportedHarnesses, runnerConfig, runnerConfigStale, &bundleSummary, forceSidebar, limits, rehearse, metricsAddr, strict)
+			if err != nil {
+				logger.Error("worker watch runtime failed", slog.String("event.type", "worker.error"), slog.String("error", err.Error()))
+				return err
+			}
+
+			logger.Info("worker watch exited", slog.String("event.type", "worker.ready"))
+
+			if cmd.Context().Err() == nil && ctx.Err() != nil {
+				out.Println()
+				out.Info("Received shutdown signal...")
+			}
+
+			if limits.bounded() {
+				return reportWorkerSummary(out, &summary)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Verify connection without claiming jobs")
+	cmd.Flags().StringVar(&queue, "queue", "", "Filter jobs by queue slug or ID (env: MUSH_QUEUE)")
+	cmd.Flags().StringVar(&habitat, "habitat", "", "Habitat slug or ID to connect to (env: MUSH_HABITAT)")
+	cmd.Flags().StringVar(&harnessType, "harness", "", "Specific harness type: claude, codex, copilot, cursor, gemini, opencode (default: all)")
+	cmd.Flags().StringVar(&bundleRef, "bundle", "", "Bundle namespace/slug[:version] to install before starting")
+	cmd.Flags().BoolVar(&forceSidebar, "force-sidebar", false, "Show the sidebar even when the terminal profile hides it")
+	addTerminalProfileFlag(cmd, &termProfile)
+	cmd.Flags().BoolVar(&headless, "headless", false, "Run without the terminal UI, logging to stderr (for services and containers)")
+	cmd.Flags().BoolVar(&detach, "detach", false, "Run as a headless worker in the background, detached from the terminal")
+	cmd.Flags().BoolVar(&once, "once", false, "Exit after processing one job")
+	cmd.Flags().IntVar(&maxJobs, "max-jobs", 0, "Exit after processing this many jobs (default: no limit)")
+	cmd.Flags().DurationVar(&maxDuration, "max-duration", 0, "Exit after running this long, e.g. 4h (default: no limit)")
+	cmd.Flags().DurationVar(&idleTimeout, "exit-when-idle", 0, "Exit after this long without a job, e.g. 30m (default: no limit)")
+	cmd.Flags().StringVar(&rehearse, "rehearse", "", "Answer Claude jobs by replaying this Claude session transcript")
+	cmd.Flags().BoolVar(&strict, "strict", false, "Fail startup instead of starting with a degraded configuration")
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "Serve Prometheus metrics at /metrics on this address, e.g. 127.0.0.1:9464 (default: worker.metrics_addr)")
+	cmd.MarkFlagsMutuallyExclusive("once", "max-jobs")
+	cmd.MarkFlagsMutuallyExclusive("headless", "force-sidebar")
+	cmd.MarkFlagsMutuallyExclusive("detach", "force-sidebar")
+	cmd.MarkFlagsMutuallyExclusive("headless", "terminal-profile")
+	cmd.MarkFlagsMutuallyExclusive("detach", "terminal-profile")
+	cmd.MarkFlagsMutuallyExclusive("detach", "dry-run")
+
+	return cmd
+}
+
+func runWatch(
+	ctx context.Context,
+	c *client.Client,
+	habitatID, queueID, queueSlug string,
+	supportedHarnesses []string,
+	runnerConfig *client.RunnerConfigResponse,
+	runnerConfigStale bool,
+	bundleSummary *harness.BundleSummary,
+	forceSidebar bool,
+	limits workerLimits,
+	rehearsal string,
+	metricsAddr string,
+	strict bool,
+) (harness.WorkerSummary, error) {
+	var summary harness.WorkerSummary
+
+	cfg := workerHarnessConfig(c, habitatID, queueID, queueSlug, supportedHarnesses, runnerConfig, runnerConfigStale, bundleSummary, limits, &summary)
+	cfg.ForceSidebar = forceSidebar
+	cfg.Rehearsal = rehearsal
+	cfg.RequireTranscript = strict
+
+	if metricsAddr != "" {
+		cfg.MetricsAddr = metricsAddr
+	}
+
+	if err := harness.Run(ctx, cfg); err != nil {
+		return summary, workerRunError("Watch harness failed", err)
+	}
+
+	return summary, nil
+}
+
+// workerHarnessConfig builds the harness config shared by the watch and
+// headless surfaces. The session's results are stored in summary on exit.
+func workerHarnessConfig(
+	c *client.Client,
+	habitatID, queueID, queueSlug string,
+	supportedHarnesses []string,
+	runnerConfig *client.RunnerConfigResponse,
+	runnerConfigStale bool,
+	bundleSummary *harness.BundleSummary,
+	limits workerLimits,
+	summary *harness.WorkerSummary,
+) *harness.Config {
+	localCfg := config.Load()
+
+	return &harness.Config{
+		Client:             c,
+		HabitatID:          habitatID,
+		QueueID:            queueID,
+		QueueSlug:          queueSlug,
+		SupportedHarnesses: supportedHarnesses,
+		RunnerConfig:       runnerConfig,
+		RunnerConfigStale:  runnerConfigStale,
+		TranscriptEnabled:  localCfg.HistoryEnabled(),
+		TranscriptDir:      localCfg.HistoryDir(),
+		TranscriptLines:    localCfg.HistoryScrollbackLines(),
+		BundleName:         bundleSummary.Name,
+		BundleVer:          bundleSummary.Version,
+		BundleSummary:      *bundleSummary,
+		MaxJobs:            limits.maxJobs,
+		MaxDuration:        limits.maxDuration,
+		IdleTimeout:        limits.idleTimeout,
+		MetricsAddr:        localCfg.WorkerMetricsAddr(),
+		OnWorkerExit:       func(s harness.WorkerSummary) { *summary = s },
+	}
+}
+
+// runHeadless runs the worker job loop without the terminal UI.
+func runHeadless(
+	ctx context.Context,
+	c *client.Client,
+	habitatID, queueID, queueSlug string,
+	supportedHarnesses []string,
+	runnerConfig *client.RunnerConfigResponse,
+	runnerConfigStale bool,
+	bundleSummary *harness.BundleSummary,
+	limits workerLimits,
+	rehearsal string,
+	metricsAddr string,
+	strict bool,
+) (harness.WorkerSummary, error) {
+	var summary harness.WorkerSummary
+
+	cfg := workerHarnessConfig(c, habitatID, queueID, queueSlug, supportedHarnesses, runnerConfig, runnerConfigStale, bundleSummary, limits, &summary)
+	cfg.Rehearsal = rehearsal
+	cfg.RequireTranscript = strict
+
+	if metricsAddr != "" {
+		cfg.MetricsAddr = metricsAddr
+	}
+
+	if err := harness.RunHeadless(ctx, cfg, harness.DefaultHeadlessDrainTimeout); err != nil {
+		return summary, workerRunError("Headless worker failed", err)
+	}
+
+	return summary, nil
+}
+
+// workerRunError reports a worker session that failed. A worker the platform
+// could not register is a network failure, temporary when the request may
+// succeed later; a --strict worker without a transcript is a config failure;
+// anything else is an execution failure.
+func workerRunError(message string, err error) error {
+	if errors.Is(err, engine.ErrRegistrationFailed) {
+		return clierrors.NetworkFailed(message, err).
+			WithHint("Check your network connection and API credentials")
+	}
+
+	if errors.Is(err, harness.ErrTranscriptUnavailable) {
+		return clierrors.Wrap(clierrors.ExitConfig, message, err).
+			WithHint("Check that history.dir is writable, or drop --strict to run without job history")
+	}
+
+	return clierrors.Wrap(clierrors.ExitExecution, message, err)
+}
+
+// handleWorkerNavResult handles the ActionWorkerStart result from the TUI.
+func handleWorkerNavResult(cmd *cobra.Command, out *output.Writer, result *nav.Result) error {
+	logger := observability.FromContext(cmd.Context()).With(
+		slog.String("component", "worker"),
+		slog.String("event.type", "worker.start.nav"),
+	)
+
+	_, c, err := apiClientFactory()
+	if err != nil {
+		return err
+	}
+
+	identity, err := c.CachedIdentity(cmd.Context())
+	if err != nil {
+		return clierrors.AuthFailed(err)
+	}
+
+	out.Print("Authenticated as: %s (Organization: %s)\n", identity.CredentialName, identity.OrganizationName)
+
+	runnerConfig, runnerConfigStale := fetchRunnerConfig(cmd.Context(), c, result.HabitatID, out, logger)
+
+	// The navigator already confirmed the queue has an instruction; if it
+	// cannot be looked up again, the MCP check is skipped rather than
+	// blocking the worker.
+	if availability, availErr := c.GetQueueInstructionAvailability(cmd.Context(), result.QueueID); availErr == nil {
+		if err := checkInstructionMCPProviders(out, availability, runnerConfig, runnerConfigStale, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	if !out.Terminal().IsTTY {
+		return &clierrors.CLIError{
+			Message: "Watch mode requires a terminal (TTY)",
+			Hint:    "Run this command directly in a terminal, not in a pipe or script",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	out.Print("Surface: watch\n")
+	out.Print("Harnesses: %s\n", strings.Join(result.SupportedHarnesses, ", "))
+	out.Print("Queue: %s (%s)\n", result.QueueName, result.QueueID)
+	out.Println()
+
+	_, watchErr := runWatch(ctx, c, result.HabitatID, result.QueueID, "", result.SupportedHarnesses, runnerConfig, runnerConfigStale, &harness.BundleSummary{}, false, workerLimits{}, "", "", false)
+	if watchErr != nil {
+		logger.Error("worker watch runtime failed",
+			slog.String("event.type", "worker.error"),
+			slog.String("error", watchErr.Error()))
+
+		return watchErr
+	}
+
+	return nil
+}
+
+// fetchRunnerConfig fetches the runner config for habitatID and records it as
+// last-known-good. When the platform is unreachable it falls back to the
+// habitat's cached copy, reporting stale=true so the harness refreshes as soon
+// as the platform recovers.
+func fetchRunnerConfig(
+	ctx context.Context,
+	c *client.Client,
+	habitatID string,
+	out *output.Writer,
+	logger *slog.Logger,
+) (cfg *client.RunnerConfigResponse, stale bool) {
+	cfg, stale, warning, _ := loadRunnerConfig(ctx, c, habitatID, logger)
+	if warning != "" {
+		out.Warning("%s", warning)
+	}
+
+	return cfg, stale
+}
+
+// loadRunnerConfig is fetchRunnerConfig without output: it returns the
+// warning to show instead of printing it, so it can run concurrently with
+// other startup requests. fetchErr is why the platform's config could not be
+// fetched, whether or not a cached copy stands in for it.
+func loadRunnerConfig(
+	ctx context.Context,
+	c *client.Client,
+	habitatID string,
+	logger *slog.Logger,
+) (cfg *client.RunnerConfigResponse, stale bool, warning string, fetchErr error) {
+	cfg, err := c.GetRunnerConfig(ctx, habitatID)
+	if err == nil {
+		if saveErr := worker.SaveRunnerConfigCache(c, habitatID, cfg, time.Now()); saveErr != nil {
+			logger.Warn("runner config cache write failed",
+				slog.String("event.type", "worker.runner_config.cache_error"),
+				slog.String("error", saveErr.Error()))
+		}
+
+		return cfg, false, "", nil
+	}
+
+	logger.Warn("runner config unavailable",
+		slog.String("event.type", "worker.runner_config.unavailable"),
+		slog.String("error", err.Error()))
+
+	cached, cacheErr := worker.LoadRunnerConfigCache(c, habitatID)
+	if cacheErr != nil {
+		if !errors.Is(cacheErr, worker.ErrNoRunnerConfigCache) {
+			logger.Warn("runner config cache unreadable",
+				slog.String("event.type", "worker.runner_config.cache_error"),
+				slog.String("error", cacheErr.Error()))
+		}
+
+		return nil, false, fmt.Sprintf("Runner config unavailable, continuing without MCP provisioning: %v", err), err
+	}
+
+	logger.Info("using cached runner config",
+		slog.String("event.type", "worker.runner_config.cached"),
+		slog.Time("runner_config.saved_at", cached.SavedAt),
+		slog.Bool("runner_config.credentials_restored", cached.CredentialsRestored))
+
+	age := render.FormatDuration(render.Age(cached.SavedAt, time.Now()))
+	if cached.CredentialsRestored {
+		warning = fmt.Sprintf("Runner config unavailable, using cached config from %s ago: %v", age, err)
+	} else {
+		warning = fmt.Sprintf("Runner config unavailable, using cached config from %s ago without credentials: %v", age, err)
+	}
+
+	return cached.Config, true, warning, err
+}
+
+func normalizeHarnessType(harnessType string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(harnessType))
+
+	if _, ok := harness.Lookup(normalized); ok {
+		return normalized, nil
+	}
+
+	return "", clierrors.InvalidHarnessType(normalized, harness.RegisteredNames())
+}
+
+// checkRehearsal validates --rehearse, which only answers Claude jobs and
+// needs a Claude session transcript with at least one prompt.
+func checkRehearsal(path, harnessType string, out *output.Writer) error {
+	if harnessType != "" && !strings.EqualFold(strings.TrimSpace(harnessType), "claude") {
+		return &clierrors.CLIError{
+			Message: "--rehearse replays a Claude session and cannot run " + harnessType + " jobs",
+			Hint:    "Drop --harness, or use --harness claude",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	prompts, err := harness.RehearsalPrompts(path)
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitUsage, "Cannot replay the rehearsal transcript", err).
+			WithHint("Pass a Claude session transcript, such as a .jsonl file under ~/.claude/projects/")
+	}
+
+	out.Print("Rehearsal: replaying %d recorded prompt(s) from %s\n", prompts, path)
+
+	return nil
+}
+
+// defaultSupportedHarnesses returns the harnesses to handle without
+// --harness: the configured worker.harnesses that are registered, or every
+// installed harness when none are configured.
+func defaultSupportedHarnesses(configured []string) []string {
+	if len(configured) == 0 {
+		return harness.AvailableNames()
+	}
+
+	names := make([]string, 0, len(configured))
+
+	for _, name := range configured {
+		if _, ok := harness.Lookup(name); ok && !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return harness.AvailableNames()
+	}
+
+	return names
+}
+
+// resolveBundle pulls and installs a bundle when the --bundle flag is set.
+func resolveBundle(
+	ctx context.Context,
+	c *client.Client,
+	bundleFlag string,
+	supportedHarnesses []string,
+	out *output.Writer,
+) (harness.BundleSummary, error) {
+	emptySummary := harness.BundleSummary{}
+	logger := observability.FromContext(ctx).With(
+		slog.String("component", "worker"),
+		slog.String("event.type", "worker.bundle"),
+	)
+
+	ref, err := bundle.ParseRef(bundleFlag)
+	if err != nil {
+		return emptySummary, &clierrors.CLIError{
+			Message: err.Error(),
+			Hint:    "Use format: namespace/slug or namespace/slug:version",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	logger = logger.With(slog.String("bundle.slug", ref.Slug))
+
+	// Determine harness type for asset mapping — use first supported harness.
+	if len(supportedHarnesses) == 0 {
+		return emptySummary, &clierrors.CLIError{
+			Message: "No supported harnesses available for bundle install",
+			Hint:    "Specify a harness with --harness or ensure at least one harness is available",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	harnessType := supportedHarnesses[0]
+
+	mapper := mapperForHarness(harnessType)
+	if mapper == nil {
+		return emptySummary, &clierrors.CLIError{
+			Message: fmt.Sprintf("No asset mapper for harness type: %s", harnessType),
+			Hint:    "This harness type does not support bundle assets",
+			Code:    clierrors.ExitUsage,
+		}
+	}
+
+	// Pull the bundle.
+	spin := out.Spinner(fmt.Sprintf("Pulling bundle %s", ref.Slug))
+	spin.Start()
+
+	resolved, cachePath, err := bundle.Pull(ctx, c, ref.Namespace, ref.Slug, ref.Version, out)
+	if err != nil {
+		spin.StopWithFailure(fmt.Sprintf("Failed to pull bundle %s", ref.Slug))
+		logger.Error("bundle pull failed", slog.String("event.type", "worker.bundle.error"), slog.String("error", err.Error()))
+
+		return emptySummary, clierrors.NetworkFailed("Failed to pull bundle", err).
+			WithHint("Check your network connection and bundle slug")
+	}
+
+	spin.StopWithSuccess(fmt.Sprintf("Pulled bundle %s v%s", ref.Slug, resolved.Version))
+
+	if err := checkBundleManifest(out, logger, resolved); err != nil {
+		return emptySummary, err
+	}
+
+	// Install assets into the working directory.
+	workDir, err := os.Getwd()
+	if err != nil {
+		return emptySummary, clierrors.Wrap(clierrors.ExitGeneral, "Failed to get working directory", err)
+	}
+
+	installResult, installErr := bundle.InstallFromCache(workDir, cachePath, &resolved.Manifest, mapper, true)
+	if installErr != nil {
+		var conflict *bundle.InstallConflictError
+		if errors.As(installErr, &conflict) {
+			logger.Warn("bundle install conflict", slog.String("event.type", "worker.bundle.conflict"), slog.String("error", installErr.Error()))
+			return emptySummary, clierrors.InstallConflict(conflict.Path)
+		}
+
+		logger.Error("bundle install failed", slog.String("event.type", "worker.bundle.error"), slog.String("error", installErr.Error()))
+
+		return emptySummary, clierrors.Wrap(clierrors.ExitGeneral, "Failed to install bundle assets", installErr)
+	}
+
+	for _, relPath := range installResult.Paths {
+		out.Success("Installed: %s", relPath)
+	}
+
+	// Track the installation.
+	trackErr := bundle.TrackInstall(workDir, &bundle.InstalledBundle{
+		Namespace:     ref.Namespace,
+		Slug:          ref.Slug,
+		Ref:           ref.Namespace + "/" + ref.Slug,
+		Version:       resolved.Version,
+		Harness:       harnessType,
+		Assets:        installResult.Paths,
+		MergedConfigs: installResult.MergedConfigs,
+		Timestamp:     time.Now(),
+	})
+	if trackErr != nil {
+		out.Warning("Failed to track installation: %v", trackErr)
+	}
+
+	out.Success("Bundle %s v%s installed (%d assets)", ref.Slug, resolved.Version, len(installResult.Paths))
+
+	logger.Info(
+		"bundle installed for worker",
+		slog.String("event.type", "worker.bundle.installed"),
+		slog.String("bundle.version", resolved.Version),
+		slog.Int("bundle.asset_count", len(installResult.Paths)),
+	)
+
+	summary := harness.SummarizeBundleManifest(&resolved.Manifest)
+	summary.Name = ref.Slug
+	summary.Version = resolved.Version
+
+	return summary, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+// Seams for tests.
+var (
+	queryWorkerStatus  = worker.QueryWorkerStatus
+	stopWorker         = worker.StopWorker
+	reloadWorker       = worker.ReloadWorker
+	stopDetachedWorker = worker.StopDetachedWorker
+)
+
+func newWorkerStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the running worker's status",
+		Long: `Show the link ID, current job, and completed and failed job counts of the
+worker running on this machine. The worker answers over a local control socket.`,
+		Example: `  mush worker status
+  mush worker status --json`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			status, err := queryWorkerStatus(cmd.Context())
+			if err != nil {
+				return workerControlError(err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(status); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			printWorkerStatus(out, status)
+
+			return nil
+		},
+	}
+}
+
+func newWorkerStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop",
+		Short: "Stop the running worker gracefully",
+		Long: `Ask the worker running on this machine to stop claiming jobs, finish the job
+in progress, and deregister from the platform. The command returns once the
+worker has accepted the request; the worker exits when the drain completes.
+
+A worker started with --detach that is not answering on the control socket
+yet, such as while it is still starting up, is sent SIGTERM instead.`,
+		Example: `  mush worker stop`,
+		Args:    noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			status, err := stopWorker(cmd.Context())
+			if errors.Is(err, worker.ErrNoWorkerRunning) {
+				return signalDetachedWorker(out)
+			}
+
+			if err != nil {
+				return workerControlError(err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(status); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			if status.JobID != "" {
+				out.Success("Stopping worker %s after job %s finishes", status.WorkerID, status.JobID)
+			} else {
+				out.Success("Stopping worker %s", status.WorkerID)
+			}
+
+			return nil
+		},
+	}
+}
+
+func newWorkerReloadCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "reload",
+		Short: "Reload the running worker's config",
+		Long: `Ask the worker running on this machine to re-read its config file and
+environment, as sending it SIGHUP does, without restarting it or its harness
+session. Each setting that changed is logged; settings that cannot change
+while the worker runs apply at its next start.`,
+		Example: `  mush worker reload`,
+		Args:    noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			status, err := reloadWorker(cmd.Context())
+			if err != nil {
+				return workerControlError(err)
+			}
+
+			if out.JSON {
+				if err := out.PrintJSON(status); err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+				}
+
+				return nil
+			}
+
+			out.Success("Reloading config of worker %s", status.WorkerID)
+
+			return nil
+		},
+	}
+}
+
+// signalDetachedWorker stops a detached worker that is not answering on the
+// control socket.
+func signalDetachedWorker(out *output.Writer) error {
+	pid, err := stopDetachedWorker()
+	if err != nil {
+		return workerControlError(err)
+	}
+
+	if out.JSON {
+		if err := out.PrintJSON(&worker.ControlStatus{PID: pid, Stopping: true}); err != nil {
+			return clierrors.Wrap(clierrors.ExitGeneral, "Failed to write JSON output", err)
+		}
+
+		return nil
+	}
+
+	out.Success("Sent SIGTERM to the detached worker (pid %d)", pid)
+
+	return nil
+}
+
+func printWorkerStatus(out *output.Writer, status *worker.ControlStatus) {
+	state := status.Status
+	if status.Stopping {
+		state += " (stopping)"
+	}
+
+	job := status.JobID
+	if job == "" {
+		job = "none"
+	}
+
+	out.Print("Worker:    %s (pid %d)\n", status.WorkerID, status.PID)
+	out.Print("Status:    %s\n", state)
+	out.Print("Job:       %s\n", job)
+	out.Print("Completed: %d\n", status.Completed)
+	out.Print("Failed:    %d\n", status.Failed)
+	out.Print("Habitat:   %s\n", status.HabitatID)
+
+	if status.QueueID != "" {
+		out.Print("Queue:     %s\n", status.QueueID)
+	}
+
+	out.Print("Uptime:    %s\n", time.Since(status.StartedAt).Round(time.Second))
+}
+
+func workerControlError(err error) error {
+	if errors.Is(err, worker.ErrNoWorkerRunning) {
+		return clierrors.WorkerNotRunning()
+	}
+
+	return clierrors.Wrap(clierrors.ExitGeneral, "Failed to reach the running worker", err)
+}
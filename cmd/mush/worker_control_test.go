@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func withWorkerControl(t *testing.T, status *worker.ControlStatus, err error) *int {
+	t.Helper()
+
+	prevStatus, prevStop, prevReload, prevDetached := queryWorkerStatus, stopWorker, reloadWorker, stopDetachedWorker
+	stops := 0
+
+	queryWorkerStatus = func(context.Context) (*worker.ControlStatus, error) {
+		return status, err
+	}
+	stopWorker = func(context.Context) (*worker.ControlStatus, error) {
+		stops++
+		return status, err
+	}
+	reloadWorker = func(context.Context) (*worker.ControlStatus, error) {
+		return status, err
+	}
+	stopDetachedWorker = func() (int, error) {
+		return 0, worker.ErrNoWorkerRunning
+	}
+
+	t.Cleanup(func() {
+		queryWorkerStatus, stopWorker, reloadWorker, stopDetachedWorker = prevStatus, prevStop, prevReload, prevDetached
+	})
+
+	return &stops
+}
+
+func TestWorkerStatusPrintsRunningWorker(t *testing.T) {
+	withWorkerControl(t, &worker.ControlStatus{
+		PID:       4242,
+		WorkerID:  "wrk-1",
+		HabitatID: "hab-1",
+		Status:    "Processing",
+		JobID:     "job-7",
+		Completed: 3,
+		Failed:    1,
+		StartedAt: time.Now().Add(-time.Minute),
+	}, nil)
+
+	out, buf := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"status"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("worker status error = %v", err)
+	}
+
+	for _, want := range []string{"wrk-1 (pid 4242)", "Processing", "job-7", "Completed: 3", "Failed:    1"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestWorkerStopRequestsDrain(t *testing.T) {
+	stops := withWorkerControl(t, &worker.ControlStatus{WorkerID: "wrk-1", JobID: "job-7", Stopping: true}, nil)
+
+	out, buf := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"stop"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("worker stop error = %v", err)
+	}
+
+	if *stops != 1 || !strings.Contains(buf.String(), "after job job-7 finishes") {
+		t.Fatalf("stops = %d, output = %q; want one stop request naming the job", *stops, buf.String())
+	}
+}
+
+func TestWorkerStopSignalsDetachedWorker(t *testing.T) {
+	withWorkerControl(t, nil, worker.ErrNoWorkerRunning)
+
+	signaled := 0
+	stopDetachedWorker = func() (int, error) {
+		signaled++
+		return 4242, nil
+	}
+
+	out, buf := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"stop"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("worker stop error = %v", err)
+	}
+
+	if signaled != 1 || !strings.Contains(buf.String(), "detached worker (pid 4242)") {
+		t.Fatalf("signaled = %d, output = %q; want the detached worker signaled", signaled, buf.String())
+	}
+}
+
+func TestWorkerControlWithoutWorker(t *testing.T) {
+	for _, sub := range []string{"status", "stop", "reload"} {
+		t.Run(sub, func(t *testing.T) {
+			withWorkerControl(t, nil, worker.ErrNoWorkerRunning)
+
+			out, _ := testWriter()
+			cmd := newWorkerCmd()
+			cmd.SetArgs([]string{sub})
+			cmd.SetOut(io.Discard)
+			cmd.SetErr(io.Discard)
+			cmd.SetContext(out.WithContext(t.Context()))
+
+			err := cmd.Execute()
+
+			var cliErr *clierrors.CLIError
+			if !errors.As(err, &cliErr) || cliErr.Message != clierrors.WorkerNotRunning().Message {
+				t.Fatalf("worker %s error = %v, want WorkerNotRunning", sub, err)
+			}
+		})
+	}
+}
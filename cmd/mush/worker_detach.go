@@ -0,0 +1,201 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+const (
+	// detachStartTimeout bounds how long `mush worker start --detach` waits
+	// for the background worker to answer on the control socket. Startup can
+	// include pulling a bundle.
+	detachStartTimeout = 2 * time.Minute
+
+	detachPollInterval = 250 * time.Millisecond
+
+	// detachOutputLines is how much of the worker's output is shown when it
+	// exits during startup.
+	detachOutputLines = 20
+)
+
+// errDetachTimeout is returned by waitForDetachedWorker when the worker is
+// still running but has not answered within detachStartTimeout.
+var errDetachTimeout = errors.New("detached worker did not answer in time")
+
+// detachWorker starts the same `mush worker start` command again as a
+// headless worker in a new session, so closing the terminal does not stop
+// it, and waits until it answers on the control socket.
+func detachWorker(ctx context.Context, out *output.Writer) error {
+	if pid, err := worker.DetachedWorkerPID(); err == nil {
+		return clierrors.DetachedWorkerRunning(pid)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to locate the mush executable", err)
+	}
+
+	consolePath, err := paths.WorkerConsoleFile()
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to locate the worker output file", err)
+	}
+
+	if err := safeio.MkdirAll(filepath.Dir(consolePath), 0o700); err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to create the worker run directory", err)
+	}
+
+	console, err := safeio.OpenFile(consolePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to open the worker output file", err)
+	}
+	defer console.Close()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to open "+os.DevNull, err)
+	}
+	defer devNull.Close()
+
+	// The worker outlives this command, so it must not be canceled with it.
+	child, err := executil.AbsoluteCommandContext(context.WithoutCancel(ctx), exe, detachedWorkerArgs(os.Args[1:])...)
+	if err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to locate the mush executable", err)
+	}
+
+	child.Stdin = devNull
+	child.Stdout = console
+	child.Stderr = console
+	child.SysProcAttr = detachedProcAttr()
+
+	if err := child.Start(); err != nil {
+		return clierrors.Wrap(clierrors.ExitExecution, "Failed to start the detached worker", err)
+	}
+
+	pid := child.Process.Pid
+
+	if err := worker.WritePIDFile(pid); err != nil {
+		out.Warning("Failed to record the worker's PID; 'mush worker stop' may not reach it until it is connected: %v", err)
+	}
+
+	exited := make(chan error, 1)
+
+	go func() { exited <- child.Wait() }()
+
+	spin := out.Spinner(fmt.Sprintf("Starting worker in the background (pid %d)", pid))
+	spin.Start()
+
+	err = waitForDetachedWorker(ctx, pid, exited)
+
+	switch {
+	case err == nil:
+		spin.StopWithSuccess(fmt.Sprintf("Worker running in the background (pid %d)", pid))
+	case errors.Is(err, errDetachTimeout):
+		spin.StopWithWarning(fmt.Sprintf("Worker started (pid %d) but is not answering on the control socket yet", pid))
+	case errors.Is(err, context.Canceled):
+		spin.StopWithWarning(fmt.Sprintf("Stopped waiting; the worker (pid %d) keeps starting in the background", pid))
+	default:
+		spin.StopWithFailure("Worker exited during startup")
+		printDetachedOutput(out, consolePath)
+
+		return &clierrors.CLIError{
+			Message: "Detached worker exited during startup",
+			Hint:    "See its output in " + consolePath + ", or run without --detach to start it in this terminal",
+			Cause:   err,
+			Code:    clierrors.ExitExecution,
+		}
+	}
+
+	out.Println()
+	out.Print("Follow its log:  mush worker logs --follow\n")
+	out.Print("Stop it:         mush worker stop\n")
+	out.Print("Console output:  %s\n", consolePath)
+
+	return nil
+}
+
+// detachedWorkerArgs returns the arguments that start the detached worker:
+// the command's own, without --detach, as a headless worker logging to its
+// log file, which `mush worker logs` reads.
+func detachedWorkerArgs(args []string) []string {
+	detached := make([]string, 0, len(args)+2)
+
+	for _, arg := range args {
+		if arg == "--detach" || strings.HasPrefix(arg, "--detach=") {
+			continue
+		}
+
+		detached = append(detached, arg)
+	}
+
+	return append(detached, "--headless", "--log-stderr=off")
+}
+
+// waitForDetachedWorker waits until the worker with pid answers on the
+// control socket, returning the worker's exit error if it exits first.
+func waitForDetachedWorker(ctx context.Context, pid int, exited <-chan error) error {
+	ctx, cancel := context.WithTimeout(ctx, detachStartTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(detachPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-exited:
+			if err == nil {
+				err = errors.New("exited with status 0")
+			}
+
+			return err
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return errDetachTimeout
+			}
+
+			return ctx.Err() //nolint:wrapcheck // interrupted by the user
+		case <-ticker.C:
+			if status, err := queryWorkerStatus(ctx); err == nil && status.PID == pid {
+				return nil
+			}
+		}
+	}
+}
+
+// printDetachedOutput prints the end of a detached worker's output.
+func printDetachedOutput(out *output.Writer, path string) {
+	data, err := safeio.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > detachOutputLines {
+		lines = lines[len(lines)-detachOutputLines:]
+	}
+
+	if len(lines) == 1 && lines[0] == "" {
+		return
+	}
+
+	out.Println()
+
+	for _, line := range lines {
+		out.Print("  %s\n", line)
+	}
+
+	out.Println()
+}
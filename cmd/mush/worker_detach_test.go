@@ -0,0 +1,46 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+	"testing"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func TestDetachedWorkerArgs(t *testing.T) {
+	got := detachedWorkerArgs([]string{"worker", "start", "--detach", "--habitat", "prod", "--detach=true", "--max-jobs", "5"})
+	want := []string{"worker", "start", "--habitat", "prod", "--max-jobs", "5", "--headless", "--log-stderr=off"}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("detachedWorkerArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestWorkerStartDetachRefusesSecondWorker(t *testing.T) {
+	t.Setenv("MUSHER_STATE_HOME", t.TempDir())
+
+	// This test process stands in for the running detached worker.
+	if err := worker.WritePIDFile(os.Getpid()); err != nil {
+		t.Fatalf("WritePIDFile() error = %v", err)
+	}
+
+	out, _ := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"start", "--detach"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	err := cmd.Execute()
+
+	var cliErr *clierrors.CLIError
+	if !errors.As(err, &cliErr) || cliErr.Message != clierrors.DetachedWorkerRunning(os.Getpid()).Message {
+		t.Fatalf("worker start --detach error = %v, want DetachedWorkerRunning", err)
+	}
+}
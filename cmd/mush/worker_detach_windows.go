@@ -0,0 +1,18 @@
+//go:build windows
+
+package main
+
+import (
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// detachedProcAttr starts the worker without a console and in its own
+// process group, so closing this console or pressing Ctrl+C in it does not
+// reach the worker.
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{
+		CreationFlags: windows.DETACHED_PROCESS | windows.CREATE_NEW_PROCESS_GROUP,
+	}
+}
@@ -0,0 +1,85 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+)
+
+// workerLimits bound a worker session for cron and CI use, where the worker
+// should exit on its own instead of watching the queue indefinitely.
+type workerLimits struct {
+	// maxJobs ends the session after this many jobs. Zero means no limit.
+	maxJobs int
+
+	// maxDuration and idleTimeout end the session after it has run that
+	// long, or gone that long without a job. Zero means no limit.
+	maxDuration time.Duration
+	idleTimeout time.Duration
+}
+
+// newWorkerLimits validates the --once, --max-jobs, --max-duration, and
+// --exit-when-idle flags.
+func newWorkerLimits(once bool, maxJobs int, maxDuration, idleTimeout time.Duration) (workerLimits, error) {
+	if maxJobs < 0 {
+		return workerLimits{}, clierrors.New(clierrors.ExitUsage, "--max-jobs must be a positive number")
+	}
+
+	if maxDuration < 0 {
+		return workerLimits{}, clierrors.New(clierrors.ExitUsage, "--max-duration must be a positive duration")
+	}
+
+	if idleTimeout < 0 {
+		return workerLimits{}, clierrors.New(clierrors.ExitUsage, "--exit-when-idle must be a positive duration")
+	}
+
+	if once {
+		maxJobs = 1
+	}
+
+	return workerLimits{maxJobs: maxJobs, maxDuration: maxDuration, idleTimeout: idleTimeout}, nil
+}
+
+// bounded reports whether the session ends on its own.
+func (l workerLimits) bounded() bool {
+	return l.maxJobs > 0 || l.maxDuration > 0 || l.idleTimeout > 0
+}
+
+// reportWorkerSummary prints the results of a bounded session. It returns an
+// error when any job failed, so scripts can tell a clean run from one that
+// needs attention: ExitTempFail when the platform requeued every failed job,
+// so running again later may succeed, and ExitExecution otherwise.
+func reportWorkerSummary(out *output.Writer, summary *harness.WorkerSummary) error {
+	processed := summary.Completed + summary.Failed
+
+	out.Println()
+
+	if summary.StopReason != "" {
+		out.Info("Worker stopped: %s", summary.StopReason)
+	}
+
+	if summary.Failed > 0 && summary.Requeued == summary.Failed {
+		return &clierrors.CLIError{
+			Message: fmt.Sprintf("%d of %d jobs failed and were requeued", summary.Failed, processed),
+			Hint:    "The failures may be temporary; run the worker again later to retry them",
+			Code:    clierrors.ExitTempFail,
+		}
+	}
+
+	if summary.Failed > 0 {
+		return &clierrors.CLIError{
+			Message: fmt.Sprintf("%d of %d jobs failed", summary.Failed, processed),
+			Hint:    "Run 'mush history list' to find the session transcripts",
+			Code:    clierrors.ExitExecution,
+		}
+	}
+
+	out.Success("Processed %d jobs: %d completed, %d failed", processed, summary.Completed, summary.Failed)
+
+	return nil
+}
@@ -0,0 +1,75 @@
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
+)
+
+func TestNewWorkerLimits(t *testing.T) {
+	limits, err := newWorkerLimits(true, 0, 0, 0)
+	if err != nil || limits.maxJobs != 1 {
+		t.Fatalf("newWorkerLimits(once) = %+v, %v; want maxJobs 1", limits, err)
+	}
+
+	limits, err = newWorkerLimits(false, 0, 0, 0)
+	if err != nil || limits.bounded() {
+		t.Fatalf("newWorkerLimits() = %+v, %v; want unbounded", limits, err)
+	}
+
+	limits, err = newWorkerLimits(false, 0, 0, 30*time.Minute)
+	if err != nil || !limits.bounded() {
+		t.Fatalf("newWorkerLimits(idle) = %+v, %v; want bounded", limits, err)
+	}
+
+	for _, args := range [][3]int{{-1, 0, 0}, {0, -1, 0}, {0, 0, -1}} {
+		_, err = newWorkerLimits(false, args[0], time.Duration(args[1])*time.Hour, time.Duration(args[2])*time.Minute)
+
+		var cliErr *clierrors.CLIError
+		if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitUsage {
+			t.Fatalf("newWorkerLimits(%v) error = %v, want an ExitUsage CLIError", args, err)
+		}
+	}
+}
+
+func TestReportWorkerSummary(t *testing.T) {
+	var buf bytes.Buffer
+
+	out := output.NewWriter(io.Discard, &buf, &terminal.Info{})
+
+	err := reportWorkerSummary(out, &harness.WorkerSummary{StopReason: "job limit reached", Completed: 2})
+	if err != nil {
+		t.Fatalf("reportWorkerSummary() error = %v", err)
+	}
+
+	if got := buf.String(); !strings.Contains(got, "job limit reached") || !strings.Contains(got, "Processed 2 jobs: 2 completed, 0 failed") {
+		t.Fatalf("reportWorkerSummary() output = %q", got)
+	}
+
+	err = reportWorkerSummary(out, &harness.WorkerSummary{Completed: 2, Failed: 1})
+
+	var cliErr *clierrors.CLIError
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitExecution || cliErr.Message != "1 of 3 jobs failed" {
+		t.Fatalf("reportWorkerSummary() error = %v, want ExitExecution '1 of 3 jobs failed'", err)
+	}
+
+	// Only some failures were requeued, so retrying would not clear them.
+	err = reportWorkerSummary(out, &harness.WorkerSummary{Completed: 1, Failed: 2, Requeued: 1})
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitExecution {
+		t.Fatalf("reportWorkerSummary() error = %v, want ExitExecution", err)
+	}
+
+	err = reportWorkerSummary(out, &harness.WorkerSummary{Completed: 1, Failed: 2, Requeued: 2})
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitTempFail {
+		t.Fatalf("reportWorkerSummary() error = %v, want ExitTempFail", err)
+	}
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/output"
+)
+
+// logLineHiddenAttrs are fields every log line carries, left out of the
+// pretty form to keep lines short.
+var logLineHiddenAttrs = map[string]bool{
+	"component":    true,
+	"session.id":   true,
+	"command.path": true,
+	"cli.version":  true,
+	"cli.commit":   true,
+}
+
+func newWorkerLogsCmd() *cobra.Command {
+	var (
+		follow     bool
+		level      string
+		components []string
+		events     []string
+		lines      int
+		file       string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show and follow the worker's structured log",
+		Long: `Show the end of the structured log the worker writes, one line per record
+with its time, level, component, message, and fields. Use --follow to keep
+printing records as they are written; following continues across log
+rotation until interrupted.
+
+The log is read from MUSH_LOG_FILE or the default log file under the state
+directory. Pass --file if the worker was started with --log-file. Records
+written by this command are left out.
+
+Filter with --level (the minimum level), --component, and --event. An event
+filter matches that event type and those under it, so --event job matches
+job.claim and job.fail. Use --json to print matching records as written.`,
+		Example: `  mush worker logs
+  mush worker logs --follow --level debug
+  mush worker logs --component harness --event job --lines 200
+  mush worker logs --follow --json | jq .msg`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			minLevel, err := observability.ParseLogLevel(level)
+			if err != nil {
+				return clierrors.Wrap(clierrors.ExitUsage, "Invalid --level", err).
+					WithHint("Use one of: error, warn, info, debug")
+			}
+
+			path := file
+			if path == "" {
+				path, err = observability.LogFilePath()
+				if err != nil {
+					return clierrors.Wrap(clierrors.ExitGeneral, "Failed to locate the worker log", err)
+				}
+			}
+
+			ctx, cancel := context.WithCancel(cmd.Context())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+
+			signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+			defer signal.Stop(sigCh)
+
+			go func() {
+				select {
+				case <-sigCh:
+					cancel()
+				case <-ctx.Done():
+				}
+			}()
+
+			opts := &observability.TailOptions{
+				Lines:  lines,
+				Follow: follow,
+				Filter: observability.LogFilter{
+					MinLevel:       minLevel,
+					Components:     components,
+					EventTypes:     events,
+					ExcludeCommand: cmd.CommandPath(),
+				},
+			}
+
+			err = observability.TailLog(ctx, path, opts, func(rec *observability.LogRecord) error {
+				if out.JSON {
+					out.Print("%s\n", rec.Raw)
+				} else {
+					out.Print("%s\n", formatLogRecord(rec))
+				}
+
+				return nil
+			})
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return clierrors.Wrap(clierrors.ExitGeneral, "No worker log at "+path, err).
+						WithHint("Start a worker with 'mush worker start', or pass --file if it logs elsewhere")
+				}
+
+				return clierrors.Wrap(clierrors.ExitGeneral, "Failed to read the worker log", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&follow, "follow", false, "Keep printing records as they are written")
+	cmd.Flags().StringVar(&level, "level", "info", "Minimum level to show: error, warn, info, debug")
+	cmd.Flags().StringSliceVar(&components, "component", nil, "Only show records from these components")
+	cmd.Flags().StringSliceVar(&events, "event", nil, "Only show records with these event types or their children")
+	cmd.Flags().IntVar(&lines, "lines", 50, "Number of matching records to show before following (-1 for all)")
+	cmd.Flags().StringVar(&file, "file", "", "Log file to read instead of the default")
+
+	return cmd
+}
+
+// formatLogRecord renders rec as "time LEVEL component message key=value...".
+func formatLogRecord(rec *observability.LogRecord) string {
+	if !rec.Structured {
+		return string(rec.Raw)
+	}
+
+	var b strings.Builder
+
+	if !rec.Time.IsZero() {
+		b.WriteString(rec.Time.Local().Format("15:04:05.000"))
+		b.WriteByte(' ')
+	}
+
+	fmt.Fprintf(&b, "%-5s", rec.Level.String())
+
+	if component := rec.Attr("component"); component != "" {
+		b.WriteByte(' ')
+		b.WriteString(component)
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(rec.Message)
+
+	keys := make([]string, 0, len(rec.Attrs))
+	for key := range rec.Attrs {
+		if !logLineHiddenAttrs[key] {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		b.WriteByte(' ')
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(formatLogValue(rec.Attrs[key]))
+	}
+
+	return b.String()
+}
+
+func formatLogValue(value any) string {
+	if s, ok := value.(string); ok {
+		if s == "" || strings.ContainsAny(s, " \t\"=") {
+			data, _ := json.Marshal(s)
+			return string(data)
+		}
+
+		return s
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+func TestWorkerLogsFiltersAndFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mush.log")
+	lines := strings.Join([]string{
+		`{"time":"2026-03-04T05:06:07Z","level":"DEBUG","msg":"polling","component":"worker","event.type":"job.poll"}`,
+		`{"time":"2026-03-04T05:06:08Z","level":"INFO","msg":"job claimed","component":"worker","event.type":"job.claim","job.id":"job-7","session.id":"s-1"}`,
+		`{"time":"2026-03-04T05:06:09Z","level":"WARN","msg":"slow","component":"harness","event.type":"harness.slow"}`,
+		`{"time":"2026-03-04T05:06:10Z","level":"INFO","msg":"reading","component":"cli","command.path":"mush worker logs"}`,
+	}, "\n") + "\n"
+
+	if err := os.WriteFile(path, []byte(lines), 0o600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+
+	out, buf := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"logs", "--file", path, "--event", "job"})
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("worker logs error = %v", err)
+	}
+
+	got := buf.String()
+	if !strings.Contains(got, "INFO  worker job claimed event.type=job.claim job.id=job-7\n") {
+		t.Fatalf("output missing the claim record:\n%s", got)
+	}
+
+	for _, unwanted := range []string{"polling", "slow", "reading", "session.id"} {
+		if strings.Contains(got, unwanted) {
+			t.Fatalf("output contains %q:\n%s", unwanted, got)
+		}
+	}
+}
+
+func TestWorkerLogsMissingFile(t *testing.T) {
+	out, _ := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"logs", "--file", filepath.Join(t.TempDir(), "missing.log")})
+	cmd.SetContext(out.WithContext(t.Context()))
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+
+	err := cmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "No worker log") {
+		t.Fatalf("worker logs error = %v, want a missing log error", err)
+	}
+}
+
+func TestFormatLogRecordQuotesValues(t *testing.T) {
+	rec := observability.ParseLogRecord([]byte(`{"level":"ERROR","msg":"failed","error":"exit status 1","attempt":2}`))
+
+	if got, want := formatLogRecord(&rec), `ERROR failed attempt=2 error="exit status 1"`; got != want {
+		t.Fatalf("formatLogRecord() = %q, want %q", got, want)
+	}
+}
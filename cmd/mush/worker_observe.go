@@ -0,0 +1,251 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func newWorkerObserveCmd() *cobra.Command {
+	var (
+		target string
+		stream bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "observe",
+		Short: "Watch a running worker's terminal without controlling it",
+		Long: `Mirror the status bar and harness terminal of a running worker, read-only.
+Nothing you type reaches the worker, so observing is safe while it runs jobs,
+and any number of observers can watch at once.
+
+By default the worker running on this machine is observed. --target takes the
+path of another worker's control socket, or an SSH host, where the worker on
+that host is observed by running 'mush worker observe' there over ssh.
+
+Observing shows the harness terminal at the worker's size; a smaller terminal
+shows its top-left part. Press q, Esc, ^C, or ^Q to stop observing.`,
+		Example: `  mush worker observe
+  mush worker observe --target build-box
+  mush worker observe --target /run/user/1001/musher/worker.sock`,
+		Args: noArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := output.FromContext(cmd.Context())
+
+			if stream {
+				return streamWorkerObservation(cmd.Context(), out, target)
+			}
+
+			if !out.Terminal().IsTTY {
+				return &clierrors.CLIError{
+					Message: "Observing a worker requires a terminal (TTY)",
+					Hint:    "Run this command directly in a terminal, or use 'mush worker status' in scripts",
+					Code:    clierrors.ExitUsage,
+				}
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+			defer stop()
+
+			return observeWorker(ctx, out, target)
+		},
+	}
+
+	cmd.Flags().StringVar(&target, "target", "", "Control socket path or SSH host of the worker to observe")
+	cmd.Flags().BoolVar(&stream, "stream", false, "Write the observation stream to stdout, for observing over SSH")
+	_ = cmd.Flags().MarkHidden("stream")
+
+	return cmd
+}
+
+// observeTarget reports whether target names an SSH host rather than a
+// control socket. Paths contain a slash or exist; anything else is a host.
+func observeTarget(target string) (host string, isHost bool) {
+	if target == "" || strings.Contains(target, "/") {
+		return "", false
+	}
+
+	if _, err := os.Stat(target); err == nil {
+		return "", false
+	}
+
+	return target, true
+}
+
+func observeWorker(ctx context.Context, out *output.Writer, target string) error {
+	var (
+		obs    *worker.Observation
+		remote *sshObservation
+		err    error
+	)
+
+	if host, isHost := observeTarget(target); isHost {
+		remote, err = startSSHObservation(ctx, host)
+		if err != nil {
+			return clierrors.Wrap(clierrors.ExitGeneral, "Failed to start ssh to "+host, err)
+		}
+
+		obs = worker.ReadObservation(remote)
+	} else {
+		obs, err = worker.ObserveWorker(ctx, target)
+		if err != nil {
+			return workerControlError(err)
+		}
+	}
+
+	defer func() { _ = obs.Close() }()
+
+	// The first frame is read before the screen is taken over, so a worker
+	// that cannot be observed is reported in plain text.
+	first, err := obs.Next()
+	if err != nil {
+		return observeError(err, remote)
+	}
+
+	label := target
+	if label == "" {
+		label = "this machine"
+		if first.Status != nil {
+			label = first.Status.WorkerID
+		}
+	}
+
+	err = harness.Observe(ctx, obs, first, label)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, io.EOF) {
+		out.Info("The worker closed the observation; it has exited or stopped serving its control socket")
+		return nil
+	}
+
+	return observeError(err, remote)
+}
+
+func observeError(err error, remote *sshObservation) error {
+	if remote != nil && errors.Is(err, io.EOF) {
+		hint := "Check that mush is installed on " + remote.host + " and a worker is running there"
+		if detail := remote.wait(); detail != "" {
+			hint = detail
+		}
+
+		return &clierrors.CLIError{
+			Message: "Failed to observe the worker on " + remote.host,
+			Hint:    hint,
+			Cause:   err,
+			Code:    clierrors.ExitGeneral,
+		}
+	}
+
+	if errors.Is(err, worker.ErrObserveUnsupported) {
+		return &clierrors.CLIError{
+			Message: "The running worker cannot be observed",
+			Hint:    "Restart it with this version of mush to observe it",
+			Code:    clierrors.ExitGeneral,
+		}
+	}
+
+	if errors.Is(err, io.EOF) {
+		return clierrors.WorkerNotRunning()
+	}
+
+	return clierrors.Wrap(clierrors.ExitGeneral, "Failed to observe the worker", err)
+}
+
+// streamWorkerObservation relays the local worker's observation stream to
+// stdout for an observer on another machine.
+func streamWorkerObservation(ctx context.Context, out *output.Writer, target string) error {
+	obs, err := worker.ObserveWorker(ctx, target)
+	if err != nil {
+		return workerControlError(err)
+	}
+
+	defer func() { _ = obs.Close() }()
+
+	if err := obs.Relay(out.Raw()); err != nil {
+		return clierrors.Wrap(clierrors.ExitGeneral, "Failed to relay the worker observation", err)
+	}
+
+	return nil
+}
+
+// sshObservation is the stream of `mush worker observe --stream` run on
+// another host over ssh.
+type sshObservation struct {
+	io.ReadCloser
+
+	host   string
+	cmd    *exec.Cmd
+	cancel context.CancelFunc
+	// errBuf keeps what ssh and the remote mush write to stderr, which
+	// would corrupt the screen, for reporting a failure.
+	errBuf bytes.Buffer
+	waited bool
+}
+
+func startSSHObservation(ctx context.Context, host string) (*sshObservation, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	cmd, err := executil.CommandContext(ctx, "ssh", "-T", host, "mush", "worker", "observe", "--stream")
+	if err != nil {
+		cancel()
+		return nil, err //nolint:wrapcheck // already names the command
+	}
+
+	s := &sshObservation{host: host, cmd: cmd, cancel: cancel}
+	cmd.Stderr = &s.errBuf
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		return nil, err //nolint:wrapcheck // reported with the host by the caller
+	}
+
+	s.ReadCloser = stdout
+
+	if err := cmd.Start(); err != nil {
+		cancel()
+		return nil, err //nolint:wrapcheck // reported with the host by the caller
+	}
+
+	return s, nil
+}
+
+// wait waits for ssh, which has closed the stream, to exit, and returns the
+// last line written to stderr.
+func (s *sshObservation) wait() string {
+	if !s.waited {
+		s.waited = true
+		_ = s.cmd.Wait()
+		s.cancel()
+	}
+
+	lines := strings.Split(strings.TrimSpace(s.errBuf.String()), "\n")
+
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// Close ends the ssh session.
+func (s *sshObservation) Close() error {
+	s.cancel()
+	s.wait()
+
+	return nil
+}
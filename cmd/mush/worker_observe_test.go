@@ -0,0 +1,110 @@
+//go:build unix
+
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func TestObserveTarget(t *testing.T) {
+	dir := t.TempDir()
+	socket := filepath.Join(dir, "worker.sock")
+
+	if err := os.WriteFile(socket, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	t.Chdir(dir)
+
+	tests := []struct {
+		target   string
+		wantHost string
+	}{
+		{target: ""},
+		{target: socket},
+		{target: "./worker.sock"},
+		{target: "worker.sock"},
+		{target: "build-box", wantHost: "build-box"},
+		{target: "deploy@build-box", wantHost: "deploy@build-box"},
+	}
+
+	for _, tt := range tests {
+		host, isHost := observeTarget(tt.target)
+		if host != tt.wantHost || isHost != (tt.wantHost != "") {
+			t.Errorf("observeTarget(%q) = %q, %v; want %q", tt.target, host, isHost, tt.wantHost)
+		}
+	}
+}
+
+type observedWorker struct {
+	output chan []byte
+}
+
+func (w *observedWorker) ControlStatus() worker.ControlStatus {
+	return worker.ControlStatus{WorkerID: "wrk-1", Status: "Processing", JobID: "job-7"}
+}
+
+func (w *observedWorker) RequestStop() {}
+
+func (w *observedWorker) RequestReload() {}
+
+func (w *observedWorker) TerminalSize() (cols, rows int) { return 120, 40 }
+
+func (w *observedWorker) SubscribeOutput() (recent []byte, output <-chan []byte, cancel func()) {
+	return []byte("recent output"), w.output, func() {}
+}
+
+func TestWorkerObserveStreamRelaysFrames(t *testing.T) {
+	// Unix socket paths are limited in length, so t.TempDir is too deep.
+	runtimeDir, err := os.MkdirTemp("", "mushobs")
+	if err != nil {
+		t.Fatalf("MkdirTemp() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(runtimeDir) })
+	t.Setenv("MUSHER_RUNTIME_DIR", runtimeDir)
+
+	// A closed output channel ends the stream, as for an observer that fell
+	// behind.
+	handler := &observedWorker{output: make(chan []byte)}
+	close(handler.output)
+
+	srv, err := worker.ListenControl(t.Context(), handler)
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	defer func() { _ = srv.Close() }()
+
+	out, buf := testWriter()
+	cmd := newWorkerCmd()
+	cmd.SetArgs([]string{"observe", "--stream"})
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	cmd.SetContext(out.WithContext(t.Context()))
+
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("worker observe --stream error = %v", err)
+	}
+
+	obs := worker.ReadObservation(io.NopCloser(strings.NewReader(buf.String())))
+
+	first, err := obs.Next()
+	if err != nil {
+		t.Fatalf("first relayed frame error = %v (stream %q)", err, buf.String())
+	}
+
+	if first.Status == nil || first.Status.JobID != "job-7" || first.Cols != 120 || string(first.Output) != "recent output" {
+		t.Fatalf("first relayed frame = %+v, want the worker's status, size, and recent output", first)
+	}
+
+	if _, err := obs.Next(); err == nil || !strings.Contains(err.Error(), "fell behind") {
+		t.Fatalf("last relayed frame error = %v, want the worker's reason for ending", err)
+	}
+}
@@ -1,4 +1,4 @@
-//go:build !unix
+//go:build !unix && !windows
 
 package main
 
@@ -41,6 +41,10 @@ Watch mode is currently supported only on Unix-like systems.`,
 	}
 
 	cmd.AddCommand(newWorkerStartCmd())
+	cmd.AddCommand(newWorkerStatusCmd())
+	cmd.AddCommand(newWorkerStopCmd())
+	cmd.AddCommand(newWorkerReloadCmd())
+	cmd.AddCommand(newWorkerLogsCmd())
 
 	return cmd
 }
@@ -0,0 +1,142 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/resolve"
+)
+
+// workerStartupConcurrency bounds the platform requests worker start has in
+// flight at once while connecting.
+const workerStartupConcurrency = 3
+
+// workerConnection is what worker start learns from the platform before a
+// habitat is selected.
+type workerConnection struct {
+	identity *client.Identity
+	habitats []client.HabitatSummary
+}
+
+// connectWorker validates the API key and lists habitats concurrently behind
+// one spinner, so startup costs one round-trip on high-latency links instead
+// of two. Output is held until every request finishes; an authentication
+// failure is reported ahead of other errors. The runner config is fetched
+// once a habitat is selected, since it depends on the habitat.
+func connectWorker(ctx context.Context, c *client.Client, out *output.Writer) (*workerConnection, error) {
+	const label = "Connecting to platform"
+
+	conn := &workerConnection{}
+
+	var (
+		identityErr error
+		habitatsErr error
+	)
+
+	steps := []func(){
+		func() { conn.identity, identityErr = c.CachedIdentity(ctx) },
+		func() { conn.habitats, habitatsErr = resolve.NewResolver(c, out).ListHabitats(ctx) },
+	}
+
+	spin := out.Spinner(label)
+	spin.Start()
+
+	var (
+		mu   sync.Mutex
+		done int
+		g    errgroup.Group
+	)
+
+	g.SetLimit(workerStartupConcurrency)
+
+	for _, step := range steps {
+		g.Go(func() error {
+			step()
+
+			mu.Lock()
+			done++
+			spin.UpdateMessage(fmt.Sprintf("%s (%d/%d)", label, done, len(steps)))
+			mu.Unlock()
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	if identityErr != nil {
+		spin.Stop()
+		return nil, clierrors.AuthFailed(identityErr)
+	}
+
+	if habitatsErr != nil {
+		spin.Stop()
+		return nil, habitatsErr
+	}
+
+	spin.StopWithSuccess("Connected to " + c.BaseURL())
+	out.Print("Authenticated as: %s (Organization: %s)\n", conn.identity.CredentialName, conn.identity.OrganizationName)
+
+	return conn, nil
+}
+
+// checkQueueAvailability fails unless queue has an active instruction, which
+// it returns.
+func checkQueueAvailability(ctx context.Context, c *client.Client, queue *client.QueueSummary) (*client.InstructionAvailability, error) {
+	availability, err := c.GetQueueInstructionAvailability(ctx, queue.ID)
+	if err != nil {
+		return nil, clierrors.NetworkFailed("Failed to check queue configuration", err).
+			WithHint("Check your network connection or run 'mush doctor'")
+	}
+
+	if availability == nil || !availability.HasActiveInstruction {
+		return nil, clierrors.NoInstructionsForQueue(queue.Name, queue.Slug)
+	}
+
+	return availability, nil
+}
+
+// checkInstructionMCPProviders fails when the instruction needs MCP providers
+// the runner config does not provide, so a missing integration is reported
+// once before claiming instead of failing every job at tool-call time. A
+// stale cached config only warns: its credentials may have expired while the
+// platform was unreachable, and the harness refreshes them once it recovers.
+func checkInstructionMCPProviders(
+	out *output.Writer,
+	availability *client.InstructionAvailability,
+	runnerConfig *client.RunnerConfigResponse,
+	runnerConfigStale bool,
+	now time.Time,
+) error {
+	if availability == nil || len(availability.RequiredMCPProviders) == 0 {
+		return nil
+	}
+
+	missing := harness.MissingMCPServers(runnerConfig, availability.RequiredMCPProviders, now)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	instructionName := availability.InstructionName
+	if instructionName == "" {
+		instructionName = availability.InstructionSlug
+	}
+
+	err := clierrors.MCPProvidersNotConnected(instructionName, missing)
+	if runnerConfigStale {
+		out.Warning("%s in the cached runner config; jobs may fail until the platform is reachable", err.Message)
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+//go:build unix
+
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
+)
+
+func TestConnectWorkerReportsAuthFailureFirst(t *testing.T) {
+	hc := &http.Client{Transport: workerRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		return workerJSONResponse(http.StatusUnauthorized, `{}`), nil
+	})}
+	c := client.NewWithHTTPClient("https://api.test", "bad-key", hc)
+	out := output.NewWriter(io.Discard, io.Discard, &terminal.Info{})
+
+	_, err := connectWorker(t.Context(), c, out)
+
+	var cliErr *clierrors.CLIError
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitAuth {
+		t.Fatalf("connectWorker() error = %v, want an ExitAuth CLIError", err)
+	}
+}
+
+func TestConnectWorkerCollectsStartupState(t *testing.T) {
+	c := workerMockClient(t, `{"configVersion":"1","organizationId":"org-1","generatedAt":"2026-02-13T12:00:00Z","refreshAfterSeconds":300,"providers":{}}`)
+	out := output.NewWriter(io.Discard, io.Discard, &terminal.Info{})
+
+	conn, err := connectWorker(t.Context(), c, out)
+	if err != nil {
+		t.Fatalf("connectWorker() error = %v", err)
+	}
+
+	if conn.identity.OrganizationName != "Test Organization" || len(conn.habitats) != 1 {
+		t.Fatalf("connectWorker() = %+v, want identity and one habitat", conn)
+	}
+}
+
+func TestLoadRunnerConfigForHabitat(t *testing.T) {
+	t.Setenv("MUSHER_STATE_HOME", t.TempDir())
+
+	var gotHabitat string
+
+	hc := &http.Client{Transport: workerRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotHabitat = r.URL.Query().Get("habitat_id")
+		return workerJSONResponse(http.StatusOK, `{"configVersion":"1","organizationId":"org-1","habitatId":"hab-1","generatedAt":"2026-02-13T12:00:00Z","refreshAfterSeconds":300,"providers":{}}`), nil
+	})}
+	c := client.NewWithHTTPClient("https://api.test", "test-key", hc)
+
+	cfg, stale, warning, fetchErr := loadRunnerConfig(t.Context(), c, "hab-1", slog.New(slog.DiscardHandler))
+	if gotHabitat != "hab-1" {
+		t.Fatalf("runner config requested for habitat %q, want hab-1", gotHabitat)
+	}
+
+	if cfg == nil || cfg.HabitatID != "hab-1" || stale || warning != "" || fetchErr != nil {
+		t.Fatalf("loadRunnerConfig() = %+v, %v, %q, %v; want a fresh habitat config", cfg, stale, warning, fetchErr)
+	}
+}
+
+func TestCheckInstructionMCPProviders(t *testing.T) {
+	out := output.NewWriter(io.Discard, io.Discard, &terminal.Info{})
+	availability := &client.InstructionAvailability{
+		HasActiveInstruction: true,
+		InstructionName:      "Triage",
+		RequiredMCPProviders: []string{"linear"},
+	}
+	connected := &client.RunnerConfigResponse{
+		Providers: map[string]client.RunnerProviderConfig{
+			"linear": {
+				Status:     "active",
+				Flags:      client.RunnerProviderFlags{MCP: true},
+				MCP:        &client.RunnerProviderMCP{URL: "https://mcp.linear.app/mcp"},
+				Credential: &client.RunnerProviderCredential{AccessToken: "tok"},
+			},
+		},
+	}
+	now := time.Now()
+
+	if err := checkInstructionMCPProviders(out, availability, connected, false, now); err != nil {
+		t.Fatalf("checkInstructionMCPProviders(connected) error = %v", err)
+	}
+
+	err := checkInstructionMCPProviders(out, availability, &client.RunnerConfigResponse{}, false, now)
+
+	var cliErr *clierrors.CLIError
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitConfig || cliErr.Message != "Linear MCP not connected for this workspace" {
+		t.Fatalf("checkInstructionMCPProviders(missing) error = %v, want an ExitConfig CLIError for Linear", err)
+	}
+
+	if err := checkInstructionMCPProviders(out, availability, &client.RunnerConfigResponse{}, true, now); err != nil {
+		t.Fatalf("checkInstructionMCPProviders(stale) error = %v, want only a warning", err)
+	}
+}
@@ -0,0 +1,64 @@
+//go:build unix || windows
+
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+)
+
+// strictHarnessesError fails a --strict worker start whose harness set
+// includes CLIs that are not installed, instead of disabling those harnesses.
+func strictHarnessesError(unavailable []string) *clierrors.CLIError {
+	return &clierrors.CLIError{
+		Message: fmt.Sprintf("Harness CLIs not found: %s", strings.Join(unavailable, ", ")),
+		Hint:    "Install them, narrow --harness or worker.harnesses to the installed ones, or drop --strict to start without them",
+		Code:    clierrors.ExitConfig,
+	}
+}
+
+// strictRunnerConfigError fails a --strict worker start when the habitat's
+// runner config could not be fetched, instead of starting from the cached
+// copy or without MCP provisioning.
+func strictRunnerConfigError(err error) *clierrors.CLIError {
+	return clierrors.Wrap(clierrors.ExitNetwork, "Runner config unavailable", err).
+		WithHint("Check your network connection or run 'mush doctor', or drop --strict to start without a fresh runner config")
+}
+
+// checkRunnerConfigErrors reports the MCP providers the platform could not
+// resolve in the runner config. Jobs run without those providers' servers, so
+// a --strict start fails; otherwise each is a warning.
+func checkRunnerConfigErrors(out *output.Writer, cfg *client.RunnerConfigResponse, strict bool) error {
+	if cfg == nil || len(cfg.Errors) == 0 {
+		return nil
+	}
+
+	problems := make([]string, 0, len(cfg.Errors))
+
+	for _, e := range cfg.Errors {
+		problem := e.Provider + ": " + e.Message
+		if e.Code != "" {
+			problem += " (" + e.Code + ")"
+		}
+
+		problems = append(problems, problem)
+	}
+
+	if !strict {
+		for _, problem := range problems {
+			out.Warning("MCP provider unavailable, continuing without it: %s", problem)
+		}
+
+		return nil
+	}
+
+	return &clierrors.CLIError{
+		Message: fmt.Sprintf("MCP providers unavailable: %s", strings.Join(problems, "; ")),
+		Hint:    "Reconnect the integrations in the console, or drop --strict to start without them",
+		Code:    clierrors.ExitConfig,
+	}
+}
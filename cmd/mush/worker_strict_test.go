@@ -0,0 +1,69 @@
+//go:build unix
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/terminal"
+)
+
+func TestCheckRunnerConfigErrors(t *testing.T) {
+	var buf bytes.Buffer
+
+	out := output.NewWriter(&buf, &buf, &terminal.Info{})
+	cfg := &client.RunnerConfigResponse{
+		Errors: []client.RunnerConfigError{{Provider: "linear", Code: "token_expired", Message: "credential expired"}},
+	}
+
+	if err := checkRunnerConfigErrors(out, cfg, false); err != nil {
+		t.Fatalf("checkRunnerConfigErrors(lenient) error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "linear: credential expired (token_expired)") {
+		t.Fatalf("lenient output = %q, want a warning naming the provider", buf.String())
+	}
+
+	err := checkRunnerConfigErrors(out, cfg, true)
+
+	var cliErr *clierrors.CLIError
+	if !clierrors.As(err, &cliErr) || cliErr.Code != clierrors.ExitConfig {
+		t.Fatalf("checkRunnerConfigErrors(strict) error = %v, want an ExitConfig CLIError", err)
+	}
+
+	if err := checkRunnerConfigErrors(out, &client.RunnerConfigResponse{}, true); err != nil {
+		t.Fatalf("checkRunnerConfigErrors(no errors) error = %v", err)
+	}
+}
+
+func TestStrictStartupErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"missing harnesses", strictHarnessesError([]string{"codex"}), clierrors.ExitConfig},
+		{"runner config", strictRunnerConfigError(errors.New("connection refused")), clierrors.ExitNetwork},
+		{"transcript", workerRunError("Headless worker failed", fmt.Errorf("%w: read-only file system", harness.ErrTranscriptUnavailable)), clierrors.ExitConfig},
+	}
+
+	for _, tt := range tests {
+		var cliErr *clierrors.CLIError
+		if !clierrors.As(tt.err, &cliErr) || cliErr.Code != tt.code {
+			t.Errorf("%s: error = %v, want a CLIError with code %d", tt.name, tt.err, tt.code)
+			continue
+		}
+
+		if !strings.Contains(cliErr.Hint, "--strict") {
+			t.Errorf("%s: hint = %q, want it to mention --strict", tt.name, cliErr.Hint)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/musher-dev/mush/internal/client"
 	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/resolve"
 	"github.com/musher-dev/mush/internal/terminal"
 )
 
@@ -191,16 +192,18 @@ func TestResolveQueueAndHabitatNoInputSelection(t *testing.T) {
 	out := output.NewWriter(io.Discard, io.Discard, &terminal.Info{})
 	out.NoInput = true
 
-	habitatID, err := resolveHabitatID(t.Context(), c, "", out)
+	resolver := resolve.NewResolver(c, out)
+
+	habitat, err := resolver.Habitat(t.Context(), "")
 	if err != nil {
-		t.Fatalf("resolveHabitatID() error = %v", err)
+		t.Fatalf("Habitat() error = %v", err)
 	}
 
-	if habitatID != "hab-1" {
-		t.Fatalf("resolveHabitatID() = %q, want hab-1", habitatID)
+	if habitat.ID != "hab-1" {
+		t.Fatalf("Habitat().ID = %q, want hab-1", habitat.ID)
 	}
 
-	queue, err := resolveQueue(t.Context(), c, "hab-1", "", out)
+	queue, err := resolveQueue(t.Context(), resolver, "hab-1", "", out)
 	if err != nil {
 		t.Fatalf("resolveQueue() error = %v", err)
 	}
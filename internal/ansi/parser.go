@@ -0,0 +1,304 @@
+package ansi
+
+// Kind identifies what a Token holds.
+type Kind int
+
+const (
+	// KindText is printable output, including C0 controls such as CR and LF,
+	// and escape bytes that do not start a valid sequence.
+	KindText Kind = iota
+	// KindEscape is a two-byte escape (ESC 7, ESC c) or an nF escape with
+	// intermediate bytes (ESC ( B).
+	KindEscape
+	// KindCSI is a control sequence (ESC [ ... final).
+	KindCSI
+	// KindOSC is an operating system command (ESC ] ... BEL or ST).
+	KindOSC
+	// KindDCS is a device control string (ESC P ... ST).
+	KindDCS
+	// KindSOS is a start of string (ESC X ... ST).
+	KindSOS
+	// KindPM is a privacy message (ESC ^ ... ST).
+	KindPM
+	// KindAPC is an application program command (ESC _ ... ST).
+	KindAPC
+)
+
+// String returns the name of the kind, for tests and debugging.
+func (k Kind) String() string {
+	switch k {
+	case KindText:
+		return "text"
+	case KindEscape:
+		return "escape"
+	case KindCSI:
+		return "csi"
+	case KindOSC:
+		return "osc"
+	case KindDCS:
+		return "dcs"
+	case KindSOS:
+		return "sos"
+	case KindPM:
+		return "pm"
+	case KindAPC:
+		return "apc"
+	default:
+		return "unknown"
+	}
+}
+
+// Token is a run of text or one complete escape sequence.
+type Token struct {
+	Kind Kind
+	// Raw holds the exact bytes of the token. Writing every Raw in order
+	// reproduces the input, less any abandoned CSI sequence.
+	Raw string
+	// Params holds CSI parameter bytes (0x30-0x3F), including any private
+	// marker, e.g. "?25" for ESC [ ? 25 h.
+	Params string
+	// Intermediate holds CSI and escape intermediate bytes (0x20-0x2F).
+	Intermediate string
+	// Final is the final byte of a CSI or escape sequence.
+	Final byte
+	// Data holds the payload of OSC, DCS, SOS, PM, and APC strings, without
+	// the introducer and terminator.
+	Data string
+}
+
+type parserState int
+
+const (
+	stGround          parserState = iota
+	stEscape                      // ESC received, waiting for dispatch byte
+	stEscIntermediate             // ESC + intermediate byte (0x20-0x2F) — nF escape
+	stCSI                         // Inside CSI sequence (ESC [)
+	stString                      // Inside OSC/DCS/SOS/PM/APC string
+	stStringEsc                   // ESC seen inside string (possible ST = ESC \)
+)
+
+// Parser tokenizes terminal output following ECMA-48. It is incremental:
+// a sequence split across Feed calls is held back until it completes, so
+// PTY output can be fed chunk by chunk. The zero value is ready to use.
+type Parser struct {
+	state parserState
+	kind  Kind
+	buf   []byte
+}
+
+// Feed tokenizes data, calling emit for each text run and complete
+// sequence in order. Text is emitted as soon as it is seen; Token values
+// do not alias data.
+//
+// Like a terminal, an ESC inside a CSI sequence abandons it and starts a
+// new escape; the abandoned bytes are dropped. An ESC followed by a byte
+// that cannot start a sequence is emitted as text.
+func (p *Parser) Feed(data []byte, emit func(Token)) {
+	textStart := 0
+
+	for i, c := range data {
+		switch p.state {
+		case stGround:
+			if c != escByte {
+				continue
+			}
+
+			if i > textStart {
+				emit(Token{Kind: KindText, Raw: string(data[textStart:i])})
+			}
+
+			p.begin()
+
+		case stEscape:
+			p.buf = append(p.buf, c)
+
+			switch {
+			case c == '[':
+				p.state = stCSI
+			case c == ']':
+				p.beginString(KindOSC)
+			case c == 'P':
+				p.beginString(KindDCS)
+			case c == 'X':
+				p.beginString(KindSOS)
+			case c == '^':
+				p.beginString(KindPM)
+			case c == '_':
+				p.beginString(KindAPC)
+			case c >= 0x20 && c <= 0x2F:
+				p.state = stEscIntermediate
+			case c >= 0x30 && c <= 0x7E:
+				// Fp (0x30-0x3F, DEC private like ESC 7/ESC 8), Fe (0x40-0x5F),
+				// or Fs (0x60-0x7E) — two-byte escape.
+				emit(p.escapeToken())
+				textStart = i + 1
+			default:
+				textStart = p.reject(i, c, emit)
+			}
+
+		case stEscIntermediate:
+			p.buf = append(p.buf, c)
+
+			switch {
+			case c >= 0x20 && c <= 0x2F:
+				// More intermediate bytes.
+			case c >= 0x30 && c <= 0x7E:
+				emit(p.escapeToken())
+				textStart = i + 1
+			default:
+				textStart = p.reject(i, c, emit)
+			}
+
+		case stCSI:
+			if c == escByte {
+				p.begin()
+				continue
+			}
+
+			p.buf = append(p.buf, c)
+
+			// CSI final byte: 0x40-0x7E per ECMA-48.
+			if c >= 0x40 && c <= 0x7E {
+				emit(p.csiToken())
+				textStart = i + 1
+			}
+
+		case stString:
+			p.buf = append(p.buf, c)
+
+			switch {
+			case c == escByte:
+				p.state = stStringEsc
+			case c == belByte && p.kind == KindOSC:
+				// xterm accepts BEL as an OSC terminator.
+				emit(p.stringToken(1))
+				textStart = i + 1
+			}
+
+		case stStringEsc:
+			p.buf = append(p.buf, c)
+
+			switch c {
+			case '\\':
+				emit(p.stringToken(2))
+				textStart = i + 1
+			case escByte:
+				// Stay in this state: the new ESC may start ST.
+			default:
+				p.state = stString
+			}
+		}
+	}
+
+	if p.state == stGround && textStart < len(data) {
+		emit(Token{Kind: KindText, Raw: string(data[textStart:])})
+	}
+}
+
+// Flush emits any sequence still incomplete at the end of the input as
+// text, verbatim rather than silently discarded, and resets the parser.
+func (p *Parser) Flush(emit func(Token)) {
+	if p.state != stGround && len(p.buf) > 0 {
+		emit(Token{Kind: KindText, Raw: string(p.buf)})
+	}
+
+	p.reset()
+}
+
+// Tokenize splits s into text runs and escape sequences. An incomplete
+// trailing sequence is returned as text.
+func Tokenize(s string) []Token {
+	var (
+		parser Parser
+		tokens []Token
+	)
+
+	emit := func(tok Token) {
+		tokens = append(tokens, tok)
+	}
+
+	parser.Feed([]byte(s), emit)
+	parser.Flush(emit)
+
+	return tokens
+}
+
+func (p *Parser) begin() {
+	p.buf = append(p.buf[:0], escByte)
+	p.state = stEscape
+}
+
+func (p *Parser) beginString(kind Kind) {
+	p.kind = kind
+	p.state = stString
+}
+
+func (p *Parser) reset() {
+	p.buf = p.buf[:0]
+	p.state = stGround
+}
+
+// reject handles a byte that cannot continue an escape: the buffered bytes
+// before it are emitted as text and c is processed again from the ground
+// state. It returns where the next text run starts.
+func (p *Parser) reject(i int, c byte, emit func(Token)) int {
+	emit(Token{Kind: KindText, Raw: string(p.buf[:len(p.buf)-1])})
+
+	if c == escByte {
+		p.begin()
+		return i + 1
+	}
+
+	p.reset()
+
+	return i
+}
+
+func (p *Parser) escapeToken() Token {
+	tok := Token{
+		Kind:         KindEscape,
+		Raw:          string(p.buf),
+		Intermediate: string(p.buf[1 : len(p.buf)-1]),
+		Final:        p.buf[len(p.buf)-1],
+	}
+	p.reset()
+
+	return tok
+}
+
+func (p *Parser) csiToken() Token {
+	var params, intermediate []byte
+
+	for _, c := range p.buf[2 : len(p.buf)-1] {
+		switch {
+		case c >= 0x30 && c <= 0x3F:
+			params = append(params, c)
+		case c >= 0x20 && c <= 0x2F:
+			intermediate = append(intermediate, c)
+		}
+	}
+
+	tok := Token{
+		Kind:         KindCSI,
+		Raw:          string(p.buf),
+		Params:       string(params),
+		Intermediate: string(intermediate),
+		Final:        p.buf[len(p.buf)-1],
+	}
+	p.reset()
+
+	return tok
+}
+
+// stringToken builds the token for a terminated string whose terminator is
+// termLen bytes long (1 for BEL, 2 for ST).
+func (p *Parser) stringToken(termLen int) Token {
+	tok := Token{
+		Kind: p.kind,
+		Raw:  string(p.buf),
+		Data: string(p.buf[2 : len(p.buf)-termLen]),
+	}
+	p.reset()
+
+	return tok
+}
@@ -0,0 +1,193 @@
+package ansi
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []Token
+		// dropped marks inputs whose tokens do not reproduce every byte.
+		dropped bool
+	}{
+		{
+			name: "text only",
+			in:   "hello\r\n",
+			want: []Token{{Kind: KindText, Raw: "hello\r\n"}},
+		},
+		{
+			name: "SGR around text",
+			in:   "\x1b[1;31mred\x1b[0m",
+			want: []Token{
+				{Kind: KindCSI, Raw: "\x1b[1;31m", Params: "1;31", Final: 'm'},
+				{Kind: KindText, Raw: "red"},
+				{Kind: KindCSI, Raw: "\x1b[0m", Params: "0", Final: 'm'},
+			},
+		},
+		{
+			name: "CSI private mode",
+			in:   "\x1b[?25l",
+			want: []Token{{Kind: KindCSI, Raw: "\x1b[?25l", Params: "?25", Final: 'l'}},
+		},
+		{
+			name: "CSI intermediate byte",
+			in:   "\x1b[2 q",
+			want: []Token{{Kind: KindCSI, Raw: "\x1b[2 q", Params: "2", Intermediate: " ", Final: 'q'}},
+		},
+		{
+			name: "OSC with BEL",
+			in:   "\x1b]0;title\a",
+			want: []Token{{Kind: KindOSC, Raw: "\x1b]0;title\a", Data: "0;title"}},
+		},
+		{
+			name: "OSC hyperlink with ST",
+			in:   "\x1b]8;;https://musher.dev\x1b\\link",
+			want: []Token{
+				{Kind: KindOSC, Raw: "\x1b]8;;https://musher.dev\x1b\\", Data: "8;;https://musher.dev"},
+				{Kind: KindText, Raw: "link"},
+			},
+		},
+		{
+			name: "DCS tmux passthrough keeps doubled ESC in data",
+			in:   "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\",
+			want: []Token{{Kind: KindDCS, Raw: "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\", Data: "tmux;\x1b\x1b]52;c;aGk=\a"}},
+		},
+		{
+			name: "BEL does not end DCS",
+			in:   "\x1bPq\a\x1b\\",
+			want: []Token{{Kind: KindDCS, Raw: "\x1bPq\a\x1b\\", Data: "q\a"}},
+		},
+		{
+			name: "APC",
+			in:   "\x1b_Gf=100\x1b\\",
+			want: []Token{{Kind: KindAPC, Raw: "\x1b_Gf=100\x1b\\", Data: "Gf=100"}},
+		},
+		{
+			name: "two-byte and nF escapes",
+			in:   "\x1b7\x1b(B",
+			want: []Token{
+				{Kind: KindEscape, Raw: "\x1b7", Final: '7'},
+				{Kind: KindEscape, Raw: "\x1b(B", Intermediate: "(", Final: 'B'},
+			},
+		},
+		{
+			name: "ESC abandons an unfinished CSI",
+			in:   "\x1b[31\x1b[0mok",
+			want: []Token{
+				{Kind: KindCSI, Raw: "\x1b[0m", Params: "0", Final: 'm'},
+				{Kind: KindText, Raw: "ok"},
+			},
+			dropped: true,
+		},
+		{
+			name: "ESC before a control byte is text",
+			in:   "a\x1b\nb",
+			want: []Token{
+				{Kind: KindText, Raw: "a"},
+				{Kind: KindText, Raw: "\x1b"},
+				{Kind: KindText, Raw: "\nb"},
+			},
+		},
+		{
+			name: "doubled ESC starts a new sequence",
+			in:   "\x1b\x1b[1mx",
+			want: []Token{
+				{Kind: KindText, Raw: "\x1b"},
+				{Kind: KindCSI, Raw: "\x1b[1m", Params: "1", Final: 'm'},
+				{Kind: KindText, Raw: "x"},
+			},
+		},
+		{
+			name: "incomplete trailing sequence is text",
+			in:   "ok\x1b]0;ti",
+			want: []Token{
+				{Kind: KindText, Raw: "ok"},
+				{Kind: KindText, Raw: "\x1b]0;ti"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Tokenize(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Tokenize(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+
+			var raw strings.Builder
+			for _, tok := range got {
+				raw.WriteString(tok.Raw)
+			}
+
+			if !tt.dropped && raw.String() != tt.in {
+				t.Fatalf("Tokenize(%q) raw bytes = %q, want the input", tt.in, raw.String())
+			}
+		})
+	}
+}
+
+func TestParserFeedSplitsAcrossChunks(t *testing.T) {
+	in := "a\x1b[38;5;149mb\x1b]0;t\x1b\\c\x1bPq\x1b\\d"
+	want := Tokenize(in)
+
+	// Feeding one byte at a time must produce the same sequences and text.
+	var (
+		parser Parser
+		got    []Token
+	)
+
+	emit := func(tok Token) {
+		if n := len(got); n > 0 && tok.Kind == KindText && got[n-1].Kind == KindText {
+			got[n-1].Raw += tok.Raw
+			return
+		}
+
+		got = append(got, tok)
+	}
+
+	for i := range len(in) {
+		parser.Feed([]byte{in[i]}, emit)
+	}
+
+	parser.Flush(emit)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("byte-at-a-time tokens = %+v, want %+v", got, want)
+	}
+}
+
+func TestStripper(t *testing.T) {
+	var s Stripper
+
+	chunks := []string{"Esc to \x1b[", "1mcan", "cel\x1b", "[0m ", "done"}
+
+	var out strings.Builder
+	for _, chunk := range chunks {
+		out.Write(s.Strip([]byte(chunk)))
+	}
+
+	if got, want := out.String(), "Esc to cancel done"; got != want {
+		t.Fatalf("Stripper output = %q, want %q", got, want)
+	}
+}
+
+func TestSequences(t *testing.T) {
+	tests := map[string]string{
+		Move(3, 7):         "\x1b[3;7H",
+		ScrollRegion(2, 9): "\x1b[2;9r",
+		LRMargins(1, 40):   "\x1b[1;40s",
+		SGR("48;5;236"):    "\x1b[48;5;236m",
+		KeyBacktab:         "\x1b[Z",
+		SaveCursor:         "\x1b7",
+	}
+
+	for got, want := range tests {
+		if got != want {
+			t.Fatalf("sequence = %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package ansi
+
+import "fmt"
+
+const (
+	escByte = 0x1b
+	belByte = 0x07
+)
+
+// Sequence introducers and terminators.
+const (
+	ESC = "\x1b"
+	BEL = "\a"
+	CSI = ESC + "[" // Control Sequence Introducer
+	OSC = ESC + "]" // Operating System Command
+	DCS = ESC + "P" // Device Control String
+	ST  = ESC + "\\"
+)
+
+// Terminal control sequences written by the harness.
+const (
+	ClearScreen   = CSI + "2J"
+	MoveTo        = CSI + "%d;%dH" // row;col (1-indexed)
+	SaveCursor    = ESC + "7"      // DECSC — safe even when DECLRMM (mode 69) is active
+	RestoreCursor = ESC + "8"      // DECRC
+	SetScrollRgn  = CSI + "%d;%dr" // top;bottom
+	SetLRMargins  = CSI + "%d;%ds" // left;right (DECSLRM)
+	ResetScroll   = CSI + "r"
+	Reset         = CSI + "0m"
+	ShowCursor    = CSI + "?25h"
+	HideCursor    = CSI + "?25l"
+	ClearLine     = CSI + "2K"
+	ClearToEOL    = CSI + "K"
+	EnableLRMode  = CSI + "?69h"
+	DisableLRMode = CSI + "?69l"
+)
+
+// Key sequences sent to a program by a terminal in normal cursor key mode.
+const (
+	KeyUp       = CSI + "A"
+	KeyDown     = CSI + "B"
+	KeyRight    = CSI + "C"
+	KeyLeft     = CSI + "D"
+	KeyHome     = CSI + "H"
+	KeyEnd      = CSI + "F"
+	KeyInsert   = CSI + "2~"
+	KeyDelete   = CSI + "3~"
+	KeyPageUp   = CSI + "5~"
+	KeyPageDown = CSI + "6~"
+	KeyBacktab  = CSI + "Z"
+)
+
+// Move returns an ANSI cursor movement sequence.
+func Move(row, col int) string {
+	return fmt.Sprintf(MoveTo, row, col)
+}
+
+// ScrollRegion returns an ANSI scroll region sequence.
+func ScrollRegion(top, bottom int) string {
+	return fmt.Sprintf(SetScrollRgn, top, bottom)
+}
+
+// LRMargins returns an ANSI DECSLRM left/right margin sequence.
+func LRMargins(left, right int) string {
+	return fmt.Sprintf(SetLRMargins, left, right)
+}
+
+// SGR returns a Select Graphic Rendition sequence, e.g. SGR("38;5;149").
+func SGR(params string) string {
+	return CSI + params + "m"
+}
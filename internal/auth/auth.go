@@ -4,13 +4,26 @@
 //  1. Environment variable: MUSHER_API_KEY
 //  2. OS Keyring (service name derived from API URL: musher/{host})
 //  3. Data file fallback: <data root>/credentials/<hostID>/api-key
+//
+// Under a named profile (MUSH_PROFILE), the keyring service and the host
+// directory are suffixed with @<profile>, so each profile keeps its own
+// credentials for a host.
+//
+// The keyring and the file are credential backends; StoreAPIKeyIn picks one
+// and MigrateToKeyring moves a key out of the file. An OAuth token from the
+// device authorization flow is kept beside them by StoreToken and read after
+// an API key.
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/musher-dev/mush/internal/paths"
@@ -23,8 +36,20 @@ import (
 // a timeout indicates the D-Bus session bus is unavailable (containers, WSL, headless).
 const keyringTimeout = 3 * time.Second
 
+// keyringUnavailable records whether the last keyring read failed for a
+// reason other than a missing entry, so a migration does not wait on a
+// keyring that just timed out.
+var keyringUnavailable atomic.Bool
+
 // keyringGet wraps keyring.Get with a timeout to prevent hanging on unavailable D-Bus.
 func keyringGet(service, user string) (string, error) {
+	key, err := keyringGetWithTimeout(service, user)
+	keyringUnavailable.Store(err != nil && !errors.Is(err, keyring.ErrNotFound))
+
+	return key, err
+}
+
+func keyringGetWithTimeout(service, user string) (string, error) {
 	type result struct {
 		key string
 		err error
@@ -80,6 +105,10 @@ func keyringDelete(service, user string) error {
 const (
 	// keyringUser is the user/account name used in OS keyring storage.
 	keyringUser = "api-key"
+	// runnerConfigKeyUser is the keyring account holding the runner config cache key.
+	runnerConfigKeyUser = "runner-config-key"
+	// runnerConfigKeySize is the AES-256 key length in bytes.
+	runnerConfigKeySize = 32
 	// envVarName is the environment variable for the API key.
 	envVarName = "MUSHER_API_KEY"
 )
@@ -103,53 +132,151 @@ func GetCredentials(apiURL string) (source CredentialSource, apiKey string) {
 		return SourceEnv, key
 	}
 
-	// Priority 2: OS Keyring (host-scoped)
-	service := paths.KeyringServiceFromURL(apiURL)
-	if key, err := keyringGet(service, keyringUser); err == nil && key != "" {
-		return SourceKeyring, key
+	// Priority 2 and 3: the keyring, then the file (both host-scoped)
+	for _, backend := range credentialBackends {
+		if key, err := backend.get(apiURL); err == nil && key != "" {
+			return backend.source(), key
+		}
 	}
 
-	// Priority 3: Data file fallback (host-scoped)
-	if key := readCredentialsFile(apiURL); key != "" {
-		return SourceFile, key
+	// An OAuth token's access token authenticates like an API key.
+	if tok, source := LoadToken(apiURL); tok != nil {
+		return source, tok.AccessToken
 	}
 
 	return SourceNone, ""
 }
 
 // StoreAPIKey stores the API key for the given API URL in the OS keyring.
-// Falls back to file storage if keyring is unavailable.
+// Falls back to file storage if keyring is unavailable. The cached identity
+// for the host is cleared.
 func StoreAPIKey(apiURL, apiKey string) error {
-	// Try keyring first
-	service := paths.KeyringServiceFromURL(apiURL)
+	_, err := StoreAPIKeyIn(apiURL, apiKey, StoreAuto)
+
+	return err
+}
+
+// StoreAPIKeyIn stores the API key for the given API URL in store and
+// returns where it was stored. StoreAuto tries the keyring and falls back to
+// the file. A copy left in the other backend is removed, so the key is
+// read from where it was stored, and so is an OAuth token. The cached
+// identity for the host is cleared.
+func StoreAPIKeyIn(apiURL, apiKey string, store Store) (CredentialSource, error) {
+	_ = ClearIdentityCache(apiURL)
+	_ = DeleteToken(apiURL)
+
+	switch store {
+	case StoreKeyring:
+		if err := keyringCredentials.set(apiURL, apiKey); err != nil {
+			return SourceNone, fmt.Errorf("store API key in keyring: %w", err)
+		}
+
+		_ = fileCredentials.delete(apiURL)
+
+		return SourceKeyring, nil
+	case StoreFile:
+		if err := fileCredentials.set(apiURL, apiKey); err != nil {
+			return SourceNone, err
+		}
+
+		_ = keyringCredentials.delete(apiURL)
+
+		return SourceFile, nil
+	default:
+		if err := keyringCredentials.set(apiURL, apiKey); err == nil {
+			_ = fileCredentials.delete(apiURL)
+
+			return SourceKeyring, nil
+		}
+
+		if err := fileCredentials.set(apiURL, apiKey); err != nil {
+			return SourceNone, err
+		}
+
+		return SourceFile, nil
+	}
+}
 
-	err := keyringSet(service, keyringUser, apiKey)
-	if err == nil {
-		return nil
+// MigrateToKeyring moves an API key kept in the credentials file for the
+// given API URL into the OS keyring and removes the file. It reports
+// whether a key was moved; with no file, or a keyring that was just found
+// unavailable, it does nothing.
+func MigrateToKeyring(apiURL string) (bool, error) {
+	apiKey, err := fileCredentials.get(apiURL)
+	if err != nil || apiKey == "" || keyringUnavailable.Load() {
+		return false, nil
 	}
 
-	// Fallback to file storage
-	return writeCredentialsFile(apiURL, apiKey)
+	if err := keyringCredentials.set(apiURL, apiKey); err != nil {
+		return false, fmt.Errorf("store API key in keyring: %w", err)
+	}
+
+	if err := fileCredentials.delete(apiURL); err != nil {
+		return true, err
+	}
+
+	return true, nil
 }
 
-// DeleteAPIKey removes the stored API key for the given API URL.
+// DeleteAPIKey removes the stored API key or OAuth token and cached identity
+// for the given API URL.
 func DeleteAPIKey(apiURL string) error {
-	service := paths.KeyringServiceFromURL(apiURL)
+	_ = ClearIdentityCache(apiURL)
 
-	// Try to delete from keyring
-	keyringErr := keyringDelete(service, keyringUser)
+	deleted := DeleteToken(apiURL)
 
-	// Also try to delete from file
-	fileErr := deleteCredentialsFile(apiURL)
+	for _, backend := range credentialBackends {
+		if err := backend.delete(apiURL); err == nil {
+			deleted = true
+		}
+	}
 
-	// Return error only if both failed and nothing was deleted
-	if keyringErr != nil && fileErr != nil {
+	// Return error only if nothing was deleted
+	if !deleted {
 		return fmt.Errorf("no stored credentials found")
 	}
 
 	return nil
 }
 
+// RunnerConfigCacheKey returns the symmetric key used to encrypt cached runner
+// config credentials for the given API URL and the active profile, creating it
+// on first use. The key lives only in the OS keyring; an error means secrets
+// must not be cached.
+func RunnerConfigCacheKey(apiURL string) ([]byte, error) {
+	service := credentialService(apiURL)
+
+	if encoded, err := keyringGet(service, runnerConfigKeyUser); err == nil && encoded != "" {
+		key, decodeErr := base64.StdEncoding.DecodeString(encoded)
+		if decodeErr == nil && len(key) == runnerConfigKeySize {
+			return key, nil
+		}
+	}
+
+	key := make([]byte, runnerConfigKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate runner config cache key: %w", err)
+	}
+
+	if err := keyringSet(service, runnerConfigKeyUser, base64.StdEncoding.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("store runner config cache key: %w", err)
+	}
+
+	return key, nil
+}
+
+// DeleteRunnerConfigCacheKey removes the runner config cache key for the
+// given API URL and the active profile. A missing key is not an error.
+func DeleteRunnerConfigCacheKey(apiURL string) error {
+	service := credentialService(apiURL)
+
+	if err := keyringDelete(service, runnerConfigKeyUser); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("delete runner config cache key: %w", err)
+	}
+
+	return nil
+}
+
 // credentialFilePath returns the host-scoped credential file path for the given API URL.
 func credentialFilePath(apiURL string) string {
 	hostID := paths.HostIDFromURL(apiURL)
@@ -17,8 +17,9 @@ func clearAuthEnv(t *testing.T) {
 
 	for _, env := range []string{
 		"MUSHER_API_KEY",
-		"MUSHER_HOME", "MUSHER_DATA_HOME",
-		"XDG_DATA_HOME", "XDG_CONFIG_HOME",
+		"MUSHER_HOME", "MUSHER_DATA_HOME", "MUSHER_CACHE_HOME",
+		"XDG_DATA_HOME", "XDG_CONFIG_HOME", "XDG_CACHE_HOME",
+		paths.ProfileEnv,
 	} {
 		t.Setenv(env, "")
 	}
@@ -305,3 +306,95 @@ func containsPath(path, expectedSuffix string) bool {
 	return len(path) >= len(expectedSuffix) &&
 		path[len(path)-len(expectedSuffix):] == expectedSuffix
 }
+
+func TestRunnerConfigCacheKey_StableAcrossCalls(t *testing.T) {
+	keyring.MockInit()
+
+	first, err := RunnerConfigCacheKey(testAPIURL)
+	if err != nil {
+		t.Fatalf("RunnerConfigCacheKey() error = %v", err)
+	}
+
+	if len(first) != runnerConfigKeySize {
+		t.Fatalf("key length = %d, want %d", len(first), runnerConfigKeySize)
+	}
+
+	second, err := RunnerConfigCacheKey(testAPIURL)
+	if err != nil {
+		t.Fatalf("RunnerConfigCacheKey() second call error = %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Error("RunnerConfigCacheKey() returned a different key on second call")
+	}
+}
+
+func TestRunnerConfigCacheKey_KeyringUnavailable(t *testing.T) {
+	keyring.MockInitWithError(fmt.Errorf("mock keyring failure"))
+
+	if _, err := RunnerConfigCacheKey(testAPIURL); err == nil {
+		t.Fatal("RunnerConfigCacheKey() should fail when keyring is unavailable")
+	}
+}
+
+func TestRunnerConfigCacheKey_ProfileScoped(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	defaultKey, err := RunnerConfigCacheKey(testAPIURL)
+	if err != nil {
+		t.Fatalf("RunnerConfigCacheKey(default) error = %v", err)
+	}
+
+	t.Setenv(paths.ProfileEnv, "staging")
+
+	stagingKey, err := RunnerConfigCacheKey(testAPIURL)
+	if err != nil {
+		t.Fatalf("RunnerConfigCacheKey(staging) error = %v", err)
+	}
+
+	if string(defaultKey) == string(stagingKey) {
+		t.Fatal("RunnerConfigCacheKey() returned the default profile's key for staging")
+	}
+}
+
+func TestCredentials_ProfileScoped(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	for _, store := range []Store{StoreKeyring, StoreFile} {
+		t.Setenv(paths.ProfileEnv, "")
+
+		if _, err := StoreAPIKeyIn(testAPIURL, "default-key", store); err != nil {
+			t.Fatalf("StoreAPIKeyIn(default, %s) error = %v", store, err)
+		}
+
+		t.Setenv(paths.ProfileEnv, "staging")
+
+		if _, key := GetCredentials(testAPIURL); key != "" {
+			t.Fatalf("GetCredentials(staging, %s) = %q before login, want none", store, key)
+		}
+
+		if _, err := StoreAPIKeyIn(testAPIURL, "staging-key", store); err != nil {
+			t.Fatalf("StoreAPIKeyIn(staging, %s) error = %v", store, err)
+		}
+
+		if _, key := GetCredentials(testAPIURL); key != "staging-key" {
+			t.Fatalf("GetCredentials(staging, %s) = %q, want staging-key", store, key)
+		}
+
+		if err := DeleteAPIKey(testAPIURL); err != nil {
+			t.Fatalf("DeleteAPIKey(staging, %s) error = %v", store, err)
+		}
+
+		t.Setenv(paths.ProfileEnv, paths.DefaultProfile)
+
+		if _, key := GetCredentials(testAPIURL); key != "default-key" {
+			t.Fatalf("GetCredentials(default, %s) = %q after the staging logout, want default-key", store, key)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// IdentityCacheTTL is how long a cached runner identity is trusted before
+// the key is validated against the API again.
+const IdentityCacheTTL = 15 * time.Minute
+
+// identityCacheEntry is the on-disk form of a cached identity.
+type identityCacheEntry struct {
+	Key      string           `json:"key"`
+	SavedAt  time.Time        `json:"savedAt"`
+	Identity *client.Identity `json:"identity"`
+}
+
+// IdentityCache is a client.IdentityCache holding one identity per API host
+// and profile.
+// Entries are keyed by a hash of the API URL and key, so changing the key
+// invalidates them; StoreAPIKey and DeleteAPIKey also clear the host's entry.
+type IdentityCache struct {
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+
+	// Refresh ignores cached entries while still recording fresh ones.
+	Refresh bool
+}
+
+var _ client.IdentityCache = (*IdentityCache)(nil)
+
+// NewIdentityCache returns the identity cache for apiURL.
+func NewIdentityCache(apiURL string) *IdentityCache {
+	return &IdentityCache{path: identityCachePath(apiURL), ttl: IdentityCacheTTL, now: time.Now}
+}
+
+// LoadIdentity implements client.IdentityCache.
+func (c *IdentityCache) LoadIdentity(key string) (*client.Identity, bool) {
+	if c.Refresh || c.path == "" {
+		return nil, false
+	}
+
+	data, exists, err := safeio.ReadFileIfExists(c.path)
+	if err != nil || !exists {
+		return nil, false
+	}
+
+	var entry identityCacheEntry
+	if json.Unmarshal(data, &entry) != nil || entry.Key != key || entry.Identity == nil {
+		return nil, false
+	}
+
+	if age := c.now().Sub(entry.SavedAt); age < 0 || age > c.ttl {
+		return nil, false
+	}
+
+	return entry.Identity, true
+}
+
+// StoreIdentity implements client.IdentityCache.
+func (c *IdentityCache) StoreIdentity(key string, identity *client.Identity) error {
+	if c.path == "" {
+		return fmt.Errorf("could not determine cache directory")
+	}
+
+	data, err := json.Marshal(identityCacheEntry{Key: key, SavedAt: c.now().UTC(), Identity: identity})
+	if err != nil {
+		return fmt.Errorf("marshal identity cache: %w", err)
+	}
+
+	if err := safeio.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("create identity cache directory: %w", err)
+	}
+
+	if err := safeio.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("write identity cache: %w", err)
+	}
+
+	return nil
+}
+
+// ClearIdentityCache removes the cached identity for apiURL. A missing entry
+// is not an error.
+func ClearIdentityCache(apiURL string) error {
+	path := identityCachePath(apiURL)
+	if path == "" {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove identity cache: %w", err)
+	}
+
+	return nil
+}
+
+// identityCachePath returns the identity cache file for apiURL. It is
+// scoped to the active profile as well as the host, so two profiles on one
+// host never read each other's identity.
+func identityCachePath(apiURL string) string {
+	path, err := paths.IdentityCacheFile(paths.HostIDFromURL(apiURL))
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Clean(path)
+}
@@ -0,0 +1,85 @@
+package auth
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/zalando/go-keyring"
+)
+
+func TestIdentityCache(t *testing.T) {
+	clearAuthEnv(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	cache := NewIdentityCache(testAPIURL)
+	cache.now = func() time.Time { return now }
+
+	if _, ok := cache.LoadIdentity("key-a"); ok {
+		t.Fatal("LoadIdentity() on empty cache = ok, want miss")
+	}
+
+	if err := cache.StoreIdentity("key-a", &client.Identity{OrganizationName: "Acme"}); err != nil {
+		t.Fatalf("StoreIdentity() error = %v", err)
+	}
+
+	if identity, ok := cache.LoadIdentity("key-a"); !ok || identity.OrganizationName != "Acme" {
+		t.Fatalf("LoadIdentity() = %+v, %v; want Acme", identity, ok)
+	}
+
+	if _, ok := cache.LoadIdentity("key-b"); ok {
+		t.Fatal("LoadIdentity() with another key = ok, want miss")
+	}
+
+	cache.Refresh = true
+	if _, ok := cache.LoadIdentity("key-a"); ok {
+		t.Fatal("LoadIdentity() with Refresh = ok, want miss")
+	}
+
+	cache.Refresh = false
+	now = now.Add(IdentityCacheTTL + time.Second)
+
+	if _, ok := cache.LoadIdentity("key-a"); ok {
+		t.Fatal("LoadIdentity() after TTL = ok, want miss")
+	}
+}
+
+func TestIdentityCache_ProfileScoped(t *testing.T) {
+	clearAuthEnv(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	if err := NewIdentityCache(testAPIURL).StoreIdentity("key-a", &client.Identity{OrganizationName: "Acme"}); err != nil {
+		t.Fatalf("StoreIdentity() error = %v", err)
+	}
+
+	t.Setenv(paths.ProfileEnv, "staging")
+
+	if identity, ok := NewIdentityCache(testAPIURL).LoadIdentity("key-a"); ok {
+		t.Fatalf("LoadIdentity() under another profile = %+v, want miss", identity)
+	}
+}
+
+func TestStoreAPIKey_ClearsIdentityCache(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInitWithError(fmt.Errorf("mock keyring failure"))
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CACHE_HOME", tmpDir)
+
+	cache := NewIdentityCache(testAPIURL)
+	if err := cache.StoreIdentity("key-a", &client.Identity{OrganizationName: "Acme"}); err != nil {
+		t.Fatalf("StoreIdentity() error = %v", err)
+	}
+
+	if err := StoreAPIKey(testAPIURL, "new-key"); err != nil {
+		t.Fatalf("StoreAPIKey() error = %v", err)
+	}
+
+	if _, ok := cache.LoadIdentity("key-a"); ok {
+		t.Fatal("identity still cached after StoreAPIKey")
+	}
+}
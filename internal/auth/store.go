@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/paths"
+)
+
+// Store selects where StoreAPIKeyIn keeps an API key.
+type Store string
+
+// Store constants name the values `mush auth login --store` and auth.store
+// accept.
+const (
+	// StoreAuto uses the OS keyring and falls back to the file when the
+	// keyring is unavailable.
+	StoreAuto Store = "auto"
+	// StoreKeyring uses the OS keyring only: macOS Keychain, Windows
+	// Credential Manager, or the Linux Secret Service.
+	StoreKeyring Store = "keychain"
+	// StoreFile uses the plaintext credentials file only.
+	StoreFile Store = "file"
+)
+
+// ParseStore returns the Store named by name, ignoring case. An empty name
+// is StoreAuto, and "keyring" is accepted for StoreKeyring.
+func ParseStore(name string) (Store, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", string(StoreAuto):
+		return StoreAuto, nil
+	case string(StoreKeyring), "keyring":
+		return StoreKeyring, nil
+	case string(StoreFile):
+		return StoreFile, nil
+	default:
+		return "", fmt.Errorf("unknown credential store %q (want auto, keychain, or file)", name)
+	}
+}
+
+// credentialBackend keeps API keys, one per API host.
+type credentialBackend interface {
+	source() CredentialSource
+	get(apiURL string) (string, error)
+	set(apiURL, apiKey string) error
+	delete(apiURL string) error
+}
+
+var (
+	keyringCredentials credentialBackend = keyringBackend{}
+	fileCredentials    credentialBackend = fileBackend{}
+)
+
+// credentialBackends are the backends GetCredentials reads, in order.
+var credentialBackends = []credentialBackend{keyringCredentials, fileCredentials}
+
+// credentialService returns the keyring service holding credentials for
+// apiURL: the host's service, scoped to the active profile like the
+// credentials file.
+func credentialService(apiURL string) string {
+	return paths.ProfileScoped(paths.KeyringServiceFromURL(apiURL))
+}
+
+// keyringBackend keeps API keys in the OS keyring under the host's service.
+type keyringBackend struct{}
+
+func (keyringBackend) source() CredentialSource { return SourceKeyring }
+
+func (keyringBackend) get(apiURL string) (string, error) {
+	return keyringGet(credentialService(apiURL), keyringUser)
+}
+
+func (keyringBackend) set(apiURL, apiKey string) error {
+	return keyringSet(credentialService(apiURL), keyringUser, apiKey)
+}
+
+func (keyringBackend) delete(apiURL string) error {
+	return keyringDelete(credentialService(apiURL), keyringUser)
+}
+
+// fileBackend keeps API keys in the host-scoped credentials file.
+type fileBackend struct{}
+
+func (fileBackend) source() CredentialSource { return SourceFile }
+
+func (fileBackend) get(apiURL string) (string, error) {
+	return readCredentialsFile(apiURL), nil
+}
+
+func (fileBackend) set(apiURL, apiKey string) error {
+	return writeCredentialsFile(apiURL, apiKey)
+}
+
+func (fileBackend) delete(apiURL string) error {
+	return deleteCredentialsFile(apiURL)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/musher-dev/mush/internal/paths"
+)
+
+func TestParseStore(t *testing.T) {
+	tests := map[string]Store{
+		"":         StoreAuto,
+		"auto":     StoreAuto,
+		"Keychain": StoreKeyring,
+		"keyring":  StoreKeyring,
+		" file ":   StoreFile,
+	}
+
+	for name, want := range tests {
+		if got, err := ParseStore(name); err != nil || got != want {
+			t.Errorf("ParseStore(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+
+	if _, err := ParseStore("vault"); err == nil {
+		t.Error("ParseStore(vault) error = nil, want an error")
+	}
+}
+
+func TestStoreAPIKeyIn_RemovesTheOtherCopy(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	source, err := StoreAPIKeyIn(testAPIURL, "file-key", StoreFile)
+	if err != nil || source != SourceFile {
+		t.Fatalf("StoreAPIKeyIn(file) = %q, %v; want the credentials file", source, err)
+	}
+
+	if source, key := GetCredentials(testAPIURL); source != SourceFile || key != "file-key" {
+		t.Fatalf("GetCredentials() = %q, %q; want file-key from the file", source, key)
+	}
+
+	source, err = StoreAPIKeyIn(testAPIURL, "keyring-key", StoreKeyring)
+	if err != nil || source != SourceKeyring {
+		t.Fatalf("StoreAPIKeyIn(keychain) = %q, %v; want the keyring", source, err)
+	}
+
+	if _, err := os.Stat(credentialFilePath(testAPIURL)); !os.IsNotExist(err) {
+		t.Fatalf("credentials file still exists after storing in the keyring, stat err = %v", err)
+	}
+
+	if _, err := StoreAPIKeyIn(testAPIURL, "file-key", StoreFile); err != nil {
+		t.Fatalf("StoreAPIKeyIn(file) error = %v", err)
+	}
+
+	if _, err := keyring.Get(paths.KeyringServiceFromURL(testAPIURL), keyringUser); err == nil {
+		t.Fatal("keyring entry still exists after storing in the file")
+	}
+}
+
+func TestStoreAPIKeyIn_KeyringRequired(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInitWithError(fmt.Errorf("mock keyring failure"))
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	if _, err := StoreAPIKeyIn(testAPIURL, "my-key", StoreKeyring); err == nil {
+		t.Fatal("StoreAPIKeyIn(keychain) error = nil, want the keyring failure")
+	}
+
+	if got := readCredentialsFile(testAPIURL); got != "" {
+		t.Fatalf("credentials file = %q, want no fallback", got)
+	}
+}
+
+func TestMigrateToKeyring(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	if err := writeCredentialsFile(testAPIURL, "old-key"); err != nil {
+		t.Fatalf("writeCredentialsFile() error = %v", err)
+	}
+
+	if source, _ := GetCredentials(testAPIURL); source != SourceFile {
+		t.Fatalf("GetCredentials() source = %q before migrating, want the file", source)
+	}
+
+	moved, err := MigrateToKeyring(testAPIURL)
+	if err != nil || !moved {
+		t.Fatalf("MigrateToKeyring() = %v, %v; want the key moved", moved, err)
+	}
+
+	if source, key := GetCredentials(testAPIURL); source != SourceKeyring || key != "old-key" {
+		t.Fatalf("GetCredentials() = %q, %q; want old-key from the keyring", source, key)
+	}
+
+	if _, err := os.Stat(credentialFilePath(testAPIURL)); !os.IsNotExist(err) {
+		t.Fatalf("credentials file still exists after migrating, stat err = %v", err)
+	}
+
+	if moved, err := MigrateToKeyring(testAPIURL); err != nil || moved {
+		t.Fatalf("MigrateToKeyring() with no file = %v, %v; want nothing moved", moved, err)
+	}
+}
+
+func TestMigrateToKeyring_KeyringUnavailable(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInitWithError(fmt.Errorf("mock keyring failure"))
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	if err := writeCredentialsFile(testAPIURL, "old-key"); err != nil {
+		t.Fatalf("writeCredentialsFile() error = %v", err)
+	}
+
+	GetCredentials(testAPIURL)
+
+	if moved, err := MigrateToKeyring(testAPIURL); err != nil || moved {
+		t.Fatalf("MigrateToKeyring() = %v, %v; want the unavailable keyring skipped", moved, err)
+	}
+
+	if got := readCredentialsFile(testAPIURL); got != "old-key" {
+		t.Fatalf("credentials file = %q, want it kept", got)
+	}
+}
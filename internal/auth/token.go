@@ -0,0 +1,176 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+const (
+	// tokenKeyringUser is the keyring account holding the OAuth token from
+	// `mush auth login --device`.
+	tokenKeyringUser = "oauth-token"
+	// tokenFileName is the OAuth token's file beside the API key file.
+	tokenFileName = "oauth-token"
+)
+
+// StoreToken stores an OAuth token for the given API URL in store and
+// returns where it was stored, like StoreAPIKeyIn. A stored API key and a
+// token copy left in the other backend are removed, so the host has one
+// credential. The cached identity for the host is cleared.
+func StoreToken(apiURL string, tok *client.Token, store Store) (CredentialSource, error) {
+	_ = ClearIdentityCache(apiURL)
+
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return SourceNone, fmt.Errorf("encode OAuth token: %w", err)
+	}
+
+	var source CredentialSource
+
+	switch store {
+	case StoreKeyring:
+		if err := setTokenKeyring(apiURL, data); err != nil {
+			return SourceNone, fmt.Errorf("store OAuth token in keyring: %w", err)
+		}
+
+		source = SourceKeyring
+	case StoreFile:
+		if err := writeTokenFile(apiURL, data); err != nil {
+			return SourceNone, err
+		}
+
+		source = SourceFile
+	default:
+		source = SourceKeyring
+
+		if err := setTokenKeyring(apiURL, data); err != nil {
+			if err := writeTokenFile(apiURL, data); err != nil {
+				return SourceNone, err
+			}
+
+			source = SourceFile
+		}
+	}
+
+	if source == SourceKeyring {
+		_ = deleteTokenFile(apiURL)
+	} else {
+		_ = keyringDelete(credentialService(apiURL), tokenKeyringUser)
+	}
+
+	for _, backend := range credentialBackends {
+		_ = backend.delete(apiURL)
+	}
+
+	return source, nil
+}
+
+// LoadToken returns the OAuth token stored for the given API URL and where
+// it was found, or nil when there is none.
+func LoadToken(apiURL string) (*client.Token, CredentialSource) {
+	if data, err := keyringGet(credentialService(apiURL), tokenKeyringUser); err == nil && data != "" {
+		if tok := decodeToken([]byte(data)); tok != nil {
+			return tok, SourceKeyring
+		}
+	}
+
+	path := tokenFilePath(apiURL)
+	if path == "" {
+		return nil, SourceNone
+	}
+
+	data, err := safeio.ReadFile(path)
+	if err != nil {
+		return nil, SourceNone
+	}
+
+	if tok := decodeToken(data); tok != nil {
+		return tok, SourceFile
+	}
+
+	return nil, SourceNone
+}
+
+// DeleteToken removes the OAuth token stored for the given API URL. It
+// reports whether one was removed.
+func DeleteToken(apiURL string) bool {
+	deleted := false
+
+	if err := keyringDelete(credentialService(apiURL), tokenKeyringUser); err == nil {
+		deleted = true
+	}
+
+	if err := deleteTokenFile(apiURL); err == nil {
+		deleted = true
+	}
+
+	return deleted
+}
+
+// decodeToken parses a stored token, or returns nil when it holds no access
+// token.
+func decodeToken(data []byte) *client.Token {
+	var tok client.Token
+	if err := json.Unmarshal(data, &tok); err != nil || tok.AccessToken == "" {
+		return nil
+	}
+
+	return &tok
+}
+
+func setTokenKeyring(apiURL string, data []byte) error {
+	return keyringSet(credentialService(apiURL), tokenKeyringUser, string(data))
+}
+
+// tokenFilePath returns the host-scoped OAuth token file path for the given
+// API URL.
+func tokenFilePath(apiURL string) string {
+	path := credentialFilePath(apiURL)
+	if path == "" {
+		return ""
+	}
+
+	return filepath.Join(filepath.Dir(path), tokenFileName)
+}
+
+// writeTokenFile writes the OAuth token to the host-scoped file fallback.
+func writeTokenFile(apiURL string, data []byte) error {
+	path := tokenFilePath(apiURL)
+	if path == "" {
+		return fmt.Errorf("could not determine data directory")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create credentials directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write OAuth token file: %w", err)
+	}
+
+	return nil
+}
+
+// deleteTokenFile removes the host-scoped OAuth token file.
+func deleteTokenFile(apiURL string) error {
+	path := tokenFilePath(apiURL)
+	if path == "" {
+		return fmt.Errorf("could not determine data directory")
+	}
+
+	if err := os.Remove(path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("OAuth token file not found")
+		}
+
+		return fmt.Errorf("remove OAuth token file: %w", err)
+	}
+
+	return nil
+}
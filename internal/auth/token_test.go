@@ -0,0 +1,82 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestStoreToken(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	if _, err := StoreAPIKeyIn(testAPIURL, "old-key", StoreFile); err != nil {
+		t.Fatalf("StoreAPIKeyIn() error = %v", err)
+	}
+
+	tok := &client.Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(time.Hour).UTC()}
+
+	source, err := StoreToken(testAPIURL, tok, StoreFile)
+	if err != nil || source != SourceFile {
+		t.Fatalf("StoreToken(file) = %q, %v; want the credentials file", source, err)
+	}
+
+	if _, err := os.Stat(credentialFilePath(testAPIURL)); !os.IsNotExist(err) {
+		t.Fatalf("API key file still exists after storing a token, stat err = %v", err)
+	}
+
+	if source, key := GetCredentials(testAPIURL); source != SourceFile || key != "access-1" {
+		t.Fatalf("GetCredentials() = %q, %q; want the access token from the file", source, key)
+	}
+
+	got, source := LoadToken(testAPIURL)
+	if got == nil || source != SourceFile || got.RefreshToken != "refresh-1" || !got.ExpiresAt.Equal(tok.ExpiresAt) {
+		t.Fatalf("LoadToken() = %+v, %q; want the stored token", got, source)
+	}
+
+	if source, err := StoreToken(testAPIURL, tok, StoreKeyring); err != nil || source != SourceKeyring {
+		t.Fatalf("StoreToken(keychain) = %q, %v; want the keyring", source, err)
+	}
+
+	if _, err := os.Stat(tokenFilePath(testAPIURL)); !os.IsNotExist(err) {
+		t.Fatalf("token file still exists after storing in the keyring, stat err = %v", err)
+	}
+
+	if _, err := StoreAPIKeyIn(testAPIURL, "new-key", StoreAuto); err != nil {
+		t.Fatalf("StoreAPIKeyIn() error = %v", err)
+	}
+
+	if tok, _ := LoadToken(testAPIURL); tok != nil {
+		t.Fatalf("LoadToken() = %+v after storing an API key, want none", tok)
+	}
+}
+
+func TestDeleteAPIKey_RemovesToken(t *testing.T) {
+	clearAuthEnv(t)
+	keyring.MockInit()
+
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_DATA_HOME", filepath.Join(tmpDir, "data"))
+
+	if _, err := StoreToken(testAPIURL, &client.Token{AccessToken: "access-1"}, StoreAuto); err != nil {
+		t.Fatalf("StoreToken() error = %v", err)
+	}
+
+	if err := DeleteAPIKey(testAPIURL); err != nil {
+		t.Fatalf("DeleteAPIKey() error = %v", err)
+	}
+
+	if source, key := GetCredentials(testAPIURL); source != SourceNone || key != "" {
+		t.Fatalf("GetCredentials() = %q, %q after logout, want none", source, key)
+	}
+}
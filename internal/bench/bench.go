@@ -0,0 +1,250 @@
+//go:build unix || windows
+
+// Package bench drives the job engine with synthetic jobs from an in-process
+// mock platform and measures its per-job cost.
+package bench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// Synthetic harness types.
+const (
+	// HarnessBash runs each job's command with bash -c.
+	HarnessBash = "bash"
+	// HarnessNoop returns immediately, isolating engine and API overhead.
+	HarnessNoop = "noop"
+)
+
+// Harnesses lists the synthetic harness types Run accepts.
+var Harnesses = []string{HarnessBash, HarnessNoop}
+
+// Options configures a benchmark run.
+type Options struct {
+	// Jobs is the number of synthetic jobs to process.
+	Jobs int
+	// Harness is one of Harnesses.
+	Harness string
+	// Command is the script each bash job runs.
+	Command string
+	// Config supplies engine settings; nil loads the user config.
+	Config *config.Config
+}
+
+// Percentiles summarizes a set of durations. Durations marshal to JSON as
+// nanoseconds.
+type Percentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// Report is the result of a benchmark run.
+type Report struct {
+	Harness   string        `json:"harness"`
+	Jobs      int           `json:"jobs"`
+	Completed int           `json:"completed"`
+	Failed    int           `json:"failed"`
+	Duration  time.Duration `json:"duration"`
+	// Throughput is completed and failed jobs per second.
+	Throughput float64 `json:"throughputPerSecond"`
+
+	// ClaimLatency is the time from the runner becoming free (registration,
+	// then each result report) until the platform handed out the next job.
+	ClaimLatency Percentiles `json:"claimLatency"`
+	// Execution is the time spent inside the executor.
+	Execution Percentiles `json:"execution"`
+	// Overhead is claim-to-report time minus execution: the engine's own
+	// per-job cost (start, enrichment, result encoding, API calls).
+	Overhead Percentiles `json:"overhead"`
+}
+
+// Run processes opts.Jobs synthetic jobs through a real engine against an
+// in-process mock platform and reports latency percentiles.
+func Run(ctx context.Context, opts *Options) (*Report, error) {
+	if opts.Jobs <= 0 {
+		return nil, fmt.Errorf("jobs must be positive, got %d", opts.Jobs)
+	}
+
+	if opts.Harness != HarnessBash && opts.Harness != HarnessNoop {
+		return nil, fmt.Errorf("unsupported bench harness %q", opts.Harness)
+	}
+
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Load()
+	}
+
+	platform := newMockPlatform(opts.Jobs, opts.Harness, opts.Command, time.Now)
+
+	srv := httptest.NewServer(platform)
+	defer srv.Close()
+
+	executor := &benchExecutor{harness: opts.Harness, durations: make(map[string]time.Duration, opts.Jobs)}
+
+	eng := engine.New(&engine.Options{
+		Client:             client.New(srv.URL, "bench-key"),
+		Config:             cfg,
+		QueueID:            "bench-queue",
+		InstanceID:         "bench",
+		Executors:          map[string]harnesstype.Executor{opts.Harness: executor},
+		SupportedHarnesses: []string{opts.Harness},
+		InitialStatus:      engine.StatusConnecting,
+	})
+
+	started := time.Now()
+
+	if err := eng.Start(ctx); err != nil {
+		return nil, fmt.Errorf("start engine: %w", err)
+	}
+
+	report := &Report{Harness: opts.Harness, Jobs: opts.Jobs}
+	waitErr := waitForJobs(ctx, eng, opts.Jobs, report)
+	report.Duration = time.Since(started)
+
+	drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+
+	if err := eng.Drain(drainCtx); err != nil && waitErr == nil {
+		waitErr = fmt.Errorf("drain engine: %w", err)
+	}
+
+	if waitErr != nil {
+		return report, waitErr
+	}
+
+	if seconds := report.Duration.Seconds(); seconds > 0 {
+		report.Throughput = float64(report.Completed+report.Failed) / seconds
+	}
+
+	claimLatency, turnaround := platform.timings()
+	executions := executor.snapshot()
+
+	execution := make([]time.Duration, 0, len(executions))
+	overhead := make([]time.Duration, 0, len(turnaround))
+
+	for jobID, total := range turnaround {
+		spent := executions[jobID]
+		execution = append(execution, spent)
+		overhead = append(overhead, max(total-spent, 0))
+	}
+
+	report.ClaimLatency = percentiles(claimLatency)
+	report.Execution = percentiles(execution)
+	report.Overhead = percentiles(overhead)
+
+	return report, nil
+}
+
+// waitForJobs waits until the engine has finished jobs jobs. Events only
+// wake the loop: the engine drops events for slow consumers, so the counts
+// come from Stats.
+func waitForJobs(ctx context.Context, eng *engine.Engine, jobs int, report *Report) error {
+	events := eng.Events()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		stats := eng.Stats()
+		report.Completed, report.Failed = stats.Completed, stats.Failed
+
+		if report.Completed+report.Failed >= jobs {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("benchmark interrupted after %d of %d jobs: %w", report.Completed+report.Failed, jobs, ctx.Err())
+		case _, ok := <-events:
+			if !ok {
+				return errors.New("engine stopped before all jobs finished")
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// percentiles returns nearest-rank percentiles of samples.
+func percentiles(samples []time.Duration) Percentiles {
+	if len(samples) == 0 {
+		return Percentiles{}
+	}
+
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p float64) time.Duration {
+		idx := int(p*float64(len(sorted))+0.999999) - 1
+		return sorted[min(max(idx, 0), len(sorted)-1)]
+	}
+
+	return Percentiles{P50: rank(0.50), P90: rank(0.90), P99: rank(0.99), Max: sorted[len(sorted)-1]}
+}
+
+// benchExecutor is the synthetic executor behind the bench harnesses.
+type benchExecutor struct {
+	harness string
+
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+func (e *benchExecutor) Setup(context.Context, *harnesstype.SetupOptions) error { return nil }
+
+func (e *benchExecutor) Execute(ctx context.Context, job *client.Job) (*harnesstype.ExecResult, error) {
+	started := time.Now()
+
+	var output harnesstype.OutputRecorder
+
+	if e.harness == HarnessBash {
+		cmd, err := executil.CommandContext(ctx, "bash", "-c", job.GetRenderedInstruction())
+		if err != nil {
+			return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error()}
+		}
+
+		cmd.Stdout = &output
+		cmd.Stderr = &output
+
+		if err := cmd.Run(); err != nil {
+			return nil, harnesstype.HandleOneShotRunError(ctx, err, &output, "bash")
+		}
+	}
+
+	elapsed := time.Since(started)
+
+	e.mu.Lock()
+	e.durations[job.ID] = elapsed
+	e.mu.Unlock()
+
+	return &harnesstype.ExecResult{Output: harnesstype.NewAgentJobOutput(output.String(), elapsed)}, nil
+}
+
+func (e *benchExecutor) Reset(context.Context) error { return nil }
+
+func (e *benchExecutor) Teardown() {}
+
+func (e *benchExecutor) snapshot() map[string]time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make(map[string]time.Duration, len(e.durations))
+	for jobID, d := range e.durations {
+		out[jobID] = d
+	}
+
+	return out
+}
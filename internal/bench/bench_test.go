@@ -0,0 +1,65 @@
+//go:build unix
+
+package bench
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestPercentiles(t *testing.T) {
+	samples := make([]time.Duration, 0, 100)
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	got := percentiles(samples)
+	want := Percentiles{P50: 50 * time.Millisecond, P90: 90 * time.Millisecond, P99: 99 * time.Millisecond, Max: 100 * time.Millisecond}
+
+	if got != want {
+		t.Fatalf("percentiles() = %+v, want %+v", got, want)
+	}
+
+	if got := percentiles(nil); got != (Percentiles{}) {
+		t.Fatalf("percentiles(nil) = %+v, want zero", got)
+	}
+
+	if got := percentiles([]time.Duration{time.Second}); got.P50 != time.Second || got.P99 != time.Second {
+		t.Fatalf("percentiles(single) = %+v", got)
+	}
+}
+
+func TestRun_NoopJobs(t *testing.T) {
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("local listener not available in this environment: %v", err)
+	}
+
+	_ = ln.Close()
+
+	report, err := Run(t.Context(), &Options{Jobs: 5, Harness: HarnessNoop})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if report.Completed != 5 || report.Failed != 0 {
+		t.Fatalf("completed=%d failed=%d, want 5/0", report.Completed, report.Failed)
+	}
+
+	if report.Throughput <= 0 || report.ClaimLatency.Max <= 0 || report.Overhead.Max <= 0 {
+		t.Fatalf("report missing measurements: %+v", report)
+	}
+}
+
+func TestRun_RejectsBadOptions(t *testing.T) {
+	if _, err := Run(t.Context(), &Options{Jobs: 0, Harness: HarnessNoop}); err == nil {
+		t.Fatal("Run() accepted zero jobs")
+	}
+
+	if _, err := Run(t.Context(), &Options{Jobs: 1, Harness: "claude"}); err == nil {
+		t.Fatal("Run() accepted a real harness")
+	}
+}
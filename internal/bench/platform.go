@@ -0,0 +1,130 @@
+//go:build unix || windows
+
+package bench
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// idleClaimDelay simulates a short long-poll when no jobs are left.
+const idleClaimDelay = 10 * time.Millisecond
+
+// mockPlatform serves the runner endpoints the engine calls, handing out a
+// fixed number of synthetic jobs and timestamping their lifecycle.
+type mockPlatform struct {
+	harnessType string
+	instruction string
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending int
+	issued  int
+	// ready is when the runner last became free for work: worker
+	// registration, then each completion or failure report.
+	ready        time.Time
+	claimLatency []time.Duration
+	claimedAt    map[string]time.Time
+	reportedAt   map[string]time.Time
+}
+
+func newMockPlatform(jobs int, harnessType, instruction string, now func() time.Time) *mockPlatform {
+	return &mockPlatform{
+		harnessType: harnessType,
+		instruction: instruction,
+		now:         now,
+		pending:     jobs,
+		claimedAt:   make(map[string]time.Time, jobs),
+		reportedAt:  make(map[string]time.Time, jobs),
+	}
+}
+
+func (p *mockPlatform) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/workers:register"):
+		p.mu.Lock()
+		p.ready = p.now()
+		p.mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"workerId":"bench-worker"}`))
+	case strings.HasSuffix(r.URL.Path, "/jobs:claim"):
+		p.claim(w, r)
+	case strings.HasSuffix(r.URL.Path, ":complete"), strings.HasSuffix(r.URL.Path, ":fail"):
+		jobID := strings.TrimPrefix(r.URL.Path, "/v1/runner/jobs/")
+		jobID = jobID[:strings.LastIndex(jobID, ":")]
+
+		p.mu.Lock()
+		p.reportedAt[jobID] = p.now()
+		p.ready = p.reportedAt[jobID]
+		p.mu.Unlock()
+
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+func (p *mockPlatform) claim(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+
+	if p.pending == 0 {
+		p.mu.Unlock()
+
+		select {
+		case <-r.Context().Done():
+		case <-time.After(idleClaimDelay):
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	p.pending--
+	p.issued++
+	jobID := fmt.Sprintf("bench-%05d", p.issued)
+	now := p.now()
+	p.claimedAt[jobID] = now
+	p.claimLatency = append(p.claimLatency, now.Sub(p.ready))
+	p.mu.Unlock()
+
+	payload, err := json.Marshal(map[string]any{
+		"job": map[string]any{
+			"id":      jobID,
+			"queueId": "bench-queue",
+			"status":  "claimed",
+		},
+		"execution": map[string]any{
+			"harnessType":         p.harnessType,
+			"renderedInstruction": p.instruction,
+		},
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = w.Write(payload)
+}
+
+// timings returns the claim latencies and the time from claim to result
+// report for each job, keyed by job ID.
+func (p *mockPlatform) timings() (claimLatency []time.Duration, turnaround map[string]time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	turnaround = make(map[string]time.Duration, len(p.reportedAt))
+
+	for jobID, reported := range p.reportedAt {
+		if claimed, ok := p.claimedAt[jobID]; ok {
+			turnaround[jobID] = reported.Sub(claimed)
+		}
+	}
+
+	return append([]time.Duration(nil), p.claimLatency...), turnaround
+}
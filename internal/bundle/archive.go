@@ -0,0 +1,467 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/musher-dev/mush/internal/buildinfo"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// Bundle archive layout (a zstd-compressed tar):
+//
+//	mush-bundle.json   — archive header (format version and bundle ref)
+//	manifest.json      — the cached resolve response
+//	assets/...         — asset files by logical path
+//	SHA256SUMS         — digest of every file above, written last
+const (
+	archiveFormatVersion = 1
+	archiveHeaderName    = "mush-bundle.json"
+	archiveManifestName  = "manifest.json"
+	archiveChecksumsName = "SHA256SUMS"
+	archiveAssetsDir     = "assets"
+
+	// maxArchiveFileBytes and maxArchiveTotalBytes bound what an import will
+	// unpack, so a hostile archive cannot fill the disk.
+	maxArchiveFileBytes  = 64 << 20
+	maxArchiveTotalBytes = 512 << 20
+)
+
+var (
+	// ErrAlreadyCached is returned by ImportArchive when the bundle version
+	// is already in the cache and replacement was not requested.
+	ErrAlreadyCached = errors.New("bundle version already cached")
+
+	// ErrArchiveIntegrity is returned when an archive's contents do not match
+	// its checksums or manifest.
+	ErrArchiveIntegrity = errors.New("bundle archive failed integrity check")
+)
+
+// ArchiveInfo describes the bundle carried by an archive.
+type ArchiveInfo struct {
+	Namespace  string
+	Slug       string
+	Version    string
+	AssetCount int
+}
+
+// Ref returns the bundle reference for the archive.
+func (a *ArchiveInfo) Ref() Ref {
+	return Ref{Namespace: a.Namespace, Slug: a.Slug, Version: a.Version}
+}
+
+type archiveHeader struct {
+	FormatVersion int    `json:"formatVersion"`
+	Namespace     string `json:"namespace"`
+	Slug          string `json:"slug"`
+	Version       string `json:"version"`
+	CreatedBy     string `json:"createdBy"`
+}
+
+// ExportArchive writes the cached bundle version ref to w as a tar.zst
+// archive. ref.Version must be set and the version must already be cached.
+func ExportArchive(ref Ref, w io.Writer) (*ArchiveInfo, error) {
+	if ref.Version == "" {
+		return nil, fmt.Errorf("export %s: version is required", ref)
+	}
+
+	if !IsCached(ref.Namespace, ref.Slug, ref.Version) {
+		return nil, fmt.Errorf("export %s: bundle version is not cached", ref)
+	}
+
+	cachePath := CachePath(ref.Namespace, ref.Slug, ref.Version)
+
+	manifestData, err := safeio.ReadFile(filepath.Join(cachePath, archiveManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("read cached manifest: %w", err)
+	}
+
+	assets, err := cachedAssetPaths(filepath.Join(cachePath, archiveAssetsDir))
+	if err != nil {
+		return nil, err
+	}
+
+	headerData, err := json.MarshalIndent(archiveHeader{
+		FormatVersion: archiveFormatVersion,
+		Namespace:     ref.Namespace,
+		Slug:          ref.Slug,
+		Version:       ref.Version,
+		CreatedBy:     "mush/" + buildinfo.Version,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal archive header: %w", err)
+	}
+
+	encoder, err := zstd.NewWriter(w)
+	if err != nil {
+		return nil, fmt.Errorf("create zstd encoder: %w", err)
+	}
+
+	tarWriter := tar.NewWriter(encoder)
+
+	var sums bytes.Buffer
+
+	addFile := func(name string, data []byte) error {
+		fmt.Fprintf(&sums, "%s  %s\n", sha256Hex(data), name)
+		return writeTarFile(tarWriter, name, data)
+	}
+
+	if err := addFile(archiveHeaderName, headerData); err != nil {
+		return nil, err
+	}
+
+	if err := addFile(archiveManifestName, manifestData); err != nil {
+		return nil, err
+	}
+
+	for _, logicalPath := range assets {
+		data, readErr := safeio.ReadFile(filepath.Join(cachePath, archiveAssetsDir, filepath.FromSlash(logicalPath)))
+		if readErr != nil {
+			return nil, fmt.Errorf("read cached asset %s: %w", logicalPath, readErr)
+		}
+
+		if err := addFile(archiveAssetsDir+"/"+logicalPath, data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writeTarFile(tarWriter, archiveChecksumsName, sums.Bytes()); err != nil {
+		return nil, err
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("finish archive: %w", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("finish zstd stream: %w", err)
+	}
+
+	return &ArchiveInfo{
+		Namespace:  ref.Namespace,
+		Slug:       ref.Slug,
+		Version:    ref.Version,
+		AssetCount: len(assets),
+	}, nil
+}
+
+// cachedAssetPaths returns the slash-separated logical paths under assetsDir, sorted.
+func cachedAssetPaths(assetsDir string) ([]string, error) {
+	var assets []string
+
+	walkErr := filepath.WalkDir(assetsDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(assetsDir, p)
+		if relErr != nil {
+			return fmt.Errorf("relative asset path: %w", relErr)
+		}
+
+		assets = append(assets, filepath.ToSlash(rel))
+
+		return nil
+	})
+	if walkErr != nil && !errors.Is(walkErr, fs.ErrNotExist) {
+		return nil, fmt.Errorf("list cached assets: %w", walkErr)
+	}
+
+	sort.Strings(assets)
+
+	return assets, nil
+}
+
+func writeTarFile(tarWriter *tar.Writer, name string, data []byte) error {
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+		ModTime:  time.Unix(0, 0),
+		Typeflag: tar.TypeReg,
+		Format:   tar.FormatPAX,
+	}
+
+	if err := tarWriter.WriteHeader(header); err != nil {
+		return fmt.Errorf("write archive header for %s: %w", name, err)
+	}
+
+	if _, err := tarWriter.Write(data); err != nil {
+		return fmt.Errorf("write archive entry %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// ImportArchive verifies a tar.zst bundle archive from r and installs it into
+// the bundle cache. Every file must match SHA256SUMS and every manifest layer
+// must match its recorded digest before anything reaches the cache. An
+// existing cached version is kept unless replace is set.
+func ImportArchive(r io.Reader, replace bool) (*ArchiveInfo, string, error) {
+	decoder, err := zstd.NewReader(bufio.NewReader(r))
+	if err != nil {
+		return nil, "", fmt.Errorf("open zstd stream: %w", err)
+	}
+	defer decoder.Close()
+
+	files, err := readArchiveFiles(tar.NewReader(decoder))
+	if err != nil {
+		return nil, "", err
+	}
+
+	info, err := verifyArchive(files)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cachePath := CachePath(info.Namespace, info.Slug, info.Version)
+	if IsCached(info.Namespace, info.Slug, info.Version) && !replace {
+		return info, cachePath, fmt.Errorf("%s: %w", info.Ref(), ErrAlreadyCached)
+	}
+
+	EnsureCacheDirTag()
+
+	if err := promoteArchive(files, cachePath); err != nil {
+		return nil, "", err
+	}
+
+	// Best-effort blob store, matching what a pull leaves behind.
+	for name, data := range files {
+		if strings.HasPrefix(name, archiveAssetsDir+"/") {
+			_, _ = StoreBlob(data)
+		}
+	}
+
+	return info, cachePath, nil
+}
+
+// readArchiveFiles reads every regular file from the archive into memory,
+// rejecting unexpected entry types, unsafe paths, and oversized content.
+func readArchiveFiles(tarReader *tar.Reader) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	var total int64
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("read archive: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeDir {
+			continue
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("%w: unsupported entry %s", ErrArchiveIntegrity, header.Name)
+		}
+
+		name := path.Clean(header.Name)
+		if err := validateArchiveName(name); err != nil {
+			return nil, err
+		}
+
+		if _, dup := files[name]; dup {
+			return nil, fmt.Errorf("%w: duplicate entry %s", ErrArchiveIntegrity, name)
+		}
+
+		if header.Size > maxArchiveFileBytes || total+header.Size > maxArchiveTotalBytes {
+			return nil, fmt.Errorf("%w: archive exceeds size limits", ErrArchiveIntegrity)
+		}
+
+		data, err := io.ReadAll(io.LimitReader(tarReader, header.Size))
+		if err != nil {
+			return nil, fmt.Errorf("read archive entry %s: %w", name, err)
+		}
+
+		total += int64(len(data))
+		files[name] = data
+	}
+
+	return files, nil
+}
+
+func validateArchiveName(name string) error {
+	switch name {
+	case archiveHeaderName, archiveManifestName, archiveChecksumsName:
+		return nil
+	}
+
+	logicalPath, ok := strings.CutPrefix(name, archiveAssetsDir+"/")
+	if !ok {
+		return fmt.Errorf("%w: unexpected entry %s", ErrArchiveIntegrity, name)
+	}
+
+	if err := ValidateLogicalPath(logicalPath); err != nil {
+		return fmt.Errorf("%w: %w", ErrArchiveIntegrity, err)
+	}
+
+	return nil
+}
+
+// verifyArchive checks the archive checksums and manifest and describes the
+// bundle it carries.
+func verifyArchive(files map[string][]byte) (*ArchiveInfo, error) {
+	sums, ok := files[archiveChecksumsName]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing %s", ErrArchiveIntegrity, archiveChecksumsName)
+	}
+
+	listed := make(map[string]bool, len(files))
+
+	for _, line := range strings.Split(strings.TrimSpace(string(sums)), "\n") {
+		digest, name, found := strings.Cut(line, "  ")
+		if !found {
+			return nil, fmt.Errorf("%w: malformed %s line %q", ErrArchiveIntegrity, archiveChecksumsName, line)
+		}
+
+		data, exists := files[name]
+		if !exists {
+			return nil, fmt.Errorf("%w: %s is listed but missing", ErrArchiveIntegrity, name)
+		}
+
+		if sha256Hex(data) != digest {
+			return nil, fmt.Errorf("%w: checksum mismatch for %s", ErrArchiveIntegrity, name)
+		}
+
+		listed[name] = true
+	}
+
+	for name := range files {
+		if name != archiveChecksumsName && !listed[name] {
+			return nil, fmt.Errorf("%w: %s is not covered by %s", ErrArchiveIntegrity, name, archiveChecksumsName)
+		}
+	}
+
+	var header archiveHeader
+	if err := json.Unmarshal(files[archiveHeaderName], &header); err != nil {
+		return nil, fmt.Errorf("%w: parse %s: %w", ErrArchiveIntegrity, archiveHeaderName, err)
+	}
+
+	if header.FormatVersion != archiveFormatVersion {
+		return nil, fmt.Errorf("unsupported bundle archive format version %d (this mush supports %d)",
+			header.FormatVersion, archiveFormatVersion)
+	}
+
+	ref := Ref{Namespace: header.Namespace, Slug: header.Slug, Version: header.Version}
+	if ref.Namespace == "" || ref.Slug == "" || ref.Version == "" {
+		return nil, fmt.Errorf("%w: archive header is missing the bundle reference", ErrArchiveIntegrity)
+	}
+
+	for _, part := range []string{ref.Namespace, ref.Slug, ref.Version} {
+		if part == "." || ValidateLogicalPath(part) != nil || strings.ContainsAny(part, `/\`) {
+			return nil, fmt.Errorf("%w: invalid bundle reference %s", ErrArchiveIntegrity, ref)
+		}
+	}
+
+	var resolved client.BundleResolveResponse
+	if err := json.Unmarshal(files[archiveManifestName], &resolved); err != nil {
+		return nil, fmt.Errorf("%w: parse %s: %w", ErrArchiveIntegrity, archiveManifestName, err)
+	}
+
+	if (resolved.Namespace != "" && resolved.Namespace != ref.Namespace) ||
+		(resolved.Slug != "" && resolved.Slug != ref.Slug) ||
+		(resolved.Version != "" && resolved.Version != ref.Version) {
+		return nil, fmt.Errorf("%w: manifest does not match archive header %s", ErrArchiveIntegrity, ref)
+	}
+
+	for _, layer := range resolved.Manifest.Layers {
+		data, exists := files[archiveAssetsDir+"/"+layer.LogicalPath]
+		if !exists {
+			return nil, fmt.Errorf("%w: asset %s is missing", ErrArchiveIntegrity, layer.LogicalPath)
+		}
+
+		if layer.ContentSHA256 != "" && sha256Hex(data) != layer.ContentSHA256 {
+			return nil, fmt.Errorf("%w: asset %s does not match the manifest digest", ErrArchiveIntegrity, layer.LogicalPath)
+		}
+	}
+
+	return &ArchiveInfo{
+		Namespace:  ref.Namespace,
+		Slug:       ref.Slug,
+		Version:    ref.Version,
+		AssetCount: len(files) - 3,
+	}, nil
+}
+
+// promoteArchive stages the verified files next to cachePath and renames
+// them into place, the same way a pull populates the cache.
+func promoteArchive(files map[string][]byte, cachePath string) error {
+	cleanStalePartials(cachePath)
+
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err != nil {
+		return fmt.Errorf("create cache parent: %w", err)
+	}
+
+	stagingDir, err := os.MkdirTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".partial.")
+	if err != nil {
+		return fmt.Errorf("create staging directory: %w", err)
+	}
+
+	stagingFailed := true
+
+	defer func() {
+		if stagingFailed {
+			_ = os.RemoveAll(stagingDir)
+		}
+	}()
+
+	if err := safeio.MkdirAll(filepath.Join(stagingDir, archiveAssetsDir), 0o755); err != nil {
+		return fmt.Errorf("create staging assets directory: %w", err)
+	}
+
+	for name, data := range files {
+		logicalPath, isAsset := strings.CutPrefix(name, archiveAssetsDir+"/")
+		if !isAsset {
+			continue
+		}
+
+		destPath := filepath.Join(stagingDir, archiveAssetsDir, filepath.FromSlash(logicalPath))
+		if err := safeio.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+			return fmt.Errorf("create asset directory: %w", err)
+		}
+
+		if err := safeio.WriteFile(destPath, data, 0o644); err != nil {
+			return fmt.Errorf("write asset %s: %w", logicalPath, err)
+		}
+	}
+
+	if err := safeio.WriteFile(filepath.Join(stagingDir, archiveManifestName), files[archiveManifestName], 0o644); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+
+	if err := os.RemoveAll(cachePath); err != nil {
+		return fmt.Errorf("remove existing cache entry: %w", err)
+	}
+
+	if err := os.Rename(stagingDir, cachePath); err != nil {
+		return fmt.Errorf("promote staging cache: %w", err)
+	}
+
+	stagingFailed = false
+
+	return nil
+}
@@ -0,0 +1,274 @@
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func seedCachedBundle(t *testing.T, ref Ref, assets map[string]string) {
+	t.Helper()
+
+	cachePath := CachePath(ref.Namespace, ref.Slug, ref.Version)
+
+	resolved := client.BundleResolveResponse{Namespace: ref.Namespace, Slug: ref.Slug, Version: ref.Version}
+
+	for logicalPath, content := range assets {
+		resolved.Manifest.Layers = append(resolved.Manifest.Layers, client.BundleLayer{
+			LogicalPath:   logicalPath,
+			ContentSHA256: sha256Hex([]byte(content)),
+		})
+
+		dest := filepath.Join(cachePath, "assets", filepath.FromSlash(logicalPath))
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			t.Fatalf("mkdir asset dir: %v", err)
+		}
+
+		if err := os.WriteFile(dest, []byte(content), 0o600); err != nil {
+			t.Fatalf("write asset: %v", err)
+		}
+	}
+
+	data, err := json.Marshal(resolved)
+	if err != nil {
+		t.Fatalf("marshal manifest: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(cachePath, "manifest.json"), data, 0o600); err != nil {
+		t.Fatalf("write manifest: %v", err)
+	}
+}
+
+// rewriteArchive decodes an archive, applies edit to each entry, and re-encodes it.
+func rewriteArchive(t *testing.T, archive []byte, edit func(name string, data []byte) []byte) []byte {
+	t.Helper()
+
+	decoder, err := zstd.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("zstd reader: %v", err)
+	}
+	defer decoder.Close()
+
+	files, err := readArchiveFiles(tar.NewReader(decoder))
+	if err != nil {
+		t.Fatalf("readArchiveFiles() error = %v", err)
+	}
+
+	var out bytes.Buffer
+
+	encoder, err := zstd.NewWriter(&out)
+	if err != nil {
+		t.Fatalf("zstd writer: %v", err)
+	}
+
+	tarWriter := tar.NewWriter(encoder)
+
+	for name, data := range files {
+		if err := writeTarFile(tarWriter, name, edit(name, data)); err != nil {
+			t.Fatalf("writeTarFile() error = %v", err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("close tar: %v", err)
+	}
+
+	if err := encoder.Close(); err != nil {
+		t.Fatalf("close zstd: %v", err)
+	}
+
+	return out.Bytes()
+}
+
+func TestArchive_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ref := Ref{Namespace: "acme", Slug: "kit", Version: "1.2.0"}
+	seedCachedBundle(t, ref, map[string]string{
+		"skills/review/SKILL.md": "# Review",
+		"agents/helper.md":       "helper",
+	})
+
+	var archive bytes.Buffer
+
+	exported, err := ExportArchive(ref, &archive)
+	if err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	if exported.AssetCount != 2 {
+		t.Fatalf("exported AssetCount = %d, want 2", exported.AssetCount)
+	}
+
+	// Import on a "different machine" with an empty cache.
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	info, cachePath, err := ImportArchive(bytes.NewReader(archive.Bytes()), false)
+	if err != nil {
+		t.Fatalf("ImportArchive() error = %v", err)
+	}
+
+	if info.Ref() != ref || info.AssetCount != 2 {
+		t.Fatalf("ImportArchive() info = %+v, want %s with 2 assets", info, ref)
+	}
+
+	if !IsCached(ref.Namespace, ref.Slug, ref.Version) {
+		t.Fatal("bundle not cached after import")
+	}
+
+	got, err := os.ReadFile(filepath.Join(cachePath, "assets", "skills", "review", "SKILL.md"))
+	if err != nil {
+		t.Fatalf("read imported asset: %v", err)
+	}
+
+	if string(got) != "# Review" {
+		t.Fatalf("imported asset = %q, want %q", got, "# Review")
+	}
+}
+
+func TestArchive_ExportIsDeterministic(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ref := Ref{Namespace: "acme", Slug: "kit", Version: "1.0.0"}
+	seedCachedBundle(t, ref, map[string]string{"a.md": "a", "b/c.md": "c"})
+
+	var first, second bytes.Buffer
+
+	if _, err := ExportArchive(ref, &first); err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	if _, err := ExportArchive(ref, &second); err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	if !bytes.Equal(first.Bytes(), second.Bytes()) {
+		t.Fatal("two exports of the same bundle differ")
+	}
+}
+
+func TestExportArchive_RequiresCachedVersion(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	var archive bytes.Buffer
+
+	if _, err := ExportArchive(Ref{Namespace: "acme", Slug: "kit"}, &archive); err == nil {
+		t.Fatal("ExportArchive() without version: expected error")
+	}
+
+	if _, err := ExportArchive(Ref{Namespace: "acme", Slug: "kit", Version: "9.9.9"}, &archive); err == nil {
+		t.Fatal("ExportArchive() of uncached version: expected error")
+	}
+}
+
+func TestImportArchive_RejectsTamperedAsset(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ref := Ref{Namespace: "acme", Slug: "kit", Version: "1.0.0"}
+	seedCachedBundle(t, ref, map[string]string{"skill.md": "original"})
+
+	var archive bytes.Buffer
+	if _, err := ExportArchive(ref, &archive); err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	tampered := rewriteArchive(t, archive.Bytes(), func(name string, data []byte) []byte {
+		if name == "assets/skill.md" {
+			return []byte("modified")
+		}
+
+		return data
+	})
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	_, _, err := ImportArchive(bytes.NewReader(tampered), false)
+	if !errors.Is(err, ErrArchiveIntegrity) {
+		t.Fatalf("ImportArchive() error = %v, want ErrArchiveIntegrity", err)
+	}
+
+	if IsCached(ref.Namespace, ref.Slug, ref.Version) {
+		t.Fatal("tampered archive reached the cache")
+	}
+}
+
+func TestImportArchive_RejectsUnlistedFile(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ref := Ref{Namespace: "acme", Slug: "kit", Version: "1.0.0"}
+	seedCachedBundle(t, ref, map[string]string{"skill.md": "original"})
+
+	var archive bytes.Buffer
+	if _, err := ExportArchive(ref, &archive); err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	// Drop the asset's checksum line so the file is no longer covered.
+	unlisted := rewriteArchive(t, archive.Bytes(), func(name string, data []byte) []byte {
+		if name != archiveChecksumsName {
+			return data
+		}
+
+		var kept []byte
+
+		for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+			if !bytes.Contains(line, []byte("assets/skill.md")) {
+				kept = append(kept, line...)
+			}
+		}
+
+		return kept
+	})
+
+	if _, _, err := ImportArchive(bytes.NewReader(unlisted), true); !errors.Is(err, ErrArchiveIntegrity) {
+		t.Fatalf("ImportArchive() error = %v, want ErrArchiveIntegrity", err)
+	}
+}
+
+func TestImportArchive_AlreadyCached(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	ref := Ref{Namespace: "acme", Slug: "kit", Version: "1.0.0"}
+	seedCachedBundle(t, ref, map[string]string{"skill.md": "v1"})
+
+	var archive bytes.Buffer
+	if _, err := ExportArchive(ref, &archive); err != nil {
+		t.Fatalf("ExportArchive() error = %v", err)
+	}
+
+	info, _, err := ImportArchive(bytes.NewReader(archive.Bytes()), false)
+	if !errors.Is(err, ErrAlreadyCached) {
+		t.Fatalf("ImportArchive() error = %v, want ErrAlreadyCached", err)
+	}
+
+	if info == nil || info.Ref() != ref {
+		t.Fatalf("ImportArchive() info = %+v, want %s", info, ref)
+	}
+
+	// Simulate a damaged cache entry, then replace it from the archive.
+	cachePath := CachePath(ref.Namespace, ref.Slug, ref.Version)
+	if err := os.WriteFile(filepath.Join(cachePath, "assets", "skill.md"), []byte("damaged"), 0o600); err != nil {
+		t.Fatalf("damage asset: %v", err)
+	}
+
+	if _, _, err := ImportArchive(bytes.NewReader(archive.Bytes()), true); err != nil {
+		t.Fatalf("ImportArchive(replace) error = %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(cachePath, "assets", "skill.md"))
+	if err != nil {
+		t.Fatalf("read asset: %v", err)
+	}
+
+	if string(got) != "v1" {
+		t.Fatalf("asset after replace = %q, want %q", got, "v1")
+	}
+}
@@ -185,6 +185,10 @@ func pullToCache(logger *slog.Logger, resolved *client.BundleResolveResponse, pu
 		return "", fmt.Errorf("create staging assets directory: %w", err)
 	}
 
+	if resolved.Manifest.SchemaVersion == 0 {
+		resolved.Manifest.SchemaVersion = pullResp.SchemaVersion
+	}
+
 	// Backfill resolved manifest layers from pull response so the cached
 	// manifest.json contains complete layer metadata for later use.
 	if len(resolved.Manifest.Layers) == 0 {
@@ -268,27 +272,26 @@ func downloadAssetsToCache(ctx context.Context, c *client.Client, logger *slog.L
 	}
 
 	// Per-asset API download (server proxies OCI content for registry bundles).
-	spin := out.Spinner(fmt.Sprintf("Downloading %d assets", len(resolved.Manifest.Layers)))
-	spin.Start()
+	download := out.Progress("Downloading assets", int64(len(resolved.Manifest.Layers)))
 
 	logger.Info("bundle asset download started", slog.String("event.type", "bundle.download.start"), slog.Int("bundle.asset_count", len(resolved.Manifest.Layers)))
 
 	for _, layer := range resolved.Manifest.Layers {
 		if err := ValidateLogicalPath(layer.LogicalPath); err != nil {
-			spin.StopWithFailure("Path validation failed")
+			download.Fail("Path validation failed")
 			logger.Error("bundle asset path validation failed", slog.String("event.type", "bundle.download.asset.error"), slog.String("bundle.asset.logical_path", layer.LogicalPath), slog.String("error", err.Error()))
 
 			return "", fmt.Errorf("invalid logical path: %w", err)
 		}
 
 		if layer.AssetID == "" {
-			spin.StopWithFailure("Asset metadata missing")
+			download.Fail("Asset metadata missing")
 			logger.Error("bundle asset metadata missing", slog.String("event.type", "bundle.download.asset.error"), slog.String("bundle.asset.logical_path", layer.LogicalPath))
 
 			return "", fmt.Errorf("asset %s is missing asset ID for API download", layer.LogicalPath)
 		}
 
-		data, fetchErr := c.FetchBundleAsset(ctx, layer.AssetID)
+		data, fetchErr := fetchLayer(ctx, c, logger, &layer)
 		if fetchErr != nil {
 			// Fallback to hub asset-by-path endpoint (works for OCI-sourced bundles
 			// where the runner endpoint may return 503).
@@ -302,7 +305,7 @@ func downloadAssetsToCache(ctx context.Context, c *client.Client, logger *slog.L
 		}
 
 		if fetchErr != nil {
-			spin.StopWithFailure("Asset download failed")
+			download.Fail("Asset download failed")
 			logger.Error("bundle asset download failed", slog.String("event.type", "bundle.download.asset.error"), slog.String("bundle.asset.logical_path", layer.LogicalPath), slog.String("error", fetchErr.Error()))
 
 			return "", fmt.Errorf("fetch asset %s: %w", layer.LogicalPath, fetchErr)
@@ -311,7 +314,7 @@ func downloadAssetsToCache(ctx context.Context, c *client.Client, logger *slog.L
 		// Verify SHA256 (may recover trailing newline stripped by server).
 		verified, verifyErr := VerifySHA256(data, layer.ContentSHA256)
 		if verifyErr != nil {
-			spin.StopWithFailure("Integrity check failed")
+			download.Fail("Integrity check failed")
 			logger.Error("bundle asset integrity check failed",
 				slog.String("event.type", "bundle.download.asset.error"),
 				slog.String("bundle.asset.logical_path", layer.LogicalPath),
@@ -332,15 +335,19 @@ func downloadAssetsToCache(ctx context.Context, c *client.Client, logger *slog.L
 		// Write to staging cache (materialized view).
 		destPath := filepath.Join(assetsDir, layer.LogicalPath)
 		if err := safeio.MkdirAll(filepath.Dir(destPath), 0o755); err != nil {
+			download.Fail("")
 			return "", fmt.Errorf("create asset directory: %w", err)
 		}
 
 		if err := safeio.WriteFile(destPath, data, 0o644); err != nil {
+			download.Fail("")
 			return "", fmt.Errorf("write asset %s: %w", layer.LogicalPath, err)
 		}
+
+		download.Add(1)
 	}
 
-	spin.StopWithSuccess(fmt.Sprintf("Downloaded %d assets", len(resolved.Manifest.Layers)))
+	download.Done(fmt.Sprintf("Downloaded %d assets", len(resolved.Manifest.Layers)))
 	logger.Info("bundle asset download completed", slog.String("event.type", "bundle.download.complete"), slog.Int("bundle.asset_count", len(resolved.Manifest.Layers)))
 
 	if err := writeManifest(stagingDir, resolved); err != nil {
@@ -0,0 +1,67 @@
+package bundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// etagEntry is the on-disk form of one cached HTTP response.
+type etagEntry struct {
+	URL  string `json:"url"`
+	ETag string `json:"etag"`
+	Body []byte `json:"body"`
+}
+
+// ETagCache is a client.ResponseCache backed by the bundle cache root, so
+// repeated resolves of the same bundle revalidate instead of re-downloading.
+type ETagCache struct {
+	dir string
+}
+
+var _ client.ResponseCache = (*ETagCache)(nil)
+
+// NewETagCache returns an ETag cache under the bundle cache root.
+func NewETagCache() *ETagCache {
+	return &ETagCache{dir: filepath.Join(cacheRootDir(), "etags")}
+}
+
+// Lookup implements client.ResponseCache.
+func (c *ETagCache) Lookup(key string) (etag string, body []byte, ok bool) {
+	data, exists, err := safeio.ReadFileIfExists(c.path(key))
+	if err != nil || !exists {
+		return "", nil, false
+	}
+
+	var entry etagEntry
+	if json.Unmarshal(data, &entry) != nil || entry.URL != key || entry.ETag == "" {
+		return "", nil, false
+	}
+
+	return entry.ETag, entry.Body, true
+}
+
+// Store implements client.ResponseCache.
+func (c *ETagCache) Store(key, etag string, body []byte) error {
+	data, err := json.Marshal(etagEntry{URL: key, ETag: etag, Body: body})
+	if err != nil {
+		return fmt.Errorf("marshal etag cache entry: %w", err)
+	}
+
+	if err := safeio.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("create etag cache directory: %w", err)
+	}
+
+	if err := safeio.WriteFile(c.path(key), data, 0o600); err != nil {
+		return fmt.Errorf("write etag cache entry: %w", err)
+	}
+
+	return nil
+}
+
+func (c *ETagCache) path(key string) string {
+	return filepath.Join(c.dir, sha256Hex([]byte(key))+".json")
+}
@@ -0,0 +1,134 @@
+package bundle
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// AddExtraDirs exposes dirs to the harness after the bundle directory, for
+// providers whose spec accepts more than one directory.
+//
+// Order is significant: the bundle directory comes first and the extra
+// directories follow in the order given, so when two directories provide a
+// skill or agent with the same name, the earlier one wins. Each shadowed
+// asset is reported as a warning. A directory given twice, or the working
+// directory itself (which the harness already sees), is skipped with a
+// warning. A path that is missing or not a directory is an error.
+func (s *LoadSession) AddExtraDirs(spec *harnesstype.ProviderSpec, dirs []string) error {
+	if len(dirs) == 0 {
+		return nil
+	}
+
+	if spec == nil || !spec.BundleDir.AcceptsExtraDirs() {
+		name := "this harness"
+		if spec != nil {
+			name = spec.DisplayName
+		}
+
+		return fmt.Errorf("%s does not support extra directories", name)
+	}
+
+	seen := map[string]string{}
+
+	for _, dir := range append([]string{s.WorkingDir, s.BundleDir}, s.ExtraDirs...) {
+		if dir == "" {
+			continue
+		}
+
+		if key, err := dirKey(dir); err == nil {
+			seen[key] = dir
+		}
+	}
+
+	for _, dir := range dirs {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return fmt.Errorf("resolve extra directory %s: %w", dir, err)
+		}
+
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("extra directory %s: %w", dir, err)
+		}
+
+		if !info.IsDir() {
+			return fmt.Errorf("extra directory %s is not a directory", dir)
+		}
+
+		key, err := dirKey(abs)
+		if err != nil {
+			return fmt.Errorf("resolve extra directory %s: %w", dir, err)
+		}
+
+		if previous, ok := seen[key]; ok {
+			s.Warnings = append(s.Warnings, fmt.Sprintf("Skipping extra directory %s: same as %s", dir, previous))
+			continue
+		}
+
+		seen[key] = abs
+		s.ExtraDirs = append(s.ExtraDirs, abs)
+	}
+
+	s.Warnings = append(s.Warnings, shadowedAssets(spec, append([]string{s.BundleDir}, s.ExtraDirs...))...)
+
+	return nil
+}
+
+// dirKey identifies a directory regardless of how its path was spelled.
+func dirKey(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("absolute path: %w", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(abs)
+	if err != nil {
+		return "", fmt.Errorf("resolve symlinks: %w", err)
+	}
+
+	return resolved, nil
+}
+
+// shadowedAssets warns about skills, agents, and commands that appear in more than one
+// of dirs; the first directory listing a name is the one the harness uses.
+func shadowedAssets(spec *harnesstype.ProviderSpec, dirs []string) []string {
+	if spec.Assets == nil {
+		return nil
+	}
+
+	var warnings []string
+
+	for _, kind := range []struct{ label, rel string }{
+		{"Skill", spec.Assets.SkillDir},
+		{"Agent", spec.Assets.AgentDir},
+		{"Command", spec.Assets.CommandDir},
+	} {
+		if kind.rel == "" {
+			continue
+		}
+
+		owner := map[string]string{}
+
+		for _, dir := range dirs {
+			entries, err := os.ReadDir(filepath.Join(dir, kind.rel))
+			if err != nil {
+				continue
+			}
+
+			for _, entry := range entries {
+				name := entry.Name()
+				if first, ok := owner[name]; ok {
+					warnings = append(warnings, fmt.Sprintf("%s %q in %s is shadowed by the one in %s", kind.label, name, dir, first))
+					continue
+				}
+
+				owner[name] = dir
+			}
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,99 @@
+package bundle
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/harness"
+)
+
+func TestLoadSessionAddExtraDirs(t *testing.T) {
+	spec, ok := harness.GetProvider("claude")
+	if !ok {
+		t.Fatal("claude provider not found")
+	}
+
+	bundleDir := t.TempDir()
+	teamDir := t.TempDir()
+	personalDir := t.TempDir()
+
+	writeDirFile(t, bundleDir, ".claude/skills/web/SKILL.md")
+	writeDirFile(t, teamDir, ".claude/skills/web/SKILL.md")
+	writeDirFile(t, teamDir, ".claude/skills/deploy/SKILL.md")
+
+	session := &LoadSession{WorkingDir: t.TempDir(), BundleDir: bundleDir}
+
+	if err := session.AddExtraDirs(spec, []string{teamDir, personalDir, teamDir, bundleDir}); err != nil {
+		t.Fatalf("AddExtraDirs() error = %v", err)
+	}
+
+	if len(session.ExtraDirs) != 2 || session.ExtraDirs[0] != teamDir || session.ExtraDirs[1] != personalDir {
+		t.Fatalf("ExtraDirs = %v, want [%s %s]", session.ExtraDirs, teamDir, personalDir)
+	}
+
+	warnings := strings.Join(session.Warnings, "\n")
+
+	if strings.Count(warnings, "Skipping extra directory") != 2 {
+		t.Fatalf("Warnings = %q, want the repeated and bundle directories skipped", warnings)
+	}
+
+	if !strings.Contains(warnings, `Skill "web" in `+teamDir+" is shadowed by the one in "+bundleDir) {
+		t.Fatalf("Warnings = %q, want shadowed skill warning", warnings)
+	}
+
+	if strings.Contains(warnings, `"deploy"`) {
+		t.Fatalf("Warnings = %q, deploy is not shadowed", warnings)
+	}
+}
+
+func TestLoadSessionAddExtraDirs_Errors(t *testing.T) {
+	claude, ok := harness.GetProvider("claude")
+	if !ok {
+		t.Fatal("claude provider not found")
+	}
+
+	session := &LoadSession{WorkingDir: t.TempDir(), BundleDir: t.TempDir()}
+
+	if err := session.AddExtraDirs(claude, []string{filepath.Join(t.TempDir(), "missing")}); err == nil {
+		t.Fatal("expected error for missing directory")
+	}
+
+	file := filepath.Join(t.TempDir(), "notes.md")
+	if err := os.WriteFile(file, []byte("notes"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := session.AddExtraDirs(claude, []string{file}); err == nil {
+		t.Fatal("expected error for a file")
+	}
+
+	for _, name := range harness.ProviderNames() {
+		spec, _ := harness.GetProvider(name)
+		if spec.BundleDir.AcceptsExtraDirs() {
+			continue
+		}
+
+		if err := session.AddExtraDirs(spec, []string{t.TempDir()}); err == nil {
+			t.Fatalf("expected error for %s, which accepts one directory", name)
+		}
+
+		return
+	}
+
+	t.Skip("every provider accepts extra directories")
+}
+
+func writeDirFile(t *testing.T, dir, rel string) {
+	t.Helper()
+
+	path := filepath.Join(dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(rel), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
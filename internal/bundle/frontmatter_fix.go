@@ -0,0 +1,85 @@
+package bundle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FrontmatterChange is one line FixFrontmatter rewrote. Line is 1-based.
+type FrontmatterChange struct {
+	Line int
+	Old  string
+	New  string
+}
+
+// FrontmatterError explains why invalid frontmatter could not be repaired.
+type FrontmatterError struct {
+	Cause error
+	Hint  string
+}
+
+func (e *FrontmatterError) Error() string {
+	return e.Cause.Error()
+}
+
+func (e *FrontmatterError) Unwrap() error {
+	return e.Cause
+}
+
+// FixFrontmatter repairs a Markdown asset's YAML frontmatter the way bundle
+// loading does, and reports the lines it changed. It returns nil changes
+// when there is no frontmatter or it is already valid, and a
+// *FrontmatterError when it is invalid and cannot be repaired.
+func FixFrontmatter(data []byte) (repaired []byte, changes []FrontmatterChange, err error) {
+	fmErr := ValidateSkillFrontmatter(data)
+	if fmErr == nil {
+		return data, nil, nil
+	}
+
+	fixed, ok := RepairSkillFrontmatter(data)
+	if !ok {
+		return data, nil, &FrontmatterError{Cause: fmErr, Hint: frontmatterHint(data)}
+	}
+
+	// Repair rewrites lines in place, so line numbers line up.
+	oldLines := strings.Split(string(data), "\n")
+	newLines := strings.Split(string(fixed), "\n")
+
+	for i := range min(len(oldLines), len(newLines)) {
+		if oldLines[i] != newLines[i] {
+			changes = append(changes, FrontmatterChange{
+				Line: i + 1,
+				Old:  strings.TrimRight(oldLines[i], "\r"),
+				New:  strings.TrimRight(newLines[i], "\r"),
+			})
+		}
+	}
+
+	return fixed, changes, nil
+}
+
+// frontmatterHint suggests a manual fix for frontmatter the automatic repair
+// does not handle.
+func frontmatterHint(data []byte) string {
+	frontmatter := string(extractFrontmatter(data))
+
+	for _, line := range strings.Split(frontmatter, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			return "YAML does not allow tabs for indentation; indent with spaces"
+		}
+	}
+
+	for _, line := range strings.Split(frontmatter, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "- ") {
+			continue
+		}
+
+		if !strings.Contains(trimmed, ":") {
+			return fmt.Sprintf("%q is not a \"key: value\" line; continue long values on indented lines or use a block scalar (|)", trimmed)
+		}
+	}
+
+	return "automatic repair only quotes top-level values that contain \": \"; " +
+		"quote values that start with special characters such as * & ! % @ ` or contain \" #\" by hand"
+}
@@ -0,0 +1,75 @@
+package bundle
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFixFrontmatter(t *testing.T) {
+	data := []byte("---\nname: deploy\ndescription: Deploy: to prod\n---\n# Deploy\n")
+
+	repaired, changes, err := FixFrontmatter(data)
+	if err != nil {
+		t.Fatalf("FixFrontmatter() error = %v", err)
+	}
+
+	if len(changes) != 1 {
+		t.Fatalf("changes = %+v, want one", changes)
+	}
+
+	want := FrontmatterChange{Line: 3, Old: "description: Deploy: to prod", New: `description: "Deploy: to prod"`}
+	if changes[0] != want {
+		t.Fatalf("change = %+v, want %+v", changes[0], want)
+	}
+
+	if ValidateSkillFrontmatter(repaired) != nil {
+		t.Fatalf("repaired frontmatter is still invalid:\n%s", repaired)
+	}
+}
+
+func TestFixFrontmatter_NothingToDo(t *testing.T) {
+	for _, data := range []string{
+		"# No frontmatter\n",
+		"---\nname: web\ndescription: Browse\n---\n",
+	} {
+		repaired, changes, err := FixFrontmatter([]byte(data))
+		if err != nil || changes != nil || string(repaired) != data {
+			t.Fatalf("FixFrontmatter(%q) = %q, %v, %v; want unchanged", data, repaired, changes, err)
+		}
+	}
+}
+
+func TestFixFrontmatter_Unrepairable(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantHint string
+	}{
+		{
+			name:     "tab indentation",
+			data:     "---\nname: web\nmetadata:\n\tteam: core\n---\n",
+			wantHint: "tabs",
+		},
+		{
+			name:     "bare continuation line",
+			data:     "---\nname: web\ndescription: Browse\nthe web\n---\n",
+			wantHint: `"the web" is not a "key: value" line`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := FixFrontmatter([]byte(tt.data))
+
+			var fmErr *FrontmatterError
+			if !errors.As(err, &fmErr) {
+				t.Fatalf("FixFrontmatter() error = %v, want *FrontmatterError", err)
+			}
+
+			if !strings.Contains(fmErr.Hint, tt.wantHint) {
+				t.Fatalf("Hint = %q, want it to contain %q", fmErr.Hint, tt.wantHint)
+			}
+		})
+	}
+}
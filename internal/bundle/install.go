@@ -21,9 +21,19 @@ type InstalledBundle struct {
 	Harness   string   `json:"harness"`
 	Assets    []string `json:"assets"` // installed file paths (relative to workDir)
 
+	// MergedConfigs records what the bundle merged into shared tool config
+	// files, which uninstall takes back out instead of deleting the file.
+	MergedConfigs []MergedConfig `json:"mergedConfigs,omitempty"`
+
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// MergedConfig lists the keys a bundle added to one tool config file.
+type MergedConfig struct {
+	Path string     `json:"path"` // relative to workDir
+	Keys [][]string `json:"keys"`
+}
+
 const installedFileName = "installed.json"
 
 // TrackInstall records a bundle installation in .musher/installed.json.
@@ -139,6 +149,8 @@ func FindInstalled(workDir string, ref Ref, harness string) (*InstalledBundle, e
 var ErrNotInstalled = errors.New("bundle not installed")
 
 // Uninstall removes installed assets for a specific bundle reference and harness.
+// Keys the bundle merged into shared tool configs are taken back out, and
+// directories left empty are pruned.
 func Uninstall(workDir string, ref Ref, harness string) ([]string, error) {
 	installed, err := LoadInstalled(workDir)
 	if err != nil {
@@ -169,22 +181,41 @@ func Uninstall(workDir string, ref Ref, harness string) ([]string, error) {
 	entry := installed[target]
 	removed := make([]string, 0, len(entry.Assets))
 
+	merged := make(map[string]struct{}, len(entry.MergedConfigs))
+	for _, config := range entry.MergedConfigs {
+		merged[config.Path] = struct{}{}
+	}
+
 	for _, relPath := range entry.Assets {
-		absPath := filepath.Join(workDir, relPath)
-		cleanWorkDir := filepath.Clean(workDir)
-		cleanAbsPath := filepath.Clean(absPath)
+		if _, ok := merged[relPath]; ok {
+			continue
+		}
 
-		if !strings.HasPrefix(cleanAbsPath, cleanWorkDir+string(filepath.Separator)) && cleanAbsPath != cleanWorkDir {
-			return nil, fmt.Errorf("refusing to remove path outside workdir: %s", relPath)
+		absPath, pathErr := installedAbsPath(workDir, relPath)
+		if pathErr != nil {
+			return nil, pathErr
 		}
 
-		if err := os.Remove(cleanAbsPath); err != nil && !os.IsNotExist(err) {
+		if err := os.Remove(absPath); err != nil && !os.IsNotExist(err) {
 			return nil, fmt.Errorf("remove %s: %w", relPath, err)
 		}
 
+		pruneEmptyDirs(workDir, filepath.Dir(absPath))
+
 		removed = append(removed, relPath)
 	}
 
+	for _, config := range entry.MergedConfigs {
+		deleted, unmergeErr := unmergeInstalledConfig(workDir, config)
+		if unmergeErr != nil {
+			return nil, unmergeErr
+		}
+
+		if deleted {
+			removed = append(removed, config.Path)
+		}
+	}
+
 	installed = append(installed[:target], installed[target+1:]...)
 	if err := saveInstalled(workDir, installed); err != nil {
 		return nil, err
@@ -193,6 +224,70 @@ func Uninstall(workDir string, ref Ref, harness string) ([]string, error) {
 	return removed, nil
 }
 
+// unmergeInstalledConfig takes a bundle's keys back out of a tool config
+// file, deleting the file when nothing else is left in it.
+func unmergeInstalledConfig(workDir string, config MergedConfig) (deleted bool, err error) {
+	absPath, err := installedAbsPath(workDir, config.Path)
+	if err != nil {
+		return false, err
+	}
+
+	data, exists, err := safeio.ReadFileIfExists(absPath)
+	if err != nil {
+		return false, fmt.Errorf("read %s: %w", config.Path, err)
+	}
+
+	if !exists {
+		return false, nil
+	}
+
+	out, empty, err := unmergeToolConfig(data, config.Keys, absPath)
+	if err != nil {
+		return false, err
+	}
+
+	if !empty {
+		return false, writeFileAtomic(filepath.Dir(absPath), filepath.Base(absPath), out)
+	}
+
+	if err := os.Remove(absPath); err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("remove %s: %w", config.Path, err)
+	}
+
+	pruneEmptyDirs(workDir, filepath.Dir(absPath))
+
+	return true, nil
+}
+
+// installedAbsPath resolves a path from installed.json, refusing any that
+// points outside workDir.
+func installedAbsPath(workDir, relPath string) (string, error) {
+	cleanWorkDir := filepath.Clean(workDir)
+	cleanAbsPath := filepath.Clean(filepath.Join(workDir, relPath))
+
+	if !strings.HasPrefix(cleanAbsPath, cleanWorkDir+string(filepath.Separator)) && cleanAbsPath != cleanWorkDir {
+		return "", fmt.Errorf("refusing to remove path outside workdir: %s", relPath)
+	}
+
+	return cleanAbsPath, nil
+}
+
+// pruneEmptyDirs removes dir and its parents while they are empty, stopping
+// at workDir.
+func pruneEmptyDirs(workDir, dir string) {
+	root := filepath.Clean(workDir)
+
+	for current := filepath.Clean(dir); current != root; current = filepath.Dir(current) {
+		if !strings.HasPrefix(current, root+string(filepath.Separator)) {
+			return
+		}
+
+		if err := os.Remove(current); err != nil {
+			return
+		}
+	}
+}
+
 func saveInstalled(workDir string, installed []InstalledBundle) error {
 	mushDir := filepath.Join(workDir, ".musher")
 	if err := safeio.MkdirAll(mushDir, 0o755); err != nil {
@@ -204,12 +299,17 @@ func saveInstalled(workDir string, installed []InstalledBundle) error {
 		return fmt.Errorf("marshal installed bundles: %w", err)
 	}
 
-	dest := filepath.Join(mushDir, installedFileName)
+	return writeFileAtomic(mushDir, installedFileName, data)
+}
+
+// writeFileAtomic replaces dir/name with data by writing a temp file in the
+// same directory and renaming it into place.
+func writeFileAtomic(dir, name string, data []byte) error {
+	dest := filepath.Join(dir, name)
 
-	// Atomic write: temp file in same dir + rename.
-	tmpFile, err := os.CreateTemp(mushDir, installedFileName+".*.tmp")
+	tmpFile, err := os.CreateTemp(dir, name+".*.tmp")
 	if err != nil {
-		return fmt.Errorf("create temp installed file: %w", err)
+		return fmt.Errorf("create temp file for %s: %w", name, err)
 	}
 
 	tmp := tmpFile.Name()
@@ -218,24 +318,24 @@ func saveInstalled(workDir string, installed []InstalledBundle) error {
 		_ = tmpFile.Close()
 		_ = os.Remove(tmp)
 
-		return fmt.Errorf("write temp installed file: %w", writeErr)
+		return fmt.Errorf("write temp file for %s: %w", name, writeErr)
 	}
 
 	if closeErr := tmpFile.Close(); closeErr != nil {
 		_ = os.Remove(tmp)
-		return fmt.Errorf("close temp installed file: %w", closeErr)
+		return fmt.Errorf("close temp file for %s: %w", name, closeErr)
 	}
 
 	if err := os.Rename(tmp, dest); err != nil {
 		// Fallback for Windows: remove dest then retry rename.
 		if removeErr := os.Remove(dest); removeErr != nil && !os.IsNotExist(removeErr) {
 			_ = os.Remove(tmp)
-			return fmt.Errorf("remove existing installed file: %w", removeErr)
+			return fmt.Errorf("remove existing %s: %w", name, removeErr)
 		}
 
 		if retryErr := os.Rename(tmp, dest); retryErr != nil {
 			_ = os.Remove(tmp)
-			return fmt.Errorf("replace installed file: %w", retryErr)
+			return fmt.Errorf("replace %s: %w", name, retryErr)
 		}
 	}
 
@@ -3,6 +3,7 @@ package bundle
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -135,6 +136,94 @@ func TestUninstallRemovesFilesAndEntry(t *testing.T) {
 	}
 }
 
+func TestUninstallUnmergesToolConfigAndPrunesDirs(t *testing.T) {
+	workDir := t.TempDir()
+
+	skillRel := filepath.Join(".codex", "skills", "web", "SKILL.md")
+	configRel := filepath.Join(".codex", "config.toml")
+
+	if err := os.MkdirAll(filepath.Join(workDir, ".codex", "skills", "web"), 0o755); err != nil {
+		t.Fatalf("MkdirAll error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(workDir, skillRel), []byte("skill"), 0o644); err != nil {
+		t.Fatalf("WriteFile(skill) error = %v", err)
+	}
+
+	config := "model = \"o3\"\n\n[mcp_servers.beta]\ncommand = \"b\"\n"
+	if err := os.WriteFile(filepath.Join(workDir, configRel), []byte(config), 0o644); err != nil {
+		t.Fatalf("WriteFile(config) error = %v", err)
+	}
+
+	bundle := &InstalledBundle{
+		Namespace: "acme",
+		Slug:      "my-bundle",
+		Harness:   "codex",
+		Assets:    []string{configRel, skillRel},
+		MergedConfigs: []MergedConfig{
+			{Path: configRel, Keys: [][]string{{"mcp_servers", "beta"}}},
+		},
+	}
+	if err := TrackInstall(workDir, bundle); err != nil {
+		t.Fatalf("TrackInstall error = %v", err)
+	}
+
+	removed, err := Uninstall(workDir, Ref{Namespace: "acme", Slug: "my-bundle"}, "codex")
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	if len(removed) != 1 || removed[0] != skillRel {
+		t.Fatalf("Uninstall() removed = %v, want [%s]", removed, skillRel)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workDir, ".codex", "skills")); !os.IsNotExist(statErr) {
+		t.Fatalf("empty skills directory should be pruned, stat error = %v", statErr)
+	}
+
+	data, err := os.ReadFile(filepath.Join(workDir, configRel))
+	if err != nil {
+		t.Fatalf("ReadFile(config) error = %v", err)
+	}
+
+	if got := string(data); strings.Contains(got, "mcp_servers") || !strings.Contains(got, "model") {
+		t.Fatalf("config.toml = %q, want user settings kept and bundle server removed", got)
+	}
+}
+
+func TestUninstallRemovesConfigLeftEmpty(t *testing.T) {
+	workDir := t.TempDir()
+
+	configRel := ".mcp.json"
+	if err := os.WriteFile(filepath.Join(workDir, configRel), []byte(`{"mcpServers":{"beta":{}}}`), 0o644); err != nil {
+		t.Fatalf("WriteFile(config) error = %v", err)
+	}
+
+	bundle := &InstalledBundle{
+		Namespace:     "acme",
+		Slug:          "my-bundle",
+		Harness:       "claude",
+		Assets:        []string{configRel},
+		MergedConfigs: []MergedConfig{{Path: configRel, Keys: [][]string{{"mcpServers", "beta"}}}},
+	}
+	if err := TrackInstall(workDir, bundle); err != nil {
+		t.Fatalf("TrackInstall error = %v", err)
+	}
+
+	removed, err := Uninstall(workDir, Ref{Namespace: "acme", Slug: "my-bundle"}, "claude")
+	if err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+
+	if len(removed) != 1 || removed[0] != configRel {
+		t.Fatalf("Uninstall() removed = %v, want [%s]", removed, configRel)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workDir, configRel)); !os.IsNotExist(statErr) {
+		t.Fatalf("empty .mcp.json should be removed, stat error = %v", statErr)
+	}
+}
+
 func TestSaveInstalledAtomic(t *testing.T) {
 	workDir := t.TempDir()
 	mushDir := filepath.Join(workDir, ".musher")
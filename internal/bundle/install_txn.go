@@ -0,0 +1,298 @@
+package bundle
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+const installJournalName = "install-journal.json"
+
+// renameFile moves staged files into place; tests replace it to fail a
+// commit partway through.
+var renameFile = os.Rename
+
+// installJournal records an install in progress in .musher, so a failed or
+// interrupted install can put every file back the way it was.
+type installJournal struct {
+	Entries []journalEntry `json:"entries"`
+
+	// CreatedDirs lists directories the install created, parents first.
+	CreatedDirs []string `json:"createdDirs,omitempty"`
+}
+
+// journalEntry is one file the install writes. Staged holds the new
+// content until commit; Backup is where the file it replaces is kept, and
+// is empty when the target did not exist.
+type journalEntry struct {
+	Target string `json:"target"`
+	Staged string `json:"staged"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// installTxn applies a set of file writes as one unit. Each file is
+// staged and verified next to its target, the journal is saved, and then
+// targets are swapped in by rename. Until the journal is removed, rollback
+// restores every target.
+type installTxn struct {
+	workDir string
+	journal installJournal
+}
+
+func newInstallTxn(workDir string) *installTxn {
+	return &installTxn{workDir: workDir}
+}
+
+// stage writes data beside target and checks it reads back intact.
+func (tx *installTxn) stage(target string, data []byte) error {
+	dir := filepath.Dir(target)
+
+	created, err := missingDirs(dir)
+	if err != nil {
+		return err
+	}
+
+	if mkErr := safeio.MkdirAll(dir, 0o755); mkErr != nil {
+		return fmt.Errorf("create directory for %s: %w", target, mkErr)
+	}
+
+	tx.journal.CreatedDirs = append(tx.journal.CreatedDirs, created...)
+
+	staged, err := stageFile(dir, filepath.Base(target), data)
+	if err != nil {
+		return fmt.Errorf("stage %s: %w", target, err)
+	}
+
+	entry := journalEntry{Target: target, Staged: staged}
+
+	if _, statErr := os.Lstat(target); statErr == nil {
+		entry.Backup = strings.Replace(staged, ".mush-stage-", ".mush-backup-", 1)
+	} else if !errors.Is(statErr, os.ErrNotExist) {
+		_ = os.Remove(staged)
+		return fmt.Errorf("stat %s: %w", target, statErr)
+	}
+
+	tx.journal.Entries = append(tx.journal.Entries, entry)
+
+	return nil
+}
+
+// commit saves the journal and swaps every staged file into place. On
+// error the caller must roll back.
+func (tx *installTxn) commit() error {
+	if err := saveInstallJournal(tx.workDir, &tx.journal); err != nil {
+		return err
+	}
+
+	for _, entry := range tx.journal.Entries {
+		if entry.Backup != "" {
+			if err := renameFile(entry.Target, entry.Backup); err != nil {
+				return fmt.Errorf("back up %s: %w", entry.Target, err)
+			}
+		}
+
+		if err := renameFile(entry.Staged, entry.Target); err != nil {
+			return fmt.Errorf("install %s: %w", entry.Target, err)
+		}
+	}
+
+	// Removing the journal is the commit point; backups left behind by a
+	// crash after it are only stray hidden files.
+	if err := removeInstallJournal(tx.workDir); err != nil {
+		return err
+	}
+
+	for _, entry := range tx.journal.Entries {
+		if entry.Backup != "" {
+			_ = os.Remove(entry.Backup)
+		}
+	}
+
+	return nil
+}
+
+// rollback undoes a staged or partly committed install.
+func (tx *installTxn) rollback() {
+	rollbackJournal(&tx.journal)
+	_ = removeInstallJournal(tx.workDir)
+}
+
+// recoverInstall rolls back an install that was interrupted before it
+// committed, as recorded by a journal left in workDir.
+func recoverInstall(workDir string) error {
+	data, exists, err := safeio.ReadFileIfExists(installJournalPath(workDir))
+	if err != nil {
+		return fmt.Errorf("read install journal: %w", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	var journal installJournal
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return fmt.Errorf("parse install journal: %w", err)
+	}
+
+	rollbackJournal(&journal)
+
+	return removeInstallJournal(workDir)
+}
+
+func rollbackJournal(journal *installJournal) {
+	for i := len(journal.Entries) - 1; i >= 0; i-- {
+		rollbackEntry(journal.Entries[i])
+	}
+
+	// Remove created directories deepest first; only empty ones go.
+	for i := len(journal.CreatedDirs) - 1; i >= 0; i-- {
+		_ = os.Remove(journal.CreatedDirs[i])
+	}
+}
+
+// rollbackEntry restores one target from wherever the commit stopped: not
+// started, target moved to its backup, or staged file renamed into place.
+func rollbackEntry(entry journalEntry) {
+	if _, err := os.Lstat(entry.Staged); err == nil {
+		_ = os.Remove(entry.Staged)
+
+		if entry.Backup != "" {
+			if _, backupErr := os.Lstat(entry.Backup); backupErr == nil {
+				_ = os.Rename(entry.Backup, entry.Target)
+			}
+		}
+
+		return
+	}
+
+	if entry.Backup != "" {
+		_ = os.Rename(entry.Backup, entry.Target)
+		return
+	}
+
+	_ = os.Remove(entry.Target)
+}
+
+// stageFile writes data to a hidden temp file in dir and reads it back to
+// catch short writes, such as on a full disk.
+func stageFile(dir, name string, data []byte) (string, error) {
+	tmpFile, err := os.CreateTemp(dir, "."+name+".mush-stage-*")
+	if err != nil {
+		return "", fmt.Errorf("create staged file: %w", err)
+	}
+
+	staged := tmpFile.Name()
+
+	_, writeErr := tmpFile.Write(data)
+	closeErr := tmpFile.Close()
+
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		_ = os.Remove(staged)
+		return "", fmt.Errorf("write staged file: %w", err)
+	}
+
+	if err := os.Chmod(staged, 0o644); err != nil {
+		_ = os.Remove(staged)
+		return "", fmt.Errorf("chmod staged file: %w", err)
+	}
+
+	written, err := safeio.ReadFile(staged)
+	if err != nil || !bytes.Equal(written, data) {
+		_ = os.Remove(staged)
+		return "", fmt.Errorf("verify staged file: wrote %d of %d bytes: %w", len(written), len(data), err)
+	}
+
+	return staged, nil
+}
+
+// missingDirs returns the directories MkdirAll(dir) would create, parents
+// first.
+func missingDirs(dir string) ([]string, error) {
+	var missing []string
+
+	for current := dir; ; {
+		_, err := os.Stat(current)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("stat %s: %w", current, err)
+		}
+
+		missing = append([]string{current}, missing...)
+
+		parent := filepath.Dir(current)
+		if parent == current {
+			break
+		}
+
+		current = parent
+	}
+
+	return missing, nil
+}
+
+func installJournalPath(workDir string) string {
+	return filepath.Join(workDir, ".musher", installJournalName)
+}
+
+func saveInstallJournal(workDir string, journal *installJournal) error {
+	mushDir := filepath.Join(workDir, ".musher")
+	if err := safeio.MkdirAll(mushDir, 0o755); err != nil {
+		return fmt.Errorf("create .musher directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(journal, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal install journal: %w", err)
+	}
+
+	return writeFileAtomic(mushDir, installJournalName, data)
+}
+
+func removeInstallJournal(workDir string) error {
+	if err := os.Remove(installJournalPath(workDir)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove install journal: %w", err)
+	}
+
+	return nil
+}
+
+// writeInstallFiles writes files (target path to content) in workDir as a
+// single transaction, first rolling back any install a crash left behind.
+func writeInstallFiles(workDir string, files map[string][]byte) error {
+	if err := recoverInstall(workDir); err != nil {
+		return fmt.Errorf("recover interrupted install: %w", err)
+	}
+
+	targets := make([]string, 0, len(files))
+	for target := range files {
+		targets = append(targets, target)
+	}
+
+	sort.Strings(targets)
+
+	tx := newInstallTxn(workDir)
+
+	for _, target := range targets {
+		if err := tx.stage(target, files[target]); err != nil {
+			tx.rollback()
+			return err
+		}
+	}
+
+	if err := tx.commit(); err != nil {
+		tx.rollback()
+		return err
+	}
+
+	return nil
+}
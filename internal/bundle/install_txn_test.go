@@ -0,0 +1,198 @@
+package bundle
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness"
+)
+
+func writeCachedSkills(t *testing.T, names ...string) (string, *client.BundleManifest) {
+	t.Helper()
+
+	cacheDir := t.TempDir()
+	manifest := &client.BundleManifest{}
+
+	for _, name := range names {
+		rel := "skills/" + name + "/SKILL.md"
+		path := filepath.Join(cacheDir, "assets", rel)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll(%s) error = %v", rel, err)
+		}
+
+		if err := os.WriteFile(path, []byte("new "+name), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", rel, err)
+		}
+
+		manifest.Layers = append(manifest.Layers, client.BundleLayer{LogicalPath: rel, AssetType: "skill"})
+	}
+
+	return cacheDir, manifest
+}
+
+func claudeMapper(t *testing.T) AssetMapper {
+	t.Helper()
+
+	spec, ok := harness.GetProvider("claude")
+	if !ok {
+		t.Fatal("claude provider not found")
+	}
+
+	return NewProviderMapper(spec)
+}
+
+func assertNoStagedFiles(t *testing.T, dir string) {
+	t.Helper()
+
+	_ = filepath.WalkDir(dir, func(path string, _ os.DirEntry, _ error) error {
+		if strings.Contains(path, ".mush-stage-") || strings.Contains(path, ".mush-backup-") {
+			t.Errorf("leftover install file %s", path)
+		}
+
+		return nil
+	})
+
+	if _, err := os.Stat(installJournalPath(dir)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("install journal still present: %v", err)
+	}
+}
+
+func TestInstallFromCache_CommitFailureRollsBack(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir, manifest := writeCachedSkills(t, "a", "b", "c")
+
+	existing := filepath.Join(workDir, ".claude", "skills", "a", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(existing, []byte("old a"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	renameFile = func(oldPath, newPath string) error {
+		if strings.HasSuffix(newPath, filepath.Join("c", "SKILL.md")) {
+			return errors.New("disk full")
+		}
+
+		return os.Rename(oldPath, newPath)
+	}
+	t.Cleanup(func() { renameFile = os.Rename })
+
+	if _, err := InstallFromCache(workDir, cacheDir, manifest, claudeMapper(t), true); err == nil {
+		t.Fatal("InstallFromCache() expected error, got nil")
+	}
+
+	data, err := os.ReadFile(existing)
+	if err != nil || string(data) != "old a" {
+		t.Fatalf("existing skill = %q, %v; want restored %q", data, err, "old a")
+	}
+
+	for _, name := range []string{"b", "c"} {
+		dir := filepath.Join(workDir, ".claude", "skills", name)
+		if _, statErr := os.Stat(dir); !errors.Is(statErr, os.ErrNotExist) {
+			t.Fatalf("skill dir %s should be removed, stat error = %v", name, statErr)
+		}
+	}
+
+	assertNoStagedFiles(t, workDir)
+}
+
+func TestInstallFromCache_StageFailureWritesNothing(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir, manifest := writeCachedSkills(t, "a", "b")
+
+	// A file where skill b's directory should go makes staging b fail.
+	blocker := filepath.Join(workDir, ".claude", "skills", "b")
+	if err := os.MkdirAll(filepath.Dir(blocker), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(blocker, []byte("not a dir"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if _, err := InstallFromCache(workDir, cacheDir, manifest, claudeMapper(t), false); err == nil {
+		t.Fatal("InstallFromCache() expected error, got nil")
+	}
+
+	if _, err := os.Stat(filepath.Join(workDir, ".claude", "skills", "a")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("skill a should not be installed, stat error = %v", err)
+	}
+
+	assertNoStagedFiles(t, workDir)
+}
+
+func TestInstallFromCache_ConflictWritesNothing(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir, manifest := writeCachedSkills(t, "a", "b")
+
+	existing := filepath.Join(workDir, ".claude", "skills", "b", "SKILL.md")
+	if err := os.MkdirAll(filepath.Dir(existing), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(existing, []byte("old b"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	_, err := InstallFromCache(workDir, cacheDir, manifest, claudeMapper(t), false)
+
+	var conflictErr *InstallConflictError
+	if !errors.As(err, &conflictErr) {
+		t.Fatalf("InstallFromCache() error = %v, want InstallConflictError", err)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workDir, ".claude", "skills", "a")); !errors.Is(statErr, os.ErrNotExist) {
+		t.Fatalf("skill a should not be installed, stat error = %v", statErr)
+	}
+}
+
+func TestInstallFromCache_RecoversInterruptedInstall(t *testing.T) {
+	workDir := t.TempDir()
+	target := filepath.Join(workDir, ".claude", "skills", "a", "SKILL.md")
+
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(target, []byte("old a"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	// Simulate a crash after the first file was swapped in.
+	tx := newInstallTxn(workDir)
+	if err := tx.stage(target, []byte("new a")); err != nil {
+		t.Fatalf("stage() error = %v", err)
+	}
+
+	if err := saveInstallJournal(workDir, &tx.journal); err != nil {
+		t.Fatalf("saveInstallJournal() error = %v", err)
+	}
+
+	entry := tx.journal.Entries[0]
+	if err := os.Rename(entry.Target, entry.Backup); err != nil {
+		t.Fatalf("Rename(backup) error = %v", err)
+	}
+
+	if err := os.Rename(entry.Staged, entry.Target); err != nil {
+		t.Fatalf("Rename(staged) error = %v", err)
+	}
+
+	cacheDir, manifest := writeCachedSkills(t, "b")
+	if _, err := InstallFromCache(workDir, cacheDir, manifest, claudeMapper(t), false); err != nil {
+		t.Fatalf("InstallFromCache() error = %v", err)
+	}
+
+	data, err := os.ReadFile(target)
+	if err != nil || string(data) != "old a" {
+		t.Fatalf("interrupted target = %q, %v; want restored %q", data, err, "old a")
+	}
+
+	assertNoStagedFiles(t, workDir)
+}
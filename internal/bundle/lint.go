@@ -0,0 +1,296 @@
+package bundle
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// Lint severities. Errors stop a harness from using an asset; warnings are
+// assets some harnesses accept and others reject or ignore.
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+)
+
+// LintIssue is one problem Lint found in a bundle.
+type LintIssue struct {
+	// Path is the asset's logical path, or empty for the manifest itself.
+	Path     string `json:"path,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// knownAssetTypes are the asset types the provider mappers place.
+var knownAssetTypes = map[string]bool{
+	"skill":            true,
+	"agent_definition": true,
+	"agent_spec":       true,
+	"tool_config":      true,
+	"command":          true,
+	"prompt":           true,
+	"hook":             true,
+	"other":            true,
+	"config":           true,
+	"reference":        true,
+}
+
+// Lint checks a bundle's manifest and cached assets for problems harnesses
+// would hit when loading it: manifest entries that are duplicated, unknown,
+// or missing from the cache, unsafe or unconventional paths, malformed skill
+// and agent frontmatter, and tool configs that do not parse. Issues are
+// sorted by path, manifest-level issues first.
+func Lint(cachePath string, manifest *client.BundleManifest) []LintIssue {
+	var issues []LintIssue
+
+	add := func(logicalPath, severity, format string, args ...any) {
+		issues = append(issues, LintIssue{Path: logicalPath, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if manifest == nil || len(manifest.Layers) == 0 {
+		add("", LintError, "manifest lists no assets")
+		return issues
+	}
+
+	seen := make(map[string]bool, len(manifest.Layers))
+
+	for _, layer := range manifest.Layers {
+		logicalPath := layer.LogicalPath
+
+		if seen[logicalPath] {
+			add(logicalPath, LintError, "listed more than once in the manifest")
+			continue
+		}
+
+		seen[logicalPath] = true
+
+		if err := ValidateLogicalPath(logicalPath); err != nil {
+			add(logicalPath, LintError, "%v", err)
+			continue
+		}
+
+		if !knownAssetTypes[layer.AssetType] {
+			add(logicalPath, LintError, "unknown asset type %q", layer.AssetType)
+			continue
+		}
+
+		data, err := safeio.ReadFile(filepath.Join(cachePath, "assets", logicalPath))
+		if err != nil {
+			add(logicalPath, LintError, "listed in the manifest but not readable: %v", err)
+			continue
+		}
+
+		if layer.ContentSHA256 != "" && layer.ContentSHA256 != fmt.Sprintf("%x", sha256.Sum256(data)) {
+			add(logicalPath, LintError, "content does not match the manifest checksum")
+		}
+
+		for _, issue := range lintAsset(layer.AssetType, logicalPath, data) {
+			issue.Path = logicalPath
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Path < issues[j].Path
+	})
+
+	return issues
+}
+
+func lintAsset(assetType, logicalPath string, data []byte) []LintIssue {
+	switch assetType {
+	case "skill":
+		return lintSkill(logicalPath, data)
+	case "agent_definition", "agent_spec":
+		return lintAgent(logicalPath, data)
+	case "tool_config":
+		return lintToolConfig(logicalPath, data)
+	case "hook":
+		return lintHookConfig(logicalPath, data)
+	default:
+		return nil
+	}
+}
+
+func lintSkill(logicalPath string, data []byte) []LintIssue {
+	base := path.Base(filepath.ToSlash(logicalPath))
+	if !strings.EqualFold(path.Ext(base), ".md") {
+		// Command-style skills (e.g. Gemini .toml commands) have no frontmatter.
+		return nil
+	}
+
+	var issues []LintIssue
+
+	if base != "SKILL.md" {
+		issues = append(issues, LintIssue{
+			Severity: LintWarning,
+			Message:  "skills are expected at <name>/SKILL.md; harnesses that discover skills by directory will miss this file",
+		})
+	}
+
+	if extractFrontmatter(data) == nil {
+		return append(issues, LintIssue{
+			Severity: LintWarning,
+			Message:  "no YAML frontmatter; harnesses use its name and description to decide when to load the skill",
+		})
+	}
+
+	if err := ValidateSkillFrontmatter(data); err != nil {
+		if _, ok := RepairSkillFrontmatter(data); ok {
+			return append(issues, LintIssue{
+				Severity: LintWarning,
+				Message:  "YAML frontmatter has unquoted values containing colons; mush repairs it when loading, but installs and other tools see the original (run 'mush bundle fix-frontmatter')",
+			})
+		}
+
+		return append(issues, LintIssue{
+			Severity: LintError,
+			Message:  fmt.Sprintf("%v; strict harnesses (e.g. Codex) will reject this skill", err),
+		})
+	}
+
+	return append(issues, missingFrontmatterKeys(data, LintWarning, "name", "description")...)
+}
+
+func lintAgent(logicalPath string, data []byte) []LintIssue {
+	if !strings.EqualFold(path.Ext(filepath.ToSlash(logicalPath)), ".md") {
+		return []LintIssue{{
+			Severity: LintWarning,
+			Message:  "agent definitions are expected to be Markdown files",
+		}}
+	}
+
+	if extractFrontmatter(data) == nil {
+		return []LintIssue{{
+			Severity: LintError,
+			Message:  "no YAML frontmatter; harnesses skip agents without a name and description",
+		}}
+	}
+
+	if err := ValidateSkillFrontmatter(data); err != nil {
+		return []LintIssue{{Severity: LintError, Message: err.Error()}}
+	}
+
+	return missingFrontmatterKeys(data, LintError, "name", "description")
+}
+
+// missingFrontmatterKeys reports each of keys absent or empty in valid
+// frontmatter.
+func missingFrontmatterKeys(data []byte, severity string, keys ...string) []LintIssue {
+	var fields map[string]any
+	if err := yaml.Unmarshal(extractFrontmatter(data), &fields); err != nil {
+		return []LintIssue{{Severity: severity, Message: "YAML frontmatter is not a mapping"}}
+	}
+
+	var issues []LintIssue
+
+	for _, key := range keys {
+		if value, ok := fields[key]; !ok || value == nil || value == "" {
+			issues = append(issues, LintIssue{
+				Severity: severity,
+				Message:  fmt.Sprintf("frontmatter is missing %q", key),
+			})
+		}
+	}
+
+	return issues
+}
+
+func lintToolConfig(logicalPath string, data []byte) []LintIssue {
+	doc := map[string]any{}
+
+	switch strings.ToLower(path.Ext(filepath.ToSlash(logicalPath))) {
+	case ".json":
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return []LintIssue{{Severity: LintError, Message: fmt.Sprintf("invalid JSON: %v", err)}}
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &doc); err != nil {
+			return []LintIssue{{Severity: LintError, Message: fmt.Sprintf("invalid TOML: %v", err)}}
+		}
+	default:
+		return []LintIssue{{
+			Severity: LintWarning,
+			Message:  "tool configs are expected to be .json or .toml; other formats are appended without merging",
+		}}
+	}
+
+	var issues []LintIssue
+
+	// Claude and Gemini use mcpServers; Codex uses mcp_servers.
+	for _, key := range []string{"mcpServers", "mcp_servers"} {
+		raw, ok := doc[key]
+		if !ok {
+			continue
+		}
+
+		servers, ok := raw.(map[string]any)
+		if !ok {
+			issues = append(issues, LintIssue{Severity: LintError, Message: fmt.Sprintf("%s must be an object of servers", key)})
+			continue
+		}
+
+		names := make([]string, 0, len(servers))
+		for name := range servers {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			server, ok := servers[name].(map[string]any)
+			if !ok {
+				issues = append(issues, LintIssue{Severity: LintError, Message: fmt.Sprintf("MCP server %q must be an object", name)})
+				continue
+			}
+
+			if server["command"] == nil && server["url"] == nil && server["httpUrl"] == nil {
+				issues = append(issues, LintIssue{
+					Severity: LintError,
+					Message:  fmt.Sprintf("MCP server %q has neither a command nor a url", name),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// HasLintErrors reports whether any issue is an error.
+func HasLintErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == LintError {
+			return true
+		}
+	}
+
+	return false
+}
+
+func lintHookConfig(logicalPath string, data []byte) []LintIssue {
+	doc := map[string]any{}
+
+	if !strings.EqualFold(path.Ext(filepath.ToSlash(logicalPath)), ".json") {
+		return []LintIssue{{Severity: LintError, Message: "hooks are merged into JSON settings and must be .json"}}
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []LintIssue{{Severity: LintError, Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+
+	if _, ok := doc["hooks"].(map[string]any); !ok {
+		return []LintIssue{{Severity: LintWarning, Message: "no \"hooks\" object; the file is merged into settings but defines no hooks"}}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package bundle
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestLint(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	writeCachedAsset(t, cacheDir, "skills/web/SKILL.md", "---\nname: web\ndescription: Browse the web\n---\nBody\n")
+	writeCachedAsset(t, cacheDir, "skills/deploy/SKILL.md", "---\nname: deploy\ndescription: Deploy: to prod\n---\nBody\n")
+	writeCachedAsset(t, cacheDir, "skills/notes.md", "Just notes\n")
+	writeCachedAsset(t, cacheDir, "agents/reviewer.md", "---\nname: reviewer\n---\nReview code\n")
+	writeCachedAsset(t, cacheDir, "tools/mcp.json", `{"mcpServers":{"linear":{"url":"https://mcp.linear.app"},"broken":{}}}`)
+	writeCachedAsset(t, cacheDir, "tools/config.toml", "model = \n")
+
+	issues := Lint(cacheDir, &client.BundleManifest{
+		Layers: []client.BundleLayer{
+			{LogicalPath: "skills/web/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "skills/deploy/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "skills/notes.md", AssetType: "skill"},
+			{LogicalPath: "agents/reviewer.md", AssetType: "agent_definition"},
+			{LogicalPath: "tools/mcp.json", AssetType: "tool_config"},
+			{LogicalPath: "tools/config.toml", AssetType: "tool_config"},
+			{LogicalPath: "tools/mcp.json", AssetType: "tool_config"},
+			{LogicalPath: "skills/missing/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "../escape.md", AssetType: "skill"},
+			{LogicalPath: "readme.txt", AssetType: "banner"},
+		},
+	})
+
+	want := []struct {
+		path, severity, fragment string
+	}{
+		{"../escape.md", LintError, "escape"},
+		{"agents/reviewer.md", LintError, `missing "description"`},
+		{"readme.txt", LintError, `unknown asset type "banner"`},
+		{"skills/deploy/SKILL.md", LintWarning, "repairs it when loading"},
+		{"skills/missing/SKILL.md", LintError, "not readable"},
+		{"skills/notes.md", LintWarning, "<name>/SKILL.md"},
+		{"skills/notes.md", LintWarning, "no YAML frontmatter"},
+		{"tools/config.toml", LintError, "invalid TOML"},
+		{"tools/mcp.json", LintError, `"broken" has neither a command nor a url`},
+		{"tools/mcp.json", LintError, "more than once"},
+	}
+
+	if len(issues) != len(want) {
+		t.Fatalf("Lint() returned %d issues, want %d: %+v", len(issues), len(want), issues)
+	}
+
+	for i, w := range want {
+		got := issues[i]
+		if got.Path != w.path || got.Severity != w.severity || !strings.Contains(got.Message, w.fragment) {
+			t.Errorf("issue %d = %+v, want %s %s containing %q", i, got, w.path, w.severity, w.fragment)
+		}
+	}
+
+	if !HasLintErrors(issues) {
+		t.Fatal("HasLintErrors() = false, want true")
+	}
+}
+
+func TestLint_CleanBundle(t *testing.T) {
+	cacheDir := t.TempDir()
+
+	writeCachedAsset(t, cacheDir, "skills/web/SKILL.md", "---\nname: web\ndescription: Browse the web\n---\n")
+	writeCachedAsset(t, cacheDir, "commands/review.toml", "prompt = \"review\"\n")
+
+	issues := Lint(cacheDir, &client.BundleManifest{
+		Layers: []client.BundleLayer{
+			{LogicalPath: "skills/web/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "commands/review.toml", AssetType: "skill"},
+		},
+	})
+	if len(issues) != 0 {
+		t.Fatalf("Lint() = %+v, want no issues", issues)
+	}
+
+	if issues := Lint(cacheDir, &client.BundleManifest{}); len(issues) != 1 || issues[0].Path != "" {
+		t.Fatalf("Lint(empty manifest) = %+v, want one manifest issue", issues)
+	}
+}
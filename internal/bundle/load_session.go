@@ -3,6 +3,7 @@ package bundle
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/harness/harnesstype"
@@ -11,6 +12,7 @@ import (
 // LoadSession describes how an ephemeral bundle load should be launched.
 type LoadSession struct {
 	BundleDir  string
+	ExtraDirs  []string
 	WorkingDir string
 	Env        []string
 	Prepared   []string
@@ -113,6 +115,51 @@ func PrepareLoadSession(
 	}
 }
 
+// Reload replaces the session's assets with those of a freshly resolved
+// bundle, keeping its extra directories and environment.
+//
+// An external bundle directory keeps its path, so the harness's --add-dir
+// arguments stay valid and harnesses that watch their asset directories see
+// the new files without restarting. Assets injected into the working
+// directory are removed and injected again from the new manifest.
+func (s *LoadSession) Reload(
+	ctx context.Context,
+	cachePath string,
+	manifest *client.BundleManifest,
+	spec *harnesstype.ProviderSpec,
+	mapper AssetMapper,
+) error {
+	bundleDir := s.BundleDir
+	s.Cleanup()
+	s.cleanup = nil
+
+	next, err := PrepareLoadSession(ctx, s.WorkingDir, cachePath, manifest, spec, mapper)
+	if err != nil {
+		return err
+	}
+
+	if bundleDir != "" && bundleDir != s.WorkingDir && next.BundleDir != bundleDir {
+		if err := os.Rename(next.BundleDir, bundleDir); err != nil {
+			next.Cleanup()
+			return fmt.Errorf("replace bundle directory: %w", err)
+		}
+
+		next.cleanup = chainCleanup(func() { _ = os.RemoveAll(bundleDir) }, next.cleanup)
+		next.BundleDir = bundleDir
+	}
+
+	s.BundleDir = next.BundleDir
+	s.Prepared = next.Prepared
+	s.Warnings = next.Warnings
+	s.cleanup = next.cleanup
+
+	if len(s.ExtraDirs) > 0 {
+		s.Warnings = append(s.Warnings, shadowedAssets(spec, append([]string{s.BundleDir}, s.ExtraDirs...))...)
+	}
+
+	return nil
+}
+
 func chainCleanup(cleanups ...func()) func() {
 	return func() {
 		for i := len(cleanups) - 1; i >= 0; i-- {
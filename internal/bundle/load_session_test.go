@@ -140,6 +140,110 @@ func TestPrepareLoadSession_CWDHarnessFallsBackToProjectInjection(t *testing.T)
 	}
 }
 
+func TestLoadSessionReload_KeepsBundleDir(t *testing.T) {
+	projectDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	writeCachedAsset(t, cacheDir, "skills/web/SKILL.md", "v1")
+
+	spec, ok := harness.GetProvider("claude")
+	if !ok {
+		t.Fatal("claude provider not found")
+	}
+
+	mapper := NewProviderMapper(spec)
+
+	session, err := PrepareLoadSession(t.Context(), projectDir, cacheDir, &client.BundleManifest{
+		Layers: []client.BundleLayer{{LogicalPath: "skills/web/SKILL.md", AssetType: "skill"}},
+	}, spec, mapper)
+	if err != nil {
+		t.Fatalf("PrepareLoadSession() error = %v", err)
+	}
+
+	t.Cleanup(session.Cleanup)
+
+	bundleDir := session.BundleDir
+
+	nextCache := t.TempDir()
+	writeCachedAsset(t, nextCache, "skills/api/SKILL.md", "v2")
+	writeCachedAsset(t, nextCache, "researcher.md", "agent")
+
+	err = session.Reload(t.Context(), nextCache, &client.BundleManifest{
+		Layers: []client.BundleLayer{
+			{LogicalPath: "skills/api/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "researcher.md", AssetType: "agent_definition"},
+		},
+	}, spec, mapper)
+	if err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	if session.BundleDir != bundleDir {
+		t.Fatalf("BundleDir = %q, want it kept at %q", session.BundleDir, bundleDir)
+	}
+
+	if _, err := os.Stat(filepath.Join(bundleDir, ".claude", "skills", "web")); !os.IsNotExist(err) {
+		t.Fatalf("removed skill still present; stat err = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(bundleDir, ".claude", "skills", "api", "SKILL.md")); err != nil {
+		t.Fatalf("expected reloaded skill in bundle dir: %v", err)
+	}
+
+	if len(session.Prepared) != 1 {
+		t.Fatalf("Prepared = %v, want the reloaded agent", session.Prepared)
+	}
+
+	session.Cleanup()
+
+	if _, err := os.Stat(bundleDir); !os.IsNotExist(err) {
+		t.Fatalf("cleanup should remove the reloaded bundle dir; stat err = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(projectDir, session.Prepared[0])); !os.IsNotExist(err) {
+		t.Fatalf("cleanup should remove the reloaded agent; stat err = %v", err)
+	}
+}
+
+func TestLoadSessionReload_CWDHarnessReinjectsChangedAssets(t *testing.T) {
+	projectDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	writeCachedAsset(t, cacheDir, "commands/review.toml", `prompt = "v1"`)
+
+	spec, ok := harness.GetProvider("gemini")
+	if !ok {
+		t.Fatal("gemini provider not found")
+	}
+
+	mapper := NewProviderMapper(spec)
+	manifest := &client.BundleManifest{
+		Layers: []client.BundleLayer{{LogicalPath: "commands/review.toml", AssetType: "skill"}},
+	}
+
+	session, err := PrepareLoadSession(t.Context(), projectDir, cacheDir, manifest, spec, mapper)
+	if err != nil {
+		t.Fatalf("PrepareLoadSession() error = %v", err)
+	}
+
+	t.Cleanup(session.Cleanup)
+
+	writeCachedAsset(t, cacheDir, "commands/review.toml", `prompt = "v2"`)
+
+	if err := session.Reload(t.Context(), cacheDir, manifest, spec, mapper); err != nil {
+		t.Fatalf("Reload() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(projectDir, ".gemini", "commands", "review.toml"))
+	if err != nil {
+		t.Fatalf("read reloaded skill: %v", err)
+	}
+
+	if string(data) != `prompt = "v2"` {
+		t.Fatalf("skill = %q, want the reloaded content", data)
+	}
+}
+
 func writeCachedAsset(t *testing.T, cacheDir, rel, data string) {
 	t.Helper()
 
@@ -220,6 +220,12 @@ func inferAssetType(relPath string) string {
 		return "skill"
 	case strings.HasPrefix(normalized, "agents/") || base == "AGENT.md":
 		return "agent_definition"
+	case strings.HasPrefix(normalized, "commands/"):
+		return "command"
+	case strings.HasPrefix(normalized, "prompts/"):
+		return "prompt"
+	case strings.HasPrefix(normalized, "hooks/") && strings.HasSuffix(base, ".json"):
+		return "hook"
 	case base == ".mcp.json" || base == "mcp.json" ||
 		strings.HasPrefix(normalized, "tools/") ||
 		(strings.HasSuffix(base, ".toml") && strings.Contains(normalized, "tools")):
@@ -228,6 +228,10 @@ func TestInferAssetType(t *testing.T) {
 		{"mcp.json", "tool_config"},
 		{"tools/server.toml", "tool_config"},
 		{"tools/config.json", "tool_config"},
+		{"commands/review.md", "command"},
+		{"prompts/summary.md", "prompt"},
+		{"hooks/format.json", "hook"},
+		{"hooks/README.md", ""},
 		{"README.md", ""},
 		{"src/main.go", ""},
 		{"random.txt", ""},
@@ -79,8 +79,8 @@ func prepareLoadCommon(mapper AssetMapper, cachePath string, manifest *client.Bu
 	toolConfigs := map[string][][]byte{}
 
 	for i := range assets {
-		switch assets[i].layer.AssetType {
-		case "tool_config":
+		switch {
+		case mergedAssetTypes[assets[i].layer.AssetType]:
 			toolConfigs[assets[i].targetPath] = append(toolConfigs[assets[i].targetPath], assets[i].data)
 		default:
 			if mkErr := safeio.MkdirAll(filepath.Dir(assets[i].targetPath), 0o755); mkErr != nil {
@@ -116,6 +116,13 @@ func prepareLoadCommon(mapper AssetMapper, cachePath string, manifest *client.Bu
 	return tmpDir, cleanup, nil
 }
 
+// mergedAssetTypes are asset types merged into a shared settings file
+// rather than written as files of their own.
+var mergedAssetTypes = map[string]bool{
+	"tool_config": true,
+	"hook":        true,
+}
+
 func mergeToolConfigDocuments(existing []byte, docs [][]byte, targetPath string) ([]byte, error) {
 	switch {
 	case strings.HasSuffix(targetPath, ".json"):
@@ -38,6 +38,21 @@ func TestProviderMapper_Claude_MapAsset(t *testing.T) {
 			layer: client.BundleLayer{AssetType: "tool_config", LogicalPath: "mcp.json"},
 			want:  filepath.Join(workDir, ".mcp.json"),
 		},
+		{
+			name:  "command",
+			layer: client.BundleLayer{AssetType: "command", LogicalPath: "commands/review.md"},
+			want:  filepath.Join(workDir, ".claude", "commands", "review.md"),
+		},
+		{
+			name:  "hook merges into settings",
+			layer: client.BundleLayer{AssetType: "hook", LogicalPath: "hooks/format.json"},
+			want:  filepath.Join(workDir, ".claude", "settings.json"),
+		},
+		{
+			name:  "prompt without prompt dir keeps logical path",
+			layer: client.BundleLayer{AssetType: "prompt", LogicalPath: "prompts/summary.md"},
+			want:  filepath.Join(workDir, "prompts", "summary.md"),
+		},
 		{
 			name:    "unknown type",
 			layer:   client.BundleLayer{AssetType: "unknown", LogicalPath: "test.txt"},
@@ -102,6 +117,16 @@ func TestProviderMapper_Codex_MapAsset(t *testing.T) {
 			layer: client.BundleLayer{AssetType: "tool_config", LogicalPath: "config.toml"},
 			want:  filepath.Join(workDir, ".codex", "config.toml"),
 		},
+		{
+			name:  "command goes to prompts dir",
+			layer: client.BundleLayer{AssetType: "command", LogicalPath: "commands/review.md"},
+			want:  filepath.Join(workDir, ".codex", "prompts", "review.md"),
+		},
+		{
+			name:    "hook unsupported",
+			layer:   client.BundleLayer{AssetType: "hook", LogicalPath: "hooks/format.json"},
+			wantErr: true,
+		},
 		{
 			name:    "unknown type",
 			layer:   client.BundleLayer{AssetType: "unknown", LogicalPath: "test.txt"},
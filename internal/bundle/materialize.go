@@ -11,15 +11,26 @@ import (
 	"github.com/musher-dev/mush/internal/safeio"
 )
 
+// InstallResult describes what InstallFromCache wrote.
+type InstallResult struct {
+	// Paths lists installed files relative to workDir, sorted.
+	Paths []string
+
+	// MergedConfigs lists the keys merged into shared tool config files.
+	MergedConfigs []MergedConfig
+}
+
 // InstallFromCache installs bundle assets into workDir using mapper rules.
-// It performs merge semantics for tool_config assets.
+// It performs merge semantics for tool_config and hook assets. Files are staged and
+// committed together: if any write fails, files already written are rolled
+// back and files they replaced are restored.
 func InstallFromCache(
 	workDir string,
 	cachePath string,
 	manifest *client.BundleManifest,
 	mapper AssetMapper,
 	force bool,
-) ([]string, error) {
+) (*InstallResult, error) {
 	type mappedAsset struct {
 		layer      client.BundleLayer
 		targetPath string
@@ -48,37 +59,29 @@ func InstallFromCache(
 		})
 	}
 
+	// Work out every file's final content and check conflicts before
+	// touching the project, so a refused install changes nothing.
+	planned := map[string][]byte{}
 	toolConfigs := map[string][][]byte{}
-	installed := map[string]struct{}{}
 
 	for i := range assets {
-		switch assets[i].layer.AssetType {
-		case "tool_config":
+		switch {
+		case mergedAssetTypes[assets[i].layer.AssetType]:
 			toolConfigs[assets[i].targetPath] = append(toolConfigs[assets[i].targetPath], assets[i].data)
 		default:
 			if !force {
-				if _, statErr := os.Stat(assets[i].targetPath); statErr == nil {
+				_, seen := planned[assets[i].targetPath]
+				if _, statErr := os.Stat(assets[i].targetPath); statErr == nil || seen {
 					return nil, &InstallConflictError{Path: assets[i].targetPath}
 				}
 			}
 
-			if mkErr := safeio.MkdirAll(filepath.Dir(assets[i].targetPath), 0o755); mkErr != nil {
-				return nil, fmt.Errorf("create directory for %s: %w", assets[i].targetPath, mkErr)
-			}
-
-			if writeErr := safeio.WriteFile(assets[i].targetPath, assets[i].data, 0o644); writeErr != nil {
-				return nil, fmt.Errorf("write %s: %w", assets[i].targetPath, writeErr)
-			}
-
-			relPath, _ := filepath.Rel(workDir, assets[i].targetPath)
-			if relPath == "" {
-				relPath = assets[i].targetPath
-			}
-
-			installed[relPath] = struct{}{}
+			planned[assets[i].targetPath] = assets[i].data
 		}
 	}
 
+	var mergedConfigs []MergedConfig
+
 	for targetPath, docs := range toolConfigs {
 		var existing []byte
 		if data, readErr := safeio.ReadFile(targetPath); readErr == nil {
@@ -90,30 +93,39 @@ func InstallFromCache(
 			return nil, mergeErr
 		}
 
-		if mkErr := safeio.MkdirAll(filepath.Dir(targetPath), 0o755); mkErr != nil {
-			return nil, fmt.Errorf("create directory for %s: %w", targetPath, mkErr)
+		keys, keysErr := toolConfigAdditions(existing, docs, targetPath)
+		if keysErr != nil {
+			return nil, keysErr
 		}
 
-		if writeErr := safeio.WriteFile(targetPath, merged, 0o644); writeErr != nil {
-			return nil, fmt.Errorf("write merged tool config %s: %w", targetPath, writeErr)
-		}
-
-		relPath, _ := filepath.Rel(workDir, targetPath)
-		if relPath == "" {
-			relPath = targetPath
-		}
+		planned[targetPath] = merged
+		mergedConfigs = append(mergedConfigs, MergedConfig{Path: installedRelPath(workDir, targetPath), Keys: keys})
+	}
 
-		installed[relPath] = struct{}{}
+	if err := writeInstallFiles(workDir, planned); err != nil {
+		return nil, err
 	}
 
-	paths := make([]string, 0, len(installed))
-	for p := range installed {
-		paths = append(paths, p)
+	paths := make([]string, 0, len(planned))
+	for targetPath := range planned {
+		paths = append(paths, installedRelPath(workDir, targetPath))
 	}
 
 	sort.Strings(paths)
+	sort.Slice(mergedConfigs, func(i, j int) bool { return mergedConfigs[i].Path < mergedConfigs[j].Path })
 
-	return paths, nil
+	return &InstallResult{Paths: paths, MergedConfigs: mergedConfigs}, nil
+}
+
+// installedRelPath returns targetPath relative to workDir, as recorded in
+// installed.json.
+func installedRelPath(workDir, targetPath string) string {
+	relPath, _ := filepath.Rel(workDir, targetPath)
+	if relPath == "" {
+		relPath = targetPath
+	}
+
+	return relPath
 }
 
 // discoveredAssetTypes are asset types that harnesses discover from the project
@@ -122,10 +134,11 @@ func InstallFromCache(
 var discoveredAssetTypes = map[string]bool{
 	"agent_definition": true,
 	"agent_spec":       true,
+	"command":          true,
 	"skill":            true,
 }
 
-// InjectAssetsForLoad copies discoverable assets (agents, skills) from cache
+// InjectAssetsForLoad copies discoverable assets (agents, skills, commands) from cache
 // into the project directory so the harness discovers them. Tool configs are
 // excluded because they are handled separately via merge logic and --mcp-config.
 // It skips files that already exist (protecting user's own assets). Returns the
@@ -181,21 +194,9 @@ func InjectAssetsForLoad(
 
 		// Validate and auto-repair YAML frontmatter for skill assets.
 		if layer.AssetType == "skill" {
-			if fmErr := ValidateSkillFrontmatter(data); fmErr != nil {
-				repaired, wasRepaired := RepairSkillFrontmatter(data)
-				if wasRepaired {
-					data = repaired
-
-					warnings = append(warnings, fmt.Sprintf(
-						"%s: auto-repaired YAML frontmatter (unquoted values with colons were quoted)",
-						layer.LogicalPath,
-					))
-				} else {
-					warnings = append(warnings, fmt.Sprintf(
-						"%s: %v; strict harnesses (e.g. Codex) will reject this skill",
-						layer.LogicalPath, fmErr,
-					))
-				}
+			var warning string
+			if data, warning = repairSkillForLoad(layer.LogicalPath, data); warning != "" {
+				warnings = append(warnings, warning)
 			}
 		}
 
@@ -57,8 +57,8 @@ func TestInstallFromCache_CodexIndividualAgentsAndToolConfig(t *testing.T) {
 		t.Fatalf("InstallFromCache() error = %v", err)
 	}
 
-	if len(installed) != 4 {
-		t.Fatalf("InstallFromCache() installed %d paths, want 4", len(installed))
+	if len(installed.Paths) != 4 {
+		t.Fatalf("InstallFromCache() installed %d paths, want 4", len(installed.Paths))
 	}
 
 	// Verify individual agent files (agents/ prefix is stripped by stripMatchingPrefix).
@@ -712,3 +712,56 @@ func TestInjectToolConfigsForLoad_NoToolConfigs(t *testing.T) {
 		t.Fatalf("InjectToolConfigsForLoad() injected %d paths, want 0", len(injected))
 	}
 }
+
+func TestInstallFromCache_ClaudeCommandAndHook(t *testing.T) {
+	workDir := t.TempDir()
+	cacheDir := t.TempDir()
+
+	write := func(path, data string) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("MkdirAll(%s) error = %v", path, err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatalf("WriteFile(%s) error = %v", path, err)
+		}
+	}
+
+	write(filepath.Join(cacheDir, "assets", "commands", "review.md"), "Review the diff.")
+	write(filepath.Join(cacheDir, "assets", "hooks", "format.json"), `{"hooks":{"PostToolUse":[{"matcher":"Edit"}]}}`)
+	write(filepath.Join(workDir, ".claude", "settings.json"), `{"model":"opus"}`)
+
+	manifest := &client.BundleManifest{
+		Layers: []client.BundleLayer{
+			{LogicalPath: "commands/review.md", AssetType: "command"},
+			{LogicalPath: "hooks/format.json", AssetType: "hook"},
+		},
+	}
+
+	claudeSpec, ok := harness.GetProvider("claude")
+	if !ok {
+		t.Fatal("claude provider not found")
+	}
+
+	result, err := InstallFromCache(workDir, cacheDir, manifest, NewProviderMapper(claudeSpec), false)
+	if err != nil {
+		t.Fatalf("InstallFromCache() error = %v", err)
+	}
+
+	if _, statErr := os.Stat(filepath.Join(workDir, ".claude", "commands", "review.md")); statErr != nil {
+		t.Fatalf("command not installed: %v", statErr)
+	}
+
+	settings, err := os.ReadFile(filepath.Join(workDir, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("ReadFile(settings.json) error = %v", err)
+	}
+
+	if s := string(settings); !strings.Contains(s, `"model"`) || !strings.Contains(s, `"PostToolUse"`) {
+		t.Fatalf("settings.json = %s, want user settings kept and hooks merged", s)
+	}
+
+	if len(result.MergedConfigs) != 1 || strings.Join(result.MergedConfigs[0].Keys[0], ".") != "hooks" {
+		t.Fatalf("MergedConfigs = %+v, want the hooks key recorded", result.MergedConfigs)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -102,3 +104,135 @@ func mergeMaps(dst, src map[string]any) {
 		}
 	}
 }
+
+// addedKeyPaths returns the key paths src adds to dst. A key dst lacks is
+// reported whole, so a new section is one path; keys that only overwrite a
+// value dst already has are not reported.
+func addedKeyPaths(dst, src map[string]any, prefix []string) [][]string {
+	var added [][]string
+
+	for k, v := range src {
+		path := append(append([]string(nil), prefix...), k)
+
+		existing, ok := dst[k]
+		if !ok {
+			added = append(added, path)
+			continue
+		}
+
+		srcMap, srcIsMap := v.(map[string]any)
+		dstMap, dstIsMap := existing.(map[string]any)
+
+		if srcIsMap && dstIsMap {
+			added = append(added, addedKeyPaths(dstMap, srcMap, path)...)
+		}
+	}
+
+	return added
+}
+
+// removeKeyPath deletes path from m and then any maps on the way that it
+// left empty.
+func removeKeyPath(m map[string]any, path []string) {
+	if len(path) == 0 {
+		return
+	}
+
+	if len(path) == 1 {
+		delete(m, path[0])
+		return
+	}
+
+	child, ok := m[path[0]].(map[string]any)
+	if !ok {
+		return
+	}
+
+	removeKeyPath(child, path[1:])
+
+	if len(child) == 0 {
+		delete(m, path[0])
+	}
+}
+
+// toolConfigAdditions returns the key paths docs add to an existing JSON or
+// TOML tool config, so uninstall can take them out again. Other formats
+// report none.
+func toolConfigAdditions(existing []byte, docs [][]byte, targetPath string) ([][]string, error) {
+	unmarshal := toolConfigUnmarshaler(targetPath)
+	if unmarshal == nil {
+		return nil, nil
+	}
+
+	merged := map[string]any{}
+	if err := unmarshal(existing, merged); err != nil {
+		return nil, fmt.Errorf("parse tool config %s: %w", targetPath, err)
+	}
+
+	var added [][]string
+
+	for i, doc := range docs {
+		next := map[string]any{}
+		if err := unmarshal(doc, next); err != nil {
+			return nil, fmt.Errorf("parse tool config %s doc %d: %w", targetPath, i+1, err)
+		}
+
+		added = append(added, addedKeyPaths(merged, next, nil)...)
+		mergeMaps(merged, next)
+	}
+
+	sort.Slice(added, func(i, j int) bool {
+		return strings.Join(added[i], "\x00") < strings.Join(added[j], "\x00")
+	})
+
+	return added, nil
+}
+
+// unmergeToolConfig removes key paths from a JSON or TOML tool config. It
+// reports empty when nothing is left, in which case out is nil.
+func unmergeToolConfig(data []byte, keys [][]string, targetPath string) (out []byte, empty bool, err error) {
+	unmarshal := toolConfigUnmarshaler(targetPath)
+	if unmarshal == nil {
+		return data, false, nil
+	}
+
+	config := map[string]any{}
+	if err := unmarshal(data, config); err != nil {
+		return nil, false, fmt.Errorf("parse tool config %s: %w", targetPath, err)
+	}
+
+	for _, key := range keys {
+		removeKeyPath(config, key)
+	}
+
+	if len(config) == 0 {
+		return nil, true, nil
+	}
+
+	if strings.HasSuffix(targetPath, ".json") {
+		out, err = json.MarshalIndent(config, "", "  ")
+		if err != nil {
+			return nil, false, fmt.Errorf("marshal tool config %s: %w", targetPath, err)
+		}
+
+		return append(out, '\n'), false, nil
+	}
+
+	out, err = toml.Marshal(config)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal tool config %s: %w", targetPath, err)
+	}
+
+	return out, false, nil
+}
+
+func toolConfigUnmarshaler(targetPath string) func([]byte, map[string]any) error {
+	switch {
+	case strings.HasSuffix(targetPath, ".json"):
+		return unmarshalJSONObject
+	case strings.HasSuffix(targetPath, ".toml"):
+		return unmarshalTOMLObject
+	default:
+		return nil
+	}
+}
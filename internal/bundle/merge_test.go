@@ -39,3 +39,55 @@ func TestMergeTOMLDocs(t *testing.T) {
 		t.Fatalf("merged toml missing expected sections: %s", s)
 	}
 }
+
+func TestToolConfigAdditionsAndUnmerge(t *testing.T) {
+	existing := []byte("[mcp_servers.alpha]\ncommand = \"a\"\n")
+	docs := [][]byte{
+		[]byte("[mcp_servers.beta]\ncommand = \"b\"\n"),
+		[]byte("[mcp_servers.alpha]\ncommand = \"a2\"\nargs = [\"--x\"]\n"),
+	}
+
+	keys, err := toolConfigAdditions(existing, docs, "config.toml")
+	if err != nil {
+		t.Fatalf("toolConfigAdditions() error = %v", err)
+	}
+
+	want := "mcp_servers.alpha.args,mcp_servers.beta"
+
+	got := make([]string, 0, len(keys))
+	for _, key := range keys {
+		got = append(got, strings.Join(key, "."))
+	}
+
+	if strings.Join(got, ",") != want {
+		t.Fatalf("toolConfigAdditions() = %v, want %s", got, want)
+	}
+
+	merged, err := MergeTOMLDocs(existing, docs)
+	if err != nil {
+		t.Fatalf("MergeTOMLDocs() error = %v", err)
+	}
+
+	out, empty, err := unmergeToolConfig(merged, keys, "config.toml")
+	if err != nil {
+		t.Fatalf("unmergeToolConfig() error = %v", err)
+	}
+
+	s := string(out)
+	if empty || !strings.Contains(s, "[mcp_servers.alpha]") || strings.Contains(s, "beta") || strings.Contains(s, "args") {
+		t.Fatalf("unmerged toml = %q (empty=%v), want only alpha without args", s, empty)
+	}
+}
+
+func TestUnmergeToolConfigReportsEmpty(t *testing.T) {
+	data := []byte(`{"mcpServers":{"beta":{"command":"b"}}}`)
+
+	out, empty, err := unmergeToolConfig(data, [][]string{{"mcpServers", "beta"}}, ".mcp.json")
+	if err != nil {
+		t.Fatalf("unmergeToolConfig() error = %v", err)
+	}
+
+	if !empty || out != nil {
+		t.Fatalf("unmergeToolConfig() = %q, empty=%v; want nil, true", out, empty)
+	}
+}
@@ -38,7 +38,25 @@ func (m *providerMapper) MapAsset(workDir string, layer *client.BundleLayer) (st
 		return filepath.Join(workDir, assets.AgentDir, stripMatchingPrefix(assets.AgentDir, layer.LogicalPath)), nil
 	case "tool_config":
 		return filepath.Join(workDir, assets.ToolConfigFile), nil
-	case "other", "config", "reference", "prompt":
+	case "command":
+		if assets.CommandDir == "" {
+			return "", fmt.Errorf("provider %s does not support command assets", m.spec.Name)
+		}
+
+		return filepath.Join(workDir, assets.CommandDir, stripAssetPrefix(assets.CommandDir, "commands", layer.LogicalPath)), nil
+	case "prompt":
+		if assets.PromptDir == "" {
+			return filepath.Join(workDir, layer.LogicalPath), nil
+		}
+
+		return filepath.Join(workDir, assets.PromptDir, stripAssetPrefix(assets.PromptDir, "prompts", layer.LogicalPath)), nil
+	case "hook":
+		if assets.HookConfigFile == "" {
+			return "", fmt.Errorf("provider %s does not support hook assets", m.spec.Name)
+		}
+
+		return filepath.Join(workDir, assets.HookConfigFile), nil
+	case "other", "config", "reference":
 		return filepath.Join(workDir, layer.LogicalPath), nil
 	default:
 		return "", fmt.Errorf("unsupported asset type for %s: %s", m.spec.Name, layer.AssetType)
@@ -66,6 +84,17 @@ func stripMatchingPrefix(dir, logicalPath string) string {
 	return logicalPath
 }
 
+// stripAssetPrefix is stripMatchingPrefix that also drops the asset type's
+// conventional directory (e.g. "commands/"), for providers whose directory
+// is named differently, such as Codex's .codex/prompts for commands.
+func stripAssetPrefix(dir, conventional, logicalPath string) string {
+	if rel := stripMatchingPrefix(dir, logicalPath); rel != logicalPath {
+		return rel
+	}
+
+	return strings.TrimPrefix(logicalPath, conventional+"/")
+}
+
 // PrepareLoad creates a temp directory with assets in the provider's native structure.
 func (m *providerMapper) PrepareLoad(_ context.Context, cachePath string, manifest *client.BundleManifest) (tmpDir string, cleanup func(), err error) {
 	return prepareLoadCommon(m, cachePath, manifest, nil)
@@ -0,0 +1,122 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// fetchLayer returns the content of layer from the runner asset endpoint.
+// Content already in the blob store is reused without a request. Otherwise
+// the download streams into a partial file named by the expected digest, so
+// an interrupted download resumes with a Range request on the next attempt.
+func fetchLayer(ctx context.Context, c *client.Client, logger *slog.Logger, layer *client.BundleLayer) ([]byte, error) {
+	if layer.ContentSHA256 == "" {
+		data, err := c.FetchBundleAsset(ctx, layer.AssetID)
+		if err != nil {
+			return nil, fmt.Errorf("runner asset endpoint: %w", err)
+		}
+
+		return data, nil
+	}
+
+	if data, err := ReadBlob(layer.ContentSHA256); err == nil && sha256Hex(data) == layer.ContentSHA256 {
+		logger.Debug("bundle asset reused from blob store",
+			slog.String("event.type", "bundle.download.asset.blob_hit"),
+			slog.String("bundle.asset.logical_path", layer.LogicalPath),
+		)
+
+		return data, nil
+	}
+
+	partialPath := filepath.Join(cacheRootDir(), "partials", "sha256", layer.ContentSHA256)
+
+	if err := downloadToPartial(ctx, c, logger, layer, partialPath); err != nil {
+		return nil, err
+	}
+
+	data, err := safeio.ReadFile(partialPath)
+	if err != nil {
+		return nil, fmt.Errorf("read partial asset: %w", err)
+	}
+
+	// Whether it verifies or not, the partial is finished: a mismatch means
+	// the bytes on disk are bad and resuming from them would never succeed.
+	_ = os.Remove(partialPath)
+
+	verified, err := VerifySHA256(data, layer.ContentSHA256)
+	if err != nil {
+		return nil, err
+	}
+
+	return verified, nil
+}
+
+// downloadToPartial appends the remainder of layer to partialPath. An error
+// leaves the bytes received so far in place for the next attempt.
+func downloadToPartial(ctx context.Context, c *client.Client, logger *slog.Logger, layer *client.BundleLayer, partialPath string) error {
+	if err := safeio.MkdirAll(filepath.Dir(partialPath), 0o700); err != nil {
+		return fmt.Errorf("create partial download directory: %w", err)
+	}
+
+	file, err := safeio.OpenFile(partialPath, os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open partial asset: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("stat partial asset: %w", err)
+	}
+
+	offset := info.Size()
+	if offset > 0 {
+		logger.Info("resuming bundle asset download",
+			slog.String("event.type", "bundle.download.asset.resume"),
+			slog.String("bundle.asset.logical_path", layer.LogicalPath),
+			slog.Int64("bundle.asset.offset", offset),
+		)
+	}
+
+	body, resumed, err := c.OpenBundleAsset(ctx, layer.AssetID, offset)
+	if errors.Is(err, client.ErrRangeNotSatisfiable) {
+		// The partial is at or past the end of the asset; start over.
+		body, resumed, err = c.OpenBundleAsset(ctx, layer.AssetID, 0)
+	}
+
+	if err != nil {
+		return fmt.Errorf("runner asset endpoint: %w", err)
+	}
+	defer body.Close()
+
+	start := offset
+	if !resumed {
+		start = 0
+
+		if err := file.Truncate(0); err != nil {
+			return fmt.Errorf("reset partial asset: %w", err)
+		}
+	}
+
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return fmt.Errorf("seek partial asset: %w", err)
+	}
+
+	if _, err := io.Copy(file, body); err != nil {
+		return fmt.Errorf("download asset: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("close partial asset: %w", err)
+	}
+
+	return nil
+}
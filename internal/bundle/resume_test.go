@@ -0,0 +1,145 @@
+package bundle
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestFetchLayer_ResumesPartialDownload(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	content := []byte("hello world")
+	layer := client.BundleLayer{AssetID: "a1", LogicalPath: "skill.md", ContentSHA256: sha256Hex(content)}
+
+	partialPath := filepath.Join(cacheRootDir(), "partials", "sha256", layer.ContentSHA256)
+	if err := os.MkdirAll(filepath.Dir(partialPath), 0o700); err != nil {
+		t.Fatalf("mkdir partials: %v", err)
+	}
+
+	if err := os.WriteFile(partialPath, content[:6], 0o600); err != nil {
+		t.Fatalf("write partial: %v", err)
+	}
+
+	clientHTTP := &http.Client{
+		Transport: cacheRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if got := r.Header.Get("Range"); got != "bytes=6-" {
+				t.Fatalf("Range header = %q, want bytes=6-", got)
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusPartialContent,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(strings.NewReader(string(content[6:]))),
+			}, nil
+		}),
+	}
+
+	c := client.NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+
+	data, err := fetchLayer(t.Context(), c, slog.New(slog.DiscardHandler), &layer)
+	if err != nil {
+		t.Fatalf("fetchLayer() error = %v", err)
+	}
+
+	if string(data) != string(content) {
+		t.Fatalf("fetchLayer() = %q, want %q", data, content)
+	}
+
+	if _, err := os.Stat(partialPath); !os.IsNotExist(err) {
+		t.Fatalf("partial file still present after a verified download (err = %v)", err)
+	}
+}
+
+func TestFetchLayer_KeepsPartialOnInterruptedDownload(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	content := []byte("hello world")
+	layer := client.BundleLayer{AssetID: "a1", LogicalPath: "skill.md", ContentSHA256: sha256Hex(content)}
+
+	clientHTTP := &http.Client{
+		Transport: cacheRoundTripFunc(func(_ *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain"}},
+				Body:       io.NopCloser(io.MultiReader(strings.NewReader("hello "), failingReader{})),
+			}, nil
+		}),
+	}
+
+	c := client.NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+
+	if _, err := fetchLayer(t.Context(), c, slog.New(slog.DiscardHandler), &layer); err == nil {
+		t.Fatal("fetchLayer() expected error for interrupted body")
+	}
+
+	partialPath := filepath.Join(cacheRootDir(), "partials", "sha256", layer.ContentSHA256)
+
+	got, err := os.ReadFile(partialPath)
+	if err != nil {
+		t.Fatalf("read partial: %v", err)
+	}
+
+	if string(got) != "hello " {
+		t.Fatalf("partial = %q, want %q", got, "hello ")
+	}
+}
+
+func TestFetchLayer_ReusesStoredBlob(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	content := []byte("cached content")
+	if _, err := StoreBlob(content); err != nil {
+		t.Fatalf("StoreBlob() error = %v", err)
+	}
+
+	clientHTTP := &http.Client{
+		Transport: cacheRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			t.Fatalf("unexpected request to %s", r.URL.Path)
+			return nil, nil
+		}),
+	}
+
+	c := client.NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+	layer := client.BundleLayer{AssetID: "a1", LogicalPath: "skill.md", ContentSHA256: sha256Hex(content)}
+
+	data, err := fetchLayer(t.Context(), c, slog.New(slog.DiscardHandler), &layer)
+	if err != nil {
+		t.Fatalf("fetchLayer() error = %v", err)
+	}
+
+	if string(data) != string(content) {
+		t.Fatalf("fetchLayer() = %q, want %q", data, content)
+	}
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func TestETagCache_RoundTrip(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	cache := NewETagCache()
+
+	if _, _, ok := cache.Lookup("https://example.test/v1/hub/bundles/acme/kit"); ok {
+		t.Fatal("Lookup() on empty cache reported a hit")
+	}
+
+	if err := cache.Store("https://example.test/v1/hub/bundles/acme/kit", `"abc"`, []byte(`{"id":"b1"}`)); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	etag, body, ok := cache.Lookup("https://example.test/v1/hub/bundles/acme/kit")
+	if !ok || etag != `"abc"` || string(body) != `{"id":"b1"}` {
+		t.Fatalf("Lookup() = (%q, %q, %v), want the stored entry", etag, body, ok)
+	}
+}
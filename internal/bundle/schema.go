@@ -0,0 +1,71 @@
+package bundle
+
+import (
+	"fmt"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+// ManifestSchemaVersion is the newest bundle manifest schema this build
+// understands. Manifests without a schemaVersion predate versioning and are
+// upgraded to it.
+const ManifestSchemaVersion = 1
+
+// ManifestVersionError is returned for a manifest written in a schema newer
+// than ManifestSchemaVersion.
+type ManifestVersionError struct {
+	Version int
+}
+
+func (e *ManifestVersionError) Error() string {
+	return fmt.Sprintf("bundle manifest schema version %d is newer than this mush supports (%d)", e.Version, ManifestSchemaVersion)
+}
+
+// CheckManifest brings manifest up to ManifestSchemaVersion in place. It
+// rejects schemas newer than this build, fills in asset types missing from
+// unversioned manifests, and drops layers whose asset type is unknown so
+// bundles from newer platforms still install what this build can place.
+// It returns a warning for each dropped layer.
+func CheckManifest(manifest *client.BundleManifest) ([]string, error) {
+	if manifest == nil {
+		return nil, nil
+	}
+
+	switch {
+	case manifest.SchemaVersion > ManifestSchemaVersion:
+		return nil, &ManifestVersionError{Version: manifest.SchemaVersion}
+	case manifest.SchemaVersion < 0:
+		return nil, fmt.Errorf("invalid bundle manifest schema version %d", manifest.SchemaVersion)
+	case manifest.SchemaVersion == 0:
+		upgradeLegacyManifest(manifest)
+	}
+
+	var warnings []string
+
+	layers := manifest.Layers[:0]
+
+	for _, layer := range manifest.Layers {
+		if !knownAssetTypes[layer.AssetType] {
+			warnings = append(warnings, fmt.Sprintf("Skipping %s: unknown asset type %q", layer.LogicalPath, layer.AssetType))
+			continue
+		}
+
+		layers = append(layers, layer)
+	}
+
+	manifest.Layers = layers
+
+	return warnings, nil
+}
+
+// upgradeLegacyManifest converts an unversioned manifest, whose layers may
+// omit assetType, to schema version 1.
+func upgradeLegacyManifest(manifest *client.BundleManifest) {
+	for i := range manifest.Layers {
+		if manifest.Layers[i].AssetType == "" {
+			manifest.Layers[i].AssetType = inferAssetType(manifest.Layers[i].LogicalPath)
+		}
+	}
+
+	manifest.SchemaVersion = 1
+}
@@ -0,0 +1,73 @@
+package bundle
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestCheckManifest_UpgradesLegacy(t *testing.T) {
+	manifest := &client.BundleManifest{
+		Layers: []client.BundleLayer{
+			{LogicalPath: "skills/web/SKILL.md"},
+			{LogicalPath: "agents/researcher.md", AssetType: "agent_definition"},
+		},
+	}
+
+	warnings, err := CheckManifest(manifest)
+	if err != nil {
+		t.Fatalf("CheckManifest() error = %v", err)
+	}
+
+	if len(warnings) != 0 {
+		t.Fatalf("CheckManifest() warnings = %v, want none", warnings)
+	}
+
+	if manifest.SchemaVersion != ManifestSchemaVersion {
+		t.Fatalf("SchemaVersion = %d, want %d", manifest.SchemaVersion, ManifestSchemaVersion)
+	}
+
+	if manifest.Layers[0].AssetType != "skill" {
+		t.Fatalf("legacy layer AssetType = %q, want inferred %q", manifest.Layers[0].AssetType, "skill")
+	}
+}
+
+func TestCheckManifest_SkipsUnknownAssetTypes(t *testing.T) {
+	manifest := &client.BundleManifest{
+		SchemaVersion: 1,
+		Layers: []client.BundleLayer{
+			{LogicalPath: "skills/web/SKILL.md", AssetType: "skill"},
+			{LogicalPath: "widgets/banner.yaml", AssetType: "widget"},
+		},
+	}
+
+	warnings, err := CheckManifest(manifest)
+	if err != nil {
+		t.Fatalf("CheckManifest() error = %v", err)
+	}
+
+	if len(manifest.Layers) != 1 || manifest.Layers[0].AssetType != "skill" {
+		t.Fatalf("Layers = %+v, want only the skill", manifest.Layers)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], `unknown asset type "widget"`) {
+		t.Fatalf("warnings = %v, want one for the widget layer", warnings)
+	}
+}
+
+func TestCheckManifest_RejectsNewerSchema(t *testing.T) {
+	manifest := &client.BundleManifest{SchemaVersion: ManifestSchemaVersion + 1}
+
+	_, err := CheckManifest(manifest)
+
+	var versionErr *ManifestVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("CheckManifest() error = %v, want ManifestVersionError", err)
+	}
+
+	if versionErr.Version != ManifestSchemaVersion+1 {
+		t.Fatalf("ManifestVersionError.Version = %d, want %d", versionErr.Version, ManifestSchemaVersion+1)
+	}
+}
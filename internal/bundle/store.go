@@ -17,6 +17,8 @@ import (
 // Content-addressable cache layout under {CacheRoot}/:
 //
 //	blobs/sha256/{digest}                              — raw asset content
+//	partials/sha256/{digest}                           — interrupted asset download
+//	etags/{urlDigest}.json                             — ETag-validated API responses
 //	manifests/{hostID}/{namespace}/{slug}/{version}.json — resolved bundle manifest
 //	refs/{hostID}/{namespace}/{slug}/latest             — latest version pointer
 //	bundles/{namespace}/{slug}/{version}/               — materialized view
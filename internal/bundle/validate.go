@@ -155,6 +155,24 @@ func RepairSkillFrontmatter(data []byte) ([]byte, bool) {
 	return result, true
 }
 
+// repairSkillForLoad validates a skill's frontmatter and repairs it when it
+// can. It returns the data to write and, when the frontmatter was invalid, a
+// warning saying whether it was repaired.
+func repairSkillForLoad(logicalPath string, data []byte) ([]byte, string) {
+	fmErr := ValidateSkillFrontmatter(data)
+	if fmErr == nil {
+		return data, ""
+	}
+
+	if repaired, ok := RepairSkillFrontmatter(data); ok {
+		return repaired, fmt.Sprintf(
+			"%s: auto-repaired YAML frontmatter (unquoted values with colons were quoted)", logicalPath,
+		)
+	}
+
+	return data, fmt.Sprintf("%s: %v; strict harnesses (e.g. Codex) will reject this skill", logicalPath, fmErr)
+}
+
 // splitPreservingCR splits on \n but preserves any trailing \r on each line.
 func splitPreservingCR(data []byte) []string {
 	return strings.Split(string(data), "\n")
@@ -10,15 +10,16 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	neturl "net/url"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/musher-dev/mush/internal/buildinfo"
 	"github.com/musher-dev/mush/internal/observability"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -28,21 +29,33 @@ const (
 	DefaultTimeout = 60 * time.Second
 	// DefaultLeaseDurationMs is the default job lease duration (45s to allow margin over 30s heartbeat).
 	DefaultLeaseDurationMs = 45000
+	// ClaimTimeoutGrace is added to a claim's long-poll wait to form the
+	// request deadline, leaving room for the server to answer after waiting.
+	ClaimTimeoutGrace = 15 * time.Second
 )
 
 // Client is the Musher API client.
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	baseURL       string
+	apiKey        string
+	httpClient    *http.Client
+	responseCache ResponseCache
+	identityCache IdentityCache
+	endpoints     *endpointSet
+	capabilities  *Capabilities
+	retry         RetryPolicy
+	oauth         *oauthState
 }
 
 // HTTPStatusError is returned when an API call receives a non-success HTTP status.
 type HTTPStatusError struct {
 	Operation string
 	Status    int
-	RequestID string
-	TraceID   string
+	// RequestID is the X-Request-Id mush sent. ServerRequestID is the one the
+	// platform answered with, set only when it differs.
+	RequestID       string
+	ServerRequestID string
+	TraceID         string
 }
 
 func (e *HTTPStatusError) Error() string {
@@ -51,6 +64,10 @@ func (e *HTTPStatusError) Error() string {
 		extras = append(extras, "request_id="+e.RequestID)
 	}
 
+	if e.ServerRequestID != "" {
+		extras = append(extras, "server_request_id="+e.ServerRequestID)
+	}
+
 	if e.TraceID != "" {
 		extras = append(extras, "trace_id="+e.TraceID)
 	}
@@ -65,9 +82,36 @@ func (e *HTTPStatusError) Error() string {
 // RequestIDValue returns the request correlation ID when available.
 func (e *HTTPStatusError) RequestIDValue() string { return e.RequestID }
 
+// StatusCode returns the HTTP status the platform answered with.
+func (e *HTTPStatusError) StatusCode() int { return e.Status }
+
 // TraceIDValue returns the distributed trace ID when available.
 func (e *HTTPStatusError) TraceIDValue() string { return e.TraceID }
 
+// RequestIDs returns the request IDs carried by API errors in err's chain:
+// the ID mush sent, then the platform's own ID when it differs.
+func RequestIDs(err error) []string {
+	var ids []string
+
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		for _, id := range []string{statusErr.RequestID, statusErr.ServerRequestID} {
+			if id != "" {
+				ids = append(ids, id)
+			}
+		}
+
+		return ids
+	}
+
+	var requestErr *RequestError
+	if errors.As(err, &requestErr) && requestErr.RequestID != "" {
+		ids = append(ids, requestErr.RequestID)
+	}
+
+	return ids
+}
+
 // RequestError represents a transport-level request failure.
 type RequestError struct {
 	Operation string
@@ -144,6 +188,7 @@ type ResponseMeta struct {
 type RunnerConfigResponse struct {
 	ConfigVersion       string                          `json:"configVersion"`
 	OrganizationID      string                          `json:"organizationId"`
+	HabitatID           string                          `json:"habitatId,omitempty"`
 	GeneratedAt         time.Time                       `json:"generatedAt"`
 	RefreshAfterSeconds int                             `json:"refreshAfterSeconds"`
 	Providers           map[string]RunnerProviderConfig `json:"providers"`
@@ -155,6 +200,7 @@ func (r *RunnerConfigResponse) UnmarshalJSON(data []byte) error {
 	type runnerConfigAlias struct {
 		ConfigVersion       string                          `json:"configVersion"`
 		OrganizationID      string                          `json:"organizationId"`
+		HabitatID           string                          `json:"habitatId,omitempty"`
 		WorkspaceID         string                          `json:"workspaceId"`
 		GeneratedAt         time.Time                       `json:"generatedAt"`
 		RefreshAfterSeconds int                             `json:"refreshAfterSeconds"`
@@ -169,6 +215,7 @@ func (r *RunnerConfigResponse) UnmarshalJSON(data []byte) error {
 
 	r.ConfigVersion = aux.ConfigVersion
 	r.OrganizationID = firstNonEmpty(aux.OrganizationID, aux.WorkspaceID)
+	r.HabitatID = aux.HabitatID
 	r.GeneratedAt = aux.GeneratedAt
 	r.RefreshAfterSeconds = aux.RefreshAfterSeconds
 	r.Providers = aux.Providers
@@ -218,6 +265,15 @@ type JobClaimRequest struct {
 	LeaseDurationMs int    `json:"leaseDurationMs"`
 }
 
+// JobBatchClaimRequest is the request body for claiming up to MaxJobs jobs
+// in one request.
+type JobBatchClaimRequest struct {
+	QueueID         string `json:"queueId,omitempty"`
+	HabitatID       string `json:"habitatId,omitempty"`
+	LeaseDurationMs int    `json:"leaseDurationMs"`
+	MaxJobs         int    `json:"maxJobs"`
+}
+
 // JobCompleteRequest is the request body for completing a job.
 type JobCompleteRequest struct {
 	OutputData map[string]any `json:"outputData,omitempty"`
@@ -258,6 +314,19 @@ type WorkerHeartbeatRequest struct {
 type WorkerHeartbeatResponse struct {
 	Status              string    `json:"status"`
 	HeartbeatDeadlineAt time.Time `json:"heartbeatDeadlineAt"`
+
+	// Maintenance is set while the platform asks workers to stop claiming.
+	Maintenance *MaintenanceWindow `json:"maintenance,omitempty"`
+}
+
+// MaintenanceWindow is a platform-wide pause on claims. Workers finish the
+// job in progress and claim again once heartbeats stop reporting it.
+type MaintenanceWindow struct {
+	// Message says why claims are paused, for display.
+	Message string `json:"message,omitempty"`
+
+	// EndsAt is when the platform expects to lift the pause, if known.
+	EndsAt *time.Time `json:"endsAt,omitempty"`
 }
 
 // DeregisterWorkerRequest is the request body for deregistering a worker.
@@ -321,9 +390,15 @@ type ExecutionConfig struct {
 	TimeoutMs int `json:"timeoutMs"`
 
 	// WorkingDirectory is the optional working directory for execution.
+	// With Repository set, a relative path is resolved inside the checkout.
 	WorkingDirectory string `json:"workingDirectory,omitempty"`
 
+	// Repository asks the runner to run the job in a fresh checkout of a repository.
+	Repository *RepositoryConfig `json:"repository,omitempty"`
+
 	// Environment contains environment variables to set for execution.
+	// Values may be secret references (such as "op://vault/item/field")
+	// that the runner resolves locally before the job runs.
 	Environment map[string]string `json:"environment,omitempty"`
 
 	// Sandbox contains optional sandbox configuration.
@@ -334,6 +409,87 @@ type ExecutionConfig struct {
 
 	// Claude holds Claude-specific configuration (when HarnessType is "claude").
 	Claude *ClaudeConfig `json:"claude,omitempty"`
+
+	// Enrichment controls optional context fetched and appended to the prompt before execution.
+	Enrichment *EnrichmentConfig `json:"enrichment,omitempty"`
+
+	// Retry changes how the runner handles a job's second and later attempts.
+	Retry *RetryConfig `json:"retry,omitempty"`
+
+	// Artifacts selects files to upload with a successful job's result.
+	Artifacts *ArtifactConfig `json:"artifacts,omitempty"`
+
+	// Git controls what the runner does with a successful job's changes,
+	// within what the worker's git workflow setting allows.
+	Git *GitWorkflowConfig `json:"git,omitempty"`
+}
+
+// GitWorkflowConfig is a job's git workflow settings.
+type GitWorkflowConfig struct {
+	// Mode is "off", "commit", "push", or "pr". A mode past the worker's
+	// setting is lowered to it; empty uses the worker's setting.
+	Mode string `json:"mode,omitempty"`
+
+	// Base is the pull request's base branch (empty = repository default).
+	Base string `json:"base,omitempty"`
+
+	// Draft opens the pull request as a draft.
+	Draft bool `json:"draft,omitempty"`
+}
+
+// ArtifactConfig selects the files uploaded with a job's result, in
+// addition to those the job leaves in its artifacts directory.
+type ArtifactConfig struct {
+	// Paths are glob patterns relative to the working directory, such as
+	// "coverage.xml" or "test-results/**/*.png". "**" matches any number of
+	// directories.
+	Paths []string `json:"paths,omitempty"`
+
+	// Diff uploads the job's changes to tracked files as changes.diff.
+	Diff bool `json:"diff,omitempty"`
+}
+
+// RetryConfig controls attempt-aware execution. The zero value runs every
+// attempt the same way.
+type RetryConfig struct {
+	// IncludePreviousError prepends the previous attempt's failure to the
+	// prompt on retries.
+	IncludePreviousError bool `json:"includePreviousError,omitempty"`
+
+	// TimeoutMultiplier scales the execution timeout on retries (values
+	// above 1 give later attempts longer to finish).
+	TimeoutMultiplier float64 `json:"timeoutMultiplier,omitempty"`
+
+	// PreserveContext keeps the harness session after a failure that will be
+	// retried instead of clearing it, so the next attempt can build on it.
+	PreserveContext bool `json:"preserveContext,omitempty"`
+}
+
+// RepositoryConfig identifies the repository a job runs in.
+type RepositoryConfig struct {
+	// URL is any URL or path git can clone.
+	URL string `json:"url"`
+
+	// Ref is the branch, tag, or commit to check out (empty = default branch).
+	Ref string `json:"ref,omitempty"`
+}
+
+// EnrichmentConfig lists the optional context sources for a job's prompt.
+type EnrichmentConfig struct {
+	// LinearIssue appends the originating Linear issue's comments and attachments.
+	LinearIssue *LinearIssueEnrichment `json:"linearIssue,omitempty"`
+}
+
+// LinearIssueEnrichment configures Linear issue context enrichment.
+type LinearIssueEnrichment struct {
+	// Comments includes the issue's comment thread.
+	Comments bool `json:"comments,omitempty"`
+
+	// Attachments includes the issue's attachment titles and links.
+	Attachments bool `json:"attachments,omitempty"`
+
+	// MaxComments keeps only the most recent comments (0 = platform default).
+	MaxComments int `json:"maxComments,omitempty"`
 }
 
 // GetHarnessType returns the harness type.
@@ -375,6 +531,10 @@ type InstructionAvailability struct {
 	InstructionID        string `json:"instructionId,omitempty"`
 	InstructionName      string `json:"instructionName,omitempty"`
 	InstructionSlug      string `json:"instructionSlug,omitempty"`
+
+	// RequiredMCPProviders names the runner config providers, such as
+	// "linear", whose MCP servers the instruction's jobs depend on.
+	RequiredMCPProviders []string `json:"requiredMcpProviders,omitempty"`
 }
 
 // Job represents a job claimed from the queue.
@@ -411,8 +571,11 @@ type Job struct {
 
 	Instruction    *InstructionConfig `json:"-"`
 	Execution      *ExecutionConfig   `json:"-"`
-	WebhookConfig  map[string]any     `json:"-"`
+	WebhookConfig  *WebhookConfig     `json:"-"`
 	ExecutionError string             `json:"-"`
+
+	// WebhookError explains why the claim's webhook config was ignored.
+	WebhookError string `json:"-"`
 }
 
 // UnmarshalJSON accepts both organization-scoped and legacy workspace-scoped job payloads.
@@ -491,12 +654,39 @@ func (j *Job) UnmarshalJSON(data []byte) error {
 // JobClaimResponse wraps the claim response payload.
 type JobClaimResponse struct {
 	Job            Job                `json:"job"`
-	WebhookConfig  map[string]any     `json:"webhookConfig,omitempty"`
+	WebhookConfig  json.RawMessage    `json:"webhookConfig,omitempty"`
 	Instruction    *InstructionConfig `json:"instruction,omitempty"`
 	Execution      *ExecutionConfig   `json:"execution,omitempty"`
 	ExecutionError string             `json:"executionError,omitempty"`
 }
 
+// JobBatchClaimResponse holds the jobs a batch claim took, each in the
+// form of a single claim.
+type JobBatchClaimResponse struct {
+	Jobs []JobClaimResponse `json:"jobs"`
+}
+
+// claimedJob folds the claim's execution details into its job.
+func (r *JobClaimResponse) claimedJob() *Job {
+	job := r.Job
+	job.Instruction = r.Instruction
+	job.Execution = r.Execution
+	job.ExecutionError = r.ExecutionError
+
+	// A webhook the runner cannot use must not cost the job, so it is
+	// dropped with an explanation instead of failing the claim.
+	if hasWebhookConfig(r.WebhookConfig) {
+		hook, err := decodeWebhookConfig(r.WebhookConfig)
+		if err != nil {
+			job.WebhookError = err.Error()
+		} else {
+			job.WebhookConfig = hook
+		}
+	}
+
+	return &job
+}
+
 // GetHarnessType returns the harness type for this job.
 func (j *Job) GetHarnessType() string {
 	if j.Execution != nil {
@@ -517,6 +707,28 @@ func (j *Job) GetRenderedInstruction() string {
 	return ""
 }
 
+// IsRetry reports whether this is a second or later attempt at the job.
+func (j *Job) IsRetry() bool {
+	return j.AttemptNumber > 1
+}
+
+// GetRetryConfig returns the job's retry settings, or the zero value when
+// the execution config has none.
+func (j *Job) GetRetryConfig() RetryConfig {
+	if j.Execution != nil && j.Execution.Retry != nil {
+		return *j.Execution.Retry
+	}
+
+	return RetryConfig{}
+}
+
+// IsFromLinear reports whether the job's CloudEvent originated from Linear.
+func (j *Job) IsFromLinear() bool {
+	return strings.Contains(strings.ToLower(j.CeSource), "linear") ||
+		strings.HasPrefix(strings.ToLower(j.CeType), "linear.") ||
+		strings.HasPrefix(strings.ToLower(j.CeType), "app.linear.")
+}
+
 // GetDisplayName returns a human-friendly job label.
 func (j *Job) GetDisplayName() string {
 	if j.InputData != nil {
@@ -560,6 +772,40 @@ func (c *Client) BaseURL() string {
 	return c.baseURL
 }
 
+// SetFallbackURLs sets base URLs to fail over to, in order, when the
+// primary base URL is unreachable, such as replicated gateways of a
+// self-hosted platform. They share the client's API key.
+func (c *Client) SetFallbackURLs(urls []string) {
+	next := c.httpClient.Transport
+	if c.endpoints != nil {
+		next = c.endpoints.next
+	}
+
+	c.endpoints = nil
+
+	hc := *c.httpClient
+	hc.Transport = next
+
+	if len(urls) > 0 {
+		c.endpoints = newEndpointSet(c.baseURL, urls, next)
+		hc.Transport = c.endpoints
+	}
+
+	c.httpClient = &hc
+}
+
+// ActiveBaseURL returns the base URL requests currently go to. It differs
+// from BaseURL only after failing over to a fallback URL.
+func (c *Client) ActiveBaseURL() string {
+	if c.endpoints != nil {
+		if url, ok := c.endpoints.fallback(); ok {
+			return url
+		}
+	}
+
+	return c.baseURL
+}
+
 // ValidateKey validates the API key and returns the runner identity.
 func (c *Client) ValidateKey(ctx context.Context) (*Identity, error) {
 	identity, _, err := c.ValidateKeyWithMeta(ctx)
@@ -602,6 +848,10 @@ func (c *Client) ValidateKeyWithMeta(ctx context.Context) (*Identity, *ResponseM
 		return nil, meta, err
 	}
 
+	if c.identityCache != nil {
+		_ = c.identityCache.StoreIdentity(c.CacheKey(), &identity)
+	}
+
 	return &identity, meta, nil
 }
 
@@ -639,8 +889,21 @@ func (c *Client) GetCurrentUserProfile(ctx context.Context) (*UserProfile, error
 }
 
 // GetRunnerConfig fetches runner runtime configuration for startup provisioning.
-func (c *Client) GetRunnerConfig(ctx context.Context) (*RunnerConfigResponse, error) {
-	req, err := c.newRequest(ctx, "GET", c.baseURL+"/v1/runner/config", http.NoBody)
+// When habitatID is set, the config also includes providers and credentials
+// scoped to that habitat; otherwise only organization-wide ones are returned.
+func (c *Client) GetRunnerConfig(ctx context.Context, habitatID string) (*RunnerConfigResponse, error) {
+	endpoint, err := neturl.Parse(c.baseURL + "/v1/runner/config")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse runner config endpoint: %w", err)
+	}
+
+	if habitatID != "" {
+		query := endpoint.Query()
+		query.Set("habitat_id", habitatID)
+		endpoint.RawQuery = query.Encode()
+	}
+
+	req, err := c.newRequest(ctx, "GET", endpoint.String(), http.NoBody)
 	if err != nil {
 		return nil, err
 	}
@@ -663,7 +926,8 @@ func (c *Client) GetRunnerConfig(ctx context.Context) (*RunnerConfigResponse, er
 	return &cfg, nil
 }
 
-// IsAuthenticated returns true if the client has an API key configured.
+// IsAuthenticated returns true if the client has an API key or OAuth token
+// configured.
 func (c *Client) IsAuthenticated() bool {
 	return c.apiKey != ""
 }
@@ -685,7 +949,7 @@ func (c *Client) setRequestHeaders(req *http.Request) {
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "mush/"+buildinfo.Version)
+	req.Header.Set("User-Agent", c.userAgent())
 }
 
 func (c *Client) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
@@ -707,7 +971,7 @@ func (c *Client) newPublicRequest(ctx context.Context, method, url string, body
 	}
 
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "mush/"+buildinfo.Version)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("X-Request-Id", uuid.NewString())
 
 	spanCtx := trace.SpanContextFromContext(req.Context())
@@ -719,6 +983,12 @@ func (c *Client) newPublicRequest(ctx context.Context, method, url string, body
 }
 
 func (c *Client) do(req *http.Request, route string) (*http.Response, error) {
+	return c.doWith(c.httpClient, req, route)
+}
+
+// doWith is do with an explicit HTTP client, for requests such as long-poll
+// claims that must not be cut off by the client's default timeout.
+func (c *Client) doWith(httpClient *http.Client, req *http.Request, route string) (*http.Response, error) {
 	requestID := strings.TrimSpace(req.Header.Get("X-Request-Id"))
 	logger := observability.FromContext(req.Context()).With(
 		slog.String("component", "client"),
@@ -731,7 +1001,7 @@ func (c *Client) do(req *http.Request, route string) (*http.Response, error) {
 
 	logger.Debug("request started", slog.String("event.type", "http.request.start"))
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.sendAuthorized(httpClient, req, route, logger)
 	durationMS := time.Since(start).Milliseconds()
 
 	if err != nil {
@@ -754,6 +1024,10 @@ func (c *Client) do(req *http.Request, route string) (*http.Response, error) {
 		logger = logger.With(slog.String("trace.id", traceID))
 	}
 
+	if serverID := serverRequestID(resp, requestID); serverID != "" {
+		logger = logger.With(slog.String("server.request.id", serverID))
+	}
+
 	logger.Debug(
 		"request completed",
 		slog.String("event.type", "http.request.finish"),
@@ -765,6 +1039,43 @@ func (c *Client) do(req *http.Request, route string) (*http.Response, error) {
 	return resp, nil
 }
 
+// sendAuthorized sends req under the retry policy with a current OAuth
+// token. A request the platform rejects with 401 is sent once more after
+// renewing the token, which the platform may have revoked before it expired.
+func (c *Client) sendAuthorized(httpClient *http.Client, req *http.Request, route string, logger *slog.Logger) (*http.Response, error) {
+	if err := c.authorize(req, ""); err != nil {
+		logger.Warn("token refresh failed",
+			slog.String("event.type", "auth.token.refresh.error"),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	resp, err := c.sendWithRetry(httpClient, req, route, logger)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || c.oauth == nil || req.Header.Get("Authorization") == "" {
+		return resp, err
+	}
+
+	next, rewindErr := rewindRequest(req)
+	if rewindErr != nil {
+		return resp, nil
+	}
+
+	rejected := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if err := c.authorize(next, rejected); err != nil {
+		logger.Warn("token refresh failed",
+			slog.String("event.type", "auth.token.refresh.error"),
+			slog.String("error", err.Error()),
+		)
+
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return c.sendWithRetry(httpClient, next, route, logger)
+}
+
 func encodeJSON(v any) ([]byte, error) {
 	data, err := json.Marshal(v)
 	if err != nil {
@@ -798,23 +1109,36 @@ func emptyJSONBody() io.Reader {
 
 // unexpectedStatus creates a formatted error from an unexpected HTTP status code.
 func unexpectedStatus(operation string, resp *http.Response) error {
-	statusCode := 0
-	requestID := ""
-	traceID := ""
+	statusErr := &HTTPStatusError{Operation: operation}
 
 	if resp != nil {
-		statusCode = resp.StatusCode
-		requestID = strings.TrimSpace(resp.Header.Get("X-Request-Id"))
-		traceID = responseTraceID(resp)
+		statusErr.Status = resp.StatusCode
+		statusErr.TraceID = responseTraceID(resp)
+
+		if resp.Request != nil {
+			statusErr.RequestID = strings.TrimSpace(resp.Request.Header.Get("X-Request-Id"))
+		}
+
+		statusErr.ServerRequestID = serverRequestID(resp, statusErr.RequestID)
+		if statusErr.RequestID == "" {
+			statusErr.RequestID, statusErr.ServerRequestID = statusErr.ServerRequestID, ""
+		}
+
 		_, _ = io.Copy(io.Discard, resp.Body)
 	}
 
-	return &HTTPStatusError{
-		Operation: operation,
-		Status:    statusCode,
-		RequestID: requestID,
-		TraceID:   traceID,
+	return statusErr
+}
+
+// serverRequestID returns the response's X-Request-Id when the platform
+// assigned its own instead of echoing sent.
+func serverRequestID(resp *http.Response, sent string) string {
+	id := strings.TrimSpace(resp.Header.Get("X-Request-Id"))
+	if id == sent {
+		return ""
 	}
+
+	return id
 }
 
 func responseTraceID(resp *http.Response) string {
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	neturl "net/url"
+)
+
+// Artifact is a file uploaded with a job's result.
+type Artifact struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType,omitempty"`
+	SizeBytes   int64  `json:"sizeBytes"`
+
+	// URL is where the platform serves the file, when it returns one.
+	URL string `json:"url,omitempty"`
+}
+
+// UploadArtifact uploads content as a file named name, a slash-separated
+// relative path, for the job. The file is sent as the "file" part of a
+// multipart form, so the whole of content is read into memory first;
+// callers bound its size.
+func (c *Client) UploadArtifact(ctx context.Context, jobID, name, contentType string, content io.Reader) (*Artifact, error) {
+	url := fmt.Sprintf("%s/v1/runner/jobs/%s/artifacts", c.baseURL, neturl.PathEscape(jobID))
+
+	var body bytes.Buffer
+
+	form := multipart.NewWriter(&body)
+
+	// Multipart readers keep only the base of a part's file name, so the
+	// full relative name is sent as its own field.
+	if err := form.WriteField("name", name); err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", multipart.FileContentDisposition("file", name))
+	header.Set("Content-Type", contentType)
+
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("failed to read artifact %s: %w", name, err)
+	}
+
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build upload: %w", err)
+	}
+
+	req, err := c.newRequest(ctx, "POST", url, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	// The platform stores a retried upload once.
+	setIdempotencyKey(req)
+
+	resp, err := c.do(req, "/v1/runner/jobs/{job_id}/artifacts")
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload artifact: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, unexpectedStatus("upload artifact", resp)
+	}
+
+	var artifact Artifact
+	if err := decodeJSON(resp.Body, &artifact, "failed to parse artifact"); err != nil {
+		return nil, err
+	}
+
+	return &artifact, nil
+}
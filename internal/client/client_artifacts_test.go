@@ -0,0 +1,65 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestClientUploadArtifact(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/runner/jobs/job-123/artifacts" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.Header.Get(IdempotencyKeyHeader) == "" {
+			t.Fatalf("%s header missing", IdempotencyKeyHeader)
+		}
+
+		reader, err := r.MultipartReader()
+		if err != nil {
+			t.Fatalf("MultipartReader() error = %v", err)
+		}
+
+		part, err := reader.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+
+		if name, _ := io.ReadAll(part); part.FormName() != "name" || string(name) != "reports/report.xml" {
+			t.Fatalf("first part = %s %q, want the artifact name", part.FormName(), name)
+		}
+
+		part, err = reader.NextPart()
+		if err != nil {
+			t.Fatalf("NextPart() error = %v", err)
+		}
+
+		data, _ := io.ReadAll(part)
+		if part.FormName() != "file" || part.FileName() != "report.xml" || part.Header.Get("Content-Type") != "application/xml" || string(data) != "<ok/>" {
+			t.Fatalf("part = %s %s %q %q, want the file", part.FormName(), part.FileName(), part.Header.Get("Content-Type"), data)
+		}
+
+		return jsonResponse(http.StatusCreated, `{"id":"art-1","name":"reports/report.xml","sizeBytes":5,"url":"https://files.test/art-1"}`), nil
+	})
+
+	artifact, err := c.UploadArtifact(t.Context(), "job-123", "reports/report.xml", "application/xml", strings.NewReader("<ok/>"))
+	if err != nil {
+		t.Fatalf("UploadArtifact() error = %v", err)
+	}
+
+	if artifact.ID != "art-1" || artifact.SizeBytes != 5 || artifact.URL == "" {
+		t.Fatalf("UploadArtifact() = %#v, want the stored artifact", artifact)
+	}
+}
+
+func TestClientUploadArtifactRejected(t *testing.T) {
+	c := newMockClient(t, func(*http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusRequestEntityTooLarge, `{"detail":"too large"}`), nil
+	})
+
+	if _, err := c.UploadArtifact(t.Context(), "job-123", "big.bin", "application/octet-stream", strings.NewReader("x")); err == nil {
+		t.Fatal("UploadArtifact() error = nil, want the rejection")
+	}
+}
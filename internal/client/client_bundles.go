@@ -1,6 +1,7 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	neturl "net/url"
+	"strings"
 )
 
 // BundleResolveResponse is the response from resolving a bundle version.
@@ -27,14 +29,17 @@ type BundleResolveResponse struct {
 
 // BundleManifest describes the layers (assets) in a bundle version.
 type BundleManifest struct {
-	Layers []BundleLayer `json:"layers"`
+	// SchemaVersion is the manifest format version; zero means the manifest
+	// predates versioning.
+	SchemaVersion int           `json:"schemaVersion,omitempty"`
+	Layers        []BundleLayer `json:"layers"`
 }
 
 // BundleLayer describes a single asset in a bundle.
 type BundleLayer struct {
 	AssetID       string `json:"assetId"`
 	LogicalPath   string `json:"logicalPath"`
-	AssetType     string `json:"assetType"` // "skill", "agent_definition", "tool_config"
+	AssetType     string `json:"assetType"` // "skill", "agent_definition", "tool_config", "command", "prompt", "hook"
 	MediaType     string `json:"mediaType,omitempty"`
 	ContentSHA256 string `json:"contentSha256"`
 	SizeBytes     int64  `json:"sizeBytes"`
@@ -65,23 +70,14 @@ func (c *Client) ResolveBundle(ctx context.Context, namespace, slug, version str
 		neturl.QueryEscape(resolvedVersion),
 	)
 
-	req, err := c.newPublicRequest(ctx, "GET", c.baseURL+assetsPath, http.NoBody)
+	body, err := c.getPublicCached(ctx, assetsPath, "resolve bundle assets")
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.do(req, assetsPath)
-	if err != nil {
-		return nil, fmt.Errorf("resolve bundle assets: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, unexpectedStatus("resolve bundle assets", resp)
-	}
-
 	var assetsResp struct {
-		Data []struct {
+		SchemaVersion int `json:"schemaVersion"`
+		Data          []struct {
 			ID            string `json:"id"`
 			AssetType     string `json:"assetType"`
 			LogicalPath   string `json:"logicalPath"`
@@ -90,7 +86,7 @@ func (c *Client) ResolveBundle(ctx context.Context, namespace, slug, version str
 		} `json:"data"`
 	}
 
-	if err := decodeJSON(resp.Body, &assetsResp, "failed to parse bundle assets"); err != nil {
+	if err := decodeJSON(bytes.NewReader(body), &assetsResp, "failed to parse bundle assets"); err != nil {
 		return nil, err
 	}
 
@@ -113,7 +109,7 @@ func (c *Client) ResolveBundle(ctx context.Context, namespace, slug, version str
 		Slug:      slug,
 		Ref:       namespace + "/" + slug,
 		State:     "published",
-		Manifest:  BundleManifest{Layers: layers},
+		Manifest:  BundleManifest{SchemaVersion: assetsResp.SchemaVersion, Layers: layers},
 	}, nil
 }
 
@@ -126,6 +122,9 @@ type PullBundleResponse struct {
 	Name        string            `json:"name"`
 	Description *string           `json:"description"`
 	Manifest    []PullBundleAsset `json:"manifest"`
+
+	// SchemaVersion is the manifest format version; zero means unversioned.
+	SchemaVersion int `json:"schemaVersion,omitempty"`
 }
 
 // PullBundleAsset is a single asset with inline content from the pull endpoint.
@@ -195,6 +194,70 @@ func (c *Client) FetchHubBundleAsset(ctx context.Context, namespace, slug, logic
 	return c.fetchAsset(ctx, path, false)
 }
 
+// ErrRangeNotSatisfiable indicates the server rejected a resume offset,
+// typically because it is at or past the end of the asset.
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// OpenBundleAsset opens a single asset by ID for streaming, asking the server
+// to skip the first offset bytes. resumed reports whether the server honored
+// the range; when it did not, body holds the full content from byte zero.
+// The caller must close body.
+func (c *Client) OpenBundleAsset(ctx context.Context, assetID string, offset int64) (body io.ReadCloser, resumed bool, err error) {
+	path := fmt.Sprintf("/v1/runner/assets/%s", neturl.PathEscape(assetID))
+
+	req, err := c.newRequest(ctx, "GET", c.baseURL+path, http.NoBody)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := c.do(req, path)
+	if err != nil {
+		return nil, false, fmt.Errorf("fetch bundle asset (%s): %w", path, err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return resp.Body, offset > 0, nil
+	case http.StatusOK:
+	case http.StatusRequestedRangeNotSatisfiable:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		return nil, false, fmt.Errorf("fetch bundle asset (%s): %w", path, ErrRangeNotSatisfiable)
+	default:
+		defer resp.Body.Close()
+		return nil, false, unexpectedStatus("fetch bundle asset", resp)
+	}
+
+	// A full response may be the JSON envelope with inline content, which
+	// cannot be resumed anyway, so unwrap it here.
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		return resp.Body, false, nil
+	}
+
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("read bundle asset (%s): %w", path, err)
+	}
+
+	content, ok, extractErr := extractAssetContent(data)
+	if extractErr != nil {
+		return nil, false, fmt.Errorf("fetch bundle asset (%s): %w", path, extractErr)
+	}
+
+	if ok {
+		data = []byte(content)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), false, nil
+}
+
 func (c *Client) fetchAsset(ctx context.Context, path string, authenticated bool) ([]byte, error) {
 	endpointURL := c.baseURL + path
 
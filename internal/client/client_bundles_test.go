@@ -292,3 +292,128 @@ func TestFetchBundleAssetNullContentTextReturnsError(t *testing.T) {
 		t.Fatalf("FetchBundleAsset() error = %v, want ErrNullContent", err)
 	}
 }
+
+type memoryResponseCache map[string][2]string
+
+func (m memoryResponseCache) Lookup(key string) (etag string, body []byte, ok bool) {
+	entry, ok := m[key]
+	return entry[0], []byte(entry[1]), ok
+}
+
+func (m memoryResponseCache) Store(key, etag string, body []byte) error {
+	m[key] = [2]string{etag, string(body)}
+	return nil
+}
+
+func TestResolveBundleRevalidatesWithETag(t *testing.T) {
+	t.Parallel()
+
+	var fullResponses int
+
+	clientHTTP := &http.Client{
+		Transport: bundleRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			etag := `"v1-detail"`
+			body := `{"id":"b1","slug":"my-bundle","latestVersion":"1.2.3","publisher":{"handle":"acme"}}`
+
+			if strings.Contains(r.URL.Path, "/assets") {
+				etag = `"v1-assets"`
+				body = `{"data":[{"id":"a1","assetType":"skill","logicalPath":"skill.md","contentSha256":"abc123","sizeBytes":100}]}`
+			}
+
+			if r.Header.Get("If-None-Match") == etag {
+				return &http.Response{StatusCode: http.StatusNotModified, Header: http.Header{}, Body: http.NoBody}, nil
+			}
+
+			fullResponses++
+
+			resp := bundleJSONResponse(http.StatusOK, body)
+			resp.Header.Set("ETag", etag)
+
+			return resp, nil
+		}),
+	}
+
+	c := NewWithHTTPClient("https://example.test", "", clientHTTP)
+	c.SetResponseCache(memoryResponseCache{})
+
+	for attempt := range 2 {
+		resolved, err := c.ResolveBundle(t.Context(), "acme", "my-bundle", "")
+		if err != nil {
+			t.Fatalf("ResolveBundle() attempt %d error = %v", attempt, err)
+		}
+
+		if resolved.Version != "1.2.3" || len(resolved.Manifest.Layers) != 1 {
+			t.Fatalf("ResolveBundle() attempt %d = %+v, want version 1.2.3 with one layer", attempt, resolved)
+		}
+	}
+
+	if fullResponses != 2 {
+		t.Fatalf("full responses = %d, want 2 (second resolve should be answered by 304s)", fullResponses)
+	}
+}
+
+func TestOpenBundleAssetResumesWithRange(t *testing.T) {
+	t.Parallel()
+
+	clientHTTP := &http.Client{
+		Transport: bundleRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if got := r.Header.Get("Range"); got != "bytes=6-" {
+				t.Fatalf("Range header = %q, want bytes=6-", got)
+			}
+
+			return bundleRawResponse(http.StatusPartialContent, "world"), nil
+		}),
+	}
+
+	c := NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+
+	body, resumed, err := c.OpenBundleAsset(t.Context(), "asset-1", 6)
+	if err != nil {
+		t.Fatalf("OpenBundleAsset() error = %v", err)
+	}
+	defer body.Close()
+
+	data, _ := io.ReadAll(body)
+	if !resumed || string(data) != "world" {
+		t.Fatalf("OpenBundleAsset() = (%q, resumed=%v), want (\"world\", true)", data, resumed)
+	}
+}
+
+func TestOpenBundleAssetWithoutRangeSupportRestarts(t *testing.T) {
+	t.Parallel()
+
+	clientHTTP := &http.Client{
+		Transport: bundleRoundTripFunc(func(_ *http.Request) (*http.Response, error) {
+			return bundleJSONResponse(http.StatusOK, `{"id":"asset-1","contentText":"hello world"}`), nil
+		}),
+	}
+
+	c := NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+
+	body, resumed, err := c.OpenBundleAsset(t.Context(), "asset-1", 6)
+	if err != nil {
+		t.Fatalf("OpenBundleAsset() error = %v", err)
+	}
+	defer body.Close()
+
+	data, _ := io.ReadAll(body)
+	if resumed || string(data) != "hello world" {
+		t.Fatalf("OpenBundleAsset() = (%q, resumed=%v), want (\"hello world\", false)", data, resumed)
+	}
+}
+
+func TestOpenBundleAssetRangeNotSatisfiable(t *testing.T) {
+	t.Parallel()
+
+	clientHTTP := &http.Client{
+		Transport: bundleRoundTripFunc(func(_ *http.Request) (*http.Response, error) {
+			return bundleRawResponse(http.StatusRequestedRangeNotSatisfiable, ""), nil
+		}),
+	}
+
+	c := NewWithHTTPClient("https://example.test", "test-key", clientHTTP)
+
+	if _, _, err := c.OpenBundleAsset(t.Context(), "asset-1", 99); !errors.Is(err, ErrRangeNotSatisfiable) {
+		t.Fatalf("OpenBundleAsset() error = %v, want ErrRangeNotSatisfiable", err)
+	}
+}
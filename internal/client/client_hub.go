@@ -1,7 +1,9 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	neturl "net/url"
@@ -138,27 +140,18 @@ func (c *Client) GetHubBundleDetail(ctx context.Context, publisherHandle, bundle
 		neturl.PathEscape(bundleSlug),
 	)
 
-	req, err := c.newPublicRequest(ctx, "GET", c.baseURL+path, http.NoBody)
+	body, err := c.getPublicCached(ctx, path, "get hub bundle detail")
 	if err != nil {
-		return nil, err
-	}
-
-	resp, err := c.do(req, path)
-	if err != nil {
-		return nil, fmt.Errorf("get hub bundle detail: %w", err)
-	}
-	defer resp.Body.Close()
+		var statusErr *HTTPStatusError
+		if errors.As(err, &statusErr) && statusErr.Status == http.StatusNotFound {
+			return nil, fmt.Errorf("bundle %s/%s not found", publisherHandle, bundleSlug)
+		}
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("bundle %s/%s not found", publisherHandle, bundleSlug)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, unexpectedStatus("get hub bundle detail", resp)
+		return nil, err
 	}
 
 	var result HubBundleDetail
-	if err := decodeJSON(resp.Body, &result, "failed to parse hub bundle detail"); err != nil {
+	if err := decodeJSON(bytes.NewReader(body), &result, "failed to parse hub bundle detail"); err != nil {
 		return nil, err
 	}
 
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IssueComment is one comment on the issue a job originated from.
+type IssueComment struct {
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// IssueAttachment is a link or file attached to the issue a job originated from.
+type IssueAttachment struct {
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// IssueContext is the issue discussion fetched for a job.
+type IssueContext struct {
+	Identifier  string            `json:"identifier,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Comments    []IssueComment    `json:"comments"`
+	Attachments []IssueAttachment `json:"attachments"`
+}
+
+// GetJobIssueContext fetches the comments and attachments of the issue a job
+// originated from. The platform reads them with the organization's own
+// integration credential, so the runner never needs one.
+func (c *Client) GetJobIssueContext(ctx context.Context, jobID string, opts *LinearIssueEnrichment) (*IssueContext, error) {
+	query := neturl.Values{}
+
+	var include []string
+
+	if opts.Comments {
+		include = append(include, "comments")
+	}
+
+	if opts.Attachments {
+		include = append(include, "attachments")
+	}
+
+	query.Set("include", strings.Join(include, ","))
+
+	if opts.MaxComments > 0 {
+		query.Set("max_comments", strconv.Itoa(opts.MaxComments))
+	}
+
+	endpointURL := fmt.Sprintf("%s/v1/runner/jobs/%s/issue-context?%s", c.baseURL, neturl.PathEscape(jobID), query.Encode())
+
+	req, err := c.newRequest(ctx, "GET", endpointURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, "/v1/runner/jobs/{job_id}/issue-context")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issue context: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("get issue context", resp)
+	}
+
+	var issue IssueContext
+	if err := decodeJSON(resp.Body, &issue, "failed to parse issue context"); err != nil {
+		return nil, err
+	}
+
+	return &issue, nil
+}
@@ -0,0 +1,456 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// streamReleaseTimeout bounds releasing a job the stream received but
+// nobody took before it closed.
+const streamReleaseTimeout = 10 * time.Second
+
+// waitingLeaseRefresh is how often the leases of jobs a batch claim took
+// ahead are heartbeated, often enough that a job handed out just before
+// the next refresh still has most of its lease left.
+const waitingLeaseRefresh = DefaultLeaseDurationMs * time.Millisecond / 4
+
+// errStreamUnsupported marks a server without the streaming claim endpoint.
+var errStreamUnsupported = errors.New("job streaming not supported")
+
+// JobStream hands out jobs the platform pushes over a server-sent events
+// stream, so a job is picked up as soon as it is queued instead of when the
+// next long-poll lands. Each job event is already claimed for this worker,
+// and the platform sends the next one only after the worker starts,
+// finishes, or releases the previous one.
+//
+// When the server has no streaming endpoint, the stream falls back to
+// ClaimJob long-polling for the rest of its life, or to ClaimJobs after
+// SetBatchSize.
+type JobStream struct {
+	c         *Client
+	ctx       context.Context
+	habitatID string
+	queueID   string
+
+	mu      sync.Mutex
+	polling bool
+	conn    *jobStreamConn
+
+	// batchSize is how many jobs a long-poll claims at once;
+	// batchUnsupported is set once the server turns batch claims down.
+	batchSize        int
+	batchUnsupported bool
+
+	// waiting holds the jobs a batch claimed beyond the one Next returned,
+	// in claim order. stopRefresh ends the goroutine keeping their leases.
+	waiting     []*Job
+	stopRefresh context.CancelFunc
+}
+
+// jobStreamConn is one open stream. Its reader goroutine hands jobs over
+// one at a time and reports how the stream ended on done.
+type jobStreamConn struct {
+	cancel context.CancelFunc
+	jobs   chan *Job
+	done   chan error
+}
+
+// StreamJobs returns a JobStream for a habitat or queue. It connects on the
+// first Next; the stream stays open until ctx ends or Close is called.
+func (c *Client) StreamJobs(ctx context.Context, habitatID, queueID string) *JobStream {
+	if queueID != "" {
+		habitatID = ""
+	}
+
+	return &JobStream{c: c, ctx: ctx, habitatID: habitatID, queueID: queueID}
+}
+
+// Streaming reports whether jobs arrive over the stream rather than by
+// long-polling.
+func (s *JobStream) Streaming() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return !s.polling
+}
+
+// SetBatchSize makes long-polling claim up to n jobs per request where the
+// server supports it. Jobs past the first wait in the stream, which keeps
+// their leases, and are returned by the following calls to Next. Streaming
+// hands out jobs one at a time regardless.
+func (s *JobStream) SetBatchSize(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.batchSize = n
+}
+
+// Next waits up to waitTimeoutSeconds for a job, with ClaimJob's results:
+// no job and no error when the wait ends empty. A stream the server closes
+// is reopened on the following call.
+func (s *JobStream) Next(ctx context.Context, waitTimeoutSeconds int) (*Job, bool, error) {
+	s.mu.Lock()
+	polling := s.polling
+	s.mu.Unlock()
+
+	if polling {
+		return s.poll(ctx, waitTimeoutSeconds)
+	}
+
+	conn, err := s.connect()
+	if errors.Is(err, errStreamUnsupported) {
+		s.mu.Lock()
+		s.polling = true
+		s.mu.Unlock()
+
+		return s.poll(ctx, waitTimeoutSeconds)
+	}
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	timer := time.NewTimer(time.Duration(waitTimeoutSeconds) * time.Second)
+	defer timer.Stop()
+
+	select {
+	case job := <-conn.jobs:
+		return job, true, nil
+	case streamErr := <-conn.done:
+		s.disconnect(conn)
+
+		if streamErr == nil || errors.Is(streamErr, io.EOF) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("job stream: %w", streamErr)
+	case <-timer.C:
+		return nil, false, nil
+	case <-ctx.Done():
+		return nil, false, fmt.Errorf("failed to claim job: %w", ctx.Err())
+	}
+}
+
+// Close ends the stream. A job received but not yet taken by Next is
+// released back to the queue, as are jobs waiting from a batch claim.
+func (s *JobStream) Close() {
+	s.mu.Lock()
+	conn := s.conn
+	s.conn = nil
+	waiting := s.waiting
+	s.waiting = nil
+	s.stopRefreshLocked()
+	s.mu.Unlock()
+
+	if conn != nil {
+		conn.cancel()
+		<-conn.done
+	}
+
+	if len(waiting) > 0 {
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(s.ctx), streamReleaseTimeout)
+		defer cancel()
+
+		for _, job := range waiting {
+			_ = s.c.ReleaseJob(releaseCtx, job.ID)
+		}
+	}
+}
+
+// poll long-polls for a job, first handing out one waiting from an earlier
+// batch claim.
+func (s *JobStream) poll(ctx context.Context, waitTimeoutSeconds int) (*Job, bool, error) {
+	s.mu.Lock()
+
+	if len(s.waiting) > 0 {
+		job := s.waiting[0]
+		s.waiting = s.waiting[1:]
+
+		if len(s.waiting) == 0 {
+			s.stopRefreshLocked()
+		}
+
+		s.mu.Unlock()
+
+		return job, true, nil
+	}
+
+	batchSize := s.batchSize
+	if s.batchUnsupported {
+		batchSize = 1
+	}
+
+	s.mu.Unlock()
+
+	if batchSize <= 1 {
+		return s.c.ClaimJob(ctx, s.habitatID, s.queueID, waitTimeoutSeconds)
+	}
+
+	jobs, err := s.c.ClaimJobs(ctx, s.habitatID, s.queueID, batchSize, waitTimeoutSeconds)
+	if errors.Is(err, ErrBatchClaimUnsupported) {
+		s.mu.Lock()
+		s.batchUnsupported = true
+		s.mu.Unlock()
+
+		return s.c.ClaimJob(ctx, s.habitatID, s.queueID, waitTimeoutSeconds)
+	}
+
+	if err != nil || len(jobs) == 0 {
+		return nil, false, err
+	}
+
+	if len(jobs) > 1 {
+		s.mu.Lock()
+		s.waiting = append(s.waiting, jobs[1:]...)
+
+		if s.stopRefresh == nil {
+			refreshCtx, stop := context.WithCancel(s.ctx)
+			s.stopRefresh = stop
+
+			go s.refreshWaiting(refreshCtx)
+		}
+		s.mu.Unlock()
+	}
+
+	return jobs[0], true, nil
+}
+
+// refreshWaiting heartbeats the jobs waiting from a batch claim until ctx
+// ends. A job whose lease the platform no longer holds is dropped.
+func (s *JobStream) refreshWaiting(ctx context.Context) {
+	ticker := time.NewTicker(waitingLeaseRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		waiting := append([]*Job(nil), s.waiting...)
+		s.mu.Unlock()
+
+		for _, job := range waiting {
+			if _, err := s.c.HeartbeatJob(ctx, job.ID); err != nil && leaseGone(err) {
+				s.dropWaiting(job)
+			}
+		}
+	}
+}
+
+// dropWaiting removes job from the waiting jobs.
+func (s *JobStream) dropWaiting(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if i := slices.Index(s.waiting, job); i >= 0 {
+		s.waiting = slices.Delete(s.waiting, i, i+1)
+	}
+
+	if len(s.waiting) == 0 {
+		s.stopRefreshLocked()
+	}
+}
+
+// stopRefreshLocked stops refreshWaiting. s.mu must be held.
+func (s *JobStream) stopRefreshLocked() {
+	if s.stopRefresh != nil {
+		s.stopRefresh()
+		s.stopRefresh = nil
+	}
+}
+
+// leaseGone reports whether a job heartbeat error means the job is no
+// longer leased to this worker.
+func leaseGone(err error) bool {
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	switch statusErr.Status {
+	case http.StatusNotFound, http.StatusConflict, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *JobStream) connect() (*jobStreamConn, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		return s.conn, nil
+	}
+
+	if s.queueID == "" && s.habitatID == "" {
+		return nil, fmt.Errorf("must provide either habitatID or queueID")
+	}
+
+	body, err := encodeJSON(JobClaimRequest{
+		QueueID:         s.queueID,
+		HabitatID:       s.habitatID,
+		LeaseDurationMs: DefaultLeaseDurationMs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(s.ctx)
+
+	req, err := s.c.newRequest(streamCtx, "POST", s.c.baseURL+"/v1/runner/jobs:stream", bytes.NewReader(body))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := s.c.doWith(s.c.longPollHTTPClient(), req, "/v1/runner/jobs:stream")
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to open job stream: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusNotAcceptable:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		cancel()
+
+		return nil, errStreamUnsupported
+	case http.StatusOK:
+	default:
+		err := unexpectedStatus("open job stream", resp)
+		resp.Body.Close()
+		cancel()
+
+		return nil, err
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); mediaType != "text/event-stream" {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		cancel()
+
+		return nil, errStreamUnsupported
+	}
+
+	conn := &jobStreamConn{cancel: cancel, jobs: make(chan *Job), done: make(chan error, 1)}
+	s.conn = conn
+
+	go s.read(streamCtx, conn, resp.Body)
+
+	return conn, nil
+}
+
+// disconnect forgets conn after its reader has ended.
+func (s *JobStream) disconnect(conn *jobStreamConn) {
+	conn.cancel()
+
+	s.mu.Lock()
+	if s.conn == conn {
+		s.conn = nil
+	}
+	s.mu.Unlock()
+}
+
+// read parses the event stream, handing each job to Next. It ends when the
+// stream does or ctx is canceled, releasing a job nobody took.
+func (s *JobStream) read(ctx context.Context, conn *jobStreamConn, body io.ReadCloser) {
+	defer body.Close()
+
+	err := readServerSentEvents(body, func(event, data string) error {
+		if event != "job" {
+			return nil // keepalives and events this build does not know
+		}
+
+		var response JobClaimResponse
+		if err := decodeJSON(strings.NewReader(data), &response, "failed to parse streamed job"); err != nil {
+			return err
+		}
+
+		job := response.claimedJob()
+
+		select {
+		case conn.jobs <- job:
+			return nil
+		case <-ctx.Done():
+			releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), streamReleaseTimeout)
+			defer cancel()
+
+			_ = s.c.ReleaseJob(releaseCtx, job.ID)
+
+			return ctx.Err()
+		}
+	})
+
+	conn.done <- err
+}
+
+// readServerSentEvents calls handle for each event in r, in the
+// text/event-stream format: "event:" and "data:" fields terminated by a
+// blank line, with ":" comment lines ignored.
+func readServerSentEvents(r io.Reader, handle func(event, data string) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 4<<20)
+
+	var (
+		event string
+		data  []string
+	)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if line == "" {
+			if len(data) > 0 {
+				name := event
+				if name == "" {
+					name = "message"
+				}
+
+				if err := handle(name, strings.Join(data, "\n")); err != nil {
+					return err
+				}
+			}
+
+			event, data = "", nil
+
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	return io.EOF
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func eventStreamResponse(body string) *http.Response {
+	resp := jsonResponse(http.StatusOK, body)
+	resp.Header.Set("Content-Type", "text/event-stream; charset=utf-8")
+
+	return resp
+}
+
+func TestJobStreamDeliversPushedJobs(t *testing.T) {
+	stream := ": connected\n\n" +
+		"event: ping\ndata: {}\n\n" +
+		"event: job\ndata: {\"job\":{\"id\":\"job-1\"},\"execution\":{\"harnessType\":\"claude\"}}\n\n"
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path != "/v1/runner/jobs:stream" {
+			t.Fatalf("path = %q, want the stream endpoint", r.URL.Path)
+		}
+
+		if got := r.Header.Get("Accept"); got != "text/event-stream" {
+			t.Fatalf("Accept = %q, want text/event-stream", got)
+		}
+
+		return eventStreamResponse(stream), nil
+	})
+
+	jobs := c.StreamJobs(t.Context(), "", "queue-1")
+	defer jobs.Close()
+
+	job, claimed, err := jobs.Next(t.Context(), 5)
+	if err != nil || !claimed {
+		t.Fatalf("Next() = %v, %v; want a job", claimed, err)
+	}
+
+	if job.ID != "job-1" || job.GetHarnessType() != "claude" {
+		t.Fatalf("Next() job = %+v, want job-1 for claude", job)
+	}
+
+	// The server closing the stream is an empty wait, not an error.
+	if _, claimed, err := jobs.Next(t.Context(), 5); err != nil || claimed {
+		t.Fatalf("Next() after stream end = %v, %v; want no job and no error", claimed, err)
+	}
+
+	if !jobs.Streaming() {
+		t.Fatal("Streaming() = false, want true")
+	}
+}
+
+func TestJobStreamFallsBackToLongPoll(t *testing.T) {
+	var streamOpens, claims atomic.Int32
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/v1/runner/jobs:stream":
+			streamOpens.Add(1)
+			return jsonResponse(http.StatusNotFound, ""), nil
+		case "/v1/runner/jobs:claim":
+			claims.Add(1)
+			return jsonResponse(http.StatusOK, `{"job":{"id":"job-2"}}`), nil
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+			return nil, io.EOF
+		}
+	})
+
+	jobs := c.StreamJobs(t.Context(), "habitat-1", "")
+	defer jobs.Close()
+
+	for range 2 {
+		job, claimed, err := jobs.Next(t.Context(), 5)
+		if err != nil || !claimed || job.ID != "job-2" {
+			t.Fatalf("Next() = %+v, %v, %v; want job-2 from the claim endpoint", job, claimed, err)
+		}
+	}
+
+	if jobs.Streaming() {
+		t.Fatal("Streaming() = true after fallback, want false")
+	}
+
+	if streamOpens.Load() != 1 || claims.Load() != 2 {
+		t.Fatalf("stream opens = %d, claims = %d; want 1 and 2", streamOpens.Load(), claims.Load())
+	}
+}
+
+func TestJobStreamFallsBackWithoutEventStream(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v1/runner/jobs:stream" {
+			return jsonResponse(http.StatusOK, `{}`), nil
+		}
+
+		return jsonResponse(http.StatusNoContent, ""), nil
+	})
+
+	jobs := c.StreamJobs(t.Context(), "", "queue-1")
+	defer jobs.Close()
+
+	if _, claimed, err := jobs.Next(t.Context(), 1); err != nil || claimed {
+		t.Fatalf("Next() = %v, %v; want an empty poll", claimed, err)
+	}
+
+	if jobs.Streaming() {
+		t.Fatal("Streaming() = true for a non-SSE response, want false")
+	}
+}
+
+func TestJobStreamBatchClaims(t *testing.T) {
+	var (
+		batches  atomic.Int32
+		maxJobs  atomic.Int32
+		released []string
+	)
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		switch {
+		case r.URL.Path == "/v1/runner/jobs:stream":
+			return jsonResponse(http.StatusNotFound, ""), nil
+		case r.URL.Path == "/v1/runner/jobs:claimBatch":
+			var body JobBatchClaimRequest
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Fatalf("decode batch claim: %v", err)
+			}
+
+			batches.Add(1)
+			maxJobs.Store(int32(body.MaxJobs))
+
+			return jsonResponse(http.StatusOK, `{"jobs":[{"job":{"id":"job-a"}},{"job":{"id":"job-b"}},{"job":{"id":"job-c"}}]}`), nil
+		case strings.HasSuffix(r.URL.Path, ":release"):
+			released = append(released, strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/runner/jobs/"), ":release"))
+			return jsonResponse(http.StatusOK, `{}`), nil
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+			return nil, io.EOF
+		}
+	})
+
+	jobs := c.StreamJobs(t.Context(), "", "queue-1")
+	jobs.SetBatchSize(3)
+
+	for _, want := range []string{"job-a", "job-b"} {
+		job, claimed, err := jobs.Next(t.Context(), 5)
+		if err != nil || !claimed || job.ID != want {
+			t.Fatalf("Next() = %+v, %v, %v; want %s", job, claimed, err, want)
+		}
+	}
+
+	if batches.Load() != 1 || maxJobs.Load() != 3 {
+		t.Fatalf("batch claims = %d with maxJobs %d, want 1 with 3", batches.Load(), maxJobs.Load())
+	}
+
+	jobs.Close()
+
+	if len(released) != 1 || released[0] != "job-c" {
+		t.Fatalf("released = %q, want the waiting job-c", released)
+	}
+}
+
+func TestJobStreamBatchClaimFallsBackToSingleClaims(t *testing.T) {
+	var batches, claims atomic.Int32
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/v1/runner/jobs:stream":
+			return jsonResponse(http.StatusNotFound, ""), nil
+		case "/v1/runner/jobs:claimBatch":
+			batches.Add(1)
+			return jsonResponse(http.StatusNotFound, ""), nil
+		case "/v1/runner/jobs:claim":
+			claims.Add(1)
+			return jsonResponse(http.StatusOK, `{"job":{"id":"job-1"}}`), nil
+		default:
+			t.Fatalf("unexpected path %q", r.URL.Path)
+			return nil, io.EOF
+		}
+	})
+
+	jobs := c.StreamJobs(t.Context(), "", "queue-1")
+	defer jobs.Close()
+
+	jobs.SetBatchSize(10)
+
+	for range 2 {
+		if job, claimed, err := jobs.Next(t.Context(), 5); err != nil || !claimed || job.ID != "job-1" {
+			t.Fatalf("Next() = %+v, %v, %v; want job-1 from the claim endpoint", job, claimed, err)
+		}
+	}
+
+	if batches.Load() != 1 || claims.Load() != 2 {
+		t.Fatalf("batch claims = %d, claims = %d; want 1 and 2", batches.Load(), claims.Load())
+	}
+}
+
+func TestReadServerSentEvents(t *testing.T) {
+	input := "data: one\ndata: two\n\n" +
+		": comment\n" +
+		"event: job\ndata:{\"id\":1}\n\n" +
+		"event: empty\n\n"
+
+	var got []string
+
+	err := readServerSentEvents(strings.NewReader(input), func(event, data string) error {
+		got = append(got, event+"="+data)
+		return nil
+	})
+	if err != io.EOF {
+		t.Fatalf("readServerSentEvents() error = %v, want io.EOF", err)
+	}
+
+	want := []string{"message=one\ntwo", `job={"id":1}`}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("events = %q, want %q", got, want)
+	}
+}
@@ -3,12 +3,19 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
+	"strconv"
+	"time"
 )
 
-// ClaimJob claims a job from a habitat or queue.
+// ClaimJob claims a job from a habitat or queue, long-polling the server for
+// up to waitTimeoutSeconds. The request gets its own deadline of the wait
+// plus ClaimTimeoutGrace instead of the client's default timeout, and a
+// server or deadline timeout is reported as no job rather than an error.
 // It reports whether a job was available separately from the returned job pointer.
 func (c *Client) ClaimJob(ctx context.Context, habitatID, queueID string, waitTimeoutSeconds int) (*Job, bool, error) {
 	url := fmt.Sprintf("%s/v1/runner/jobs:claim?wait_timeout_seconds=%d", c.baseURL, waitTimeoutSeconds)
@@ -32,19 +39,29 @@ func (c *Client) ClaimJob(ctx context.Context, habitatID, queueID string, waitTi
 		return nil, false, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	req, err := c.newRequest(ctx, "POST", url, bytes.NewReader(jsonBody))
+	claimCtx, cancel := context.WithTimeout(ctx, time.Duration(waitTimeoutSeconds)*time.Second+ClaimTimeoutGrace)
+	defer cancel()
+
+	req, err := c.newRequest(claimCtx, "POST", url, bytes.NewReader(jsonBody))
 	if err != nil {
 		return nil, false, err
 	}
 
-	resp, err := c.do(req, "/v1/runner/jobs:claim")
+	resp, err := c.doWith(c.longPollHTTPClient(), req, "/v1/runner/jobs:claim")
 	if err != nil {
+		// Our own deadline passing is an empty poll; the caller's ending is not.
+		if ctx.Err() == nil && errors.Is(claimCtx.Err(), context.DeadlineExceeded) {
+			return nil, false, nil
+		}
+
 		return nil, false, fmt.Errorf("failed to claim job: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// 204 No Content = no jobs available.
-	if resp.StatusCode == http.StatusNoContent {
+	// 204 No Content = no jobs available. 408 and 504 mean the server or a
+	// proxy gave up on the long-poll, which is also an empty poll.
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusRequestTimeout, http.StatusGatewayTimeout:
 		return nil, false, nil
 	}
 
@@ -64,18 +81,92 @@ func (c *Client) ClaimJob(ctx context.Context, habitatID, queueID string, waitTi
 			return nil, false, fmt.Errorf("failed to parse job: %w", err)
 		}
 
-		job := response.Job
-		job.Instruction = response.Instruction
-		job.Execution = response.Execution
-		job.WebhookConfig = response.WebhookConfig
-		job.ExecutionError = response.ExecutionError
-
-		return &job, true, nil
+		return response.claimedJob(), true, nil
 	}
 
 	return nil, false, unexpectedStatus("claim job", resp)
 }
 
+// ErrBatchClaimUnsupported is returned by ClaimJobs when the server has no
+// batch claim endpoint; ClaimJob claims one job at a time instead.
+var ErrBatchClaimUnsupported = errors.New("batch claim not supported")
+
+// ClaimJobs claims up to maxJobs jobs from a habitat or queue in one
+// request, long-polling like ClaimJob until at least one is available. An
+// empty wait returns no jobs and no error. Every job returned is leased to
+// this worker, so jobs it does not get to must be heartbeated or released.
+func (c *Client) ClaimJobs(ctx context.Context, habitatID, queueID string, maxJobs, waitTimeoutSeconds int) ([]*Job, error) {
+	url := fmt.Sprintf("%s/v1/runner/jobs:claimBatch?wait_timeout_seconds=%d", c.baseURL, waitTimeoutSeconds)
+
+	if queueID != "" {
+		habitatID = ""
+	}
+
+	if queueID == "" && habitatID == "" {
+		return nil, fmt.Errorf("must provide either habitatID or queueID")
+	}
+
+	jsonBody, err := encodeJSON(JobBatchClaimRequest{
+		QueueID:         queueID,
+		HabitatID:       habitatID,
+		LeaseDurationMs: DefaultLeaseDurationMs,
+		MaxJobs:         max(maxJobs, 1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	claimCtx, cancel := context.WithTimeout(ctx, time.Duration(waitTimeoutSeconds)*time.Second+ClaimTimeoutGrace)
+	defer cancel()
+
+	req, err := c.newRequest(claimCtx, "POST", url, bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.doWith(c.longPollHTTPClient(), req, "/v1/runner/jobs:claimBatch")
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(claimCtx.Err(), context.DeadlineExceeded) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent, http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return nil, nil
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, ErrBatchClaimUnsupported
+	case http.StatusOK:
+	default:
+		return nil, unexpectedStatus("claim jobs", resp)
+	}
+
+	var response JobBatchClaimResponse
+	if err := decodeJSON(resp.Body, &response, "failed to parse claimed jobs"); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(response.Jobs))
+	for i := range response.Jobs {
+		jobs = append(jobs, response.Jobs[i].claimedJob())
+	}
+
+	return jobs, nil
+}
+
+// longPollHTTPClient returns the client's HTTP client without its overall
+// timeout, so a request's context deadline alone bounds it.
+func (c *Client) longPollHTTPClient() *http.Client {
+	hc := *c.httpClient
+	hc.Timeout = 0
+
+	return &hc
+}
+
 // StartJob marks a claimed job as running.
 func (c *Client) StartJob(ctx context.Context, jobID string) (*Job, error) {
 	return c.updateJobStatus(ctx, jobID, "start", "start job")
@@ -104,6 +195,9 @@ func (c *Client) CompleteJob(ctx context.Context, jobID string, output map[strin
 		return err
 	}
 
+	// The platform applies a retried report once.
+	setIdempotencyKey(req)
+
 	resp, err := c.do(req, "/v1/runner/jobs/{job_id}:complete")
 	if err != nil {
 		return fmt.Errorf("failed to complete job: %w", err)
@@ -117,13 +211,15 @@ func (c *Client) CompleteJob(ctx context.Context, jobID string, output map[strin
 	return nil
 }
 
-// FailJob marks a job as failed.
-func (c *Client) FailJob(ctx context.Context, jobID, errorCode, errorMsg string, shouldRetry bool) error {
+// FailJob marks a job as failed. errorDetails is optional structured context
+// that retried attempts can see.
+func (c *Client) FailJob(ctx context.Context, jobID, errorCode, errorMsg string, errorDetails map[string]any, shouldRetry bool) error {
 	url := fmt.Sprintf("%s/v1/runner/jobs/%s:fail", c.baseURL, jobID)
 
 	body := JobFailRequest{
 		ErrorCode:    errorCode,
 		ErrorMessage: errorMsg,
+		ErrorDetails: errorDetails,
 		ShouldRetry:  shouldRetry,
 	}
 
@@ -137,6 +233,9 @@ func (c *Client) FailJob(ctx context.Context, jobID, errorCode, errorMsg string,
 		return err
 	}
 
+	// The platform applies a retried report once.
+	setIdempotencyKey(req)
+
 	resp, err := c.do(req, "/v1/runner/jobs/{job_id}:fail")
 	if err != nil {
 		return fmt.Errorf("failed to fail job: %w", err)
@@ -152,7 +251,7 @@ func (c *Client) FailJob(ctx context.Context, jobID, errorCode, errorMsg string,
 
 // ReleaseJob releases a job back to the queue without completing.
 func (c *Client) ReleaseJob(ctx context.Context, jobID string) error {
-	url := fmt.Sprintf("%s/v1/runner/jobs/%s:release", c.baseURL, jobID)
+	url := fmt.Sprintf("%s/v1/runner/jobs/%s:release", c.baseURL, neturl.PathEscape(jobID))
 
 	req, err := c.newRequest(ctx, "POST", url, emptyJSONBody())
 	if err != nil {
@@ -172,6 +271,124 @@ func (c *Client) ReleaseJob(ctx context.Context, jobID string) error {
 	return nil
 }
 
+// GetJob fetches a job, including the error recorded by its last failed
+// attempt.
+func (c *Client) GetJob(ctx context.Context, jobID string) (*Job, error) {
+	endpointURL := fmt.Sprintf("%s/v1/runner/jobs/%s", c.baseURL, neturl.PathEscape(jobID))
+
+	req, err := c.newRequest(ctx, "GET", endpointURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, "/v1/runner/jobs/{job_id}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("get job", resp)
+	}
+
+	var job Job
+	if err := decodeJSON(resp.Body, &job, "failed to parse job"); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+// JobListOptions narrows ListJobs. Empty fields match every job.
+type JobListOptions struct {
+	HabitatID string
+	QueueID   string
+	Status    string
+
+	// Limit caps how many jobs are returned; zero leaves it to the server.
+	Limit int
+}
+
+// ListJobs lists the organization's jobs, newest first.
+func (c *Client) ListJobs(ctx context.Context, opts JobListOptions) ([]Job, error) {
+	endpoint, err := neturl.Parse(c.baseURL + "/v1/runner/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse jobs endpoint: %w", err)
+	}
+
+	query := endpoint.Query()
+
+	for key, value := range map[string]string{
+		"habitat_id": opts.HabitatID,
+		"queue_id":   opts.QueueID,
+		"status":     opts.Status,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	endpoint.RawQuery = query.Encode()
+
+	req, err := c.newRequest(ctx, "GET", endpoint.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.do(req, "/v1/runner/jobs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("list jobs", resp)
+	}
+
+	var response struct {
+		Data []Job `json:"data"`
+	}
+	if err := decodeJSON(resp.Body, &response, "failed to parse jobs response"); err != nil {
+		return nil, err
+	}
+
+	return response.Data, nil
+}
+
+// RetryJob queues a failed job to run again and returns it.
+func (c *Client) RetryJob(ctx context.Context, jobID string) (*Job, error) {
+	endpointURL := fmt.Sprintf("%s/v1/runner/jobs/%s:retry", c.baseURL, neturl.PathEscape(jobID))
+
+	req, err := c.newRequest(ctx, "POST", endpointURL, emptyJSONBody())
+	if err != nil {
+		return nil, err
+	}
+
+	// A repeated retry request must not queue the job twice.
+	setIdempotencyKey(req)
+
+	resp, err := c.do(req, "/v1/runner/jobs/{job_id}:retry")
+	if err != nil {
+		return nil, fmt.Errorf("failed to retry job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("retry job", resp)
+	}
+
+	var job Job
+	if err := decodeJSON(resp.Body, &job, "failed to parse job"); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
 func (c *Client) updateJobStatus(ctx context.Context, jobID, endpointAction, operation string) (*Job, error) {
 	endpointURL := fmt.Sprintf("%s/v1/runner/jobs/%s:%s", c.baseURL, jobID, endpointAction)
 
@@ -180,6 +397,11 @@ func (c *Client) updateJobStatus(ctx context.Context, jobID, endpointAction, ope
 		return nil, err
 	}
 
+	// Extending the lease again is harmless, so heartbeats retry like reads.
+	if endpointAction == "heartbeat" {
+		setIdempotencyKey(req)
+	}
+
 	resp, err := c.do(req, "/v1/runner/jobs/{job_id}:"+endpointAction)
 	if err != nil {
 		return nil, fmt.Errorf("failed to %s: %w", operation, err)
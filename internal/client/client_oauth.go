@@ -0,0 +1,351 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// OAuthClientID identifies mush to the platform's OAuth endpoints.
+	OAuthClientID = "mush-cli"
+
+	// deviceCodeGrantType is the RFC 8628 grant for exchanging a device code.
+	deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+	// defaultDevicePollInterval is the wait between token polls when the
+	// platform does not name one.
+	defaultDevicePollInterval = 5 * time.Second
+
+	// slowDownIncrement is added to the poll interval each time the
+	// platform answers slow_down, as RFC 8628 asks.
+	slowDownIncrement = 5 * time.Second
+
+	// tokenRefreshSkew renews an access token this long before it expires,
+	// so a request does not race the expiry.
+	tokenRefreshSkew = time.Minute
+)
+
+var (
+	// ErrDeviceAccessDenied is returned when the user declines the device
+	// authorization in the browser.
+	ErrDeviceAccessDenied = errors.New("device authorization was denied")
+
+	// ErrDeviceCodeExpired is returned when the device code expires before
+	// the user approves it.
+	ErrDeviceCodeExpired = errors.New("device code expired before it was approved")
+)
+
+// DeviceAuthorization is the platform's answer to a device authorization
+// request: the code the user enters and where to enter it.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// Token is an OAuth access token and the refresh token that renews it.
+// Grant identifies the device login that issued it and is kept by every
+// renewal, so it names the credential while the tokens themselves rotate.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+	Grant        string    `json:"grant,omitempty"`
+}
+
+// expiring reports whether the token expires within tokenRefreshSkew of now.
+// A token without an expiry never does.
+func (t *Token) expiring(now time.Time) bool {
+	return !t.ExpiresAt.IsZero() && now.Add(tokenRefreshSkew).After(t.ExpiresAt)
+}
+
+// tokenResponse is the body of a successful or failed token request.
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// token converts a successful token response, keeping refreshToken when the
+// platform did not issue a new one.
+func (r *tokenResponse) token(now time.Time, refreshToken string) *Token {
+	tok := &Token{
+		AccessToken:  r.AccessToken,
+		RefreshToken: firstNonEmpty(r.RefreshToken, refreshToken),
+	}
+
+	if r.ExpiresIn > 0 {
+		tok.ExpiresAt = now.Add(time.Duration(r.ExpiresIn) * time.Second)
+	}
+
+	return tok
+}
+
+// oauthState holds the OAuth token a client authenticates with and renews.
+type oauthState struct {
+	mu    sync.Mutex
+	token Token
+	save  func(*Token) error
+}
+
+// SetOAuthToken authenticates the client with tok instead of an API key.
+// The client renews the token with its refresh token shortly before it
+// expires and when the platform rejects it, and passes each renewed token to
+// save, which may be nil. A token stored before tokens carried a grant is
+// given one derived from its refresh token, which the next renewal stores.
+// Call it before the client is shared between goroutines.
+func (c *Client) SetOAuthToken(tok *Token, save func(*Token) error) {
+	state := &oauthState{token: *tok, save: save}
+	if state.token.Grant == "" {
+		sum := sha256.Sum256([]byte(firstNonEmpty(tok.RefreshToken, tok.AccessToken)))
+		state.token.Grant = hex.EncodeToString(sum[:16])
+	}
+
+	c.apiKey = tok.AccessToken
+	c.oauth = state
+}
+
+// grant returns the grant of the client's OAuth token, or "" when the client
+// authenticates with an API key.
+func (c *Client) grant() string {
+	if c.oauth == nil {
+		return ""
+	}
+
+	c.oauth.mu.Lock()
+	defer c.oauth.mu.Unlock()
+
+	return c.oauth.token.Grant
+}
+
+// StartDeviceAuthorization asks the platform for a device code and the code
+// the user enters in a browser to approve it (RFC 8628).
+func (c *Client) StartDeviceAuthorization(ctx context.Context) (*DeviceAuthorization, error) {
+	form := neturl.Values{"client_id": {OAuthClientID}}
+
+	resp, err := c.postForm(ctx, "/v1/oauth/device/code", form)
+	if err != nil {
+		return nil, fmt.Errorf("failed to start device authorization: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus("device authorization", resp)
+	}
+
+	var device DeviceAuthorization
+	if err := decodeJSON(resp.Body, &device, "failed to parse device authorization"); err != nil {
+		return nil, err
+	}
+
+	if device.DeviceCode == "" || device.UserCode == "" || device.VerificationURI == "" {
+		return nil, errors.New("device authorization response is missing the device code, user code, or verification URI")
+	}
+
+	return &device, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user approves or denies
+// device, or its code expires, and returns the issued token.
+func (c *Client) PollDeviceToken(ctx context.Context, device *DeviceAuthorization) (*Token, error) {
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultDevicePollInterval
+	}
+
+	if device.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(device.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	form := neturl.Values{
+		"grant_type":  {deviceCodeGrantType},
+		"device_code": {device.DeviceCode},
+		"client_id":   {OAuthClientID},
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrDeviceCodeExpired
+			}
+
+			return nil, fmt.Errorf("device authorization canceled: %w", ctx.Err())
+		case <-timer.C:
+		}
+
+		body, status, err := c.requestToken(ctx, form)
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, ErrDeviceCodeExpired
+			}
+
+			return nil, err
+		}
+
+		switch {
+		case status == http.StatusOK && body.AccessToken != "":
+			tok := body.token(time.Now(), "")
+			tok.Grant = uuid.NewString()
+
+			return tok, nil
+		case body.Error == "authorization_pending":
+		case body.Error == "slow_down":
+			interval += slowDownIncrement
+		case body.Error == "access_denied":
+			return nil, ErrDeviceAccessDenied
+		case body.Error == "expired_token":
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, tokenError("device token", status, body)
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// RefreshToken exchanges refreshToken for a new access token. The returned
+// token keeps refreshToken when the platform does not rotate it.
+func (c *Client) RefreshToken(ctx context.Context, refreshToken string) (*Token, error) {
+	form := neturl.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+		"client_id":     {OAuthClientID},
+	}
+
+	body, status, err := c.requestToken(ctx, form)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != http.StatusOK || body.AccessToken == "" {
+		return nil, tokenError("refresh token", status, body)
+	}
+
+	return body.token(time.Now(), refreshToken), nil
+}
+
+// requestToken posts form to the token endpoint and decodes the answer,
+// which carries an OAuth error code on failure.
+func (c *Client) requestToken(ctx context.Context, form neturl.Values) (*tokenResponse, int, error) {
+	resp, err := c.postForm(ctx, "/v1/oauth/token", form)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body tokenResponse
+	if err := decodeJSON(resp.Body, &body, "failed to parse token response"); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, resp.StatusCode, unexpectedStatus("token", resp)
+		}
+
+		return nil, resp.StatusCode, err
+	}
+
+	return &body, resp.StatusCode, nil
+}
+
+// postForm sends form to an OAuth endpoint, which takes no Authorization
+// header.
+func (c *Client) postForm(ctx context.Context, route string, form neturl.Values) (*http.Response, error) {
+	req, err := c.newPublicRequest(ctx, "POST", c.baseURL+route, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	return c.do(req, route)
+}
+
+// tokenError describes a failed token request from its OAuth error code.
+func tokenError(operation string, status int, body *tokenResponse) error {
+	switch {
+	case body.ErrorDescription != "":
+		return fmt.Errorf("%s failed: %s", operation, body.ErrorDescription)
+	case body.Error != "":
+		return fmt.Errorf("%s failed: %s", operation, body.Error)
+	default:
+		return fmt.Errorf("%s failed with status %d", operation, status)
+	}
+}
+
+// accessToken returns the bearer token for a request, renewing an OAuth
+// token that is about to expire or that the platform rejected. rejected is
+// the token a request was refused with, or "". A token another request
+// already renewed is not renewed again.
+func (c *Client) accessToken(ctx context.Context, rejected string) (string, error) {
+	if c.oauth == nil {
+		return c.apiKey, nil
+	}
+
+	state := c.oauth
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if state.token.AccessToken != rejected && !state.token.expiring(time.Now()) {
+		return state.token.AccessToken, nil
+	}
+
+	if state.token.RefreshToken == "" {
+		return state.token.AccessToken, errors.New("access token expired and cannot be refreshed; run 'mush auth login --device'")
+	}
+
+	tok, err := c.RefreshToken(ctx, state.token.RefreshToken)
+	if err != nil {
+		return state.token.AccessToken, err
+	}
+
+	tok.Grant = state.token.Grant
+	state.token = *tok
+
+	if state.save != nil {
+		_ = state.save(tok)
+	}
+
+	return tok.AccessToken, nil
+}
+
+// authorize sets req's bearer token from the client's OAuth token, renewing
+// it first when it is about to expire or is rejected, the token the platform
+// refused. Requests without an Authorization header, such as public ones,
+// are left alone.
+func (c *Client) authorize(req *http.Request, rejected string) error {
+	if c.oauth == nil || req.Header.Get("Authorization") == "" {
+		return nil
+	}
+
+	token, err := c.accessToken(req.Context(), rejected)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const identityJSON = `{"credentialName":"device-login","organizationName":"Acme Corp"}`
+
+func TestDeviceAuthorizationFlow(t *testing.T) {
+	t.Parallel()
+
+	var polls atomic.Int32
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("%s sent an Authorization header", r.URL.Path)
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm() error = %v", err)
+		}
+
+		if got := r.PostForm.Get("client_id"); got != OAuthClientID {
+			t.Errorf("client_id = %q, want %q", got, OAuthClientID)
+		}
+
+		switch r.URL.Path {
+		case "/v1/oauth/device/code":
+			return jsonResponse(http.StatusOK, `{"device_code":"dev-1","user_code":"ABCD-EFGH","verification_uri":"https://musher.dev/device","expires_in":60,"interval":1}`), nil
+		case "/v1/oauth/token":
+			if got := r.PostForm.Get("grant_type"); got != deviceCodeGrantType || r.PostForm.Get("device_code") != "dev-1" {
+				t.Errorf("token form = %v, want the device code grant", r.PostForm)
+			}
+
+			if polls.Add(1) == 1 {
+				return jsonResponse(http.StatusBadRequest, `{"error":"authorization_pending"}`), nil
+			}
+
+			return jsonResponse(http.StatusOK, `{"access_token":"access-1","refresh_token":"refresh-1","token_type":"Bearer","expires_in":3600}`), nil
+		default:
+			t.Fatalf("unexpected request %s", r.URL.Path)
+			return nil, nil
+		}
+	})
+
+	device, err := c.StartDeviceAuthorization(t.Context())
+	if err != nil {
+		t.Fatalf("StartDeviceAuthorization() error = %v", err)
+	}
+
+	if device.UserCode != "ABCD-EFGH" || device.VerificationURI != "https://musher.dev/device" {
+		t.Fatalf("device = %+v", device)
+	}
+
+	tok, err := c.PollDeviceToken(t.Context(), device)
+	if err != nil {
+		t.Fatalf("PollDeviceToken() error = %v", err)
+	}
+
+	if tok.AccessToken != "access-1" || tok.RefreshToken != "refresh-1" || tok.ExpiresAt.IsZero() {
+		t.Fatalf("token = %+v", tok)
+	}
+
+	if polls.Load() != 2 {
+		t.Errorf("polls = %d, want 2", polls.Load())
+	}
+}
+
+func TestPollDeviceTokenDenied(t *testing.T) {
+	t.Parallel()
+
+	c := newMockClient(t, func(*http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusBadRequest, `{"error":"access_denied"}`), nil
+	})
+
+	_, err := c.PollDeviceToken(t.Context(), &DeviceAuthorization{DeviceCode: "dev-1", Interval: 1, ExpiresIn: 60})
+	if !errors.Is(err, ErrDeviceAccessDenied) {
+		t.Fatalf("PollDeviceToken() error = %v, want ErrDeviceAccessDenied", err)
+	}
+}
+
+func TestOAuthTokenRefreshesBeforeExpiry(t *testing.T) {
+	t.Parallel()
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v1/oauth/token" {
+			if err := r.ParseForm(); err != nil {
+				t.Fatalf("ParseForm() error = %v", err)
+			}
+
+			if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-1" {
+				t.Errorf("refresh form = %v", r.PostForm)
+			}
+
+			return jsonResponse(http.StatusOK, `{"access_token":"access-2","expires_in":3600}`), nil
+		}
+
+		if got := r.Header.Get("Authorization"); got != "Bearer access-2" {
+			t.Errorf("Authorization = %q, want the renewed token", got)
+		}
+
+		return jsonResponse(http.StatusOK, identityJSON), nil
+	})
+
+	var saved *Token
+
+	c.SetOAuthToken(&Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(time.Second)},
+		func(tok *Token) error {
+			saved = tok
+
+			return nil
+		})
+
+	if _, err := c.ValidateKey(t.Context()); err != nil {
+		t.Fatalf("ValidateKey() error = %v", err)
+	}
+
+	if saved == nil || saved.AccessToken != "access-2" || saved.RefreshToken != "refresh-1" {
+		t.Fatalf("saved token = %+v, want access-2 with the old refresh token", saved)
+	}
+}
+
+func TestOAuthTokenRefreshesAfterRejection(t *testing.T) {
+	t.Parallel()
+
+	var refreshes atomic.Int32
+
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/v1/oauth/token" {
+			refreshes.Add(1)
+
+			return jsonResponse(http.StatusOK, `{"access_token":"access-2","refresh_token":"refresh-2","expires_in":3600}`), nil
+		}
+
+		if r.Header.Get("Authorization") == "Bearer access-1" {
+			return jsonResponse(http.StatusUnauthorized, `{}`), nil
+		}
+
+		return jsonResponse(http.StatusOK, identityJSON), nil
+	})
+
+	c.SetOAuthToken(&Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(time.Hour)}, nil)
+
+	identity, err := c.ValidateKey(t.Context())
+	if err != nil {
+		t.Fatalf("ValidateKey() error = %v", err)
+	}
+
+	if identity.CredentialName != "device-login" || refreshes.Load() != 1 {
+		t.Fatalf("identity = %+v, refreshes = %d; want one refresh", identity, refreshes.Load())
+	}
+
+	if _, err := c.ValidateKey(t.Context()); err != nil || refreshes.Load() != 1 {
+		t.Fatalf("second ValidateKey() = %v, refreshes = %d; want the renewed token reused", err, refreshes.Load())
+	}
+}
@@ -0,0 +1,36 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/musher-dev/mush/internal/telemetry"
+)
+
+// SendUsageReport submits an anonymous usage report. It is sent without
+// credentials so reports cannot be tied to an account.
+func (c *Client) SendUsageReport(ctx context.Context, report *telemetry.Report) error {
+	jsonBody, err := encodeJSON(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal usage report: %w", err)
+	}
+
+	req, err := c.newPublicRequest(ctx, "POST", c.baseURL+"/v1/cli/telemetry", bytes.NewReader(jsonBody))
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.do(req, "/v1/cli/telemetry")
+	if err != nil {
+		return fmt.Errorf("send usage report: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return unexpectedStatus("send usage report", resp)
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/telemetry"
+)
+
+func TestSendUsageReportNoAuthHeader(t *testing.T) {
+	t.Parallel()
+
+	clientHTTP := &http.Client{
+		Transport: bundleRoundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodPost || r.URL.Path != "/v1/cli/telemetry" {
+				t.Fatalf("request = %s %s, want POST /v1/cli/telemetry", r.Method, r.URL.Path)
+			}
+
+			if got := r.Header.Get("Authorization"); got != "" {
+				t.Fatalf("Authorization header = %q, want empty for usage reports", got)
+			}
+
+			var report telemetry.Report
+			if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
+				t.Fatalf("decode usage report: %v", err)
+			}
+
+			if report.InstallID != "install-1" || len(report.Commands) != 1 {
+				t.Fatalf("usage report = %+v", report)
+			}
+
+			return bundleJSONResponse(http.StatusAccepted, `{}`), nil
+		}),
+	}
+
+	c := NewWithHTTPClient("https://example.test", "my-secret-key", clientHTTP)
+
+	err := c.SendUsageReport(t.Context(), &telemetry.Report{
+		InstallID: "install-1",
+		Commands:  []telemetry.CommandStats{{Command: "doctor", Count: 1}},
+	})
+	if err != nil {
+		t.Fatalf("SendUsageReport() error = %v", err)
+	}
+}
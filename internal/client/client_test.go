@@ -3,6 +3,7 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
@@ -117,6 +118,59 @@ func TestClientValidateKeyLegacyWorkspacePayload(t *testing.T) {
 	}
 }
 
+type memoryIdentityCache map[string]*Identity
+
+func (m memoryIdentityCache) LoadIdentity(key string) (*Identity, bool) {
+	identity, ok := m[key]
+	return identity, ok
+}
+
+func (m memoryIdentityCache) StoreIdentity(key string, identity *Identity) error {
+	m[key] = identity
+	return nil
+}
+
+func TestClientCachedIdentity(t *testing.T) {
+	var requests int
+
+	hc := &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+		return jsonResponse(http.StatusOK, `{"credentialName":"my-ci-runner","organizationName":"Acme Corp"}`), nil
+	})}
+
+	cache := memoryIdentityCache{}
+
+	c := NewWithHTTPClient("https://api.test", "test-key", hc)
+	c.SetIdentityCache(cache)
+
+	for attempt := range 2 {
+		identity, err := c.CachedIdentity(t.Context())
+		if err != nil {
+			t.Fatalf("CachedIdentity() attempt %d error = %v", attempt, err)
+		}
+
+		if identity.OrganizationName != "Acme Corp" {
+			t.Fatalf("OrganizationName = %q, want Acme Corp", identity.OrganizationName)
+		}
+	}
+
+	if requests != 1 {
+		t.Fatalf("requests = %d, want 1 (second call should be served from the cache)", requests)
+	}
+
+	// A different key must not reuse the cached identity.
+	other := NewWithHTTPClient("https://api.test", "other-key", hc)
+	other.SetIdentityCache(cache)
+
+	if _, err := other.CachedIdentity(t.Context()); err != nil {
+		t.Fatalf("CachedIdentity() with other key error = %v", err)
+	}
+
+	if requests != 2 {
+		t.Fatalf("requests = %d, want 2 after switching keys", requests)
+	}
+}
+
 func TestClientGetCurrentUserProfile(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -181,6 +235,8 @@ func TestClientClaimJob(t *testing.T) {
 	}{
 		{name: "job available", statusCode: http.StatusOK, body: `{"job":{"id":"job-123","queueId":"queue-123","priority":"normal","status":"queued","attemptNumber":1,"maxAttempts":3}}`, wantJob: true},
 		{name: "no content", statusCode: http.StatusNoContent, body: "", wantJob: false},
+		{name: "request timeout is an empty poll", statusCode: http.StatusRequestTimeout, body: "", wantJob: false},
+		{name: "gateway timeout is an empty poll", statusCode: http.StatusGatewayTimeout, body: "", wantJob: false},
 		{name: "null response rejected", statusCode: http.StatusOK, body: "null", wantJob: false, wantErr: true},
 		{name: "empty body rejected", statusCode: http.StatusOK, body: "", wantJob: false, wantErr: true},
 	}
@@ -259,7 +315,7 @@ func TestClientGetRunnerConfig(t *testing.T) {
 		return jsonResponse(http.StatusOK, `{"configVersion":"1","organizationId":"org-123","generatedAt":"2026-02-13T12:00:00Z","refreshAfterSeconds":300,"providers":{"linear":{"status":"active","credential":{"accessToken":"tok_123"},"flags":{"mcp":true}}}}`), nil
 	})
 
-	cfg, err := c.GetRunnerConfig(t.Context())
+	cfg, err := c.GetRunnerConfig(t.Context(), "")
 	if err != nil {
 		t.Fatalf("GetRunnerConfig() error = %v", err)
 	}
@@ -273,6 +329,25 @@ func TestClientGetRunnerConfig(t *testing.T) {
 	}
 }
 
+func TestClientGetRunnerConfigForHabitat(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if got := r.URL.Query().Get("habitat_id"); got != "hab-1" {
+			t.Fatalf("habitat_id = %q, want hab-1", got)
+		}
+
+		return jsonResponse(http.StatusOK, `{"configVersion":"1","organizationId":"org-123","habitatId":"hab-1","generatedAt":"2026-02-13T12:00:00Z","refreshAfterSeconds":300,"providers":{}}`), nil
+	})
+
+	cfg, err := c.GetRunnerConfig(t.Context(), "hab-1")
+	if err != nil {
+		t.Fatalf("GetRunnerConfig() error = %v", err)
+	}
+
+	if cfg.HabitatID != "hab-1" {
+		t.Fatalf("HabitatID = %q, want hab-1", cfg.HabitatID)
+	}
+}
+
 func TestClientJobLifecycleEndpoints(t *testing.T) {
 	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
 		switch r.URL.Path {
@@ -313,7 +388,7 @@ func TestClientJobLifecycleEndpoints(t *testing.T) {
 		t.Fatalf("CompleteJob() error = %v", err)
 	}
 
-	if err := c.FailJob(t.Context(), "job-123", "execution_error", "test error", true); err != nil {
+	if err := c.FailJob(t.Context(), "job-123", "execution_error", "test error", nil, true); err != nil {
 		t.Fatalf("FailJob() error = %v", err)
 	}
 
@@ -322,6 +397,165 @@ func TestClientJobLifecycleEndpoints(t *testing.T) {
 	}
 }
 
+func TestClientGetJob(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/runner/jobs/job-123" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		return jsonResponse(http.StatusOK, `{"id":"job-123","attemptNumber":2,"maxAttempts":3,`+
+			`"errorCode":"timeout","errorMessage":"timed out","errorDetails":{"step":"tests"}}`), nil
+	})
+
+	job, err := c.GetJob(t.Context(), "job-123")
+	if err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+
+	if job.ErrorCode != "timeout" || job.ErrorMessage != "timed out" || job.ErrorDetails["step"] != "tests" {
+		t.Fatalf("GetJob() = %#v, want the previous failure", job)
+	}
+
+	if !job.IsRetry() || job.MaxAttempts != 3 {
+		t.Fatalf("GetJob() attempt = %d/%d, want a retry", job.AttemptNumber, job.MaxAttempts)
+	}
+}
+
+func TestClientListJobs(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/runner/jobs" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		query := r.URL.Query()
+		if query.Get("queue_id") != "queue-1" || query.Get("status") != "failed" || query.Get("limit") != "5" {
+			t.Fatalf("query = %q, want queue, status, and limit filters", r.URL.RawQuery)
+		}
+
+		if query.Has("habitat_id") {
+			t.Fatalf("query = %q, want no habitat filter", r.URL.RawQuery)
+		}
+
+		return jsonResponse(http.StatusOK, `{"data":[{"id":"job-1","status":"failed"},{"id":"job-2","status":"failed"}]}`), nil
+	})
+
+	jobs, err := c.ListJobs(t.Context(), JobListOptions{QueueID: "queue-1", Status: "failed", Limit: 5})
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+
+	if len(jobs) != 2 || jobs[0].ID != "job-1" || jobs[1].Status != "failed" {
+		t.Fatalf("ListJobs() = %#v, want both jobs", jobs)
+	}
+}
+
+func TestClientRetryJob(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/runner/jobs/job-123:retry" {
+			t.Fatalf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+
+		if r.Header.Get(IdempotencyKeyHeader) == "" {
+			t.Fatalf("%s header missing", IdempotencyKeyHeader)
+		}
+
+		return jsonResponse(http.StatusOK, `{"id":"job-123","status":"pending","attemptNumber":2,"maxAttempts":3}`), nil
+	})
+
+	job, err := c.RetryJob(t.Context(), "job-123")
+	if err != nil {
+		t.Fatalf("RetryJob() error = %v", err)
+	}
+
+	if job.Status != "pending" || job.AttemptNumber != 2 {
+		t.Fatalf("RetryJob() = %#v, want the requeued job", job)
+	}
+}
+
+func TestClientClaimJobDeadlineFollowsWait(t *testing.T) {
+	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+		deadline, ok := r.Context().Deadline()
+		if !ok {
+			t.Fatal("claim request has no deadline")
+		}
+
+		// Longer than DefaultTimeout, so the client timeout must not apply.
+		if remaining := time.Until(deadline); remaining < 90*time.Second || remaining > 90*time.Second+ClaimTimeoutGrace {
+			t.Fatalf("claim deadline in %s, want 90s plus up to %s grace", remaining, ClaimTimeoutGrace)
+		}
+
+		return jsonResponse(http.StatusNoContent, ""), nil
+	})
+
+	if _, claimed, err := c.ClaimJob(t.Context(), "", "queue-1", 90); err != nil || claimed {
+		t.Fatalf("ClaimJob() = %v, %v; want no job", claimed, err)
+	}
+
+	if c.longPollHTTPClient().Timeout != 0 || c.httpClient.Timeout != DefaultTimeout {
+		t.Fatal("long-poll client must drop the timeout without changing the shared client")
+	}
+}
+
+func TestClientClaimJobRequestIDs(t *testing.T) {
+	tests := []struct {
+		name       string
+		serverID   func(sent string) string
+		wantServer bool
+	}{
+		{name: "server echoes the request ID", serverID: func(sent string) string { return sent }},
+		{name: "server assigns its own ID", serverID: func(string) string { return "srv-42" }, wantServer: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sent string
+
+			c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
+				sent = r.Header.Get("X-Request-Id")
+
+				resp := jsonResponse(http.StatusServiceUnavailable, `{}`)
+				resp.Header.Set("X-Request-Id", tt.serverID(sent))
+				resp.Request = r
+
+				return resp, nil
+			})
+
+			_, _, err := c.ClaimJob(t.Context(), "", "queue-1", 0)
+
+			var statusErr *HTTPStatusError
+			if !errors.As(err, &statusErr) {
+				t.Fatalf("ClaimJob() error = %v, want HTTPStatusError", err)
+			}
+
+			if sent == "" || statusErr.RequestID != sent {
+				t.Fatalf("RequestID = %q, want the sent ID %q", statusErr.RequestID, sent)
+			}
+
+			ids := RequestIDs(err)
+
+			if tt.wantServer {
+				if statusErr.ServerRequestID != "srv-42" || len(ids) != 2 || ids[1] != "srv-42" {
+					t.Fatalf("ServerRequestID = %q, RequestIDs() = %v, want srv-42 surfaced", statusErr.ServerRequestID, ids)
+				}
+
+				if !strings.Contains(err.Error(), "server_request_id=srv-42") {
+					t.Fatalf("Error() = %q, want the server request ID", err.Error())
+				}
+
+				return
+			}
+
+			if statusErr.ServerRequestID != "" || len(ids) != 1 {
+				t.Fatalf("ServerRequestID = %q, RequestIDs() = %v, want only the sent ID", statusErr.ServerRequestID, ids)
+			}
+
+			if !strings.Contains(err.Error(), "request_id="+sent) {
+				t.Fatalf("Error() = %q, want the request ID", err.Error())
+			}
+		})
+	}
+}
+
 func TestClientWorkerLifecycleEndpoints(t *testing.T) {
 	now := time.Now().UTC().Format(time.RFC3339Nano)
 	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
@@ -383,6 +617,25 @@ func TestJobFieldsAndHelpers(t *testing.T) {
 	}
 }
 
+func TestJobIsFromLinear(t *testing.T) {
+	tests := []struct {
+		source, ceType string
+		want           bool
+	}{
+		{source: "https://linear.app/acme", ceType: "issue.created", want: true},
+		{source: "webhook", ceType: "linear.issue.assigned", want: true},
+		{source: "webhook", ceType: "app.linear.comment.created", want: true},
+		{source: "https://github.com/acme/repo", ceType: "com.github.push", want: false},
+	}
+
+	for _, tt := range tests {
+		job := Job{CeSource: tt.source, CeType: tt.ceType}
+		if got := job.IsFromLinear(); got != tt.want {
+			t.Errorf("IsFromLinear(%q, %q) = %v, want %v", tt.source, tt.ceType, got, tt.want)
+		}
+	}
+}
+
 func TestClaimJobSendsJSONBody(t *testing.T) {
 	c := newMockClient(t, func(r *http.Request) (*http.Response, error) {
 		if r.URL.Path != "/v1/runner/jobs:claim" {
@@ -59,6 +59,9 @@ func (c *Client) HeartbeatWorker(ctx context.Context, workerID, currentJobID str
 		return nil, err
 	}
 
+	// Heartbeats are safe to repeat, so they retry like reads.
+	setIdempotencyKey(httpReq)
+
 	resp, err := c.do(httpReq, "/v1/runner/workers/{worker_id}:heartbeat")
 	if err != nil {
 		return nil, fmt.Errorf("failed to heartbeat worker: %w", err)
@@ -0,0 +1,262 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	neturl "net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+const (
+	// failbackInterval is how long a failed-over client waits between
+	// health checks of the endpoints ahead of the active one.
+	failbackInterval = 30 * time.Second
+
+	// healthCheckTimeout bounds one endpoint health check.
+	healthCheckTimeout = 3 * time.Second
+)
+
+// endpointSet is a RoundTripper that sends API requests to the first
+// healthy endpoint among a primary base URL and its fallbacks. Requests are
+// built against the primary and rewritten to the active endpoint.
+//
+// A request that fails at the network level, or with a 502 or 503 from a
+// gateway, moves on to the next endpoint, which then stays active. Only
+// requests that are safe to repeat fail over once sent: reads, claims, and
+// the job stream. Any request fails over when its connection could not be
+// opened, since nothing reached the server. While failed over, the
+// endpoints ahead of the active one are health checked every
+// failbackInterval and the first that answers becomes active again.
+type endpointSet struct {
+	urls []string // primary first, without trailing slashes
+	next http.RoundTripper
+	now  func() time.Time
+
+	mu        sync.Mutex
+	active    int
+	lastCheck time.Time
+	checking  bool
+}
+
+func newEndpointSet(primary string, fallbacks []string, next http.RoundTripper) *endpointSet {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	urls := []string{strings.TrimRight(primary, "/")}
+
+	for _, fallback := range fallbacks {
+		fallback = strings.TrimRight(strings.TrimSpace(fallback), "/")
+		if fallback != "" && !slices.Contains(urls, fallback) {
+			urls = append(urls, fallback)
+		}
+	}
+
+	return &endpointSet{urls: urls, next: next, now: time.Now}
+}
+
+// fallback returns the active fallback URL, or false while the primary is
+// active.
+func (s *endpointSet) fallback() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.urls[s.active], s.active != 0
+}
+
+// RoundTrip implements http.RoundTripper.
+func (s *endpointSet) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasPrefix(req.URL.String(), s.urls[0]) {
+		return s.next.RoundTrip(req) //nolint:wrapcheck // transport errors pass through unchanged
+	}
+
+	s.maybeFailBack(req.Context())
+
+	s.mu.Lock()
+	start := s.active
+	s.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		idx := (start + attempt) % len(s.urls)
+
+		out, err := s.rewrite(req, idx, attempt > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := s.next.RoundTrip(out)
+
+		last := attempt == len(s.urls)-1
+		if last || !s.shouldFailOver(req, resp, err) {
+			if err == nil && idx != start {
+				s.setActive(req.Context(), start, idx)
+			}
+
+			return resp, err //nolint:wrapcheck // transport errors pass through unchanged
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+	}
+}
+
+// rewrite returns req aimed at endpoint idx. A retry needs a fresh body.
+func (s *endpointSet) rewrite(req *http.Request, idx int, retry bool) (*http.Request, error) {
+	if idx == 0 && !retry {
+		return req, nil
+	}
+
+	out := req.Clone(req.Context())
+
+	if idx != 0 {
+		u, err := neturl.Parse(s.urls[idx] + strings.TrimPrefix(req.URL.String(), s.urls[0]))
+		if err != nil {
+			return nil, fmt.Errorf("rewrite request for %s: %w", s.urls[idx], err)
+		}
+
+		out.URL = u
+		out.Host = ""
+	}
+
+	if retry && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("replay request body: %w", err)
+		}
+
+		out.Body = body
+	}
+
+	return out, nil
+}
+
+func (s *endpointSet) shouldFailOver(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		return dialFailed(err) || repeatable(req)
+	}
+
+	return repeatable(req) && (resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusServiceUnavailable)
+}
+
+// repeatable reports whether req can be sent again after reaching a server.
+func repeatable(req *http.Request) bool {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return true
+	}
+
+	return strings.HasSuffix(req.URL.Path, "/jobs:claim") || strings.HasSuffix(req.URL.Path, "/jobs:stream")
+}
+
+// dialFailed reports whether err happened before a connection was opened.
+func dialFailed(err error) bool {
+	var opErr *net.OpError
+
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+func (s *endpointSet) setActive(ctx context.Context, from, to int) {
+	s.mu.Lock()
+	changed := s.active == from
+	if changed {
+		s.active = to
+		s.lastCheck = s.now()
+	}
+	s.mu.Unlock()
+
+	if changed {
+		observability.FromContext(ctx).Warn("API endpoint failed over",
+			slog.String("component", "client"),
+			slog.String("event.type", "client.endpoint.failover"),
+			slog.String("endpoint.from", s.urls[from]),
+			slog.String("endpoint.to", s.urls[to]),
+		)
+	}
+}
+
+// maybeFailBack starts a background health check of the endpoints ahead of
+// the active one when one is due.
+func (s *endpointSet) maybeFailBack(ctx context.Context) {
+	s.mu.Lock()
+
+	if s.active == 0 || s.checking || s.now().Sub(s.lastCheck) < failbackInterval {
+		s.mu.Unlock()
+		return
+	}
+
+	s.checking = true
+	active := s.active
+	s.mu.Unlock()
+
+	go func() {
+		checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), healthCheckTimeout)
+		defer cancel()
+
+		healthy := -1
+
+		for idx := range active {
+			if s.healthy(checkCtx, idx) {
+				healthy = idx
+				break
+			}
+		}
+
+		s.mu.Lock()
+		s.checking = false
+		s.lastCheck = s.now()
+		s.mu.Unlock()
+
+		if healthy >= 0 {
+			observability.FromContext(ctx).Info("API endpoint recovered",
+				slog.String("component", "client"),
+				slog.String("event.type", "client.endpoint.failback"),
+				slog.String("endpoint.from", s.urls[active]),
+				slog.String("endpoint.to", s.urls[healthy]),
+			)
+
+			s.mu.Lock()
+			if s.active == active {
+				s.active = healthy
+			}
+			s.mu.Unlock()
+		}
+	}()
+}
+
+// healthy reports whether endpoint idx answers with anything other than a
+// gateway error.
+func (s *endpointSet) healthy(ctx context.Context, idx int) bool {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.urls[idx], http.NoBody)
+	if err != nil {
+		return false
+	}
+
+	resp, err := s.next.RoundTrip(req)
+	if err != nil {
+		return false
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return resp.StatusCode != http.StatusBadGateway && resp.StatusCode != http.StatusServiceUnavailable
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// endpointMock answers requests by host: hosts in down refuse connections,
+// hosts in gateway answer 502, and the rest answer with body.
+type endpointMock struct {
+	mu      sync.Mutex
+	down    map[string]bool
+	gateway map[string]bool
+	hosts   []string
+	bodies  []string
+	body    string
+}
+
+func (m *endpointMock) roundTrip(req *http.Request) (*http.Response, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hosts = append(m.hosts, req.URL.Host)
+
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		m.bodies = append(m.bodies, string(data))
+	}
+
+	if m.down[req.URL.Host] {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+
+	if m.gateway[req.URL.Host] {
+		return jsonResponse(http.StatusBadGateway, ""), nil
+	}
+
+	return jsonResponse(http.StatusOK, m.body), nil
+}
+
+func (m *endpointMock) requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string(nil), m.hosts...)
+}
+
+func newFailoverClient(t *testing.T, m *endpointMock) *Client {
+	t.Helper()
+
+	c := newMockClient(t, m.roundTrip)
+	c.SetFallbackURLs([]string{"https://gw2.test/", "https://api.test", "https://gw3.test"})
+
+	return c
+}
+
+func TestFailover_ReadMovesToNextEndpointAndStays(t *testing.T) {
+	m := &endpointMock{
+		down: map[string]bool{"api.test": true},
+		body: `{"data":[{"id":"hab-1","slug":"local","name":"Local"}]}`,
+	}
+	c := newFailoverClient(t, m)
+
+	if got := c.ActiveBaseURL(); got != "https://api.test" {
+		t.Fatalf("ActiveBaseURL() = %q before any request, want the primary", got)
+	}
+
+	for range 2 {
+		if _, err := c.ListHabitats(t.Context()); err != nil {
+			t.Fatalf("ListHabitats() error = %v", err)
+		}
+	}
+
+	want := []string{"api.test", "gw2.test", "gw2.test"}
+	if got := m.requests(); !slices.Equal(got, want) {
+		t.Fatalf("requests went to %v, want %v", got, want)
+	}
+
+	if got := c.ActiveBaseURL(); got != "https://gw2.test" {
+		t.Fatalf("ActiveBaseURL() = %q, want the fallback", got)
+	}
+}
+
+func TestFailover_ClaimReplaysBodyOnGatewayError(t *testing.T) {
+	m := &endpointMock{
+		gateway: map[string]bool{"api.test": true},
+		body:    `{"data":{"job":{"id":"job-1"}}}`,
+	}
+	c := newFailoverClient(t, m)
+
+	if _, _, err := c.ClaimJob(t.Context(), "", "q-1", 1); err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+
+	if got := m.requests(); !slices.Equal(got, []string{"api.test", "gw2.test"}) {
+		t.Fatalf("requests went to %v, want the primary then gw2", got)
+	}
+
+	if len(m.bodies) != 2 || m.bodies[0] == "" || m.bodies[0] != m.bodies[1] {
+		t.Fatalf("claim bodies = %q, want the same body sent twice", m.bodies)
+	}
+}
+
+func TestFailover_WritesOnlyFailOverWhenUnsent(t *testing.T) {
+	tests := []struct {
+		name    string
+		mock    *endpointMock
+		wantErr bool
+		want    []string
+	}{
+		{
+			name:    "gateway error is returned",
+			mock:    &endpointMock{gateway: map[string]bool{"api.test": true}},
+			wantErr: true,
+			want:    []string{"api.test"},
+		},
+		{
+			name: "refused connection fails over",
+			mock: &endpointMock{down: map[string]bool{"api.test": true}},
+			want: []string{"api.test", "gw2.test"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFailoverClient(t, tt.mock)
+
+			err := c.CompleteJob(t.Context(), "job-1", map[string]any{"ok": true})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CompleteJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := tt.mock.requests(); !slices.Equal(got, tt.want) {
+				t.Fatalf("requests went to %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailover_AllEndpointsDown(t *testing.T) {
+	m := &endpointMock{down: map[string]bool{"api.test": true, "gw2.test": true, "gw3.test": true}}
+	c := newFailoverClient(t, m)
+
+	_, err := c.ListHabitats(t.Context())
+
+	var requestErr *RequestError
+	if !errors.As(err, &requestErr) {
+		t.Fatalf("ListHabitats() error = %v, want a RequestError", err)
+	}
+
+	if got := m.requests(); !slices.Equal(got, []string{"api.test", "gw2.test", "gw3.test"}) {
+		t.Fatalf("requests went to %v, want each endpoint once", got)
+	}
+
+	if got := c.ActiveBaseURL(); got != "https://api.test" {
+		t.Fatalf("ActiveBaseURL() = %q, want the primary kept", got)
+	}
+}
+
+func TestFailover_FailsBackWhenPrimaryRecovers(t *testing.T) {
+	m := &endpointMock{down: map[string]bool{"api.test": true}, body: `{"data":[]}`}
+
+	set := newEndpointSet("https://api.test", []string{"https://gw2.test"}, roundTripFunc(m.roundTrip))
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	set.now = func() time.Time { return now }
+
+	c := newMockClient(t, m.roundTrip)
+	c.httpClient.Transport = set
+	c.endpoints = set
+
+	if _, err := c.ListHabitats(t.Context()); err != nil {
+		t.Fatalf("ListHabitats() error = %v", err)
+	}
+
+	m.mu.Lock()
+	m.down["api.test"] = false
+	m.mu.Unlock()
+
+	now = now.Add(failbackInterval)
+
+	if _, err := c.ListHabitats(t.Context()); err != nil {
+		t.Fatalf("ListHabitats() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.ActiveBaseURL() != "https://api.test" {
+		if time.Now().After(deadline) {
+			t.Fatalf("ActiveBaseURL() = %q, want a fail back to the primary", c.ActiveBaseURL())
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSetFallbackURLs_None(t *testing.T) {
+	m := &endpointMock{body: `{"data":[]}`}
+	c := newMockClient(t, m.roundTrip)
+	c.SetFallbackURLs([]string{"https://gw2.test"})
+	c.SetFallbackURLs(nil)
+
+	if _, ok := c.httpClient.Transport.(*endpointSet); ok {
+		t.Fatal("transport still fails over after clearing the fallback URLs")
+	}
+
+	if got := c.ActiveBaseURL(); got != "https://api.test" {
+		t.Fatalf("ActiveBaseURL() = %q, want the base URL", got)
+	}
+}
@@ -6,14 +6,40 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 
 	"github.com/musher-dev/mush/internal/safeio"
 	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
+// sharedTransports holds one transport per CA bundle path, so every API
+// client in the process, and Prewarm, draws on the same connection pool.
+var sharedTransports sync.Map // CA path → *http.Transport
+
 // NewInstrumentedHTTPClient creates an HTTP client with OpenTelemetry transport
-// and optional custom CA bundle support.
+// and optional custom CA bundle support. Clients created with the same CA
+// bundle share idle connections.
 func NewInstrumentedHTTPClient(caCertFile string) (*http.Client, error) {
+	transport, err := sharedTransport(caCertFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Timeout:   DefaultTimeout,
+		Transport: otelhttp.NewTransport(transport),
+	}, nil
+}
+
+// sharedTransport returns the process-wide API transport for caCertFile,
+// creating it on first use.
+func sharedTransport(caCertFile string) (*http.Transport, error) {
+	customCAPath := strings.TrimSpace(caCertFile)
+
+	if cached, ok := sharedTransports.Load(customCAPath); ok {
+		return cached.(*http.Transport), nil
+	}
+
 	baseTransport, ok := http.DefaultTransport.(*http.Transport)
 	if !ok {
 		return nil, fmt.Errorf("default transport type %T is not *http.Transport", http.DefaultTransport)
@@ -22,7 +48,6 @@ func NewInstrumentedHTTPClient(caCertFile string) (*http.Client, error) {
 	transport := baseTransport.Clone()
 	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
 
-	customCAPath := strings.TrimSpace(caCertFile)
 	if customCAPath != "" {
 		pemData, err := safeio.ReadFile(customCAPath)
 		if err != nil {
@@ -41,8 +66,7 @@ func NewInstrumentedHTTPClient(caCertFile string) (*http.Client, error) {
 		transport.TLSClientConfig.RootCAs = pool
 	}
 
-	return &http.Client{
-		Timeout:   DefaultTimeout,
-		Transport: otelhttp.NewTransport(transport),
-	}, nil
+	actual, _ := sharedTransports.LoadOrStore(customCAPath, transport)
+
+	return actual.(*http.Transport), nil
 }
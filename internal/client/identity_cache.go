@@ -0,0 +1,52 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdentityCache stores the identity returned by ValidateKey so commands can
+// skip the /v1/runner/me round-trip while it is fresh.
+type IdentityCache interface {
+	// LoadIdentity returns the identity cached under key, if still fresh.
+	LoadIdentity(key string) (*Identity, bool)
+	// StoreIdentity records identity under key.
+	StoreIdentity(key string, identity *Identity) error
+}
+
+// SetIdentityCache enables identity caching for CachedIdentity. Successful
+// ValidateKey calls refresh the cache. Call it before the client is shared
+// between goroutines.
+func (c *Client) SetIdentityCache(cache IdentityCache) {
+	c.identityCache = cache
+}
+
+// CachedIdentity returns the runner identity from the identity cache when it
+// holds a fresh entry for this API URL and key, and validates the key
+// otherwise. Use ValidateKey when the key itself must be checked.
+func (c *Client) CachedIdentity(ctx context.Context) (*Identity, error) {
+	if c.identityCache != nil {
+		if identity, ok := c.identityCache.LoadIdentity(c.CacheKey()); ok {
+			return identity, nil
+		}
+	}
+
+	return c.ValidateKey(ctx)
+}
+
+// CacheKey identifies the API URL and credential without exposing the key.
+// Caches keyed by it, like the identity and runner config caches, never hand
+// one credential's data to another. An OAuth token is identified by its
+// grant, so the key survives token renewals and restarts with the renewed
+// token.
+func (c *Client) CacheKey() string {
+	credential := c.apiKey
+	if grant := c.grant(); grant != "" {
+		credential = "oauth:" + grant
+	}
+
+	sum := sha256.Sum256([]byte(c.baseURL + "\n" + credential))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// prewarmTimeout bounds a pre-warm; a slower network gains nothing from it.
+const prewarmTimeout = 5 * time.Second
+
+// ConnectionTiming breaks down how long opening a connection to the API took.
+type ConnectionTiming struct {
+	// Host is the API hostname.
+	Host string
+
+	// DNS, Connect, and TLS are the durations of each phase. A phase that
+	// did not run, such as TLS for an http URL, is zero.
+	DNS     time.Duration
+	Connect time.Duration
+	TLS     time.Duration
+
+	// Total is the time until the first response byte.
+	Total time.Duration
+
+	// Reused is true when an idle pooled connection was used, so no phase
+	// ran at all.
+	Reused bool
+
+	// Error is a user-friendly error summary when no response arrived.
+	Error string
+}
+
+// Summary describes the phases, e.g. "DNS 12ms, connect 31ms, TLS 48ms".
+func (t *ConnectionTiming) Summary() string {
+	if t.Reused {
+		return "reused connection"
+	}
+
+	parts := []string{
+		fmt.Sprintf("DNS %dms", t.DNS.Milliseconds()),
+		fmt.Sprintf("connect %dms", t.Connect.Milliseconds()),
+	}
+
+	if t.TLS > 0 {
+		parts = append(parts, fmt.Sprintf("TLS %dms", t.TLS.Milliseconds()))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Prewarm resolves the API host and opens a connection to it, including the
+// TLS handshake, on the shared transport for caCertFile. The connection is
+// left idle in the pool, so the next API request from a client built with
+// NewInstrumentedHTTPClient skips that latency. Any HTTP response counts as
+// success.
+func Prewarm(ctx context.Context, baseURL, caCertFile string) *ConnectionTiming {
+	timing := &ConnectionTiming{Host: baseURL}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Hostname() == "" {
+		timing.Error = "invalid URL"
+		return timing
+	}
+
+	timing.Host = parsed.Hostname()
+
+	transport, err := sharedTransport(caCertFile)
+	if err != nil {
+		timing.Error = fmt.Sprintf("custom CA bundle error: %v", err)
+		return timing
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prewarmTimeout)
+	defer cancel()
+
+	var dnsStart, connectStart, tlsStart time.Time
+
+	trace := &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { dnsStart = time.Now() },
+		DNSDone:           func(httptrace.DNSDoneInfo) { timing.DNS = time.Since(dnsStart) },
+		ConnectStart:      func(string, string) { connectStart = time.Now() },
+		ConnectDone:       func(string, string, error) { timing.Connect = time.Since(connectStart) },
+		TLSHandshakeStart: func() { tlsStart = time.Now() },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { timing.TLS = time.Since(tlsStart) },
+		GotConn:           func(info httptrace.GotConnInfo) { timing.Reused = info.Reused },
+	}
+
+	req, err := http.NewRequestWithContext(httptrace.WithClientTrace(ctx, trace), http.MethodHead, baseURL, http.NoBody)
+	if err != nil {
+		timing.Error = summarizeNetworkError(err)
+		return timing
+	}
+
+	httpClient := &http.Client{
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		timing.Error = summarizeNetworkError(err)
+		return timing
+	}
+
+	timing.Total = time.Since(start)
+
+	// Reading the body to the end returns the connection to the pool.
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	return timing
+}
+
+// StartPrewarm runs Prewarm in the background, for use while the user is
+// busy with a prompt. The returned function waits for it and returns the
+// timing.
+func StartPrewarm(ctx context.Context, baseURL, caCertFile string) func() *ConnectionTiming {
+	done := make(chan *ConnectionTiming, 1)
+
+	go func() { done <- Prewarm(ctx, baseURL, caCertFile) }()
+
+	var timing *ConnectionTiming
+
+	return func() *ConnectionTiming {
+		if timing == nil {
+			timing = <-done
+		}
+
+		return timing
+	}
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrewarm_TimesPhasesAndPoolsConnection(t *testing.T) {
+	requireLocalListener(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	first := Prewarm(t.Context(), srv.URL, "")
+	if first.Error != "" {
+		t.Fatalf("Prewarm() error = %q", first.Error)
+	}
+
+	if first.Reused {
+		t.Fatal("first Prewarm() reused a connection, want a new one")
+	}
+
+	if first.Total <= 0 {
+		t.Fatalf("Total = %v, want > 0", first.Total)
+	}
+
+	second := Prewarm(t.Context(), srv.URL, "")
+	if !second.Reused {
+		t.Fatal("second Prewarm() opened a new connection, want the pooled one")
+	}
+}
+
+func TestPrewarm_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseURL    string
+		caCertFile string
+		wantErr    string
+	}{
+		{"invalid url", "://bad", "", "invalid URL"},
+		{"missing host", "https://", "", "invalid URL"},
+		{"unreadable ca bundle", "https://api.example.com", "/nonexistent/ca.pem", "custom CA bundle error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timing := Prewarm(t.Context(), tt.baseURL, tt.caCertFile)
+			if !strings.Contains(timing.Error, tt.wantErr) {
+				t.Fatalf("Prewarm() error = %q, want it to contain %q", timing.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStartPrewarm_WaitReturnsTiming(t *testing.T) {
+	requireLocalListener(t)
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	wait := StartPrewarm(t.Context(), srv.URL, "")
+
+	timing := wait()
+	if timing == nil || timing != wait() {
+		t.Fatal("wait() should return the same timing on every call")
+	}
+
+	// The test server's certificate is self-signed, so the handshake runs
+	// and then fails verification.
+	if timing.Error == "" || timing.TLS <= 0 {
+		t.Fatalf("timing = %+v, want a timed TLS handshake that failed", timing)
+	}
+}
+
+func TestConnectionTimingSummary(t *testing.T) {
+	tests := []struct {
+		name   string
+		timing ConnectionTiming
+		want   string
+	}{
+		{
+			name:   "tls",
+			timing: ConnectionTiming{DNS: 12 * time.Millisecond, Connect: 31 * time.Millisecond, TLS: 48 * time.Millisecond},
+			want:   "DNS 12ms, connect 31ms, TLS 48ms",
+		},
+		{
+			name:   "plain http",
+			timing: ConnectionTiming{DNS: 2 * time.Millisecond, Connect: 5 * time.Millisecond},
+			want:   "DNS 2ms, connect 5ms",
+		},
+		{
+			name:   "reused",
+			timing: ConnectionTiming{Reused: true},
+			want:   "reused connection",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.timing.Summary(); got != tt.want {
+				t.Fatalf("Summary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// ResponseCache stores response bodies by URL together with their ETag so
+// unchanged responses can be revalidated instead of downloaded again.
+type ResponseCache interface {
+	// Lookup returns the cached ETag and body for key.
+	Lookup(key string) (etag string, body []byte, ok bool)
+	// Store records body under key with its ETag.
+	Store(key, etag string, body []byte) error
+}
+
+// SetResponseCache enables ETag revalidation for cacheable endpoints such as
+// bundle manifests. Call it before the client is shared between goroutines.
+func (c *Client) SetResponseCache(cache ResponseCache) {
+	c.responseCache = cache
+}
+
+// getPublicCached performs an unauthenticated GET for path. With a response
+// cache configured, a cached copy is revalidated with If-None-Match and reused
+// on 304 Not Modified. Statuses other than 200 and 304 return *HTTPStatusError.
+func (c *Client) getPublicCached(ctx context.Context, path, operation string) ([]byte, error) {
+	endpointURL := c.baseURL + path
+
+	req, err := c.newPublicRequest(ctx, "GET", endpointURL, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		cachedETag string
+		cachedBody []byte
+		cached     bool
+	)
+
+	if c.responseCache != nil {
+		cachedETag, cachedBody, cached = c.responseCache.Lookup(endpointURL)
+		if cached && cachedETag != "" {
+			req.Header.Set("If-None-Match", cachedETag)
+		}
+	}
+
+	resp, err := c.do(req, path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", operation, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && cached {
+		return cachedBody, nil
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, unexpectedStatus(operation, resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: read response: %w", operation, err)
+	}
+
+	// Weak validators are fine here: the body is JSON metadata, and a
+	// semantically equivalent copy is all callers need.
+	if etag := strings.TrimSpace(resp.Header.Get("ETag")); etag != "" && c.responseCache != nil {
+		_ = c.responseCache.Store(endpointURL, etag, body)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,221 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// IdempotencyKeyHeader names a request the platform applies at most once,
+// however often it is sent. Requests carrying it are retried like reads.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy controls how the client repeats requests that fail with a
+// transient error: a network error, or a 429, 500, 502, 503, or 504.
+//
+// Reads and requests with an Idempotency-Key are retried for any transient
+// error. Other writes are retried only when the connection could not be
+// opened, since nothing reached the server.
+type RetryPolicy struct {
+	// MaxAttempts is the number of tries, including the first. One or less
+	// turns retries off.
+	MaxAttempts int
+
+	// BaseDelay is the wait before the first retry. It doubles for each
+	// later one, up to MaxDelay, and each wait is jittered by up to half.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// SetRetryPolicy sets how the client retries transient failures. Clients
+// start with retries off.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// delay returns the wait before retry number retry (1 for the first),
+// stretched to the server's Retry-After when that is longer.
+func (p RetryPolicy) delay(retry int, resp *http.Response) time.Duration {
+	backoff := p.BaseDelay
+	for i := 1; i < retry && backoff < p.MaxDelay; i++ {
+		backoff *= 2
+	}
+
+	backoff = min(backoff, p.MaxDelay)
+	if backoff > 0 {
+		backoff = backoff/2 + rand.N(backoff/2+1) //nolint:gosec // jitter needs no cryptographic randomness
+	}
+
+	if after := retryAfter(resp); after > backoff {
+		backoff = min(after, p.MaxDelay)
+	}
+
+	return backoff
+}
+
+// retryAfter returns the delay a 429 or 503 response asks for in seconds,
+// or zero.
+func retryAfter(resp *http.Response) time.Duration {
+	if resp == nil || (resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable) {
+		return 0
+	}
+
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// retryReason returns why a response or error is worth retrying, or "" when
+// it is final.
+func retryReason(req *http.Request, resp *http.Response, err error) string {
+	if req.Context().Err() != nil {
+		return ""
+	}
+
+	if err != nil {
+		if dialFailed(err) {
+			return "dial"
+		}
+
+		if idempotent(req) {
+			return "network"
+		}
+
+		return ""
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if idempotent(req) {
+			return strconv.Itoa(resp.StatusCode)
+		}
+	}
+
+	return ""
+}
+
+// idempotent reports whether req can be sent again after reaching a server.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	}
+
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// setIdempotencyKey marks req as safe to retry. The key stays the same
+// across the request's retries, so the platform applies it once.
+func setIdempotencyKey(req *http.Request) {
+	req.Header.Set(IdempotencyKeyHeader, uuid.NewString())
+}
+
+// sendWithRetry sends req, repeating it under the client's retry policy.
+func (c *Client) sendWithRetry(httpClient *http.Client, req *http.Request, route string, logger *slog.Logger) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, err := httpClient.Do(req)
+
+		if attempt >= c.retry.MaxAttempts {
+			return resp, err //nolint:wrapcheck // doWith wraps the final error
+		}
+
+		reason := retryReason(req, resp, err)
+		if reason == "" {
+			return resp, err //nolint:wrapcheck // doWith wraps the final error
+		}
+
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return resp, err //nolint:wrapcheck // doWith wraps the final error
+		}
+
+		delay := c.retry.delay(attempt, resp)
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		logger.Warn("request retrying",
+			slog.String("event.type", "http.request.retry"),
+			slog.Int("http.request.attempt", attempt+1),
+			slog.String("retry.reason", reason),
+			slog.Int64("retry.delay_ms", delay.Milliseconds()),
+		)
+		recordRetry(req.Context(), route, reason)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+
+			return nil, fmt.Errorf("retry after %s canceled: %w", reason, req.Context().Err())
+		case <-timer.C:
+		}
+
+		req = next
+	}
+}
+
+// rewindRequest returns a copy of req that can be sent again.
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	out := req.Clone(req.Context())
+
+	if req.Body == nil || req.Body == http.NoBody {
+		return out, nil
+	}
+
+	if req.GetBody == nil {
+		return nil, errors.New("request body cannot be replayed")
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("replay request body: %w", err)
+	}
+
+	out.Body = body
+
+	return out, nil
+}
+
+var (
+	retryCounterOnce sync.Once
+	retryCounter     metric.Int64Counter
+)
+
+// recordRetry counts a retry on the mush.client.retries metric.
+func recordRetry(ctx context.Context, route, reason string) {
+	retryCounterOnce.Do(func() {
+		retryCounter, _ = observability.Meter("mush.client").Int64Counter("mush.client.retries",
+			metric.WithDescription("API requests sent again after a transient failure"),
+		)
+	})
+
+	if retryCounter == nil {
+		return
+	}
+
+	retryCounter.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("http.route", route),
+		attribute.String("retry.reason", reason),
+	))
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// flakyServer answers each request with the next status in statuses, or
+// refuses the connection for a zero status, then answers 200.
+type flakyServer struct {
+	mu       sync.Mutex
+	statuses []int
+	keys     []string
+	bodies   []string
+}
+
+func (s *flakyServer) roundTrip(req *http.Request) (*http.Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.keys = append(s.keys, req.Header.Get(IdempotencyKeyHeader))
+
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		s.bodies = append(s.bodies, string(body))
+	}
+
+	if len(s.statuses) == 0 {
+		return jsonResponse(http.StatusOK, `{}`), nil
+	}
+
+	status := s.statuses[0]
+	s.statuses = s.statuses[1:]
+
+	if status == 0 {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	}
+
+	return jsonResponse(status, ""), nil
+}
+
+func (s *flakyServer) requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.keys)
+}
+
+func newRetryClient(t *testing.T, s *flakyServer) *Client {
+	t.Helper()
+
+	c := newMockClient(t, s.roundTrip)
+	c.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond})
+
+	return c
+}
+
+func TestRetry_ReadsRetryTransientStatuses(t *testing.T) {
+	s := &flakyServer{statuses: []int{http.StatusServiceUnavailable, http.StatusInternalServerError}}
+	c := newRetryClient(t, s)
+
+	if _, err := c.GetJob(t.Context(), "job-1"); err != nil {
+		t.Fatalf("GetJob() error = %v", err)
+	}
+
+	if got := s.requests(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	s := &flakyServer{statuses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}}
+	c := newRetryClient(t, s)
+
+	_, err := c.GetJob(t.Context(), "job-1")
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("GetJob() error = %v, want the last 502", err)
+	}
+
+	if got := s.requests(); got != 3 {
+		t.Fatalf("requests = %d, want 3", got)
+	}
+}
+
+func TestRetry_CompletionKeepsItsIdempotencyKey(t *testing.T) {
+	s := &flakyServer{statuses: []int{http.StatusGatewayTimeout}}
+	c := newRetryClient(t, s)
+
+	if err := c.CompleteJob(t.Context(), "job-1", map[string]any{"ok": true}); err != nil {
+		t.Fatalf("CompleteJob() error = %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.keys) != 2 || s.keys[0] == "" || s.keys[0] != s.keys[1] {
+		t.Fatalf("%s headers = %q, want one key sent twice", IdempotencyKeyHeader, s.keys)
+	}
+
+	if s.bodies[0] == "" || s.bodies[0] != s.bodies[1] {
+		t.Fatalf("bodies = %q, want the same body replayed", s.bodies)
+	}
+}
+
+func TestRetry_OtherWritesRetryOnlyUnsentRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int
+		wantErr      bool
+	}{
+		{name: "server error is final", statuses: []int{http.StatusInternalServerError}, wantRequests: 1, wantErr: true},
+		{name: "refused connection is retried", statuses: []int{0}, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &flakyServer{statuses: tt.statuses}
+			c := newRetryClient(t, s)
+
+			err := c.ReleaseJob(t.Context(), "job-1")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReleaseJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got := s.requests(); got != tt.wantRequests {
+				t.Fatalf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetry_OffByDefault(t *testing.T) {
+	s := &flakyServer{statuses: []int{http.StatusServiceUnavailable}}
+	c := newMockClient(t, s.roundTrip)
+
+	if _, err := c.GetJob(t.Context(), "job-1"); err == nil {
+		t.Fatal("GetJob() error = nil, want the 503")
+	}
+
+	if got := s.requests(); got != 1 {
+		t.Fatalf("requests = %d, want 1", got)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	tests := []struct {
+		name    string
+		retry   int
+		resp    *http.Response
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{name: "first retry", retry: 1, wantMin: 50 * time.Millisecond, wantMax: 100 * time.Millisecond},
+		{name: "doubles", retry: 3, wantMin: 200 * time.Millisecond, wantMax: 400 * time.Millisecond},
+		{name: "capped", retry: 10, wantMin: 500 * time.Millisecond, wantMax: time.Second},
+		{
+			name:    "honors Retry-After up to the cap",
+			retry:   1,
+			resp:    &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"30"}}},
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 20 {
+				if got := policy.delay(tt.retry, tt.resp); got < tt.wantMin || got > tt.wantMax {
+					t.Fatalf("delay(%d) = %v, want within [%v, %v]", tt.retry, got, tt.wantMin, tt.wantMax)
+				}
+			}
+		})
+	}
+}
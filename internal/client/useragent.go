@@ -0,0 +1,95 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/musher-dev/mush/internal/buildinfo"
+)
+
+// Capabilities are hints about the machine mush runs on, sent in the
+// User-Agent so the platform can serve execution configs and deprecation
+// warnings that fit it.
+type Capabilities struct {
+	OS        string
+	Arch      string
+	Terminal  string
+	Harnesses []string
+}
+
+// UserAgent returns the User-Agent for caps, for example
+// "mush/1.4.0 (linux; amd64; term=xterm-256color; harnesses=claude,codex)".
+// Empty fields are left out.
+func (caps Capabilities) UserAgent() string {
+	var hints []string
+
+	for _, hint := range []string{caps.OS, caps.Arch} {
+		if hint = userAgentToken(hint); hint != "" {
+			hints = append(hints, hint)
+		}
+	}
+
+	if term := userAgentToken(caps.Terminal); term != "" {
+		hints = append(hints, "term="+term)
+	}
+
+	var harnesses []string
+
+	for _, name := range caps.Harnesses {
+		if name = userAgentToken(name); name != "" {
+			harnesses = append(harnesses, name)
+		}
+	}
+
+	if len(harnesses) > 0 {
+		hints = append(hints, "harnesses="+strings.Join(harnesses, ","))
+	}
+
+	if len(hints) == 0 {
+		return defaultUserAgent()
+	}
+
+	return defaultUserAgent() + " (" + strings.Join(hints, "; ") + ")"
+}
+
+func defaultUserAgent() string {
+	return "mush/" + buildinfo.Version
+}
+
+// userAgentToken keeps the characters of value that are safe inside a
+// User-Agent comment.
+func userAgentToken(value string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		case strings.ContainsRune("-._+", r):
+			return r
+		default:
+			return -1
+		}
+	}, strings.TrimSpace(value))
+}
+
+// SetCapabilities sends caps in the User-Agent of every request. Callers
+// leave it unset when the user has turned capability hints off.
+func (c *Client) SetCapabilities(caps Capabilities) {
+	c.capabilities = &caps
+}
+
+// Capabilities returns the hints set with SetCapabilities, or false when
+// none are sent.
+func (c *Client) Capabilities() (Capabilities, bool) {
+	if c.capabilities == nil {
+		return Capabilities{}, false
+	}
+
+	return *c.capabilities, true
+}
+
+func (c *Client) userAgent() string {
+	if c.capabilities == nil {
+		return defaultUserAgent()
+	}
+
+	return c.capabilities.UserAgent()
+}
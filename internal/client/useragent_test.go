@@ -0,0 +1,69 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/buildinfo"
+)
+
+func TestCapabilitiesUserAgent(t *testing.T) {
+	base := "mush/" + buildinfo.Version
+
+	tests := []struct {
+		name string
+		caps Capabilities
+		want string
+	}{
+		{
+			name: "none",
+			want: base,
+		},
+		{
+			name: "all hints",
+			caps: Capabilities{OS: "linux", Arch: "amd64", Terminal: "xterm-256color", Harnesses: []string{"claude", "codex"}},
+			want: base + " (linux; amd64; term=xterm-256color; harnesses=claude,codex)",
+		},
+		{
+			name: "unsafe characters dropped",
+			caps: Capabilities{OS: "darwin", Terminal: "xterm (custom); x", Harnesses: []string{"claude", "  "}},
+			want: base + " (darwin; term=xtermcustomx; harnesses=claude)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.caps.UserAgent(); got != tt.want {
+				t.Fatalf("UserAgent() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientSendsCapabilities(t *testing.T) {
+	var agents []string
+
+	c := newMockClient(t, func(req *http.Request) (*http.Response, error) {
+		agents = append(agents, req.Header.Get("User-Agent"))
+		return jsonResponse(http.StatusOK, `{"data":[]}`), nil
+	})
+
+	if _, ok := c.Capabilities(); ok {
+		t.Fatal("Capabilities() reported hints before any were set")
+	}
+
+	if _, err := c.ListHabitats(t.Context()); err != nil {
+		t.Fatalf("ListHabitats() error = %v", err)
+	}
+
+	c.SetCapabilities(Capabilities{OS: "linux", Arch: "arm64", Harnesses: []string{"claude"}})
+
+	if _, err := c.ListHabitats(t.Context()); err != nil {
+		t.Fatalf("ListHabitats() error = %v", err)
+	}
+
+	base := "mush/" + buildinfo.Version
+	if len(agents) != 2 || agents[0] != base || agents[1] != base+" (linux; arm64; harnesses=claude)" {
+		t.Fatalf("User-Agent headers = %q", agents)
+	}
+}
@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent names a job lifecycle event posted to a webhook.
+type WebhookEvent string
+
+// WebhookEvent values.
+const (
+	WebhookJobStarted   WebhookEvent = "job.started"
+	WebhookJobProgress  WebhookEvent = "job.progress"
+	WebhookJobCompleted WebhookEvent = "job.completed"
+	WebhookJobFailed    WebhookEvent = "job.failed"
+)
+
+// WebhookSignatureHeader carries "sha256=<hex HMAC-SHA256 of the body>" when
+// the webhook has a secret.
+const WebhookSignatureHeader = "X-Musher-Signature"
+
+// webhookTimeout bounds each delivery so a slow receiver cannot hold up a job.
+const webhookTimeout = 10 * time.Second
+
+// WebhookConfig asks the runner to post a job's progress to a URL while it
+// runs.
+type WebhookConfig struct {
+	// URL receives a JSON POST for each event. It must be http or https.
+	URL string `json:"url"`
+
+	// Events limits the events posted (empty = all of them).
+	Events []WebhookEvent `json:"events,omitempty"`
+
+	// Headers are added to every delivery, such as for authentication.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Secret signs each body in the X-Musher-Signature header.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Validate reports whether the runner can deliver to w.
+func (w *WebhookConfig) Validate() error {
+	parsed, err := url.Parse(w.URL)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("webhook url %q must be an absolute http or https URL", w.URL)
+	}
+
+	for _, event := range w.Events {
+		switch event {
+		case WebhookJobStarted, WebhookJobProgress, WebhookJobCompleted, WebhookJobFailed:
+		default:
+			return fmt.Errorf("webhook event %q is not supported", event)
+		}
+	}
+
+	for name := range w.Headers {
+		if strings.EqualFold(name, WebhookSignatureHeader) || strings.EqualFold(name, "Content-Type") {
+			return fmt.Errorf("webhook header %q is set by the runner", name)
+		}
+
+		if name == "" || strings.ContainsAny(name, " :\r\n") {
+			return fmt.Errorf("webhook header name %q is invalid", name)
+		}
+	}
+
+	return nil
+}
+
+// Wants reports whether event should be posted to w.
+func (w *WebhookConfig) Wants(event WebhookEvent) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+
+	for _, want := range w.Events {
+		if want == event {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WebhookPayload is the JSON body of a webhook delivery.
+type WebhookPayload struct {
+	Event         WebhookEvent `json:"event"`
+	JobID         string       `json:"jobId"`
+	QueueID       string       `json:"queueId,omitempty"`
+	AttemptNumber int          `json:"attemptNumber,omitempty"`
+	Time          time.Time    `json:"time"`
+
+	// ElapsedMs is how long the job has been running.
+	ElapsedMs int64 `json:"elapsedMs"`
+
+	// Turns and CostUSD are the harness's usage so far, when it reports any.
+	Turns   int      `json:"turns,omitempty"`
+	CostUSD *float64 `json:"costUsd,omitempty"`
+
+	// ErrorCode and ErrorMessage describe a job.failed event.
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+}
+
+// PostWebhook delivers payload, usually a *WebhookPayload, to hook as JSON.
+// The API key is never sent; hook's own headers authenticate the delivery.
+func (c *Client) PostWebhook(ctx context.Context, hook *WebhookConfig, payload any) error {
+	body, err := encodeJSON(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", c.userAgent())
+	req.Header.Set("X-Request-Id", uuid.NewString())
+
+	if hook.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(hook.Secret, body))
+	}
+
+	resp, err := c.doWith(c.httpClient, req, "webhook")
+	if err != nil {
+		return fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return unexpectedStatus("post webhook", resp)
+	}
+
+	return nil
+}
+
+// SignWebhook returns the X-Musher-Signature value for body.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func hasWebhookConfig(raw json.RawMessage) bool {
+	raw = bytes.TrimSpace(raw)
+
+	return len(raw) > 0 && !bytes.Equal(raw, []byte("null"))
+}
+
+// decodeWebhookConfig decodes and validates a claim's webhook config.
+func decodeWebhookConfig(raw json.RawMessage) (*WebhookConfig, error) {
+	var hook WebhookConfig
+	if err := json.Unmarshal(raw, &hook); err != nil {
+		return nil, errors.New("webhook config is not an object with a url")
+	}
+
+	if err := hook.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &hook, nil
+}
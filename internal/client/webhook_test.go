@@ -0,0 +1,178 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestWebhookConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		hook    WebhookConfig
+		wantErr string
+	}{
+		{
+			name: "valid",
+			hook: WebhookConfig{
+				URL:     "https://hooks.example.com/jobs",
+				Events:  []WebhookEvent{WebhookJobStarted, WebhookJobFailed},
+				Headers: map[string]string{"Authorization": "Bearer token"},
+			},
+		},
+		{
+			name:    "relative url",
+			hook:    WebhookConfig{URL: "/jobs"},
+			wantErr: "absolute http or https URL",
+		},
+		{
+			name:    "unsupported scheme",
+			hook:    WebhookConfig{URL: "ftp://hooks.example.com"},
+			wantErr: "absolute http or https URL",
+		},
+		{
+			name:    "unknown event",
+			hook:    WebhookConfig{URL: "https://hooks.example.com", Events: []WebhookEvent{"job.paused"}},
+			wantErr: `event "job.paused" is not supported`,
+		},
+		{
+			name:    "reserved header",
+			hook:    WebhookConfig{URL: "https://hooks.example.com", Headers: map[string]string{"x-musher-signature": "forged"}},
+			wantErr: "is set by the runner",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.hook.Validate()
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClaimedJobWebhookConfig(t *testing.T) {
+	tests := []struct {
+		name      string
+		claim     string
+		wantURL   string
+		wantError string
+	}{
+		{
+			name:  "absent",
+			claim: `{"job":{"id":"job-1"}}`,
+		},
+		{
+			name:  "null",
+			claim: `{"job":{"id":"job-1"},"webhookConfig":null}`,
+		},
+		{
+			name:    "valid",
+			claim:   `{"job":{"id":"job-1"},"webhookConfig":{"url":"https://hooks.example.com","events":["job.completed"]}}`,
+			wantURL: "https://hooks.example.com",
+		},
+		{
+			name:      "wrong shape",
+			claim:     `{"job":{"id":"job-1"},"webhookConfig":{"url":42}}`,
+			wantError: "not an object with a url",
+		},
+		{
+			name:      "invalid",
+			claim:     `{"job":{"id":"job-1"},"webhookConfig":{"url":"mailto:ops@example.com"}}`,
+			wantError: "absolute http or https URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claim JobClaimResponse
+			if err := json.Unmarshal([]byte(tt.claim), &claim); err != nil {
+				t.Fatalf("decode claim: %v", err)
+			}
+
+			job := claim.claimedJob()
+
+			gotURL := ""
+			if job.WebhookConfig != nil {
+				gotURL = job.WebhookConfig.URL
+			}
+
+			if gotURL != tt.wantURL {
+				t.Fatalf("WebhookConfig.URL = %q, want %q", gotURL, tt.wantURL)
+			}
+
+			if (tt.wantError == "" && job.WebhookError != "") || !strings.Contains(job.WebhookError, tt.wantError) {
+				t.Fatalf("WebhookError = %q, want %q", job.WebhookError, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var (
+		gotReq  *http.Request
+		gotBody []byte
+	)
+
+	c := newMockClient(t, func(req *http.Request) (*http.Response, error) {
+		gotReq = req
+		gotBody, _ = io.ReadAll(req.Body)
+
+		return jsonResponse(http.StatusNoContent, ""), nil
+	})
+
+	hook := &WebhookConfig{
+		URL:     "https://hooks.example.com/jobs",
+		Headers: map[string]string{"X-Team": "platform"},
+		Secret:  "shh",
+	}
+
+	err := c.PostWebhook(t.Context(), hook, &WebhookPayload{Event: WebhookJobStarted, JobID: "job-1"})
+	if err != nil {
+		t.Fatalf("PostWebhook() error = %v", err)
+	}
+
+	if gotReq.URL.String() != hook.URL || gotReq.Method != http.MethodPost {
+		t.Fatalf("request = %s %s, want POST %s", gotReq.Method, gotReq.URL, hook.URL)
+	}
+
+	if gotReq.Header.Get("Authorization") != "" {
+		t.Fatal("webhook delivery sent the API key")
+	}
+
+	if gotReq.Header.Get("X-Team") != "platform" {
+		t.Fatalf("X-Team = %q, want the configured header", gotReq.Header.Get("X-Team"))
+	}
+
+	if got, want := gotReq.Header.Get(WebhookSignatureHeader), SignWebhook("shh", gotBody); got != want {
+		t.Fatalf("%s = %q, want %q", WebhookSignatureHeader, got, want)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(gotBody, &payload); err != nil || payload.Event != WebhookJobStarted || payload.JobID != "job-1" {
+		t.Fatalf("payload = %s (err %v)", gotBody, err)
+	}
+}
+
+func TestPostWebhook_ErrorStatus(t *testing.T) {
+	c := newMockClient(t, func(*http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusBadGateway, ""), nil
+	})
+
+	err := c.PostWebhook(t.Context(), &WebhookConfig{URL: "https://hooks.example.com"}, &WebhookPayload{Event: WebhookJobProgress})
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Fatalf("PostWebhook() error = %v, want the 502 status", err)
+	}
+}
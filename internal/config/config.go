@@ -2,8 +2,12 @@
 //
 // Configuration sources (in priority order):
 //  1. Environment variables (MUSHER_*)
-//  2. Config file (<user config dir>/musher/config.yaml)
-//  3. Built-in defaults
+//  2. The active profile's settings (profiles.<name> in the config file)
+//  3. Config file (<user config dir>/musher/config.yaml)
+//  4. Built-in defaults
+//
+// The active profile is named by MUSH_PROFILE, which the --profile flag
+// sets; without one, or with "default", no profile settings apply.
 package config
 
 import (
@@ -12,6 +16,8 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -25,40 +31,89 @@ const (
 	DefaultAPIURL = "https://api.musher.dev"
 	// DefaultPollInterval is the default poll interval as a duration string.
 	DefaultPollInterval = "30s"
+	// DefaultPollIntervalMax is the default ceiling the poll interval backs
+	// off to while the queue is empty.
+	DefaultPollIntervalMax = "5m"
 	// DefaultHeartbeatInterval is the default heartbeat interval as a duration string.
 	DefaultHeartbeatInterval = "30s"
 	// DefaultUpdateCheckInterval is the default background update check interval.
 	DefaultUpdateCheckInterval = "24h"
+	// DefaultTimeoutWarning is how long before the execution timeout a job is
+	// warned by default.
+	DefaultTimeoutWarning = "2m"
+	// DefaultGitBranchPrefix starts the name of the branch the git workflow
+	// commits a job's changes to.
+	DefaultGitBranchPrefix = "mush/"
+	// MaxClaimBatchSize caps how many jobs one claim request may take.
+	MaxClaimBatchSize = 50
 )
 
 const (
 	defaultPollIntervalDuration      = 30 * time.Second
+	defaultPollIntervalMaxDuration   = 5 * time.Minute
 	defaultHeartbeatIntervalDuration = 30 * time.Second
 	minIntervalDuration              = 1 * time.Second
+	defaultTimeoutWarning            = 2 * time.Minute
+	defaultAPIRetryMaxAttempts       = 3
+	defaultAPIRetryBaseDelay         = 500 * time.Millisecond
+	defaultAPIRetryMaxDelay          = 10 * time.Second
 )
 
+// profilesKey holds the named profiles' settings in the config file.
+const profilesKey = "profiles"
+
+// profileNamePattern matches valid profile names. Dots would split the
+// name into nested config keys.
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
 // Config holds the Mush configuration.
 type Config struct {
-	v *viper.Viper
+	v       *viper.Viper
+	profile string
 }
 
-// Load reads configuration from all sources.
+// Load reads configuration from all sources for the active profile.
 func Load() *Config {
+	return LoadProfile(paths.ActiveProfile())
+}
+
+// LoadProfile reads configuration from all sources, applying the named
+// profile's settings over the config file's. Names are not case-sensitive;
+// an empty name, or "default", loads the default profile.
+func LoadProfile(name string) *Config {
+	name = strings.ToLower(name)
+	if name == paths.DefaultProfile {
+		name = ""
+	}
+
 	v := viper.New()
 
 	// Set defaults
 	v.SetDefault("api.url", DefaultAPIURL)
+	v.SetDefault("api.capability_hints", true)
+	v.SetDefault("auth.store", "auto")
 	v.SetDefault("worker.poll_interval", DefaultPollInterval)
+	v.SetDefault("worker.poll_interval_max", DefaultPollIntervalMax)
 	v.SetDefault("worker.heartbeat_interval", DefaultHeartbeatInterval)
+	v.SetDefault("worker.claim_batch_size", 1)
+	v.SetDefault("worker.worktree_guard", WorktreeGuardOff)
+	v.SetDefault("worker.timeout_warning", DefaultTimeoutWarning)
+	v.SetDefault("worker.git_workflow", GitWorkflowOff)
+	v.SetDefault("worker.git_branch_prefix", DefaultGitBranchPrefix)
+	v.SetDefault("worker.network_recording", false)
+	v.SetDefault("worker.metrics_addr", "")
 	v.SetDefault("network.ca_cert_file", "")
 	v.SetDefault("tui", true)
+	v.SetDefault("terminal.profile", "auto")
 	v.SetDefault("history.enabled", true)
 	v.SetDefault("history.scrollback_lines", 10000)
 	v.SetDefault("history.retention", (30 * 24 * time.Hour).String())
 	v.SetDefault("update.auto_apply", true)
 	v.SetDefault("update.check_interval", DefaultUpdateCheckInterval)
+	v.SetDefault("telemetry.enabled", false)
 	v.SetDefault("harness.scrollback_lines", 1000)
 	v.SetDefault("experimental", false)
+	v.SetDefault("log.level", "")
 
 	// Config file location
 	configDir, err := paths.ConfigRoot()
@@ -90,7 +145,48 @@ func Load() *Config {
 		}
 	}
 
-	return &Config{v: v}
+	if name != "" {
+		if settings := v.GetStringMap(profilesKey + "." + name); len(settings) > 0 {
+			if err := v.MergeConfigMap(settings); err != nil {
+				slog.Default().Warn("error applying config profile", "component", "config", "event.type", "config.profile.warning", "profile", name, "error", err.Error())
+			}
+		}
+	}
+
+	return &Config{v: v, profile: name}
+}
+
+// ValidateProfileName reports whether name can name a profile: letters,
+// digits, hyphens, and underscores, starting with a letter or digit.
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q (use letters, digits, hyphens, and underscores)", name)
+	}
+
+	return nil
+}
+
+// Profile returns the name of the profile the config was loaded for, or ""
+// for the default profile.
+func (c *Config) Profile() string {
+	return c.profile
+}
+
+// Profiles returns the names of the named profiles in the config file,
+// sorted.
+func (c *Config) Profiles() []string {
+	settings := c.v.GetStringMap(profilesKey)
+
+	names := make([]string, 0, len(settings))
+	for name := range settings {
+		if name != paths.DefaultProfile {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
 }
 
 // Get returns a configuration value.
@@ -108,7 +204,10 @@ func (c *Config) GetInt(key string) int {
 	return c.v.GetInt(key)
 }
 
-// Set sets a configuration value and persists it.
+// Set sets a configuration value and persists it, in the profile the config
+// was loaded for. Only the config file's own settings are written back, so
+// defaults, environment variables, and another profile's settings stay out
+// of it.
 func (c *Config) Set(key string, value interface{}) error {
 	c.v.Set(key, value)
 
@@ -124,7 +223,20 @@ func (c *Config) Set(key string, value interface{}) error {
 
 	configFile := filepath.Join(configDir, "config.yaml")
 
-	if err := c.v.WriteConfigAs(configFile); err != nil {
+	file := viper.New()
+	file.SetConfigFile(configFile)
+
+	if err := file.ReadInConfig(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	if c.profile != "" {
+		key = profilesKey + "." + c.profile + "." + key
+	}
+
+	file.Set(key, value)
+
+	if err := file.WriteConfigAs(configFile); err != nil {
 		return fmt.Errorf("write config file: %w", err)
 	}
 
@@ -141,26 +253,393 @@ func (c *Config) APIURL() string {
 	return c.GetString("api.url")
 }
 
+// APIFallbackURLs returns the API URLs to fail over to, in order, when
+// api.url is unreachable. The value may be a YAML list or a comma-separated
+// string.
+func (c *Config) APIFallbackURLs() []string {
+	var urls []string
+
+	for _, entry := range c.v.GetStringSlice("api.fallback_urls") {
+		for _, url := range strings.Split(entry, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				urls = append(urls, url)
+			}
+		}
+	}
+
+	return urls
+}
+
+// CapabilityHints returns whether API requests tell the platform the OS,
+// architecture, terminal type, and enabled harnesses in the User-Agent.
+func (c *Config) CapabilityHints() bool {
+	return c.v.GetBool("api.capability_hints")
+}
+
+// APIRetryMaxAttempts returns how many times an API request that fails
+// with a transient error is tried, including the first. 1 turns retries off.
+func (c *Config) APIRetryMaxAttempts() int {
+	if !c.v.IsSet("api.retry.max_attempts") {
+		return defaultAPIRetryMaxAttempts
+	}
+
+	return max(c.GetInt("api.retry.max_attempts"), 1)
+}
+
+// APIRetryBaseDelay returns the wait before the first API retry.
+func (c *Config) APIRetryBaseDelay() time.Duration {
+	return c.positiveDuration("api.retry.base_delay", defaultAPIRetryBaseDelay)
+}
+
+// APIRetryMaxDelay returns the longest wait between API retries. It is
+// never shorter than APIRetryBaseDelay.
+func (c *Config) APIRetryMaxDelay() time.Duration {
+	return max(c.positiveDuration("api.retry.max_delay", defaultAPIRetryMaxDelay), c.APIRetryBaseDelay())
+}
+
 // CACertFile returns the optional custom CA certificate bundle path.
 func (c *Config) CACertFile() string {
 	return strings.TrimSpace(c.GetString("network.ca_cert_file"))
 }
 
+// AuthStore returns where 'mush auth login' keeps the API key: auto,
+// keychain, or file. Outside file, a key found in the credentials file is
+// moved to the keychain when it is next used.
+func (c *Config) AuthStore() string {
+	return strings.TrimSpace(c.GetString("auth.store"))
+}
+
 // PollInterval returns the poll interval as a duration.
 func (c *Config) PollInterval() time.Duration {
 	return c.parseDuration("worker.poll_interval", defaultPollIntervalDuration)
 }
 
+// PollIntervalMax returns the longest the poll interval backs off to while
+// the queue stays empty. It is never shorter than PollInterval, so setting
+// it to the poll interval turns backoff off.
+func (c *Config) PollIntervalMax() time.Duration {
+	return max(c.parseDuration("worker.poll_interval_max", defaultPollIntervalMaxDuration), c.PollInterval())
+}
+
+// ClaimBatchSize returns how many jobs a worker claims at most in one
+// request, between 1 and MaxClaimBatchSize. Jobs beyond the first wait on
+// the worker, holding their leases, until it gets to them.
+func (c *Config) ClaimBatchSize() int {
+	return min(max(c.GetInt("worker.claim_batch_size"), 1), MaxClaimBatchSize)
+}
+
 // HeartbeatInterval returns the heartbeat interval as a duration.
 func (c *Config) HeartbeatInterval() time.Duration {
 	return c.parseDuration("worker.heartbeat_interval", defaultHeartbeatIntervalDuration)
 }
 
+// WorkerHarnesses returns the harness types 'mush worker start' handles
+// when --harness is not given, or nil to handle every installed harness.
+// The value may be a YAML list or a comma-separated string.
+func (c *Config) WorkerHarnesses() []string {
+	var names []string
+
+	for _, entry := range c.v.GetStringSlice("worker.harnesses") {
+		for _, name := range strings.Split(entry, ",") {
+			if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+
+	return names
+}
+
+// WorkerNetworkRecording reports whether the worker records the outbound
+// HTTP calls its harness processes make during each job, from
+// worker.network_recording. It takes effect when the worker starts.
+func (c *Config) WorkerNetworkRecording() bool {
+	return c.v.GetBool("worker.network_recording")
+}
+
+// WorkerMetricsAddr returns the address the worker serves Prometheus
+// metrics on, such as "127.0.0.1:9464", from worker.metrics_addr. Empty
+// means no metrics endpoint.
+func (c *Config) WorkerMetricsAddr() string {
+	return strings.TrimSpace(c.v.GetString("worker.metrics_addr"))
+}
+
+// Worktree guard modes for worker.worktree_guard.
+const (
+	// WorktreeGuardOff claims jobs regardless of the working tree's state.
+	WorktreeGuardOff = "off"
+	// WorktreeGuardPause stops claiming while the working tree is unsafe.
+	WorktreeGuardPause = "pause"
+	// WorktreeGuardRefuse claims jobs but releases any that would run in an
+	// unsafe working tree.
+	WorktreeGuardRefuse = "refuse"
+)
+
+// WorktreeGuard protects a developer's in-progress work from jobs that run
+// in their checkout.
+type WorktreeGuard struct {
+	// Mode is one of the WorktreeGuard* constants.
+	Mode string
+	// ProtectedBranches are branches a job must never run on.
+	ProtectedBranches []string
+}
+
+// WorktreeGuard returns the worktree guard for a queue. Settings under
+// worker.queues.<key> override worker.worktree_guard and
+// worker.protected_branches; the first key with a setting wins, so callers
+// can pass both the queue slug and ID. Unknown modes fall back to off.
+func (c *Config) WorktreeGuard(queueKeys ...string) WorktreeGuard {
+	lookup := func(setting string) string {
+		return c.queueSetting(setting, queueKeys)
+	}
+
+	guard := WorktreeGuard{Mode: strings.ToLower(strings.TrimSpace(c.GetString(lookup("worktree_guard"))))}
+
+	switch guard.Mode {
+	case WorktreeGuardPause, WorktreeGuardRefuse:
+	default:
+		guard.Mode = WorktreeGuardOff
+	}
+
+	for _, entry := range c.v.GetStringSlice(lookup("protected_branches")) {
+		for _, branch := range strings.Split(entry, ",") {
+			if branch = strings.TrimSpace(branch); branch != "" {
+				guard.ProtectedBranches = append(guard.ProtectedBranches, branch)
+			}
+		}
+	}
+
+	return guard
+}
+
+// TimeoutWarning returns how long before a job's execution timeout the
+// harness is told to wrap up, or zero when warnings are off. Like the
+// worktree guard, worker.queues.<key>.timeout_warning overrides
+// worker.timeout_warning. The value is a duration or "off".
+func (c *Config) TimeoutWarning(queueKeys ...string) time.Duration {
+	raw := strings.ToLower(strings.TrimSpace(c.GetString(c.queueSetting("timeout_warning", queueKeys))))
+	if raw == "off" || raw == "false" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultTimeoutWarning
+	}
+
+	return max(d, 0)
+}
+
+// Git workflow modes for worker.git_workflow, each doing everything the one
+// before it does.
+const (
+	// GitWorkflowOff leaves a job's changes where the agent left them.
+	GitWorkflowOff = "off"
+	// GitWorkflowCommit commits a job's changes to a branch named from the
+	// job ID.
+	GitWorkflowCommit = "commit"
+	// GitWorkflowPush also pushes the branch.
+	GitWorkflowPush = "push"
+	// GitWorkflowPR also opens a pull request for the branch with gh.
+	GitWorkflowPR = "pr"
+)
+
+// GitWorkflow is what the worker does with a completed job's changes.
+type GitWorkflow struct {
+	// Mode is one of the GitWorkflow* constants.
+	Mode string
+	// BranchPrefix is prepended to the job ID to name the branch.
+	BranchPrefix string
+}
+
+// GitWorkflow returns the git workflow for a queue. Like the worktree guard,
+// worker.queues.<key>.git_workflow and .git_branch_prefix override the
+// top-level settings. Unknown modes fall back to off.
+func (c *Config) GitWorkflow(queueKeys ...string) GitWorkflow {
+	workflow := GitWorkflow{
+		Mode:         strings.ToLower(strings.TrimSpace(c.GetString(c.queueSetting("git_workflow", queueKeys)))),
+		BranchPrefix: strings.TrimSpace(c.GetString(c.queueSetting("git_branch_prefix", queueKeys))),
+	}
+
+	switch workflow.Mode {
+	case GitWorkflowCommit, GitWorkflowPush, GitWorkflowPR:
+	default:
+		workflow.Mode = GitWorkflowOff
+	}
+
+	return workflow
+}
+
+// queueSetting returns the config key for a worker setting, preferring the
+// first of queueKeys with an override under worker.queues.
+func (c *Config) queueSetting(setting string, queueKeys []string) string {
+	for _, key := range queueKeys {
+		if key == "" {
+			continue
+		}
+
+		if queueKey := "worker.queues." + strings.ToLower(key) + "." + setting; c.v.IsSet(queueKey) {
+			return queueKey
+		}
+	}
+
+	return "worker." + setting
+}
+
+// SecretAllowlist returns the secret references jobs may name in their
+// environment, from secrets.allow. Entries ending in "*" allow every
+// reference with that prefix. The value may be a YAML list or a
+// comma-separated string.
+func (c *Config) SecretAllowlist() []string {
+	var allow []string
+
+	for _, entry := range c.v.GetStringSlice("secrets.allow") {
+		for _, ref := range strings.Split(entry, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				allow = append(allow, ref)
+			}
+		}
+	}
+
+	return allow
+}
+
+// Result sink types for results.sinks.
+const (
+	// ResultSinkFile writes each result as JSON into a directory.
+	ResultSinkFile = "file"
+	// ResultSinkWebhook posts each result as JSON to a URL.
+	ResultSinkWebhook = "webhook"
+	// ResultSinkSlack posts a one-line summary to a Slack incoming webhook.
+	ResultSinkSlack = "slack"
+)
+
+// ResultSink is one entry of results.sinks: somewhere a finished job's
+// result is sent after it has been reported to the platform.
+type ResultSink struct {
+	// Type is one of the ResultSink* constants.
+	Type string `mapstructure:"type"`
+
+	// Dir is where a file sink writes <job-id>-<attempt>.json. A leading ~/
+	// is the home directory.
+	Dir string `mapstructure:"dir"`
+
+	// URL receives a webhook sink's POST, or is a Slack incoming webhook.
+	URL string `mapstructure:"url"`
+
+	// Headers and Secret authenticate and sign webhook sink deliveries the
+	// same way as a job's own webhook.
+	Headers map[string]string `mapstructure:"headers"`
+	Secret  string            `mapstructure:"secret"`
+
+	// On limits the sink to "completed" or "failed" jobs (empty = both).
+	On []string `mapstructure:"on"`
+}
+
+// Wants reports whether the sink takes results with status, "completed" or
+// "failed".
+func (s *ResultSink) Wants(status string) bool {
+	if len(s.On) == 0 {
+		return true
+	}
+
+	for _, on := range s.On {
+		if strings.EqualFold(strings.TrimSpace(on), status) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ResultSinks returns the sinks under results.sinks. Each sink's fields are
+// checked when it is used, so one bad entry doesn't disable the others.
+func (c *Config) ResultSinks() ([]ResultSink, error) {
+	var sinks []ResultSink
+	if err := c.v.UnmarshalKey("results.sinks", &sinks); err != nil {
+		return nil, fmt.Errorf("decode results.sinks: %w", err)
+	}
+
+	for i := range sinks {
+		sinks[i].Type = strings.ToLower(strings.TrimSpace(sinks[i].Type))
+
+		if rest, ok := strings.CutPrefix(sinks[i].Dir, "~/"); ok {
+			if home, err := os.UserHomeDir(); err == nil {
+				sinks[i].Dir = filepath.Join(home, rest)
+			}
+		}
+	}
+
+	return sinks, nil
+}
+
+// DefaultOutputProcessorTimeout bounds one output processor run when its
+// entry sets no timeout.
+const DefaultOutputProcessorTimeout = 30 * time.Second
+
+// OutputProcessor is one entry of results.processors: a command that reads
+// a completed job's output as JSON on stdin and writes the JSON object to
+// report instead on stdout.
+type OutputProcessor struct {
+	// Name identifies the processor in logs. It defaults to the command.
+	Name string `mapstructure:"name"`
+
+	// Command is the program and its arguments. A program without a path
+	// is looked up in PATH; no shell is involved.
+	Command []string `mapstructure:"command"`
+
+	// Timeout bounds one run (0 = DefaultOutputProcessorTimeout).
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// OutputProcessors returns the processors under results.processors, in the
+// order they run.
+func (c *Config) OutputProcessors() ([]OutputProcessor, error) {
+	var processors []OutputProcessor
+	if err := c.v.UnmarshalKey("results.processors", &processors); err != nil {
+		return nil, fmt.Errorf("decode results.processors: %w", err)
+	}
+
+	for i := range processors {
+		p := &processors[i]
+
+		if len(p.Command) == 0 || strings.TrimSpace(p.Command[0]) == "" {
+			return nil, fmt.Errorf("results.processors[%d]: command is required", i)
+		}
+
+		if p.Timeout < 0 {
+			return nil, fmt.Errorf("results.processors[%d]: timeout must not be negative", i)
+		}
+
+		if p.Timeout == 0 {
+			p.Timeout = DefaultOutputProcessorTimeout
+		}
+
+		if p.Name = strings.TrimSpace(p.Name); p.Name == "" {
+			p.Name = p.Command[0]
+		}
+	}
+
+	return processors, nil
+}
+
 // TUI returns whether the interactive TUI is enabled.
 func (c *Config) TUI() bool {
 	return c.v.GetBool("tui")
 }
 
+// TerminalProfile returns the terminal profile name from terminal.profile:
+// "auto" to detect it, or a built-in profile name.
+func (c *Config) TerminalProfile() string {
+	return strings.TrimSpace(c.v.GetString("terminal.profile"))
+}
+
+// InputLock returns whether the worker's terminal keeps keystrokes from
+// reaching the harness while a job runs.
+func (c *Config) InputLock() bool {
+	return c.v.GetBool("worker.input_lock")
+}
+
 // HistoryEnabled returns whether transcript history is enabled.
 func (c *Config) HistoryEnabled() bool {
 	return c.v.GetBool("history.enabled")
@@ -196,6 +675,17 @@ func (c *Config) parseDuration(key string, fallback time.Duration) time.Duration
 	return fallback
 }
 
+// positiveDuration reads a config key as a duration, returning fallback
+// if the value is empty, unparseable, or not positive.
+func (c *Config) positiveDuration(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(c.GetString(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+
+	return d
+}
+
 // HistoryRetention returns the configured retention period for history pruning.
 func (c *Config) HistoryRetention() time.Duration {
 	d, err := time.ParseDuration(c.GetString("history.retention"))
@@ -211,11 +701,22 @@ func (c *Config) HarnessScrollbackLines() int {
 	return c.GetInt("harness.scrollback_lines")
 }
 
+// LogLevel returns the configured log level, or "" when unset.
+func (c *Config) LogLevel() string {
+	return strings.TrimSpace(c.GetString("log.level"))
+}
+
 // Experimental returns whether experimental features are enabled.
 func (c *Config) Experimental() bool {
 	return c.v.GetBool("experimental")
 }
 
+// TelemetryEnabled returns whether the user opted in to anonymous usage
+// telemetry. DO_NOT_TRACK is honored separately by the telemetry package.
+func (c *Config) TelemetryEnabled() bool {
+	return c.v.GetBool("telemetry.enabled")
+}
+
 // UpdateAutoApply returns whether background auto-apply is enabled.
 func (c *Config) UpdateAutoApply() bool {
 	return c.v.GetBool("update.auto_apply")
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 )
@@ -233,6 +234,196 @@ func TestConfig_APIURL(t *testing.T) {
 	}
 }
 
+func TestLoadProfile(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", tmpDir)
+	unsetEnvForTest(t, "MUSHER_API_URL")
+	unsetEnvForTest(t, "MUSHER_AUTH_STORE")
+	unsetEnvForTest(t, "MUSH_PROFILE")
+
+	if err := Load().Set("api.url", "https://api.example.dev"); err != nil {
+		t.Fatalf("Set(default api.url) error = %v", err)
+	}
+
+	staging := LoadProfile("staging")
+	if err := staging.Set("api.url", "https://staging.example.dev"); err != nil {
+		t.Fatalf("Set(staging api.url) error = %v", err)
+	}
+
+	if err := staging.Set("auth.store", "file"); err != nil {
+		t.Fatalf("Set(staging auth.store) error = %v", err)
+	}
+
+	if got := Load().APIURL(); got != "https://api.example.dev" {
+		t.Errorf("default APIURL() = %q, want the default profile's URL", got)
+	}
+
+	t.Setenv("MUSH_PROFILE", "staging")
+
+	cfg := Load()
+	if cfg.Profile() != "staging" || cfg.APIURL() != "https://staging.example.dev" || cfg.AuthStore() != "file" {
+		t.Errorf("staging config = %q, %q, %q; want the staging profile's settings", cfg.Profile(), cfg.APIURL(), cfg.AuthStore())
+	}
+
+	if got := LoadProfile("default").APIURL(); got != "https://api.example.dev" {
+		t.Errorf("LoadProfile(default).APIURL() = %q, want the default profile's URL", got)
+	}
+
+	t.Setenv("MUSHER_API_URL", "https://env.example.dev")
+
+	if got := Load().APIURL(); got != "https://env.example.dev" {
+		t.Errorf("APIURL() = %q, want the environment to override the profile", got)
+	}
+
+	if got := Load().Profiles(); !slices.Equal(got, []string{"staging"}) {
+		t.Errorf("Profiles() = %q, want [staging]", got)
+	}
+
+	for _, name := range []string{"prod", "team_2", "eu-west"} {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) error = %v", name, err)
+		}
+	}
+
+	for _, name := range []string{"", "a.b", "-x", "a/b"} {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) = nil, want an error", name)
+		}
+	}
+}
+
+func TestConfig_APIFallbackURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVal string
+		want   []string
+	}{
+		{name: "default", envVal: "", want: nil},
+		{name: "comma separated", envVal: "https://gw2.example.com, https://gw3.example.com,", want: []string{"https://gw2.example.com", "https://gw3.example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("HOME", tmpDir)
+
+			if tt.envVal == "" {
+				unsetEnvForTest(t, "MUSHER_API_FALLBACK_URLS")
+			} else {
+				t.Setenv("MUSHER_API_FALLBACK_URLS", tt.envVal)
+			}
+
+			got := Load().APIFallbackURLs()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("APIFallbackURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_CapabilityHints(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVal string
+		want   bool
+	}{
+		{name: "default", envVal: "", want: true},
+		{name: "disabled", envVal: "false", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("HOME", tmpDir)
+
+			if tt.envVal == "" {
+				unsetEnvForTest(t, "MUSHER_API_CAPABILITY_HINTS")
+			} else {
+				t.Setenv("MUSHER_API_CAPABILITY_HINTS", tt.envVal)
+			}
+
+			if got := Load().CapabilityHints(); got != tt.want {
+				t.Errorf("CapabilityHints() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_APIRetry(t *testing.T) {
+	tests := []struct {
+		name         string
+		env          map[string]string
+		wantAttempts int
+		wantBase     time.Duration
+		wantMax      time.Duration
+	}{
+		{
+			name:         "defaults",
+			wantAttempts: 3,
+			wantBase:     500 * time.Millisecond,
+			wantMax:      10 * time.Second,
+		},
+		{
+			name: "from env",
+			env: map[string]string{
+				"MUSHER_API_RETRY_MAX_ATTEMPTS": "5",
+				"MUSHER_API_RETRY_BASE_DELAY":   "250ms",
+				"MUSHER_API_RETRY_MAX_DELAY":    "30s",
+			},
+			wantAttempts: 5,
+			wantBase:     250 * time.Millisecond,
+			wantMax:      30 * time.Second,
+		},
+		{
+			name: "zero attempts turns retries off",
+			env: map[string]string{
+				"MUSHER_API_RETRY_MAX_ATTEMPTS": "0",
+			},
+			wantAttempts: 1,
+			wantBase:     500 * time.Millisecond,
+			wantMax:      10 * time.Second,
+		},
+		{
+			name: "invalid delays fall back and max stays above base",
+			env: map[string]string{
+				"MUSHER_API_RETRY_BASE_DELAY": "20s",
+				"MUSHER_API_RETRY_MAX_DELAY":  "soon",
+			},
+			wantAttempts: 3,
+			wantBase:     20 * time.Second,
+			wantMax:      20 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			for _, key := range []string{"MUSHER_API_RETRY_MAX_ATTEMPTS", "MUSHER_API_RETRY_BASE_DELAY", "MUSHER_API_RETRY_MAX_DELAY"} {
+				unsetEnvForTest(t, key)
+			}
+
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg := Load()
+
+			if got := cfg.APIRetryMaxAttempts(); got != tt.wantAttempts {
+				t.Errorf("APIRetryMaxAttempts() = %d, want %d", got, tt.wantAttempts)
+			}
+
+			if got := cfg.APIRetryBaseDelay(); got != tt.wantBase {
+				t.Errorf("APIRetryBaseDelay() = %v, want %v", got, tt.wantBase)
+			}
+
+			if got := cfg.APIRetryMaxDelay(); got != tt.wantMax {
+				t.Errorf("APIRetryMaxDelay() = %v, want %v", got, tt.wantMax)
+			}
+		})
+	}
+}
+
 func TestConfig_CACertFile(t *testing.T) {
 	tmpDir := t.TempDir()
 	t.Setenv("HOME", tmpDir)
@@ -245,6 +436,22 @@ func TestConfig_CACertFile(t *testing.T) {
 	}
 }
 
+func TestConfig_AuthStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	unsetEnvForTest(t, "MUSHER_AUTH_STORE")
+
+	if got := Load().AuthStore(); got != "auto" {
+		t.Errorf("AuthStore() default = %q, want %q", got, "auto")
+	}
+
+	t.Setenv("MUSHER_AUTH_STORE", " file ")
+
+	if got := Load().AuthStore(); got != "file" {
+		t.Errorf("AuthStore() = %q, want %q", got, "file")
+	}
+}
+
 func runDurationConfigCase(t *testing.T, envKey, envValue string, getter func(*Config) time.Duration) time.Duration {
 	t.Helper()
 
@@ -293,6 +500,73 @@ func TestConfig_PollInterval(t *testing.T) {
 	}
 }
 
+func TestConfig_PollIntervalMax(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVal string
+		want   time.Duration
+	}{
+		{
+			name:   "default",
+			envVal: "",
+			want:   5 * time.Minute,
+		},
+		{
+			name:   "duration string from env",
+			envVal: "2m",
+			want:   2 * time.Minute,
+		},
+		{
+			name:   "never below the poll interval",
+			envVal: "10s",
+			want:   30 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unsetEnvForTest(t, "MUSHER_WORKER_POLL_INTERVAL")
+
+			got := runDurationConfigCase(t, "MUSHER_WORKER_POLL_INTERVAL_MAX", tt.envVal, func(cfg *Config) time.Duration {
+				return cfg.PollIntervalMax()
+			})
+
+			if got != tt.want {
+				t.Errorf("PollIntervalMax() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_ClaimBatchSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVal string
+		want   int
+	}{
+		{name: "default", envVal: "", want: 1},
+		{name: "from env", envVal: "10", want: 10},
+		{name: "at least one", envVal: "0", want: 1},
+		{name: "capped", envVal: "500", want: MaxClaimBatchSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+
+			if tt.envVal == "" {
+				unsetEnvForTest(t, "MUSHER_WORKER_CLAIM_BATCH_SIZE")
+			} else {
+				t.Setenv("MUSHER_WORKER_CLAIM_BATCH_SIZE", tt.envVal)
+			}
+
+			if got := Load().ClaimBatchSize(); got != tt.want {
+				t.Errorf("ClaimBatchSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestConfig_HeartbeatInterval(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -324,6 +598,201 @@ func TestConfig_HeartbeatInterval(t *testing.T) {
 	}
 }
 
+func TestConfig_WorkerHarnesses(t *testing.T) {
+	tests := []struct {
+		name   string
+		envVal string
+		want   []string
+	}{
+		{name: "default", envVal: "", want: nil},
+		{name: "comma separated", envVal: "claude, Codex", want: []string{"claude", "codex"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("HOME", tmpDir)
+
+			if tt.envVal == "" {
+				unsetEnvForTest(t, "MUSHER_WORKER_HARNESSES")
+			} else {
+				t.Setenv("MUSHER_WORKER_HARNESSES", tt.envVal)
+			}
+
+			got := Load().WorkerHarnesses()
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WorkerHarnesses() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_WorktreeGuard(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	unsetEnvForTest(t, "MUSHER_WORKER_WORKTREE_GUARD")
+	unsetEnvForTest(t, "MUSHER_WORKER_PROTECTED_BRANCHES")
+
+	if got := Load().WorktreeGuard("jobs"); got.Mode != WorktreeGuardOff || got.ProtectedBranches != nil {
+		t.Fatalf("default WorktreeGuard() = %+v, want off with no protected branches", got)
+	}
+
+	cfg := Load()
+
+	for key, value := range map[string]any{
+		"worker.worktree_guard":                "pause",
+		"worker.protected_branches":            "main, release",
+		"worker.queues.nightly.worktree_guard": "refuse",
+		"worker.queues.q-2.worktree_guard":     "bogus",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s) error = %v", key, err)
+		}
+	}
+
+	reloaded := Load()
+
+	tests := []struct {
+		keys []string
+		mode string
+	}{
+		{keys: []string{"jobs", "q-1"}, mode: WorktreeGuardPause},
+		{keys: []string{"Nightly", "q-3"}, mode: WorktreeGuardRefuse},
+		{keys: []string{"", "q-2"}, mode: WorktreeGuardOff},
+	}
+
+	for _, tt := range tests {
+		got := reloaded.WorktreeGuard(tt.keys...)
+		if got.Mode != tt.mode {
+			t.Errorf("WorktreeGuard(%q).Mode = %q, want %q", tt.keys, got.Mode, tt.mode)
+		}
+
+		if !slices.Equal(got.ProtectedBranches, []string{"main", "release"}) {
+			t.Errorf("WorktreeGuard(%q).ProtectedBranches = %q, want [main release]", tt.keys, got.ProtectedBranches)
+		}
+	}
+}
+
+func TestConfig_TimeoutWarning(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	unsetEnvForTest(t, "MUSHER_WORKER_TIMEOUT_WARNING")
+
+	if got := Load().TimeoutWarning("jobs"); got != 2*time.Minute {
+		t.Fatalf("default TimeoutWarning() = %v, want 2m", got)
+	}
+
+	cfg := Load()
+
+	for key, value := range map[string]any{
+		"worker.timeout_warning":                "5m",
+		"worker.queues.nightly.timeout_warning": "off",
+		"worker.queues.q-2.timeout_warning":     "soon",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s) error = %v", key, err)
+		}
+	}
+
+	reloaded := Load()
+
+	tests := []struct {
+		keys []string
+		want time.Duration
+	}{
+		{keys: []string{"jobs", "q-1"}, want: 5 * time.Minute},
+		{keys: []string{"Nightly", "q-3"}, want: 0},
+		{keys: []string{"", "q-2"}, want: 2 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := reloaded.TimeoutWarning(tt.keys...); got != tt.want {
+			t.Errorf("TimeoutWarning(%q) = %v, want %v", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_GitWorkflow(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	unsetEnvForTest(t, "MUSHER_WORKER_GIT_WORKFLOW")
+	unsetEnvForTest(t, "MUSHER_WORKER_GIT_BRANCH_PREFIX")
+
+	if got := Load().GitWorkflow("jobs"); got.Mode != GitWorkflowOff || got.BranchPrefix != DefaultGitBranchPrefix {
+		t.Fatalf("default GitWorkflow() = %+v, want off with the default prefix", got)
+	}
+
+	cfg := Load()
+
+	for key, value := range map[string]any{
+		"worker.git_workflow":                     "push",
+		"worker.queues.nightly.git_workflow":      "PR",
+		"worker.queues.nightly.git_branch_prefix": "bot/",
+		"worker.queues.q-2.git_workflow":          "merge",
+	} {
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s) error = %v", key, err)
+		}
+	}
+
+	reloaded := Load()
+
+	tests := []struct {
+		keys []string
+		want GitWorkflow
+	}{
+		{keys: []string{"jobs", "q-1"}, want: GitWorkflow{Mode: GitWorkflowPush, BranchPrefix: "mush/"}},
+		{keys: []string{"Nightly", "q-3"}, want: GitWorkflow{Mode: GitWorkflowPR, BranchPrefix: "bot/"}},
+		{keys: []string{"", "q-2"}, want: GitWorkflow{Mode: GitWorkflowOff, BranchPrefix: "mush/"}},
+	}
+
+	for _, tt := range tests {
+		if got := reloaded.GitWorkflow(tt.keys...); got != tt.want {
+			t.Errorf("GitWorkflow(%q) = %+v, want %+v", tt.keys, got, tt.want)
+		}
+	}
+}
+
+func TestConfig_SecretAllowlist(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	t.Setenv("MUSHER_SECRETS_ALLOW", "op://Eng/*, env://NPM_TOKEN,")
+
+	if got := Load().SecretAllowlist(); !slices.Equal(got, []string{"op://Eng/*", "env://NPM_TOKEN"}) {
+		t.Fatalf("SecretAllowlist() = %q, want the two references", got)
+	}
+}
+
+func TestConfig_WorkerNetworkRecording(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+
+	if Load().WorkerNetworkRecording() {
+		t.Fatal("WorkerNetworkRecording() = true by default, want false")
+	}
+
+	t.Setenv("MUSHER_WORKER_NETWORK_RECORDING", "true")
+
+	if !Load().WorkerNetworkRecording() {
+		t.Fatal("WorkerNetworkRecording() = false with MUSHER_WORKER_NETWORK_RECORDING=true, want true")
+	}
+}
+
+func TestConfig_WorkerMetricsAddr(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+
+	if got := Load().WorkerMetricsAddr(); got != "" {
+		t.Fatalf("WorkerMetricsAddr() = %q by default, want empty", got)
+	}
+
+	t.Setenv("MUSHER_WORKER_METRICS_ADDR", " 127.0.0.1:9464 ")
+
+	if got := Load().WorkerMetricsAddr(); got != "127.0.0.1:9464" {
+		t.Fatalf("WorkerMetricsAddr() = %q, want 127.0.0.1:9464", got)
+	}
+}
+
 func TestConfig_UpdateAutoApply(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -434,6 +903,117 @@ func TestConfig_KeybindingsInvalidValueFallsBack(t *testing.T) {
 	}
 }
 
+func TestConfig_ResultSinks(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("HOME", tmpDir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".config", "musher"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	configPath := filepath.Join(tmpDir, ".config", "musher", "config.yaml")
+	yaml := `results:
+  sinks:
+    - type: File
+      dir: ~/results
+    - type: slack
+      url: https://hooks.slack.com/services/T/B/X
+      on: [failed]
+`
+
+	if err := os.WriteFile(configPath, []byte(yaml), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	sinks, err := Load().ResultSinks()
+	if err != nil {
+		t.Fatalf("ResultSinks() error = %v", err)
+	}
+
+	if len(sinks) != 2 {
+		t.Fatalf("ResultSinks() = %+v, want 2 sinks", sinks)
+	}
+
+	if sinks[0].Type != ResultSinkFile || sinks[0].Dir != filepath.Join(tmpDir, "results") {
+		t.Fatalf("file sink = %+v, want type file in ~/results", sinks[0])
+	}
+
+	if !sinks[0].Wants("completed") || !sinks[0].Wants("failed") {
+		t.Fatal("sink without on should take every result")
+	}
+
+	if sinks[1].Wants("completed") || !sinks[1].Wants("failed") {
+		t.Fatalf("slack sink on = %v, want only failed results", sinks[1].On)
+	}
+}
+
+func TestConfig_OutputProcessors(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    []OutputProcessor
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			yaml: `results:
+  processors:
+    - command: [jq, -c, "{ticket: .output}"]
+    - name: tests
+      command: [/opt/hooks/count-tests]
+      timeout: 5s
+`,
+			want: []OutputProcessor{
+				{Name: "jq", Command: []string{"jq", "-c", "{ticket: .output}"}, Timeout: DefaultOutputProcessorTimeout},
+				{Name: "tests", Command: []string{"/opt/hooks/count-tests"}, Timeout: 5 * time.Second},
+			},
+		},
+		{
+			name:    "missing command",
+			yaml:    "results:\n  processors:\n    - name: empty\n",
+			wantErr: "results.processors[0]: command is required",
+		},
+		{
+			name:    "negative timeout",
+			yaml:    "results:\n  processors:\n    - command: [jq]\n      timeout: -1s\n",
+			wantErr: "results.processors[0]: timeout must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			t.Setenv("XDG_CONFIG_HOME", tmpDir)
+
+			if err := os.MkdirAll(filepath.Join(tmpDir, "musher"), 0o700); err != nil {
+				t.Fatalf("MkdirAll() error = %v", err)
+			}
+
+			if err := os.WriteFile(filepath.Join(tmpDir, "musher", "config.yaml"), []byte(tt.yaml), 0o600); err != nil {
+				t.Fatalf("WriteFile() error = %v", err)
+			}
+
+			got, err := Load().OutputProcessors()
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("OutputProcessors() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("OutputProcessors() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("OutputProcessors() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestParseKeybindingValue(t *testing.T) {
 	t.Parallel()
 
@@ -472,3 +1052,62 @@ func TestParseKeybindingValue(t *testing.T) {
 		})
 	}
 }
+
+func TestDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(tmpDir, ".config"))
+	unsetEnvForTest(t, "MUSHER_LOG_LEVEL")
+	unsetEnvForTest(t, "MUSHER_WORKER_POLL_INTERVAL")
+	unsetEnvForTest(t, "MUSHER_WORKER_HEARTBEAT_INTERVAL")
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".config", "musher"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	// Environment variables are read lazily, so a reload is driven by the
+	// config file, as it is when a worker gets SIGHUP.
+	configPath := filepath.Join(tmpDir, ".config", "musher", "config.yaml")
+	writeConfig := func(body string) {
+		t.Helper()
+
+		if err := os.WriteFile(configPath, []byte(body), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+	}
+
+	writeConfig("worker:\n  poll_interval: 30s\n  heartbeat_interval: 30s\n")
+
+	prev := Load()
+
+	writeConfig("worker:\n  poll_interval: 10s\n  heartbeat_interval: 30s\nlog:\n  level: debug\n")
+
+	next := Load()
+
+	got := Diff(prev, next, ReloadableKeys)
+	want := []Change{
+		{Key: "worker.poll_interval", Old: "30s", New: "10s"},
+		{Key: "log.level", Old: "", New: "debug"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() = %+v, want %+v", got, want)
+	}
+
+	if next.LogLevel() != "debug" {
+		t.Fatalf("LogLevel() = %q, want debug", next.LogLevel())
+	}
+
+	writeConfig("worker:\n  poll_interval: 10s\n  heartbeat_interval: 30s\n  protected_branches: [main]\n" +
+		"results:\n  sinks:\n    - type: slack\n      url: https://hooks.slack.com/services/T0/B0/secret\n")
+
+	got = Diff(next, Load(), ReloadableKeys)
+	want = []Change{
+		{Key: "worker.protected_branches", Old: "", New: `["main"]`},
+		{Key: "results.sinks", Old: "(redacted)", New: "(redacted)"},
+		{Key: "log.level", Old: "debug", New: ""},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Diff() of lists = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// ReloadableKeys lists the settings a running worker applies on reload
+// without restarting or dropping its harness session.
+var ReloadableKeys = []string{
+	"worker.poll_interval",
+	"worker.poll_interval_max",
+	"worker.claim_batch_size",
+	"worker.heartbeat_interval",
+	"worker.worktree_guard",
+	"worker.protected_branches",
+	"worker.queues",
+	"worker.timeout_warning",
+	"worker.git_workflow",
+	"worker.git_branch_prefix",
+	"worker.input_lock",
+	"results.sinks",
+	"results.processors",
+	"log.level",
+}
+
+// redactedKeys are reloadable keys whose values may hold credentials, such
+// as a Slack webhook URL, so Diff reports only that they changed.
+var redactedKeys = []string{"results.sinks"}
+
+// Change describes a setting whose value differs between two configs.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// Diff returns the keys whose values differ between prev and next, in the
+// order given. Lists and maps are compared as JSON.
+func Diff(prev, next *Config, keys []string) []Change {
+	var changes []Change
+
+	for _, key := range keys {
+		oldValue := reloadValue(prev, key)
+		newValue := reloadValue(next, key)
+
+		if oldValue == newValue {
+			continue
+		}
+
+		if slices.Contains(redactedKeys, key) {
+			oldValue, newValue = "(redacted)", "(redacted)"
+		}
+
+		changes = append(changes, Change{Key: key, Old: oldValue, New: newValue})
+	}
+
+	return changes
+}
+
+// reloadValue returns key's value in c as text. Lists and maps, which have
+// no string form, are rendered as JSON.
+func reloadValue(c *Config, key string) string {
+	switch value := c.Get(key).(type) {
+	case []any, []string, map[string]any:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+
+		return string(data)
+	default:
+		return c.GetString(key)
+	}
+}
@@ -0,0 +1,291 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// claudePromptGlyph is what the Claude executor watches for to tell the
+// input prompt is ready (claude.PromptDetectionBytes).
+const claudePromptGlyph = "❯ "
+
+// mushHookMarker appears in every hook command mush installs.
+const mushHookMarker = "MUSHER_SIGNAL_DIR"
+
+// maxStatusLineScript bounds how much of a statusLine script is searched.
+const maxStatusLineScript = 1 << 20
+
+// claudeSettings is the part of a Claude settings file that can break a
+// mush session.
+type claudeSettings struct {
+	DisableAllHooks bool `json:"disableAllHooks"`
+
+	Permissions struct {
+		DefaultMode                  string `json:"defaultMode"`
+		DisableBypassPermissionsMode string `json:"disableBypassPermissionsMode"`
+	} `json:"permissions"`
+
+	Hooks map[string][]json.RawMessage `json:"hooks"`
+
+	StatusLine *struct {
+		Type    string `json:"type"`
+		Command string `json:"command"`
+	} `json:"statusLine"`
+}
+
+type claudeHookEntry struct {
+	Matcher any    `json:"matcher"`
+	Command string `json:"command"`
+	Hooks   []struct {
+		Command string `json:"command"`
+	} `json:"hooks"`
+}
+
+// claudeConflict is one setting that breaks the harness. A blocking
+// conflict stops jobs from running or finishing at all.
+type claudeConflict struct {
+	path     string
+	blocking bool
+	problem  string
+}
+
+// claudeSettingsFiles returns the Claude settings files that apply to a
+// session started in dir, in Claude's precedence order from lowest.
+func claudeSettingsFiles(dir string) []string {
+	var files []string
+
+	configDir := os.Getenv("CLAUDE_CONFIG_DIR")
+	if configDir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			configDir = filepath.Join(home, ".claude")
+		}
+	}
+
+	if configDir != "" {
+		files = append(files, filepath.Join(configDir, "settings.json"))
+	}
+
+	files = append(files,
+		filepath.Join(dir, ".claude", "settings.json"),
+		filepath.Join(dir, ".claude", "settings.local.json"),
+	)
+
+	switch runtime.GOOS {
+	case "darwin":
+		files = append(files, "/Library/Application Support/ClaudeCode/managed-settings.json")
+	case "linux":
+		files = append(files, "/etc/claude-code/managed-settings.json")
+	}
+
+	return files
+}
+
+// checkClaudeSettings looks for Claude settings that conflict with how mush
+// drives a Claude session: the Stop hook that signals a finished job, the
+// --dangerously-skip-permissions flag, and the prompt glyph that marks it
+// ready for input.
+func checkClaudeSettings(context.Context) Result {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return Result{
+			Status:  StatusWarn,
+			Message: "Cannot determine working directory",
+			Detail:  err.Error(),
+		}
+	}
+
+	found := 0
+
+	var (
+		conflicts  []claudeConflict
+		unreadable []string
+	)
+
+	for _, path := range claudeSettingsFiles(cwd) {
+		data, exists, readErr := safeio.ReadFileIfExists(path)
+		if readErr != nil {
+			unreadable = append(unreadable, fmt.Sprintf("%s: %v", path, readErr))
+			continue
+		}
+
+		if !exists {
+			continue
+		}
+
+		found++
+
+		fileConflicts, parseErr := claudeSettingsConflicts(path, data, filepath.Base(path) == "settings.local.json")
+		if parseErr != nil {
+			unreadable = append(unreadable, fmt.Sprintf("%s: %v", path, parseErr))
+			continue
+		}
+
+		conflicts = append(conflicts, fileConflicts...)
+	}
+
+	return claudeSettingsResult(found, conflicts, unreadable)
+}
+
+func claudeSettingsResult(found int, conflicts []claudeConflict, unreadable []string) Result {
+	if len(conflicts) == 0 && len(unreadable) == 0 {
+		if found == 0 {
+			return Result{
+				Status:  StatusPass,
+				Message: "No Claude settings files found",
+			}
+		}
+
+		return Result{
+			Status:  StatusPass,
+			Message: fmt.Sprintf("No conflicting settings in %d file(s)", found),
+		}
+	}
+
+	status := StatusWarn
+	details := make([]string, 0, len(conflicts)+len(unreadable))
+
+	for _, c := range conflicts {
+		if c.blocking {
+			status = StatusFail
+		}
+
+		details = append(details, c.path+": "+c.problem)
+	}
+
+	for _, u := range unreadable {
+		details = append(details, "cannot read "+u)
+	}
+
+	message := fmt.Sprintf("%d setting(s) conflict with the Claude harness", len(conflicts))
+	if len(conflicts) == 0 {
+		message = "Cannot read Claude settings"
+	}
+
+	return Result{
+		Status:  status,
+		Message: message,
+		Detail:  strings.Join(details, "; "),
+	}
+}
+
+// claudeSettingsConflicts returns the settings in one file that conflict
+// with the harness. mergesHooks is set for settings.local.json, the file
+// mush adds its hooks to for the length of a session.
+func claudeSettingsConflicts(path string, data []byte, mergesHooks bool) ([]claudeConflict, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	var settings claudeSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	var conflicts []claudeConflict
+
+	add := func(blocking bool, format string, args ...any) {
+		conflicts = append(conflicts, claudeConflict{path: path, blocking: blocking, problem: fmt.Sprintf(format, args...)})
+	}
+
+	if settings.DisableAllHooks {
+		add(true, "disableAllHooks is true, so the Stop hook mush installs never runs and jobs are not seen to finish until they time out")
+	}
+
+	if settings.Permissions.DisableBypassPermissionsMode == "disable" {
+		add(true, `permissions.disableBypassPermissionsMode is "disable", so Claude refuses the --dangerously-skip-permissions flag mush starts job sessions with`)
+	}
+
+	if settings.Permissions.DefaultMode == "plan" {
+		add(false, `permissions.defaultMode is "plan", so bundle sessions, which start without --dangerously-skip-permissions, cannot edit files until you leave plan mode`)
+	}
+
+	for _, event := range []string{"Stop", "UserPromptSubmit"} {
+		for _, raw := range settings.Hooks[event] {
+			var entry claudeHookEntry
+			if err := json.Unmarshal(raw, &entry); err != nil {
+				add(mergesHooks, "hooks.%s has an entry that is not an object", event)
+				continue
+			}
+
+			if mergesHooks {
+				if entry.Command != "" {
+					add(true, "hooks.%s uses the legacy entry format (a top-level command), so mush cannot add its own %s hook; move the command into hooks[]", event, event)
+				}
+
+				if _, isString := entry.Matcher.(string); entry.Matcher != nil && !isString {
+					add(true, "hooks.%s has a matcher that is not a string, so mush cannot add its own %s hook", event, event)
+				}
+			}
+
+			if event != "Stop" {
+				continue
+			}
+
+			commands := []string{entry.Command}
+			for _, hook := range entry.Hooks {
+				commands = append(commands, hook.Command)
+			}
+
+			for _, command := range commands {
+				if command == "" || strings.Contains(command, mushHookMarker) {
+					continue
+				}
+
+				add(false, "Stop hook %q runs when each turn ends, next to the hook mush uses to mark a job complete; if it blocks stopping (exit code 2 or \"decision\": \"block\"), Claude keeps working after mush has reported the job finished", command)
+			}
+		}
+	}
+
+	if line := settings.StatusLine; line != nil && line.Command != "" && statusLinePrintsPrompt(line.Command) {
+		add(false, "statusLine command %q prints %q, the glyph mush watches for to tell Claude's prompt is ready, so a job can be typed in before Claude can take it", line.Command, strings.TrimSpace(claudePromptGlyph))
+	}
+
+	return conflicts, nil
+}
+
+// statusLinePrintsPrompt reports whether a statusLine command, or the script
+// it runs, contains Claude's prompt glyph.
+func statusLinePrintsPrompt(command string) bool {
+	if strings.Contains(command, claudePromptGlyph) {
+		return true
+	}
+
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return false
+	}
+
+	script := fields[0]
+	if len(fields) > 1 && (fields[0] == "sh" || fields[0] == "bash" || fields[0] == "zsh" || fields[0] == "node" || fields[0] == "python3") {
+		script = fields[1]
+	}
+
+	if rest, ok := strings.CutPrefix(script, "~/"); ok {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return false
+		}
+
+		script = filepath.Join(home, rest)
+	}
+
+	info, err := os.Stat(script)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxStatusLineScript {
+		return false
+	}
+
+	data, err := safeio.ReadFile(script)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Contains(data, []byte(claudePromptGlyph))
+}
@@ -7,6 +7,8 @@
 //   - API connectivity and response time
 //   - Authentication status and credential source
 //   - CLI version against latest release
+//   - Harness process startup in the last worker session
+//   - Claude settings that conflict with the Claude harness
 package doctor
 
 import (
@@ -17,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
@@ -29,6 +32,7 @@ import (
 	"github.com/musher-dev/mush/internal/paths"
 	"github.com/musher-dev/mush/internal/safeio"
 	"github.com/musher-dev/mush/internal/update"
+	"github.com/musher-dev/mush/internal/worker"
 )
 
 // Status represents the result of a diagnostic check.
@@ -57,6 +61,9 @@ type Check func(ctx context.Context) Result
 // Runner executes diagnostic checks.
 type Runner struct {
 	checks []namedCheck
+
+	// OnCheck, if set, is called with each check's name before it runs.
+	OnCheck func(name string)
 }
 
 type namedCheck struct {
@@ -78,6 +85,8 @@ func New() *Runner {
 	r.AddCheck("Clock Skew", checkClockSkew)
 	r.AddCheck("Authentication", checkAuthentication)
 	r.AddCheck("CLI Version", checkCLIVersion)
+	r.AddCheck("Harness Supervision", checkHarnessSupervision)
+	r.AddCheck("Claude Settings", checkClaudeSettings)
 
 	return r
 }
@@ -87,11 +96,20 @@ func (r *Runner) AddCheck(name string, check Check) {
 	r.checks = append(r.checks, namedCheck{name: name, check: check})
 }
 
+// Len returns the number of registered checks.
+func (r *Runner) Len() int {
+	return len(r.checks)
+}
+
 // Run executes all registered checks and returns the results.
 func (r *Runner) Run(ctx context.Context) []Result {
 	results := make([]Result, 0, len(r.checks))
 
 	for _, nc := range r.checks {
+		if r.OnCheck != nil {
+			r.OnCheck(nc.name)
+		}
+
 		result := nc.check(ctx)
 		result.Name = nc.name
 		results = append(results, result)
@@ -291,12 +309,21 @@ func checkAPIConnectivity(ctx context.Context) Result {
 	cfg := config.Load()
 	apiURL := cfg.APIURL()
 
+	// Break a cold connection down by phase so slow DNS or TLS stands out.
+	timing := client.Prewarm(ctx, apiURL, cfg.CACertFile())
+
 	probe := client.ProbeHealth(ctx, apiURL, cfg.CACertFile())
 	if probe.Reachable {
-		return Result{
+		result := Result{
 			Status:  StatusPass,
 			Message: fmt.Sprintf("%s (%dms)", apiURL, probe.Latency.Milliseconds()),
 		}
+
+		if timing.Error == "" {
+			result.Detail = timing.Summary()
+		}
+
+		return result
 	}
 
 	return Result{
@@ -385,6 +412,77 @@ func checkClockSkew(ctx context.Context) Result {
 	}
 }
 
+// slowReadyThreshold is the average startup time above which a harness is
+// reported as slow to become ready.
+const slowReadyThreshold = 10 * time.Second
+
+// checkHarnessSupervision reports how the last worker session's harness
+// processes started: restarts, prompt-detection timeouts, and readiness time.
+func checkHarnessSupervision(context.Context) Result {
+	record, err := worker.LoadSupervision()
+	if errors.Is(err, worker.ErrNoSupervisionRecord) {
+		return Result{
+			Status:  StatusPass,
+			Message: "No worker sessions recorded",
+		}
+	}
+
+	if err != nil {
+		return Result{
+			Status:  StatusWarn,
+			Message: "Cannot read harness supervision record",
+			Detail:  err.Error(),
+		}
+	}
+
+	return supervisionResult(record)
+}
+
+func supervisionResult(record *worker.SupervisionRecord) Result {
+	names := make([]string, 0, len(record.Harnesses))
+	for name := range record.Harnesses {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	status := StatusPass
+	summaries := make([]string, 0, len(names))
+
+	var problems []string
+
+	for _, name := range names {
+		s := record.Harnesses[name]
+		summaries = append(summaries, fmt.Sprintf("%s: %d restarts, %d bypass accepts, %d ready timeouts, ready in %s avg",
+			name, s.PTYRestarts(), s.BypassAccepts, s.ReadyTimeouts, s.AverageReady().Round(100*time.Millisecond)))
+
+		if s.ReadyTimeouts > 0 {
+			status = StatusWarn
+			problems = append(problems, fmt.Sprintf("%s prompt was not detected %d time(s)", name, s.ReadyTimeouts))
+		}
+
+		if s.AverageReady() > slowReadyThreshold {
+			status = StatusWarn
+			problems = append(problems, fmt.Sprintf("%s took over %s to become ready", name, slowReadyThreshold))
+		}
+	}
+
+	detail := "Recorded " + record.UpdatedAt.Local().Format(time.DateTime)
+	if len(problems) > 0 {
+		detail = strings.Join(problems, "; ") + "; run the harness CLI directly to check for prompts, login, or update notices. " + detail
+	}
+
+	if len(summaries) == 0 {
+		summaries = append(summaries, "No supervised harnesses")
+	}
+
+	return Result{
+		Status:  status,
+		Message: strings.Join(summaries, "; "),
+		Detail:  detail,
+	}
+}
+
 func checkProxyEnvironment(context.Context) Result {
 	keys := []string{
 		"HTTPS_PROXY", "https_proxy",
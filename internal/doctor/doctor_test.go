@@ -1,9 +1,15 @@
 package doctor
 
 import (
+	"context"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/worker"
 )
 
 func clearDoctorEnv(t *testing.T) {
@@ -197,3 +203,187 @@ func TestCheckCredentialsFile_TooPermissive(t *testing.T) {
 		t.Errorf("expected WARN, got %v: %s — %s", result.Status, result.Message, result.Detail)
 	}
 }
+
+func TestRunner_OnCheckCalledInOrder(t *testing.T) {
+	runner := &Runner{}
+	runner.AddCheck("First", func(context.Context) Result { return Result{Status: StatusPass} })
+	runner.AddCheck("Second", func(context.Context) Result { return Result{Status: StatusWarn} })
+
+	var started []string
+
+	runner.OnCheck = func(name string) { started = append(started, name) }
+
+	results := runner.Run(t.Context())
+
+	if runner.Len() != 2 || len(results) != 2 {
+		t.Fatalf("Len() = %d, results = %d, want 2", runner.Len(), len(results))
+	}
+
+	if len(started) != 2 || started[0] != "First" || started[1] != "Second" {
+		t.Fatalf("OnCheck calls = %q, want [First Second]", started)
+	}
+}
+
+func TestSupervisionResult(t *testing.T) {
+	healthy := &worker.SupervisionRecord{
+		UpdatedAt: time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC),
+		Harnesses: map[string]harnesstype.Supervision{
+			"claude": {PTYStarts: 2, BypassAccepts: 1, ReadyWaits: 2, ReadyTotal: 6 * time.Second},
+		},
+	}
+
+	result := supervisionResult(healthy)
+	if result.Status != StatusPass {
+		t.Fatalf("expected PASS, got %v: %s — %s", result.Status, result.Message, result.Detail)
+	}
+
+	if !strings.Contains(result.Message, "claude: 1 restarts, 1 bypass accepts, 0 ready timeouts, ready in 3s avg") {
+		t.Fatalf("unexpected message %q", result.Message)
+	}
+
+	flaky := &worker.SupervisionRecord{
+		Harnesses: map[string]harnesstype.Supervision{
+			"claude": {PTYStarts: 1, ReadyTimeouts: 1, ReadyWaits: 1, ReadyTotal: 15 * time.Second},
+		},
+	}
+
+	result = supervisionResult(flaky)
+	if result.Status != StatusWarn {
+		t.Fatalf("expected WARN, got %v: %s — %s", result.Status, result.Message, result.Detail)
+	}
+
+	if !strings.Contains(result.Detail, "prompt was not detected 1 time(s)") || !strings.Contains(result.Detail, "to become ready") {
+		t.Fatalf("unexpected detail %q", result.Detail)
+	}
+}
+
+func TestCheckHarnessSupervision_NoRecord(t *testing.T) {
+	clearDoctorEnv(t)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	result := checkHarnessSupervision(t.Context())
+	if result.Status != StatusPass {
+		t.Errorf("expected PASS, got %v: %s — %s", result.Status, result.Message, result.Detail)
+	}
+}
+
+func TestClaudeSettingsConflicts(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "statusline.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '❯ %s' \"$PWD\"\n"), 0o700); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		settings     string
+		mergesHooks  bool
+		want         []string
+		wantBlocking bool
+	}{
+		{name: "empty", settings: ``},
+		{name: "unrelated settings", settings: `{"model":"opus","permissions":{"defaultMode":"acceptEdits"}}`},
+		{
+			name:     "mush hooks are ignored",
+			settings: `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"sh -c \"touch \\\"$MUSHER_SIGNAL_DIR/complete\\\"\""}]}]}}`,
+		},
+		{
+			name:     "custom stop hook",
+			settings: `{"hooks":{"Stop":[{"hooks":[{"type":"command","command":"./check-tests.sh"}]}]}}`,
+			want:     []string{`Stop hook "./check-tests.sh"`},
+		},
+		{
+			name:         "hooks disabled",
+			settings:     `{"disableAllHooks":true}`,
+			want:         []string{"disableAllHooks is true"},
+			wantBlocking: true,
+		},
+		{
+			name:         "bypass disabled",
+			settings:     `{"permissions":{"disableBypassPermissionsMode":"disable"}}`,
+			want:         []string{"--dangerously-skip-permissions"},
+			wantBlocking: true,
+		},
+		{
+			name:     "plan mode",
+			settings: `{"permissions":{"defaultMode":"plan"}}`,
+			want:     []string{`defaultMode is "plan"`},
+		},
+		{
+			name:         "legacy hook in the file mush merges into",
+			settings:     `{"hooks":{"UserPromptSubmit":[{"command":"log-prompt"}]}}`,
+			mergesHooks:  true,
+			want:         []string{"legacy entry format"},
+			wantBlocking: true,
+		},
+		{
+			name:     "legacy hook elsewhere",
+			settings: `{"hooks":{"UserPromptSubmit":[{"command":"log-prompt"}]}}`,
+		},
+		{
+			name:     "status line prints the prompt glyph",
+			settings: `{"statusLine":{"type":"command","command":"sh ` + script + `"}}`,
+			want:     []string{"statusLine command", "the glyph mush watches for"},
+		},
+		{
+			name:     "plain status line",
+			settings: `{"statusLine":{"type":"command","command":"echo main"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conflicts, err := claudeSettingsConflicts("settings.json", []byte(tt.settings), tt.mergesHooks)
+			if err != nil {
+				t.Fatalf("claudeSettingsConflicts() error = %v", err)
+			}
+
+			if len(tt.want) == 0 {
+				if len(conflicts) != 0 {
+					t.Fatalf("conflicts = %+v, want none", conflicts)
+				}
+
+				return
+			}
+
+			if len(conflicts) != 1 {
+				t.Fatalf("conflicts = %+v, want one", conflicts)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(conflicts[0].problem, want) {
+					t.Errorf("problem = %q, want it to mention %q", conflicts[0].problem, want)
+				}
+			}
+
+			if conflicts[0].blocking != tt.wantBlocking {
+				t.Errorf("blocking = %v, want %v", conflicts[0].blocking, tt.wantBlocking)
+			}
+		})
+	}
+}
+
+func TestCheckClaudeSettings(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("CLAUDE_CONFIG_DIR", configDir)
+	t.Chdir(t.TempDir())
+
+	result := checkClaudeSettings(t.Context())
+	if result.Status != StatusPass {
+		t.Fatalf("expected PASS without settings, got %v: %s — %s", result.Status, result.Message, result.Detail)
+	}
+
+	settings := `{"disableAllHooks":true,"hooks":{"Stop":[{"hooks":[{"type":"command","command":"notify-send done"}]}]}}`
+	if err := os.WriteFile(filepath.Join(configDir, "settings.json"), []byte(settings), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	result = checkClaudeSettings(t.Context())
+	if result.Status != StatusFail {
+		t.Fatalf("expected FAIL, got %v: %s — %s", result.Status, result.Message, result.Detail)
+	}
+
+	if result.Message != "2 setting(s) conflict with the Claude harness" ||
+		!strings.Contains(result.Detail, filepath.Join(configDir, "settings.json")+": disableAllHooks") {
+		t.Fatalf("unexpected result %q — %q", result.Message, result.Detail)
+	}
+}
@@ -0,0 +1,339 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// Artifact limits. Files past them are skipped with a warning; the job
+// still completes.
+const (
+	maxArtifactFiles      = 50
+	maxArtifactBytes      = 25 << 20
+	maxArtifactTotalBytes = 100 << 20
+)
+
+// diffArtifactName is the artifact holding a job's changes when its
+// artifacts config asks for them.
+const diffArtifactName = "changes.diff"
+
+// artifactFile is a file to upload, named as the platform will show it.
+type artifactFile struct {
+	name string
+	path string
+}
+
+// collectArtifacts returns the files to upload with job's result: those the
+// job left in its artifacts directory, those matching the job's artifact
+// patterns in its working directory, and its diff since startHead when
+// asked for. A name seen twice keeps its first file.
+func collectArtifacts(ctx context.Context, job *client.Job, scratch *jobScratch, startHead string) ([]artifactFile, []error) {
+	var (
+		files []artifactFile
+		errs  []error
+		seen  = make(map[string]bool)
+	)
+
+	add := func(name, path string) {
+		if seen[name] {
+			return
+		}
+
+		seen[name] = true
+		files = append(files, artifactFile{name: name, path: path})
+	}
+
+	err := filepath.WalkDir(scratch.artifactsDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		rel, err := filepath.Rel(scratch.artifactsDir, path)
+		if err != nil {
+			return err //nolint:wrapcheck // reported with the directory below
+		}
+
+		add(filepath.ToSlash(rel), path)
+
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("read artifacts directory: %w", err))
+	}
+
+	var config *client.ArtifactConfig
+	if job.Execution != nil {
+		config = job.Execution.Artifacts
+	}
+
+	if config == nil {
+		return files, errs
+	}
+
+	dir := jobWorkDir(job)
+
+	for _, pattern := range config.Paths {
+		matches, err := globArtifacts(dir, pattern)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for _, name := range matches {
+			add(name, filepath.Join(dir, filepath.FromSlash(name)))
+		}
+	}
+
+	if config.Diff && startHead != "" {
+		path, err := writeDiff(ctx, dir, startHead, scratch.root)
+		if err != nil {
+			errs = append(errs, err)
+		} else if path != "" {
+			add(diffArtifactName, path)
+		}
+	}
+
+	return files, errs
+}
+
+// writeDiff writes the changes to tracked files in dir since startHead to
+// a file under scratchDir, returning "" when nothing changed.
+func writeDiff(ctx context.Context, dir, startHead, scratchDir string) (string, error) {
+	diff, err := runGit(ctx, dir, "diff", "--binary", startHead)
+	if err != nil {
+		return "", fmt.Errorf("diff job changes: %w", err)
+	}
+
+	if diff == "" {
+		return "", nil
+	}
+
+	path := filepath.Join(scratchDir, diffArtifactName)
+	if err := os.WriteFile(path, []byte(diff+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write job diff: %w", err)
+	}
+
+	return path, nil
+}
+
+// globArtifacts returns the regular files under dir matching pattern, as
+// slash-separated paths relative to dir. A pattern may not leave dir.
+func globArtifacts(dir, pattern string) ([]string, error) {
+	pattern = strings.TrimPrefix(path.Clean(filepath.ToSlash(pattern)), "./")
+	if !fs.ValidPath(pattern) {
+		return nil, fmt.Errorf("artifact pattern %q must be a relative path inside the working directory", pattern)
+	}
+
+	segments := strings.Split(pattern, "/")
+
+	// Walk only below the pattern's literal leading directories.
+	literal := 0
+	for literal < len(segments)-1 && !strings.ContainsAny(segments[literal], `*?[\`) {
+		literal++
+	}
+
+	root := path.Join(segments[:literal]...)
+	if root == "" {
+		root = "."
+	}
+
+	var matches []string
+
+	err := fs.WalkDir(os.DirFS(dir), root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == ".git" {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.Type().IsRegular() && matchArtifactPattern(segments, strings.Split(name, "/")) {
+			matches = append(matches, name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("match artifact pattern %q: %w", pattern, err)
+	}
+
+	return matches, nil
+}
+
+// matchArtifactPattern reports whether name matches pattern, both split
+// into path segments. A "**" segment matches any number of segments.
+func matchArtifactPattern(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := range len(name) + 1 {
+				if matchArtifactPattern(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+// uploadArtifacts collects job's artifacts and uploads them, returning
+// references to those uploaded. Failures are reported as warnings and
+// never affect the job.
+func (e *Engine) uploadArtifacts(ctx context.Context, job *client.Job, scratch *jobScratch, startHead string) []harnesstype.ArtifactRef {
+	logger := observability.FromContext(ctx)
+
+	warn := func(err error) {
+		logger.Warn("artifact skipped",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.artifact.error"),
+			slog.String("error", err.Error()),
+		)
+		e.ReportError(SeverityWarning, "Artifact upload failed: "+err.Error())
+	}
+
+	files, errs := collectArtifacts(ctx, job, scratch, startHead)
+	for _, err := range errs {
+		warn(err)
+	}
+
+	if len(files) > maxArtifactFiles {
+		warn(fmt.Errorf("job produced %d artifacts; uploading the first %d", len(files), maxArtifactFiles))
+		files = files[:maxArtifactFiles]
+	}
+
+	var (
+		refs  []harnesstype.ArtifactRef
+		total int64
+	)
+
+	for _, file := range files {
+		ref, size, err := e.uploadArtifact(ctx, job.ID, file, maxArtifactTotalBytes-total)
+		if err != nil {
+			warn(err)
+			continue
+		}
+
+		total += size
+
+		refs = append(refs, *ref)
+	}
+
+	if len(refs) > 0 {
+		logger.Info("artifacts uploaded",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.artifact.upload"),
+			slog.Int("artifact.count", len(refs)),
+			slog.Int64("artifact.bytes", total),
+		)
+	}
+
+	return refs
+}
+
+// uploadArtifact uploads one file of at most maxArtifactBytes and budget.
+func (e *Engine) uploadArtifact(ctx context.Context, jobID string, file artifactFile, budget int64) (*harnesstype.ArtifactRef, int64, error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file.name, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file.name, err)
+	}
+
+	size := info.Size()
+
+	switch {
+	case size > maxArtifactBytes:
+		return nil, 0, fmt.Errorf("%s is %d bytes, over the %d byte limit", file.name, size, maxArtifactBytes)
+	case size > budget:
+		return nil, 0, fmt.Errorf("%s would take the job's artifacts over %d bytes", file.name, maxArtifactTotalBytes)
+	}
+
+	contentType, err := artifactContentType(file.name, f)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file.name, err)
+	}
+
+	artifact, err := e.client.UploadArtifact(ctx, jobID, file.name, contentType, io.LimitReader(f, size))
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", file.name, err)
+	}
+
+	name := artifact.Name
+	if name == "" {
+		name = file.name
+	}
+
+	return &harnesstype.ArtifactRef{
+		ID:          artifact.ID,
+		Name:        name,
+		ContentType: contentType,
+		SizeBytes:   size,
+		URL:         artifact.URL,
+	}, size, nil
+}
+
+// artifactContentType returns the file's type from its extension or, failing
+// that, its first bytes, leaving f at its start.
+func artifactContentType(name string, f *os.File) (string, error) {
+	if name == diffArtifactName {
+		return "text/x-diff; charset=utf-8", nil
+	}
+
+	if contentType := mime.TypeByExtension(path.Ext(name)); contentType != "" {
+		return contentType, nil
+	}
+
+	head := make([]byte, 512)
+
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", fmt.Errorf("rewind file: %w", err)
+	}
+
+	return http.DetectContentType(head[:n]), nil
+}
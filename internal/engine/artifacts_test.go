@@ -0,0 +1,129 @@
+//go:build unix
+
+package engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestMatchArtifactPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"coverage.xml", "coverage.xml", true},
+		{"*.xml", "reports/junit.xml", false},
+		{"reports/*.xml", "reports/junit.xml", true},
+		{"reports/**/*.png", "reports/a/b/shot.png", true},
+		{"reports/**/*.png", "reports/shot.png", true},
+		{"**", "a/b/c", true},
+		{"reports/**", "other/a", false},
+	}
+
+	for _, tt := range tests {
+		got := matchArtifactPattern(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/"))
+		if got != tt.want {
+			t.Errorf("matchArtifactPattern(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCollectArtifacts(t *testing.T) {
+	workDir := t.TempDir()
+
+	for _, name := range []string{"reports/unit/junit.xml", "reports/notes.txt", ".git/reports/junit.xml", "report.xml"} {
+		writeTestFile(t, filepath.Join(workDir, name), "x")
+	}
+
+	job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{
+		WorkingDirectory: workDir,
+		Artifacts:        &client.ArtifactConfig{Paths: []string{"reports/**/*.xml", "./report.xml", "../secrets"}},
+	}}
+
+	scratch, cleanup, err := prepareScratch(job)
+	if err != nil {
+		t.Fatalf("prepareScratch() error = %v", err)
+	}
+	defer cleanup()
+
+	writeTestFile(t, filepath.Join(scratch.artifactsDir, "report.xml"), "from the job")
+
+	files, errs := collectArtifacts(t.Context(), job, scratch, "")
+
+	var names []string
+	for _, file := range files {
+		names = append(names, file.name)
+	}
+
+	if want := []string{"report.xml", "reports/unit/junit.xml"}; !slices.Equal(names, want) {
+		t.Fatalf("collected %q, want %q", names, want)
+	}
+
+	if files[0].path != filepath.Join(scratch.artifactsDir, "report.xml") {
+		t.Fatalf("report.xml path = %s, want the artifacts directory's copy", files[0].path)
+	}
+
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "../secrets") {
+		t.Fatalf("errors = %v, want the pattern leaving the working directory", errs)
+	}
+}
+
+func TestEngine_UploadsArtifacts(t *testing.T) {
+	executor := &fakeExecutor{run: func(job *client.Job) {
+		writeTestFile(t, filepath.Join(job.Execution.Environment[harnesstype.EnvArtifactsDir], "screenshots", "home.png"), "png")
+	}}
+
+	eng, platform := newTestEngine(t, executor)
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if !slices.Equal(platform.artifacts, []string{"screenshots/home.png"}) {
+		t.Fatalf("uploaded %q, want the job's screenshot", platform.artifacts)
+	}
+
+	data, err := json.Marshal(platform.outputs[0]["artifacts"])
+	if err != nil {
+		t.Fatalf("marshal artifacts: %v", err)
+	}
+
+	var refs []harnesstype.ArtifactRef
+	if err := json.Unmarshal(data, &refs); err != nil {
+		t.Fatalf("decode artifacts %s: %v", data, err)
+	}
+
+	if len(refs) != 1 || refs[0].ID != "art-1" || refs[0].Name != "screenshots/home.png" || refs[0].ContentType != "image/png" || refs[0].SizeBytes != 3 {
+		t.Fatalf("output artifacts = %s, want a reference to the screenshot", data)
+	}
+}
+
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
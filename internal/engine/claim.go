@@ -0,0 +1,570 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+const (
+	// claimErrorBackoff is the pause after a failed claim request.
+	claimErrorBackoff = 5 * time.Second
+	// executorRefreshBackoff is the pause after a failed executor refresh.
+	executorRefreshBackoff = 2 * time.Second
+)
+
+// claimLoop polls for and processes jobs. Claims stop when claimCtx is
+// canceled; in-flight jobs run under ctx so a drain can let them finish.
+// The engine also stops claiming on its own once a limit in Options is
+// reached, always between jobs.
+func (e *Engine) claimLoop(ctx, claimCtx context.Context) {
+	e.setStatus(StatusConnected)
+
+	started := e.now()
+	processed := 0
+
+	var backoff pollBackoff
+
+	// windDownCtx ends at the next MaxDuration or IdleTimeout deadline and is
+	// replaced after every job, since each one resets the idle timer.
+	windDownCtx, stopReason, cancelWindDown := e.windDownContext(claimCtx, started, started)
+	defer func() { cancelWindDown() }()
+
+	// Jobs are pushed over a stream when the platform supports it and
+	// long-polled otherwise.
+	jobs := e.client.StreamJobs(claimCtx, e.habitatID, e.queueID)
+	defer jobs.Close()
+
+	for claimCtx.Err() == nil {
+		if windDownCtx.Err() != nil {
+			e.finish(stopReason)
+			return
+		}
+
+		// Check if any Refreshable executors need restart.
+		if err := e.maybeRefreshExecutors(claimCtx); err != nil {
+			e.ReportError(SeverityError, fmt.Sprintf("Executor refresh failed: %v", err))
+			sleepContext(windDownCtx, executorRefreshBackoff)
+
+			continue
+		}
+
+		guard := e.worktreeGuard()
+
+		if e.localTaskHoldsClaims() {
+			jobs.Close() // hand back a pushed job while the user has the session
+			sleepContext(windDownCtx, e.config().PollInterval())
+
+			continue
+		}
+
+		if e.claimsPaused(claimCtx, guard) {
+			jobs.Close() // hand back a pushed job instead of holding its lease
+			sleepContext(windDownCtx, e.config().PollInterval())
+
+			continue
+		}
+
+		// A batch claim never takes more jobs than the job limit has left.
+		batchSize := e.config().ClaimBatchSize()
+		if e.maxJobs > 0 {
+			batchSize = min(batchSize, e.maxJobs-processed)
+		}
+
+		jobs.SetBatchSize(batchSize)
+
+		// Wait for a job.
+		pollInterval := e.nextPollInterval(&backoff)
+		claimStarted := e.now()
+
+		job, claimed, err := jobs.Next(windDownCtx, int(pollInterval.Seconds()))
+		if err != nil {
+			if claimCtx.Err() != nil {
+				return // Draining or canceled
+			}
+
+			if windDownCtx.Err() != nil {
+				continue // A limit was reached mid-poll
+			}
+
+			e.reportAPIError(SeverityWarning, "Claim failed", err)
+			sleepContext(windDownCtx, claimErrorBackoff)
+
+			continue
+		}
+
+		e.metrics.claimDuration.ObserveDuration(e.now().Sub(claimStarted))
+
+		if !claimed || job == nil {
+			backoff.recordEmpty()
+			continue // No job, poll again
+		}
+
+		backoff.reset()
+		e.metrics.jobsClaimed.Inc()
+
+		// Map execution.harnessType to local harness selection.
+		harnessType := job.GetHarnessType()
+		if harnessType == "" {
+			e.ReportError(SeverityError, "Missing harness type in job execution config")
+			e.releaseJob(ctx, job)
+
+			continue
+		}
+
+		if !e.isHarnessSupported(harnessType) {
+			e.ReportError(SeverityError, fmt.Sprintf("Unsupported harness type: %s", harnessType))
+			e.releaseJob(ctx, job)
+
+			continue
+		}
+
+		if e.refuseUnsafeJob(ctx, job, guard) {
+			sleepContext(windDownCtx, claimErrorBackoff)
+
+			continue
+		}
+
+		// A local task may have begun while the claim was in flight.
+		if !e.reserveJob(job) {
+			e.releaseJob(ctx, job)
+			continue
+		}
+
+		e.processJob(ctx, job)
+
+		processed++
+		if e.maxJobs > 0 && processed >= e.maxJobs {
+			e.finish(StopJobLimit)
+			return
+		}
+
+		cancelWindDown()
+		windDownCtx, stopReason, cancelWindDown = e.windDownContext(claimCtx, started, e.now())
+	}
+}
+
+// windDownContext returns a context that ends when the engine should stop
+// claiming on its own: MaxDuration after started, or IdleTimeout after
+// lastJob, whichever is sooner. Without either limit it never ends before
+// parent.
+func (e *Engine) windDownContext(parent context.Context, started, lastJob time.Time) (context.Context, StopReason, context.CancelFunc) {
+	var (
+		deadline time.Time
+		reason   StopReason
+	)
+
+	if e.maxDuration > 0 {
+		deadline, reason = started.Add(e.maxDuration), StopMaxDuration
+	}
+
+	if e.idleTimeout > 0 {
+		if idle := lastJob.Add(e.idleTimeout); deadline.IsZero() || idle.Before(deadline) {
+			deadline, reason = idle, StopIdle
+		}
+	}
+
+	if deadline.IsZero() {
+		ctx, cancel := context.WithCancel(parent)
+		return ctx, "", cancel
+	}
+
+	ctx, cancel := context.WithDeadline(parent, deadline)
+
+	return ctx, reason, cancel
+}
+
+// processJob handles the lifecycle of a single job using the executor.
+func (e *Engine) processJob(parentCtx context.Context, job *client.Job) {
+	jobCtx := parentCtx
+
+	if e.jobLogDir != "" {
+		logCtx, closeLog, err := observability.WithJobLog(parentCtx, observability.JobLogPath(e.jobLogDir, job.ID))
+		if err != nil {
+			e.ReportError(SeverityWarning, fmt.Sprintf("Job log disabled: %v", err))
+		} else {
+			jobCtx = logCtx
+			defer func() { _ = closeLog() }()
+		}
+	}
+
+	// Everything below logs through the job-scoped logger, including API calls
+	// and the executor.
+	jobCtx = observability.WithJob(jobCtx, observability.JobScope{
+		JobID:       job.ID,
+		QueueID:     job.QueueID,
+		HarnessType: job.GetHarnessType(),
+		Attempt:     job.AttemptNumber,
+	})
+	logger := observability.FromContext(jobCtx).With(slog.String("component", "engine"))
+
+	ctx, span := observability.Tracer("mush.harness").Start(jobCtx, "job.process",
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID),
+			attribute.String("job.queue_id", job.QueueID),
+			attribute.String("job.harness_type", job.GetHarnessType()),
+			attribute.String("job.priority", job.Priority),
+			attribute.Int("job.attempt_number", job.AttemptNumber),
+		),
+	)
+	defer span.End()
+
+	harnessType := job.GetHarnessType()
+
+	executor, ok := e.executors[harnessType]
+	if !ok {
+		e.ReportError(SeverityError, fmt.Sprintf("No executor for harness type: %s", harnessType))
+		span.SetStatus(codes.Error, "unsupported harness type")
+		e.releaseJob(ctx, job)
+
+		e.jobMu.Lock()
+		e.currentJob = nil
+		e.jobMu.Unlock()
+
+		return
+	}
+
+	// leaseCtx is canceled with errLeaseLost if a resume finds the lease gone.
+	leaseCtx, cancelLease := context.WithCancelCause(ctx)
+	defer cancelLease(nil)
+
+	webhook := e.newJobWebhook(ctx, job)
+
+	e.jobMu.Lock()
+	e.currentJob = job
+	e.cancelJob = cancelLease
+	e.jobWebhook = webhook
+	e.jobStarted = e.now()
+	e.jobMu.Unlock()
+
+	e.setStatus(StatusProcessing)
+	e.emit(Event{Type: EventJobStarted, Status: StatusProcessing, JobID: job.ID})
+	e.recordJobStart(job)
+	e.startNetworkRecording()
+	logger.Info("job started", slog.String("event.type", "job.start"))
+
+	// Start heartbeat for the job.
+	heartbeatCtx, cancelHeartbeat := context.WithCancel(jobCtx)
+	go e.heartbeatLoop(heartbeatCtx, job.ID)
+
+	if reporter, ok := executor.(harnesstype.UsageReporter); ok {
+		go e.usageLoop(heartbeatCtx, job, reporter)
+	}
+
+	go webhook.progressLoop(heartbeatCtx)
+
+	defer func() {
+		cancelHeartbeat()
+		e.jobMu.Lock()
+		e.currentJob = nil
+		e.cancelJob = nil
+		e.jobWebhook = nil
+		e.jobStarted = time.Time{}
+		e.jobMu.Unlock()
+		e.statusMu.Lock()
+		e.jobUsage = nil
+		e.statusMu.Unlock()
+		e.setStatus(StatusConnected)
+	}()
+
+	if _, err := e.client.StartJob(ctx, job.ID); err != nil {
+		e.reportAPIError(SeverityWarning, "Start job failed", err)
+	}
+
+	webhook.post(ctx, client.WebhookJobStarted, nil)
+
+	e.enrichPrompt(ctx, job)
+	e.prependPreviousFailure(ctx, job)
+
+	cleanupWorkspace, err := e.prepareWorkspace(ctx, job)
+	if err != nil {
+		failure := classifyFailure("workspace_error", err)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, failure.Code)
+		logFailure(logger, failure)
+		e.failJob(ctx, job, failure)
+
+		return
+	}
+	defer cleanupWorkspace()
+
+	scratch, cleanupScratch, err := prepareScratch(job)
+	if err != nil {
+		failure := classifyFailure("workspace_error", err)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, failure.Code)
+		logFailure(logger, failure)
+		e.failJob(ctx, job, failure)
+
+		return
+	}
+	defer cleanupScratch()
+
+	if reason, err := e.resolveSecrets(ctx, job); err != nil {
+		failure := classifyFailure(reason, err)
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, failure.Code)
+		logFailure(logger, failure)
+		e.failJob(ctx, job, failure)
+
+		return
+	}
+
+	// Recorded before execution so the result can report what the job changed
+	// and the git workflow can leave the directory as the job found it.
+	start := recordGitStart(ctx, jobWorkDir(job))
+	startHead := start.head
+
+	// Determine execution timeout.
+	execTimeout := DefaultExecutionTimeout
+	if job.Execution != nil && job.Execution.TimeoutMs > 0 {
+		execTimeout = time.Duration(job.Execution.TimeoutMs) * time.Millisecond
+	}
+
+	execTimeout = retryTimeout(job, execTimeout)
+
+	execCtx, cancelExec := context.WithTimeout(leaseCtx, execTimeout)
+	defer cancelExec()
+
+	stopWarning := e.warnBeforeTimeout(execCtx, executor, execTimeout)
+	defer stopWarning()
+
+	// Execute the job via the executor.
+	execCtx, execSpan := observability.Tracer("mush.harness").Start(execCtx, "job.execute",
+		trace.WithAttributes(
+			attribute.String("job.id", job.ID),
+			attribute.String("job.harness_type", harnessType),
+		),
+	)
+
+	result, execErr := executor.Execute(execCtx, job)
+
+	execSpan.End()
+	e.logNetworkSummary(logger)
+
+	// The platform has already requeued the job, so there is nothing to report.
+	if errors.Is(context.Cause(leaseCtx), errLeaseLost) {
+		span.SetStatus(codes.Error, "lease_lost")
+		logger.Warn("job abandoned",
+			slog.String("event.type", "job.lease_lost"),
+		)
+
+		e.statusMu.Lock()
+		e.failed++
+		e.requeued++
+		e.statusMu.Unlock()
+		e.metrics.jobsFailed.Inc()
+
+		e.emit(Event{Type: EventJobFailed, Status: StatusProcessing, JobID: job.ID, Message: errLeaseLost.Error()})
+
+		return
+	}
+
+	if execErr != nil {
+		failure := classifyFailure("execution_error", execErr)
+
+		span.RecordError(execErr)
+		span.SetStatus(codes.Error, failure.Code)
+		logFailure(logger, failure)
+
+		e.failJob(ctx, job, failure)
+		e.resetAfterFailure(jobCtx, executor, job, failure.Retry)
+
+		return
+	}
+
+	// Artifacts are collected first: the git workflow moves the job's changes
+	// out of its working directory onto their own branch.
+	if carrier, ok := result.Output.(harnesstype.ArtifactCarrier); ok {
+		if artifacts := e.uploadArtifacts(ctx, job, scratch, startHead); len(artifacts) > 0 {
+			carrier.SetArtifacts(artifacts)
+		}
+	}
+
+	carrier, hasMetadata := result.Output.(harnesstype.ResultMetadataCarrier)
+	if hasMetadata {
+		carrier.SetResultMetadata(collectResultMetadata(ctx, job, startHead, carrier.ResponseText(), gitWorkflowResult{}))
+	}
+
+	outputData, err := scratch.encodeOutput(result.Output)
+
+	// The git workflow runs only for output that validated, so a job that
+	// fails never leaves a pushed branch or an open pull request behind.
+	if err == nil && hasMetadata {
+		workflow := e.runGitWorkflow(ctx, job, start, carrier.ResponseText())
+		if workflow.commit != "" || workflow.pullRequestURL != "" {
+			carrier.SetResultMetadata(collectResultMetadata(ctx, job, startHead, carrier.ResponseText(), workflow))
+			outputData, err = harnesstype.EncodeOutput(result.Output)
+		}
+	}
+
+	if err == nil {
+		outputData, err = e.processOutput(ctx, job, result.Output, outputData)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "invalid_output")
+		logger.Error("job output rejected",
+			slog.String("event.type", "job.fail"),
+			slog.String("job.error_code", "invalid_output"),
+			slog.String("error", err.Error()),
+		)
+		e.failJob(ctx, job, classifyFailure("invalid_output", err))
+
+		return
+	}
+
+	span.SetStatus(codes.Ok, "")
+	logger.Info("job finished", slog.String("event.type", "job.complete"))
+	e.completeJob(ctx, job, outputData)
+
+	// Reset the executor for the next job.
+	if err := executor.Reset(jobCtx); err != nil {
+		e.ReportError(SeverityError, fmt.Sprintf("Executor reset failed: %v", err))
+	}
+}
+
+// heartbeatLoop sends periodic heartbeats for the current job.
+func (e *Engine) heartbeatLoop(ctx context.Context, jobID string) {
+	ticker := time.NewTicker(e.config().HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := e.client.HeartbeatJob(ctx, jobID); err != nil {
+				observability.FromContext(ctx).Warn("job heartbeat failed",
+					slog.String("component", "engine"),
+					slog.String("event.type", "job.heartbeat.error"),
+					slog.String("error", err.Error()),
+				)
+				e.reportAPIError(SeverityWarning, "Heartbeat failed", err)
+				e.metrics.heartbeatFailures.Inc()
+
+				continue
+			}
+
+			e.statusMu.Lock()
+			e.lastHeartbeat = e.now()
+			e.statusMu.Unlock()
+		}
+	}
+}
+
+// completeJob reports job completion to the API.
+func (e *Engine) completeJob(ctx context.Context, job *client.Job, outputData map[string]any) {
+	if err := e.client.CompleteJob(ctx, job.ID, outputData); err != nil {
+		e.reportAPIError(SeverityError, "Complete failed", err)
+		e.failJob(ctx, job, classifyFailure("completion_report_failed", err))
+
+		return
+	}
+
+	e.statusMu.Lock()
+	e.completed++
+	e.statusMu.Unlock()
+	e.metrics.jobsCompleted.Inc()
+
+	e.currentWebhook().post(ctx, client.WebhookJobCompleted, nil)
+	e.publishResult(ctx, job, outputData, nil)
+	e.recordJobEnd(job, outputData, nil)
+	e.emit(Event{Type: EventJobCompleted, Status: StatusProcessing, JobID: job.ID})
+}
+
+// releaseJob returns a job to the queue.
+func (e *Engine) releaseJob(ctx context.Context, job *client.Job) {
+	if err := e.client.ReleaseJob(ctx, job.ID); err != nil {
+		e.reportAPIError(SeverityWarning, "Release failed", err)
+	}
+}
+
+// failJob reports a classified job failure to the API.
+func (e *Engine) failJob(ctx context.Context, job *client.Job, failure Failure) {
+	if err := e.client.FailJob(ctx, job.ID, failure.Code, failure.Message, failure.Details(), failure.Retry); err != nil {
+		e.reportAPIError(SeverityError, "Fail report failed", err)
+	}
+
+	e.statusMu.Lock()
+	e.failed++
+
+	if failure.Retry {
+		e.requeued++
+	}
+	e.statusMu.Unlock()
+	e.metrics.jobsFailed.Inc()
+
+	e.currentWebhook().post(ctx, client.WebhookJobFailed, &failure)
+	e.publishResult(ctx, job, nil, &failure)
+	e.recordJobEnd(job, nil, &failure)
+	e.emit(Event{Type: EventJobFailed, Status: StatusProcessing, JobID: job.ID, Message: failure.Message})
+}
+
+// logFailure records why a job failed and what the policy decided.
+func logFailure(logger *slog.Logger, failure Failure) {
+	attrs := []any{
+		slog.String("event.type", "job.fail"),
+		slog.String("job.error_code", failure.Code),
+		slog.Bool("job.retry", failure.Retry),
+		slog.String("error", failure.Message),
+	}
+
+	if failure.Reason != failure.Code {
+		attrs = append(attrs, slog.String("job.error_reason", failure.Reason))
+	}
+
+	if failure.Backoff > 0 {
+		attrs = append(attrs, slog.Int64("job.retry_after_ms", failure.Backoff.Milliseconds()))
+	}
+
+	logger.Warn("job failed", attrs...)
+}
+
+func (e *Engine) setStatus(status Status) {
+	e.statusMu.Lock()
+	changed := e.status != status
+	e.status = status
+	e.statusMu.Unlock()
+
+	if changed {
+		e.emit(Event{Type: EventStatusChanged, Status: status})
+	}
+}
+
+func (e *Engine) isHarnessSupported(harnessType string) bool {
+	for _, supported := range e.supportedHarnesses {
+		if supported == harnessType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sleepContext pauses for d or until ctx is canceled.
+func sleepContext(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
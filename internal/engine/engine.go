@@ -0,0 +1,575 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/buildinfo"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/netrecord"
+	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/worker"
+	"github.com/musher-dev/mush/internal/workspace"
+)
+
+// DefaultExecutionTimeout is the fallback when no execution timeout is set on the job.
+const DefaultExecutionTimeout = 10 * time.Minute
+
+// ErrAlreadyStarted is returned when Start is called more than once.
+var ErrAlreadyStarted = errors.New("engine already started")
+
+// ErrRegistrationFailed is returned by Start when the worker cannot be
+// registered with the platform. The request's error is wrapped with it.
+var ErrRegistrationFailed = errors.New("failed to register worker")
+
+// Options configures an Engine.
+type Options struct {
+	Client     *client.Client
+	Config     *config.Config
+	HabitatID  string
+	QueueID    string
+	InstanceID string
+
+	// QueueSlug selects per-queue settings under worker.queues alongside
+	// QueueID. It may be empty.
+	QueueSlug string
+
+	// Executors maps harness type to a set-up executor. The map is shared with
+	// the host, which may populate it after New but must not modify it after Start.
+	Executors          map[string]harnesstype.Executor
+	SupportedHarnesses []string
+
+	// RunnerConfig is the runner config the executors were set up with.
+	RunnerConfig *client.RunnerConfigResponse
+
+	// RefreshInterval is the delay before the first runner config refresh.
+	// Zero uses the default interval.
+	RefreshInterval time.Duration
+
+	// InitialStatus is reported until Start is called.
+	InitialStatus Status
+
+	// MaxJobs stops claiming after this many jobs have been processed; the
+	// engine then reports StopJobLimit through Finished. Zero means no limit.
+	MaxJobs int
+
+	// MaxDuration stops claiming once this long has passed since Start, and
+	// IdleTimeout once this long has passed without a job. A job in progress
+	// is always finished first. Zero disables either limit.
+	MaxDuration time.Duration
+	IdleTimeout time.Duration
+
+	// Workspaces holds repository checkouts for jobs that name a repository.
+	// Nil uses the cache under the state directory.
+	Workspaces *workspace.Cache
+
+	// JobRecorder, if set, is called when a job starts and again when it
+	// completes or fails, so the host can mark the job in its transcript.
+	JobRecorder func(*transcript.JobRecord)
+
+	// NetworkRecorder, if set, is the proxy the executors' processes send
+	// their HTTP calls through. The engine attributes the calls made while a
+	// job runs to that job.
+	NetworkRecorder *netrecord.Recorder
+
+	// JobLogDir, if set, receives a log of each job's records, one file per
+	// job named by observability.JobLogPath.
+	JobLogDir string
+
+	// Now overrides the clock, mainly for tests.
+	Now func() time.Time
+}
+
+// Engine manages job polling, execution, heartbeats, and worker lifecycle.
+type Engine struct {
+	client     *client.Client
+	habitatID  string
+	queueID    string
+	queueSlug  string
+	instanceID string
+
+	// Config (guarded by cfgMu); replaced by Reload.
+	cfgMu sync.Mutex
+	cfg   *config.Config
+
+	// Set once, read-only thereafter.
+	executors          map[string]harnesstype.Executor
+	supportedHarnesses []string
+	workspaces         *workspace.Cache
+	jobRecorder        func(*transcript.JobRecord)
+	network            *netrecord.Recorder
+	jobLogDir          string
+	metrics            *engineMetrics
+	maxJobs            int
+	maxDuration        time.Duration
+	idleTimeout        time.Duration
+	now                func() time.Time
+
+	// Job lifecycle state (guarded by jobMu).
+	jobMu      sync.Mutex
+	currentJob *client.Job
+	cancelJob  context.CancelCauseFunc
+	jobWebhook *jobWebhook
+	jobStarted time.Time
+	// localTask is set while the user works in the session between jobs;
+	// statusBeforeLocalTask is restored when it ends.
+	localTask             bool
+	statusBeforeLocalTask Status
+
+	// Status state (guarded by statusMu).
+	statusMu      sync.Mutex
+	status        Status
+	lastHeartbeat time.Time
+	completed     int
+	failed        int
+	requeued      int
+	errors        errorHistory
+	workerID      string
+	pausedReason  string
+	maintenance   *client.MaintenanceWindow
+	jobUsage      *JobUsage
+	stopReason    StopReason
+	pollInterval  time.Duration
+	pollBackedOff bool
+
+	// Runner config refresh state (guarded by refreshMu).
+	refreshMu       sync.Mutex
+	refreshInterval time.Duration
+	runnerConfig    *client.RunnerConfigResponse
+	refreshNow      chan struct{}
+
+	// Event delivery (eventsClosed guarded by eventsMu).
+	eventsMu     sync.Mutex
+	events       chan Event
+	eventsClosed bool
+
+	// Run state (guarded by runMu).
+	runMu        sync.Mutex
+	started      bool
+	drained      bool
+	cancel       context.CancelFunc
+	stopClaiming context.CancelFunc
+	claimDone    chan struct{}
+	loops        sync.WaitGroup
+
+	// finished is closed when the engine stops claiming on its own.
+	finished   chan struct{}
+	finishOnce sync.Once
+}
+
+// Stats holds a point-in-time snapshot of engine state.
+type Stats struct {
+	Status        Status
+	WorkerID      string
+	JobID         string
+	LastHeartbeat time.Time
+
+	// JobStartedAt is when the running job started, or zero.
+	JobStartedAt time.Time
+
+	Completed     int
+	Failed        int
+	LastError     string
+	LastErrorTime time.Time
+
+	// Requeued counts the Failed jobs the platform takes back to run again:
+	// those whose lease was lost and those failed as retryable.
+	Requeued int
+
+	// LastErrorSeverity and LastErrorCount describe the LastError entry.
+	LastErrorSeverity Severity
+	LastErrorCount    int
+
+	// LastErrorRequestID is the API request ID behind LastError, if any.
+	LastErrorRequestID string
+
+	// Errors is the deduplicated error history, newest first.
+	Errors []ErrorEntry
+
+	// Usage is the running job's usage, or nil before the executor reports any.
+	Usage *JobUsage
+
+	// StopReason is why the engine stopped claiming on its own or through
+	// Stop, or empty.
+	StopReason StopReason
+
+	// PollInterval is the interval of the current or next claim, and
+	// PollBackedOff reports that it was lengthened because the queue has
+	// been empty. PollInterval is zero until the first claim.
+	PollInterval  time.Duration
+	PollBackedOff bool
+}
+
+// New creates an Engine. It does not contact the platform until Start.
+func New(opts *Options) *Engine {
+	now := opts.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Load()
+	}
+
+	return &Engine{
+		client:             opts.Client,
+		cfg:                cfg,
+		habitatID:          opts.HabitatID,
+		queueID:            opts.QueueID,
+		queueSlug:          opts.QueueSlug,
+		instanceID:         opts.InstanceID,
+		executors:          opts.Executors,
+		supportedHarnesses: append([]string(nil), opts.SupportedHarnesses...),
+		workspaces:         opts.Workspaces,
+		jobRecorder:        opts.JobRecorder,
+		network:            opts.NetworkRecorder,
+		jobLogDir:          opts.JobLogDir,
+		metrics:            newEngineMetrics(),
+		maxJobs:            opts.MaxJobs,
+		maxDuration:        opts.MaxDuration,
+		idleTimeout:        opts.IdleTimeout,
+		now:                now,
+		status:             opts.InitialStatus,
+		lastHeartbeat:      now(),
+		refreshInterval:    opts.RefreshInterval,
+		runnerConfig:       opts.RunnerConfig,
+		refreshNow:         make(chan struct{}, 1),
+		events:             make(chan Event, eventBufferSize),
+		finished:           make(chan struct{}),
+	}
+}
+
+// Start registers the worker and begins claiming jobs in the background.
+// The loops run until Drain is called or ctx is canceled.
+func (e *Engine) Start(ctx context.Context) error {
+	e.runMu.Lock()
+	defer e.runMu.Unlock()
+
+	if e.started {
+		return ErrAlreadyStarted
+	}
+
+	if e.client == nil {
+		return fmt.Errorf("engine requires an API client")
+	}
+
+	name, metadata := worker.DefaultWorkerInfo()
+
+	workerID, err := worker.Register(ctx, e.client, e.habitatID, e.instanceID, name, metadata, buildinfo.Version)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrRegistrationFailed, err)
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	claimCtx, stopClaiming := context.WithCancel(runCtx)
+
+	e.statusMu.Lock()
+	e.workerID = workerID
+	e.statusMu.Unlock()
+
+	e.started = true
+	e.cancel = cancel
+	e.stopClaiming = stopClaiming
+	e.claimDone = make(chan struct{})
+
+	worker.StartHeartbeat(runCtx, e.client, workerID, e.CurrentJobID, func(resp *client.WorkerHeartbeatResponse) {
+		e.setMaintenance(runCtx, resp.Maintenance)
+	}, func(err error) {
+		e.reportAPIError(SeverityWarning, "Worker heartbeat failed", err)
+	})
+
+	e.loops.Add(1)
+
+	go func() {
+		defer e.loops.Done()
+		defer close(e.claimDone)
+
+		e.claimLoop(runCtx, claimCtx)
+	}()
+
+	e.loops.Add(1)
+
+	go func() { defer e.loops.Done(); e.wakeLoop(runCtx) }()
+
+	if e.hasRefreshableExecutor() {
+		e.loops.Add(1)
+
+		go func() { defer e.loops.Done(); e.refreshLoop(runCtx) }()
+	}
+
+	return nil
+}
+
+// Drain stops claiming new jobs, lets the in-flight job finish, stops the
+// background loops, and deregisters the worker. If ctx ends first, the
+// in-flight job is canceled and the context error is returned.
+// The Events channel is closed when Drain returns.
+func (e *Engine) Drain(ctx context.Context) error {
+	e.runMu.Lock()
+	defer e.runMu.Unlock()
+
+	if e.drained {
+		return nil
+	}
+
+	e.drained = true
+	defer e.closeEvents()
+
+	if !e.started {
+		return nil
+	}
+
+	var drainErr error
+
+	e.stopClaiming()
+
+	select {
+	case <-e.claimDone:
+	case <-ctx.Done():
+		drainErr = fmt.Errorf("drain engine: %w", ctx.Err())
+	}
+
+	e.cancel()
+
+	loopsDone := make(chan struct{})
+
+	go func() { e.loops.Wait(); close(loopsDone) }()
+
+	select {
+	case <-loopsDone:
+	case <-ctx.Done():
+		if drainErr == nil {
+			drainErr = fmt.Errorf("drain engine: %w", ctx.Err())
+		}
+	}
+
+	stats := e.Stats()
+	if err := worker.Deregister(e.client, stats.WorkerID, stats.Completed, stats.Failed); err != nil {
+		e.reportAPIError(SeverityWarning, "Worker deregistration failed", err)
+
+		if drainErr == nil {
+			drainErr = fmt.Errorf("drain engine: %w", err)
+		}
+	}
+
+	return drainErr
+}
+
+// Events returns the channel of engine events. Events are dropped rather
+// than blocking the job loop when the consumer falls behind.
+func (e *Engine) Events() <-chan Event {
+	return e.events
+}
+
+// Finished is closed when the engine stops claiming jobs on its own, such as
+// after MaxJobs or IdleTimeout, or through Stop; Stats reports the reason.
+// The host should then Drain.
+func (e *Engine) Finished() <-chan struct{} {
+	return e.finished
+}
+
+// Stop asks the host to wind the engine down as if a limit had been reached:
+// Finished is closed with StopRequested, and the host's Drain lets the
+// in-flight job finish before deregistering.
+func (e *Engine) Stop() {
+	e.finish(StopRequested)
+}
+
+// finish stops claiming for reason. Only the first reason is kept.
+func (e *Engine) finish(reason StopReason) {
+	e.finishOnce.Do(func() {
+		e.statusMu.Lock()
+		e.stopReason = reason
+		status := e.status
+		e.statusMu.Unlock()
+
+		e.emit(Event{Type: EventClaimingStopped, Status: status, Message: string(reason)})
+		close(e.finished)
+	})
+}
+
+// Stats returns a consistent snapshot of the engine state.
+func (e *Engine) Stats() Stats {
+	e.statusMu.Lock()
+	stats := Stats{
+		Status:        e.status,
+		WorkerID:      e.workerID,
+		LastHeartbeat: e.lastHeartbeat,
+		Completed:     e.completed,
+		Failed:        e.failed,
+		Requeued:      e.requeued,
+		Errors:        e.errors.snapshot(),
+		StopReason:    e.stopReason,
+		PollInterval:  e.pollInterval,
+		PollBackedOff: e.pollBackedOff,
+	}
+
+	if e.jobUsage != nil {
+		usage := *e.jobUsage
+		stats.Usage = &usage
+	}
+
+	if latest, ok := e.errors.latest(); ok {
+		stats.LastError = latest.Message
+		stats.LastErrorTime = latest.LastSeen
+		stats.LastErrorSeverity = latest.Severity
+		stats.LastErrorCount = latest.Count
+		stats.LastErrorRequestID = latest.RequestID
+	}
+
+	e.statusMu.Unlock()
+
+	e.jobMu.Lock()
+	if e.currentJob != nil {
+		stats.JobID = e.currentJob.ID
+		stats.JobStartedAt = e.jobStarted
+	}
+	e.jobMu.Unlock()
+
+	return stats
+}
+
+// RunnerConfig returns the current runner config.
+func (e *Engine) RunnerConfig() *client.RunnerConfigResponse {
+	e.refreshMu.Lock()
+	defer e.refreshMu.Unlock()
+
+	return e.runnerConfig
+}
+
+// CurrentJobID returns the ID of the currently executing job, or "".
+func (e *Engine) CurrentJobID() string {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if e.currentJob == nil {
+		return ""
+	}
+
+	return e.currentJob.ID
+}
+
+// CurrentJobHarnessType returns the harness type of the currently executing job, or "".
+func (e *Engine) CurrentJobHarnessType() string {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if e.currentJob == nil {
+		return ""
+	}
+
+	return e.currentJob.GetHarnessType()
+}
+
+// HasActiveInterruptableJob returns true when the current job's executor
+// implements harnesstype.InterruptHandler.
+func (e *Engine) HasActiveInterruptableJob() bool {
+	harnessType := e.CurrentJobHarnessType()
+	if harnessType == "" {
+		return false
+	}
+
+	executor, ok := e.executors[harnessType]
+	if !ok {
+		return false
+	}
+
+	_, isHandler := executor.(harnesstype.InterruptHandler)
+
+	return isHandler
+}
+
+// Reload replaces the engine's config. The poll interval applies from the
+// next claim and the heartbeat interval from the next job.
+func (e *Engine) Reload(cfg *config.Config) {
+	e.cfgMu.Lock()
+	e.cfg = cfg
+	e.cfgMu.Unlock()
+}
+
+func (e *Engine) config() *config.Config {
+	e.cfgMu.Lock()
+	defer e.cfgMu.Unlock()
+
+	return e.cfg
+}
+
+// SetStatus updates the engine status, for hosts that drive executors
+// directly (such as interactive bundle sessions).
+func (e *Engine) SetStatus(status Status) {
+	e.setStatus(status)
+}
+
+// ReportError records an error in the history surfaced by Stats. Repeats of
+// the same message are counted rather than stored again.
+func (e *Engine) ReportError(severity Severity, msg string) {
+	e.statusMu.Lock()
+	e.errors.record(severity, msg, e.now())
+	status := e.status
+	e.statusMu.Unlock()
+
+	e.emit(Event{Type: EventError, Status: status, Message: msg})
+}
+
+// reportAPIError records a failed platform call as "<what>: <err>". The
+// message keeps the request IDs for platform-side debugging, but repeats
+// that differ only in those IDs are still folded together.
+func (e *Engine) reportAPIError(severity Severity, what string, err error) {
+	msg := fmt.Sprintf("%s: %v", what, err)
+
+	e.statusMu.Lock()
+	e.errors.recordRequest(severity, msg, client.RequestIDs(err), e.now())
+	status := e.status
+	e.statusMu.Unlock()
+
+	e.emit(Event{Type: EventError, Status: status, Message: msg})
+}
+
+// emit delivers an event without blocking.
+func (e *Engine) emit(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = e.now()
+	}
+
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+
+	if e.eventsClosed {
+		return
+	}
+
+	select {
+	case e.events <- ev:
+	default:
+	}
+}
+
+func (e *Engine) closeEvents() {
+	e.eventsMu.Lock()
+	defer e.eventsMu.Unlock()
+
+	if e.eventsClosed {
+		return
+	}
+
+	e.eventsClosed = true
+	close(e.events)
+}
+
+func (e *Engine) hasRefreshableExecutor() bool {
+	for _, executor := range e.executors {
+		if _, ok := executor.(harnesstype.Refreshable); ok {
+			return true
+		}
+	}
+
+	return false
+}
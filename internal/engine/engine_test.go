@@ -0,0 +1,700 @@
+//go:build unix
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+func requireLocalListener(t *testing.T) {
+	t.Helper()
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(t.Context(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("local listener not available in this environment: %v", err)
+		return
+	}
+
+	_ = ln.Close()
+}
+
+// fakePlatform serves the runner endpoints the engine calls and hands out
+// a single job.
+type fakePlatform struct {
+	mu         sync.Mutex
+	claimed    bool
+	completed  []string
+	outputs    []map[string]any
+	failed     []client.JobFailRequest
+	artifacts  []string
+	deregister *client.DeregisterWorkerRequest
+
+	// claimBody replaces the default claim response for the single job.
+	claimBody string
+
+	// leaseGone makes job heartbeats fail as if the lease had expired.
+	leaseGone bool
+
+	// holdClaims makes empty claims long-poll until the request ends;
+	// polling is closed when the first one starts.
+	holdClaims bool
+	polling    chan struct{}
+}
+
+func (p *fakePlatform) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/workers:register"):
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"workerId":"worker-1"}`))
+	case strings.HasSuffix(r.URL.Path, "/jobs:claim"):
+		p.mu.Lock()
+		first := !p.claimed
+		p.claimed = true
+		body := p.claimBody
+		p.mu.Unlock()
+
+		if first {
+			if body == "" {
+				body = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"}}`
+			}
+
+			_, _ = w.Write([]byte(body))
+
+			return
+		}
+
+		p.mu.Lock()
+		hold := p.holdClaims
+		if hold {
+			select {
+			case <-p.polling:
+			default:
+				close(p.polling)
+			}
+		}
+		p.mu.Unlock()
+
+		if hold {
+			// The server only notices the client hanging up once the body
+			// has been read.
+			_, _ = io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+
+			return
+		}
+
+		// Simulate a short long-poll with no work available.
+		select {
+		case <-r.Context().Done():
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	case strings.Contains(r.URL.Path, "/jobs/") && strings.HasSuffix(r.URL.Path, ":heartbeat"):
+		p.mu.Lock()
+		gone := p.leaseGone
+		p.mu.Unlock()
+
+		if gone {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		_, _ = w.Write([]byte(`{}`))
+	case strings.HasSuffix(r.URL.Path, ":complete"):
+		jobID := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/runner/jobs/"), ":complete")
+
+		var req client.JobCompleteRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		p.mu.Lock()
+		p.completed = append(p.completed, jobID)
+		p.outputs = append(p.outputs, req.OutputData)
+		p.mu.Unlock()
+
+		_, _ = w.Write([]byte(`{}`))
+	case strings.HasSuffix(r.URL.Path, "/artifacts"):
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		_ = file.Close()
+		name := r.FormValue("name")
+
+		p.mu.Lock()
+		p.artifacts = append(p.artifacts, name)
+		id := fmt.Sprintf("art-%d", len(p.artifacts))
+		p.mu.Unlock()
+
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"id":%q,"name":%q,"sizeBytes":%d}`, id, name, header.Size)
+	case strings.HasSuffix(r.URL.Path, ":fail"):
+		var req client.JobFailRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		p.mu.Lock()
+		p.failed = append(p.failed, req)
+		p.mu.Unlock()
+
+		_, _ = w.Write([]byte(`{}`))
+	case strings.HasSuffix(r.URL.Path, ":deregister"):
+		var req client.DeregisterWorkerRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		p.mu.Lock()
+		p.deregister = &req
+		p.mu.Unlock()
+
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		_, _ = w.Write([]byte(`{}`))
+	}
+}
+
+type fakeExecutor struct {
+	err    error
+	output harnesstype.JobOutput
+
+	// run, if set, is called with the job before Execute returns.
+	run func(*client.Job)
+
+	// block makes Execute wait until its context is canceled.
+	block bool
+}
+
+func (e *fakeExecutor) Setup(context.Context, *harnesstype.SetupOptions) error { return nil }
+
+func (e *fakeExecutor) Execute(ctx context.Context, job *client.Job) (*harnesstype.ExecResult, error) {
+	if e.block {
+		<-ctx.Done()
+
+		return nil, ctx.Err()
+	}
+
+	if e.run != nil {
+		e.run(job)
+	}
+
+	if e.err != nil {
+		return nil, e.err
+	}
+
+	if e.output != nil {
+		return &harnesstype.ExecResult{Output: e.output}, nil
+	}
+
+	return &harnesstype.ExecResult{Output: harnesstype.NewAgentJobOutput("done", time.Second)}, nil
+}
+
+func (e *fakeExecutor) Reset(context.Context) error { return nil }
+
+func (e *fakeExecutor) Teardown() {}
+
+func newTestEngine(t *testing.T, executor harnesstype.Executor) (*Engine, *fakePlatform) {
+	t.Helper()
+	requireLocalListener(t)
+
+	platform := &fakePlatform{}
+	srv := httptest.NewServer(platform)
+	t.Cleanup(srv.Close)
+
+	eng := New(&Options{
+		Client:             client.New(srv.URL, "test-key"),
+		Config:             config.Load(),
+		QueueID:            "queue-1",
+		Executors:          map[string]harnesstype.Executor{"test": executor},
+		SupportedHarnesses: []string{"test"},
+		InitialStatus:      StatusConnecting,
+	})
+
+	return eng, platform
+}
+
+func waitForEvent(t *testing.T, events <-chan Event, want EventType) Event {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+
+	for {
+		select {
+		case ev, ok := <-events:
+			if !ok {
+				t.Fatalf("events closed before %s", want)
+			}
+
+			if ev.Type == want {
+				return ev
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s event", want)
+		}
+	}
+}
+
+func TestEngine_DrainInterruptsLongPoll(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimed = true
+	platform.holdClaims = true
+	platform.polling = make(chan struct{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	select {
+	case <-platform.polling:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the claim long-poll")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	if err := eng.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Drain() took %s during a long-poll, want it to cancel the claim immediately", elapsed)
+	}
+}
+
+func TestEngine_CompletesClaimedJobAndDrains(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ev := waitForEvent(t, eng.Events(), EventJobCompleted)
+	if ev.JobID != "job-1" {
+		t.Fatalf("completed JobID = %q, want job-1", ev.JobID)
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	stats := eng.Stats()
+	if stats.Completed != 1 || stats.Failed != 0 {
+		t.Fatalf("stats completed=%d failed=%d, want 1/0", stats.Completed, stats.Failed)
+	}
+
+	if stats.WorkerID != "worker-1" {
+		t.Fatalf("WorkerID = %q, want worker-1", stats.WorkerID)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.completed) != 1 || platform.completed[0] != "job-1" {
+		t.Fatalf("completed jobs = %v, want [job-1]", platform.completed)
+	}
+
+	if platform.deregister == nil || platform.deregister.JobsCompleted != 1 {
+		t.Fatalf("deregister = %+v, want JobsCompleted=1", platform.deregister)
+	}
+
+	for range eng.Events() {
+		// Drain closes the channel; ranging must terminate.
+	}
+}
+
+func TestEngine_WritesJobLog(t *testing.T) {
+	eng, _ := newTestEngine(t, &fakeExecutor{})
+	eng.jobLogDir = t.TempDir()
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	data, err := os.ReadFile(observability.JobLogPath(eng.jobLogDir, "job-1"))
+	if err != nil {
+		t.Fatalf("read job log: %v", err)
+	}
+
+	var events []string
+
+	for _, line := range bytes.Split(bytes.TrimSpace(data), []byte("\n")) {
+		rec := observability.ParseLogRecord(line)
+		if rec.Attr("job.id") != "job-1" {
+			t.Fatalf("job log line %s, want job.id job-1", line)
+		}
+
+		events = append(events, rec.Attr("event.type"))
+	}
+
+	if !slices.Contains(events, "job.start") || !slices.Contains(events, "job.complete") {
+		t.Fatalf("job log events = %v, want job.start and job.complete", events)
+	}
+}
+
+func TestEngine_MaxJobsStopsClaiming(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	eng.maxJobs = 1
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ev := waitForEvent(t, eng.Events(), EventClaimingStopped)
+	if ev.Message != string(StopJobLimit) {
+		t.Fatalf("claiming stopped Message = %q, want %q", ev.Message, StopJobLimit)
+	}
+
+	select {
+	case <-eng.Finished():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Finished() not closed after the job limit")
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	stats := eng.Stats()
+	if stats.StopReason != StopJobLimit {
+		t.Fatalf("StopReason = %q, want %q", stats.StopReason, StopJobLimit)
+	}
+
+	if stats.Completed != 1 {
+		t.Fatalf("Completed = %d, want 1", stats.Completed)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if platform.deregister == nil || platform.deregister.JobsCompleted != 1 {
+		t.Fatalf("deregister = %+v, want JobsCompleted=1", platform.deregister)
+	}
+}
+
+func TestEngine_WindDownLimitsStopClaiming(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxDuration time.Duration
+		idleTimeout time.Duration
+		want        StopReason
+	}{
+		{name: "max duration ends a long-poll", maxDuration: 100 * time.Millisecond, want: StopMaxDuration},
+		{name: "idle timeout", idleTimeout: 100 * time.Millisecond, want: StopIdle},
+		{name: "sooner limit wins", maxDuration: time.Hour, idleTimeout: 100 * time.Millisecond, want: StopIdle},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eng, platform := newTestEngine(t, &fakeExecutor{})
+			eng.maxDuration = tt.maxDuration
+			eng.idleTimeout = tt.idleTimeout
+			platform.claimed = true
+			platform.holdClaims = tt.want == StopMaxDuration
+			platform.polling = make(chan struct{})
+
+			if err := eng.Start(t.Context()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			select {
+			case <-eng.Finished():
+			case <-time.After(5 * time.Second):
+				t.Fatal("Finished() not closed after the limit")
+			}
+
+			if err := eng.Drain(t.Context()); err != nil {
+				t.Fatalf("Drain() error = %v", err)
+			}
+
+			stats := eng.Stats()
+			if stats.StopReason != tt.want {
+				t.Fatalf("StopReason = %q, want %q", stats.StopReason, tt.want)
+			}
+
+			if len(stats.Errors) != 0 {
+				t.Fatalf("Errors = %+v, want none for a limit reached mid-poll", stats.Errors)
+			}
+
+			platform.mu.Lock()
+			defer platform.mu.Unlock()
+
+			if platform.deregister == nil {
+				t.Fatal("worker was not deregistered")
+			}
+		})
+	}
+}
+
+func TestEngine_StopWindsDownLikeALimit(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimed = true
+	platform.holdClaims = true
+	platform.polling = make(chan struct{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	eng.Stop()
+	eng.finish(StopIdle)
+
+	select {
+	case <-eng.Finished():
+	case <-time.After(5 * time.Second):
+		t.Fatal("Finished() not closed after Stop")
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if got := eng.Stats().StopReason; got != StopRequested {
+		t.Fatalf("StopReason = %q, want %q", got, StopRequested)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if platform.deregister == nil {
+		t.Fatal("worker was not deregistered")
+	}
+}
+
+func TestEngine_PermanentExecErrorFailsWithoutRetry(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{
+		err: &harnesstype.ExecError{Reason: "invalid_input", Message: "bad prompt", Retry: false},
+	})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ev := waitForEvent(t, eng.Events(), EventJobFailed)
+	if ev.Message != "bad prompt" {
+		t.Fatalf("failure message = %q, want %q", ev.Message, "bad prompt")
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if stats := eng.Stats(); stats.Failed != 1 || stats.Requeued != 0 {
+		t.Fatalf("Failed = %d, Requeued = %d, want 1 and 0", stats.Failed, stats.Requeued)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.failed) != 1 {
+		t.Fatalf("fail reports = %d, want 1", len(platform.failed))
+	}
+
+	if got := platform.failed[0]; got.ErrorCode != "invalid_input" || got.ShouldRetry {
+		t.Fatalf("fail report = %+v, want invalid_input without retry", got)
+	}
+}
+
+func TestEngine_InvalidOutputFailsWithoutRetry(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{
+		output: &harnesstype.AgentJobOutput{SchemaVersion: 0, Success: true, Output: "stale runner"},
+	})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobFailed)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.completed) != 0 {
+		t.Fatalf("completed jobs = %v, want none", platform.completed)
+	}
+
+	if len(platform.failed) != 1 || platform.failed[0].ErrorCode != "invalid_output" || platform.failed[0].ShouldRetry {
+		t.Fatalf("fail reports = %+v, want one invalid_output without retry", platform.failed)
+	}
+}
+
+func TestEngine_StartTwice(t *testing.T) {
+	eng, _ := newTestEngine(t, &fakeExecutor{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = eng.Drain(context.Background()) })
+
+	if err := eng.Start(t.Context()); err == nil {
+		t.Fatal("second Start() error = nil, want ErrAlreadyStarted")
+	}
+}
+
+func TestEngine_DrainWithoutStart(t *testing.T) {
+	eng := New(&Options{Config: config.Load(), InitialStatus: StatusStarting})
+
+	eng.SetStatus(StatusReady)
+	eng.ReportError(SeverityError, "boom")
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	stats := eng.Stats()
+	if stats.Status != StatusReady || stats.LastError != "boom" {
+		t.Fatalf("stats = %+v, want Ready with last error", stats)
+	}
+
+	var got []EventType
+	for ev := range eng.Events() {
+		got = append(got, ev.Type)
+	}
+
+	if len(got) != 2 || got[0] != EventStatusChanged || got[1] != EventError {
+		t.Fatalf("events = %v, want [status_changed error]", got)
+	}
+}
+
+func TestNormalizeRefreshInterval(t *testing.T) {
+	if got := normalizeRefreshInterval(0); got != 300*time.Second {
+		t.Fatalf("normalize(0) = %s, want 300s", got)
+	}
+
+	if got := normalizeRefreshInterval(10); got != 60*time.Second {
+		t.Fatalf("normalize(10) = %s, want 60s", got)
+	}
+
+	if got := normalizeRefreshInterval(3600); got != 900*time.Second {
+		t.Fatalf("normalize(3600) = %s, want 900s", got)
+	}
+}
+
+func TestEngine_ReportErrorDeduplicates(t *testing.T) {
+	eng := New(&Options{Config: config.Load()})
+
+	eng.ReportError(SeverityWarning, "Heartbeat failed: timeout")
+	eng.ReportError(SeverityError, "Complete failed: 500")
+	eng.ReportError(SeverityWarning, "Heartbeat failed: timeout")
+
+	stats := eng.Stats()
+	if stats.LastError != "Heartbeat failed: timeout" || stats.LastErrorSeverity != SeverityWarning || stats.LastErrorCount != 2 {
+		t.Fatalf("stats = %+v, want repeated heartbeat warning as latest", stats)
+	}
+
+	if len(stats.Errors) != 2 {
+		t.Fatalf("len(Errors) = %d, want 2", len(stats.Errors))
+	}
+
+	if stats.Errors[0].Message != "Heartbeat failed: timeout" || stats.Errors[1].Severity != SeverityError {
+		t.Fatalf("Errors = %+v, want newest first", stats.Errors)
+	}
+}
+
+func TestErrorHistory_EvictsOldest(t *testing.T) {
+	var history errorHistory
+
+	now := time.Unix(0, 0)
+	for i := 0; i <= errorHistorySize; i++ {
+		history.record(SeverityError, fmt.Sprintf("error %d", i), now)
+	}
+
+	entries := history.snapshot()
+	if len(entries) != errorHistorySize {
+		t.Fatalf("len(entries) = %d, want %d", len(entries), errorHistorySize)
+	}
+
+	if last := entries[len(entries)-1].Message; last != "error 1" {
+		t.Fatalf("oldest entry = %q, want error 1", last)
+	}
+}
+
+func TestErrorHistory_FoldsRequestIDs(t *testing.T) {
+	var history errorHistory
+
+	now := time.Unix(0, 0)
+	history.recordRequest(SeverityWarning, "Claim failed: status 503 (request_id=req-1)", []string{"req-1"}, now)
+	history.recordRequest(SeverityWarning, "Claim failed: status 503 (request_id=req-2)", []string{"req-2"}, now.Add(time.Second))
+
+	entries := history.snapshot()
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d, want 1 folded entry", len(entries))
+	}
+
+	entry := entries[0]
+	if entry.Count != 2 || entry.RequestID != "req-2" || !strings.Contains(entry.Message, "req-2") {
+		t.Fatalf("entry = %+v, want count 2 carrying the latest request ID", entry)
+	}
+}
+
+func TestEngine_ResumeAbandonsJobWithLostLease(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{block: true})
+
+	platform.mu.Lock()
+	platform.leaseGone = true
+	platform.mu.Unlock()
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobStarted)
+
+	if stats := eng.Stats(); stats.JobID != "job-1" || stats.JobStartedAt.IsZero() {
+		t.Fatalf("stats job=%q started=%v, want job-1 with its start time", stats.JobID, stats.JobStartedAt)
+	}
+
+	eng.Resume(t.Context())
+	waitForEvent(t, eng.Events(), EventResumed)
+
+	ev := waitForEvent(t, eng.Events(), EventJobFailed)
+	if ev.JobID != "job-1" || ev.Message != errLeaseLost.Error() {
+		t.Fatalf("failed event = %+v, want job-1 lease lost", ev)
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if stats := eng.Stats(); stats.Failed != 1 || stats.Requeued != 1 || stats.LastErrorSeverity != SeverityError || !stats.JobStartedAt.IsZero() {
+		t.Fatalf("stats = %+v, want one failure with an error-severity lease report", stats)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.completed) != 0 || len(platform.failed) != 0 {
+		t.Fatalf("completed=%v failed=%v, want no result reports for an abandoned job", platform.completed, platform.failed)
+	}
+}
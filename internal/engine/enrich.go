@@ -0,0 +1,117 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// enrichmentTimeout bounds the context fetch so it cannot eat into the
+// job's execution time.
+const enrichmentTimeout = 10 * time.Second
+
+// enrichPrompt appends the context requested by the job's enrichment config
+// to its rendered instruction. Enrichment is best-effort: on failure the job
+// runs with the prompt it was claimed with.
+func (e *Engine) enrichPrompt(ctx context.Context, job *client.Job) {
+	if job.Execution == nil || job.Execution.Enrichment == nil {
+		return
+	}
+
+	opts := job.Execution.Enrichment.LinearIssue
+	if opts == nil || (!opts.Comments && !opts.Attachments) || !job.IsFromLinear() {
+		return
+	}
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "engine"))
+
+	fetchCtx, cancel := context.WithTimeout(ctx, enrichmentTimeout)
+	defer cancel()
+
+	issue, err := e.client.GetJobIssueContext(fetchCtx, job.ID, opts)
+	if err != nil {
+		logger.Warn("issue context enrichment failed",
+			slog.String("event.type", "job.enrich.error"),
+			slog.String("error", err.Error()),
+		)
+		e.reportAPIError(SeverityWarning, "Linear issue context unavailable", err)
+
+		return
+	}
+
+	section := formatIssueContext(issue)
+	if section == "" {
+		return
+	}
+
+	job.Execution.RenderedInstruction = strings.TrimRight(job.Execution.RenderedInstruction, "\n") + "\n\n" + section
+
+	logger.Info("prompt enriched with issue context",
+		slog.String("event.type", "job.enrich"),
+		slog.Int("issue.comments", len(issue.Comments)),
+		slog.Int("issue.attachments", len(issue.Attachments)),
+	)
+}
+
+// formatIssueContext renders issue comments and attachments as a Markdown
+// section, or "" when there is nothing to add.
+func formatIssueContext(issue *client.IssueContext) string {
+	if len(issue.Comments) == 0 && len(issue.Attachments) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString("## Linear issue context")
+
+	if issue.Identifier != "" {
+		fmt.Fprintf(&b, " (%s)", issue.Identifier)
+	}
+
+	b.WriteString("\n")
+
+	if issue.URL != "" {
+		fmt.Fprintf(&b, "\n%s\n", issue.URL)
+	}
+
+	if len(issue.Comments) > 0 {
+		b.WriteString("\n### Comments\n")
+
+		for _, comment := range issue.Comments {
+			author := comment.Author
+			if author == "" {
+				author = "unknown"
+			}
+
+			fmt.Fprintf(&b, "\n**%s**", author)
+
+			if !comment.CreatedAt.IsZero() {
+				fmt.Fprintf(&b, " (%s)", comment.CreatedAt.UTC().Format("2006-01-02 15:04 UTC"))
+			}
+
+			fmt.Fprintf(&b, ":\n%s\n", strings.TrimSpace(comment.Body))
+		}
+	}
+
+	if len(issue.Attachments) > 0 {
+		b.WriteString("\n### Attachments\n\n")
+
+		for _, attachment := range issue.Attachments {
+			title := attachment.Title
+			if title == "" {
+				title = attachment.URL
+			}
+
+			fmt.Fprintf(&b, "- [%s](%s)\n", title, attachment.URL)
+		}
+	}
+
+	return b.String()
+}
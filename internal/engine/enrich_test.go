@@ -0,0 +1,114 @@
+//go:build unix
+
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+)
+
+func linearJob() *client.Job {
+	return &client.Job{
+		ID:       "job-1",
+		CeSource: "https://linear.app/acme",
+		CeType:   "linear.issue.assigned",
+		Execution: &client.ExecutionConfig{
+			RenderedInstruction: "Fix the bug.\n",
+			Enrichment: &client.EnrichmentConfig{
+				LinearIssue: &client.LinearIssueEnrichment{Comments: true, Attachments: true},
+			},
+		},
+	}
+}
+
+func TestEnrichPrompt_AppendsIssueContext(t *testing.T) {
+	requireLocalListener(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/runner/jobs/job-1/issue-context" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+
+		if got := r.URL.Query().Get("include"); got != "comments,attachments" {
+			t.Errorf("include = %q, want comments,attachments", got)
+		}
+
+		_, _ = w.Write([]byte(`{
+			"identifier": "ENG-42",
+			"comments": [{"author": "sam", "body": "Repro on staging only.", "createdAt": "2026-03-01T10:30:00Z"}],
+			"attachments": [{"title": "Sentry event", "url": "https://sentry.example/1"}]
+		}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	eng := New(&Options{Client: client.New(srv.URL, "test-key"), Config: config.Load()})
+	job := linearJob()
+
+	eng.enrichPrompt(t.Context(), job)
+
+	got := job.GetRenderedInstruction()
+	for _, want := range []string{
+		"Fix the bug.\n\n## Linear issue context (ENG-42)",
+		"**sam** (2026-03-01 10:30 UTC):\nRepro on staging only.",
+		"- [Sentry event](https://sentry.example/1)",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("prompt missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestEnrichPrompt_SkipsNonLinearJobs(t *testing.T) {
+	eng := New(&Options{Client: client.New("http://127.0.0.1:0", "test-key"), Config: config.Load()})
+
+	job := linearJob()
+	job.CeSource = "https://github.com/acme/repo"
+	job.CeType = "com.github.issues.opened"
+
+	eng.enrichPrompt(t.Context(), job)
+
+	if got := job.GetRenderedInstruction(); got != "Fix the bug.\n" {
+		t.Fatalf("prompt = %q, want unchanged", got)
+	}
+}
+
+func TestEnrichPrompt_FailureKeepsPrompt(t *testing.T) {
+	requireLocalListener(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	eng := New(&Options{Client: client.New(srv.URL, "test-key"), Config: config.Load()})
+	job := linearJob()
+
+	eng.enrichPrompt(t.Context(), job)
+
+	if got := job.GetRenderedInstruction(); got != "Fix the bug.\n" {
+		t.Fatalf("prompt = %q, want unchanged", got)
+	}
+
+	if stats := eng.Stats(); stats.LastErrorSeverity != SeverityWarning {
+		t.Fatalf("LastErrorSeverity = %v, want warning", stats.LastErrorSeverity)
+	}
+}
+
+func TestFormatIssueContext_Empty(t *testing.T) {
+	if got := formatIssueContext(&client.IssueContext{Identifier: "ENG-1"}); got != "" {
+		t.Fatalf("formatIssueContext(empty) = %q, want empty", got)
+	}
+
+	got := formatIssueContext(&client.IssueContext{
+		Comments: []client.IssueComment{{Body: "  hi  ", CreatedAt: time.Time{}}},
+	})
+	if !strings.Contains(got, "**unknown**:\nhi\n") {
+		t.Fatalf("formatIssueContext() = %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"strings"
+	"time"
+)
+
+// Severity classifies a reported error.
+type Severity int
+
+// Severity values, in increasing order of importance.
+const (
+	// SeverityWarning marks transient problems the engine retries on its own,
+	// such as a missed heartbeat or a failed claim poll.
+	SeverityWarning Severity = iota
+	// SeverityError marks problems that lost or failed work.
+	SeverityError
+)
+
+// String returns the lower-case severity name.
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// errorHistorySize is the number of distinct errors retained.
+const errorHistorySize = 50
+
+// ErrorEntry is one distinct error in the engine's history. Repeats of the
+// same message and severity are folded into a single entry; API errors that
+// differ only in their request IDs count as repeats.
+type ErrorEntry struct {
+	Message   string
+	Severity  Severity
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+
+	// RequestID is the API request ID of the latest occurrence, if any.
+	RequestID string
+
+	// key identifies repeats: the message without request IDs.
+	key string
+}
+
+// errorHistory is a bounded, deduplicating error log ordered oldest first.
+type errorHistory struct {
+	entries []ErrorEntry
+}
+
+// record adds msg to the history. A repeat of an existing entry bumps its
+// count and moves it to the newest position instead of adding a new entry.
+func (h *errorHistory) record(severity Severity, msg string, now time.Time) {
+	h.recordRequest(severity, msg, nil, now)
+}
+
+// recordRequest is record for API errors: requestIDs are left out when
+// matching repeats, and the first one is kept as the entry's RequestID.
+func (h *errorHistory) recordRequest(severity Severity, msg string, requestIDs []string, now time.Time) {
+	key := msg
+	for _, id := range requestIDs {
+		key = strings.ReplaceAll(key, id, "")
+	}
+
+	requestID := ""
+	if len(requestIDs) > 0 {
+		requestID = requestIDs[0]
+	}
+
+	for i, entry := range h.entries {
+		if entry.key != key || entry.Severity != severity {
+			continue
+		}
+
+		entry.Count++
+		entry.LastSeen = now
+		entry.Message = msg
+		entry.RequestID = requestID
+
+		h.entries = append(h.entries[:i], h.entries[i+1:]...)
+		h.entries = append(h.entries, entry)
+
+		return
+	}
+
+	if len(h.entries) == errorHistorySize {
+		h.entries = h.entries[1:]
+	}
+
+	h.entries = append(h.entries, ErrorEntry{
+		Message:   msg,
+		Severity:  severity,
+		Count:     1,
+		FirstSeen: now,
+		LastSeen:  now,
+		RequestID: requestID,
+		key:       key,
+	})
+}
+
+// latest returns the most recently reported entry.
+func (h *errorHistory) latest() (ErrorEntry, bool) {
+	if len(h.entries) == 0 {
+		return ErrorEntry{}, false
+	}
+
+	return h.entries[len(h.entries)-1], true
+}
+
+// snapshot returns a copy of the history, newest first.
+func (h *errorHistory) snapshot() []ErrorEntry {
+	out := make([]ErrorEntry, len(h.entries))
+	for i, entry := range h.entries {
+		out[len(h.entries)-1-i] = entry
+	}
+
+	return out
+}
@@ -0,0 +1,64 @@
+// Package engine runs the worker job loop independently of any UI.
+//
+// An Engine claims jobs, keeps their leases alive with heartbeats, executes
+// them through harness executors, reports results, and refreshes runner
+// config. Hosts such as the watch TUI observe it through Stats and Events.
+package engine
+
+import "time"
+
+// EventType identifies the kind of Event emitted by an Engine.
+type EventType string
+
+// EventType values.
+const (
+	EventStatusChanged EventType = "status_changed"
+	EventJobStarted    EventType = "job_started"
+	EventJobCompleted  EventType = "job_completed"
+	EventJobFailed     EventType = "job_failed"
+	EventError         EventType = "error"
+
+	// EventResumed is emitted after the engine detects the process woke from
+	// a system sleep or was resumed with SIGCONT.
+	EventResumed EventType = "resumed"
+
+	// EventClaimingStopped is emitted when the engine stops claiming jobs on
+	// its own or through Stop. Message holds the StopReason.
+	EventClaimingStopped EventType = "claiming_stopped"
+)
+
+// StopReason explains why an engine stopped claiming jobs before the host
+// drained it.
+type StopReason string
+
+// StopReason values.
+const (
+	// StopJobLimit means Options.MaxJobs jobs have been processed.
+	StopJobLimit StopReason = "job limit reached"
+	// StopMaxDuration means Options.MaxDuration has passed since Start.
+	StopMaxDuration StopReason = "maximum duration reached"
+	// StopIdle means no job arrived within Options.IdleTimeout.
+	StopIdle StopReason = "idle timeout reached"
+	// StopRequested means Stop was called, such as by `mush worker stop`.
+	StopRequested StopReason = "stop requested"
+)
+
+// Event is a notification of an engine state change.
+type Event struct {
+	Type EventType
+
+	// Status is the engine status after the event.
+	Status Status
+
+	// JobID is set for job lifecycle events.
+	JobID string
+
+	// Message describes errors and failures.
+	Message string
+
+	Time time.Time
+}
+
+// eventBufferSize bounds queued events; slow consumers miss events rather
+// than stalling the job loop.
+const eventBufferSize = 64
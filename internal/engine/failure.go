@@ -0,0 +1,210 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// Failure is a classified job failure: what is reported to FailJob and
+// whether the platform should try the job again.
+type Failure struct {
+	// Code is the error code reported to the platform.
+	Code string
+
+	// Reason is the executor's classification before policy was applied.
+	Reason string
+
+	// Message is the human-readable error description.
+	Message string
+
+	// Retry asks the platform to requeue the job.
+	Retry bool
+
+	// Backoff is how long the platform should wait before the next attempt,
+	// or zero for no preference.
+	Backoff time.Duration
+
+	// ExitCode is the harness exit status, when it exited non-zero.
+	ExitCode int
+
+	// PartialOutput is the tail of the harness output before it failed, and
+	// LastActivity is when that output last changed.
+	PartialOutput string
+	LastActivity  time.Time
+}
+
+// Details returns the structured context sent alongside the error code.
+func (f Failure) Details() map[string]any {
+	details := map[string]any{}
+
+	if f.Reason != "" && f.Reason != f.Code {
+		details["reason"] = f.Reason
+	}
+
+	if f.ExitCode != 0 {
+		details["exitCode"] = f.ExitCode
+	}
+
+	if f.Retry && f.Backoff > 0 {
+		details["retryAfterSeconds"] = int(f.Backoff.Seconds())
+	}
+
+	if f.PartialOutput != "" {
+		details["partialOutput"] = f.PartialOutput
+	}
+
+	if !f.LastActivity.IsZero() {
+		details["lastActivityAt"] = f.LastActivity.UTC().Format(time.RFC3339)
+	}
+
+	if len(details) == 0 {
+		return nil
+	}
+
+	return details
+}
+
+// failureRule maps failures it matches to a code and retry decision.
+type failureRule struct {
+	code    string
+	retry   bool
+	backoff time.Duration
+	match   func(f *Failure, err error) bool
+}
+
+// failurePolicy is checked in order; the first matching rule wins. Failures
+// no rule matches keep the executor's own reason and retry decision.
+var failurePolicy = []failureRule{
+	// The job itself is at fault; another attempt would fail the same way.
+	{code: "prompt_error", match: reasonIs("prompt_error")},
+	{code: "invalid_output", match: reasonIs("invalid_output")},
+	{code: "budget_exceeded", match: reasonIs("budget_exceeded")},
+	{code: "max_turns_exceeded", match: reasonIs("max_turns_exceeded")},
+	{code: "secret_not_allowed", match: reasonIs("secret_not_allowed")},
+
+	// Retries get a scaled timeout, so they can go straight back on the queue.
+	{code: "timeout", retry: true, match: reasonIs("timeout")},
+
+	{code: "network_error", retry: true, backoff: 30 * time.Second, match: isNetworkFailure},
+	{code: "mcp_unavailable", retry: true, backoff: time.Minute, match: isMCPFailure},
+
+	// The shell could not find or run the harness binary. Another worker may
+	// have it installed, but this one will keep failing until someone fixes it.
+	{code: "harness_unavailable", retry: true, backoff: 5 * time.Minute, match: exitCodeIn(126, 127)},
+
+	// A secret manager that is locked or missing the secret; likewise until
+	// someone unlocks or fixes it.
+	{code: "secret_unavailable", retry: true, backoff: 5 * time.Minute, match: reasonIs("secret_unavailable")},
+
+	// Killed by a signal: an OOM kill or an operator, not the job's own doing.
+	{code: "harness_killed", retry: true, backoff: 30 * time.Second, match: exitCodeIn(-1, 137, 143)},
+
+	{code: "workspace_error", retry: true, backoff: 15 * time.Second, match: reasonIs("workspace_error")},
+	{code: "completion_report_failed", retry: true, backoff: 5 * time.Second, match: reasonIs("completion_report_failed")},
+}
+
+// classifyFailure applies failurePolicy to a failed job. reason is the
+// failure's default code; an *harnesstype.ExecError in err replaces it.
+func classifyFailure(reason string, err error) Failure {
+	f := Failure{Code: reason, Reason: reason, Retry: true}
+	if err != nil {
+		f.Message = err.Error()
+	}
+
+	var ee *harnesstype.ExecError
+	if errors.As(err, &ee) {
+		f.Code = ee.Reason
+		f.Reason = ee.Reason
+		f.Message = ee.Message
+		f.Retry = ee.Retry
+		f.ExitCode = ee.ExitCode
+		f.PartialOutput = ee.PartialOutput
+		f.LastActivity = ee.LastActivity
+	}
+
+	for _, rule := range failurePolicy {
+		if rule.match(&f, err) {
+			f.Code = rule.code
+			f.Retry = rule.retry
+			f.Backoff = rule.backoff
+
+			break
+		}
+	}
+
+	return f
+}
+
+func reasonIs(reason string) func(*Failure, error) bool {
+	return func(f *Failure, _ error) bool {
+		return f.Reason == reason
+	}
+}
+
+func exitCodeIn(codes ...int) func(*Failure, error) bool {
+	return func(f *Failure, _ error) bool {
+		for _, code := range codes {
+			if f.ExitCode == code {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// networkMessages are fragments harness CLIs and git print when the
+// network, rather than the job, is at fault.
+var networkMessages = []string{
+	"connection refused",
+	"connection reset",
+	"no such host",
+	"could not resolve host",
+	"network is unreachable",
+	"tls handshake timeout",
+	"i/o timeout",
+}
+
+func isNetworkFailure(f *Failure, err error) bool {
+	var requestErr *client.RequestError
+	if errors.As(err, &requestErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return containsAny(strings.ToLower(f.Message), networkMessages)
+}
+
+func isMCPFailure(f *Failure, _ error) bool {
+	if f.Reason == "mcp_error" {
+		return true
+	}
+
+	msg := strings.ToLower(f.Message)
+	if !strings.Contains(msg, "mcp") {
+		return false
+	}
+
+	return containsAny(msg, []string{"not found", "failed to start", "failed to connect", "unavailable", "missing"})
+}
+
+func containsAny(s string, fragments []string) bool {
+	for _, fragment := range fragments {
+		if strings.Contains(s, fragment) {
+			return true
+		}
+	}
+
+	return false
+}
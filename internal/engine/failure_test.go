@@ -0,0 +1,169 @@
+//go:build unix
+
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestClassifyFailure(t *testing.T) {
+	tests := []struct {
+		name        string
+		reason      string
+		err         error
+		wantCode    string
+		wantRetry   bool
+		wantBackoff time.Duration
+	}{
+		{
+			name:     "prompt error is permanent",
+			reason:   "execution_error",
+			err:      &harnesstype.ExecError{Reason: "prompt_error", Message: "missing execution config", Retry: true},
+			wantCode: "prompt_error",
+		},
+		{
+			name:     "budget exceeded is permanent",
+			reason:   "execution_error",
+			err:      &harnesstype.ExecError{Reason: "budget_exceeded", Message: "job spent $5.10, over its $5.00 budget", Retry: true},
+			wantCode: "budget_exceeded",
+		},
+		{
+			name:      "timeout retries immediately",
+			reason:    "execution_error",
+			err:       &harnesstype.ExecError{Reason: "timeout", Message: "codex execution timed out", Retry: true},
+			wantCode:  "timeout",
+			wantRetry: true,
+		},
+		{
+			name:        "command not found",
+			reason:      "execution_error",
+			err:         &harnesstype.ExecError{Reason: "execution_error", Message: "gemini exited with code 127", Retry: true, ExitCode: 127},
+			wantCode:    "harness_unavailable",
+			wantRetry:   true,
+			wantBackoff: 5 * time.Minute,
+		},
+		{
+			name:        "killed by signal",
+			reason:      "execution_error",
+			err:         &harnesstype.ExecError{Reason: "codex_error", Message: "codex exited with code 137", Retry: true, ExitCode: 137},
+			wantCode:    "harness_killed",
+			wantRetry:   true,
+			wantBackoff: 30 * time.Second,
+		},
+		{
+			name:      "other exit codes keep the executor's decision",
+			reason:    "execution_error",
+			err:       &harnesstype.ExecError{Reason: "codex_error", Message: "codex exited with code 2", Retry: true, ExitCode: 2},
+			wantCode:  "codex_error",
+			wantRetry: true,
+		},
+		{
+			name:        "network failure in harness output",
+			reason:      "execution_error",
+			err:         &harnesstype.ExecError{Reason: "execution_error", Message: "opencode exited with code 1: dial tcp: connection refused", Retry: true, ExitCode: 1},
+			wantCode:    "network_error",
+			wantRetry:   true,
+			wantBackoff: 30 * time.Second,
+		},
+		{
+			name:        "transport error reporting completion",
+			reason:      "completion_report_failed",
+			err:         fmt.Errorf("failed to complete job: %w", &client.RequestError{Operation: "POST", Cause: errors.New("EOF")}),
+			wantCode:    "network_error",
+			wantRetry:   true,
+			wantBackoff: 30 * time.Second,
+		},
+		{
+			name:        "missing MCP server",
+			reason:      "execution_error",
+			err:         &harnesstype.ExecError{Reason: "execution_error", Message: `MCP server "linear" failed to start`, Retry: true},
+			wantCode:    "mcp_unavailable",
+			wantRetry:   true,
+			wantBackoff: time.Minute,
+		},
+		{
+			name:        "workspace checkout",
+			reason:      "workspace_error",
+			err:         errors.New("prepare workspace: ref not found"),
+			wantCode:    "workspace_error",
+			wantRetry:   true,
+			wantBackoff: 15 * time.Second,
+		},
+		{
+			name:     "secret reference not allowed",
+			reason:   "secret_not_allowed",
+			err:      errors.New("resolve TOKEN: secret reference not allowed: env://TOKEN"),
+			wantCode: "secret_not_allowed",
+		},
+		{
+			name:        "secret manager locked",
+			reason:      "secret_unavailable",
+			err:         errors.New("resolve TOKEN: op://Eng/GitHub/token: op: not signed in"),
+			wantCode:    "secret_unavailable",
+			wantRetry:   true,
+			wantBackoff: 5 * time.Minute,
+		},
+		{
+			name:     "invalid output",
+			reason:   "invalid_output",
+			err:      errors.New("unsupported schema version 0"),
+			wantCode: "invalid_output",
+		},
+		{
+			name:     "unknown reason keeps the executor's decision",
+			reason:   "execution_error",
+			err:      &harnesstype.ExecError{Reason: "invalid_input", Message: "bad prompt"},
+			wantCode: "invalid_input",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyFailure(tt.reason, tt.err)
+
+			if got.Code != tt.wantCode || got.Retry != tt.wantRetry || got.Backoff != tt.wantBackoff {
+				t.Fatalf("classifyFailure() = %s retry=%v backoff=%v, want %s retry=%v backoff=%v",
+					got.Code, got.Retry, got.Backoff, tt.wantCode, tt.wantRetry, tt.wantBackoff)
+			}
+		})
+	}
+}
+
+func TestFailureDetails(t *testing.T) {
+	failure := classifyFailure("execution_error", &harnesstype.ExecError{
+		Reason:   "execution_error",
+		Message:  "copilot exited with code 127",
+		Retry:    true,
+		ExitCode: 127,
+	})
+
+	details := failure.Details()
+	if details["reason"] != "execution_error" || details["exitCode"] != 127 || details["retryAfterSeconds"] != 300 {
+		t.Fatalf("Details() = %v", details)
+	}
+
+	if got := classifyFailure("invalid_output", errors.New("bad")).Details(); got != nil {
+		t.Fatalf("Details() = %v, want nil for a plain permanent failure", got)
+	}
+}
+
+func TestFailureDetails_PartialOutput(t *testing.T) {
+	lastActivity := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	execErr := (&harnesstype.ExecError{Reason: "timeout", Message: "codex execution timed out", Retry: true}).
+		WithPartialOutput("\x1b[32mediting main.go\x1b[0m\n", lastActivity)
+
+	details := classifyFailure("execution_error", execErr).Details()
+	if details["partialOutput"] != "editing main.go" {
+		t.Fatalf("partialOutput = %q, want ANSI-stripped output", details["partialOutput"])
+	}
+
+	if details["lastActivityAt"] != "2026-05-06T07:08:09Z" {
+		t.Fatalf("lastActivityAt = %v", details["lastActivityAt"])
+	}
+}
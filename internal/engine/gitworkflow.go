@@ -0,0 +1,304 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// gitWorkflowTimeout bounds each git or gh command the workflow runs.
+// Pushes and pull requests go over the network, so it is longer than
+// gitProbeTimeout.
+const gitWorkflowTimeout = 2 * time.Minute
+
+// gitWorkflowRemote is the remote the workflow pushes to.
+const gitWorkflowRemote = "origin"
+
+// gitWorkflowModes lists the workflow modes from least to most done.
+var gitWorkflowModes = []string{
+	config.GitWorkflowOff,
+	config.GitWorkflowCommit,
+	config.GitWorkflowPush,
+	config.GitWorkflowPR,
+}
+
+// gitWorkflowResult describes what the workflow did with a job's changes.
+type gitWorkflowResult struct {
+	branch         string
+	commit         string
+	pushed         bool
+	pullRequestURL string
+}
+
+// gitStart is the state of a job's working directory before the job ran.
+type gitStart struct {
+	// head is the commit checked out, or "" outside a repository with a commit.
+	head string
+	// branch is the branch checked out, or "" when HEAD is detached.
+	branch string
+	// dirty reports uncommitted changes, which the workflow must not commit.
+	dirty bool
+}
+
+// recordGitStart records dir's git state before a job runs in it.
+func recordGitStart(ctx context.Context, dir string) gitStart {
+	start := gitStart{head: gitHead(ctx, dir)}
+	if start.head == "" {
+		return start
+	}
+
+	if branch, err := runGit(ctx, dir, "symbolic-ref", "-q", "--short", "HEAD"); err == nil {
+		start.branch = branch
+	}
+
+	status, err := runGit(ctx, dir, "status", "--porcelain", "--untracked-files=normal")
+	start.dirty = err != nil || status != ""
+
+	return start
+}
+
+// gitWorkflow returns the workflow for job: the worker's setting for this
+// queue, lowered to the job's own mode when it asks for less.
+func (e *Engine) gitWorkflow(job *client.Job) config.GitWorkflow {
+	workflow := e.config().GitWorkflow(e.queueSlug, e.queueID)
+
+	if job.Execution == nil || job.Execution.Git == nil {
+		return workflow
+	}
+
+	requested := slices.Index(gitWorkflowModes, strings.ToLower(strings.TrimSpace(job.Execution.Git.Mode)))
+	if requested >= 0 && requested < slices.Index(gitWorkflowModes, workflow.Mode) {
+		workflow.Mode = gitWorkflowModes[requested]
+	}
+
+	return workflow
+}
+
+// runGitWorkflow commits a completed job's changes to a branch named from
+// the job ID and, as the workflow allows, pushes it and opens a pull
+// request. Failures are reported as warnings and never affect the job; the
+// result still names a branch the changes were committed to before one.
+func (e *Engine) runGitWorkflow(ctx context.Context, job *client.Job, start gitStart, response string) gitWorkflowResult {
+	workflow := e.gitWorkflow(job)
+	if workflow.Mode == config.GitWorkflowOff {
+		return gitWorkflowResult{}
+	}
+
+	logger := observability.FromContext(ctx)
+
+	result, err := applyGitWorkflow(ctx, job, workflow, start, response)
+	if err != nil {
+		logger.Warn("git workflow failed",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.git.error"),
+			slog.String("git.workflow", workflow.Mode),
+			slog.String("error", err.Error()),
+		)
+		e.ReportError(SeverityWarning, "Git workflow failed: "+err.Error())
+
+		return result
+	}
+
+	if result.branch == "" {
+		return result
+	}
+
+	attrs := []any{
+		slog.String("component", "engine"),
+		slog.String("event.type", "job.git.complete"),
+		slog.String("git.workflow", workflow.Mode),
+		slog.String("git.branch", result.branch),
+		slog.Bool("git.pushed", result.pushed),
+	}
+	if result.pullRequestURL != "" {
+		attrs = append(attrs, slog.String("git.pull_request_url", result.pullRequestURL))
+	}
+
+	logger.Info("job changes committed", attrs...)
+
+	return result
+}
+
+// applyGitWorkflow runs the workflow's steps in the job's working directory.
+// The result has no branch when the job changed nothing. It refuses a
+// directory that had uncommitted changes before the job, which it can't
+// tell apart from the job's, and it leaves the directory on the branch and
+// commit the job started from, so the next job doesn't build on this one.
+func applyGitWorkflow(ctx context.Context, job *client.Job, workflow config.GitWorkflow, start gitStart, response string) (result gitWorkflowResult, err error) {
+	if start.head == "" {
+		return result, errors.New("working directory is not a git repository with a commit")
+	}
+
+	if start.dirty {
+		return result, errors.New("working directory had uncommitted changes before the job ran; commit or stash them so only the job's changes are committed")
+	}
+
+	dir := jobWorkDir(job)
+
+	status, err := runWorkflowCommand(ctx, dir, "git", "status", "--porcelain", "--untracked-files=normal")
+	if err != nil {
+		return result, err
+	}
+
+	if status == "" && gitHead(ctx, dir) == start.head {
+		return result, nil
+	}
+
+	branch := workflow.BranchPrefix + job.ID
+
+	if _, err := runWorkflowCommand(ctx, dir, "git", "checkout", "-q", "-B", branch); err != nil {
+		return result, err
+	}
+
+	defer func() {
+		if restoreErr := restoreGitStart(ctx, dir, start); restoreErr != nil && err == nil {
+			err = restoreErr
+		}
+	}()
+
+	title := gitWorkflowTitle(job)
+
+	if status != "" {
+		if _, err := runWorkflowCommand(ctx, dir, "git", "add", "-A"); err != nil {
+			return result, err
+		}
+
+		message := fmt.Sprintf("%s\n\nMusher-Job: %s\n", title, job.ID)
+		if _, err := runWorkflowCommand(ctx, dir, "git", "commit", "-q", "-m", message); err != nil {
+			return result, err
+		}
+	}
+
+	result.branch = branch
+	result.commit = gitHead(ctx, dir)
+
+	if workflow.Mode == config.GitWorkflowCommit {
+		return result, nil
+	}
+
+	// Push to the remote's URL rather than its name: the warm cache's mirror
+	// clones refuse pushes with a refspec to a mirror remote.
+	remoteURL, err := runWorkflowCommand(ctx, dir, "git", "remote", "get-url", "--push", gitWorkflowRemote)
+	if err != nil {
+		return result, err
+	}
+
+	// The branch belongs to the job, so a retry replaces what an earlier
+	// attempt pushed.
+	refspec := "refs/heads/" + branch + ":refs/heads/" + branch
+	if _, err := runWorkflowCommand(ctx, dir, "git", "push", "-q", "--force", remoteURL, refspec); err != nil {
+		return result, err
+	}
+
+	result.pushed = true
+
+	// An agent that opened its own pull request doesn't need another.
+	if workflow.Mode != config.GitWorkflowPR || harnesstype.FindPullRequestURL(response) != "" {
+		return result, nil
+	}
+
+	args := []string{"pr", "create", "--head", branch, "--title", title, "--body", gitWorkflowBody(job, response)}
+
+	if git := job.Execution.Git; git != nil {
+		if git.Base != "" {
+			args = append(args, "--base", git.Base)
+		}
+
+		if git.Draft {
+			args = append(args, "--draft")
+		}
+	}
+
+	out, err := runWorkflowCommand(ctx, dir, "gh", args...)
+	if err != nil {
+		return result, err
+	}
+
+	// gh prints the new pull request's URL last.
+	lines := strings.Split(out, "\n")
+	if url := strings.TrimSpace(lines[len(lines)-1]); strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://") {
+		result.pullRequestURL = url
+	}
+
+	return result, nil
+}
+
+// restoreGitStart checks out the branch and commit a job started from. The
+// job's commits are already on its own branch, so a branch the agent moved
+// is put back where it was.
+func restoreGitStart(ctx context.Context, dir string, start gitStart) error {
+	if start.branch == "" {
+		_, err := runWorkflowCommand(ctx, dir, "git", "checkout", "-q", "--detach", start.head)
+		return err
+	}
+
+	_, err := runWorkflowCommand(ctx, dir, "git", "checkout", "-q", "-B", start.branch, start.head)
+
+	return err
+}
+
+// gitWorkflowTitle returns the commit subject and pull request title for a
+// job's changes.
+func gitWorkflowTitle(job *client.Job) string {
+	if name := job.GetDisplayName(); name != "Job" {
+		return strings.Join(strings.Fields(name), " ")
+	}
+
+	return "Musher job " + job.ID
+}
+
+// gitWorkflowBody returns the pull request description: the agent's summary
+// and the job it came from.
+func gitWorkflowBody(job *client.Job, response string) string {
+	body := fmt.Sprintf("Opened by Musher for job `%s`.", job.ID)
+
+	if excerpt := harnesstype.SummaryExcerpt(response); excerpt != "" {
+		body = excerpt + "\n\n---\n\n" + body
+	}
+
+	return body
+}
+
+// runWorkflowCommand runs a git or gh command in dir with prompts disabled,
+// returning trimmed stdout. Errors carry the command's stderr.
+func runWorkflowCommand(ctx context.Context, dir, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitWorkflowTimeout)
+	defer cancel()
+
+	cmd, err := executil.CommandContext(ctx, name, args...)
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", name, args[0], err)
+	}
+
+	cmd.Dir = dir
+	// A job must never hang waiting for credentials on the operator's terminal.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0", "GH_PROMPT_DISABLED=1")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s %s: %s: %w", name, args[0], msg, err)
+		}
+
+		return "", fmt.Errorf("%s %s: %w", name, args[0], err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
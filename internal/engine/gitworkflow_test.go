@@ -0,0 +1,200 @@
+//go:build unix
+
+package engine
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestEngine_GitWorkflowMode(t *testing.T) {
+	t.Setenv("MUSHER_WORKER_GIT_WORKFLOW", "push")
+
+	eng, _ := newTestEngine(t, &fakeExecutor{})
+
+	tests := []struct {
+		git  *client.GitWorkflowConfig
+		want string
+	}{
+		{git: nil, want: config.GitWorkflowPush},
+		{git: &client.GitWorkflowConfig{Mode: "commit"}, want: config.GitWorkflowCommit},
+		{git: &client.GitWorkflowConfig{Mode: "pr"}, want: config.GitWorkflowPush},
+		{git: &client.GitWorkflowConfig{Mode: "sometimes"}, want: config.GitWorkflowPush},
+	}
+
+	for _, tt := range tests {
+		job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{Git: tt.git}}
+		if got := eng.gitWorkflow(job).Mode; got != tt.want {
+			t.Errorf("gitWorkflow(%+v).Mode = %q, want %q", tt.git, got, tt.want)
+		}
+	}
+}
+
+func TestEngine_GitWorkflowOpensPullRequest(t *testing.T) {
+	remote := t.TempDir()
+	dir := t.TempDir()
+	gitInit(t, dir)
+	runTestGit(t, remote, "init", "-q", "--bare")
+	runTestGit(t, dir, "remote", "add", "origin", remote)
+
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "t")
+	}
+
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "t@example.com")
+	}
+
+	// A fake gh records its arguments and prints a pull request URL.
+	bin := t.TempDir()
+	ghArgs := filepath.Join(bin, "gh-args")
+	script := "#!/bin/sh\nprintf '%s\\n' \"$@\" > " + ghArgs + "\necho https://github.com/acme/repo/pull/7\n"
+	writeTestFile(t, filepath.Join(bin, "gh"), script)
+
+	if err := os.Chmod(filepath.Join(bin, "gh"), 0o700); err != nil {
+		t.Fatalf("Chmod() error = %v", err)
+	}
+
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("MUSHER_WORKER_GIT_WORKFLOW", "pr")
+
+	executor := &fakeExecutor{run: func(*client.Job) {
+		writeTestFile(t, filepath.Join(dir, "fix.txt"), "fixed\n")
+	}}
+
+	eng, platform := newTestEngine(t, executor)
+	platform.claimBody = `{"job":{"id":"job-1","inputData":{"name":"Fix the build"}},` +
+		`"execution":{"harnessType":"test","workingDirectory":"` + dir + `","git":{"draft":true}}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if got := runTestGit(t, remote, "log", "-1", "--format=%s%n%b", "mush/job-1"); !strings.HasPrefix(got, "Fix the build\n") || !strings.Contains(got, "Musher-Job: job-1") {
+		t.Fatalf("pushed commit message = %q, want the job's name and trailer", got)
+	}
+
+	if got := runTestGit(t, dir, "rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Fatalf("checked out %q after the workflow, want main restored", got)
+	}
+
+	if got := runTestGit(t, dir, "status", "--porcelain"); got != "" {
+		t.Fatalf("status after the workflow = %q, want the job's changes moved to its branch", got)
+	}
+
+	args, err := os.ReadFile(ghArgs)
+	if err != nil {
+		t.Fatalf("gh was not run: %v", err)
+	}
+
+	for _, want := range []string{"pr\ncreate\n", "--head\nmush/job-1\n", "--title\nFix the build\n", "--draft\n"} {
+		if !strings.Contains(string(args), want) {
+			t.Fatalf("gh args = %q, want %q", args, want)
+		}
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	meta, _ := platform.outputs[0]["resultMetadata"].(map[string]any)
+	if meta["branch"] != "mush/job-1" || meta["pullRequestUrl"] != "https://github.com/acme/repo/pull/7" {
+		t.Fatalf("resultMetadata = %v, want the branch and pull request", meta)
+	}
+}
+
+func TestEngine_GitWorkflowSkippedForInvalidOutput(t *testing.T) {
+	dir := t.TempDir()
+	gitInit(t, dir)
+
+	for _, key := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(key, "t")
+	}
+
+	for _, key := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(key, "t@example.com")
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("MUSHER_WORKER_GIT_WORKFLOW", "commit")
+
+	executor := &fakeExecutor{
+		output: &harnesstype.AgentJobOutput{Output: "done"},
+		run: func(*client.Job) {
+			writeTestFile(t, filepath.Join(dir, "fix.txt"), "fixed\n")
+		},
+	}
+
+	eng, platform := newTestEngine(t, executor)
+	platform.claimBody = `{"job":{"id":"job-1"},` +
+		`"execution":{"harnessType":"test","workingDirectory":"` + dir + `"}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobFailed)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if got := runTestGit(t, dir, "branch", "--list", "mush/*"); got != "" {
+		t.Fatalf("branches after a failed job = %q, want none", got)
+	}
+
+	if got := runTestGit(t, dir, "status", "--porcelain"); got != "?? fix.txt" {
+		t.Fatalf("status after a failed job = %q, want the changes left in place", got)
+	}
+}
+
+func TestApplyGitWorkflow_RefusesDirtyStart(t *testing.T) {
+	dir := t.TempDir()
+	gitInit(t, dir)
+
+	// Present before the job ran, so it is not the job's to commit.
+	writeTestFile(t, filepath.Join(dir, ".env"), "TOKEN=secret\n")
+
+	start := recordGitStart(t.Context(), dir)
+	if !start.dirty || start.branch != "main" {
+		t.Fatalf("recordGitStart() = %+v, want a dirty start on main", start)
+	}
+
+	writeTestFile(t, filepath.Join(dir, "fix.txt"), "fixed\n")
+
+	job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{WorkingDirectory: dir}}
+	workflow := config.GitWorkflow{Mode: config.GitWorkflowCommit, BranchPrefix: "mush/"}
+
+	result, err := applyGitWorkflow(t.Context(), job, workflow, start, "")
+	if err == nil || !strings.Contains(err.Error(), "uncommitted changes before the job") {
+		t.Fatalf("applyGitWorkflow() = %+v, %v; want it to refuse the dirty directory", result, err)
+	}
+
+	if got := runTestGit(t, dir, "branch", "--list", "mush/job-1"); got != "" {
+		t.Fatalf("job branch created for a dirty directory: %q", got)
+	}
+}
+
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
@@ -0,0 +1,105 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+)
+
+// worktreeGuard returns the configured guard for this engine's queue.
+func (e *Engine) worktreeGuard() config.WorktreeGuard {
+	return e.config().WorktreeGuard(e.queueSlug, e.queueID)
+}
+
+// guardedWorkDir returns the directory a job would modify in place, or ""
+// when the job gets a fresh worktree and can't touch the developer's work.
+func guardedWorkDir(job *client.Job) string {
+	if job.Execution != nil && job.Execution.Repository != nil {
+		return ""
+	}
+
+	return jobWorkDir(job)
+}
+
+// unsafeWorktreeReason reports why an agent must not modify dir: it has
+// uncommitted changes or is on a protected branch. Directories outside a git
+// repository have nothing to protect and return "".
+func unsafeWorktreeReason(ctx context.Context, dir string, protectedBranches []string) string {
+	if _, err := runGit(ctx, dir, "rev-parse", "--git-dir"); err != nil {
+		return ""
+	}
+
+	if len(protectedBranches) > 0 {
+		if branch, err := runGit(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && slices.Contains(protectedBranches, branch) {
+			return fmt.Sprintf("%s is on protected branch %s", dir, branch)
+		}
+	}
+
+	status, err := runGit(ctx, dir, "status", "--porcelain", "--untracked-files=normal")
+	if err != nil {
+		return fmt.Sprintf("cannot read git status of %s: %v", dir, err)
+	}
+
+	if status != "" {
+		return fmt.Sprintf("%s has uncommitted changes", dir)
+	}
+
+	return ""
+}
+
+// claimsPaused reports whether claims are held, for a platform maintenance
+// window or because the pause guard finds the worker's directory unsafe,
+// reporting each new reason once.
+func (e *Engine) claimsPaused(ctx context.Context, guard config.WorktreeGuard) bool {
+	reason := e.maintenanceReason()
+	if reason == "" && guard.Mode == config.WorktreeGuardPause {
+		reason = unsafeWorktreeReason(ctx, ".", guard.ProtectedBranches)
+	}
+
+	e.statusMu.Lock()
+	changed := reason != e.pausedReason
+	e.pausedReason = reason
+	e.statusMu.Unlock()
+
+	if !changed {
+		return reason != ""
+	}
+
+	if reason == "" {
+		e.setStatus(StatusConnected)
+		return false
+	}
+
+	e.setStatus(StatusPaused)
+	e.ReportError(SeverityWarning, fmt.Sprintf("Claims paused: %s", reason))
+
+	return true
+}
+
+// refuseUnsafeJob releases a claimed job that would run in an unsafe
+// directory and reports whether it did.
+func (e *Engine) refuseUnsafeJob(ctx context.Context, job *client.Job, guard config.WorktreeGuard) bool {
+	if guard.Mode == config.WorktreeGuardOff {
+		return false
+	}
+
+	dir := guardedWorkDir(job)
+	if dir == "" {
+		return false
+	}
+
+	reason := unsafeWorktreeReason(ctx, dir, guard.ProtectedBranches)
+	if reason == "" {
+		return false
+	}
+
+	e.ReportError(SeverityWarning, fmt.Sprintf("Released job %s: %s", job.ID, reason))
+	e.releaseJob(ctx, job)
+
+	return true
+}
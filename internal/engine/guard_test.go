@@ -0,0 +1,52 @@
+//go:build unix
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestUnsafeWorktreeReason(t *testing.T) {
+	outside := t.TempDir()
+	if got := unsafeWorktreeReason(t.Context(), outside, []string{"main"}); got != "" {
+		t.Errorf("directory outside a repository: reason = %q, want none", got)
+	}
+
+	dir := t.TempDir()
+	gitInit(t, dir)
+
+	if got := unsafeWorktreeReason(t.Context(), dir, nil); got != "" {
+		t.Errorf("clean repository: reason = %q, want none", got)
+	}
+
+	if got := unsafeWorktreeReason(t.Context(), dir, []string{"release", "main"}); !strings.Contains(got, "protected branch main") {
+		t.Errorf("protected branch: reason = %q", got)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("wip\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := unsafeWorktreeReason(t.Context(), dir, nil); !strings.Contains(got, "uncommitted changes") {
+		t.Errorf("untracked file: reason = %q", got)
+	}
+}
+
+func TestGuardedWorkDir(t *testing.T) {
+	inPlace := &client.Job{Execution: &client.ExecutionConfig{WorkingDirectory: "/src/app"}}
+	if got := guardedWorkDir(inPlace); got != "/src/app" {
+		t.Errorf("in-place job: guardedWorkDir() = %q, want /src/app", got)
+	}
+
+	isolated := &client.Job{Execution: &client.ExecutionConfig{
+		Repository: &client.RepositoryConfig{URL: "https://example.com/app.git"},
+	}}
+	if got := guardedWorkDir(isolated); got != "" {
+		t.Errorf("job with a repository: guardedWorkDir() = %q, want none", got)
+	}
+}
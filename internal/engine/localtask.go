@@ -0,0 +1,94 @@
+//go:build unix || windows
+
+package engine
+
+import "github.com/musher-dev/mush/internal/client"
+
+// BeginLocalTask holds claims while the user works in the harness session
+// between jobs, so a claimed job is not typed into the middle of their
+// prompt. It reports false when a job is already running. Every call that
+// reports true must be paired with EndLocalTask.
+func (e *Engine) BeginLocalTask() bool {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if e.currentJob != nil {
+		return false
+	}
+
+	e.localTask = true
+	e.showLocalTaskLocked()
+
+	return true
+}
+
+// EndLocalTask releases the claim hold taken by BeginLocalTask and restores
+// the status it replaced.
+func (e *Engine) EndLocalTask() {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if !e.localTask {
+		return
+	}
+
+	e.localTask = false
+
+	e.statusMu.Lock()
+	restore := e.status == StatusLocalTask
+	if restore {
+		e.status = e.statusBeforeLocalTask
+	}
+	status := e.status
+	e.statusMu.Unlock()
+
+	if restore {
+		e.emit(Event{Type: EventStatusChanged, Status: status})
+	}
+}
+
+// localTaskHoldsClaims reports whether a local task holds claims. The claim
+// loop calls it before each claim, so the status keeps showing the local
+// task over the loop's own.
+func (e *Engine) localTaskHoldsClaims() bool {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if e.localTask {
+		e.showLocalTaskLocked()
+	}
+
+	return e.localTask
+}
+
+// showLocalTaskLocked switches the status to StatusLocalTask, remembering
+// the one to restore. The caller must hold jobMu so EndLocalTask can't
+// restore in between.
+func (e *Engine) showLocalTaskLocked() {
+	e.statusMu.Lock()
+	changed := e.status != StatusLocalTask
+	if changed {
+		e.statusBeforeLocalTask = e.status
+		e.status = StatusLocalTask
+	}
+	e.statusMu.Unlock()
+
+	if changed {
+		e.emit(Event{Type: EventStatusChanged, Status: StatusLocalTask})
+	}
+}
+
+// reserveJob makes job the current job unless a local task began while it
+// was being claimed, in which case the caller must hand it back.
+func (e *Engine) reserveJob(job *client.Job) bool {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	if e.localTask {
+		return false
+	}
+
+	e.currentJob = job
+
+	return true
+}
@@ -0,0 +1,76 @@
+//go:build unix
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestEngine_LocalTaskHoldsClaims(t *testing.T) {
+	t.Setenv("MUSHER_WORKER_POLL_INTERVAL", "1s")
+
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+
+	if !eng.BeginLocalTask() {
+		t.Fatal("BeginLocalTask() = false with no job running")
+	}
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	time.Sleep(1500 * time.Millisecond)
+
+	platform.mu.Lock()
+	claimed := platform.claimed
+	platform.mu.Unlock()
+
+	if claimed {
+		t.Fatal("engine claimed a job during a local task")
+	}
+
+	if got := eng.Stats().Status; got != StatusLocalTask {
+		t.Fatalf("Status = %s, want %s", got, StatusLocalTask)
+	}
+
+	eng.EndLocalTask()
+
+	ev := waitForEvent(t, eng.Events(), EventJobCompleted)
+	if ev.JobID != "job-1" {
+		t.Fatalf("completed JobID = %q, want job-1", ev.JobID)
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
+
+func TestEngine_LocalTaskAndJobExcludeEachOther(t *testing.T) {
+	eng := New(&Options{InitialStatus: StatusConnected})
+	job := &client.Job{ID: "job-1"}
+
+	if !eng.BeginLocalTask() {
+		t.Fatal("BeginLocalTask() = false with no job running")
+	}
+
+	if eng.reserveJob(job) {
+		t.Fatal("reserveJob() = true during a local task")
+	}
+
+	eng.EndLocalTask()
+
+	if got := eng.Stats().Status; got != StatusConnected {
+		t.Fatalf("Status after EndLocalTask = %s, want %s", got, StatusConnected)
+	}
+
+	if !eng.reserveJob(job) {
+		t.Fatal("reserveJob() = false after the local task ended")
+	}
+
+	if eng.BeginLocalTask() {
+		t.Fatal("BeginLocalTask() = true while a job is running")
+	}
+}
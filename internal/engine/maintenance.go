@@ -0,0 +1,71 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// setMaintenance records the maintenance window reported by a worker
+// heartbeat; nil means none. The claim loop pauses and resumes on its next
+// pass.
+func (e *Engine) setMaintenance(ctx context.Context, window *client.MaintenanceWindow) {
+	e.statusMu.Lock()
+	wasActive := e.maintenance != nil
+	e.maintenance = window
+	e.statusMu.Unlock()
+
+	if wasActive == (window != nil) {
+		return
+	}
+
+	logger := observability.FromContext(ctx)
+
+	if window == nil {
+		logger.Info("maintenance window lifted",
+			slog.String("component", "engine"),
+			slog.String("event.type", "worker.maintenance.end"),
+		)
+
+		return
+	}
+
+	attrs := []any{
+		slog.String("component", "engine"),
+		slog.String("event.type", "worker.maintenance.start"),
+		slog.String("maintenance.message", window.Message),
+	}
+	if window.EndsAt != nil {
+		attrs = append(attrs, slog.Time("maintenance.ends_at", *window.EndsAt))
+	}
+
+	logger.Info("maintenance window started", attrs...)
+}
+
+// maintenanceReason describes the current maintenance window for the
+// paused status, or returns "" when there is none.
+func (e *Engine) maintenanceReason() string {
+	e.statusMu.Lock()
+	window := e.maintenance
+	e.statusMu.Unlock()
+
+	if window == nil {
+		return ""
+	}
+
+	reason := "platform maintenance"
+	if window.EndsAt != nil {
+		reason += " until " + window.EndsAt.Local().Format(time.Kitchen)
+	}
+
+	if window.Message != "" {
+		reason += ": " + window.Message
+	}
+
+	return reason
+}
@@ -0,0 +1,49 @@
+//go:build unix
+
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+)
+
+func TestClaimsPausedForMaintenance(t *testing.T) {
+	eng, _ := newTestEngine(t, &fakeExecutor{})
+	eng.setStatus(StatusConnected)
+
+	guard := config.WorktreeGuard{Mode: config.WorktreeGuardOff}
+
+	if eng.claimsPaused(t.Context(), guard) {
+		t.Fatal("claimsPaused() = true before any maintenance window")
+	}
+
+	endsAt := time.Now().Add(time.Hour)
+	eng.setMaintenance(t.Context(), &client.MaintenanceWindow{Message: "database upgrade", EndsAt: &endsAt})
+
+	if !eng.claimsPaused(t.Context(), guard) {
+		t.Fatal("claimsPaused() = false during a maintenance window")
+	}
+
+	stats := eng.Stats()
+	if stats.Status != StatusPaused {
+		t.Fatalf("Status = %s, want %s", stats.Status, StatusPaused)
+	}
+
+	if !strings.Contains(stats.LastError, "platform maintenance until") || !strings.HasSuffix(stats.LastError, ": database upgrade") {
+		t.Fatalf("LastError = %q, want the maintenance window", stats.LastError)
+	}
+
+	eng.setMaintenance(t.Context(), nil)
+
+	if eng.claimsPaused(t.Context(), guard) {
+		t.Fatal("claimsPaused() = true after the maintenance window lifted")
+	}
+
+	if got := eng.Stats().Status; got != StatusConnected {
+		t.Fatalf("Status = %s, want %s", got, StatusConnected)
+	}
+}
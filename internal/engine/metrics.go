@@ -0,0 +1,42 @@
+//go:build unix || windows
+
+package engine
+
+import "github.com/musher-dev/mush/internal/metrics"
+
+// engineMetrics are the engine's counters and histograms, served by the host
+// on its metrics endpoint.
+type engineMetrics struct {
+	registry *metrics.Registry
+
+	jobsClaimed       *metrics.Counter
+	jobsCompleted     *metrics.Counter
+	jobsFailed        *metrics.Counter
+	heartbeatFailures *metrics.Counter
+	claimDuration     *metrics.Histogram
+}
+
+func newEngineMetrics() *engineMetrics {
+	reg := metrics.NewRegistry()
+
+	return &engineMetrics{
+		registry: reg,
+		jobsClaimed: reg.Counter("mush_worker_jobs_claimed_total",
+			"Jobs the worker claimed from the platform."),
+		jobsCompleted: reg.Counter("mush_worker_jobs_completed_total",
+			"Jobs the worker completed."),
+		jobsFailed: reg.Counter("mush_worker_jobs_failed_total",
+			"Jobs that failed on the worker, including ones whose lease was lost."),
+		heartbeatFailures: reg.Counter("mush_worker_heartbeat_failures_total",
+			"Job heartbeats the platform did not accept."),
+		claimDuration: reg.Histogram("mush_worker_claim_duration_seconds",
+			"Time from asking the platform for a job to getting one or an empty answer, including the long-poll wait.",
+			metrics.DurationBuckets),
+	}
+}
+
+// Metrics returns the registry holding the engine's metrics. The host may
+// register its own before serving it.
+func (e *Engine) Metrics() *metrics.Registry {
+	return e.metrics.registry
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package engine
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEngine_Metrics(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	var b strings.Builder
+	if err := eng.Metrics().Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"mush_worker_jobs_claimed_total 1\n",
+		"mush_worker_jobs_completed_total 1\n",
+		"mush_worker_jobs_failed_total 0\n",
+		"mush_worker_heartbeat_failures_total 0\n",
+		"# TYPE mush_worker_claim_duration_seconds histogram\n",
+	} {
+		if !strings.Contains(b.String(), want) {
+			t.Fatalf("metrics missing %q:\n%s", want, b.String())
+		}
+	}
+
+	if strings.Contains(b.String(), "mush_worker_claim_duration_seconds_count 0\n") {
+		t.Fatalf("metrics = %s, want the claim observed", b.String())
+	}
+}
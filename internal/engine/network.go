@@ -0,0 +1,44 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"log/slog"
+
+	"github.com/musher-dev/mush/internal/netrecord"
+)
+
+// startNetworkRecording discards the calls recorded before the job started,
+// so the job's summary holds only its own.
+func (e *Engine) startNetworkRecording() {
+	if e.network != nil {
+		e.network.Reset()
+	}
+}
+
+// networkSummary returns the calls recorded during the current job, or nil
+// when recording is off or the job made none.
+func (e *Engine) networkSummary() *netrecord.Summary {
+	if e.network == nil {
+		return nil
+	}
+
+	return e.network.Summary()
+}
+
+// logNetworkSummary logs the hosts the current job called and how much it
+// sent and received.
+func (e *Engine) logNetworkSummary(logger *slog.Logger) {
+	summary := e.networkSummary()
+	if summary == nil {
+		return
+	}
+
+	logger.Info("job network calls",
+		slog.String("event.type", "job.network.summary"),
+		slog.Int("network.requests", summary.Requests),
+		slog.Int64("network.bytes_sent", summary.BytesSent),
+		slog.Int64("network.bytes_received", summary.BytesReceived),
+		slog.Any("network.hosts", summary.Hosts()),
+	)
+}
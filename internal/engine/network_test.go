@@ -0,0 +1,97 @@
+//go:build unix
+
+package engine
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/netrecord"
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+func TestEngine_RecordsJobNetworkCalls(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, "pong")
+	}))
+	t.Cleanup(target.Close)
+
+	rec, err := netrecord.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = rec.Close() })
+
+	proxyURL, err := url.Parse(rec.URL())
+	if err != nil {
+		t.Fatalf("url.Parse() error = %v", err)
+	}
+
+	httpClient := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+	get := func() {
+		req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, target.URL, http.NoBody)
+		if err != nil {
+			t.Errorf("NewRequestWithContext() error = %v", err)
+			return
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			t.Errorf("Do() error = %v", err)
+			return
+		}
+
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+
+	// A call before the job starts is not the job's.
+	get()
+
+	var (
+		mu  sync.Mutex
+		end *transcript.JobRecord
+	)
+
+	eng, platform := newTestEngine(t, &fakeExecutor{run: func(*client.Job) {
+		get()
+		get()
+	}})
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"}}`
+	eng.network = rec
+	eng.jobRecorder = func(r *transcript.JobRecord) {
+		if r.Event == transcript.JobFinished {
+			mu.Lock()
+			end = r
+			mu.Unlock()
+		}
+	}
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if end == nil || end.Network == nil {
+		t.Fatalf("end record = %+v, want a network summary", end)
+	}
+
+	host := strings.TrimPrefix(target.URL, "http://")
+	if got := end.Network.Calls; len(got) != 1 || got[0].Host != host || got[0].Method != http.MethodGet || got[0].Count != 2 || got[0].BytesReceived != 8 {
+		t.Fatalf("Network.Calls = %+v, want two GETs to %s receiving 8 bytes", got, host)
+	}
+}
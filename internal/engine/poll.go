@@ -0,0 +1,53 @@
+//go:build unix || windows
+
+package engine
+
+import "time"
+
+// pollBackoffAfter is how many consecutive empty polls run at the base
+// interval before it starts to back off.
+const pollBackoffAfter = 3
+
+// pollBackoff lengthens the claim poll interval while the queue stays empty,
+// doubling it up to a ceiling, and snaps back to the base interval as soon as
+// a job arrives. Claims long-poll and return as soon as work is available,
+// so a longer interval means fewer idle requests rather than slower pickup.
+type pollBackoff struct {
+	empty int
+}
+
+// interval returns the poll interval for the next claim.
+func (b *pollBackoff) interval(base, ceiling time.Duration) time.Duration {
+	d := base
+
+	for i := pollBackoffAfter; i <= b.empty && d < ceiling; i++ {
+		d *= 2
+	}
+
+	return max(min(d, ceiling), base)
+}
+
+// recordEmpty notes a poll that returned no job.
+func (b *pollBackoff) recordEmpty() {
+	b.empty++
+}
+
+// reset returns to the base interval after a job arrives.
+func (b *pollBackoff) reset() {
+	b.empty = 0
+}
+
+// nextPollInterval returns the interval for the next claim and records it
+// for Stats.
+func (e *Engine) nextPollInterval(backoff *pollBackoff) time.Duration {
+	cfg := e.config()
+	base := cfg.PollInterval()
+	interval := backoff.interval(base, cfg.PollIntervalMax())
+
+	e.statusMu.Lock()
+	e.pollInterval = interval
+	e.pollBackedOff = interval > base
+	e.statusMu.Unlock()
+
+	return interval
+}
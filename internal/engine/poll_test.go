@@ -0,0 +1,47 @@
+//go:build unix
+
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPollBackoff(t *testing.T) {
+	base, ceiling := 30*time.Second, 5*time.Minute
+
+	var b pollBackoff
+
+	var got []time.Duration
+
+	for range 8 {
+		got = append(got, b.interval(base, ceiling))
+		b.recordEmpty()
+	}
+
+	want := []time.Duration{
+		30 * time.Second, 30 * time.Second, 30 * time.Second,
+		time.Minute, 2 * time.Minute, 4 * time.Minute,
+		5 * time.Minute, 5 * time.Minute,
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("intervals = %v, want %v", got, want)
+		}
+	}
+
+	b.reset()
+
+	if d := b.interval(base, ceiling); d != base {
+		t.Fatalf("interval after reset = %v, want %v", d, base)
+	}
+}
+
+func TestPollBackoff_CeilingAtBaseDisablesBackoff(t *testing.T) {
+	b := pollBackoff{empty: 10}
+
+	if d := b.interval(30*time.Second, 30*time.Second); d != 30*time.Second {
+		t.Fatalf("interval = %v, want 30s", d)
+	}
+}
@@ -0,0 +1,150 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// maxProcessedOutputBytes caps the JSON an output processor may write.
+const maxProcessedOutputBytes = 4 << 20
+
+// processOutput runs a completed job's output through each processor under
+// results.processors, in order, and returns the output to report. Each
+// processor gets the previous one's output; one that fails is skipped with
+// a warning. The final output is validated against output's schema, and an
+// error wrapping harnesstype.ErrInvalidOutput is returned when a processor
+// broke it.
+func (e *Engine) processOutput(ctx context.Context, job *client.Job, output harnesstype.JobOutput, outputData map[string]any) (map[string]any, error) {
+	logger := observability.FromContext(ctx).With(slog.String("component", "engine"))
+
+	processors, err := e.config().OutputProcessors()
+	if err != nil {
+		logger.Warn("output processors ignored",
+			slog.String("event.type", "job.output_processor.error"),
+			slog.String("error", err.Error()),
+		)
+		e.ReportError(SeverityWarning, "Output processors ignored: "+err.Error())
+
+		return outputData, nil
+	}
+
+	if len(processors) == 0 {
+		return outputData, nil
+	}
+
+	for i := range processors {
+		processor := &processors[i]
+		started := e.now()
+
+		processed, err := runOutputProcessor(ctx, job, processor, outputData)
+		if err != nil {
+			logger.Warn("output processor failed",
+				slog.String("event.type", "job.output_processor.error"),
+				slog.String("output_processor.name", processor.Name),
+				slog.String("error", err.Error()),
+			)
+			e.ReportError(SeverityWarning, fmt.Sprintf("Output processor %s failed: %v", processor.Name, err))
+
+			continue
+		}
+
+		outputData = processed
+
+		logger.Info("job output processed",
+			slog.String("event.type", "job.output_processor"),
+			slog.String("output_processor.name", processor.Name),
+			slog.Int64("output_processor.duration_ms", e.now().Sub(started).Milliseconds()),
+		)
+	}
+
+	if err := harnesstype.ValidatePayload(output, outputData); err != nil {
+		return nil, fmt.Errorf("processed output: %w", err)
+	}
+
+	return outputData, nil
+}
+
+// runOutputProcessor runs one processor in the job's working directory with
+// outputData as JSON on stdin, and decodes the JSON object it writes to
+// stdout. Errors carry the processor's stderr.
+func runOutputProcessor(ctx context.Context, job *client.Job, processor *config.OutputProcessor, outputData map[string]any) (map[string]any, error) {
+	input, err := json.Marshal(outputData)
+	if err != nil {
+		return nil, fmt.Errorf("encode job output: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, processor.Timeout)
+	defer cancel()
+
+	cmd, err := executil.CommandContext(ctx, processor.Command[0], processor.Command[1:]...)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already names the command
+	}
+
+	cmd.Dir = jobWorkDir(job)
+	// The job's own environment may hold resolved secrets, so only its ID
+	// is passed on.
+	cmd.Env = append(os.Environ(), harnesstype.EnvJobID+"="+job.ID)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = time.Second
+
+	var stdout, stderr bytes.Buffer
+
+	cmd.Stdout = &limitedBuffer{buf: &stdout, limit: maxProcessedOutputBytes}
+	cmd.Stderr = &limitedBuffer{buf: &stderr, limit: 4 << 10}
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", processor.Timeout)
+		}
+
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+
+		return nil, fmt.Errorf("run: %w", err)
+	}
+
+	if stdout.Len() > maxProcessedOutputBytes {
+		return nil, fmt.Errorf("output exceeds %d bytes", maxProcessedOutputBytes)
+	}
+
+	var processed map[string]any
+
+	trimmed := bytes.TrimSpace(stdout.Bytes())
+	if len(trimmed) == 0 || trimmed[0] != '{' || json.Unmarshal(trimmed, &processed) != nil {
+		return nil, errors.New("output must be a JSON object")
+	}
+
+	return processed, nil
+}
+
+// limitedBuffer keeps up to one byte past limit, so an overflow can be
+// reported, and discards the rest without failing the writer.
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit + 1 - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(len(p), room)])
+	}
+
+	return len(p), nil
+}
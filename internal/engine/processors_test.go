@@ -0,0 +1,118 @@
+//go:build unix
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeProcessorScript writes an executable shell script and returns its path.
+func writeProcessorScript(t *testing.T, name, body string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o700); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	return path
+}
+
+func TestEngine_ProcessesOutputBeforeCompleting(t *testing.T) {
+	failing := writeProcessorScript(t, "failing", "echo boom >&2\nexit 1\n")
+	tickets := writeProcessorScript(t, "tickets",
+		`sed 's/^{/{"ticket":"ENG-1","job":"'"$MUSH_JOB_ID"'",/'`+"\n")
+	notJSON := writeProcessorScript(t, "not-json", "cat >/dev/null\necho done\n")
+
+	configHome := filepath.Join(t.TempDir(), "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	if err := os.MkdirAll(filepath.Join(configHome, "musher"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	yaml := "results:\n  processors:\n" +
+		"    - command: [" + failing + "]\n" +
+		"    - name: tickets\n      command: [" + tickets + "]\n" +
+		"    - command: [" + notJSON + "]\n"
+	if err := os.WriteFile(filepath.Join(configHome, "musher", "config.yaml"), []byte(yaml), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.outputs) != 1 {
+		t.Fatalf("outputs = %v, want one completed job", platform.outputs)
+	}
+
+	output := platform.outputs[0]
+	if output["ticket"] != "ENG-1" || output["job"] != "job-1" || output["output"] != "done" {
+		t.Fatalf("output = %v, want the job's output with the processor's fields", output)
+	}
+
+	var warnings []string
+	for _, entry := range eng.Stats().Errors {
+		warnings = append(warnings, entry.Message)
+	}
+
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "Output processor "+failing+" failed: boom") ||
+		!strings.Contains(joined, "Output processor "+notJSON+" failed: output must be a JSON object") {
+		t.Fatalf("warnings = %q, want both failing processors reported", warnings)
+	}
+}
+
+func TestEngine_FailsJobWhenProcessorBreaksOutput(t *testing.T) {
+	dropOutput := writeProcessorScript(t, "drop-output", "cat >/dev/null\necho '{\"schemaVersion\":1}'\n")
+
+	configHome := filepath.Join(t.TempDir(), "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	if err := os.MkdirAll(filepath.Join(configHome, "musher"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	yaml := "results:\n  processors:\n    - command: [" + dropOutput + "]\n"
+	if err := os.WriteFile(filepath.Join(configHome, "musher", "config.yaml"), []byte(yaml), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobFailed)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.outputs) != 0 {
+		t.Fatalf("outputs = %v, want the broken output withheld", platform.outputs)
+	}
+
+	if len(platform.failed) != 1 || platform.failed[0].ErrorCode != "invalid_output" {
+		t.Fatalf("fail reports = %+v, want one invalid_output", platform.failed)
+	}
+}
@@ -0,0 +1,123 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+const (
+	defaultRunnerConfigRefreshSeconds = 300
+	minRunnerConfigRefreshSeconds     = 60
+	maxRunnerConfigRefreshSeconds     = 900
+)
+
+// MinRefreshInterval is the shortest allowed runner config refresh interval.
+const MinRefreshInterval = minRunnerConfigRefreshSeconds * time.Second
+
+func normalizeRefreshInterval(seconds int) time.Duration {
+	if seconds <= 0 {
+		seconds = defaultRunnerConfigRefreshSeconds
+	}
+
+	if seconds < minRunnerConfigRefreshSeconds {
+		seconds = minRunnerConfigRefreshSeconds
+	}
+
+	if seconds > maxRunnerConfigRefreshSeconds {
+		seconds = maxRunnerConfigRefreshSeconds
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
+// refreshLoop periodically refreshes the runner config for MCP credential rotation.
+func (e *Engine) refreshLoop(ctx context.Context) {
+	e.refreshMu.Lock()
+	interval := e.refreshInterval
+	e.refreshMu.Unlock()
+
+	if interval <= 0 {
+		interval = normalizeRefreshInterval(0)
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-e.refreshNow:
+		}
+
+		cfg, err := e.client.GetRunnerConfig(ctx, e.habitatID)
+		if err != nil {
+			e.reportAPIError(SeverityWarning, "Runner config refresh failed", err)
+			timer.Reset(interval)
+
+			continue
+		}
+
+		if saveErr := worker.SaveRunnerConfigCache(e.client, e.habitatID, cfg, e.now()); saveErr != nil {
+			observability.FromContext(ctx).Warn("runner config cache write failed",
+				slog.String("component", "engine"),
+				slog.String("event.type", "worker.runner_config.cache_error"),
+				slog.String("error", saveErr.Error()),
+			)
+		}
+
+		e.refreshMu.Lock()
+
+		interval = normalizeRefreshInterval(cfg.RefreshAfterSeconds)
+		e.refreshInterval = interval
+
+		// Check all refreshable executors.
+		for _, executor := range e.executors {
+			if r, ok := executor.(harnesstype.Refreshable); ok {
+				if r.NeedsRefresh(cfg) {
+					e.runnerConfig = cfg
+				}
+			}
+		}
+
+		e.refreshMu.Unlock()
+		timer.Reset(interval)
+	}
+}
+
+// maybeRefreshExecutors applies a pending runner config to idle Refreshable executors.
+func (e *Engine) maybeRefreshExecutors(ctx context.Context) error {
+	if e.CurrentJobID() != "" {
+		return nil
+	}
+
+	e.refreshMu.Lock()
+	cfg := e.runnerConfig
+	e.refreshMu.Unlock()
+
+	for harnessName, executor := range e.executors {
+		r, ok := executor.(harnesstype.Refreshable)
+		if !ok {
+			continue
+		}
+
+		if !r.NeedsRefresh(cfg) {
+			continue
+		}
+
+		if err := r.ApplyRefresh(ctx, cfg); err != nil {
+			return fmt.Errorf("apply refresh for %s: %w", harnessName, err)
+		}
+	}
+
+	return nil
+}
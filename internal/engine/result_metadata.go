@@ -0,0 +1,147 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// gitProbeTimeout bounds each git command run to describe a job's changes.
+const gitProbeTimeout = 5 * time.Second
+
+// jobWorkDir returns the directory a job's harness runs in.
+func jobWorkDir(job *client.Job) string {
+	if job.Execution != nil && strings.TrimSpace(job.Execution.WorkingDirectory) != "" {
+		return job.Execution.WorkingDirectory
+	}
+
+	return "."
+}
+
+func runGit(ctx context.Context, dir string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, gitProbeTimeout)
+	defer cancel()
+
+	cmd, err := executil.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitHead returns the commit checked out in dir, or "" when dir is not a git
+// repository with at least one commit.
+func gitHead(ctx context.Context, dir string) string {
+	head, err := runGit(ctx, dir, "rev-parse", "--verify", "--quiet", "HEAD")
+	if err != nil {
+		return ""
+	}
+
+	return head
+}
+
+// collectResultMetadata describes what a job changed in its working directory
+// since startHead and renders the human summary. When the git workflow
+// committed the changes, they are described from its branch and commit,
+// and a pull request it opened wins over one found in the response. It
+// never fails: anything that cannot be determined is left out.
+func collectResultMetadata(ctx context.Context, job *client.Job, startHead, response string, workflow gitWorkflowResult) *harnesstype.ResultMetadata {
+	dir := jobWorkDir(job)
+
+	pullRequestURL := workflow.pullRequestURL
+	if pullRequestURL == "" {
+		pullRequestURL = harnesstype.FindPullRequestURL(response)
+	}
+
+	meta := &harnesstype.ResultMetadata{PullRequestURL: pullRequestURL}
+	diffArgs := []string{"diff", "--shortstat", startHead}
+
+	if workflow.commit != "" {
+		// The workflow restored the directory, so the changes live only on
+		// its branch.
+		meta.Branch = workflow.branch
+		meta.Commit = workflow.commit
+		diffArgs = append(diffArgs, workflow.commit)
+	} else if head := gitHead(ctx, dir); head != "" {
+		if branch, err := runGit(ctx, dir, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && branch != "HEAD" {
+			meta.Branch = branch
+		}
+
+		if head != startHead {
+			meta.Commit = head
+		}
+	}
+
+	if startHead != "" {
+		if shortstat, err := runGit(ctx, dir, diffArgs...); err == nil {
+			meta.Diffstat = parseShortstat(shortstat)
+		}
+	}
+
+	summary, err := harnesstype.RenderResultSummary(&harnesstype.SummaryData{
+		Excerpt:        harnesstype.SummaryExcerpt(response),
+		Response:       response,
+		JobID:          job.ID,
+		Branch:         meta.Branch,
+		Commit:         meta.Commit,
+		PullRequestURL: meta.PullRequestURL,
+		Diffstat:       meta.Diffstat,
+	})
+	if err != nil {
+		observability.FromContext(ctx).Warn("result summary template failed",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.result_summary.error"),
+			slog.String("error", err.Error()),
+		)
+
+		summary = harnesstype.SummaryExcerpt(response)
+	}
+
+	meta.Summary = summary
+
+	return meta
+}
+
+var shortstatPattern = regexp.MustCompile(`(\d+) (file|insertion|deletion)`)
+
+// parseShortstat parses `git diff --shortstat` output such as
+// "3 files changed, 10 insertions(+), 2 deletions(-)". An empty diff yields
+// a zero Diffstat.
+func parseShortstat(shortstat string) *harnesstype.Diffstat {
+	stat := &harnesstype.Diffstat{}
+
+	for _, match := range shortstatPattern.FindAllStringSubmatch(shortstat, -1) {
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+
+		switch match[2] {
+		case "file":
+			stat.FilesChanged = count
+		case "insertion":
+			stat.Insertions = count
+		case "deletion":
+			stat.Deletions = count
+		}
+	}
+
+	return stat
+}
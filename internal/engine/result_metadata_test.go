@@ -0,0 +1,149 @@
+//go:build unix
+
+package engine
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestParseShortstat(t *testing.T) {
+	tests := []struct {
+		in   string
+		want harnesstype.Diffstat
+	}{
+		{in: " 3 files changed, 10 insertions(+), 2 deletions(-)", want: harnesstype.Diffstat{FilesChanged: 3, Insertions: 10, Deletions: 2}},
+		{in: " 1 file changed, 1 insertion(+)", want: harnesstype.Diffstat{FilesChanged: 1, Insertions: 1}},
+		{in: " 1 file changed, 4 deletions(-)", want: harnesstype.Diffstat{FilesChanged: 1, Deletions: 4}},
+		{in: "", want: harnesstype.Diffstat{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseShortstat(tt.in); *got != tt.want {
+			t.Errorf("parseShortstat(%q) = %+v, want %+v", tt.in, *got, tt.want)
+		}
+	}
+}
+
+func gitInit(t *testing.T, dir string) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	for _, args := range [][]string{
+		{"init", "-q", "-b", "main"},
+		{"-c", "user.email=t@example.com", "-c", "user.name=t", "commit", "-q", "--allow-empty", "-m", "init"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+}
+
+func TestCollectResultMetadata(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	dir := t.TempDir()
+	gitInit(t, dir)
+
+	startHead := gitHead(t.Context(), dir)
+	if startHead == "" {
+		t.Fatal("gitHead() returned empty for a repository with a commit")
+	}
+
+	// Untracked files do not count; modify a tracked file instead.
+	tracked := filepath.Join(dir, "notes.txt")
+	if err := os.WriteFile(tracked, []byte("a\n"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cmd := exec.Command("git", "-C", dir, "add", "notes.txt")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git add: %v\n%s", err, out)
+	}
+
+	job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{WorkingDirectory: dir}}
+	response := "Working on it.\n\nAdded notes. Opened https://github.com/acme/repo/pull/42 for review."
+
+	meta := collectResultMetadata(t.Context(), job, startHead, response, gitWorkflowResult{})
+
+	if meta.Branch != "main" {
+		t.Errorf("Branch = %q, want main", meta.Branch)
+	}
+
+	if meta.Commit != "" {
+		t.Errorf("Commit = %q, want empty (HEAD did not move)", meta.Commit)
+	}
+
+	if meta.PullRequestURL != "https://github.com/acme/repo/pull/42" {
+		t.Errorf("PullRequestURL = %q", meta.PullRequestURL)
+	}
+
+	if meta.Diffstat == nil || meta.Diffstat.FilesChanged != 1 || meta.Diffstat.Insertions != 1 {
+		t.Errorf("Diffstat = %+v, want 1 file, 1 insertion", meta.Diffstat)
+	}
+
+	for _, want := range []string{"Added notes.", "Pull request: https://github.com/acme/repo/pull/42", "Branch: `main`", "Changes: 1 file(s), +1 -0"} {
+		if !strings.Contains(meta.Summary, want) {
+			t.Errorf("Summary missing %q:\n%s", want, meta.Summary)
+		}
+	}
+
+	if strings.Contains(meta.Summary, "Working on it.") {
+		t.Errorf("Summary should only quote the last paragraph:\n%s", meta.Summary)
+	}
+}
+
+func TestCollectResultMetadata_UserTemplate(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	tmplPath := filepath.Join(configHome, "musher", "templates", "result-summary.tmpl")
+	if err := os.MkdirAll(filepath.Dir(tmplPath), 0o700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := os.WriteFile(tmplPath, []byte("Job {{.JobID}}: {{.Excerpt}}"), 0o600); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+
+	job := &client.Job{ID: "job-7", Execution: &client.ExecutionConfig{WorkingDirectory: t.TempDir()}}
+
+	meta := collectResultMetadata(t.Context(), job, "", "All done.", gitWorkflowResult{})
+	if meta.Summary != "Job job-7: All done." {
+		t.Fatalf("Summary = %q, want %q", meta.Summary, "Job job-7: All done.")
+	}
+
+	if meta.Branch != "" || meta.Diffstat != nil {
+		t.Fatalf("git fields set outside a repository: %+v", meta)
+	}
+}
+
+func TestAgentJobOutput_RejectsInvalidResultMetadata(t *testing.T) {
+	out := harnesstype.NewAgentJobOutput("done", 0)
+	out.SetResultMetadata(&harnesstype.ResultMetadata{PullRequestURL: "javascript:alert(1)"})
+
+	if _, err := harnesstype.EncodeOutput(out); err == nil {
+		t.Fatal("EncodeOutput() accepted a non-http pull request URL")
+	}
+
+	out.SetResultMetadata(&harnesstype.ResultMetadata{Summary: "ok", Diffstat: &harnesstype.Diffstat{FilesChanged: 1}})
+
+	payload, err := harnesstype.EncodeOutput(out)
+	if err != nil {
+		t.Fatalf("EncodeOutput() error = %v", err)
+	}
+
+	if _, ok := payload["resultMetadata"].(map[string]any); !ok {
+		t.Fatalf("payload missing resultMetadata: %v", payload)
+	}
+}
@@ -0,0 +1,265 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/safeio"
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+// Result statuses, as matched by a sink's "on" list.
+const (
+	resultCompleted = "completed"
+	resultFailed    = "failed"
+)
+
+// jobResult is what results.sinks receive for a finished job.
+type jobResult struct {
+	JobID         string    `json:"jobId"`
+	QueueID       string    `json:"queueId,omitempty"`
+	HabitatID     string    `json:"habitatId,omitempty"`
+	Status        string    `json:"status"`
+	AttemptNumber int       `json:"attemptNumber,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+	FinishedAt    time.Time `json:"finishedAt"`
+	DurationMs    int64     `json:"durationMs"`
+
+	// Output is the result reported to the platform for a completed job.
+	Output map[string]any `json:"output,omitempty"`
+
+	// ErrorCode, ErrorMessage, and Retry describe a failed job.
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+	Retry        bool   `json:"retry,omitempty"`
+
+	// Turns and CostUSD are the harness's usage, when it reports any.
+	Turns   int      `json:"turns,omitempty"`
+	CostUSD *float64 `json:"costUsd,omitempty"`
+}
+
+// publishResult sends a finished job's result to each configured sink, with
+// failure set for a failed job. Sink errors are reported as warnings and
+// never affect the job.
+func (e *Engine) publishResult(ctx context.Context, job *client.Job, outputData map[string]any, failure *Failure) {
+	logger := observability.FromContext(ctx)
+
+	sinks, err := e.config().ResultSinks()
+	if err != nil {
+		logger.Warn("result sinks ignored",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.result_sink.error"),
+			slog.String("error", err.Error()),
+		)
+		e.ReportError(SeverityWarning, "Result sinks ignored: "+err.Error())
+
+		return
+	}
+
+	if len(sinks) == 0 {
+		return
+	}
+
+	result := e.newJobResult(job, outputData, failure)
+
+	for i := range sinks {
+		sink := &sinks[i]
+		if !sink.Wants(result.Status) {
+			continue
+		}
+
+		if err := e.sendResult(ctx, sink, result); err != nil {
+			logger.Warn("result sink failed",
+				slog.String("component", "engine"),
+				slog.String("event.type", "job.result_sink.error"),
+				slog.String("result_sink.type", sink.Type),
+				slog.String("error", err.Error()),
+			)
+			e.ReportError(SeverityWarning, fmt.Sprintf("Result sink %s failed: %v", sink.Type, err))
+		}
+	}
+}
+
+func (e *Engine) newJobResult(job *client.Job, outputData map[string]any, failure *Failure) *jobResult {
+	e.jobMu.Lock()
+	started := e.jobStarted
+	e.jobMu.Unlock()
+
+	now := e.now()
+	if started.IsZero() {
+		started = now
+	}
+
+	result := &jobResult{
+		JobID:         job.ID,
+		QueueID:       job.QueueID,
+		HabitatID:     job.HabitatID,
+		Status:        resultCompleted,
+		AttemptNumber: job.AttemptNumber,
+		StartedAt:     started.UTC(),
+		FinishedAt:    now.UTC(),
+		DurationMs:    now.Sub(started).Milliseconds(),
+		Output:        outputData,
+	}
+
+	if failure != nil {
+		result.Status = resultFailed
+		result.Output = nil
+		result.ErrorCode = failure.Code
+		result.ErrorMessage = failure.Message
+		result.Retry = failure.Retry
+	}
+
+	if usage := e.Stats().Usage; usage != nil {
+		result.Turns = usage.Turns
+
+		if usage.CostKnown {
+			cost := usage.CostUSD
+			result.CostUSD = &cost
+		}
+	}
+
+	return result
+}
+
+// recordJobStart passes the job's start record to the host's JobRecorder.
+func (e *Engine) recordJobStart(job *client.Job) {
+	if e.jobRecorder == nil {
+		return
+	}
+
+	e.jobMu.Lock()
+	started := e.jobStarted
+	e.jobMu.Unlock()
+
+	e.jobRecorder(&transcript.JobRecord{
+		Event:     transcript.JobStarted,
+		JobID:     job.ID,
+		Name:      job.GetDisplayName(),
+		Harness:   job.GetHarnessType(),
+		QueueID:   job.QueueID,
+		Attempt:   job.AttemptNumber,
+		Prompt:    job.GetRenderedInstruction(),
+		StartedAt: started.UTC(),
+	})
+}
+
+// recordJobEnd passes the job's finish record to the host's JobRecorder,
+// with failure set for a failed job.
+func (e *Engine) recordJobEnd(job *client.Job, outputData map[string]any, failure *Failure) {
+	if e.jobRecorder == nil {
+		return
+	}
+
+	result := e.newJobResult(job, outputData, failure)
+	rec := &transcript.JobRecord{
+		Event:        transcript.JobFinished,
+		JobID:        job.ID,
+		Name:         job.GetDisplayName(),
+		Harness:      job.GetHarnessType(),
+		QueueID:      job.QueueID,
+		Attempt:      job.AttemptNumber,
+		Status:       result.Status,
+		StartedAt:    result.StartedAt,
+		DurationMs:   result.DurationMs,
+		ErrorCode:    result.ErrorCode,
+		ErrorMessage: result.ErrorMessage,
+		Turns:        result.Turns,
+		CostUSD:      result.CostUSD,
+		Network:      e.networkSummary(),
+	}
+
+	if output, ok := result.Output["output"].(string); ok {
+		rec.Output = output
+	}
+
+	e.jobRecorder(rec)
+}
+
+// sendResult delivers result to one sink.
+func (e *Engine) sendResult(ctx context.Context, sink *config.ResultSink, result *jobResult) error {
+	switch sink.Type {
+	case config.ResultSinkFile:
+		return writeResultFile(sink.Dir, result)
+	case config.ResultSinkWebhook:
+		hook := &client.WebhookConfig{URL: sink.URL, Headers: sink.Headers, Secret: sink.Secret}
+		if err := hook.Validate(); err != nil {
+			return err //nolint:wrapcheck // Validate's message names the bad field
+		}
+
+		return e.client.PostWebhook(ctx, hook, result) //nolint:wrapcheck // PostWebhook wraps its errors
+	case config.ResultSinkSlack:
+		hook := &client.WebhookConfig{URL: sink.URL}
+		if err := hook.Validate(); err != nil {
+			return err //nolint:wrapcheck // Validate's message names the bad field
+		}
+
+		return e.client.PostWebhook(ctx, hook, map[string]string{"text": slackResultText(result)}) //nolint:wrapcheck // PostWebhook wraps its errors
+	default:
+		return fmt.Errorf("unknown result sink type %q", sink.Type)
+	}
+}
+
+// writeResultFile writes result to <dir>/<job-id>-<attempt>.json.
+func writeResultFile(dir string, result *jobResult) error {
+	if dir == "" {
+		return errors.New("file result sink needs a dir")
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode result: %w", err)
+	}
+
+	if err := safeio.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create result dir: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%d.json", resultFileStem(result.JobID), result.AttemptNumber)
+	if err := safeio.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write result: %w", err)
+	}
+
+	return nil
+}
+
+// resultFileStem keeps a job ID from escaping the sink's directory.
+func resultFileStem(jobID string) string {
+	stem := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == 0 {
+			return '_'
+		}
+
+		return r
+	}, jobID)
+
+	if stem == "" || stem == "." || stem == ".." {
+		return "job"
+	}
+
+	return stem
+}
+
+// slackResultText is the one-line summary posted to a Slack sink.
+func slackResultText(result *jobResult) string {
+	elapsed := (time.Duration(result.DurationMs) * time.Millisecond).Round(time.Second)
+
+	if result.Status == resultFailed {
+		return fmt.Sprintf(":x: Job %s failed after %s (attempt %d): %s: %s",
+			result.JobID, elapsed, result.AttemptNumber, result.ErrorCode, result.ErrorMessage)
+	}
+
+	return fmt.Sprintf(":white_check_mark: Job %s completed in %s (attempt %d)",
+		result.JobID, elapsed, result.AttemptNumber)
+}
@@ -0,0 +1,210 @@
+//go:build unix
+
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+// writeResultSinks points the config at a config.yaml declaring sinks.
+func writeResultSinks(t *testing.T, sinks string) {
+	t.Helper()
+
+	configHome := filepath.Join(t.TempDir(), "config")
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	if err := os.MkdirAll(filepath.Join(configHome, "musher"), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	yaml := "results:\n  sinks:\n" + sinks
+	if err := os.WriteFile(filepath.Join(configHome, "musher", "config.yaml"), []byte(yaml), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+}
+
+func TestEngine_PublishesResultToSinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		executor  *fakeExecutor
+		wait      EventType
+		wantFile  string
+		wantSlack string
+	}{
+		{
+			name:     "completed goes to the file sink only",
+			executor: &fakeExecutor{},
+			wait:     EventJobCompleted,
+			wantFile: "completed",
+		},
+		{
+			name:      "failed goes to both sinks",
+			executor:  &fakeExecutor{err: &harnesstype.ExecError{Reason: "execution_error", Message: "boom"}},
+			wait:      EventJobFailed,
+			wantFile:  "failed",
+			wantSlack: ":x: Job job-1 failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu    sync.Mutex
+				texts []string
+			)
+
+			slackSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var body struct {
+					Text string `json:"text"`
+				}
+				_ = json.NewDecoder(r.Body).Decode(&body)
+
+				mu.Lock()
+				texts = append(texts, body.Text)
+				mu.Unlock()
+			}))
+			t.Cleanup(slackSrv.Close)
+
+			dir := filepath.Join(t.TempDir(), "results")
+			writeResultSinks(t, "    - type: file\n      dir: "+dir+"\n"+
+				"    - type: slack\n      url: "+slackSrv.URL+"\n      on: [failed]\n")
+
+			eng, platform := newTestEngine(t, tt.executor)
+			platform.claimBody = `{"job":{"id":"job-1","attemptNumber":2},"execution":{"harnessType":"test"}}`
+
+			if err := eng.Start(t.Context()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			waitForEvent(t, eng.Events(), tt.wait)
+
+			if err := eng.Drain(t.Context()); err != nil {
+				t.Fatalf("Drain() error = %v", err)
+			}
+
+			data, err := os.ReadFile(filepath.Join(dir, "job-1-2.json"))
+			if err != nil {
+				t.Fatalf("result file: %v", err)
+			}
+
+			var result jobResult
+			if err := json.Unmarshal(data, &result); err != nil {
+				t.Fatalf("decode result %q: %v", data, err)
+			}
+
+			if result.JobID != "job-1" || result.Status != tt.wantFile || result.AttemptNumber != 2 {
+				t.Fatalf("result = %+v, want job-1 attempt 2 %s", result, tt.wantFile)
+			}
+
+			if tt.wantFile == "completed" && result.Output["output"] != "done" {
+				t.Fatalf("result output = %v, want the job's output", result.Output)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if tt.wantSlack == "" {
+				if len(texts) != 0 {
+					t.Fatalf("slack messages = %q, want none", texts)
+				}
+
+				return
+			}
+
+			if len(texts) != 1 || !strings.HasPrefix(texts[0], tt.wantSlack) || !strings.Contains(texts[0], "boom") {
+				t.Fatalf("slack messages = %q, want one starting %q", texts, tt.wantSlack)
+			}
+		})
+	}
+}
+
+func TestEngine_ResultSinkErrorIsAWarning(t *testing.T) {
+	writeResultSinks(t, "    - type: carrier-pigeon\n")
+
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ev := waitForEvent(t, eng.Events(), EventError)
+	if !strings.Contains(ev.Message, `Result sink carrier-pigeon failed: unknown result sink type "carrier-pigeon"`) {
+		t.Fatalf("error event = %q, want the unknown sink", ev.Message)
+	}
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if got := eng.Stats().Completed; got != 1 {
+		t.Fatalf("Completed = %d, want the job to complete despite the sink", got)
+	}
+}
+
+func TestEngine_RecordsJobStartAndEnd(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		records []transcript.JobRecord
+	)
+
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimBody = `{"job":{"id":"job-1","queueId":"q-1"},"execution":{"harnessType":"test","renderedInstruction":"Fix the build"}}`
+	eng.jobRecorder = func(rec *transcript.JobRecord) {
+		mu.Lock()
+		records = append(records, *rec)
+		mu.Unlock()
+	}
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if len(records) != 2 {
+		t.Fatalf("records = %+v, want a start and an end", records)
+	}
+
+	start, end := records[0], records[1]
+	if start.Event != transcript.JobStarted || start.JobID != "job-1" || start.Prompt != "Fix the build" || start.Harness != "test" || start.StartedAt.IsZero() {
+		t.Fatalf("start record = %+v, want job-1 with its prompt", start)
+	}
+
+	if end.Event != transcript.JobFinished || end.Status != resultCompleted || end.Output != "done" || !end.StartedAt.Equal(start.StartedAt) {
+		t.Fatalf("end record = %+v, want job-1 completed with its output", end)
+	}
+}
+
+func TestResultFileStem(t *testing.T) {
+	tests := map[string]string{
+		"job-1":     "job-1",
+		"../../etc": ".._.._etc",
+		"a/b\\c":    "a_b_c",
+		"..":        "job",
+		"":          "job",
+	}
+
+	for jobID, want := range tests {
+		if got := resultFileStem(jobID); got != want {
+			t.Errorf("resultFileStem(%q) = %q, want %q", jobID, got, want)
+		}
+	}
+}
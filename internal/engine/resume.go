@@ -0,0 +1,119 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// errLeaseLost cancels the in-flight job when the platform no longer holds
+// its lease for this worker.
+var errLeaseLost = errors.New("job lease lost")
+
+// wakeLoop watches for system sleep and resumes the engine on wake.
+func (e *Engine) wakeLoop(ctx context.Context) {
+	ticker := time.NewTicker(wakeCheckInterval)
+	defer ticker.Stop()
+
+	detector := newSleepDetector(e.now(), wakeCheckInterval, wakeGapThreshold)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if slept, ok := detector.observe(e.now()); ok {
+				e.resume(ctx, "clock_jump", slept)
+			}
+		}
+	}
+}
+
+// Resume re-establishes platform state after the process was suspended, for
+// hosts that observe the wake directly (such as on SIGCONT). It heartbeats the
+// worker, verifies the in-flight job's lease, and triggers a runner config
+// refresh. It is a no-op before Start.
+func (e *Engine) Resume(ctx context.Context) {
+	e.resume(ctx, "sigcont", 0)
+}
+
+func (e *Engine) resume(ctx context.Context, trigger string, slept time.Duration) {
+	stats := e.Stats()
+	if stats.WorkerID == "" {
+		return
+	}
+
+	observability.FromContext(ctx).Info("worker resumed",
+		slog.String("component", "engine"),
+		slog.String("event.type", "worker.resume"),
+		slog.String("resume.trigger", trigger),
+		slog.Duration("resume.slept", slept),
+	)
+
+	e.emit(Event{Type: EventResumed, Status: stats.Status, JobID: stats.JobID})
+
+	if resp, err := e.client.HeartbeatWorker(ctx, stats.WorkerID, stats.JobID); err != nil {
+		e.reportAPIError(SeverityWarning, "Worker heartbeat after wake failed", err)
+	} else {
+		e.setMaintenance(ctx, resp.Maintenance)
+	}
+
+	if stats.JobID != "" {
+		e.verifyLease(ctx, stats.JobID)
+	}
+
+	select {
+	case e.refreshNow <- struct{}{}:
+	default:
+	}
+}
+
+// verifyLease heartbeats jobID and abandons it if the platform reports the
+// lease is gone, instead of letting it run to a completion that will be rejected.
+func (e *Engine) verifyLease(ctx context.Context, jobID string) {
+	if _, err := e.client.HeartbeatJob(ctx, jobID); err != nil {
+		if !isLeaseLost(err) {
+			e.reportAPIError(SeverityWarning, "Heartbeat after wake failed", err)
+
+			return
+		}
+
+		e.ReportError(SeverityError, fmt.Sprintf("Job %s lease lost while suspended", jobID))
+
+		e.jobMu.Lock()
+		if e.currentJob != nil && e.currentJob.ID == jobID && e.cancelJob != nil {
+			e.cancelJob(errLeaseLost)
+		}
+		e.jobMu.Unlock()
+
+		return
+	}
+
+	e.statusMu.Lock()
+	e.lastHeartbeat = e.now()
+	e.statusMu.Unlock()
+}
+
+// isLeaseLost reports whether a job heartbeat error means the job is no longer
+// leased to this worker.
+func isLeaseLost(err error) bool {
+	var statusErr *client.HTTPStatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+
+	switch statusErr.Status {
+	case http.StatusNotFound, http.StatusConflict, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,152 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// maxRetryTimeout caps a retry's scaled execution timeout.
+const maxRetryTimeout = 2 * time.Hour
+
+// retryTimeout returns the execution timeout for the job's attempt: base on
+// the first attempt, scaled by the retry config's multiplier on later ones.
+func retryTimeout(job *client.Job, base time.Duration) time.Duration {
+	multiplier := job.GetRetryConfig().TimeoutMultiplier
+	if !job.IsRetry() || multiplier <= 1 || math.IsInf(multiplier, 0) {
+		return base
+	}
+
+	scaled := float64(base) * multiplier
+	if scaled >= float64(maxRetryTimeout) {
+		return max(base, maxRetryTimeout)
+	}
+
+	return time.Duration(scaled)
+}
+
+// prependPreviousFailure tells a retried job why its previous attempt
+// failed. The claim payload usually carries the error; when it doesn't, the
+// job is fetched. Like enrichment, this is best-effort.
+func (e *Engine) prependPreviousFailure(ctx context.Context, job *client.Job) {
+	if !job.IsRetry() || !job.GetRetryConfig().IncludePreviousError {
+		return
+	}
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "engine"))
+
+	previous := job
+	if job.ErrorCode == "" && job.ErrorMessage == "" {
+		fetchCtx, cancel := context.WithTimeout(ctx, enrichmentTimeout)
+		defer cancel()
+
+		fetched, err := e.client.GetJob(fetchCtx, job.ID)
+		if err != nil {
+			logger.Warn("previous failure unavailable",
+				slog.String("event.type", "job.retry.context_error"),
+				slog.String("error", err.Error()),
+			)
+			e.reportAPIError(SeverityWarning, "Previous failure unavailable", err)
+
+			return
+		}
+
+		previous = fetched
+	}
+
+	section := formatPreviousFailure(job.AttemptNumber, job.MaxAttempts, previous)
+	if section == "" {
+		return
+	}
+
+	job.Execution.RenderedInstruction = section + "\n" + job.Execution.RenderedInstruction
+
+	logger.Info("prompt prefixed with previous failure",
+		slog.String("event.type", "job.retry.context"),
+		slog.String("job.previous_error_code", previous.ErrorCode),
+	)
+}
+
+// formatPreviousFailure renders the previous attempt's error as a Markdown
+// section, or "" when no error was recorded.
+func formatPreviousFailure(attempt, maxAttempts int, previous *client.Job) string {
+	message := strings.TrimSpace(previous.ErrorMessage)
+	if previous.ErrorCode == "" && message == "" {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString("## Previous attempt failed\n\n")
+
+	if maxAttempts > 0 {
+		fmt.Fprintf(&b, "This is attempt %d of %d.", attempt, maxAttempts)
+	} else {
+		fmt.Fprintf(&b, "This is attempt %d.", attempt)
+	}
+
+	b.WriteString(" The previous attempt failed")
+
+	if previous.ErrorCode != "" {
+		fmt.Fprintf(&b, " with `%s`", previous.ErrorCode)
+	}
+
+	if message != "" {
+		fmt.Fprintf(&b, ":\n\n%s\n", message)
+	} else {
+		b.WriteString(".\n")
+	}
+
+	// Partial output is multi-line; it gets its own block after the list.
+	keys := make([]string, 0, len(previous.ErrorDetails))
+	for key := range previous.ErrorDetails {
+		if key != "partialOutput" {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) > 0 {
+		b.WriteString("\nDetails:\n\n")
+
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			fmt.Fprintf(&b, "- %s: %v\n", key, previous.ErrorDetails[key])
+		}
+	}
+
+	if partial, ok := previous.ErrorDetails["partialOutput"].(string); ok && partial != "" {
+		fmt.Fprintf(&b, "\nIts last output was:\n\n```\n%s\n```\n", partial)
+	}
+
+	b.WriteString("\nAvoid repeating what caused it.\n")
+
+	return b.String()
+}
+
+// resetAfterFailure clears the executor after a failed job so the next job
+// starts fresh, unless the job will be retried with PreserveContext.
+func (e *Engine) resetAfterFailure(ctx context.Context, executor harnesstype.Executor, job *client.Job, retry bool) {
+	if retry && job.GetRetryConfig().PreserveContext {
+		observability.FromContext(ctx).Info("harness context kept for retry",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.retry.preserve_context"),
+		)
+
+		return
+	}
+
+	if err := executor.Reset(ctx); err != nil {
+		e.ReportError(SeverityError, fmt.Sprintf("Executor reset failed: %v", err))
+	}
+}
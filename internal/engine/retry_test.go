@@ -0,0 +1,102 @@
+//go:build unix
+
+package engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+)
+
+func retriedJob(attempt int, retry *client.RetryConfig) *client.Job {
+	return &client.Job{
+		ID:            "job-1",
+		AttemptNumber: attempt,
+		MaxAttempts:   3,
+		Execution:     &client.ExecutionConfig{RenderedInstruction: "Fix the build.", Retry: retry},
+	}
+}
+
+func TestRetryTimeout(t *testing.T) {
+	base := 10 * time.Minute
+
+	tests := []struct {
+		name string
+		job  *client.Job
+		want time.Duration
+	}{
+		{name: "first attempt", job: retriedJob(1, &client.RetryConfig{TimeoutMultiplier: 2}), want: base},
+		{name: "retry without config", job: retriedJob(2, nil), want: base},
+		{name: "retry scaled", job: retriedJob(2, &client.RetryConfig{TimeoutMultiplier: 1.5}), want: 15 * time.Minute},
+		{name: "multiplier below one ignored", job: retriedJob(2, &client.RetryConfig{TimeoutMultiplier: 0.5}), want: base},
+		{name: "capped", job: retriedJob(3, &client.RetryConfig{TimeoutMultiplier: 100}), want: maxRetryTimeout},
+	}
+
+	for _, tt := range tests {
+		if got := retryTimeout(tt.job, base); got != tt.want {
+			t.Errorf("%s: retryTimeout() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPrependPreviousFailure_FetchesMissingError(t *testing.T) {
+	requireLocalListener(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/runner/jobs/job-1" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+
+		_, _ = w.Write([]byte(`{"id":"job-1","errorCode":"timeout","errorMessage":"claude execution timed out",` +
+			`"errorDetails":{"lastTool":"Bash"}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	e := New(&Options{Client: client.New(srv.URL, "test-key"), Config: config.Load()})
+
+	job := retriedJob(2, &client.RetryConfig{IncludePreviousError: true})
+	e.prependPreviousFailure(t.Context(), job)
+
+	prompt := job.Execution.RenderedInstruction
+	for _, want := range []string{"attempt 2 of 3", "`timeout`", "claude execution timed out", "- lastTool: Bash"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt missing %q:\n%s", want, prompt)
+		}
+	}
+
+	if !strings.HasSuffix(prompt, "\nFix the build.") {
+		t.Errorf("original prompt should follow the failure context:\n%s", prompt)
+	}
+}
+
+func TestPrependPreviousFailure_FirstAttemptUnchanged(t *testing.T) {
+	e := New(&Options{Client: client.New("http://127.0.0.1:0", "test-key"), Config: config.Load()})
+
+	job := retriedJob(1, &client.RetryConfig{IncludePreviousError: true})
+	e.prependPreviousFailure(t.Context(), job)
+
+	if job.Execution.RenderedInstruction != "Fix the build." {
+		t.Errorf("prompt = %q, want unchanged", job.Execution.RenderedInstruction)
+	}
+}
+
+func TestFormatPreviousFailure_PartialOutputBlock(t *testing.T) {
+	section := formatPreviousFailure(2, 3, &client.Job{
+		ErrorCode:    "timeout",
+		ErrorMessage: "codex execution timed out",
+		ErrorDetails: map[string]any{"partialOutput": "editing main.go\nrunning tests", "exitCode": 1},
+	})
+
+	if !strings.Contains(section, "- exitCode: 1\n") || strings.Contains(section, "- partialOutput") {
+		t.Errorf("details list should skip partialOutput:\n%s", section)
+	}
+
+	if !strings.Contains(section, "```\nediting main.go\nrunning tests\n```\n") {
+		t.Errorf("partial output should be fenced:\n%s", section)
+	}
+}
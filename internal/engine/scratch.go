@@ -0,0 +1,110 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// jobScratch is the per-job directory tree behind the MUSH_* job
+// environment contract.
+type jobScratch struct {
+	root         string
+	tmpDir       string
+	artifactsDir string
+	resultFile   string
+}
+
+// prepareScratch creates the job's scratch directories and adds the job
+// environment variables to its execution config. The returned func removes
+// the directories.
+func prepareScratch(job *client.Job) (*jobScratch, func(), error) {
+	root, err := os.MkdirTemp("", "mush-job-")
+	if err != nil {
+		return nil, nil, fmt.Errorf("create job scratch directory: %w", err)
+	}
+
+	cleanup := func() { _ = os.RemoveAll(root) }
+
+	scratch := &jobScratch{
+		root:         root,
+		tmpDir:       filepath.Join(root, "tmp"),
+		artifactsDir: filepath.Join(root, "artifacts"),
+		resultFile:   filepath.Join(root, "result.json"),
+	}
+
+	for _, dir := range []string{scratch.tmpDir, scratch.artifactsDir} {
+		if err := os.Mkdir(dir, 0o700); err != nil {
+			cleanup()
+
+			return nil, nil, fmt.Errorf("create job scratch directory: %w", err)
+		}
+	}
+
+	if job.Execution == nil {
+		job.Execution = &client.ExecutionConfig{}
+	}
+
+	env := maps.Clone(job.Execution.Environment)
+	if env == nil {
+		env = map[string]string{}
+	}
+
+	env[harnesstype.EnvJobID] = job.ID
+	env[harnesstype.EnvTmpDir] = scratch.tmpDir
+	env[harnesstype.EnvArtifactsDir] = scratch.artifactsDir
+	env[harnesstype.EnvResultFile] = scratch.resultFile
+	job.Execution.Environment = env
+
+	return scratch, cleanup, nil
+}
+
+// readResult returns the JSON object the job wrote to its result file, or
+// nil when it wrote none.
+func (s *jobScratch) readResult() (json.RawMessage, error) {
+	f, err := os.Open(s.resultFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("open result file: %w", err)
+	}
+	defer f.Close()
+
+	// Read one byte past the limit so ValidateResult can report the overflow.
+	data, err := io.ReadAll(io.LimitReader(f, harnesstype.MaxResultFileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read result file: %w", err)
+	}
+
+	if err := harnesstype.ValidateResult(data); err != nil {
+		return nil, err //nolint:wrapcheck // already describes the result file
+	}
+
+	return json.RawMessage(data), nil
+}
+
+// encodeOutput attaches the job's result file to output, when the job wrote
+// one, and validates and encodes the result payload.
+func (s *jobScratch) encodeOutput(output harnesstype.JobOutput) (map[string]any, error) {
+	result, err := s.readResult()
+	if err != nil {
+		return nil, err
+	}
+
+	if carrier, ok := output.(harnesstype.ResultCarrier); ok && result != nil {
+		carrier.SetResult(result)
+	}
+
+	return harnesstype.EncodeOutput(output) //nolint:wrapcheck // validation errors are reported as-is
+}
@@ -0,0 +1,90 @@
+//go:build unix
+
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestPrepareScratch(t *testing.T) {
+	job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{Environment: map[string]string{"KEEP": "1"}}}
+	original := job.Execution.Environment
+
+	scratch, cleanup, err := prepareScratch(job)
+	if err != nil {
+		t.Fatalf("prepareScratch() error = %v", err)
+	}
+
+	env := job.Execution.Environment
+	if env["KEEP"] != "1" || env[harnesstype.EnvJobID] != "job-1" || env[harnesstype.EnvResultFile] != scratch.resultFile {
+		t.Fatalf("environment = %v", env)
+	}
+
+	if _, ok := original[harnesstype.EnvTmpDir]; ok {
+		t.Fatal("prepareScratch() modified the claimed job's environment map in place")
+	}
+
+	for _, dir := range []string{env[harnesstype.EnvTmpDir], env[harnesstype.EnvArtifactsDir]} {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			t.Fatalf("scratch directory %s missing: %v", dir, err)
+		}
+	}
+
+	cleanup()
+
+	if _, err := os.Stat(scratch.root); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("cleanup left %s behind: %v", scratch.root, err)
+	}
+}
+
+func TestJobScratch_EncodeOutput(t *testing.T) {
+	scratch, cleanup, err := prepareScratch(&client.Job{ID: "job-1"})
+	if err != nil {
+		t.Fatalf("prepareScratch() error = %v", err)
+	}
+	defer cleanup()
+
+	output := harnesstype.NewAgentJobOutput("done", 0)
+
+	data, err := scratch.encodeOutput(output)
+	if err != nil {
+		t.Fatalf("encodeOutput() without a result file error = %v", err)
+	}
+
+	if _, ok := data["result"]; ok {
+		t.Fatalf("result = %v, want omitted", data["result"])
+	}
+
+	if err := os.WriteFile(scratch.resultFile, []byte(`{"tests":"passed"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err = scratch.encodeOutput(output)
+	if err != nil {
+		t.Fatalf("encodeOutput() error = %v", err)
+	}
+
+	if got, _ := json.Marshal(data["result"]); string(got) != `{"tests":"passed"}` {
+		t.Fatalf("result = %s", got)
+	}
+
+	for name, content := range map[string]string{
+		"not an object": `["a"]`,
+		"too large":     `{"x":"` + strings.Repeat("a", harnesstype.MaxResultFileBytes) + `"}`,
+	} {
+		if err := os.WriteFile(scratch.resultFile, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := scratch.encodeOutput(harnesstype.NewAgentJobOutput("done", 0)); !errors.Is(err, harnesstype.ErrInvalidOutput) {
+			t.Errorf("%s: encodeOutput() error = %v, want ErrInvalidOutput", name, err)
+		}
+	}
+}
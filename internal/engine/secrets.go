@@ -0,0 +1,43 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/secrets"
+)
+
+// resolveSecrets replaces the secret references in job's environment with
+// the secrets they name, read from this machine's secret managers. It
+// returns the failure reason to report when a reference can't be resolved.
+func (e *Engine) resolveSecrets(ctx context.Context, job *client.Job) (string, error) {
+	if job.Execution == nil || len(job.Execution.Environment) == 0 {
+		return "", nil
+	}
+
+	resolver := secrets.NewResolver(e.config().SecretAllowlist())
+
+	names, err := resolver.ResolveEnvironment(ctx, job.Execution.Environment)
+	if err != nil {
+		if errors.Is(err, secrets.ErrNotAllowed) {
+			return "secret_not_allowed", err
+		}
+
+		return "secret_unavailable", err
+	}
+
+	if len(names) > 0 {
+		observability.FromContext(ctx).Info("job secrets resolved",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.secrets.resolved"),
+			slog.Any("secret.variables", names),
+		)
+	}
+
+	return "", nil
+}
@@ -0,0 +1,66 @@
+//go:build unix
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestEngine_ResolvesSecrets(t *testing.T) {
+	t.Setenv("MUSH_TEST_TOKEN", "s3cret")
+	t.Setenv("MUSHER_SECRETS_ALLOW", "env://MUSH_TEST_TOKEN")
+
+	var got string
+
+	executor := &fakeExecutor{run: func(job *client.Job) {
+		got = job.Execution.Environment["TOKEN"]
+	}}
+
+	eng, platform := newTestEngine(t, executor)
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test","environment":{"TOKEN":"env://MUSH_TEST_TOKEN"}}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	if got != "s3cret" {
+		t.Fatalf("TOKEN = %q, want the resolved secret", got)
+	}
+}
+
+func TestEngine_FailsOnDisallowedSecret(t *testing.T) {
+	t.Setenv("MUSH_TEST_TOKEN", "s3cret")
+	t.Setenv("MUSHER_SECRETS_ALLOW", "")
+
+	executor := &fakeExecutor{run: func(*client.Job) {
+		t.Error("job ran with an unresolved secret reference")
+	}}
+
+	eng, platform := newTestEngine(t, executor)
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test","environment":{"TOKEN":"env://MUSH_TEST_TOKEN"}}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobFailed)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+
+	platform.mu.Lock()
+	defer platform.mu.Unlock()
+
+	if len(platform.failed) != 1 || platform.failed[0].ErrorCode != "secret_not_allowed" || platform.failed[0].ShouldRetry {
+		t.Fatalf("failed = %+v, want one non-retryable secret_not_allowed", platform.failed)
+	}
+}
@@ -1,22 +1,27 @@
-// Package harness provides a PTY-based TUI for embedding Claude Code.
-package harness
+package engine
 
-// ConnectionStatus represents the connection state.
-type ConnectionStatus int
+// Status represents the engine's connection state.
+type Status int
 
-// ConnectionStatus values.
+// Status values.
 const (
-	StatusDisconnected ConnectionStatus = iota
+	StatusDisconnected Status = iota
 	StatusConnecting
 	StatusStarting
 	StatusReady
 	StatusConnected
 	StatusProcessing
 	StatusError
+	// StatusPaused means claims are held by the worktree guard or a
+	// platform maintenance window.
+	StatusPaused
+	// StatusLocalTask means claims are held while the user works in the
+	// harness session between jobs.
+	StatusLocalTask
 )
 
 // String returns a human-readable status.
-func (s ConnectionStatus) String() string {
+func (s Status) String() string {
 	switch s {
 	case StatusDisconnected:
 		return "Disconnected"
@@ -32,6 +37,10 @@ func (s ConnectionStatus) String() string {
 		return "Processing"
 	case StatusError:
 		return "Error"
+	case StatusPaused:
+		return "Paused"
+	case StatusLocalTask:
+		return "Local task"
 	default:
 		return "Unknown"
 	}
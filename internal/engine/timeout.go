@@ -0,0 +1,54 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// warnBeforeTimeout tells the harness to wrap up once the job is within the
+// queue's timeout warning of execTimeout, so a job that would be cut off
+// leaves a summary of its partial work. It returns a func that cancels the
+// warning; executors that can't take one mid-job are left alone.
+func (e *Engine) warnBeforeTimeout(ctx context.Context, executor harnesstype.Executor, execTimeout time.Duration) func() {
+	warner, ok := executor.(harnesstype.TimeoutWarner)
+	if !ok {
+		return func() {}
+	}
+
+	warning := e.config().TimeoutWarning(e.queueSlug, e.queueID)
+	if warning <= 0 || warning >= execTimeout {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(execTimeout-warning, func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		logger := observability.FromContext(ctx).With(slog.String("component", "engine"))
+
+		if err := warner.WarnTimeout(warning); err != nil {
+			logger.Warn("timeout warning not delivered",
+				slog.String("event.type", "job.timeout.warning_error"),
+				slog.String("error", err.Error()),
+			)
+
+			return
+		}
+
+		logger.Info("harness warned of timeout",
+			slog.String("event.type", "job.timeout.warning"),
+			slog.Int64("job.remaining_ms", warning.Milliseconds()),
+		)
+		e.ReportError(SeverityWarning, fmt.Sprintf("Job times out in %s; asked the harness to wrap up", warning))
+	})
+
+	return func() { timer.Stop() }
+}
@@ -0,0 +1,59 @@
+//go:build unix
+
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type warnedExecutor struct {
+	fakeExecutor
+
+	warned chan time.Duration
+}
+
+func (e *warnedExecutor) WarnTimeout(remaining time.Duration) error {
+	e.warned <- remaining
+
+	return nil
+}
+
+func TestWarnBeforeTimeout(t *testing.T) {
+	t.Setenv("MUSHER_WORKER_TIMEOUT_WARNING", "1s")
+
+	executor := &warnedExecutor{warned: make(chan time.Duration, 1)}
+	eng, _ := newTestEngine(t, executor)
+
+	stop := eng.warnBeforeTimeout(t.Context(), executor, 1100*time.Millisecond)
+	defer stop()
+
+	select {
+	case remaining := <-executor.warned:
+		if remaining != time.Second {
+			t.Fatalf("WarnTimeout(%v), want 1s remaining", remaining)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout warning was not sent")
+	}
+
+	if got := eng.Stats().LastError; got == "" {
+		t.Fatal("expected the warning in the error history")
+	}
+}
+
+func TestWarnBeforeTimeout_SkipsShortTimeouts(t *testing.T) {
+	t.Setenv("MUSHER_WORKER_TIMEOUT_WARNING", "1s")
+
+	executor := &warnedExecutor{warned: make(chan time.Duration, 1)}
+	eng, _ := newTestEngine(t, executor)
+
+	stop := eng.warnBeforeTimeout(t.Context(), executor, 500*time.Millisecond)
+	stop()
+
+	select {
+	case remaining := <-executor.warned:
+		t.Fatalf("WarnTimeout(%v) for a timeout shorter than the warning", remaining)
+	case <-time.After(700 * time.Millisecond):
+	}
+}
@@ -0,0 +1,86 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+const (
+	// usagePollInterval is how often a running job's usage is sampled.
+	usagePollInterval = 2 * time.Second
+	// usageWarnFraction is the share of a limit at which a warning is raised.
+	usageWarnFraction = 0.8
+)
+
+// JobUsage is the running job's usage and the limits from its constraints.
+// A zero limit means the job has none.
+type JobUsage struct {
+	harnesstype.Usage
+
+	MaxTurns     int
+	MaxBudgetUSD float64
+}
+
+// NearTurnLimit reports whether the job has used most of its turns.
+func (u *JobUsage) NearTurnLimit() bool {
+	return u.MaxTurns > 0 && float64(u.Turns) >= usageWarnFraction*float64(u.MaxTurns)
+}
+
+// NearBudgetLimit reports whether the job has spent most of its budget.
+func (u *JobUsage) NearBudgetLimit() bool {
+	return u.MaxBudgetUSD > 0 && u.CostKnown && u.CostUSD >= usageWarnFraction*u.MaxBudgetUSD
+}
+
+// usageLoop samples the executor's usage for the running job until ctx is
+// canceled, warning once per limit when the job nears it.
+func (e *Engine) usageLoop(ctx context.Context, job *client.Job, reporter harnesstype.UsageReporter) {
+	ticker := time.NewTicker(usagePollInterval)
+	defer ticker.Stop()
+
+	limits := JobUsage{}
+	if job.Execution != nil && job.Execution.Constraints != nil {
+		limits.MaxTurns = job.Execution.Constraints.MaxTurns
+		limits.MaxBudgetUSD = job.Execution.Constraints.MaxBudgetUSD
+	}
+
+	var warnedTurns, warnedBudget bool
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		usage, ok := reporter.JobUsage()
+		if !ok {
+			continue
+		}
+
+		current := limits
+		current.Usage = usage
+
+		// Checked under the lock so a sample can't outlive the job.
+		e.statusMu.Lock()
+		if ctx.Err() == nil {
+			e.jobUsage = &current
+		}
+		e.statusMu.Unlock()
+
+		if !warnedTurns && current.NearTurnLimit() {
+			warnedTurns = true
+			e.ReportError(SeverityWarning, fmt.Sprintf("Job %s has used %d of %d turns", job.ID, current.Turns, current.MaxTurns))
+		}
+
+		if !warnedBudget && current.NearBudgetLimit() {
+			warnedBudget = true
+			e.ReportError(SeverityWarning, fmt.Sprintf("Job %s has spent $%.2f of its $%.2f budget", job.ID, current.CostUSD, current.MaxBudgetUSD))
+		}
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unix
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestJobUsage_NearLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		usage      JobUsage
+		wantTurns  bool
+		wantBudget bool
+	}{
+		{name: "no limits", usage: JobUsage{Usage: harnesstype.Usage{Turns: 500, CostUSD: 90, CostKnown: true}}},
+		{name: "below threshold", usage: JobUsage{Usage: harnesstype.Usage{Turns: 31}, MaxTurns: 40}},
+		{name: "turns near limit", usage: JobUsage{Usage: harnesstype.Usage{Turns: 32}, MaxTurns: 40}, wantTurns: true},
+		{
+			name:       "budget near limit",
+			usage:      JobUsage{Usage: harnesstype.Usage{CostUSD: 4, CostKnown: true}, MaxBudgetUSD: 5},
+			wantBudget: true,
+		},
+		{name: "cost unknown", usage: JobUsage{MaxBudgetUSD: 5}},
+	}
+
+	for _, tt := range tests {
+		if got := tt.usage.NearTurnLimit(); got != tt.wantTurns {
+			t.Errorf("%s: NearTurnLimit() = %v, want %v", tt.name, got, tt.wantTurns)
+		}
+
+		if got := tt.usage.NearBudgetLimit(); got != tt.wantBudget {
+			t.Errorf("%s: NearBudgetLimit() = %v, want %v", tt.name, got, tt.wantBudget)
+		}
+	}
+}
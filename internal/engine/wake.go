@@ -0,0 +1,40 @@
+package engine
+
+import "time"
+
+const (
+	// wakeCheckInterval is how often the engine checks for a sleep gap.
+	wakeCheckInterval = 5 * time.Second
+	// wakeGapThreshold is how late a check must run before it is treated as
+	// a system sleep rather than scheduling jitter.
+	wakeGapThreshold = 30 * time.Second
+)
+
+// sleepDetector spots system sleep (laptop lid close, SIGSTOP) between
+// periodic checks. Depending on the platform, the monotonic clock either
+// pauses or keeps running while suspended, so it compares both the monotonic
+// and the wall-clock progress against the expected check interval.
+type sleepDetector struct {
+	interval  time.Duration
+	threshold time.Duration
+	last      time.Time
+}
+
+func newSleepDetector(now time.Time, interval, threshold time.Duration) *sleepDetector {
+	return &sleepDetector{interval: interval, threshold: threshold, last: now}
+}
+
+// observe records a check at now and reports how long the process appears to
+// have been suspended since the previous check.
+func (d *sleepDetector) observe(now time.Time) (time.Duration, bool) {
+	monotonic := now.Sub(d.last)
+	wall := now.Round(0).Sub(d.last.Round(0))
+	d.last = now
+
+	gap := max(monotonic, wall) - d.interval
+	if gap < d.threshold {
+		return 0, false
+	}
+
+	return gap, true
+}
@@ -0,0 +1,45 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSleepDetector(t *testing.T) {
+	start := time.Now()
+
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		wantGap time.Duration
+		wantOK  bool
+	}{
+		{name: "on schedule", elapsed: 5 * time.Second},
+		{name: "jitter", elapsed: 20 * time.Second},
+		{name: "suspended", elapsed: 5*time.Second + 10*time.Minute, wantGap: 10 * time.Minute, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detector := newSleepDetector(start, 5*time.Second, 30*time.Second)
+
+			gap, ok := detector.observe(start.Add(tt.elapsed))
+			if ok != tt.wantOK || gap != tt.wantGap {
+				t.Fatalf("observe() = (%v, %v), want (%v, %v)", gap, ok, tt.wantGap, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSleepDetector_WallClockGap(t *testing.T) {
+	// Go cannot construct a monotonic reading that lags the wall clock, so
+	// strip both readings to exercise the wall-clock comparison that catches
+	// platforms where the monotonic clock pauses during suspend.
+	start := time.Now().Round(0)
+	detector := newSleepDetector(start, 5*time.Second, 30*time.Second)
+
+	gap, ok := detector.observe(start.Add(time.Hour))
+	if !ok || gap != time.Hour-5*time.Second {
+		t.Fatalf("observe() = (%v, %v), want (%v, true)", gap, ok, time.Hour-5*time.Second)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// jobWebhook posts one job's lifecycle to the webhook named in its claim.
+// A nil *jobWebhook posts nothing.
+type jobWebhook struct {
+	engine  *Engine
+	job     *client.Job
+	hook    *client.WebhookConfig
+	started time.Time
+
+	// mu serializes deliveries so events arrive in order; finished drops
+	// progress that would land after the final event.
+	mu       sync.Mutex
+	finished bool
+}
+
+// newJobWebhook returns job's webhook, or nil when it has none. A config the
+// runner cannot use is reported as a warning and the job runs without it.
+func (e *Engine) newJobWebhook(ctx context.Context, job *client.Job) *jobWebhook {
+	if job.WebhookError != "" {
+		observability.FromContext(ctx).Warn("webhook config ignored",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.webhook.unsupported"),
+			slog.String("error", job.WebhookError),
+		)
+		e.ReportError(SeverityWarning, "Webhook ignored: "+job.WebhookError)
+	}
+
+	if job.WebhookConfig == nil {
+		return nil
+	}
+
+	return &jobWebhook{engine: e, job: job, hook: job.WebhookConfig, started: e.now()}
+}
+
+// currentWebhook returns the running job's webhook, or nil.
+func (e *Engine) currentWebhook() *jobWebhook {
+	e.jobMu.Lock()
+	defer e.jobMu.Unlock()
+
+	return e.jobWebhook
+}
+
+// progressLoop posts job.progress every heartbeat interval until ctx is
+// canceled.
+func (w *jobWebhook) progressLoop(ctx context.Context) {
+	if w == nil || !w.hook.Wants(client.WebhookJobProgress) {
+		return
+	}
+
+	ticker := time.NewTicker(w.engine.config().HeartbeatInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.post(ctx, client.WebhookJobProgress, nil)
+		}
+	}
+}
+
+// post delivers event, with failure set for job.failed. Delivery errors are
+// reported as warnings and never affect the job.
+func (w *jobWebhook) post(ctx context.Context, event client.WebhookEvent, failure *Failure) {
+	if w == nil || !w.hook.Wants(event) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.finished {
+		return
+	}
+
+	if event == client.WebhookJobCompleted || event == client.WebhookJobFailed {
+		w.finished = true
+	}
+
+	now := w.engine.now()
+	payload := &client.WebhookPayload{
+		Event:         event,
+		JobID:         w.job.ID,
+		QueueID:       w.job.QueueID,
+		AttemptNumber: w.job.AttemptNumber,
+		Time:          now.UTC(),
+		ElapsedMs:     now.Sub(w.started).Milliseconds(),
+	}
+
+	if usage := w.engine.Stats().Usage; usage != nil {
+		payload.Turns = usage.Turns
+
+		if usage.CostKnown {
+			cost := usage.CostUSD
+			payload.CostUSD = &cost
+		}
+	}
+
+	if failure != nil {
+		payload.ErrorCode = failure.Code
+		payload.ErrorMessage = failure.Message
+	}
+
+	if err := w.engine.client.PostWebhook(ctx, w.hook, payload); err != nil {
+		observability.FromContext(ctx).Warn("webhook delivery failed",
+			slog.String("component", "engine"),
+			slog.String("event.type", "job.webhook.error"),
+			slog.String("webhook.event", string(event)),
+			slog.String("error", err.Error()),
+		)
+		w.engine.ReportError(SeverityWarning, "Webhook delivery failed: "+err.Error())
+	}
+}
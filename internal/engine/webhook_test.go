@@ -0,0 +1,126 @@
+//go:build unix
+
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// webhookReceiver records the events posted to it.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	payloads []client.WebhookPayload
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var payload client.WebhookPayload
+	_ = json.NewDecoder(req.Body).Decode(&payload)
+
+	r.mu.Lock()
+	r.payloads = append(r.payloads, payload)
+	r.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *webhookReceiver) events() []client.WebhookEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	events := make([]client.WebhookEvent, 0, len(r.payloads))
+	for _, payload := range r.payloads {
+		events = append(events, payload.Event)
+	}
+
+	return events
+}
+
+func TestEngine_PostsWebhookEvents(t *testing.T) {
+	tests := []struct {
+		name     string
+		executor *fakeExecutor
+		wait     EventType
+		want     []client.WebhookEvent
+	}{
+		{
+			name:     "completed",
+			executor: &fakeExecutor{},
+			wait:     EventJobCompleted,
+			want:     []client.WebhookEvent{client.WebhookJobStarted, client.WebhookJobCompleted},
+		},
+		{
+			name:     "failed",
+			executor: &fakeExecutor{err: &harnesstype.ExecError{Reason: "execution_error", Message: "boom"}},
+			wait:     EventJobFailed,
+			want:     []client.WebhookEvent{client.WebhookJobStarted, client.WebhookJobFailed},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := &webhookReceiver{}
+			hookSrv := httptest.NewServer(receiver)
+			t.Cleanup(hookSrv.Close)
+
+			eng, platform := newTestEngine(t, tt.executor)
+			platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"},` +
+				`"webhookConfig":{"url":"` + hookSrv.URL + `"}}`
+
+			if err := eng.Start(t.Context()); err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+
+			waitForEvent(t, eng.Events(), tt.wait)
+
+			if err := eng.Drain(t.Context()); err != nil {
+				t.Fatalf("Drain() error = %v", err)
+			}
+
+			if got := receiver.events(); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("webhook events = %v, want %v", got, tt.want)
+			}
+
+			receiver.mu.Lock()
+			defer receiver.mu.Unlock()
+
+			last := receiver.payloads[len(receiver.payloads)-1]
+			if last.JobID != "job-1" {
+				t.Fatalf("JobID = %q, want job-1", last.JobID)
+			}
+
+			if tt.wait == EventJobFailed && !strings.Contains(last.ErrorMessage, "boom") {
+				t.Fatalf("ErrorMessage = %q, want the failure", last.ErrorMessage)
+			}
+		})
+	}
+}
+
+func TestEngine_WarnsAboutUnusableWebhook(t *testing.T) {
+	eng, platform := newTestEngine(t, &fakeExecutor{})
+	platform.claimBody = `{"job":{"id":"job-1"},"execution":{"harnessType":"test"},` +
+		`"webhookConfig":{"url":"https://hooks.example.com","events":["job.paused"]}}`
+
+	if err := eng.Start(t.Context()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	ev := waitForEvent(t, eng.Events(), EventError)
+	if !strings.Contains(ev.Message, `Webhook ignored: webhook event "job.paused" is not supported`) {
+		t.Fatalf("error event = %q, want the unsupported webhook", ev.Message)
+	}
+
+	waitForEvent(t, eng.Events(), EventJobCompleted)
+
+	if err := eng.Drain(t.Context()); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+}
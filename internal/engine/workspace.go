@@ -0,0 +1,70 @@
+//go:build unix || windows
+
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/workspace"
+)
+
+// prepareWorkspace checks the job's repository out into a fresh worktree of
+// the warm cache and points the job's working directory at it. Jobs without a
+// repository are left alone. The returned cleanup removes the worktree.
+func (e *Engine) prepareWorkspace(ctx context.Context, job *client.Job) (func(), error) {
+	if job.Execution == nil || job.Execution.Repository == nil {
+		return func() {}, nil
+	}
+
+	repo := job.Execution.Repository
+
+	cache := e.workspaces
+	if cache == nil {
+		defaultCache, err := workspace.NewCache()
+		if err != nil {
+			return nil, fmt.Errorf("prepare workspace: %w", err)
+		}
+
+		cache = defaultCache
+	}
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "engine"))
+	started := e.now()
+
+	worktree, err := cache.Checkout(ctx, repo.URL, repo.Ref, job.ID)
+	if err != nil {
+		return nil, fmt.Errorf("prepare workspace for %s: %w", repo.URL, err)
+	}
+
+	workDir := worktree.Path
+	if rel := job.Execution.WorkingDirectory; rel != "" {
+		if !filepath.IsLocal(rel) {
+			_ = worktree.Remove(context.WithoutCancel(ctx))
+			return nil, fmt.Errorf("prepare workspace: working directory %q must be relative to the repository", rel)
+		}
+
+		workDir = filepath.Join(worktree.Path, rel)
+	}
+
+	job.Execution.WorkingDirectory = workDir
+
+	logger.Info("workspace ready",
+		slog.String("event.type", "job.workspace.ready"),
+		slog.String("workspace.commit", worktree.Commit),
+		slog.Int64("workspace.duration_ms", e.now().Sub(started).Milliseconds()),
+	)
+
+	return func() {
+		if err := worktree.Remove(context.WithoutCancel(ctx)); err != nil {
+			logger.Warn("workspace cleanup failed",
+				slog.String("event.type", "job.workspace.cleanup_error"),
+				slog.String("error", err.Error()),
+			)
+		}
+	}, nil
+}
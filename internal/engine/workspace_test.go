@@ -0,0 +1,82 @@
+//go:build unix
+
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/workspace"
+)
+
+func TestPrepareWorkspace_RunsJobInWorktree(t *testing.T) {
+	origin := t.TempDir()
+	gitInit(t, origin)
+
+	eng := New(&Options{Config: config.Load(), Workspaces: workspace.NewCacheAt(t.TempDir())})
+	job := &client.Job{
+		ID: "job-1",
+		Execution: &client.ExecutionConfig{
+			WorkingDirectory: "services/api",
+			Repository:       &client.RepositoryConfig{URL: origin},
+		},
+	}
+
+	cleanup, err := eng.prepareWorkspace(t.Context(), job)
+	if err != nil {
+		t.Fatalf("prepareWorkspace() error = %v", err)
+	}
+
+	workDir := job.Execution.WorkingDirectory
+	if filepath.Base(workDir) != "api" || !filepath.IsAbs(workDir) {
+		t.Fatalf("WorkingDirectory = %q, want services/api inside the worktree", workDir)
+	}
+
+	worktreeRoot := filepath.Dir(filepath.Dir(workDir))
+	if _, err := os.Stat(filepath.Join(worktreeRoot, ".git")); err != nil {
+		t.Fatalf("worktree not checked out at %s: %v", worktreeRoot, err)
+	}
+
+	cleanup()
+
+	if _, err := os.Stat(worktreeRoot); !os.IsNotExist(err) {
+		t.Fatalf("worktree still present after cleanup (err = %v)", err)
+	}
+}
+
+func TestPrepareWorkspace_RejectsEscapingWorkingDirectory(t *testing.T) {
+	origin := t.TempDir()
+	gitInit(t, origin)
+
+	eng := New(&Options{Config: config.Load(), Workspaces: workspace.NewCacheAt(t.TempDir())})
+	job := &client.Job{
+		ID: "job-1",
+		Execution: &client.ExecutionConfig{
+			WorkingDirectory: "../outside",
+			Repository:       &client.RepositoryConfig{URL: origin},
+		},
+	}
+
+	if _, err := eng.prepareWorkspace(t.Context(), job); err == nil {
+		t.Fatal("prepareWorkspace() accepted a working directory outside the repository")
+	}
+}
+
+func TestPrepareWorkspace_NoRepository(t *testing.T) {
+	eng := New(&Options{Config: config.Load()})
+	job := &client.Job{ID: "job-1", Execution: &client.ExecutionConfig{WorkingDirectory: "/srv/app"}}
+
+	cleanup, err := eng.prepareWorkspace(t.Context(), job)
+	if err != nil {
+		t.Fatalf("prepareWorkspace() error = %v", err)
+	}
+
+	cleanup()
+
+	if job.Execution.WorkingDirectory != "/srv/app" {
+		t.Fatalf("WorkingDirectory = %q, want unchanged", job.Execution.WorkingDirectory)
+	}
+}
@@ -5,8 +5,10 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"strings"
 )
 
@@ -16,6 +18,7 @@ const (
 	tlsDocURL        = errorDocsBaseURL + "#err-net-001-tls-certificate-trust-failure"
 	clockDocURL      = errorDocsBaseURL + "#err-net-002-clock-skew"
 	queueDocURL      = errorDocsBaseURL + "#err-queue-001-no-active-queue-instruction"
+	queueMCPDocURL   = errorDocsBaseURL + "#err-queue-002-required-mcp-provider-not-connected"
 )
 
 // Exit codes for CLI errors.
@@ -28,6 +31,7 @@ const (
 	ExitTimeout   = 5  // Execution timeout
 	ExitExecution = 6  // Execution failure
 	ExitUsage     = 64 // Command line usage error (BSD convention)
+	ExitTempFail  = 75 // Temporary failure; retrying later may succeed (BSD EX_TEMPFAIL)
 )
 
 // CLIError represents a user-facing CLI error with actionable guidance.
@@ -109,6 +113,12 @@ func (e *CLIError) WithRequestID(requestID string) *CLIError {
 	return e
 }
 
+// Retryable reports whether the error is temporary, so running the command
+// again later may succeed.
+func (e *CLIError) Retryable() bool {
+	return e.Code == ExitTempFail
+}
+
 // As is a convenience function for errors.As with CLIError.
 func As(err error, target **CLIError) bool {
 	return errors.As(err, target)
@@ -191,6 +201,27 @@ func CannotPrompt(envVar string) *CLIError {
 	}
 }
 
+// PromptRequired returns an error when a value would be chosen
+// interactively but prompts are unavailable. flag and envVar name the
+// non-interactive equivalents.
+func PromptRequired(subject, flag, envVar string) *CLIError {
+	return &CLIError{
+		Message: fmt.Sprintf("Cannot prompt for %s in non-interactive mode", subject),
+		Hint:    fmt.Sprintf("Pass %s or set the %s environment variable", flag, envVar),
+		Code:    ExitUsage,
+	}
+}
+
+// ConfirmationRequired returns an error when an action needs confirmation
+// but prompts are unavailable.
+func ConfirmationRequired(action string) *CLIError {
+	return &CLIError{
+		Message: fmt.Sprintf("Cannot confirm %s in non-interactive mode", action),
+		Hint:    "Use --force to skip confirmation",
+		Code:    ExitUsage,
+	}
+}
+
 // HabitatNotFound returns an error for an unknown habitat.
 func HabitatNotFound(name string) *CLIError {
 	return &CLIError{
@@ -200,6 +231,16 @@ func HabitatNotFound(name string) *CLIError {
 	}
 }
 
+// HabitatAmbiguous returns an error when input matches several habitats and
+// prompts are unavailable to pick one.
+func HabitatAmbiguous(input string, candidates []string) *CLIError {
+	return &CLIError{
+		Message: fmt.Sprintf("Habitat %q matches %d habitats: %s", input, len(candidates), strings.Join(candidates, ", ")),
+		Hint:    "Pass the habitat's slug or ID to --habitat",
+		Code:    ExitUsage,
+	}
+}
+
 // NoHabitats returns an error when no habitats exist in the organization.
 func NoHabitats() *CLIError {
 	return &CLIError{
@@ -218,6 +259,16 @@ func QueueNotFound(name string) *CLIError {
 	}
 }
 
+// QueueAmbiguous returns an error when input matches several queues and
+// prompts are unavailable to pick one.
+func QueueAmbiguous(input string, candidates []string) *CLIError {
+	return &CLIError{
+		Message: fmt.Sprintf("Queue %q matches %d queues: %s", input, len(candidates), strings.Join(candidates, ", ")),
+		Hint:    "Pass the queue's slug or ID to --queue",
+		Code:    ExitUsage,
+	}
+}
+
 // NoQueuesForHabitat returns an error when a habitat has no eligible queues.
 func NoQueuesForHabitat() *CLIError {
 	return &CLIError{
@@ -227,6 +278,56 @@ func NoQueuesForHabitat() *CLIError {
 	}
 }
 
+// MCPProvidersNotConnected returns an error when a queue's instruction needs
+// MCP providers that the runner config does not provide, so every job would
+// fail at tool-call time.
+func MCPProvidersNotConnected(instructionName string, providers []string) *CLIError {
+	names := make([]string, 0, len(providers))
+	for _, provider := range providers {
+		names = append(names, providerDisplayName(provider))
+	}
+
+	var label string
+
+	switch len(names) {
+	case 0:
+		label = "Required"
+	case 1:
+		label = names[0]
+	case 2:
+		label = names[0] + " and " + names[1]
+	default:
+		label = strings.Join(names[:len(names)-1], ", ") + ", and " + names[len(names)-1]
+	}
+
+	instruction := "This queue's instruction"
+	if instructionName != "" {
+		instruction = fmt.Sprintf("Instruction %q", instructionName)
+	}
+
+	pronoun, integration := "it", "integration"
+	if len(names) > 1 {
+		pronoun, integration = "them", "integrations"
+	}
+
+	return &CLIError{
+		Message: label + " MCP not connected for this workspace",
+		Hint: fmt.Sprintf("%s needs %s. Connect the %s in the console (for this workspace or habitat), then rerun 'mush worker start'. See: %s",
+			instruction, pronoun, integration, queueMCPDocURL),
+		Code:      ExitConfig,
+		ErrorCode: "ERR-QUEUE-002",
+	}
+}
+
+// providerDisplayName capitalizes a provider key such as "linear" for messages.
+func providerDisplayName(provider string) string {
+	if provider == "" {
+		return provider
+	}
+
+	return strings.ToUpper(provider[:1]) + provider[1:]
+}
+
 // NoInstructionsForQueue returns an error when no active instruction exists for a queue.
 func NoInstructionsForQueue(queueName, queueSlug string) *CLIError {
 	label := queueName
@@ -254,7 +355,7 @@ func NoInstructionsForQueue(queueName, queueSlug string) *CLIError {
 func HabitatRequired() *CLIError {
 	return &CLIError{
 		Message: "Habitat required",
-		Hint:    "Run 'mush habitat list' to see available habitats",
+		Hint:    "Pass --habitat or set MUSH_HABITAT; run 'mush habitat list' to see available habitats",
 		Code:    ExitConfig,
 	}
 }
@@ -263,7 +364,7 @@ func HabitatRequired() *CLIError {
 func QueueRequired() *CLIError {
 	return &CLIError{
 		Message: "Queue required",
-		Hint:    "Use --queue to specify a queue, or run without --no-input to select interactively",
+		Hint:    "Pass --queue or set MUSH_QUEUE, or run without --no-input to select interactively",
 		Code:    ExitUsage,
 	}
 }
@@ -296,14 +397,43 @@ func JobNotFound(jobID string) *CLIError {
 	}
 }
 
+// NetworkFailed wraps a failed API request. A cause that may clear up on its
+// own, such as a timeout, a refused connection, or a 408, 429, or 5xx answer,
+// gets ExitTempFail; one the platform will answer the same way again gets
+// ExitNetwork.
+func NetworkFailed(message string, cause error) *CLIError {
+	code := ExitNetwork
+	if transient(cause) {
+		code = ExitTempFail
+	}
+
+	return Wrap(code, message, cause)
+}
+
 // WorkerRegistrationFailed returns an error when worker registration fails.
 func WorkerRegistrationFailed(cause error) *CLIError {
-	return enrichFromCause(&CLIError{
-		Message: "Failed to register worker",
-		Hint:    "Check your network connection and API credentials",
-		Cause:   cause,
-		Code:    ExitNetwork,
-	})
+	return NetworkFailed("Failed to register worker", cause).
+		WithHint("Check your network connection and API credentials")
+}
+
+// WorkerNotRunning returns an error when no local worker answers on the
+// control socket.
+func WorkerNotRunning() *CLIError {
+	return &CLIError{
+		Message: "No worker is running",
+		Hint:    "Start one with 'mush worker start'",
+		Code:    ExitGeneral,
+	}
+}
+
+// DetachedWorkerRunning returns an error when a detached worker is already
+// running and another is asked for.
+func DetachedWorkerRunning(pid int) *CLIError {
+	return &CLIError{
+		Message: fmt.Sprintf("A detached worker is already running (pid %d)", pid),
+		Hint:    "Follow it with 'mush worker logs --follow', or stop it with 'mush worker stop'",
+		Code:    ExitGeneral,
+	}
 }
 
 // ExecutionTimedOut returns an error for execution timeout with context.
@@ -481,6 +611,33 @@ func errorString(err error) string {
 	return err.Error()
 }
 
+type statusCodeCause interface {
+	StatusCode() int
+}
+
+// transient reports whether a failed request may succeed when sent again
+// later.
+func transient(cause error) bool {
+	if cause == nil || errors.Is(cause, context.Canceled) {
+		return false
+	}
+
+	var statusCause statusCodeCause
+	if errors.As(cause, &statusCause) {
+		switch status := statusCause.StatusCode(); status {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests:
+			return true
+		default:
+			return status >= http.StatusInternalServerError
+		}
+	}
+
+	// No answer at all. Certificate failures stay until the trust setup is
+	// fixed; anything else, such as a refused connection or a timeout, may
+	// clear up.
+	return !containsAny(errorString(cause), "certificate", "x509")
+}
+
 type requestIDCause interface {
 	RequestIDValue() string
 }
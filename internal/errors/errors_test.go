@@ -1,6 +1,8 @@
 package errors
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -181,15 +183,21 @@ func TestAllErrorsHaveHints(t *testing.T) {
 		{"AuthFailed", AuthFailed(nil)},
 		{"CredentialsInvalid", CredentialsInvalid(nil)},
 		{"CannotPrompt", CannotPrompt("TEST_VAR")},
+		{"PromptRequired", PromptRequired("a value", "--flag", "TEST_VAR")},
+		{"ConfirmationRequired", ConfirmationRequired("an action")},
 		{"HabitatNotFound", HabitatNotFound("test")},
+		{"HabitatAmbiguous", HabitatAmbiguous("web", []string{"web-a", "web-b"})},
 		{"NoHabitats", NoHabitats()},
 		{"QueueNotFound", QueueNotFound("queue-123")},
+		{"QueueAmbiguous", QueueAmbiguous("triage", []string{"triage-a", "triage-b"})},
 		{"NoQueuesForHabitat", NoQueuesForHabitat()},
 		{"HabitatRequired", HabitatRequired()},
 		{"APIKeyEmpty", APIKeyEmpty()},
 		{"ConfigFailed", ConfigFailed("test operation", nil)},
 		{"JobNotFound", JobNotFound("job-123")},
 		{"WorkerRegistrationFailed", WorkerRegistrationFailed(nil)},
+		{"WorkerNotRunning", WorkerNotRunning()},
+		{"DetachedWorkerRunning", DetachedWorkerRunning(4242)},
 		{"ExecutionTimedOut", ExecutionTimedOut("1m", nil)},
 		{"ClaudeExecutionFailed", ClaudeExecutionFailed(1, "error message")},
 		{"ClaudeSignalKilled", ClaudeSignalKilled()},
@@ -266,6 +274,45 @@ func TestWrap(t *testing.T) {
 	}
 }
 
+// statusError stands in for an API error carrying the platform's answer.
+type statusError int
+
+func (e statusError) Error() string   { return fmt.Sprintf("request failed with status %d", int(e)) }
+func (e statusError) StatusCode() int { return int(e) }
+
+func TestNetworkFailed(t *testing.T) {
+	tests := []struct {
+		name  string
+		cause error
+		want  int
+	}{
+		{"no cause", nil, ExitNetwork},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:443: connect: connection refused"), ExitTempFail},
+		{"deadline", fmt.Errorf("claim: %w", context.DeadlineExceeded), ExitTempFail},
+		{"canceled", fmt.Errorf("claim: %w", context.Canceled), ExitNetwork},
+		{"certificate", errors.New("tls: failed to verify certificate: x509: unknown authority"), ExitNetwork},
+		{"rate limited", fmt.Errorf("claim: %w", statusError(429)), ExitTempFail},
+		{"unavailable", statusError(503), ExitTempFail},
+		{"request timeout", statusError(408), ExitTempFail},
+		{"forbidden", statusError(403), ExitNetwork},
+		{"not found", statusError(404), ExitNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NetworkFailed("Failed to fetch jobs", tt.cause)
+
+			if err.Code != tt.want {
+				t.Errorf("code = %d, want %d", err.Code, tt.want)
+			}
+
+			if err.Retryable() != (tt.want == ExitTempFail) {
+				t.Errorf("Retryable() = %v for code %d", err.Retryable(), err.Code)
+			}
+		})
+	}
+}
+
 // formatCLIError produces a deterministic string representation of a CLIError for golden file comparison.
 func formatCLIError(err *CLIError) string {
 	return fmt.Sprintf("Message: %s\nHint: %s\nCode: %d\n", err.Message, err.Hint, err.Code)
@@ -280,17 +327,23 @@ func TestErrorMessages_Golden(t *testing.T) {
 		{"AuthFailed", AuthFailed(nil)},
 		{"CredentialsInvalid", CredentialsInvalid(nil)},
 		{"CannotPrompt", CannotPrompt("MUSHER_API_KEY")},
+		{"PromptRequired", PromptRequired("a habitat", "--habitat", "MUSH_HABITAT")},
+		{"ConfirmationRequired", ConfirmationRequired("uninstall")},
 		{"HabitatNotFound", HabitatNotFound("prod-habitat")},
 		{"NoHabitats", NoHabitats()},
 		{"QueueNotFound", QueueNotFound("queue-123")},
 		{"NoQueuesForHabitat", NoQueuesForHabitat()},
 		{"NoInstructionsForQueue", NoInstructionsForQueue("My Queue", "my-queue")},
+		{"MCPProvidersNotConnected", MCPProvidersNotConnected("Triage", []string{"linear"})},
+		{"MCPProvidersNotConnected_Multiple", MCPProvidersNotConnected("", []string{"linear", "github", "sentry"})},
 		{"HabitatRequired", HabitatRequired()},
 		{"QueueRequired", QueueRequired()},
 		{"APIKeyEmpty", APIKeyEmpty()},
 		{"ConfigFailed", ConfigFailed("store credentials", nil)},
 		{"JobNotFound", JobNotFound("job-abc-123")},
 		{"WorkerRegistrationFailed", WorkerRegistrationFailed(nil)},
+		{"WorkerNotRunning", WorkerNotRunning()},
+		{"DetachedWorkerRunning", DetachedWorkerRunning(4242)},
 		{"ExecutionTimedOut_NoTools", ExecutionTimedOut("5m0s", nil)},
 		{"ExecutionTimedOut_WithTools", ExecutionTimedOut("5m0s", []string{"Read", "Bash", "Edit"})},
 		{"ClaudeExecutionFailed_RateLimit", ClaudeExecutionFailed(1, "rate limit exceeded")},
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
@@ -24,13 +24,39 @@ var builtins = []harnesstype.Module{
 
 func init() {
 	for _, mod := range builtins {
-		Register(Info{
-			Name:      mod.Spec.Name,
-			Available: harnesstype.AvailableFunc(mod.Spec),
-			New:       mod.NewExecutor,
-			MCPSpec:   mod.MCPSpec,
-		})
-
-		registerProviderSpec(mod.Spec)
+		registerModule(mod)
 	}
 }
+
+// registerModule registers a provider module's executor and spec.
+func registerModule(mod harnesstype.Module) {
+	Register(Info{
+		Name:      mod.Spec.Name,
+		Available: harnesstype.AvailableFunc(mod.Spec),
+		New:       mod.NewExecutor,
+		MCPSpec:   mod.MCPSpec,
+	})
+
+	registerProviderSpec(mod.Spec)
+}
+
+// newExecutor returns a new executor for the harness type in info. With a
+// rehearsal transcript, Claude jobs are replayed from it instead.
+func newExecutor(info *Info, rehearsal string) harnesstype.Executor {
+	if rehearsal != "" && info.Name == claude.Module.Spec.Name {
+		return claude.NewRehearsal(rehearsal)
+	}
+
+	return info.New()
+}
+
+// RehearsalPrompts returns how many prompts the Claude session transcript
+// at path records, or why it can't be replayed.
+func RehearsalPrompts(path string) (int, error) {
+	turns, err := claude.ReadRehearsal(path)
+	if err != nil {
+		return 0, err //nolint:wrapcheck // ReadRehearsal errors name the transcript
+	}
+
+	return len(turns), nil
+}
@@ -1,8 +1,9 @@
-//go:build !unix
+//go:build !unix && !windows
 
 package harness
 
 import (
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 	"github.com/musher-dev/mush/internal/harness/providers/claude"
 	"github.com/musher-dev/mush/internal/harness/providers/codex"
 	"github.com/musher-dev/mush/internal/harness/providers/copilot"
@@ -19,3 +20,9 @@ func init() {
 	registerProviderSpec(gemini.Module.Spec)
 	registerProviderSpec(opencode.Module.Spec)
 }
+
+// registerModule registers a provider module's spec; executors need a Unix
+// pty or a Windows pseudo console.
+func registerModule(mod harnesstype.Module) {
+	registerProviderSpec(mod.Spec)
+}
@@ -0,0 +1,166 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+// statusFileInterval is how often a running worker rewrites its status file.
+const statusFileInterval = 2 * time.Second
+
+// workerControl answers `mush worker status`, `mush worker stop`,
+// `mush worker reload`, and `mush worker observe` for a running engine, and
+// keeps its status file current.
+type workerControl struct {
+	eng       *engine.Engine
+	output    *outputFanout
+	reload    chan<- struct{}
+	habitatID string
+	queueID   string
+	startedAt time.Time
+}
+
+func (c *workerControl) ControlStatus() worker.ControlStatus {
+	return c.controlStatus(c.eng.Stats())
+}
+
+func (c *workerControl) controlStatus(stats engine.Stats) worker.ControlStatus {
+	return worker.ControlStatus{
+		PID:       os.Getpid(),
+		WorkerID:  stats.WorkerID,
+		HabitatID: c.habitatID,
+		QueueID:   c.queueID,
+		Status:    stats.Status.String(),
+		JobID:     stats.JobID,
+		Completed: stats.Completed,
+		Failed:    stats.Failed,
+		StartedAt: c.startedAt,
+		Stopping:  stats.StopReason != "",
+	}
+}
+
+func (c *workerControl) RequestStop() {
+	c.eng.Stop()
+}
+
+// RequestReload queues a config reload for the runtime's signal loop. A
+// reload already queued covers this one.
+func (c *workerControl) RequestReload() {
+	select {
+	case c.reload <- struct{}{}:
+	default:
+	}
+}
+
+func (c *workerControl) TerminalSize() (cols, rows int) {
+	return c.output.TerminalSize()
+}
+
+func (c *workerControl) SubscribeOutput() (recent []byte, output <-chan []byte, cancel func()) {
+	return c.output.SubscribeOutput()
+}
+
+// statusFile returns the engine's state for the status file at now.
+func (c *workerControl) statusFile(now time.Time) *worker.StatusFile {
+	stats := c.eng.Stats()
+	status := &worker.StatusFile{
+		ControlStatus: c.controlStatus(stats),
+		LastError:     stats.LastError,
+		UpdatedAt:     now.UTC(),
+	}
+
+	if !stats.JobStartedAt.IsZero() {
+		started := stats.JobStartedAt.UTC()
+		status.JobStartedAt = &started
+	}
+
+	if stats.JobID != "" && !stats.LastHeartbeat.IsZero() {
+		heartbeat := stats.LastHeartbeat.UTC()
+		age := int64(now.Sub(stats.LastHeartbeat) / time.Second)
+		status.LastHeartbeat = &heartbeat
+		status.HeartbeatAgeSeconds = &age
+	}
+
+	return status
+}
+
+// statusFileLoop rewrites the status file every statusFileInterval until ctx
+// is canceled. Write errors are logged once, not every interval.
+func (c *workerControl) statusFileLoop(ctx context.Context, logger *slog.Logger) {
+	ticker := time.NewTicker(statusFileInterval)
+	defer ticker.Stop()
+
+	failing := false
+
+	for {
+		err := worker.WriteStatusFile(c.statusFile(time.Now()))
+		if err != nil && !failing {
+			logger.Warn("worker status file not written", slog.String("event.type", "worker.status_file_error"), slog.String("error", err.Error()))
+		}
+
+		failing = err != nil
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// listenWorkerControl serves the control socket, with output for observers
+// and reload requests sent to reload, and writes the status file for eng
+// until the returned function is called.
+// A worker that cannot take the socket keeps running without either, so the
+// file always describes the worker that `mush worker status` reaches.
+func listenWorkerControl(ctx context.Context, logger *slog.Logger, eng *engine.Engine, output *outputFanout, reload chan<- struct{}, habitatID, queueID string) func() {
+	control := &workerControl{
+		eng:       eng,
+		output:    output,
+		reload:    reload,
+		habitatID: habitatID,
+		queueID:   queueID,
+		startedAt: time.Now(),
+	}
+
+	srv, err := worker.ListenControl(ctx, control)
+	if err != nil {
+		msg := "worker control socket unavailable"
+		if errors.Is(err, worker.ErrWorkerRunning) {
+			msg = "another worker owns the control socket; `mush worker stop` will not reach this one"
+		}
+
+		logger.Warn(msg, slog.String("event.type", "worker.control_error"), slog.String("error", err.Error()))
+
+		return func() {}
+	}
+
+	statusCtx, stopStatus := context.WithCancel(ctx)
+	statusDone := make(chan struct{})
+
+	go func() {
+		defer close(statusDone)
+		control.statusFileLoop(statusCtx, logger)
+	}()
+
+	return func() {
+		stopStatus()
+		<-statusDone
+
+		if removeErr := worker.RemoveStatusFile(); removeErr != nil {
+			logger.Warn("worker status file not removed", slog.String("event.type", "worker.status_file_error"), slog.String("error", removeErr.Error()))
+		}
+
+		if closeErr := srv.Close(); closeErr != nil {
+			logger.Warn("worker control socket close failed", slog.String("event.type", "worker.control_error"), slog.String("error", closeErr.Error()))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+//go:build unix
+
+package harness
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func TestListenWorkerControl_WritesStatusFile(t *testing.T) {
+	// Unix socket paths are short, so the runtime root can't be t.TempDir().
+	runtimeDir, err := os.MkdirTemp("", "mushrt")
+	if err != nil {
+		t.Fatalf("MkdirTemp() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(runtimeDir) })
+
+	stateDir := t.TempDir()
+	t.Setenv("MUSHER_HOME", "")
+	t.Setenv("MUSHER_STATE_HOME", "")
+	t.Setenv("MUSHER_RUNTIME_DIR", "")
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv("XDG_STATE_HOME", stateDir)
+
+	path := filepath.Join(stateDir, "musher", "worker-status.json")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eng := engine.New(&engine.Options{InitialStatus: engine.StatusConnected})
+
+	stop := listenWorkerControl(t.Context(), logger, eng, newOutputFanout(80, 24), make(chan struct{}, 1), "hab-1", "queue-1")
+
+	var status worker.StatusFile
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, readErr := os.ReadFile(path)
+		if readErr == nil {
+			if err := json.Unmarshal(data, &status); err != nil {
+				t.Fatalf("decode %q: %v", data, err)
+			}
+
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("status file not written: %v", readErr)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if status.PID != os.Getpid() || status.Status != "Connected" || status.HabitatID != "hab-1" || status.QueueID != "queue-1" {
+		t.Fatalf("status = %+v, want this idle worker", status)
+	}
+
+	if status.JobStartedAt != nil || status.HeartbeatAgeSeconds != nil {
+		t.Fatalf("status = %+v, want no job fields while idle", status)
+	}
+
+	stop()
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("status file after stop: %v, want it removed", err)
+	}
+}
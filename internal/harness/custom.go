@@ -0,0 +1,88 @@
+package harness
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/harness/providers/shell"
+)
+
+// customNamePattern keeps custom harness names usable as --harness values.
+var customNamePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// customSources records the file each custom harness was loaded from, so
+// loading the same directory again registers nothing twice.
+var (
+	customMu      sync.Mutex
+	customSources = map[string]string{}
+)
+
+// LoadCustomHarnesses registers the shell harnesses defined by the *.yaml
+// files in dir. A missing dir is not an error. Files that fail to parse,
+// lack a shell block, or reuse another harness's name are skipped and
+// reported in the returned error; the rest are still registered.
+func LoadCustomHarnesses(dir string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return fmt.Errorf("list custom harnesses: %w", err)
+	}
+
+	var errs []error
+
+	for _, path := range files {
+		if err := loadCustomHarness(path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", filepath.Base(path), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func loadCustomHarness(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read harness definition: %w", err)
+	}
+
+	spec, err := harnesstype.ParseSpec(data)
+	if err != nil {
+		return err //nolint:wrapcheck // ParseSpec errors name the provider
+	}
+
+	if spec.Shell == nil {
+		return fmt.Errorf("provider %s: a shell block is required", spec.Name)
+	}
+
+	if !customNamePattern.MatchString(spec.Name) {
+		return fmt.Errorf("provider %s: name must be lowercase letters, digits, and dashes", spec.Name)
+	}
+
+	if spec.DisplayName == "" {
+		spec.DisplayName = spec.Name
+	}
+
+	customMu.Lock()
+	defer customMu.Unlock()
+
+	if source, ok := customSources[spec.Name]; ok {
+		if source == path {
+			return nil
+		}
+
+		return fmt.Errorf("provider %s: already defined in %s", spec.Name, filepath.Base(source))
+	}
+
+	if _, ok := GetProvider(spec.Name); ok {
+		return fmt.Errorf("provider %s: name is taken by a built-in harness", spec.Name)
+	}
+
+	registerModule(shell.NewModule(spec))
+	customSources[spec.Name] = path
+
+	return nil
+}
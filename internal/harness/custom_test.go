@@ -0,0 +1,67 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadCustomHarnesses(t *testing.T) {
+	dir := t.TempDir()
+
+	writeDefinition := func(file, body string) {
+		t.Helper()
+
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(body), 0o600); err != nil {
+			t.Fatalf("write %s: %v", file, err)
+		}
+	}
+
+	writeDefinition("aider.yaml", "name: test-aider\nbinary: aider\nshell:\n  prompt: arg\n  promptFlag: --message\n")
+	writeDefinition("copy.yaml", "name: test-aider\nbinary: aider-next\nshell: {}\n")
+	writeDefinition("claude.yaml", "name: claude\nbinary: claude\nshell: {}\n")
+	writeDefinition("plain.yaml", "name: test-plain\nbinary: plain\n")
+	writeDefinition("notes.txt", "not a definition")
+
+	err := LoadCustomHarnesses(dir)
+	if err == nil {
+		t.Fatal("LoadCustomHarnesses() error = nil, want the skipped definitions")
+	}
+
+	for _, want := range []string{
+		"copy.yaml: provider test-aider: already defined in aider.yaml",
+		"claude.yaml: provider claude: name is taken by a built-in harness",
+		"plain.yaml: provider test-plain: a shell block is required",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadCustomHarnesses() error = %v, want %q", err, want)
+		}
+	}
+
+	info, ok := Lookup("test-aider")
+	if !ok {
+		t.Fatal("Lookup(test-aider) = false, want the custom harness registered")
+	}
+
+	if info.New() == nil {
+		t.Fatal("custom harness has no executor")
+	}
+
+	if spec, ok := GetProvider("test-aider"); !ok || spec.Binary != "aider" {
+		t.Fatalf("GetProvider(test-aider) = %+v, %v; want the aider definition", spec, ok)
+	}
+
+	// Loading the directory again must not register anything twice.
+	if err := LoadCustomHarnesses(dir); err != nil && strings.Contains(err.Error(), "aider.yaml:") {
+		t.Fatalf("reload error = %v, want aider.yaml to load cleanly", err)
+	}
+}
+
+func TestLoadCustomHarnesses_MissingDir(t *testing.T) {
+	if err := LoadCustomHarnesses(filepath.Join(t.TempDir(), "missing")); err != nil {
+		t.Fatalf("LoadCustomHarnesses() error = %v, want nil", err)
+	}
+}
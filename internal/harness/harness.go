@@ -1,10 +1,11 @@
-//go:build unix
+//go:build unix || windows
 
 // Package harness provides the interactive watch runtime for harness executors.
 package harness
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -22,6 +23,7 @@ type Config struct {
 	Client             *client.Client
 	HabitatID          string
 	QueueID            string
+	QueueSlug          string
 	SupportedHarnesses []string
 	InstanceID         string
 	RunnerConfig       *client.RunnerConfigResponse
@@ -29,17 +31,78 @@ type Config struct {
 	TranscriptDir      string
 	TranscriptLines    int
 
-	// ForceSidebar skips the LR margin probe and assumes sidebar support.
+	// RequireTranscript fails the session with ErrTranscriptUnavailable
+	// when the transcript store cannot be opened, instead of running
+	// without history.
+	RequireTranscript bool
+
+	// RunnerConfigStale marks RunnerConfig as a cached last-known-good copy,
+	// so the first platform refresh is scheduled as early as allowed.
+	RunnerConfigStale bool
+
+	// ForceSidebar shows the sidebar even when the terminal profile hides
+	// it.
 	ForceSidebar bool
 
+	// Rehearsal is a recorded Claude session transcript. When set, Claude
+	// jobs are answered by replaying it instead of running claude.
+	Rehearsal string
+
+	// MaxJobs ends the worker session once this many jobs have been
+	// processed. Zero keeps polling until the user exits.
+	MaxJobs int
+
+	// MaxDuration and IdleTimeout end the worker session once it has run
+	// that long, or gone that long without a job, after finishing any job
+	// in progress. Zero disables either limit.
+	MaxDuration time.Duration
+	IdleTimeout time.Duration
+
+	// MetricsAddr is the address to serve Prometheus metrics on at
+	// /metrics, such as "127.0.0.1:9464". Empty serves none.
+	MetricsAddr string
+
+	// OnWorkerExit, if set, is called with the session's results after the
+	// worker has drained and deregistered.
+	OnWorkerExit func(WorkerSummary)
+
 	// BundleLoadMode runs a single interactive session instead of polling for jobs.
-	BundleLoadMode bool
-	BundleName     string // for status bar display
-	BundleVer      string // for status bar display
-	BundleDir      string // temp dir with harness-native asset structure
-	BundleWorkDir  string // working directory for interactive bundle sessions
-	BundleEnv      []string
-	BundleSummary  BundleSummary
+	BundleLoadMode  bool
+	BundleName      string   // for status bar display
+	BundleVer       string   // for status bar display
+	BundleDir       string   // temp dir with harness-native asset structure
+	BundleExtraDirs []string // further directories passed after BundleDir
+	BundleWorkDir   string   // working directory for interactive bundle sessions
+	BundleEnv       []string
+	BundleSummary   BundleSummary
+
+	// BundleReload re-resolves the bundle and refreshes the session's assets
+	// in place. When set, F5 calls it and restarts harnesses that need it.
+	BundleReload func(ctx context.Context) (*BundleReload, error)
+}
+
+// ErrTranscriptUnavailable is returned when Config.RequireTranscript is set
+// and the session's transcript store cannot be opened.
+var ErrTranscriptUnavailable = errors.New("transcript unavailable")
+
+// WorkerSummary describes how a worker session ended.
+type WorkerSummary struct {
+	// StopReason is set when the session ended on its own, such as after
+	// MaxJobs or IdleTimeout, or through `mush worker stop`; it is empty
+	// when the user or a signal ended it.
+	StopReason string
+	Completed  int
+	Failed     int
+
+	// Requeued counts the Failed jobs the platform takes back to run again.
+	Requeued int
+}
+
+// BundleReload describes the bundle a load session was reloaded to.
+type BundleReload struct {
+	Version  string
+	Summary  BundleSummary
+	Warnings []string
 }
 
 // BundleSummary captures loaded bundle component names for sidebar rendering.
@@ -68,6 +131,11 @@ func LoadedMCPServers(cfg *client.RunnerConfigResponse, now time.Time) []string
 	return harnesstype.LoadedMCPProviderNames(cfg, now)
 }
 
+// MissingMCPServers returns the required MCP providers that are not loaded.
+func MissingMCPServers(cfg *client.RunnerConfigResponse, required []string, now time.Time) []string {
+	return harnesstype.MissingMCPProviders(cfg, required, now)
+}
+
 // genericDirNames are directory names that are too generic to use as display
 // names for bundle assets. When a SKILL.md or AGENT.md file is nested inside
 // one of these directories, we walk further up the path to find a descriptive
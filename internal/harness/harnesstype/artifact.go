@@ -0,0 +1,26 @@
+package harnesstype
+
+// ArtifactRef points to a file uploaded with a job's result.
+type ArtifactRef struct {
+	ID string `json:"id"`
+
+	// Name is the file's path relative to the directory it was collected
+	// from.
+	Name        string `json:"name"`
+	ContentType string `json:"contentType,omitempty"`
+	SizeBytes   int64  `json:"sizeBytes"`
+
+	// URL is where the platform serves the file, when it returned one.
+	URL string `json:"url,omitempty"`
+}
+
+// ArtifactCarrier is implemented by job outputs that can carry references
+// to uploaded artifacts.
+type ArtifactCarrier interface {
+	SetArtifacts(artifacts []ArtifactRef)
+}
+
+// SetArtifacts implements ArtifactCarrier.
+func (o *AgentJobOutput) SetArtifacts(artifacts []ArtifactRef) {
+	o.Artifacts = artifacts
+}
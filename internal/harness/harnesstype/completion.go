@@ -0,0 +1,214 @@
+package harnesstype
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+// Completion modes a provider spec can declare.
+const (
+	// CompletionSignalFile completes when a hook creates a file in the signal directory.
+	CompletionSignalFile = "signal_file"
+	// CompletionHookJSON is CompletionSignalFile where the hook writes its JSON input to the file.
+	CompletionHookJSON = "hook_json"
+	// CompletionOutputMarker completes when the harness prints a marker string.
+	CompletionOutputMarker = "output_marker"
+	// CompletionProcessExit completes when the harness process exits.
+	CompletionProcessExit = "process_exit"
+)
+
+// CompletionPollInterval is how often file-based detectors check for the signal file.
+const CompletionPollInterval = 200 * time.Millisecond
+
+// ErrHarnessStopped is returned by CompletionDetector.Wait when the executor
+// shuts down before the job completes.
+var ErrHarnessStopped = errors.New("harness stopped")
+
+// Completion is what a detector learned when the job finished.
+type Completion struct {
+	// Payload is the hook's JSON input for CompletionHookJSON, otherwise nil.
+	Payload json.RawMessage
+}
+
+// CompletionDetector tells a long-running harness executor when the job it
+// injected has finished. Executors feed it output and exit notifications;
+// each detector uses only the events its mode depends on.
+type CompletionDetector interface {
+	// Reset clears anything left over from the previous job.
+	Reset()
+
+	// Observe is called with each chunk of harness output.
+	Observe(p []byte)
+
+	// Exited is called when the harness process exits.
+	Exited()
+
+	// Wait blocks until the job completes, ctx is done, or stop is closed.
+	Wait(ctx context.Context, stop <-chan struct{}) (*Completion, error)
+}
+
+// NewCompletionDetector builds the detector a provider spec declares.
+// File-based modes resolve their file inside signalDir.
+func NewCompletionDetector(spec *CompletionSpec, signalDir string) (CompletionDetector, error) {
+	if spec == nil {
+		return nil, errors.New("provider spec has no completion block")
+	}
+
+	switch spec.Mode {
+	case CompletionSignalFile, CompletionHookJSON:
+		if signalDir == "" {
+			return nil, fmt.Errorf("completion mode %s requires a signal directory", spec.Mode)
+		}
+
+		return &fileDetector{
+			path:     filepath.Join(signalDir, spec.File),
+			wantJSON: spec.Mode == CompletionHookJSON,
+		}, nil
+	case CompletionOutputMarker:
+		return &markerDetector{marker: []byte(spec.Marker), seen: make(chan struct{}, 1)}, nil
+	case CompletionProcessExit:
+		return &exitDetector{exited: make(chan struct{})}, nil
+	default:
+		return nil, fmt.Errorf("unknown completion mode %q", spec.Mode)
+	}
+}
+
+// fileDetector polls for a file created by a harness hook.
+type fileDetector struct {
+	path     string
+	wantJSON bool
+}
+
+func (d *fileDetector) Reset() {
+	_ = os.Remove(d.path)
+}
+
+func (d *fileDetector) Observe([]byte) {}
+
+func (d *fileDetector) Exited() {}
+
+func (d *fileDetector) Wait(ctx context.Context, stop <-chan struct{}) (*Completion, error) {
+	ticker := time.NewTicker(CompletionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for completion canceled: %w", ctx.Err())
+		case <-stop:
+			return nil, ErrHarnessStopped
+		case <-ticker.C:
+		}
+
+		data, err := os.ReadFile(d.path)
+		if err != nil {
+			continue
+		}
+
+		completion := &Completion{}
+
+		if d.wantJSON {
+			// The hook may still be writing; try again on the next tick.
+			if !json.Valid(data) {
+				continue
+			}
+
+			completion.Payload = json.RawMessage(data)
+		}
+
+		_ = os.Remove(d.path)
+
+		return completion, nil
+	}
+}
+
+// markerDetector watches harness output for a marker string. It matches on
+// text with escape sequences removed, so a styled marker still matches, and
+// keeps a tail of the previous chunk so a marker split across reads does too.
+type markerDetector struct {
+	marker []byte
+	seen   chan struct{}
+
+	mu       sync.Mutex
+	tail     []byte
+	stripper ansi.Stripper
+}
+
+func (d *markerDetector) Reset() {
+	d.mu.Lock()
+	d.tail = nil
+	d.mu.Unlock()
+
+	select {
+	case <-d.seen:
+	default:
+	}
+}
+
+func (d *markerDetector) Observe(p []byte) {
+	if len(d.marker) == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	window := append(d.tail, d.stripper.Strip(p)...)
+	found := bytes.Contains(window, d.marker)
+
+	keep := min(len(window), len(d.marker)-1)
+	d.tail = append([]byte(nil), window[len(window)-keep:]...)
+	d.mu.Unlock()
+
+	if found {
+		select {
+		case d.seen <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (d *markerDetector) Exited() {}
+
+func (d *markerDetector) Wait(ctx context.Context, stop <-chan struct{}) (*Completion, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for completion canceled: %w", ctx.Err())
+	case <-stop:
+		return nil, ErrHarnessStopped
+	case <-d.seen:
+		return &Completion{}, nil
+	}
+}
+
+// exitDetector completes when the harness process exits.
+type exitDetector struct {
+	once   sync.Once
+	exited chan struct{}
+}
+
+func (d *exitDetector) Reset() {}
+
+func (d *exitDetector) Observe([]byte) {}
+
+func (d *exitDetector) Exited() {
+	d.once.Do(func() { close(d.exited) })
+}
+
+func (d *exitDetector) Wait(ctx context.Context, stop <-chan struct{}) (*Completion, error) {
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("wait for completion canceled: %w", ctx.Err())
+	case <-stop:
+		return nil, ErrHarnessStopped
+	case <-d.exited:
+		return &Completion{}, nil
+	}
+}
@@ -0,0 +1,152 @@
+package harnesstype
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func waitCompletion(t *testing.T, detector CompletionDetector) (*Completion, error) {
+	t.Helper()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 2*time.Second)
+	defer cancel()
+
+	return detector.Wait(ctx, nil)
+}
+
+func TestCompletionDetector_SignalFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "complete")
+
+	detector, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionSignalFile, File: "complete"}, dir)
+	if err != nil {
+		t.Fatalf("NewCompletionDetector() error = %v", err)
+	}
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	detector.Reset()
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Reset() left signal file behind: %v", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(path, nil, 0o600)
+	}()
+
+	completion, err := waitCompletion(t, detector)
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if completion.Payload != nil {
+		t.Fatalf("Payload = %s, want nil", completion.Payload)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Wait() did not consume signal file: %v", err)
+	}
+}
+
+func TestCompletionDetector_HookJSON(t *testing.T) {
+	dir := t.TempDir()
+
+	detector, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionHookJSON, File: "stop.json"}, dir)
+	if err != nil {
+		t.Fatalf("NewCompletionDetector() error = %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "stop.json"), []byte(`{"session_id":"abc"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	completion, err := waitCompletion(t, detector)
+	if err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	if string(completion.Payload) != `{"session_id":"abc"}` {
+		t.Fatalf("Payload = %s, want hook input", completion.Payload)
+	}
+}
+
+func TestCompletionDetector_OutputMarkerAcrossChunks(t *testing.T) {
+	detector, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionOutputMarker, Marker: "<<DONE>>"}, "")
+	if err != nil {
+		t.Fatalf("NewCompletionDetector() error = %v", err)
+	}
+
+	detector.Observe([]byte("working... <<DO"))
+	detector.Observe([]byte("NE>> trailing"))
+
+	if _, err := waitCompletion(t, detector); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+
+	detector.Observe([]byte("<<DONE>>"))
+	detector.Reset()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 50*time.Millisecond)
+	defer cancel()
+
+	if _, err := detector.Wait(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait() after Reset() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestCompletionDetector_OutputMarkerStyled(t *testing.T) {
+	detector, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionOutputMarker, Marker: "<<DONE>>"}, "")
+	if err != nil {
+		t.Fatalf("NewCompletionDetector() error = %v", err)
+	}
+
+	detector.Observe([]byte("\x1b[32m<<DO\x1b"))
+	detector.Observe([]byte("[1mNE>>\x1b[0m"))
+
+	if _, err := waitCompletion(t, detector); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestCompletionDetector_ProcessExitAndStop(t *testing.T) {
+	detector, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionProcessExit}, "")
+	if err != nil {
+		t.Fatalf("NewCompletionDetector() error = %v", err)
+	}
+
+	stop := make(chan struct{})
+	close(stop)
+
+	if _, err := detector.Wait(t.Context(), stop); !errors.Is(err, ErrHarnessStopped) {
+		t.Fatalf("Wait() with stopped harness error = %v, want ErrHarnessStopped", err)
+	}
+
+	detector.Exited()
+	detector.Exited()
+
+	if _, err := waitCompletion(t, detector); err != nil {
+		t.Fatalf("Wait() error = %v", err)
+	}
+}
+
+func TestNewCompletionDetector_Errors(t *testing.T) {
+	if _, err := NewCompletionDetector(nil, "/tmp"); err == nil {
+		t.Fatal("expected error for nil spec")
+	}
+
+	if _, err := NewCompletionDetector(&CompletionSpec{Mode: CompletionSignalFile, File: "complete"}, ""); err == nil {
+		t.Fatal("expected error for signal file without a signal directory")
+	}
+
+	if _, err := NewCompletionDetector(&CompletionSpec{Mode: "telepathy"}, ""); err == nil {
+		t.Fatal("expected error for unknown mode")
+	}
+}
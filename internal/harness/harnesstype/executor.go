@@ -2,7 +2,9 @@ package harnesstype
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"time"
 
 	"github.com/musher-dev/mush/internal/client"
 )
@@ -54,6 +56,63 @@ type InterruptHandler interface {
 	Interrupt() error
 }
 
+// UsageReporter is for executors that can report usage while a job runs.
+// JobUsage reports false when no job is running or usage is not known yet.
+type UsageReporter interface {
+	JobUsage() (Usage, bool)
+}
+
+// SupervisionReporter is for executors that supervise a long-running harness
+// process and can report how its starts and readiness waits went.
+type SupervisionReporter interface {
+	Supervision() Supervision
+}
+
+// TimeoutWarner is for executors whose harness can be told mid-job that the
+// execution timeout is near, so it can wrap up before being cut off.
+type TimeoutWarner interface {
+	WarnTimeout(remaining time.Duration) error
+}
+
+// LocalTaskRunner is for executors whose harness the user can prompt
+// directly between jobs. BeginLocalTask is called when the user starts
+// typing; WaitLocalTask blocks until the harness finishes the prompt and
+// returns its output.
+type LocalTaskRunner interface {
+	BeginLocalTask()
+	WaitLocalTask(ctx context.Context) (string, error)
+}
+
+// BundleReloader is for executors whose harness reads some bundle assets,
+// such as agents or the MCP config, only at startup. ReloadBundle restarts
+// the harness after a bundle load session reloads its assets.
+type BundleReloader interface {
+	ReloadBundle(ctx context.Context) error
+}
+
+// TimeoutWarningMessage is the note sent to a harness when remaining time is
+// left before its job times out.
+func TimeoutWarningMessage(remaining time.Duration) string {
+	return fmt.Sprintf(
+		"Note from the job runner: about %s remain before this job times out and is stopped. "+
+			"Wrap up now: don't start new work, leave the workspace in a consistent state, "+
+			"and summarize what you finished and what is left.",
+		formatRemaining(remaining),
+	)
+}
+
+func formatRemaining(d time.Duration) string {
+	if d >= time.Minute && d%time.Minute == 0 {
+		if d == time.Minute {
+			return "1 minute"
+		}
+
+		return fmt.Sprintf("%d minutes", int(d/time.Minute))
+	}
+
+	return d.Round(time.Second).String()
+}
+
 // SetupOptions contains the configuration for executor setup.
 type SetupOptions struct {
 	// TermWriter is the writer for terminal output.
@@ -72,10 +131,15 @@ type SetupOptions struct {
 	// BundleDir is the temp directory with harness-native bundle assets.
 	BundleDir string
 
+	// ExtraDirs are further directories passed after BundleDir, for
+	// providers whose spec accepts them.
+	ExtraDirs []string
+
 	// WorkingDir is the directory the interactive harness process should run in.
 	WorkingDir string
 
-	// Env is appended to the process environment for interactive bundle sessions.
+	// Env is appended to the environment of the harness processes, both the
+	// interactive session and the processes run per job.
 	Env []string
 
 	// BundleLoadMode indicates this is an interactive bundle session.
@@ -94,8 +158,8 @@ type SetupOptions struct {
 
 // ExecResult holds the result of a job execution.
 type ExecResult struct {
-	// OutputData is the structured output to report to the API.
-	OutputData map[string]any
+	// Output is the typed result payload to report to the API.
+	Output JobOutput
 }
 
 // ExecError represents a structured execution error.
@@ -106,8 +170,20 @@ type ExecError struct {
 	// Message is the human-readable error description.
 	Message string
 
-	// Retry indicates whether the job should be retried.
+	// Retry indicates whether the job should be retried. The engine's
+	// failure policy may override it for reasons it knows better.
 	Retry bool
+
+	// ExitCode is the harness process exit status when it exited non-zero,
+	// otherwise 0.
+	ExitCode int
+
+	// PartialOutput is the tail of what the harness printed before it
+	// failed; see WithPartialOutput.
+	PartialOutput string
+
+	// LastActivity is when the harness last printed anything, if known.
+	LastActivity time.Time
 }
 
 func (e *ExecError) Error() string {
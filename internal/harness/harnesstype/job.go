@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harnesstype
 
@@ -6,11 +6,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"os/exec"
 	"strings"
 
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/observability"
 )
 
 // GetPromptFromJob extracts the prompt from a job's data and execution config.
@@ -36,17 +38,24 @@ func GetPromptFromJob(job *client.Job) (string, error) {
 
 // HandleOneShotRunError converts a one-shot executor run error into an *ExecError,
 // handling context cancellation, deadline exceeded, and exit-code extraction.
-func HandleOneShotRunError(ctx context.Context, runErr error, rawOutput, name string) *ExecError {
+// The output recorded before the failure is attached as partial output.
+func HandleOneShotRunError(ctx context.Context, runErr error, output *OutputRecorder, name string) *ExecError {
+	rawOutput := output.String()
+
 	if ctxErr := ctx.Err(); ctxErr != nil {
 		if errors.Is(ctxErr, context.DeadlineExceeded) {
-			return &ExecError{Reason: "timeout", Message: fmt.Sprintf("%s execution timed out", name), Retry: true}
+			execErr := &ExecError{Reason: "timeout", Message: fmt.Sprintf("%s execution timed out", name), Retry: true}
+
+			return execErr.WithPartialOutput(rawOutput, output.LastActivity())
 		}
 
-		return &ExecError{
+		execErr := &ExecError{
 			Reason:  "execution_error",
 			Message: fmt.Sprintf("%s execution canceled: %v", name, ctxErr),
 			Retry:   true,
 		}
+
+		return execErr.WithPartialOutput(rawOutput, output.LastActivity())
 	}
 
 	exitCode := 1
@@ -56,6 +65,13 @@ func HandleOneShotRunError(ctx context.Context, runErr error, rawOutput, name st
 		exitCode = exitErr.ExitCode()
 	}
 
+	observability.FromContext(ctx).Debug("harness process exited",
+		slog.String("component", "harness"),
+		slog.String("event.type", "harness.process.exit"),
+		slog.String("harness.binary", name),
+		slog.Int("process.exit_code", exitCode),
+	)
+
 	msg := fmt.Sprintf("%s exited with code %d", name, exitCode)
 
 	cleanOutput := ansi.Strip(strings.TrimSpace(rawOutput))
@@ -63,9 +79,12 @@ func HandleOneShotRunError(ctx context.Context, runErr error, rawOutput, name st
 		msg = fmt.Sprintf("%s: %s", msg, cleanOutput)
 	}
 
-	return &ExecError{
-		Reason:  "execution_error",
-		Message: msg,
-		Retry:   true,
+	execErr := &ExecError{
+		Reason:   "execution_error",
+		Message:  msg,
+		Retry:    true,
+		ExitCode: exitCode,
 	}
+
+	return execErr.WithPartialOutput(rawOutput, output.LastActivity())
 }
@@ -0,0 +1,83 @@
+package harnesstype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+// Environment variables the engine sets for every job, whatever the harness.
+// They are added to the job's execution environment before Execute.
+const (
+	// EnvJobID is the ID of the running job.
+	EnvJobID = "MUSH_JOB_ID"
+	// EnvTmpDir is a private scratch directory, removed after the job.
+	EnvTmpDir = "MUSH_TMPDIR"
+	// EnvArtifactsDir is where the job leaves files to upload with its result.
+	EnvArtifactsDir = "MUSH_ARTIFACTS_DIR"
+	// EnvResultFile is where the job may write a JSON object that is
+	// reported as the result's "result" field.
+	EnvResultFile = "MUSH_RESULT_FILE"
+)
+
+// MaxResultFileBytes caps the JSON a job may write to EnvResultFile.
+const MaxResultFileBytes = 256 << 10
+
+// ResultCarrier is implemented by job outputs that can carry the structured
+// result a job wrote to EnvResultFile.
+type ResultCarrier interface {
+	SetResult(result json.RawMessage)
+}
+
+// SetResult implements ResultCarrier.
+func (o *AgentJobOutput) SetResult(result json.RawMessage) {
+	o.Result = result
+}
+
+// ValidateResult checks data written to EnvResultFile: a JSON object no
+// larger than MaxResultFileBytes.
+func ValidateResult(data []byte) error {
+	if len(data) > MaxResultFileBytes {
+		return fmt.Errorf("%w: result file exceeds %d bytes", ErrInvalidOutput, MaxResultFileBytes)
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	if !json.Valid(trimmed) || len(trimmed) == 0 || trimmed[0] != '{' {
+		return fmt.Errorf("%w: result file must contain a JSON object", ErrInvalidOutput)
+	}
+
+	return nil
+}
+
+// JobEnvPreamble describes the job environment in prose, for harnesses
+// whose long-running process can't be given per-job environment variables.
+// It returns "" when the engine set none.
+func JobEnvPreamble(job *client.Job) string {
+	if job == nil || job.Execution == nil {
+		return ""
+	}
+
+	env := job.Execution.Environment
+	if env[EnvTmpDir] == "" && env[EnvArtifactsDir] == "" && env[EnvResultFile] == "" {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteString("Job directories (not exported to your shell; use the paths directly):\n")
+
+	for _, entry := range []struct{ name, use string }{
+		{EnvTmpDir, "scratch space, deleted after the job"},
+		{EnvArtifactsDir, "files to upload with the job's result, such as reports or screenshots"},
+		{EnvResultFile, "optionally write a JSON object with structured results here"},
+	} {
+		if value := env[entry.name]; value != "" {
+			fmt.Fprintf(&b, "- %s=%s: %s\n", entry.name, value, entry.use)
+		}
+	}
+
+	return b.String()
+}
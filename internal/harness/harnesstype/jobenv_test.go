@@ -0,0 +1,42 @@
+package harnesstype
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+)
+
+func TestJobEnvPreamble(t *testing.T) {
+	if got := JobEnvPreamble(&client.Job{Execution: &client.ExecutionConfig{}}); got != "" {
+		t.Fatalf("JobEnvPreamble() without job directories = %q, want empty", got)
+	}
+
+	job := &client.Job{Execution: &client.ExecutionConfig{Environment: map[string]string{
+		EnvTmpDir:     "/tmp/mush-job-1/tmp",
+		EnvResultFile: "/tmp/mush-job-1/result.json",
+	}}}
+
+	got := JobEnvPreamble(job)
+	for _, want := range []string{"MUSH_TMPDIR=/tmp/mush-job-1/tmp", "MUSH_RESULT_FILE=/tmp/mush-job-1/result.json"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("JobEnvPreamble() missing %q:\n%s", want, got)
+		}
+	}
+
+	if strings.Contains(got, EnvArtifactsDir) {
+		t.Errorf("JobEnvPreamble() lists an unset directory:\n%s", got)
+	}
+}
+
+func TestValidateResult(t *testing.T) {
+	if err := ValidateResult([]byte(" {\"ok\":true}\n")); err != nil {
+		t.Fatalf("ValidateResult() error = %v", err)
+	}
+
+	for _, bad := range []string{"", "null", "[1]", "{"} {
+		if err := ValidateResult([]byte(bad)); err == nil {
+			t.Errorf("ValidateResult(%q) = nil, want error", bad)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harnesstype
 
@@ -173,6 +173,26 @@ func CreateMCPConfigFile(logger *slog.Logger, mcpSpec *MCPSpec, cfg *client.Runn
 	return path, signature, cleanup, nil
 }
 
+// MissingMCPProviders returns the providers in required that cfg does not
+// load, in the order given. Names match case-insensitively.
+func MissingMCPProviders(cfg *client.RunnerConfigResponse, required []string, now time.Time) []string {
+	loaded := make(map[string]bool)
+	for _, spec := range BuildMCPProviderSpecs(cfg, now) {
+		loaded[strings.ToLower(spec.Name)] = true
+	}
+
+	var missing []string
+
+	for _, name := range required {
+		name = strings.TrimSpace(name)
+		if name != "" && !loaded[strings.ToLower(name)] {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
+
 // LoadedMCPProviderNames returns the names of providers from a RunnerConfig that
 // pass all MCP filters.
 func LoadedMCPProviderNames(cfg *client.RunnerConfigResponse, now time.Time) []string {
@@ -0,0 +1,187 @@
+package harnesstype
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// OutputSchemaVersion is stamped on every job result payload. Bump it when a
+// field is renamed or removed, or changes meaning, so the platform can tell
+// runner versions apart.
+const OutputSchemaVersion = 1
+
+// ErrInvalidOutput is returned when a job result payload fails validation.
+var ErrInvalidOutput = errors.New("invalid job output")
+
+// JobOutput is a typed job result payload reported to the platform.
+type JobOutput interface {
+	// Validate reports whether the payload is well-formed and safe to send.
+	Validate() error
+}
+
+// AgentJobOutput is the result payload for harnesses that return a single text
+// response from a coding agent (codex, copilot, cursor, gemini, opencode) and
+// for shell harnesses. Claude reports a ClaudeJobOutput, which extends it.
+type AgentJobOutput struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Success       bool   `json:"success"`
+	Output        string `json:"output"`
+	DurationMs    int    `json:"durationMs"`
+
+	// ResultMetadata is optional git and summary context for integrations.
+	ResultMetadata *ResultMetadata `json:"resultMetadata,omitempty"`
+
+	// Result is the JSON object the job wrote to EnvResultFile, if any.
+	Result json.RawMessage `json:"result,omitempty"`
+
+	// Artifacts are the files uploaded with the result.
+	Artifacts []ArtifactRef `json:"artifacts,omitempty"`
+}
+
+// NewAgentJobOutput returns a successful AgentJobOutput at the current schema
+// version. Bytes in output that are not valid UTF-8, which terminal output
+// often carries, are replaced with U+FFFD rather than failing the job.
+func NewAgentJobOutput(output string, duration time.Duration) *AgentJobOutput {
+	return &AgentJobOutput{
+		SchemaVersion: OutputSchemaVersion,
+		Success:       true,
+		Output:        strings.ToValidUTF8(output, "\uFFFD"),
+		DurationMs:    int(duration / time.Millisecond),
+	}
+}
+
+// ClaudeJobOutput is the result payload for the claude harness: the agent
+// fields plus the session's usage, when the transcript recorded it.
+type ClaudeJobOutput struct {
+	AgentJobOutput
+
+	Usage *UsageOutput `json:"usage,omitempty"`
+}
+
+// UsageOutput is the usage reported with a ClaudeJobOutput.
+type UsageOutput struct {
+	Turns        int   `json:"turns"`
+	InputTokens  int64 `json:"inputTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+
+	// CostUSD is omitted when Claude did not record a cost.
+	CostUSD *float64 `json:"costUsd,omitempty"`
+}
+
+// NewClaudeJobOutput returns a successful ClaudeJobOutput at the current
+// schema version, cleaning output like NewAgentJobOutput.
+func NewClaudeJobOutput(output string, duration time.Duration) *ClaudeJobOutput {
+	return &ClaudeJobOutput{AgentJobOutput: *NewAgentJobOutput(output, duration)}
+}
+
+// SetUsage records the job's usage.
+func (o *ClaudeJobOutput) SetUsage(usage Usage) {
+	out := &UsageOutput{
+		Turns:        usage.Turns,
+		InputTokens:  usage.InputTokens,
+		OutputTokens: usage.OutputTokens,
+	}
+
+	if usage.CostKnown {
+		out.CostUSD = &usage.CostUSD
+	}
+
+	o.Usage = out
+}
+
+// Validate implements JobOutput.
+func (o *ClaudeJobOutput) Validate() error {
+	if err := o.AgentJobOutput.Validate(); err != nil {
+		return err
+	}
+
+	if u := o.Usage; u != nil && (u.Turns < 0 || u.InputTokens < 0 || u.OutputTokens < 0) {
+		return fmt.Errorf("%w: negative usage count", ErrInvalidOutput)
+	}
+
+	return nil
+}
+
+// Validate implements JobOutput.
+func (o *AgentJobOutput) Validate() error {
+	if o.SchemaVersion != OutputSchemaVersion {
+		return fmt.Errorf("%w: schemaVersion %d, want %d", ErrInvalidOutput, o.SchemaVersion, OutputSchemaVersion)
+	}
+
+	if o.DurationMs < 0 {
+		return fmt.Errorf("%w: negative durationMs %d", ErrInvalidOutput, o.DurationMs)
+	}
+
+	if !utf8.ValidString(o.Output) {
+		return fmt.Errorf("%w: output is not valid UTF-8", ErrInvalidOutput)
+	}
+
+	if o.Result != nil {
+		if err := ValidateResult(o.Result); err != nil {
+			return err
+		}
+	}
+
+	return o.ResultMetadata.validate()
+}
+
+// EncodeOutput validates out and converts it to the wire form sent with job completion.
+func EncodeOutput(out JobOutput) (map[string]any, error) {
+	if out == nil {
+		return nil, fmt.Errorf("%w: missing output", ErrInvalidOutput)
+	}
+
+	if err := out.Validate(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("marshal job output: %w", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("encode job output: %w", err)
+	}
+
+	return payload, nil
+}
+
+// ValidatePayload checks a wire-form payload, such as one an output processor
+// rewrote, against the schema of out's type. The payload must keep the
+// schemaVersion and output fields; fields the schema does not know are
+// allowed.
+func ValidatePayload(out JobOutput, payload map[string]any) error {
+	for _, field := range []string{"schemaVersion", "output"} {
+		if _, ok := payload[field]; !ok {
+			return fmt.Errorf("%w: missing %s", ErrInvalidOutput, field)
+		}
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal job output: %w", err)
+	}
+
+	kind := reflect.TypeOf(out)
+	if kind == nil || kind.Kind() != reflect.Pointer {
+		return fmt.Errorf("%w: unsupported output type %T", ErrInvalidOutput, out)
+	}
+
+	typed, ok := reflect.New(kind.Elem()).Interface().(JobOutput)
+	if !ok {
+		return fmt.Errorf("%w: unsupported output type %T", ErrInvalidOutput, out)
+	}
+
+	if err := json.Unmarshal(data, typed); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+
+	return typed.Validate()
+}
@@ -0,0 +1,79 @@
+package harnesstype
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestNewAgentJobOutput_ReplacesInvalidUTF8(t *testing.T) {
+	out := NewAgentJobOutput("done \xff\xfe ok", time.Second)
+
+	if err := out.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v, want the stray bytes replaced", err)
+	}
+
+	if want := "done � ok"; out.Output != want {
+		t.Fatalf("Output = %q, want %q", out.Output, want)
+	}
+}
+
+func TestClaudeJobOutput_Encode(t *testing.T) {
+	out := NewClaudeJobOutput("done", 2*time.Second)
+	out.SetUsage(Usage{Turns: 3, InputTokens: 1200, OutputTokens: 300, CostUSD: 0.25, CostKnown: true})
+
+	payload, err := EncodeOutput(out)
+	if err != nil {
+		t.Fatalf("EncodeOutput() error = %v", err)
+	}
+
+	if payload["output"] != "done" || payload["schemaVersion"] != float64(OutputSchemaVersion) {
+		t.Fatalf("payload = %v, want the agent fields at the top level", payload)
+	}
+
+	data, err := json.Marshal(payload["usage"])
+	if err != nil {
+		t.Fatalf("marshal usage: %v", err)
+	}
+
+	if want := `{"costUsd":0.25,"inputTokens":1200,"outputTokens":300,"turns":3}`; string(data) != want {
+		t.Fatalf("usage = %s, want %s", data, want)
+	}
+
+	out.SetUsage(Usage{Turns: 1})
+
+	if payload, err := EncodeOutput(out); err != nil || payload["usage"].(map[string]any)["costUsd"] != nil {
+		t.Fatalf("EncodeOutput(unknown cost) = %v, %v; want costUsd omitted", payload, err)
+	}
+}
+
+func TestValidatePayload(t *testing.T) {
+	t.Parallel()
+
+	payload, err := EncodeOutput(NewClaudeJobOutput("done", time.Second))
+	if err != nil {
+		t.Fatalf("EncodeOutput() error = %v", err)
+	}
+
+	payload["ticket"] = "ENG-1"
+	if err := ValidatePayload(&ClaudeJobOutput{}, payload); err != nil {
+		t.Fatalf("ValidatePayload(extra field) error = %v", err)
+	}
+
+	for name, mutate := range map[string]func(map[string]any){
+		"missing output":        func(p map[string]any) { delete(p, "output") },
+		"missing schemaVersion": func(p map[string]any) { delete(p, "schemaVersion") },
+		"wrong schemaVersion":   func(p map[string]any) { p["schemaVersion"] = 99 },
+		"negative usage":        func(p map[string]any) { p["usage"] = map[string]any{"turns": -1} },
+		"mistyped output":       func(p map[string]any) { p["output"] = 42 },
+	} {
+		broken := maps.Clone(payload)
+		mutate(broken)
+
+		if err := ValidatePayload(&ClaudeJobOutput{}, broken); !errors.Is(err, ErrInvalidOutput) {
+			t.Errorf("ValidatePayload(%s) error = %v, want ErrInvalidOutput", name, err)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package harnesstype
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+// MaxPartialOutputBytes caps the output attached to a failed job. The tail
+// is kept, since the last thing the harness printed says the most about
+// where it stopped.
+const MaxPartialOutputBytes = 8 << 10
+
+// OutputRecorder buffers harness output and remembers when it last arrived.
+// It is safe to share between a command's stdout and stderr.
+type OutputRecorder struct {
+	mu   sync.Mutex
+	buf  bytes.Buffer
+	last time.Time
+}
+
+// Write implements io.Writer.
+func (r *OutputRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(p) > 0 {
+		r.last = time.Now()
+	}
+
+	return r.buf.Write(p) //nolint:wrapcheck // bytes.Buffer writes never fail
+}
+
+// String returns everything written so far.
+func (r *OutputRecorder) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.buf.String()
+}
+
+// LastActivity returns when output last arrived, or the zero time if none has.
+func (r *OutputRecorder) LastActivity() time.Time {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.last
+}
+
+// WithPartialOutput attaches what the harness printed before failing to e,
+// stripped of ANSI sequences and cut to its last MaxPartialOutputBytes.
+func (e *ExecError) WithPartialOutput(raw string, lastActivity time.Time) *ExecError {
+	e.PartialOutput = TailOutput(raw, MaxPartialOutputBytes)
+	e.LastActivity = lastActivity
+
+	return e
+}
+
+// TailOutput strips ANSI sequences from raw and returns at most limit bytes
+// from its end, starting on a rune boundary. Truncated output is prefixed
+// with "…".
+func TailOutput(raw string, limit int) string {
+	clean := strings.TrimSpace(ansi.Strip(raw))
+	if len(clean) <= limit {
+		return clean
+	}
+
+	start := len(clean) - limit
+	for start < len(clean) && !utf8.RuneStart(clean[start]) {
+		start++
+	}
+
+	return "…" + clean[start:]
+}
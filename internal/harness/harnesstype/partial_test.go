@@ -0,0 +1,37 @@
+package harnesstype
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTailOutput(t *testing.T) {
+	if got := TailOutput("  \x1b[1mdone\x1b[0m\n", 100); got != "done" {
+		t.Fatalf("TailOutput() = %q, want %q", got, "done")
+	}
+
+	raw := strings.Repeat("a", 10) + "é" + "xyz"
+	if got := TailOutput(raw, 4); got != "…xyz" {
+		t.Fatalf("TailOutput() = %q, want cut after the split rune", got)
+	}
+
+	if got := TailOutput(raw, 5); got != "…éxyz" {
+		t.Fatalf("TailOutput() = %q, want %q", got, "…éxyz")
+	}
+}
+
+func TestOutputRecorder(t *testing.T) {
+	var recorder OutputRecorder
+
+	if !recorder.LastActivity().IsZero() {
+		t.Fatal("LastActivity() before any output should be zero")
+	}
+
+	before := time.Now()
+	_, _ = recorder.Write([]byte("hello"))
+
+	if recorder.String() != "hello" || recorder.LastActivity().Before(before) {
+		t.Fatalf("recorder = %q at %v", recorder.String(), recorder.LastActivity())
+	}
+}
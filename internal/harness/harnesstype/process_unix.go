@@ -0,0 +1,66 @@
+//go:build unix
+
+package harnesstype
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/creack/pty"
+)
+
+// SendSignal sends a signal to a process group first, falling back to the PID.
+func SendSignal(pid, pgid int, sig syscall.Signal) {
+	if pgid > 0 {
+		if err := syscall.Kill(-pgid, sig); err == nil || errors.Is(err, syscall.ESRCH) {
+			return
+		}
+	}
+
+	if pid <= 0 {
+		return
+	}
+
+	_ = syscall.Kill(pid, sig)
+}
+
+// ProcessGroupID returns the process group of a started cmd, or 0.
+func ProcessGroupID(cmd *exec.Cmd) int {
+	if cmd == nil || cmd.Process == nil || cmd.Process.Pid <= 0 {
+		return 0
+	}
+
+	pgid, err := syscall.Getpgid(cmd.Process.Pid)
+	if err != nil {
+		return 0
+	}
+
+	return pgid
+}
+
+// StartPTY starts cmd in a new session on a pseudo-terminal of the given
+// size. cmd.Stdin, cmd.Stdout, and cmd.Stderr must be nil; all three become
+// the terminal.
+func StartPTY(cmd *exec.Cmd, rows, cols int) (PTY, error) {
+	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{Rows: uint16(rows), Cols: uint16(cols)})
+	if err != nil {
+		return nil, err //nolint:wrapcheck // callers annotate PTY start errors
+	}
+
+	return NewPTY(ptmx), nil
+}
+
+// NewPTY wraps a pty master.
+func NewPTY(ptmx *os.File) PTY {
+	return &unixPTY{File: ptmx}
+}
+
+type unixPTY struct {
+	*os.File
+}
+
+func (p *unixPTY) Resize(rows, cols int) error {
+	return pty.Setsize(p.File, &pty.Winsize{Rows: uint16(rows), Cols: uint16(cols)}) //nolint:wrapcheck // resize errors are ignored by callers
+}
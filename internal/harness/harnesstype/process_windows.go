@@ -0,0 +1,217 @@
+//go:build windows
+
+package harnesstype
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// SendSignal delivers SIGKILL by terminating the process. Windows has no
+// other signals to send: a process in a pseudo console is asked to exit by
+// closing the console, which StopInteractiveProcess and the executors do
+// before signaling, so other signals are ignored.
+func SendSignal(pid, _ int, sig syscall.Signal) {
+	if pid <= 0 || sig != syscall.SIGKILL {
+		return
+	}
+
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return
+	}
+
+	_ = proc.Kill()
+	_ = proc.Release()
+}
+
+// ProcessGroupID returns 0: Windows has no process groups to signal.
+func ProcessGroupID(*exec.Cmd) int {
+	return 0
+}
+
+// StartPTY starts cmd attached to a new ConPTY pseudo console of the given
+// size. cmd.Stdin, cmd.Stdout, and cmd.Stderr are ignored; the console is
+// all three. The process is created directly rather than by cmd.Start, with
+// cmd.Process set so cmd.Wait works as usual.
+func StartPTY(cmd *exec.Cmd, rows, cols int) (PTY, error) {
+	if cmd.Process != nil {
+		return nil, errors.New("exec: already started")
+	}
+
+	if cmd.Err != nil {
+		return nil, cmd.Err //nolint:wrapcheck // the command's own lookup error
+	}
+
+	// The console reads the process's input from inRead and writes its
+	// output to outWrite; the harness holds the other ends.
+	var inRead, inWrite, outRead, outWrite windows.Handle
+
+	if err := windows.CreatePipe(&inRead, &inWrite, nil, 0); err != nil {
+		return nil, fmt.Errorf("create console input pipe: %w", err)
+	}
+
+	if err := windows.CreatePipe(&outRead, &outWrite, nil, 0); err != nil {
+		_ = windows.CloseHandle(inRead)
+		_ = windows.CloseHandle(inWrite)
+
+		return nil, fmt.Errorf("create console output pipe: %w", err)
+	}
+
+	p := &conPTY{
+		in:  os.NewFile(uintptr(inWrite), "conpty-input"),
+		out: os.NewFile(uintptr(outRead), "conpty-output"),
+	}
+
+	err := windows.CreatePseudoConsole(consoleSize(rows, cols), inRead, outWrite, 0, &p.console)
+
+	// The console has its own copies of these ends.
+	_ = windows.CloseHandle(inRead)
+	_ = windows.CloseHandle(outWrite)
+
+	if err != nil {
+		_ = p.in.Close()
+		_ = p.out.Close()
+
+		return nil, fmt.Errorf("create pseudo console: %w", err)
+	}
+
+	if err := startInConsole(cmd, p.console); err != nil {
+		_ = p.Close()
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func startInConsole(cmd *exec.Cmd, console windows.Handle) error {
+	attrs, err := windows.NewProcThreadAttributeList(1)
+	if err != nil {
+		return fmt.Errorf("allocate process attributes: %w", err)
+	}
+	defer attrs.Delete()
+
+	// The attribute's value is the console handle itself, not a pointer to
+	// it.
+	if err := attrs.Update(windows.PROC_THREAD_ATTRIBUTE_PSEUDOCONSOLE, *(*unsafe.Pointer)(unsafe.Pointer(&console)), unsafe.Sizeof(console)); err != nil {
+		return fmt.Errorf("attach pseudo console: %w", err)
+	}
+
+	startup := &windows.StartupInfoEx{ProcThreadAttributeList: attrs.List()}
+	startup.Cb = uint32(unsafe.Sizeof(*startup))
+
+	appName, err := windows.UTF16PtrFromString(cmd.Path)
+	if err != nil {
+		return fmt.Errorf("encode command path: %w", err)
+	}
+
+	commandLine, err := windows.UTF16PtrFromString(windows.ComposeCommandLine(cmd.Args))
+	if err != nil {
+		return fmt.Errorf("encode command line: %w", err)
+	}
+
+	var dir *uint16
+
+	if cmd.Dir != "" {
+		if dir, err = windows.UTF16PtrFromString(cmd.Dir); err != nil {
+			return fmt.Errorf("encode working directory: %w", err)
+		}
+	}
+
+	var env *uint16
+
+	if cmd.Env != nil {
+		block := environmentBlock(cmd.Env)
+		env = &block[0]
+	}
+
+	var info windows.ProcessInformation
+
+	flags := uint32(windows.EXTENDED_STARTUPINFO_PRESENT | windows.CREATE_UNICODE_ENVIRONMENT)
+	if err := windows.CreateProcess(appName, commandLine, nil, nil, false, flags, env, dir, &startup.StartupInfo, &info); err != nil {
+		return &os.PathError{Op: "start", Path: cmd.Path, Err: err}
+	}
+
+	defer func() {
+		_ = windows.CloseHandle(info.Thread)
+		_ = windows.CloseHandle(info.Process)
+	}()
+
+	// The process handle held until here keeps the PID from being reused.
+	proc, err := os.FindProcess(int(info.ProcessId))
+	if err != nil {
+		return fmt.Errorf("open started process: %w", err)
+	}
+
+	cmd.Process = proc
+
+	return nil
+}
+
+// environmentBlock encodes env for CreateProcess: each entry NUL-terminated,
+// and the block terminated by one more NUL.
+func environmentBlock(env []string) []uint16 {
+	var block []uint16
+
+	for _, entry := range env {
+		block = append(block, utf16.Encode([]rune(entry))...)
+		block = append(block, 0)
+	}
+
+	if len(env) == 0 {
+		block = append(block, 0)
+	}
+
+	return append(block, 0)
+}
+
+func consoleSize(rows, cols int) windows.Coord {
+	return windows.Coord{X: int16(max(cols, 1)), Y: int16(max(rows, 1))}
+}
+
+// conPTY is a ConPTY pseudo console and the harness's ends of its pipes.
+type conPTY struct {
+	console windows.Handle
+	in      *os.File
+	out     *os.File
+
+	closeOnce sync.Once
+}
+
+func (p *conPTY) Read(b []byte) (int, error) {
+	return p.out.Read(b) //nolint:wrapcheck // passed through unchanged
+}
+
+func (p *conPTY) Write(b []byte) (int, error) {
+	return p.in.Write(b) //nolint:wrapcheck // passed through unchanged
+}
+
+func (p *conPTY) Resize(rows, cols int) error {
+	if err := windows.ResizePseudoConsole(p.console, consoleSize(rows, cols)); err != nil {
+		return fmt.Errorf("resize pseudo console: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the pseudo console, which sends the processes attached to it
+// CTRL_CLOSE_EVENT, and then the pipes. A reader sees EOF once the console
+// has flushed its output.
+func (p *conPTY) Close() error {
+	p.closeOnce.Do(func() {
+		windows.ClosePseudoConsole(p.console)
+
+		_ = p.in.Close()
+		_ = p.out.Close()
+	})
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package harnesstype
+
+import "io"
+
+// PTY is the controlling side of the pseudo-terminal a harness process runs
+// in: reads return the process's output and writes are its input. On Unix
+// it is the pty master; on Windows, the pipes of a ConPTY pseudo console.
+type PTY interface {
+	io.ReadWriteCloser
+
+	// Resize sets the size of the terminal the process sees.
+	Resize(rows, cols int) error
+}
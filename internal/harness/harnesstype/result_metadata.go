@@ -0,0 +1,166 @@
+package harnesstype
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// maxSummaryExcerptRunes bounds the part of the agent response quoted in a
+// result summary.
+const maxSummaryExcerptRunes = 1000
+
+// DefaultResultSummaryTemplate renders the human summary in ResultMetadata
+// when no user template exists at paths.ResultSummaryTemplateFile.
+const DefaultResultSummaryTemplate = `{{.Excerpt}}
+{{- if .PullRequestURL}}
+
+Pull request: {{.PullRequestURL}}
+{{- end}}
+{{- if .Branch}}
+Branch: ` + "`{{.Branch}}`" + `
+{{- end}}
+{{- with .Diffstat}}
+Changes: {{.FilesChanged}} file(s), +{{.Insertions}} -{{.Deletions}}
+{{- end}}
+`
+
+// ResultMetadata is the structured result context reported under the
+// "resultMetadata" key of a job result, for integrations (such as Linear
+// comment-back) that render results for humans. Every field is optional.
+type ResultMetadata struct {
+	Branch         string    `json:"branch,omitempty"`
+	Commit         string    `json:"commit,omitempty"`
+	PullRequestURL string    `json:"pullRequestUrl,omitempty"`
+	Diffstat       *Diffstat `json:"diffstat,omitempty"`
+	Summary        string    `json:"summary,omitempty"`
+}
+
+// Diffstat counts the changes a job made to the working tree.
+type Diffstat struct {
+	FilesChanged int `json:"filesChanged"`
+	Insertions   int `json:"insertions"`
+	Deletions    int `json:"deletions"`
+}
+
+// ResultMetadataCarrier is implemented by job outputs that can carry
+// ResultMetadata.
+type ResultMetadataCarrier interface {
+	// ResponseText returns the agent's final response.
+	ResponseText() string
+	// SetResultMetadata attaches metadata to the output.
+	SetResultMetadata(meta *ResultMetadata)
+}
+
+// ResponseText implements ResultMetadataCarrier.
+func (o *AgentJobOutput) ResponseText() string {
+	return o.Output
+}
+
+// SetResultMetadata implements ResultMetadataCarrier.
+func (o *AgentJobOutput) SetResultMetadata(meta *ResultMetadata) {
+	o.ResultMetadata = meta
+}
+
+func (m *ResultMetadata) validate() error {
+	if m == nil {
+		return nil
+	}
+
+	if !utf8.ValidString(m.Summary) || !utf8.ValidString(m.Branch) {
+		return fmt.Errorf("%w: resultMetadata is not valid UTF-8", ErrInvalidOutput)
+	}
+
+	if m.PullRequestURL != "" {
+		parsed, err := url.Parse(m.PullRequestURL)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+			return fmt.Errorf("%w: resultMetadata.pullRequestUrl %q is not an http(s) URL", ErrInvalidOutput, m.PullRequestURL)
+		}
+	}
+
+	if d := m.Diffstat; d != nil && (d.FilesChanged < 0 || d.Insertions < 0 || d.Deletions < 0) {
+		return fmt.Errorf("%w: negative resultMetadata.diffstat count", ErrInvalidOutput)
+	}
+
+	return nil
+}
+
+// pullRequestURLPattern matches GitHub pull request and GitLab merge request URLs.
+var pullRequestURLPattern = regexp.MustCompile(
+	`https://(?:github\.com/[\w.-]+/[\w.-]+/pull/\d+|gitlab\.[\w.-]+/[\w./-]+/-/merge_requests/\d+)`,
+)
+
+// FindPullRequestURL returns the last pull or merge request URL mentioned in
+// text, or "" when there is none.
+func FindPullRequestURL(text string) string {
+	matches := pullRequestURLPattern.FindAllString(text, -1)
+	if len(matches) == 0 {
+		return ""
+	}
+
+	return matches[len(matches)-1]
+}
+
+// SummaryExcerpt returns the last paragraph of an agent response, where
+// agents conventionally summarize their work, capped at a readable length.
+func SummaryExcerpt(response string) string {
+	paragraphs := strings.Split(strings.ReplaceAll(strings.TrimSpace(response), "\r\n", "\n"), "\n\n")
+
+	excerpt := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+	if utf8.RuneCountInString(excerpt) <= maxSummaryExcerptRunes {
+		return excerpt
+	}
+
+	runes := []rune(excerpt)
+
+	return strings.TrimSpace(string(runes[:maxSummaryExcerptRunes])) + "…"
+}
+
+// SummaryData is the data passed to the result summary template.
+type SummaryData struct {
+	// Excerpt is SummaryExcerpt of the agent response.
+	Excerpt string
+	// Response is the full agent response.
+	Response string
+
+	JobID          string
+	Branch         string
+	Commit         string
+	PullRequestURL string
+	Diffstat       *Diffstat
+}
+
+// RenderResultSummary renders data with the user's result summary template,
+// falling back to DefaultResultSummaryTemplate.
+func RenderResultSummary(data *SummaryData) (string, error) {
+	text := DefaultResultSummaryTemplate
+
+	if path, err := paths.ResultSummaryTemplateFile(); err == nil {
+		custom, exists, readErr := safeio.ReadFileIfExists(path)
+		if readErr != nil {
+			return "", fmt.Errorf("read result summary template: %w", readErr)
+		}
+
+		if exists {
+			text = string(custom)
+		}
+	}
+
+	tmpl, err := template.New("result-summary").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("parse result summary template: %w", err)
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render result summary: %w", err)
+	}
+
+	return strings.TrimSpace(b.String()), nil
+}
@@ -1,7 +1,10 @@
 package harnesstype
 
 import (
+	"errors"
 	"fmt"
+	"path/filepath"
+	"regexp"
 
 	"github.com/musher-dev/mush/internal/executil"
 	"gopkg.in/yaml.v3"
@@ -9,16 +12,18 @@ import (
 
 // ProviderSpec describes a harness provider loaded from an embedded YAML file.
 type ProviderSpec struct {
-	Name        string         `yaml:"name"`
-	DisplayName string         `yaml:"displayName"`
-	Description string         `yaml:"description"`
-	Binary      string         `yaml:"binary"`
-	Directories *Directories   `yaml:"directories,omitempty"`
-	BundleDir   *BundleDirSpec `yaml:"bundleDir,omitempty"`
-	CLI         *CLIFlags      `yaml:"cli,omitempty"`
-	Assets      *AssetPaths    `yaml:"assets,omitempty"`
-	MCP         *MCPDef        `yaml:"mcp,omitempty"`
-	Status      *StatusSpec    `yaml:"status,omitempty"`
+	Name        string          `yaml:"name"`
+	DisplayName string          `yaml:"displayName"`
+	Description string          `yaml:"description"`
+	Binary      string          `yaml:"binary"`
+	Directories *Directories    `yaml:"directories,omitempty"`
+	BundleDir   *BundleDirSpec  `yaml:"bundleDir,omitempty"`
+	CLI         *CLIFlags       `yaml:"cli,omitempty"`
+	Assets      *AssetPaths     `yaml:"assets,omitempty"`
+	MCP         *MCPDef         `yaml:"mcp,omitempty"`
+	Status      *StatusSpec     `yaml:"status,omitempty"`
+	Completion  *CompletionSpec `yaml:"completion,omitempty"`
+	Shell       *ShellSpec      `yaml:"shell,omitempty"`
 }
 
 // Directories describes harness-specific config directory paths.
@@ -31,6 +36,37 @@ type Directories struct {
 type BundleDirSpec struct {
 	Mode string `yaml:"mode"` // "add_dir", "cd_flag", "cwd"
 	Flag string `yaml:"flag"` // CLI flag for add_dir/cd_flag modes
+
+	// Multiple is set when an add_dir flag may be repeated, so directories
+	// beyond the bundle's can be added after it.
+	Multiple bool `yaml:"multiple,omitempty"`
+}
+
+// AcceptsExtraDirs reports whether directories beyond the bundle's can be
+// passed to the harness.
+func (b *BundleDirSpec) AcceptsExtraDirs() bool {
+	return b != nil && b.Mode == "add_dir" && b.Flag != "" && b.Multiple
+}
+
+// Args returns the flags that pass bundleDir, then extraDirs in order, to the
+// harness. Extra directories are dropped unless the spec accepts them.
+func (b *BundleDirSpec) Args(bundleDir string, extraDirs []string) []string {
+	if b == nil || b.Flag == "" {
+		return nil
+	}
+
+	var args []string
+	if bundleDir != "" {
+		args = append(args, b.Flag, bundleDir)
+	}
+
+	if b.AcceptsExtraDirs() {
+		for _, dir := range extraDirs {
+			args = append(args, b.Flag, dir)
+		}
+	}
+
+	return args
 }
 
 // CLIFlags describes harness-specific CLI flags.
@@ -43,6 +79,18 @@ type AssetPaths struct {
 	SkillDir       string `yaml:"skillDir"`
 	AgentDir       string `yaml:"agentDir"`
 	ToolConfigFile string `yaml:"toolConfigFile"`
+
+	// CommandDir holds slash commands; providers without one reject
+	// command assets.
+	CommandDir string `yaml:"commandDir,omitempty"`
+
+	// PromptDir holds prompt templates. Without it, prompt assets keep
+	// their logical path.
+	PromptDir string `yaml:"promptDir,omitempty"`
+
+	// HookConfigFile is the settings file hook assets are merged into;
+	// providers without one reject hook assets.
+	HookConfigFile string `yaml:"hookConfigFile,omitempty"`
 }
 
 // MCPDef describes MCP configuration for a harness.
@@ -60,6 +108,52 @@ type StatusSpec struct {
 	AuthCheck      *AuthCheck `yaml:"authCheck,omitempty"`
 }
 
+// CompletionSpec describes how a long-running harness signals that a job
+// finished. Providers that run one process per job omit it.
+type CompletionSpec struct {
+	Mode   string `yaml:"mode"`             // "signal_file", "hook_json", "output_marker", "process_exit"
+	File   string `yaml:"file,omitempty"`   // signal file name for signal_file/hook_json
+	Marker string `yaml:"marker,omitempty"` // output text for output_marker
+}
+
+// NeedsSignalDir reports whether the mode relies on a signal directory.
+func (c *CompletionSpec) NeedsSignalDir() bool {
+	return c != nil && (c.Mode == CompletionSignalFile || c.Mode == CompletionHookJSON)
+}
+
+// Prompt delivery modes for a shell harness.
+const (
+	// ShellPromptStdin runs one process per job and writes the prompt to its stdin.
+	ShellPromptStdin = "stdin"
+	// ShellPromptArg runs one process per job with the prompt as its last
+	// argument, after promptFlag when one is set.
+	ShellPromptArg = "arg"
+	// ShellPromptPTY keeps one process running in a PTY and types each
+	// job's prompt into it. The spec's completion block says when a job ends.
+	ShellPromptPTY = "pty"
+)
+
+// ShellSpec describes how a custom harness defined in the user's config
+// directory runs its binary. Built-in providers leave it unset.
+type ShellSpec struct {
+	// Args are passed to the binary for every process it runs.
+	Args []string `yaml:"args,omitempty"`
+
+	// Prompt is one of the ShellPrompt* modes. It defaults to pty when the
+	// spec declares a completion block and to stdin otherwise.
+	Prompt string `yaml:"prompt,omitempty"`
+
+	// PromptFlag precedes the prompt in arg mode, e.g. "--message".
+	PromptFlag string `yaml:"promptFlag,omitempty"`
+
+	// ReadyPattern is a regular expression matched against pty output, with
+	// escape sequences removed, that shows the harness is ready for a prompt.
+	ReadyPattern string `yaml:"readyPattern,omitempty"`
+
+	// ResetInput is typed into the pty between jobs, e.g. "/clear".
+	ResetInput string `yaml:"resetInput,omitempty"`
+}
+
 // AuthCheck describes a file-based credential check for a harness provider.
 type AuthCheck struct {
 	Path        string `yaml:"path"`
@@ -68,19 +162,31 @@ type AuthCheck struct {
 
 // MustParseSpec parses a YAML spec from raw bytes and panics on failure.
 func MustParseSpec(data []byte) *ProviderSpec {
+	spec, err := ParseSpec(data)
+	if err != nil {
+		panic("harnesstype: " + err.Error())
+	}
+
+	return spec
+}
+
+// ParseSpec parses and validates a YAML spec from raw bytes.
+func ParseSpec(data []byte) (*ProviderSpec, error) {
 	var spec ProviderSpec
 	if err := yaml.Unmarshal(data, &spec); err != nil {
-		panic(fmt.Sprintf("harnesstype: unmarshal spec: %v", err))
+		return nil, fmt.Errorf("unmarshal spec: %w", err)
 	}
 
-	validateProviderSpec(&spec)
+	if err := validateProviderSpec(&spec); err != nil {
+		return nil, err
+	}
 
-	return &spec
+	return &spec, nil
 }
 
-func validateProviderSpec(spec *ProviderSpec) {
+func validateProviderSpec(spec *ProviderSpec) error {
 	if spec.Name == "" {
-		panic("harnesstype: spec name is required")
+		return errors.New("spec name is required")
 	}
 
 	if spec.BundleDir != nil && spec.BundleDir.Mode != "" {
@@ -88,7 +194,17 @@ func validateProviderSpec(spec *ProviderSpec) {
 		case "add_dir", "cd_flag", "cwd":
 			// valid
 		default:
-			panic(fmt.Sprintf("harnesstype: provider %s: invalid bundleDir.mode %q", spec.Name, spec.BundleDir.Mode))
+			return fmt.Errorf("provider %s: invalid bundleDir.mode %q", spec.Name, spec.BundleDir.Mode)
+		}
+
+		if spec.BundleDir.Multiple && spec.BundleDir.Mode != "add_dir" {
+			return fmt.Errorf("provider %s: bundleDir.multiple requires add_dir mode", spec.Name)
+		}
+	}
+
+	if spec.Completion != nil {
+		if err := validateCompletionSpec(spec.Name, spec.Completion); err != nil {
+			return err
 		}
 	}
 
@@ -97,9 +213,80 @@ func validateProviderSpec(spec *ProviderSpec) {
 		case "json", "toml":
 			// valid
 		default:
-			panic(fmt.Sprintf("harnesstype: provider %s: invalid mcp.format %q", spec.Name, spec.MCP.Format))
+			return fmt.Errorf("provider %s: invalid mcp.format %q", spec.Name, spec.MCP.Format)
+		}
+	}
+
+	if spec.Shell != nil {
+		return validateShellSpec(spec)
+	}
+
+	return nil
+}
+
+func validateCompletionSpec(name string, completion *CompletionSpec) error {
+	switch completion.Mode {
+	case CompletionSignalFile, CompletionHookJSON:
+		if file := completion.File; file == "" || file == "." || file == ".." || file != filepath.Base(file) {
+			return fmt.Errorf("provider %s: completion.file must be a plain file name", name)
+		}
+	case CompletionOutputMarker:
+		if completion.Marker == "" {
+			return fmt.Errorf("provider %s: completion.marker is required", name)
+		}
+	case CompletionProcessExit:
+		// valid
+	default:
+		return fmt.Errorf("provider %s: invalid completion.mode %q", name, completion.Mode)
+	}
+
+	return nil
+}
+
+// validateShellSpec checks a shell block and fills in its prompt mode.
+func validateShellSpec(spec *ProviderSpec) error {
+	shell := spec.Shell
+	session := spec.Completion != nil && spec.Completion.Mode != CompletionProcessExit
+
+	if spec.Binary == "" {
+		return fmt.Errorf("provider %s: binary is required for a shell harness", spec.Name)
+	}
+
+	if shell.Prompt == "" {
+		shell.Prompt = ShellPromptStdin
+		if session {
+			shell.Prompt = ShellPromptPTY
+		}
+	}
+
+	switch shell.Prompt {
+	case ShellPromptStdin, ShellPromptArg:
+		if session {
+			return fmt.Errorf("provider %s: shell.prompt %s runs a process per job; completion.mode %s needs pty", spec.Name, shell.Prompt, spec.Completion.Mode)
+		}
+
+		if shell.ReadyPattern != "" || shell.ResetInput != "" {
+			return fmt.Errorf("provider %s: shell.readyPattern and shell.resetInput require shell.prompt pty", spec.Name)
+		}
+	case ShellPromptPTY:
+		if !session {
+			return fmt.Errorf("provider %s: shell.prompt pty requires a completion block other than process_exit", spec.Name)
+		}
+	default:
+		return fmt.Errorf("provider %s: invalid shell.prompt %q", spec.Name, shell.Prompt)
+	}
+
+	if shell.PromptFlag != "" && shell.Prompt != ShellPromptArg {
+		return fmt.Errorf("provider %s: shell.promptFlag requires shell.prompt arg", spec.Name)
+	}
+
+	if shell.ReadyPattern != "" {
+		if _, err := regexp.Compile(shell.ReadyPattern); err != nil {
+			return fmt.Errorf("provider %s: invalid shell.readyPattern: %w", spec.Name, err)
 		}
 	}
+
+	return nil
 }
 
 // AvailableFunc returns a lazy closure that checks if a provider's binary is available.
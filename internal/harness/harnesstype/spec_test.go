@@ -0,0 +1,97 @@
+package harnesstype
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpec_Shell(t *testing.T) {
+	tests := []struct {
+		name       string
+		yaml       string
+		wantPrompt string
+		wantErr    string
+	}{
+		{
+			name:       "one process per job by default",
+			yaml:       "binary: aider\nshell:\n  args: [--yes-always]\n",
+			wantPrompt: ShellPromptStdin,
+		},
+		{
+			name:       "completion block makes a pty session",
+			yaml:       "binary: aider\nshell:\n  readyPattern: '> $'\ncompletion:\n  mode: output_marker\n  marker: Tokens\n",
+			wantPrompt: ShellPromptPTY,
+		},
+		{
+			name:       "process exit keeps one process per job",
+			yaml:       "binary: aider\nshell:\n  prompt: arg\n  promptFlag: --message\ncompletion:\n  mode: process_exit\n",
+			wantPrompt: ShellPromptArg,
+		},
+		{
+			name:    "binary required",
+			yaml:    "shell: {}\n",
+			wantErr: "binary is required",
+		},
+		{
+			name:    "pty without completion",
+			yaml:    "binary: aider\nshell:\n  prompt: pty\n",
+			wantErr: "requires a completion block",
+		},
+		{
+			name:    "stdin with a session completion",
+			yaml:    "binary: aider\nshell:\n  prompt: stdin\ncompletion:\n  mode: signal_file\n  file: done\n",
+			wantErr: "needs pty",
+		},
+		{
+			name:    "ready pattern outside a session",
+			yaml:    "binary: aider\nshell:\n  readyPattern: '> '\n",
+			wantErr: "require shell.prompt pty",
+		},
+		{
+			name:    "prompt flag outside arg mode",
+			yaml:    "binary: aider\nshell:\n  promptFlag: --message\n",
+			wantErr: "requires shell.prompt arg",
+		},
+		{
+			name:    "bad ready pattern",
+			yaml:    "binary: aider\nshell:\n  readyPattern: '('\ncompletion:\n  mode: output_marker\n  marker: done\n",
+			wantErr: "invalid shell.readyPattern",
+		},
+		{
+			name:    "unknown prompt mode",
+			yaml:    "binary: aider\nshell:\n  prompt: file\n",
+			wantErr: `invalid shell.prompt "file"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := ParseSpec([]byte("name: aider\n" + tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseSpec() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseSpec() error = %v", err)
+			}
+
+			if spec.Shell.Prompt != tt.wantPrompt {
+				t.Fatalf("Shell.Prompt = %q, want %q", spec.Shell.Prompt, tt.wantPrompt)
+			}
+		})
+	}
+}
+
+func TestMustParseSpec_Panics(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(r.(string), "harnesstype: provider x: invalid mcp.format") {
+			t.Fatalf("recover() = %v, want the validation panic", r)
+		}
+	}()
+
+	MustParseSpec([]byte("name: x\nmcp:\n  format: ini\n"))
+}
@@ -0,0 +1,46 @@
+package harnesstype
+
+import "time"
+
+// Supervision counts how an executor's harness process has behaved since
+// setup: how often it was (re)started, how startup prompts were handled,
+// and how long it took to become ready.
+type Supervision struct {
+	// PTYStarts counts harness process starts, including the first.
+	PTYStarts int `json:"ptyStarts"`
+
+	// BypassAccepts counts permission dialogs that were auto-accepted.
+	BypassAccepts int `json:"bypassAccepts"`
+
+	// ReadyTimeouts counts waits where the prompt was never detected.
+	ReadyTimeouts int `json:"readyTimeouts"`
+
+	// ReadyWaits and ReadyTotal accumulate time from start to readiness.
+	ReadyWaits int           `json:"readyWaits"`
+	ReadyTotal time.Duration `json:"readyTotalNs"`
+}
+
+// PTYRestarts returns how many times the process was started after the first.
+func (s Supervision) PTYRestarts() int {
+	return max(s.PTYStarts-1, 0)
+}
+
+// AverageReady returns the mean time to readiness, or zero before any wait.
+func (s Supervision) AverageReady() time.Duration {
+	if s.ReadyWaits == 0 {
+		return 0
+	}
+
+	return s.ReadyTotal / time.Duration(s.ReadyWaits)
+}
+
+// Add returns the sum of s and other.
+func (s Supervision) Add(other Supervision) Supervision {
+	return Supervision{
+		PTYStarts:     s.PTYStarts + other.PTYStarts,
+		BypassAccepts: s.BypassAccepts + other.BypassAccepts,
+		ReadyTimeouts: s.ReadyTimeouts + other.ReadyTimeouts,
+		ReadyWaits:    s.ReadyWaits + other.ReadyWaits,
+		ReadyTotal:    s.ReadyTotal + other.ReadyTotal,
+	}
+}
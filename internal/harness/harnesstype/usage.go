@@ -0,0 +1,20 @@
+package harnesstype
+
+// Usage is the resource use of the job an executor is running.
+type Usage struct {
+	// Turns counts agentic turns (model responses) so far.
+	Turns int
+
+	// InputTokens includes cache reads and writes.
+	InputTokens  int64
+	OutputTokens int64
+
+	// CostUSD is only meaningful when CostKnown is set.
+	CostUSD   float64
+	CostKnown bool
+}
+
+// Tokens returns input and output tokens combined.
+func (u Usage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
@@ -0,0 +1,321 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+const (
+	// headlessTermWidth and headlessTermHeight size the pseudo-terminal
+	// harnesses run in when there is no real terminal to match.
+	headlessTermWidth  = 120
+	headlessTermHeight = 40
+
+	// DefaultHeadlessDrainTimeout bounds how long a headless worker lets the
+	// in-flight job finish after it is asked to stop, leaving room to
+	// deregister within a container runtime's default 10s stop grace period.
+	DefaultHeadlessDrainTimeout = 8 * time.Second
+)
+
+// RunHeadless runs the worker job loop without a terminal UI, for systemd
+// units, containers, and CI runners. Harness output goes only to the
+// transcript, and progress is reported through the structured logger in ctx.
+//
+// When ctx ends, claiming stops and the in-flight job gets up to
+// drainTimeout to finish before it is canceled and the worker deregistered.
+// On Unix, SIGHUP reloads the config file and SIGCONT resyncs with the
+// platform; `mush worker reload` reloads it too, and `mush worker stop`
+// drains it like a shutdown signal.
+func RunHeadless(ctx context.Context, cfg *Config, drainTimeout time.Duration) error {
+	if cfg.Client == nil {
+		return fmt.Errorf("missing client in harness config")
+	}
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "harness"))
+
+	// Jobs and harness processes run under runCtx, which outlives ctx so a
+	// shutdown signal drains the in-flight job instead of killing it.
+	runCtx, cancelRun := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRun()
+
+	loadedCfg := config.Load()
+	executors := make(map[string]harnesstype.Executor)
+
+	store, err := openTranscript(cfg.TranscriptEnabled, cfg.TranscriptDir, cfg.TranscriptLines, loadedCfg, cfg.SupportedHarnesses)
+	if err != nil {
+		if cfg.RequireTranscript {
+			return fmt.Errorf("%w: %w", ErrTranscriptUnavailable, err)
+		}
+
+		logger.Warn("transcript disabled", slog.String("event.type", "worker.transcript_error"), slog.String("error", err.Error()))
+	}
+
+	network, err := openNetworkRecorder(loadedCfg, false)
+	if err != nil {
+		logger.Warn("network recording disabled", slog.String("event.type", "worker.network_recording_error"), slog.String("error", err.Error()))
+	}
+
+	if network != nil {
+		defer func() { _ = network.Close() }()
+	}
+
+	var eng *engine.Engine
+
+	var recordJob func(*transcript.JobRecord)
+	if store != nil {
+		recordJob = func(rec *transcript.JobRecord) {
+			if appendErr := store.AppendJob(rec); appendErr != nil {
+				eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript write failed: %v", appendErr))
+			}
+		}
+	}
+
+	eng = newWorkerEngine(cfg, loadedCfg, executors, engine.StatusConnecting, time.Now, recordJob, network)
+
+	if store != nil {
+		defer func() {
+			if closeErr := store.Close(); closeErr != nil {
+				logger.Warn("transcript close failed", slog.String("event.type", "worker.transcript_error"), slog.String("error", closeErr.Error()))
+			}
+		}()
+	}
+
+	var signalDir string
+
+	if needsSignalDir(cfg.SupportedHarnesses) {
+		signalDir, err = os.MkdirTemp("", "mush-signals-")
+		if err != nil {
+			return fmt.Errorf("failed to create signal directory: %w", err)
+		}
+
+		defer func() { _ = os.RemoveAll(signalDir) }()
+	}
+
+	var harnessEnv []string
+	if network != nil {
+		harnessEnv = network.Env()
+	}
+
+	harnessExited := make(chan string, len(cfg.SupportedHarnesses))
+	output := newOutputFanout(headlessTermWidth, headlessTermHeight)
+
+	defer func() {
+		for _, executor := range executors {
+			executor.Teardown()
+		}
+	}()
+
+	for _, harnessType := range cfg.SupportedHarnesses {
+		info, ok := Lookup(harnessType)
+		if !ok {
+			continue
+		}
+
+		executor := newExecutor(&info, cfg.Rehearsal)
+
+		setupOpts := harnesstype.SetupOptions{
+			TermWriter:   io.Discard,
+			TermWidth:    headlessTermWidth,
+			TermHeight:   headlessTermHeight,
+			SignalDir:    signalDir,
+			RunnerConfig: eng.RunnerConfig(),
+			Env:          harnessEnv,
+			OnOutput: func(p []byte) {
+				output.Write(p)
+
+				if store == nil || len(p) == 0 {
+					return
+				}
+
+				if appendErr := store.Append("pty", p); appendErr != nil {
+					eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript write failed: %v", appendErr))
+				}
+			},
+			OnExit: func() {
+				select {
+				case harnessExited <- harnessType:
+				default:
+				}
+			},
+		}
+
+		if err := executor.Setup(runCtx, &setupOpts); err != nil {
+			return fmt.Errorf("failed to setup %s executor: %w", harnessType, err)
+		}
+
+		executors[harnessType] = executor
+	}
+
+	if err := eng.Start(runCtx); err != nil {
+		return fmt.Errorf("start worker: %w", err)
+	}
+
+	reloadRequests := make(chan struct{}, 1)
+
+	defer listenWorkerControl(ctx, logger, eng, output, reloadRequests, cfg.HabitatID, cfg.QueueID)()
+
+	if cfg.MetricsAddr != "" {
+		stopMetrics, metricsErr := serveMetrics(ctx, logger, cfg.MetricsAddr, eng, executors)
+		if metricsErr != nil {
+			logger.Warn("metrics endpoint unavailable", slog.String("event.type", "worker.metrics_error"), slog.String("error", metricsErr.Error()))
+		} else {
+			defer stopMetrics()
+		}
+	}
+
+	logger.Info("headless worker started",
+		slog.String("event.type", "worker.headless.started"),
+		slog.String("worker.id", eng.Stats().WorkerID),
+		slog.String("habitat.id", cfg.HabitatID),
+		slog.String("queue.id", cfg.QueueID),
+		slog.Any("harnesses", cfg.SupportedHarnesses),
+	)
+
+	logDone := make(chan struct{})
+
+	go func() { defer close(logDone); logEngineEvents(logger, eng.Events()) }()
+
+	sigCh := make(chan os.Signal, 1)
+	notifyRuntimeSignals(sigCh)
+
+	defer signal.Stop(sigCh)
+
+wait:
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("shutdown requested, draining",
+				slog.String("event.type", "worker.headless.draining"),
+				slog.Duration("drain.timeout", drainTimeout))
+
+			break wait
+		case <-eng.Finished():
+			break wait
+		case harnessType := <-harnessExited:
+			logger.Error("harness exited, stopping worker",
+				slog.String("event.type", "worker.headless.harness_exited"),
+				slog.String("harness", harnessType))
+
+			break wait
+		case <-reloadRequests:
+			loadedCfg = reloadHeadlessConfig(logger, eng, loadedCfg)
+		case sig := <-sigCh:
+			if isResumeSignal(sig) {
+				eng.Resume(runCtx)
+				continue
+			}
+
+			loadedCfg = reloadHeadlessConfig(logger, eng, loadedCfg)
+		}
+	}
+
+	drainCtx, cancelDrain := context.WithTimeout(context.WithoutCancel(ctx), drainTimeout)
+	defer cancelDrain()
+
+	if err := eng.Drain(drainCtx); err != nil {
+		logger.Warn("job engine shutdown incomplete",
+			slog.String("event.type", "worker.drain_error"),
+			slog.String("error", err.Error()))
+	}
+
+	<-logDone
+
+	stats := eng.Stats()
+
+	logger.Info("headless worker stopped",
+		slog.String("event.type", "worker.headless.stopped"),
+		slog.String("stop.reason", string(stats.StopReason)),
+		slog.Int("jobs.completed", stats.Completed),
+		slog.Int("jobs.failed", stats.Failed),
+		slog.Int("jobs.requeued", stats.Requeued),
+	)
+
+	if cfg.OnWorkerExit != nil {
+		cfg.OnWorkerExit(WorkerSummary{
+			StopReason: string(stats.StopReason),
+			Completed:  stats.Completed,
+			Failed:     stats.Failed,
+			Requeued:   stats.Requeued,
+		})
+	}
+
+	return nil
+}
+
+// logEngineEvents logs each engine event until the channel closes on Drain.
+func logEngineEvents(logger *slog.Logger, events <-chan engine.Event) {
+	for ev := range events {
+		attrs := []any{slog.String("event.type", "worker.engine."+string(ev.Type))}
+		if ev.JobID != "" {
+			attrs = append(attrs, slog.String("job.id", ev.JobID))
+		}
+
+		if ev.Message != "" {
+			attrs = append(attrs, slog.String("message", ev.Message))
+		}
+
+		switch ev.Type {
+		case engine.EventJobStarted:
+			logger.Info("job started", attrs...)
+		case engine.EventJobCompleted:
+			logger.Info("job completed", attrs...)
+		case engine.EventJobFailed:
+			logger.Warn("job failed", attrs...)
+		case engine.EventError:
+			logger.Warn("worker error", attrs...)
+		case engine.EventClaimingStopped:
+			logger.Info("claiming stopped", attrs...)
+		case engine.EventResumed:
+			logger.Info("worker resumed", attrs...)
+		default:
+			logger.Debug("worker status changed", append(attrs, slog.String("status", ev.Status.String()))...)
+		}
+	}
+}
+
+// reloadHeadlessConfig re-reads the config and applies the settings in
+// config.ReloadableKeys, logging each one that changed.
+func reloadHeadlessConfig(logger *slog.Logger, eng *engine.Engine, current *config.Config) *config.Config {
+	next := config.Load()
+	changes := config.Diff(current, next, config.ReloadableKeys)
+
+	if level := reloadedLogLevel(current, next); level != "" {
+		if _, err := observability.SetLevel(level); err != nil {
+			eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Config reload: %v", err))
+		}
+	}
+
+	eng.Reload(next)
+	logConfigChanges(logger, changes)
+
+	return next
+}
+
+// reloadedLogLevel returns the log level a reload applies: next's log.level,
+// or "info" when the setting was removed, so the old level does not outlive
+// it. It returns "" when neither config sets one, leaving the level chosen
+// at startup, such as by --log-level, in place.
+func reloadedLogLevel(prev, next *config.Config) string {
+	if level := next.LogLevel(); level != "" {
+		return level
+	}
+
+	if prev.LogLevel() != "" {
+		return "info"
+	}
+
+	return ""
+}
@@ -0,0 +1,129 @@
+//go:build unix
+
+package harness
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+func TestLogEngineEvents(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	events := make(chan engine.Event, 4)
+	events <- engine.Event{Type: engine.EventStatusChanged, Status: engine.StatusConnected}
+	events <- engine.Event{Type: engine.EventJobStarted, JobID: "job-1"}
+	events <- engine.Event{Type: engine.EventJobFailed, JobID: "job-1", Message: "exit status 1"}
+	events <- engine.Event{Type: engine.EventClaimingStopped, Message: string(engine.StopIdle)}
+	close(events)
+
+	logEngineEvents(logger, events)
+
+	var records []map[string]any
+
+	for line := range bytes.Lines(buf.Bytes()) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+
+		records = append(records, record)
+	}
+
+	want := []struct{ level, eventType, jobID string }{
+		{"INFO", "worker.engine.job_started", "job-1"},
+		{"WARN", "worker.engine.job_failed", "job-1"},
+		{"INFO", "worker.engine.claiming_stopped", ""},
+	}
+
+	if len(records) != len(want) {
+		t.Fatalf("logged %d records, want %d (status changes are debug): %s", len(records), len(want), buf.String())
+	}
+
+	for i, w := range want {
+		got := records[i]
+		if got["level"] != w.level || got["event.type"] != w.eventType {
+			t.Errorf("record %d = %v, want level %s and event.type %s", i, got, w.level, w.eventType)
+		}
+
+		if jobID, _ := got["job.id"].(string); jobID != w.jobID {
+			t.Errorf("record %d job.id = %q, want %q", i, jobID, w.jobID)
+		}
+	}
+
+	if records[1]["message"] != "exit status 1" {
+		t.Errorf("job_failed message = %v, want the failure message", records[1]["message"])
+	}
+}
+
+func TestRunHeadlessRequireTranscript(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	// A file where the transcript directory should be cannot hold sessions.
+	dir := filepath.Join(t.TempDir(), "history")
+	if err := os.WriteFile(dir, nil, 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	cfg := &Config{
+		Client:             client.New("http://127.0.0.1:0", "test-key"),
+		SupportedHarnesses: []string{"claude"},
+		TranscriptEnabled:  true,
+		TranscriptDir:      dir,
+		RequireTranscript:  true,
+	}
+
+	err := RunHeadless(t.Context(), cfg, 0)
+	if !errors.Is(err, ErrTranscriptUnavailable) {
+		t.Fatalf("RunHeadless() error = %v, want ErrTranscriptUnavailable", err)
+	}
+}
+
+func TestReloadHeadlessConfig_ResetsRemovedLogLevel(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+	t.Setenv("MUSHER_LOG_LEVEL", "")
+
+	configPath := filepath.Join(configHome, "musher", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o700); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+
+	if err := os.WriteFile(configPath, []byte("log:\n  level: debug\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	previous, err := observability.SetLevel("debug")
+	if err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+
+	t.Cleanup(func() { _, _ = observability.SetLevel(previous.String()) })
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	eng := engine.New(&engine.Options{InitialStatus: engine.StatusConnected})
+	current := config.Load()
+
+	if err := os.WriteFile(configPath, []byte("worker:\n  poll_interval: 10s\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	reloadHeadlessConfig(logger, eng, current)
+
+	if got := observability.CurrentLevel(); got != slog.LevelInfo {
+		t.Fatalf("level after log.level was removed = %v, want INFO", got)
+	}
+}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
@@ -6,36 +6,14 @@ import (
 	"sort"
 	"time"
 
-	harnessstate "github.com/musher-dev/mush/internal/harness/state"
-
+	"github.com/musher-dev/mush/internal/engine"
 	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	harnessstate "github.com/musher-dev/mush/internal/harness/state"
 )
 
-const (
-	defaultRunnerConfigRefreshSeconds = 300
-	minRunnerConfigRefreshSeconds     = 60
-	maxRunnerConfigRefreshSeconds     = 900
-)
-
-func normalizeRefreshInterval(seconds int) time.Duration {
-	if seconds <= 0 {
-		seconds = defaultRunnerConfigRefreshSeconds
-	}
-
-	if seconds < minRunnerConfigRefreshSeconds {
-		seconds = minRunnerConfigRefreshSeconds
-	}
-
-	if seconds > maxRunnerConfigRefreshSeconds {
-		seconds = maxRunnerConfigRefreshSeconds
-	}
-
-	return time.Duration(seconds) * time.Second
-}
-
-// buildMCPServerStatuses builds snapshot-ready MCP server status entries from a JobLoop.
-func buildMCPServerStatuses(jobs *JobLoop, now time.Time) []harnessstate.MCPServerStatus {
-	cfg := jobs.RunnerConfig()
+// buildMCPServerStatuses builds snapshot-ready MCP server status entries from the engine's runner config.
+func buildMCPServerStatuses(eng *engine.Engine, now time.Time) []harnessstate.MCPServerStatus {
+	cfg := eng.RunnerConfig()
 	if cfg == nil || len(cfg.Providers) == 0 {
 		return nil
 	}
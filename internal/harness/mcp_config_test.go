@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
@@ -128,20 +128,6 @@ func TestCreateMCPConfigFile(t *testing.T) {
 	}
 }
 
-func TestNormalizeRefreshInterval(t *testing.T) {
-	if got := normalizeRefreshInterval(0); got != 300*time.Second {
-		t.Fatalf("normalize(0) = %s, want 300s", got)
-	}
-
-	if got := normalizeRefreshInterval(10); got != 60*time.Second {
-		t.Fatalf("normalize(10) = %s, want 60s", got)
-	}
-
-	if got := normalizeRefreshInterval(3600); got != 900*time.Second {
-		t.Fatalf("normalize(3600) = %s, want 900s", got)
-	}
-}
-
 func TestLoadedMCPProviderNames(t *testing.T) {
 	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
 	exp := now.Add(10 * time.Minute)
@@ -211,6 +197,37 @@ func TestLoadedMCPServers_ExportedWrapper(t *testing.T) {
 	}
 }
 
+func TestMissingMCPServers(t *testing.T) {
+	now := time.Date(2026, 2, 14, 12, 0, 0, 0, time.UTC)
+	cfg := &client.RunnerConfigResponse{
+		Providers: map[string]client.RunnerProviderConfig{
+			"linear": {
+				Status:     "active",
+				Flags:      client.RunnerProviderFlags{MCP: true},
+				MCP:        &client.RunnerProviderMCP{URL: "https://mcp.linear.app/mcp"},
+				Credential: &client.RunnerProviderCredential{AccessToken: "tok"},
+			},
+			"github": {
+				Status: "disabled",
+				Flags:  client.RunnerProviderFlags{MCP: true},
+				MCP:    &client.RunnerProviderMCP{URL: "https://example.com/mcp"},
+				Credential: &client.RunnerProviderCredential{
+					AccessToken: "tok_gh",
+				},
+			},
+		},
+	}
+
+	missing := MissingMCPServers(cfg, []string{"Linear", "github", "sentry"}, now)
+	if len(missing) != 2 || missing[0] != "github" || missing[1] != "sentry" {
+		t.Fatalf("MissingMCPServers = %#v, want [github sentry]", missing)
+	}
+
+	if missing := MissingMCPServers(nil, []string{"linear"}, now); len(missing) != 1 {
+		t.Fatalf("MissingMCPServers(nil) = %#v, want [linear]", missing)
+	}
+}
+
 func TestBuildOpenCodeMCPConfig(t *testing.T) {
 	specs := []MCPProviderSpec{
 		{
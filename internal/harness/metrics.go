@@ -0,0 +1,68 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// metricsReadHeaderTimeout bounds how long a scraper may take to send its
+// request headers.
+const metricsReadHeaderTimeout = 10 * time.Second
+
+// serveMetrics serves eng's metrics, with the PTY restarts of the executors
+// that supervise a long-running harness, at /metrics on addr until the
+// returned function is called. The executors must be set up first.
+func serveMetrics(ctx context.Context, logger *slog.Logger, addr string, eng *engine.Engine, executors map[string]harnesstype.Executor) (func(), error) {
+	reg := eng.Metrics()
+	reg.CounterFunc("mush_worker_pty_restarts_total",
+		"Times a harness process was started again after its first start.", "harness",
+		func() map[string]float64 {
+			restarts := make(map[string]float64)
+
+			for harnessType, executor := range executors {
+				if reporter, ok := executor.(harnesstype.SupervisionReporter); ok {
+					restarts[harnessType] = float64(reporter.Supervision().PTYRestarts())
+				}
+			}
+
+			return restarts
+		})
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(ctx, "tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen for metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", reg)
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: metricsReadHeaderTimeout,
+	}
+
+	go func() {
+		if serveErr := srv.Serve(ln); serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			logger.Warn("metrics endpoint stopped", slog.String("event.type", "worker.metrics_error"), slog.String("error", serveErr.Error()))
+		}
+	}()
+
+	logger.Info("metrics endpoint listening",
+		slog.String("event.type", "worker.metrics.listening"),
+		slog.String("metrics.url", "http://"+ln.Addr().String()+"/metrics"),
+	)
+
+	return func() { _ = srv.Close() }, nil
+}
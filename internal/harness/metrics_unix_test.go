@@ -0,0 +1,67 @@
+//go:build unix
+
+package harness
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// supervisedExecutor is an executor that reports supervision counters.
+type supervisedExecutor struct {
+	harnesstype.Executor
+	supervision harnesstype.Supervision
+}
+
+func (e *supervisedExecutor) Supervision() harnesstype.Supervision { return e.supervision }
+
+func TestServeMetrics(t *testing.T) {
+	var logs bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+	eng := engine.New(&engine.Options{InitialStatus: engine.StatusConnected})
+	executors := map[string]harnesstype.Executor{
+		"claude": &supervisedExecutor{supervision: harnesstype.Supervision{PTYStarts: 3}},
+	}
+
+	stop, err := serveMetrics(t.Context(), logger, "127.0.0.1:0", eng, executors)
+	if err != nil {
+		t.Fatalf("serveMetrics() error = %v", err)
+	}
+	defer stop()
+
+	match := regexp.MustCompile(`metrics.url=(\S+)`).FindStringSubmatch(logs.String())
+	if match == nil {
+		t.Fatalf("log = %q, want the metrics URL", logs.String())
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, match[1], http.NoBody)
+	if err != nil {
+		t.Fatalf("NewRequestWithContext() error = %v", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s error = %v", match[1], err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+
+	for _, want := range []string{
+		"mush_worker_jobs_claimed_total 0\n",
+		`mush_worker_pty_restarts_total{harness="claude"} 2` + "\n",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
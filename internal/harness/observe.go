@@ -0,0 +1,213 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hinshun/vt10x"
+	"github.com/mattn/go-runewidth"
+
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+// observer draws a running worker's status bar and harness terminal from
+// the frames of `mush worker observe`. It never sends the worker input.
+type observer struct {
+	screen tcell.Screen
+	vt     vt10x.Terminal
+	target string
+
+	cols, rows int
+	status     worker.ControlStatus
+}
+
+type observedFrame struct {
+	frame *worker.ObserveFrame
+	err   error
+}
+
+// Observe shows obs, whose first frame the caller has read, in the terminal
+// until the user quits, returning nil, or the stream ends, returning its
+// error: io.EOF when the worker exits. target names the worker in the
+// status bar.
+func Observe(ctx context.Context, obs *worker.Observation, first *worker.ObserveFrame, target string) error {
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("init terminal screen: %w", err)
+	}
+
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("initialize terminal screen: %w", err)
+	}
+
+	defer screen.Fini()
+
+	o := &observer{screen: screen, target: target}
+	o.apply(first)
+
+	frames := make(chan observedFrame)
+
+	go func() {
+		for {
+			frame, err := obs.Next()
+
+			select {
+			case frames <- observedFrame{frame: frame, err: err}:
+			case <-ctx.Done():
+				return
+			}
+
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	events := make(chan tcell.Event)
+	quit := make(chan struct{})
+
+	go screen.ChannelEvents(events, quit)
+	defer close(quit)
+
+	o.draw()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case next := <-frames:
+			if next.err != nil {
+				return fmt.Errorf("observe worker: %w", next.err)
+			}
+
+			o.apply(next.frame)
+			o.draw()
+		case ev := <-events:
+			switch ev := ev.(type) {
+			case *tcell.EventKey:
+				if observeQuitKey(ev) {
+					return nil
+				}
+			case *tcell.EventResize:
+				screen.Sync()
+				o.draw()
+			}
+		}
+	}
+}
+
+// observeQuitKey reports whether ev ends the observation: ^Q, ^C, Esc, or q.
+func observeQuitKey(ev *tcell.EventKey) bool {
+	switch ev.Key() {
+	case tcell.KeyCtrlQ, tcell.KeyCtrlC, tcell.KeyEscape:
+		return true
+	case tcell.KeyRune:
+		return ev.Rune() == 'q'
+	default:
+		return false
+	}
+}
+
+// apply updates the status and the terminal from a frame. A new size starts
+// a fresh terminal, as the worker discards output drawn at the old one.
+func (o *observer) apply(frame *worker.ObserveFrame) {
+	if frame.Status != nil {
+		o.status = *frame.Status
+	}
+
+	if frame.Cols > 0 && frame.Rows > 0 && (o.vt == nil || frame.Cols != o.cols || frame.Rows != o.rows) {
+		o.cols, o.rows = frame.Cols, frame.Rows
+		o.vt = vt10x.New(vt10x.WithSize(o.cols, o.rows))
+	}
+
+	if o.vt != nil && len(frame.Output) > 0 {
+		_, _ = o.vt.Write(frame.Output)
+	}
+}
+
+func (o *observer) draw() {
+	o.screen.HideCursor()
+	o.renderTopBar()
+	o.renderTerminal()
+	o.screen.Show()
+}
+
+func (o *observer) renderTopBar() {
+	width, _ := o.screen.Size()
+	barStyle := tcell.StyleDefault.Background(tnSurface).Foreground(tnText)
+
+	for col := 0; col < width; col++ {
+		o.screen.SetContent(col, 0, ' ', nil, barStyle)
+	}
+
+	statusLabel := o.status.Status
+	if statusLabel == "" {
+		statusLabel = "Connecting..."
+	}
+
+	if o.status.Stopping {
+		statusLabel += " (stopping)"
+	}
+
+	spans := []styledSpan{
+		{"MUSH", barStyle.Foreground(tnAccent).Bold(true)},
+		{"  Status: ", barStyle},
+		{statusLabel, barStyle.Foreground(statusTCellColor(o.status.Status)).Bold(true)},
+		{"  Mode: ", barStyle},
+		{"OBSERVE", barStyle.Foreground(tnAccent)},
+		{fmt.Sprintf("  OK:%d Fail:%d", o.status.Completed, o.status.Failed), barStyle},
+	}
+
+	if o.status.JobID != "" {
+		spans = append(spans, styledSpan{"  Job: " + o.status.JobID, barStyle})
+	}
+
+	if o.target != "" {
+		spans = append(spans, styledSpan{"  " + o.target, barStyle.Foreground(tnMuted)})
+	}
+
+	leftWidth := 0
+	for _, span := range spans {
+		leftWidth += runewidth.StringWidth(span.text)
+	}
+
+	col := 0
+	for _, span := range spans {
+		col = drawScreenText(o.screen, col, 0, width, span.text, span.style)
+	}
+
+	right := "Read-only | q Quit"
+	if rightStart := width - runewidth.StringWidth(right); rightStart > leftWidth {
+		drawScreenText(o.screen, rightStart, 0, width, right, barStyle)
+	}
+}
+
+// renderTerminal draws the worker's terminal below the status bar at its
+// own size, cut off where this terminal is smaller.
+func (o *observer) renderTerminal() {
+	width, height := o.screen.Size()
+	clearStyle := tcell.StyleDefault.Background(tnPTYBg).Foreground(tnText)
+
+	for row := 1; row < height; row++ {
+		for col := 0; col < width; col++ {
+			o.screen.SetContent(col, row, ' ', nil, clearStyle)
+		}
+	}
+
+	if o.vt == nil {
+		return
+	}
+
+	o.vt.Lock()
+	defer o.vt.Unlock()
+
+	for row := 0; row < o.rows && row+1 < height; row++ {
+		for col := 0; col < o.cols && col < width; col++ {
+			glyph := o.vt.Cell(col, row)
+			o.screen.SetContent(col, row+1, glyphRune(glyph), nil, glyphStyle(glyph))
+		}
+	}
+}
@@ -0,0 +1,70 @@
+//go:build unix
+
+package harness
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+func screenRow(t *testing.T, sim tcell.SimulationScreen, row int) string {
+	t.Helper()
+
+	cells, width, _ := sim.GetContents()
+
+	var line strings.Builder
+
+	for col := 0; col < width; col++ {
+		if runes := cells[row*width+col].Runes; len(runes) > 0 {
+			line.WriteRune(runes[0])
+		}
+	}
+
+	return line.String()
+}
+
+func TestObserver_DrawsStatusAndTerminal(t *testing.T) {
+	sim := tcell.NewSimulationScreen("UTF-8")
+	if err := sim.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+
+	t.Cleanup(sim.Fini)
+	sim.SetSize(100, 10)
+
+	o := &observer{screen: sim, target: "build-box"}
+	o.apply(&worker.ObserveFrame{
+		Status: &worker.ControlStatus{Status: "Processing", JobID: "job-7", Completed: 2},
+		Cols:   40,
+		Rows:   5,
+		Output: []byte("hello from the harness"),
+	})
+	o.draw()
+
+	bar := screenRow(t, sim, 0)
+	for _, want := range []string{"Status: Processing", "Mode: OBSERVE", "OK:2 Fail:0", "Job: job-7", "build-box", "Read-only"} {
+		if !strings.Contains(bar, want) {
+			t.Fatalf("status bar = %q, want %q", bar, want)
+		}
+	}
+
+	if row := screenRow(t, sim, 1); !strings.HasPrefix(row, "hello from the harness") {
+		t.Fatalf("first terminal row = %q, want the harness output", row)
+	}
+
+	// Output-only frames keep the status and add to the terminal.
+	o.apply(&worker.ObserveFrame{Output: []byte("\r\nsecond line")})
+	o.draw()
+
+	if row := screenRow(t, sim, 2); !strings.HasPrefix(row, "second line") {
+		t.Fatalf("second terminal row = %q, want the new output", row)
+	}
+
+	if bar := screenRow(t, sim, 0); !strings.Contains(bar, "Job: job-7") {
+		t.Fatalf("status bar = %q after an output frame, want the last status", bar)
+	}
+}
@@ -0,0 +1,112 @@
+package harness
+
+import (
+	"bytes"
+	"sync"
+)
+
+const (
+	// outputBacklogLimit bounds the recent output kept for an observer that
+	// subscribes partway through a job, so it can draw the screen it joins.
+	outputBacklogLimit = 256 << 10
+
+	// observerQueueLimit is how many chunks an observer may fall behind
+	// before it is dropped. Observers never slow the harness.
+	observerQueueLimit = 256
+)
+
+// outputFanout copies the harness terminal's output to the observers of
+// `mush worker observe`, and keeps the terminal's size and recent output for
+// new ones.
+type outputFanout struct {
+	mu          sync.Mutex
+	cols, rows  int
+	backlog     []byte
+	subscribers map[chan []byte]struct{}
+}
+
+func newOutputFanout(cols, rows int) *outputFanout {
+	return &outputFanout{
+		cols:        cols,
+		rows:        rows,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Write sends p to every observer and adds it to the backlog. An observer
+// whose queue is full is dropped by closing its channel.
+func (f *outputFanout) Write(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+
+	chunk := append([]byte(nil), p...)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.backlog = append(f.backlog, chunk...)
+	if over := len(f.backlog) - outputBacklogLimit; over > 0 {
+		trimmed := f.backlog[over:]
+		// Start at a line so a new observer does not begin mid-sequence.
+		if i := bytes.IndexByte(trimmed, '\n'); i >= 0 {
+			trimmed = trimmed[i+1:]
+		}
+
+		f.backlog = append([]byte(nil), trimmed...)
+	}
+
+	for ch := range f.subscribers {
+		select {
+		case ch <- chunk:
+		default:
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Resize records the terminal's new size. The backlog was drawn at the old
+// size, so it is discarded.
+func (f *outputFanout) Resize(cols, rows int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if cols == f.cols && rows == f.rows {
+		return
+	}
+
+	f.cols, f.rows = cols, rows
+	f.backlog = nil
+}
+
+// TerminalSize returns the harness terminal's size.
+func (f *outputFanout) TerminalSize() (cols, rows int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.cols, f.rows
+}
+
+// SubscribeOutput returns the backlog and a channel of the output written
+// from then on.
+func (f *outputFanout) SubscribeOutput() (recent []byte, output <-chan []byte, cancel func()) {
+	ch := make(chan []byte, observerQueueLimit)
+
+	f.mu.Lock()
+	recent = append([]byte(nil), f.backlog...)
+	f.subscribers[ch] = struct{}{}
+	f.mu.Unlock()
+
+	cancel = func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if _, ok := f.subscribers[ch]; ok {
+			delete(f.subscribers, ch)
+			close(ch)
+		}
+	}
+
+	return recent, ch, cancel
+}
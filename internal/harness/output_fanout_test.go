@@ -0,0 +1,82 @@
+package harness
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestOutputFanout_SubscriberGetsBacklogThenOutput(t *testing.T) {
+	f := newOutputFanout(80, 24)
+	f.Write([]byte("before\r\n"))
+
+	recent, output, cancel := f.SubscribeOutput()
+	defer cancel()
+
+	if string(recent) != "before\r\n" {
+		t.Fatalf("recent = %q, want the output written before subscribing", recent)
+	}
+
+	f.Write([]byte("after"))
+
+	if got := <-output; string(got) != "after" {
+		t.Fatalf("output = %q, want %q", got, "after")
+	}
+}
+
+func TestOutputFanout_BacklogIsBoundedAndStartsAtALine(t *testing.T) {
+	f := newOutputFanout(80, 24)
+	line := strings.Repeat("x", 99) + "\n"
+
+	for range outputBacklogLimit/len(line) + 10 {
+		f.Write([]byte(line))
+	}
+
+	recent, _, cancel := f.SubscribeOutput()
+	defer cancel()
+
+	if len(recent) > outputBacklogLimit {
+		t.Fatalf("backlog = %d bytes, want at most %d", len(recent), outputBacklogLimit)
+	}
+
+	if !bytes.HasPrefix(recent, []byte(line)) {
+		t.Fatalf("backlog starts %q, want a whole line", recent[:20])
+	}
+}
+
+func TestOutputFanout_DropsSlowSubscriber(t *testing.T) {
+	f := newOutputFanout(80, 24)
+
+	_, output, cancel := f.SubscribeOutput()
+	defer cancel()
+
+	for range observerQueueLimit + 1 {
+		f.Write([]byte("x"))
+	}
+
+	received := 0
+	for range output {
+		received++
+	}
+
+	if received != observerQueueLimit {
+		t.Fatalf("received %d chunks before the channel closed, want %d", received, observerQueueLimit)
+	}
+}
+
+func TestOutputFanout_ResizeDiscardsBacklog(t *testing.T) {
+	f := newOutputFanout(80, 24)
+	f.Write([]byte("drawn at 80 columns"))
+	f.Resize(120, 30)
+
+	if cols, rows := f.TerminalSize(); cols != 120 || rows != 30 {
+		t.Fatalf("TerminalSize() = %d×%d, want 120×30", cols, rows)
+	}
+
+	recent, _, cancel := f.SubscribeOutput()
+	defer cancel()
+
+	if len(recent) != 0 {
+		t.Fatalf("recent = %q after resize, want none", recent)
+	}
+}
@@ -0,0 +1,180 @@
+package harness
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+const (
+	// defaultOutputPumpLimit bounds the terminal output waiting to be drawn.
+	defaultOutputPumpLimit = 4 << 20
+
+	// dropResyncWindow is how far past a drop the pump looks for the start
+	// of an escape sequence, so the terminal does not resume mid-sequence.
+	dropResyncWindow = 4 << 10
+)
+
+// outputPump keeps a slow terminal from stalling the harness. Write queues
+// mirror output and returns at once; a goroutine writes it to dst, joining
+// everything queued since its last write into one. When more than limit
+// bytes are waiting, the oldest are dropped and counted.
+//
+// Only the terminal mirror goes through the pump. Capture (transcripts,
+// completion detection) reads the output directly and never loses bytes.
+type outputPump struct {
+	dst   io.Writer
+	limit int
+
+	// onDrop is called, outside the lock, the first time output is dropped
+	// after the pump last caught up.
+	onDrop func()
+
+	mu       sync.Mutex
+	pending  []byte
+	dropped  int64
+	dropping bool
+	closed   bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+func newOutputPump(dst io.Writer, limit int, onDrop func()) *outputPump {
+	if limit <= 0 {
+		limit = defaultOutputPumpLimit
+	}
+
+	p := &outputPump{
+		dst:    dst,
+		limit:  limit,
+		onDrop: onDrop,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	go p.run()
+
+	return p
+}
+
+// Write queues b for the terminal. It never blocks on the terminal and
+// always reports the whole of b as written.
+func (p *outputPump) Write(b []byte) (int, error) {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return len(b), nil
+	}
+
+	p.pending = append(p.pending, b...)
+
+	notify := false
+
+	if over := len(p.pending) - p.limit; over > 0 {
+		cut := dropCut(p.pending, over)
+		p.pending = append(p.pending[:0], p.pending[cut:]...)
+		p.dropped += int64(cut)
+
+		notify = !p.dropping
+		p.dropping = true
+	}
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+
+	p.mu.Unlock()
+
+	if notify && p.onDrop != nil {
+		p.onDrop()
+	}
+
+	return len(b), nil
+}
+
+// Dropped returns the number of bytes dropped since the pump started.
+func (p *outputPump) Dropped() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.dropped
+}
+
+// Close writes what is still queued and stops the pump. It waits at most
+// timeout for a blocked terminal write to finish.
+func (p *outputPump) Close(timeout time.Duration) {
+	p.mu.Lock()
+
+	if p.closed {
+		p.mu.Unlock()
+
+		return
+	}
+
+	p.closed = true
+	close(p.wake)
+	p.mu.Unlock()
+
+	select {
+	case <-p.done:
+	case <-time.After(timeout):
+	}
+}
+
+func (p *outputPump) run() {
+	defer close(p.done)
+
+	var buf []byte
+
+	for range p.wake {
+		buf = p.flush(buf)
+	}
+
+	p.flush(buf)
+}
+
+// flush swaps the queue with buf and writes what was queued, so queueing
+// and writing alternate between two buffers without allocating.
+func (p *outputPump) flush(buf []byte) []byte {
+	p.mu.Lock()
+	buf, p.pending = p.pending, buf[:0]
+	p.mu.Unlock()
+
+	if len(buf) > 0 {
+		_, _ = p.dst.Write(buf)
+	}
+
+	// The pump has caught up only once a write leaves nothing queued behind
+	// it; until then, further drops belong to the same episode.
+	p.mu.Lock()
+	if len(p.pending) == 0 {
+		p.dropping = false
+	}
+	p.mu.Unlock()
+
+	return buf
+}
+
+// dropCut returns how many leading bytes of buf to drop to shed at least n.
+// The cut moves forward to the next escape sequence nearby, or else to a
+// rune boundary, so what remains starts cleanly.
+func dropCut(buf []byte, n int) int {
+	window := buf[n:min(len(buf), n+dropResyncWindow)]
+	if i := bytes.Index(window, []byte(ansi.ESC)); i >= 0 {
+		return n + i
+	}
+
+	for n < len(buf) && !utf8.RuneStart(buf[n]) {
+		n++
+	}
+
+	return n
+}
@@ -0,0 +1,181 @@
+package harness
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slowTerminal blocks each write until the test releases it.
+type slowTerminal struct {
+	release chan struct{}
+	started chan struct{}
+
+	mu     sync.Mutex
+	out    bytes.Buffer
+	writes int
+}
+
+func newSlowTerminal() *slowTerminal {
+	return &slowTerminal{release: make(chan struct{}), started: make(chan struct{}, 16)}
+}
+
+func (w *slowTerminal) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writes++
+
+	return w.out.Write(p)
+}
+
+func (w *slowTerminal) contents() (string, int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.out.String(), w.writes
+}
+
+func TestOutputPump_WriteDoesNotBlockOnSlowTerminal(t *testing.T) {
+	term := newSlowTerminal()
+	pump := newOutputPump(term, 1<<10, nil)
+
+	_, _ = pump.Write([]byte("first "))
+	<-term.started
+
+	// The terminal is stuck on the first write; these must return at once
+	// and be joined into a single write.
+	returned := make(chan struct{})
+
+	go func() {
+		for _, chunk := range []string{"a", "b", "c"} {
+			_, _ = pump.Write([]byte(chunk))
+		}
+
+		close(returned)
+	}()
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked on a slow terminal")
+	}
+
+	close(term.release)
+	pump.Close(time.Second)
+
+	out, writes := term.contents()
+	if out != "first abc" {
+		t.Fatalf("terminal got %q, want %q", out, "first abc")
+	}
+
+	if writes != 2 {
+		t.Fatalf("terminal writes = %d, want 2 (queued chunks joined)", writes)
+	}
+
+	if got := pump.Dropped(); got != 0 {
+		t.Fatalf("Dropped() = %d, want 0", got)
+	}
+}
+
+func TestOutputPump_DropsOldestWhenFull(t *testing.T) {
+	term := newSlowTerminal()
+
+	var drops int
+
+	pump := newOutputPump(term, 16, func() { drops++ })
+
+	_, _ = pump.Write([]byte("x"))
+	<-term.started
+
+	_, _ = pump.Write([]byte("old old old "))
+	_, _ = pump.Write([]byte("\x1b[1mnew\x1b[0m"))
+	_, _ = pump.Write([]byte("!"))
+
+	close(term.release)
+	pump.Close(time.Second)
+
+	out, _ := term.contents()
+	if want := "x\x1b[1mnew\x1b[0m!"; out != want {
+		t.Fatalf("terminal got %q, want %q (resumed at the escape sequence)", out, want)
+	}
+
+	if got := pump.Dropped(); got != int64(len("old old old ")) {
+		t.Fatalf("Dropped() = %d, want %d", got, len("old old old "))
+	}
+
+	if drops != 1 {
+		t.Fatalf("onDrop calls = %d, want 1 per episode", drops)
+	}
+}
+
+func TestOutputPump_ReportsDropOncePerEpisodeAcrossFlushes(t *testing.T) {
+	term := newSlowTerminal()
+
+	var drops int
+
+	pump := newOutputPump(term, 16, func() { drops++ })
+
+	_, _ = pump.Write([]byte("x"))
+	<-term.started
+
+	// Each round overflows the queue while the terminal is stuck, then lets
+	// one write finish, so the next flush takes a full queue and blocks too.
+	for range 3 {
+		_, _ = pump.Write([]byte(strings.Repeat("y", 20)))
+
+		term.release <- struct{}{}
+		<-term.started
+	}
+
+	if drops != 1 {
+		t.Fatalf("onDrop calls while the terminal stayed behind = %d, want 1", drops)
+	}
+
+	close(term.release)
+	pump.Close(time.Second)
+}
+
+func TestOutputPump_CloseFlushesAndIgnoresLaterWrites(t *testing.T) {
+	var out strings.Builder
+
+	pump := newOutputPump(&out, 0, nil)
+
+	_, _ = pump.Write([]byte("done"))
+	pump.Close(time.Second)
+
+	if n, err := pump.Write([]byte("late")); n != 4 || err != nil {
+		t.Fatalf("Write after Close = (%d, %v), want (4, nil)", n, err)
+	}
+
+	if out.String() != "done" {
+		t.Fatalf("terminal got %q, want %q", out.String(), "done")
+	}
+}
+
+func TestDropCut(t *testing.T) {
+	tests := []struct {
+		name string
+		buf  string
+		n    int
+		want int
+	}{
+		{name: "moves to the next escape sequence", buf: "abc\x1b[0mdef", n: 1, want: 3},
+		{name: "cut already at a sequence", buf: "ab\x1b[0m", n: 2, want: 2},
+		{name: "plain text cuts on a rune boundary", buf: "a你好", n: 2, want: 4},
+		{name: "drops everything", buf: "abc", n: 3, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dropCut([]byte(tt.buf), tt.n); got != tt.want {
+				t.Fatalf("dropCut(%q, %d) = %d, want %d", tt.buf, tt.n, got, tt.want)
+			}
+		})
+	}
+}
@@ -96,6 +96,10 @@ func TestGetProvider_Claude(t *testing.T) {
 	if spec.Status.AuthCheck.Path != "~/.claude/.credentials.json" {
 		t.Fatalf("Status.AuthCheck.Path = %q, want ~/.claude/.credentials.json", spec.Status.AuthCheck.Path)
 	}
+
+	if spec.Completion == nil || spec.Completion.Mode != "signal_file" || spec.Completion.File != "complete" {
+		t.Fatalf("Completion = %+v, want signal_file on complete", spec.Completion)
+	}
 }
 
 func TestGetProvider_Codex(t *testing.T) {
@@ -131,6 +135,10 @@ func TestGetProvider_Codex(t *testing.T) {
 	if spec.Status.AuthCheck != nil {
 		t.Fatal("expected Status.AuthCheck to be nil for codex")
 	}
+
+	if spec.Completion != nil {
+		t.Fatalf("Completion = %+v, want nil for one-shot codex", spec.Completion)
+	}
 }
 
 func TestGetProvider_Copilot(t *testing.T) {
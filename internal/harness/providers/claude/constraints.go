@@ -0,0 +1,89 @@
+//go:build unix || windows
+
+package claude
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// constraintPollInterval is how often a running job's usage is checked
+// against its constraints.
+const constraintPollInterval = 2 * time.Second
+
+// jobConstraints returns job's turn and budget limits, or nil when it has
+// neither.
+//
+// The Claude session is interactive and outlives each job, so --max-turns,
+// which only applies to a single print-mode run, can't carry the limit.
+// Both limits are enforced from the session transcript instead.
+func jobConstraints(job *client.Job) *client.HarnessConstraints {
+	if job.Execution == nil || job.Execution.Constraints == nil {
+		return nil
+	}
+
+	constraints := job.Execution.Constraints
+	if constraints.MaxTurns <= 0 && constraints.MaxBudgetUSD <= 0 {
+		return nil
+	}
+
+	return constraints
+}
+
+// exceededConstraint returns the failure for usage past a limit, or nil.
+// A job may finish the turn that reaches its limit; the next one fails it.
+func exceededConstraint(usage harnesstype.Usage, constraints *client.HarnessConstraints) *harnesstype.ExecError {
+	if constraints.MaxBudgetUSD > 0 && usage.CostKnown && usage.CostUSD > constraints.MaxBudgetUSD {
+		return &harnesstype.ExecError{
+			Reason:  "budget_exceeded",
+			Message: fmt.Sprintf("job spent $%.2f, over its $%.2f budget", usage.CostUSD, constraints.MaxBudgetUSD),
+		}
+	}
+
+	if constraints.MaxTurns > 0 && usage.Turns > constraints.MaxTurns {
+		return &harnesstype.ExecError{
+			Reason:  "max_turns_exceeded",
+			Message: fmt.Sprintf("job took %d turns, over its limit of %d", usage.Turns, constraints.MaxTurns),
+		}
+	}
+
+	return nil
+}
+
+// checkConstraints returns the failure for the running job's usage, or nil.
+func (e *Executor) checkConstraints(constraints *client.HarnessConstraints) *harnesstype.ExecError {
+	usage, ok := e.JobUsage()
+	if !ok {
+		return nil
+	}
+
+	return exceededConstraint(usage, constraints)
+}
+
+// enforceConstraints checks the running job's usage until ctx is canceled.
+// When the job goes over a limit, Claude's turn is interrupted and cancel
+// is called with the failure.
+func (e *Executor) enforceConstraints(ctx context.Context, cancel context.CancelCauseFunc, constraints *client.HarnessConstraints) {
+	ticker := time.NewTicker(constraintPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if failure := e.checkConstraints(constraints); failure != nil {
+			_ = e.Interrupt()
+
+			cancel(failure)
+
+			return
+		}
+	}
+}
@@ -0,0 +1,84 @@
+//go:build unix
+
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestExceededConstraint(t *testing.T) {
+	limits := &client.HarnessConstraints{MaxTurns: 10, MaxBudgetUSD: 2}
+
+	tests := []struct {
+		name       string
+		usage      harnesstype.Usage
+		wantReason string
+	}{
+		{name: "within limits", usage: harnesstype.Usage{Turns: 10, CostUSD: 2, CostKnown: true}},
+		{name: "over turns", usage: harnesstype.Usage{Turns: 11}, wantReason: "max_turns_exceeded"},
+		{name: "over budget", usage: harnesstype.Usage{Turns: 3, CostUSD: 2.01, CostKnown: true}, wantReason: "budget_exceeded"},
+		{name: "unknown cost", usage: harnesstype.Usage{Turns: 3, CostUSD: 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := exceededConstraint(tt.usage, limits)
+
+			if tt.wantReason == "" {
+				if got != nil {
+					t.Fatalf("exceededConstraint() = %+v, want nil", got)
+				}
+
+				return
+			}
+
+			if got == nil || got.Reason != tt.wantReason || got.Retry {
+				t.Fatalf("exceededConstraint() = %+v, want permanent %s", got, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestJobConstraints(t *testing.T) {
+	if got := jobConstraints(&client.Job{}); got != nil {
+		t.Fatalf("jobConstraints() without execution = %+v, want nil", got)
+	}
+
+	unlimited := &client.Job{Execution: &client.ExecutionConfig{Constraints: &client.HarnessConstraints{}}}
+	if got := jobConstraints(unlimited); got != nil {
+		t.Fatalf("jobConstraints() with zero limits = %+v, want nil", got)
+	}
+}
+
+func TestClaudeCheckConstraintsReadsTranscript(t *testing.T) {
+	dir := t.TempDir()
+	transcript := filepath.Join(dir, "session.jsonl")
+
+	appendTranscript(t, transcript,
+		`{"type":"assistant","timestamp":"2026-03-01T10:00:02Z","costUSD":0.75,"message":{"id":"msg_1","usage":{"output_tokens":5}}}`+"\n"+
+			`{"type":"assistant","timestamp":"2026-03-01T10:00:09Z","costUSD":0.5,"message":{"id":"msg_2","usage":{"output_tokens":5}}}`+"\n")
+
+	session := `{"transcript_path":"` + transcript + `"}`
+	if err := os.WriteFile(filepath.Join(dir, SessionFileName), []byte(session), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e := NewExecutor()
+	e.signalDir = dir
+	e.usage = newTranscriptUsage(time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC))
+
+	failure := e.checkConstraints(&client.HarnessConstraints{MaxBudgetUSD: 1})
+	if failure == nil || failure.Reason != "budget_exceeded" || failure.Message != "job spent $1.25, over its $1.00 budget" {
+		t.Fatalf("checkConstraints() = %+v, want the budget failure", failure)
+	}
+
+	if failure := e.checkConstraints(&client.HarnessConstraints{MaxTurns: 2}); failure != nil {
+		t.Fatalf("checkConstraints() at the turn limit = %+v, want nil", failure)
+	}
+}
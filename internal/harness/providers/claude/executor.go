@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package claude
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"os/exec"
@@ -14,12 +15,11 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/creack/pty"
-
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/executil"
 	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
 )
 
 // PTYWriteChunkSize is the max bytes to write to the PTY at once.
@@ -37,30 +37,83 @@ const PTYPasteSettleDelay = 500 * time.Millisecond
 
 const defaultPTYShutdownDeadline = 3 * time.Second
 
+// sessionPhase is where the Claude session is in its lifecycle.
+type sessionPhase int
+
+const (
+	// phaseStarting means the process is starting or restarting and its
+	// prompt has not been confirmed yet.
+	phaseStarting sessionPhase = iota
+	// phaseIdle means the prompt is confirmed and no job is running.
+	phaseIdle
+	// phaseRunning means a job prompt was injected; output is captured
+	// until its completion signal arrives.
+	phaseRunning
+)
+
+// ptyProcess is one Claude process and the PTY it runs in.
+type ptyProcess struct {
+	ptmx harnesstype.PTY
+	cmd  *exec.Cmd
+	pgid int
+
+	// exited is closed once cmd.Wait returns. Only newPTYProcess waits on
+	// cmd, so closePTY and watchProcessExit never call Wait twice.
+	exited chan struct{}
+}
+
+// newPTYProcess tracks a started cmd and begins waiting for it to exit.
+func newPTYProcess(cmd *exec.Cmd, ptmx harnesstype.PTY) *ptyProcess {
+	proc := &ptyProcess{ptmx: ptmx, cmd: cmd, exited: make(chan struct{})}
+
+	if cmd == nil || cmd.Process == nil {
+		close(proc.exited)
+		return proc
+	}
+
+	proc.pgid = harnesstype.ProcessGroupID(cmd)
+
+	go func() {
+		_ = cmd.Wait()
+
+		close(proc.exited)
+	}()
+
+	return proc
+}
+
 // Executor runs jobs via Claude Code in a persistent PTY session.
+//
+// The output reader, the executor's callers, and process watchers run on
+// different goroutines. All mutable session state is guarded by mu, which
+// is never held while writing to the PTY or calling SetupOptions callbacks.
 type Executor struct {
-	mu     sync.Mutex
 	logger *slog.Logger
-	ptmx   *os.File
-	cmd    *exec.Cmd
-	pgid   int
 
-	opts harnesstype.SetupOptions
+	mu sync.Mutex
 
-	// promptDetected is signaled when Claude is ready for input.
-	promptDetected  chan struct{}
-	readyForJob     bool
-	lastPromptSeen  time.Time
+	opts      harnesstype.SetupOptions
+	signalDir string
+	proc      *ptyProcess
+
+	phase           sessionPhase
 	promptConfirmed bool
+	promptTimer     *time.Timer
 	bypassAccepted  bool
 
-	// Output capture during job execution.
-	captureMu    sync.Mutex
+	// Output captured while a job runs.
 	outputBuffer bytes.Buffer
-	capturing    bool
+	lastOutputAt time.Time
 
-	// Signal directory for completion detection.
-	signalDir string
+	// Detector that watches for the spec's completion signal.
+	completion harnesstype.CompletionDetector
+
+	// Usage of the running job, read from the session transcript.
+	usage *transcriptUsage
+
+	// Process supervision counters, and when the current PTY started.
+	supervision  harnesstype.Supervision
+	ptyStartedAt time.Time
 
 	// MCP config management.
 	mcpConfigPath   string
@@ -69,24 +122,29 @@ type Executor struct {
 	loadedMCPNames  []string
 	runnerConfig    *client.RunnerConfigResponse
 
+	// hooks restore function.
+	restoreHooks func() error
+
+	// The fields below are set by NewExecutor (or tests) before the
+	// executor is used and never change.
+
 	// PTY injection helpers (injectable for tests).
-	setPTYSize       func(*os.File, *pty.Winsize) error
-	startPTYWithSize func(*exec.Cmd, *pty.Winsize) (*os.File, error)
+	startProcessPTY  func(cmd *exec.Cmd, rows, cols int) (harnesstype.PTY, error)
 	startPTYFunc     func(context.Context) error
 	startOutputFunc  func()
 	waitForReadyFunc func(context.Context) bool
 	watchExitFunc    func()
 
+	// promptDetected is signaled when Claude is ready for input.
+	promptDetected chan struct{}
+
 	// ptyReady delivers active PTY handles to the output reader loop.
-	ptyReady chan *os.File
+	ptyReady chan harnesstype.PTY
 
 	// done signals executor shutdown.
 	done     chan struct{}
 	doneOnce sync.Once
 
-	// hooks restore function.
-	restoreHooks func() error
-
 	// shutdown deadline for PTY process.
 	ptyShutdownDeadline time.Duration
 
@@ -94,16 +152,30 @@ type Executor struct {
 	outputReaderDone chan struct{}
 }
 
+// sessionConfig is the executor configuration read by the output reader
+// and process watchers, copied under mu.
+type sessionConfig struct {
+	opts       harnesstype.SetupOptions
+	signalDir  string
+	completion harnesstype.CompletionDetector
+}
+
+func (e *Executor) config() sessionConfig {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return sessionConfig{opts: e.opts, signalDir: e.signalDir, completion: e.completion}
+}
+
 // NewExecutor creates a new Executor with default settings.
 func NewExecutor() *Executor {
 	executor := &Executor{
 		logger:              slog.Default(),
 		promptDetected:      make(chan struct{}, 1),
-		ptyReady:            make(chan *os.File, 4),
+		ptyReady:            make(chan harnesstype.PTY, 4),
 		done:                make(chan struct{}),
 		outputReaderDone:    make(chan struct{}),
-		setPTYSize:          pty.Setsize,
-		startPTYWithSize:    pty.StartWithSize,
+		startProcessPTY:     harnesstype.StartPTY,
 		ptyShutdownDeadline: defaultPTYShutdownDeadline,
 	}
 
@@ -118,17 +190,32 @@ func NewExecutor() *Executor {
 
 // Setup initializes the Claude executor: signal dir, stop hook, MCP config, PTY.
 func (e *Executor) Setup(ctx context.Context, opts *harnesstype.SetupOptions) error {
+	e.mu.Lock()
 	e.opts = *opts
 	e.signalDir = opts.SignalDir
+	e.mu.Unlock()
 
 	// Install Stop hook for completion signaling.
-	if e.signalDir != "" {
-		restoreHooks, err := InstallStopHook(e.signalDir)
+	if opts.SignalDir != "" {
+		restoreHooks, err := InstallStopHook(opts.SignalDir)
 		if err != nil {
 			return err
 		}
 
+		e.mu.Lock()
 		e.restoreHooks = restoreHooks
+		e.mu.Unlock()
+	}
+
+	if !spec.Completion.NeedsSignalDir() || opts.SignalDir != "" {
+		detector, err := harnesstype.NewCompletionDetector(spec.Completion, opts.SignalDir)
+		if err != nil {
+			return fmt.Errorf("configure completion detection: %w", err)
+		}
+
+		e.mu.Lock()
+		e.completion = detector
+		e.mu.Unlock()
 	}
 
 	// Build ephemeral Claude MCP config from runner config.
@@ -161,30 +248,11 @@ func (e *Executor) Setup(ctx context.Context, opts *harnesstype.SetupOptions) er
 	startOutput()
 
 	// Watch for process exit and notify harness.
-	// Capture cmd locally to avoid racing with Teardown/closePTY setting e.cmd = nil.
-	// We also check the done channel so that intentional PTY restarts (e.g. ApplyRefresh)
-	// do not spuriously fire OnExit and tear down the harness.
+	// Capture the process now so a later restart is not mistaken for it.
 	watchExit := e.watchExitFunc
 	if watchExit == nil {
-		cmd := e.cmd
-		watchExit = func() {
-			if cmd == nil {
-				return
-			}
-
-			go func() {
-				_ = cmd.Wait()
-
-				select {
-				case <-e.done:
-					// Executor is shutting down intentionally; do not fire OnExit.
-				default:
-					if opts.OnExit != nil {
-						opts.OnExit()
-					}
-				}
-			}()
-		}
+		proc := e.activeProcess()
+		watchExit = func() { e.watchProcessExit(proc) }
 	}
 
 	watchExit()
@@ -215,52 +283,186 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		return nil, &harnesstype.ExecError{Reason: "prompt_error", Message: err.Error()}
 	}
 
-	// Clear any prior signal file and record current job.
-	if e.signalDir != "" {
-		_ = os.Remove(e.signalPath())
-		_ = os.WriteFile(e.currentJobPath(), []byte(job.ID), 0o600)
+	// The PTY's environment was fixed when Claude started, so the job
+	// directories are spelled out in the prompt instead.
+	if preamble := harnesstype.JobEnvPreamble(job); preamble != "" {
+		prompt = preamble + "\n" + prompt
+	}
+
+	cfg := e.config()
+
+	// Clear any prior completion signal and record current job.
+	if cfg.completion != nil {
+		cfg.completion.Reset()
+	}
+
+	if cfg.signalDir != "" {
+		_ = os.WriteFile(currentJobPath(cfg.signalDir), []byte(job.ID), 0o600)
+		removeSessionFile(cfg.signalDir)
 	}
 
 	// Start capturing output.
-	e.captureMu.Lock()
-	e.capturing = true
+	e.mu.Lock()
+	e.phase = phaseRunning
 	e.outputBuffer.Reset()
-	e.readyForJob = false
-	e.captureMu.Unlock()
+	e.lastOutputAt = time.Time{}
+
+	if cfg.signalDir != "" {
+		e.usage = newTranscriptUsage(time.Now())
+	}
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.usage = nil
+		e.phase = phaseIdle
+		e.outputBuffer.Reset()
+		e.mu.Unlock()
+	}()
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "harness"))
 
 	// Inject the prompt into the PTY.
 	e.injectPrompt(prompt)
+	logger.Debug("prompt injected",
+		slog.String("event.type", "harness.prompt.inject"),
+		slog.Int("prompt.length", len(prompt)),
+	)
 
 	startedAt := time.Now()
 
+	// waitCtx is canceled with an *ExecError if the job goes over a limit.
+	waitCtx := ctx
+
+	if constraints := jobConstraints(job); constraints != nil && cfg.signalDir != "" {
+		var cancelWait context.CancelCauseFunc
+
+		waitCtx, cancelWait = context.WithCancelCause(ctx)
+		enforced := make(chan struct{})
+
+		go func() {
+			defer close(enforced)
+			e.enforceConstraints(waitCtx, cancelWait, constraints)
+		}()
+
+		defer func() {
+			cancelWait(nil)
+			<-enforced
+		}()
+	}
+
 	// Wait for completion signal with timeout.
-	output, execErr := e.waitForSignalFile(ctx)
+	output, execErr := e.waitForCompletion(waitCtx, cfg.completion)
 	duration := time.Since(startedAt)
 
 	if execErr != nil {
+		var limitErr *harnesstype.ExecError
+		if ctx.Err() == nil && errors.As(context.Cause(waitCtx), &limitErr) {
+			logger.Info("job stopped at its limit",
+				slog.String("event.type", "harness.constraint.exceeded"),
+				slog.String("job.error_code", limitErr.Reason),
+				slog.String("error", limitErr.Message),
+			)
+
+			e.mu.Lock()
+			partial := e.outputBuffer.String()
+			lastOutputAt := e.lastOutputAt
+			e.mu.Unlock()
+
+			return nil, limitErr.WithPartialOutput(partial, lastOutputAt)
+		}
+
 		reason := "execution_error"
 		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
 			reason = "timeout"
 		}
 
-		return nil, &harnesstype.ExecError{Reason: reason, Message: execErr.Error(), Retry: true}
+		logger.Debug("completion signal not received",
+			slog.String("event.type", "harness.signal.error"),
+			slog.Int64("duration_ms", duration.Milliseconds()),
+			slog.String("error", execErr.Error()),
+		)
+
+		e.mu.Lock()
+		partial := e.outputBuffer.String()
+		lastOutputAt := e.lastOutputAt
+		e.mu.Unlock()
+
+		failure := &harnesstype.ExecError{Reason: reason, Message: execErr.Error(), Retry: true}
+
+		return nil, failure.WithPartialOutput(partial, lastOutputAt)
+	}
+
+	logger.Debug("completion signal received",
+		slog.String("event.type", "harness.signal.complete"),
+		slog.Int64("duration_ms", duration.Milliseconds()),
+	)
+
+	result := harnesstype.NewClaudeJobOutput(output, duration)
+	if usage, ok := e.JobUsage(); ok {
+		result.SetUsage(usage)
 	}
 
-	return &harnesstype.ExecResult{
-		OutputData: map[string]any{
-			"success":    true,
-			"output":     output,
-			"durationMs": int(duration / time.Millisecond),
-		},
-	}, nil
+	return &harnesstype.ExecResult{Output: result}, nil
+}
+
+// WarnTimeout types a wrap-up note into the running session. Claude queues
+// it behind the turn in progress and reads it before finishing.
+func (e *Executor) WarnTimeout(remaining time.Duration) error {
+	e.mu.Lock()
+	running := e.phase == phaseRunning
+	e.mu.Unlock()
+
+	if !running {
+		return errors.New("no job running")
+	}
+
+	if e.activePTY() == nil {
+		return errors.New("claude is not running")
+	}
+
+	e.injectPrompt(harnesstype.TimeoutWarningMessage(remaining))
+
+	return nil
+}
+
+// BeginLocalTask implements LocalTaskRunner. It clears any stale completion
+// signal so only the Stop hook for the user's own prompt ends the task.
+func (e *Executor) BeginLocalTask() {
+	if completion := e.config().completion; completion != nil {
+		completion.Reset()
+	}
+
+	e.mu.Lock()
+	e.phase = phaseRunning
+	e.outputBuffer.Reset()
+	e.lastOutputAt = time.Time{}
+	e.mu.Unlock()
+}
+
+// WaitLocalTask implements LocalTaskRunner.
+func (e *Executor) WaitLocalTask(ctx context.Context) (string, error) {
+	defer func() {
+		e.mu.Lock()
+		e.phase = phaseIdle
+		e.outputBuffer.Reset()
+		e.mu.Unlock()
+	}()
+
+	return e.waitForCompletion(ctx, e.config().completion)
 }
 
 // Reset sends /clear and waits for the prompt to reappear.
 func (e *Executor) Reset(ctx context.Context) error {
+	cfg := e.config()
+
 	// Clean up signal/job files.
-	if e.signalDir != "" {
-		_ = os.Remove(e.currentJobPath())
-		_ = os.Remove(e.signalPath())
+	if cfg.completion != nil {
+		cfg.completion.Reset()
+	}
+
+	if cfg.signalDir != "" {
+		_ = os.Remove(currentJobPath(cfg.signalDir))
 	}
 
 	e.sendClear()
@@ -286,6 +488,12 @@ func (e *Executor) Teardown() {
 		close(e.done)
 	})
 
+	e.mu.Lock()
+	if e.promptTimer != nil {
+		e.promptTimer.Stop()
+	}
+	e.mu.Unlock()
+
 	e.closePTY()
 
 	// Wait for output reader to finish.
@@ -296,38 +504,30 @@ func (e *Executor) Teardown() {
 
 	e.cleanupMCPConfigFile()
 
-	if e.restoreHooks != nil {
-		_ = e.restoreHooks()
-		e.restoreHooks = nil
+	e.mu.Lock()
+	restoreHooks := e.restoreHooks
+	e.restoreHooks = nil
+	e.mu.Unlock()
+
+	if restoreHooks != nil {
+		_ = restoreHooks()
 	}
 }
 
 // Resize implements Resizable.
 func (e *Executor) Resize(rows, cols int) {
-	e.mu.Lock()
-	ptmx := e.ptmx
-	e.mu.Unlock()
+	ptmx := e.activePTY()
 
 	if ptmx == nil {
 		return
 	}
 
-	setSize := e.setPTYSize
-	if setSize == nil {
-		setSize = pty.Setsize
-	}
-
-	_ = setSize(ptmx, &pty.Winsize{
-		Rows: uint16(rows),
-		Cols: uint16(cols),
-	})
+	_ = ptmx.Resize(rows, cols)
 }
 
 // WriteInput implements InputReceiver.
 func (e *Executor) WriteInput(p []byte) (int, error) {
-	e.mu.Lock()
-	ptmx := e.ptmx
-	e.mu.Unlock()
+	ptmx := e.activePTY()
 
 	if ptmx == nil {
 		return 0, nil
@@ -358,7 +558,7 @@ func (e *Executor) NeedsRefresh(cfg *client.RunnerConfigResponse) bool {
 
 // ApplyRefresh implements Refreshable.
 func (e *Executor) ApplyRefresh(ctx context.Context, cfg *client.RunnerConfigResponse) error {
-	oldNames := e.loadedMCPNames
+	oldNames := e.loadedMCPServers()
 
 	if err := e.applyRunnerConfig(cfg); err != nil {
 		e.logger.Error(
@@ -371,18 +571,16 @@ func (e *Executor) ApplyRefresh(ctx context.Context, cfg *client.RunnerConfigRes
 		return err
 	}
 
-	e.closePTY()
-
-	if err := e.startPTY(ctx); err != nil {
+	if err := e.restartPTY(ctx); err != nil {
 		return err
 	}
 
 	e.waitForReady(ctx)
 
-	newNames := e.loadedMCPNames
-	if !harnesstype.SameStringSlice(oldNames, newNames) && e.opts.OnOutput != nil {
+	newNames := e.loadedMCPServers()
+	if onOutput := e.config().opts.OnOutput; !harnesstype.SameStringSlice(oldNames, newNames) && onOutput != nil {
 		msg := fmt.Sprintf("MCP servers reloaded: %s\r\n", harnesstype.SummarizeMCPServers(newNames))
-		e.opts.OnOutput([]byte(msg))
+		onOutput([]byte(msg))
 	}
 
 	e.logger.Info(
@@ -396,9 +594,96 @@ func (e *Executor) ApplyRefresh(ctx context.Context, cfg *client.RunnerConfigRes
 	return nil
 }
 
+// ReloadBundle implements harnesstype.BundleReloader. Claude Code discovers
+// agents and reads the MCP config only when it starts, so the session is
+// restarted against the reloaded bundle directory.
+func (e *Executor) ReloadBundle(ctx context.Context) error {
+	if err := e.restartPTY(ctx); err != nil {
+		return err
+	}
+
+	waitForReady := e.waitForReadyFunc
+	if waitForReady == nil {
+		waitForReady = e.waitForReady
+	}
+
+	onReady := e.config().opts.OnReady
+
+	go func() {
+		if waitForReady(ctx) && onReady != nil {
+			onReady()
+		}
+	}()
+
+	e.logger.Info(
+		"bundle reloaded",
+		slog.String("component", "harness"),
+		slog.String("event.type", "harness.bundle.reload"),
+	)
+
+	return nil
+}
+
 // --- Internal methods ---
 
+// restartPTY replaces the Claude process, for changes it only reads at
+// startup, and watches the new process for exit.
+func (e *Executor) restartPTY(ctx context.Context) error {
+	e.closePTY()
+
+	startPTY := e.startPTYFunc
+	if startPTY == nil {
+		startPTY = e.startPTY
+	}
+
+	if err := startPTY(ctx); err != nil {
+		return err
+	}
+
+	e.watchProcessExit(e.activeProcess())
+
+	return nil
+}
+
+// watchProcessExit notifies the harness when proc exits on its own. Exits
+// caused by Teardown, or by closePTY replacing the process, are ignored so
+// they do not tear down the harness.
+func (e *Executor) watchProcessExit(proc *ptyProcess) {
+	if proc == nil {
+		return
+	}
+
+	go func() {
+		<-proc.exited
+
+		select {
+		case <-e.done:
+			return
+		default:
+		}
+
+		e.mu.Lock()
+		replaced := e.proc != proc
+		e.mu.Unlock()
+
+		if replaced {
+			return
+		}
+
+		cfg := e.config()
+
+		if cfg.completion != nil {
+			cfg.completion.Exited()
+		}
+
+		if cfg.opts.OnExit != nil {
+			cfg.opts.OnExit()
+		}
+	}()
+}
+
 func (e *Executor) startPTY(ctx context.Context) error {
+	cfg := e.config()
 	args := e.commandArgs()
 	e.logger.Debug(
 		"starting harness PTY",
@@ -415,41 +700,39 @@ func (e *Executor) startPTY(ctx context.Context) error {
 	cmd.Env = append(os.Environ(),
 		"TERM=xterm-256color",
 		"FORCE_COLOR=1",
-		"MUSHER_SIGNAL_DIR="+e.signalDir,
+		"MUSHER_SIGNAL_DIR="+cfg.signalDir,
 	)
 
-	cmd.Env = append(cmd.Env, e.opts.Env...)
-	if e.opts.WorkingDir != "" {
-		cmd.Dir = e.opts.WorkingDir
+	cmd.Env = append(cmd.Env, cfg.opts.Env...)
+	if cfg.opts.WorkingDir != "" {
+		cmd.Dir = cfg.opts.WorkingDir
 	}
 
 	// NOTE: cmd.Stdin/Stdout/Stderr must remain nil here.
-	// creack/pty.StartWithSize assigns the PTY tty to all three;
+	// harnesstype.StartPTY assigns the PTY tty to all three;
 	// pre-setting Stdin to a non-tty would break Setctty (fd 0 must be the tty).
-	startWithSize := e.startPTYWithSize
-	if startWithSize == nil {
-		startWithSize = pty.StartWithSize
+	startPTY := e.startProcessPTY
+	if startPTY == nil {
+		startPTY = harnesstype.StartPTY
 	}
 
-	ptmx, err := startWithSize(cmd, &pty.Winsize{
-		Rows: uint16(e.opts.TermHeight),
-		Cols: uint16(e.opts.TermWidth),
-	})
+	ptmx, err := startPTY(cmd, cfg.opts.TermHeight, cfg.opts.TermWidth)
 	if err != nil {
 		return harnesstype.AnnotateStartPTYError(err, cmd.Path) //nolint:wrapcheck // internal helper already wraps
 	}
 
+	proc := newPTYProcess(cmd, ptmx)
+
 	e.mu.Lock()
-	e.ptmx = ptmx
-	e.cmd = cmd
-	e.pgid = 0
+	e.proc = proc
+	e.promptConfirmed = false
 
-	if cmd.Process != nil && cmd.Process.Pid > 0 {
-		if pgid, pgErr := syscall.Getpgid(cmd.Process.Pid); pgErr == nil {
-			e.pgid = pgid
-		}
+	if e.phase != phaseRunning {
+		e.phase = phaseStarting
 	}
 
+	e.supervision.PTYStarts++
+	e.ptyStartedAt = time.Now()
 	e.mu.Unlock()
 
 	// Drain stale handles before delivering the new one.
@@ -463,14 +746,15 @@ func (e *Executor) startPTY(ctx context.Context) error {
 }
 
 func (e *Executor) commandArgs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
 	var args []string
 	if !e.opts.BundleLoadMode {
 		args = append(args, "--dangerously-skip-permissions")
 	}
 
-	if e.opts.BundleDir != "" && spec.BundleDir != nil && spec.BundleDir.Flag != "" {
-		args = append(args, spec.BundleDir.Flag, e.opts.BundleDir)
-	}
+	args = append(args, spec.BundleDir.Args(e.opts.BundleDir, e.opts.ExtraDirs)...)
 
 	if e.mcpConfigPath != "" && spec.CLI != nil && spec.CLI.MCPConfig != "" {
 		args = append(args, spec.CLI.MCPConfig, e.mcpConfigPath)
@@ -481,19 +765,19 @@ func (e *Executor) commandArgs() []string {
 
 func (e *Executor) closePTY() {
 	e.mu.Lock()
-	ptmx := e.ptmx
-	cmd := e.cmd
-	pgid := e.pgid
-	e.ptmx = nil
-	e.cmd = nil
-	e.pgid = 0
+	proc := e.proc
+	e.proc = nil
 	e.mu.Unlock()
 
-	if ptmx != nil {
-		_ = ptmx.Close()
+	if proc == nil {
+		return
+	}
+
+	if proc.ptmx != nil {
+		_ = proc.ptmx.Close()
 	}
 
-	if cmd == nil || cmd.Process == nil {
+	if proc.cmd == nil || proc.cmd.Process == nil {
 		return
 	}
 
@@ -503,13 +787,7 @@ func (e *Executor) closePTY() {
 		slog.String("event.type", "harness.pty.stop"),
 	)
 
-	waitCh := make(chan error, 1)
-
-	go func() {
-		waitCh <- cmd.Wait()
-	}()
-
-	harnesstype.SendSignal(cmd.Process.Pid, pgid, syscall.SIGTERM)
+	harnesstype.SendSignal(proc.cmd.Process.Pid, proc.pgid, syscall.SIGTERM)
 
 	deadline := e.ptyShutdownDeadline
 	if deadline <= 0 {
@@ -517,23 +795,31 @@ func (e *Executor) closePTY() {
 	}
 
 	select {
-	case <-waitCh:
+	case <-proc.exited:
 		return
 	case <-time.After(deadline):
-		harnesstype.SendSignal(cmd.Process.Pid, pgid, syscall.SIGKILL)
+		harnesstype.SendSignal(proc.cmd.Process.Pid, proc.pgid, syscall.SIGKILL)
 
 		select {
-		case <-waitCh:
+		case <-proc.exited:
 		case <-time.After(deadline):
 		}
 	}
 }
 
-func (e *Executor) activePTY() *os.File {
+func (e *Executor) activeProcess() *ptyProcess {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	return e.ptmx
+	return e.proc
+}
+
+func (e *Executor) activePTY() harnesstype.PTY {
+	if proc := e.activeProcess(); proc != nil {
+		return proc.ptmx
+	}
+
+	return nil
 }
 
 func (e *Executor) copyPTYOutput() {
@@ -553,12 +839,18 @@ func (e *Executor) copyPTYOutput() {
 	}
 }
 
-func (e *Executor) readPTYOutput(ptmx *os.File) {
+func (e *Executor) readPTYOutput(ptmx harnesstype.PTY) {
+	cfg := e.config()
 	buf := make([]byte, 4096)
 	promptRing := make([]byte, len(PromptDetectionBytes))
 	promptRingIdx := 0
 
-	var dialogBuf bytes.Buffer
+	// Dialog and prompt detection match on text with escape sequences
+	// removed, so styling between characters cannot hide a match.
+	var (
+		dialogBuf bytes.Buffer
+		stripper  ansi.Stripper
+	)
 
 	for {
 		select {
@@ -576,61 +868,57 @@ func (e *Executor) readPTYOutput(ptmx *os.File) {
 			continue
 		}
 
+		chunk := buf[:bytesRead]
+
 		// Write to terminal output.
-		if e.opts.TermWriter != nil {
-			_, _ = e.opts.TermWriter.Write(buf[:bytesRead])
+		if cfg.opts.TermWriter != nil {
+			_, _ = cfg.opts.TermWriter.Write(chunk)
 		}
 
-		if e.opts.OnOutput != nil {
-			e.opts.OnOutput(buf[:bytesRead])
+		if cfg.opts.OnOutput != nil {
+			cfg.opts.OnOutput(chunk)
 		}
 
-		// Detect bypass dialog and auto-accept (only in worker mode where
-		// --dangerously-skip-permissions triggers a trust dialog).
-		if !e.opts.BundleLoadMode {
-			e.captureMu.Lock()
+		if cfg.completion != nil {
+			cfg.completion.Observe(chunk)
+		}
 
-			if !e.bypassAccepted {
-				dialogBuf.Write(buf[:bytesRead])
+		text := stripper.Strip(chunk)
+		acceptBypass := false
 
-				if bytes.Contains(dialogBuf.Bytes(), []byte("Esc to cancel")) {
-					e.bypassAccepted = true
-					e.captureMu.Unlock()
-					dialogBuf.Reset()
+		e.mu.Lock()
 
-					go func() {
-						time.Sleep(300 * time.Millisecond)
+		// Capture output if we're processing a job.
+		if e.phase == phaseRunning {
+			e.outputBuffer.Write(chunk)
+			e.lastOutputAt = time.Now()
+		}
 
-						if active := e.activePTY(); active != nil {
-							_, _ = active.WriteString("\x1b[B")
+		e.promptConfirmed = false
+
+		// Detect bypass dialog and auto-accept (only in worker mode where
+		// --dangerously-skip-permissions triggers a trust dialog).
+		if !cfg.opts.BundleLoadMode && !e.bypassAccepted {
+			dialogBuf.Write(text)
 
-							time.Sleep(100 * time.Millisecond)
+			if bytes.Contains(dialogBuf.Bytes(), []byte("Esc to cancel")) {
+				e.bypassAccepted = true
+				e.supervision.BypassAccepts++
+				acceptBypass = true
 
-							_, _ = active.WriteString("\r")
-						}
-					}()
-				} else {
-					e.captureMu.Unlock()
-				}
-			} else {
-				e.captureMu.Unlock()
+				dialogBuf.Reset()
 			}
 		}
 
-		// Capture output if we're processing a job.
-		e.captureMu.Lock()
+		e.mu.Unlock()
 
-		if e.capturing {
-			e.outputBuffer.Write(buf[:bytesRead])
+		if acceptBypass {
+			go e.acceptBypassDialog()
 		}
 
-		e.promptConfirmed = false
-
-		e.captureMu.Unlock()
-
 		// Detect prompt pattern.
-		for i := 0; i < bytesRead; i++ {
-			promptRing[promptRingIdx] = buf[i]
+		for _, c := range text {
+			promptRing[promptRingIdx] = c
 			promptRingIdx = (promptRingIdx + 1) % len(PromptDetectionBytes)
 
 			if checkPromptMatch(promptRing, promptRingIdx) {
@@ -640,6 +928,20 @@ func (e *Executor) readPTYOutput(ptmx *os.File) {
 	}
 }
 
+// acceptBypassDialog selects "Yes, I accept" in Claude's bypass permissions
+// dialog once it has rendered.
+func (e *Executor) acceptBypassDialog() {
+	time.Sleep(300 * time.Millisecond)
+
+	if active := e.activePTY(); active != nil {
+		_, _ = io.WriteString(active, ansi.KeyDown)
+
+		time.Sleep(100 * time.Millisecond)
+
+		_, _ = io.WriteString(active, "\r")
+	}
+}
+
 func checkPromptMatch(ring []byte, idx int) bool {
 	for i := 0; i < len(PromptDetectionBytes); i++ {
 		ringIdx := (idx + i) % len(ring)
@@ -651,31 +953,39 @@ func checkPromptMatch(ring []byte, idx int) bool {
 	return true
 }
 
+// onPromptPatternSeen (re)starts the debounce timer; the prompt is
+// confirmed once it has been quiet for PromptDebounceTime.
 func (e *Executor) onPromptPatternSeen() {
-	e.captureMu.Lock()
-	e.lastPromptSeen = time.Now()
-	e.captureMu.Unlock()
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
-	go func() {
-		time.Sleep(PromptDebounceTime)
+	if e.promptTimer == nil {
+		e.promptTimer = time.AfterFunc(PromptDebounceTime, e.confirmPrompt)
+		return
+	}
 
-		e.captureMu.Lock()
+	e.promptTimer.Reset(PromptDebounceTime)
+}
 
-		timeSincePrompt := time.Since(e.lastPromptSeen)
-		if timeSincePrompt >= PromptDebounceTime-10*time.Millisecond && !e.promptConfirmed {
-			e.promptConfirmed = true
-			e.captureMu.Unlock()
-			e.onPromptConfirmed()
-		} else {
-			e.captureMu.Unlock()
-		}
-	}()
+// confirmPrompt signals readiness once per prompt: output after it clears
+// promptConfirmed so the next sighting can confirm again.
+func (e *Executor) confirmPrompt() {
+	e.mu.Lock()
+	confirmed := e.promptConfirmed
+	e.promptConfirmed = true
+	e.mu.Unlock()
+
+	if !confirmed {
+		e.onPromptConfirmed()
+	}
 }
 
 func (e *Executor) onPromptConfirmed() {
-	e.captureMu.Lock()
-	e.readyForJob = true
-	e.captureMu.Unlock()
+	e.mu.Lock()
+	if e.phase == phaseStarting {
+		e.phase = phaseIdle
+	}
+	e.mu.Unlock()
 
 	select {
 	case e.promptDetected <- struct{}{}:
@@ -696,22 +1006,46 @@ func (e *Executor) waitForReady(ctx context.Context) bool {
 	case <-e.done:
 		return false
 	case <-e.promptDetected:
+		e.recordReady(false)
+
 		return true
 	case <-time.After(15 * time.Second):
-		e.captureMu.Lock()
+		e.mu.Lock()
 		bypassed := e.bypassAccepted
-		e.captureMu.Unlock()
+		e.mu.Unlock()
 
 		if bypassed {
 			time.Sleep(2 * time.Second)
 		}
 
+		e.recordReady(true)
+
 		// Timeout is treated as ready (best-effort; prompt may have been
 		// missed or the harness may not emit a detectable prompt).
 		return true
 	}
 }
 
+// recordReady adds the time since the PTY started to the readiness totals.
+func (e *Executor) recordReady(timedOut bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.ptyStartedAt.IsZero() {
+		return
+	}
+
+	e.supervision.ReadyWaits++
+	e.supervision.ReadyTotal += time.Since(e.ptyStartedAt)
+
+	if timedOut {
+		e.supervision.ReadyTimeouts++
+	}
+
+	// Only the first wait after a start measures startup.
+	e.ptyStartedAt = time.Time{}
+}
+
 func (e *Executor) drainPromptDetected() {
 	for {
 		select {
@@ -745,35 +1079,32 @@ func (e *Executor) injectPrompt(prompt string) {
 
 	time.Sleep(PTYPasteSettleDelay)
 
-	_, _ = ptmx.WriteString("\r")
+	_, _ = io.WriteString(ptmx, "\r")
 }
 
-func (e *Executor) waitForSignalFile(ctx context.Context) (string, error) {
-	ticker := time.NewTicker(SignalPollInterval)
-	defer ticker.Stop()
-
-	for {
+// waitForCompletion waits for the spec's completion signal and returns the
+// output captured since the prompt was injected.
+func (e *Executor) waitForCompletion(ctx context.Context, completion harnesstype.CompletionDetector) (string, error) {
+	if completion == nil {
 		select {
 		case <-ctx.Done():
-			return "", fmt.Errorf("wait for signal file canceled: %w", ctx.Err())
+			return "", fmt.Errorf("wait for completion canceled: %w", ctx.Err())
 		case <-e.done:
-			return "", errors.New("harness stopped")
-		case <-ticker.C:
-			if _, err := os.Stat(e.signalPath()); err != nil {
-				continue
-			}
+			return "", harnesstype.ErrHarnessStopped
+		}
+	}
 
-			_ = os.Remove(e.signalPath())
+	if _, err := completion.Wait(ctx, e.done); err != nil {
+		return "", err //nolint:wrapcheck // detector errors already describe the wait
+	}
 
-			e.captureMu.Lock()
-			e.capturing = false
-			output := ansi.Strip(e.outputBuffer.String())
-			e.outputBuffer.Reset()
-			e.captureMu.Unlock()
+	e.mu.Lock()
+	e.phase = phaseIdle
+	output := ansi.Strip(e.outputBuffer.String())
+	e.outputBuffer.Reset()
+	e.mu.Unlock()
 
-			return output, nil
-		}
-	}
+	return output, nil
 }
 
 func (e *Executor) sendClear() {
@@ -784,27 +1115,50 @@ func (e *Executor) sendClear() {
 
 	time.Sleep(500 * time.Millisecond)
 
-	_, _ = ptmx.WriteString("/clear")
+	_, _ = io.WriteString(ptmx, "/clear")
 
 	time.Sleep(PTYPostWriteDelay)
 
-	_, _ = ptmx.WriteString("\r")
+	_, _ = io.WriteString(ptmx, "\r")
 }
 
-func (e *Executor) signalPath() string {
-	if e.signalDir == "" {
-		return ""
+// JobUsage reports the running job's turns, tokens, and cost (when Claude
+// records it) from the session transcript.
+func (e *Executor) JobUsage() (harnesstype.Usage, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.usage == nil {
+		return harnesstype.Usage{}, false
+	}
+
+	path := sessionTranscriptPath(e.signalDir)
+	if path == "" {
+		return harnesstype.Usage{}, false
+	}
+
+	if err := e.usage.update(path); err != nil {
+		return harnesstype.Usage{}, false
 	}
 
-	return e.signalDir + "/" + SignalFileName
+	return e.usage.total(), true
 }
 
-func (e *Executor) currentJobPath() string {
-	if e.signalDir == "" {
+// Supervision reports PTY starts, auto-accepted dialogs, and readiness
+// waits since setup.
+func (e *Executor) Supervision() harnesstype.Supervision {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.supervision
+}
+
+func currentJobPath(signalDir string) string {
+	if signalDir == "" {
 		return ""
 	}
 
-	return e.signalDir + "/current-job"
+	return signalDir + "/current-job"
 }
 
 func (e *Executor) applyRunnerConfig(cfg *client.RunnerConfigResponse) error {
@@ -821,12 +1175,14 @@ func (e *Executor) applyRunnerConfig(cfg *client.RunnerConfigResponse) error {
 
 	names := harnesstype.LoadedMCPProviderNames(cfg, now)
 
+	e.mu.Lock()
 	oldCleanup := e.mcpConfigRemove
 	e.mcpConfigPath = path
 	e.mcpConfigSig = sig
 	e.mcpConfigRemove = cleanup
 	e.loadedMCPNames = names
 	e.runnerConfig = cfg
+	e.mu.Unlock()
 
 	if oldCleanup != nil {
 		_ = oldCleanup()
@@ -844,18 +1200,29 @@ func (e *Executor) applyRunnerConfig(cfg *client.RunnerConfigResponse) error {
 }
 
 func (e *Executor) cleanupMCPConfigFile() {
-	if e.mcpConfigRemove == nil {
-		return
-	}
-
-	_ = e.mcpConfigRemove()
+	e.mu.Lock()
+	remove := e.mcpConfigRemove
 	e.mcpConfigRemove = nil
 	e.mcpConfigPath = ""
+	e.mu.Unlock()
+
+	if remove != nil {
+		_ = remove()
+	}
+}
+
+func (e *Executor) loadedMCPServers() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.loadedMCPNames
 }
 
 // SetSignalDir implements SignalDirConsumer.
 func (e *Executor) SetSignalDir(dir string) {
+	e.mu.Lock()
 	e.signalDir = dir
+	e.mu.Unlock()
 }
 
 // WantsTranscript implements TranscriptSource.
@@ -865,9 +1232,7 @@ func (e *Executor) WantsTranscript() bool {
 
 // Interrupt implements InterruptHandler — writes Ctrl+C to the PTY.
 func (e *Executor) Interrupt() error {
-	e.mu.Lock()
-	ptmx := e.ptmx
-	e.mu.Unlock()
+	ptmx := e.activePTY()
 
 	if ptmx == nil {
 		return nil
@@ -887,6 +1252,9 @@ var (
 	_ harnesstype.Resizable         = (*Executor)(nil)
 	_ harnesstype.InputReceiver     = (*Executor)(nil)
 	_ harnesstype.Refreshable       = (*Executor)(nil)
+	_ harnesstype.BundleReloader    = (*Executor)(nil)
+	_ harnesstype.TimeoutWarner     = (*Executor)(nil)
+	_ harnesstype.LocalTaskRunner   = (*Executor)(nil)
 	_ harnesstype.SignalDirConsumer = (*Executor)(nil)
 	_ harnesstype.TranscriptSource  = (*Executor)(nil)
 	_ harnesstype.InterruptHandler  = (*Executor)(nil)
@@ -44,6 +44,25 @@ func TestClaudeCommandArgs_BundleLoadModeOmitsSkipPermissions(t *testing.T) {
 	assertStringSliceEqual(t, got, want)
 }
 
+func TestClaudeCommandArgs_ExtraDirsFollowBundleDir(t *testing.T) {
+	exec := NewExecutor()
+
+	exec.opts = harnesstype.SetupOptions{
+		BundleLoadMode: true,
+		BundleDir:      "/tmp/bundle",
+		ExtraDirs:      []string{"/srv/team", "/srv/shared"},
+	}
+
+	got := exec.commandArgs()
+	want := []string{
+		"--add-dir", "/tmp/bundle",
+		"--add-dir", "/srv/team",
+		"--add-dir", "/srv/shared",
+	}
+
+	assertStringSliceEqual(t, got, want)
+}
+
 func assertStringSliceEqual(t *testing.T, got, want []string) {
 	t.Helper()
 
@@ -0,0 +1,231 @@
+//go:build unix
+
+package claude
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/creack/pty"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// fakePTYs stands in for Claude: each start opens a real PTY pair, hands the
+// master to the executor, and drains whatever the executor types. Starting a
+// new PTY closes the previous one's terminal side, as the old process exiting
+// would, so the executor's blocked read returns.
+type fakePTYs struct {
+	t *testing.T
+
+	mu  sync.Mutex
+	tty *os.File
+}
+
+func (f *fakePTYs) start(e *Executor) error {
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		return fmt.Errorf("open pty: %w", err)
+	}
+
+	f.t.Cleanup(func() {
+		_ = ptmx.Close()
+		_ = tty.Close()
+	})
+
+	go func() { _, _ = io.Copy(io.Discard, tty) }()
+
+	f.mu.Lock()
+	if f.tty != nil {
+		_ = f.tty.Close()
+	}
+
+	f.tty = tty
+	f.mu.Unlock()
+
+	e.mu.Lock()
+	proc := newPTYProcess(nil, harnesstype.NewPTY(ptmx))
+	e.proc = proc
+	e.supervision.PTYStarts++
+	e.mu.Unlock()
+
+	e.ptyReady <- proc.ptmx
+
+	return nil
+}
+
+// write sends output from the current fake Claude process. Writes to a
+// replaced PTY fail and are ignored.
+func (f *fakePTYs) write(p string) {
+	f.mu.Lock()
+	tty := f.tty
+	f.mu.Unlock()
+
+	if tty != nil {
+		_, _ = tty.WriteString(p)
+	}
+}
+
+// quickCompletion reports each job complete shortly after it starts.
+type quickCompletion struct{}
+
+func (quickCompletion) Reset()         {}
+func (quickCompletion) Observe([]byte) {}
+func (quickCompletion) Exited()        {}
+
+func (quickCompletion) Wait(ctx context.Context, stop <-chan struct{}) (*harnesstype.Completion, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-stop:
+		return nil, harnesstype.ErrHarnessStopped
+	case <-time.After(20 * time.Millisecond):
+		return &harnesstype.Completion{}, nil
+	}
+}
+
+// TestClaudeExecutorConcurrentRestartRefreshExecute runs jobs while the PTY
+// restarts, the MCP config refreshes, and the runtime resizes, types, and
+// polls the executor. Run with -race.
+func TestClaudeExecutorConcurrentRestartRefreshExecute(t *testing.T) {
+	e := NewExecutor()
+	ptys := &fakePTYs{t: t}
+
+	e.startPTYFunc = func(context.Context) error { return ptys.start(e) }
+	e.waitForReadyFunc = func(context.Context) bool { return true }
+	e.watchExitFunc = func() {}
+
+	ctx := t.Context()
+
+	err := e.Setup(ctx, &harnesstype.SetupOptions{
+		OnOutput:   func([]byte) {},
+		OnReady:    func() {},
+		TermWidth:  80,
+		TermHeight: 24,
+	})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	e.mu.Lock()
+	e.completion = quickCompletion{}
+	e.mu.Unlock()
+
+	stop := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	loop := func(fn func(i int)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := 0; ; i++ {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				fn(i)
+			}
+		}()
+	}
+
+	loop(func(int) {
+		ptys.write("thinking \x1b[1m❯\x1b[0m Esc to cancel\r\n")
+		time.Sleep(time.Millisecond)
+	})
+	loop(func(i int) {
+		if i%2 == 0 {
+			_ = e.ReloadBundle(ctx)
+		} else {
+			_ = e.restartPTY(ctx)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	})
+	loop(func(int) {
+		cfg := &client.RunnerConfigResponse{}
+		if e.NeedsRefresh(cfg) {
+			_ = e.applyRunnerConfig(cfg)
+		}
+
+		_ = e.commandArgs()
+		_ = e.loadedMCPServers()
+
+		time.Sleep(5 * time.Millisecond)
+	})
+	loop(func(i int) {
+		e.Resize(24+i%5, 80)
+		_, _ = e.WriteInput([]byte("x"))
+		_ = e.Interrupt()
+		_ = e.WarnTimeout(time.Minute)
+		_ = e.Supervision()
+		_, _ = e.JobUsage()
+
+		time.Sleep(2 * time.Millisecond)
+	})
+
+	for i := range 3 {
+		job := &client.Job{ID: "job", Execution: &client.ExecutionConfig{RenderedInstruction: "do work"}}
+		if _, err := e.Execute(ctx, job); err != nil {
+			t.Fatalf("Execute() #%d error = %v", i, err)
+		}
+	}
+
+	close(stop)
+	wg.Wait()
+	e.Teardown()
+
+	if got := e.Supervision(); got.PTYStarts < 2 || got.BypassAccepts != 1 {
+		t.Fatalf("Supervision() = %+v, want restarts and exactly one bypass accept", got)
+	}
+}
+
+func TestClaudePromptDebounceConfirmsOnce(t *testing.T) {
+	e := NewExecutor()
+	defer e.Teardown()
+
+	// Repeated sightings, as a redrawn prompt produces, restart the debounce.
+	for range 5 {
+		e.onPromptPatternSeen()
+		time.Sleep(PromptDebounceTime / 10)
+	}
+
+	select {
+	case <-e.promptDetected:
+		t.Fatal("prompt confirmed before the debounce elapsed")
+	default:
+	}
+
+	select {
+	case <-e.promptDetected:
+	case <-time.After(2 * PromptDebounceTime):
+		t.Fatal("prompt not confirmed after the debounce elapsed")
+	}
+
+	e.mu.Lock()
+	phase := e.phase
+	e.mu.Unlock()
+
+	if phase != phaseIdle {
+		t.Fatalf("phase = %v after the prompt was confirmed, want idle", phase)
+	}
+
+	// Without new output the same prompt is not confirmed again.
+	e.confirmPrompt()
+
+	select {
+	case <-e.promptDetected:
+		t.Fatal("prompt confirmed twice without new output")
+	default:
+	}
+}
@@ -4,6 +4,7 @@ package claude
 
 import (
 	"context"
+	"os/exec"
 	"testing"
 	"time"
 
@@ -167,3 +168,109 @@ func TestClaudeSetupOnReadySkippedOnCancel(t *testing.T) {
 	default:
 	}
 }
+
+func TestClaudeWaitForReadyRecordsSupervision(t *testing.T) {
+	exec := NewExecutor()
+	exec.ptyStartedAt = time.Now().Add(-2 * time.Second)
+	exec.supervision.PTYStarts = 2
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		exec.onPromptConfirmed()
+	}()
+
+	if !exec.waitForReady(t.Context()) {
+		t.Fatal("waitForReady() = false, want true")
+	}
+
+	got := exec.Supervision()
+	if got.ReadyWaits != 1 || got.ReadyTimeouts != 0 {
+		t.Fatalf("Supervision() = %+v, want one ready wait and no timeouts", got)
+	}
+
+	if avg := got.AverageReady(); avg < 2*time.Second {
+		t.Fatalf("AverageReady() = %v, want >= 2s", avg)
+	}
+
+	if got.PTYRestarts() != 1 {
+		t.Fatalf("PTYRestarts() = %d, want 1", got.PTYRestarts())
+	}
+
+	// A later wait without a fresh start does not count as startup time.
+	exec.recordReady(false)
+
+	if again := exec.Supervision(); again.ReadyWaits != 1 {
+		t.Fatalf("ReadyWaits = %d after second wait, want 1", again.ReadyWaits)
+	}
+}
+
+func TestClaudeReloadBundleRestartsAndSignalsReady(t *testing.T) {
+	e := NewExecutor()
+	starts := 0
+	onReadyCalled := make(chan struct{}, 1)
+
+	e.startPTYFunc = func(context.Context) error {
+		starts++
+		return nil
+	}
+	e.waitForReadyFunc = func(context.Context) bool { return true }
+	e.opts = harnesstype.SetupOptions{
+		BundleLoadMode: true,
+		OnReady: func() {
+			onReadyCalled <- struct{}{}
+		},
+	}
+
+	if err := e.ReloadBundle(t.Context()); err != nil {
+		t.Fatalf("ReloadBundle() error = %v", err)
+	}
+
+	if starts != 1 {
+		t.Fatalf("PTY starts = %d, want 1", starts)
+	}
+
+	select {
+	case <-onReadyCalled:
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("OnReady not called after reload")
+	}
+}
+
+func TestClaudeWatchProcessExitIgnoresReplacedProcess(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		replaced bool
+	}{
+		{name: "exits on its own"},
+		{name: "replaced by a restart", replaced: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewExecutor()
+			exited := make(chan struct{}, 1)
+			e.opts.OnExit = func() { exited <- struct{}{} }
+
+			cmd := exec.Command("true")
+			if err := cmd.Start(); err != nil {
+				t.Skipf("start true: %v", err)
+			}
+
+			proc := newPTYProcess(cmd, nil)
+			if !tt.replaced {
+				e.proc = proc
+			}
+
+			e.watchProcessExit(proc)
+
+			select {
+			case <-exited:
+				if tt.replaced {
+					t.Fatal("OnExit called for a replaced process")
+				}
+			case <-time.After(500 * time.Millisecond):
+				if !tt.replaced {
+					t.Fatal("OnExit not called when the process exited")
+				}
+			}
+		})
+	}
+}
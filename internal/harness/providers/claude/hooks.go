@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package claude
 
@@ -71,8 +71,9 @@ type hookCommand struct {
 	Command string `json:"command,omitempty"`
 }
 
-// InstallStopHook ensures a Stop hook is installed for completion signaling.
-// It returns a restore function to revert any changes on exit.
+// InstallStopHook ensures a Stop hook is installed for completion signaling,
+// along with a UserPromptSubmit hook that records the session for usage
+// tracking. It returns a restore function to revert any changes on exit.
 func InstallStopHook(signalDir string) (func() error, error) {
 	if signalDir == "" {
 		return nil, fmt.Errorf("signal directory is required")
@@ -108,54 +109,30 @@ func InstallStopHook(signalDir string) (func() error, error) {
 		settings.Hooks = make(map[string][]hookEntry)
 	}
 
-	stopHooks := settings.Hooks["Stop"]
-
-	command := fmt.Sprintf(
+	stopCommand := fmt.Sprintf(
 		"sh -c \"if [ -n \\\"$MUSHER_SIGNAL_DIR\\\" ]; then touch \\\"$MUSHER_SIGNAL_DIR/%s\\\"; fi\"",
 		SignalFileName,
 	)
 
-	normalizedStopHooks := make([]hookEntry, 0, len(stopHooks)+1)
-	alreadyPresent := false
-
-	for _, item := range stopHooks {
-		if item.Command != "" {
-			return nil, fmt.Errorf("unsupported legacy Stop hook entry format: use hooks[] commands")
-		}
-
-		// Stop hooks in current Claude schema do not require matcher, but if present it must be a string.
-		if item.Matcher != nil {
-			if _, isString := item.Matcher.(string); !isString {
-				return nil, fmt.Errorf("invalid Stop hook matcher type: expected string")
-			}
-		}
-
-		if item.Hooks == nil {
-			item.Hooks = []hookCommand{}
-		}
+	// The UserPromptSubmit hook saves its input, which names the session
+	// transcript the executor reads live usage from.
+	sessionCommand := fmt.Sprintf(
+		"sh -c \"if [ -n \\\"$MUSHER_SIGNAL_DIR\\\" ]; then cat > \\\"$MUSHER_SIGNAL_DIR/%s\\\"; fi\"",
+		SessionFileName,
+	)
 
-		for _, hook := range item.Hooks {
-			if hook.Command == command {
-				alreadyPresent = true
-			}
+	for _, hook := range []struct{ event, command string }{
+		{event: "Stop", command: stopCommand},
+		{event: "UserPromptSubmit", command: sessionCommand},
+	} {
+		merged, err := mergeHookCommand(hook.event, settings.Hooks[hook.event], hook.command)
+		if err != nil {
+			return nil, err
 		}
 
-		normalizedStopHooks = append(normalizedStopHooks, item)
-	}
-
-	if !alreadyPresent {
-		normalizedStopHooks = append(normalizedStopHooks, hookEntry{
-			Hooks: []hookCommand{
-				{
-					Type:    "command",
-					Command: command,
-				},
-			},
-		})
+		settings.Hooks[hook.event] = merged
 	}
 
-	settings.Hooks["Stop"] = normalizedStopHooks
-
 	if mkdirErr := safeio.MkdirAll(filepath.Dir(settingsPath), 0o755); mkdirErr != nil {
 		return nil, fmt.Errorf("failed to create .claude directory: %w", mkdirErr)
 	}
@@ -187,3 +164,48 @@ func InstallStopHook(signalDir string) (func() error, error) {
 
 	return restore, nil
 }
+
+// mergeHookCommand validates an event's hook entries and appends command
+// unless an entry already runs it.
+func mergeHookCommand(event string, entries []hookEntry, command string) ([]hookEntry, error) {
+	normalized := make([]hookEntry, 0, len(entries)+1)
+	alreadyPresent := false
+
+	for _, item := range entries {
+		if item.Command != "" {
+			return nil, fmt.Errorf("unsupported legacy %s hook entry format: use hooks[] commands", event)
+		}
+
+		// Hooks in current Claude schema do not require matcher, but if present it must be a string.
+		if item.Matcher != nil {
+			if _, isString := item.Matcher.(string); !isString {
+				return nil, fmt.Errorf("invalid %s hook matcher type: expected string", event)
+			}
+		}
+
+		if item.Hooks == nil {
+			item.Hooks = []hookCommand{}
+		}
+
+		for _, hook := range item.Hooks {
+			if hook.Command == command {
+				alreadyPresent = true
+			}
+		}
+
+		normalized = append(normalized, item)
+	}
+
+	if !alreadyPresent {
+		normalized = append(normalized, hookEntry{
+			Hooks: []hookCommand{
+				{
+					Type:    "command",
+					Command: command,
+				},
+			},
+		})
+	}
+
+	return normalized, nil
+}
@@ -1,10 +1,11 @@
-//go:build unix
+//go:build unix || windows
 
 package claude
 
 import "time"
 
-// SignalFileName is the marker file created by the Stop hook.
+// SignalFileName is the marker file created by the Stop hook. It must match
+// completion.file in spec.yaml.
 const SignalFileName = "complete"
 
 // PromptDetectionBytes contains the bytes to detect Claude's input prompt.
@@ -15,6 +16,3 @@ var PromptDetectionBytes = []byte{0xe2, 0x9d, 0xaf, 0x20} // "❯ " in UTF-8
 // PromptDebounceTime is how long to wait after seeing the prompt before
 // declaring Claude is ready. Used only for initial startup detection.
 const PromptDebounceTime = 1 * time.Second
-
-// SignalPollInterval is how often to check for completion signal files.
-const SignalPollInterval = 200 * time.Millisecond
@@ -0,0 +1,471 @@
+//go:build unix || windows
+
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// rehearsalTranscriptName is the transcript a rehearsal writes into the
+// signal directory as it replays, so usage is read the way it is from a
+// live session.
+const rehearsalTranscriptName = "rehearsal-transcript.jsonl"
+
+// RehearsalTurn is one prompt of a recorded Claude session and the entries
+// Claude wrote answering it.
+type RehearsalTurn struct {
+	Prompt string
+
+	// Entries are the transcript lines of the turn, the prompt first.
+	Entries [][]byte
+
+	// Output is what the turn prints, rendered from its text and tool use.
+	Output string
+}
+
+// rehearsalEntry is the part of a Claude transcript line a rehearsal reads.
+type rehearsalEntry struct {
+	Type    string `json:"type"`
+	IsMeta  bool   `json:"isMeta"`
+	Message struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+}
+
+type rehearsalBlock struct {
+	Type  string         `json:"type"`
+	Text  string         `json:"text"`
+	Name  string         `json:"name"`
+	Input map[string]any `json:"input"`
+}
+
+// ReadRehearsal splits the Claude session transcript at path into turns,
+// one per prompt typed into the session. Entries before the first prompt
+// are dropped.
+func ReadRehearsal(path string) ([]RehearsalTurn, error) {
+	file, err := safeio.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open rehearsal transcript: %w", err)
+	}
+	defer file.Close()
+
+	var (
+		turns  []RehearsalTurn
+		lineNo int
+	)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxTranscriptLine)
+
+	for scanner.Scan() {
+		lineNo++
+
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var entry rehearsalEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("rehearsal transcript line %d is not JSON: %w", lineNo, err)
+		}
+
+		if prompt, ok := entry.prompt(); ok {
+			turns = append(turns, RehearsalTurn{Prompt: prompt})
+		}
+
+		if len(turns) == 0 {
+			continue
+		}
+
+		turn := &turns[len(turns)-1]
+		turn.Entries = append(turn.Entries, append([]byte(nil), line...))
+
+		if entry.Type == "assistant" {
+			turn.Output += entry.render()
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read rehearsal transcript: %w", err)
+	}
+
+	if len(turns) == 0 {
+		return nil, fmt.Errorf("rehearsal transcript %s has no prompts", path)
+	}
+
+	return turns, nil
+}
+
+// prompt returns the text of a prompt typed into the session. Tool results
+// and meta entries are also user entries, but continue the turn.
+func (e *rehearsalEntry) prompt() (string, bool) {
+	if e.Type != "user" || e.IsMeta {
+		return "", false
+	}
+
+	var text string
+	if err := json.Unmarshal(e.Message.Content, &text); err == nil {
+		return text, true
+	}
+
+	var blocks []rehearsalBlock
+	if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+		return "", false
+	}
+
+	var parts []string
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			parts = append(parts, block.Text)
+		case "tool_result":
+			return "", false
+		}
+	}
+
+	if len(parts) == 0 {
+		return "", false
+	}
+
+	return strings.Join(parts, "\n"), true
+}
+
+// render prints an assistant entry the way Claude shows it: text, and a
+// line for each tool it calls.
+func (e *rehearsalEntry) render() string {
+	var blocks []rehearsalBlock
+	if err := json.Unmarshal(e.Message.Content, &blocks); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "text":
+			if text := strings.TrimSpace(block.Text); text != "" {
+				b.WriteString("⏺ " + strings.ReplaceAll(text, "\n", "\r\n  ") + "\r\n")
+			}
+		case "tool_use":
+			b.WriteString("⏺ " + block.Name + "(" + toolSummary(block.Input) + ")\r\n")
+		}
+	}
+
+	return b.String()
+}
+
+// toolSummary picks the input that best identifies a tool call.
+func toolSummary(input map[string]any) string {
+	for _, key := range []string{"command", "file_path", "pattern", "url", "description"} {
+		if value, ok := input[key].(string); ok && value != "" {
+			if first, _, cut := strings.Cut(value, "\n"); cut {
+				return first + " …"
+			}
+
+			return value
+		}
+	}
+
+	return ""
+}
+
+// Rehearsal is an executor that replays a recorded Claude session instead
+// of running claude, so the claim, execute, and report path can be tested
+// in CI without a Claude install or API costs.
+//
+// Each job gets the next recorded turn, whatever its prompt. The turn's
+// output is printed as the session would, its entries are written to a
+// transcript for usage and limits, and completion is signaled through the
+// same file the Stop hook creates.
+type Rehearsal struct {
+	path string
+
+	mu         sync.Mutex
+	opts       harnesstype.SetupOptions
+	turns      []RehearsalTurn
+	next       int
+	completion harnesstype.CompletionDetector
+	usage      *transcriptUsage
+	output     *harnesstype.OutputRecorder
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewRehearsal returns an executor that replays the Claude session
+// transcript at path.
+func NewRehearsal(path string) *Rehearsal {
+	return &Rehearsal{path: path, done: make(chan struct{})}
+}
+
+// Setup reads the recorded session.
+func (r *Rehearsal) Setup(_ context.Context, opts *harnesstype.SetupOptions) error {
+	if opts.BundleDir != "" {
+		return errors.New("rehearsal does not support interactive bundle sessions")
+	}
+
+	turns, err := ReadRehearsal(r.path)
+	if err != nil {
+		return err
+	}
+
+	detector, err := harnesstype.NewCompletionDetector(spec.Completion, opts.SignalDir)
+	if err != nil {
+		return fmt.Errorf("configure completion detection: %w", err)
+	}
+
+	r.mu.Lock()
+	r.opts = *opts
+	r.turns = turns
+	r.completion = detector
+	r.mu.Unlock()
+
+	r.emit([]byte(fmt.Sprintf("Rehearsing %d recorded prompt(s) from %s\r\n\r\n", len(turns), r.path)))
+
+	if opts.OnReady != nil {
+		opts.OnReady()
+	}
+
+	return nil
+}
+
+// Execute replays the next recorded turn and waits for its completion
+// signal.
+func (r *Rehearsal) Execute(ctx context.Context, job *client.Job) (*harnesstype.ExecResult, error) {
+	prompt, err := harnesstype.GetPromptFromJob(job)
+	if err != nil {
+		return nil, &harnesstype.ExecError{Reason: "prompt_error", Message: err.Error()}
+	}
+
+	r.mu.Lock()
+
+	if r.next >= len(r.turns) {
+		count := len(r.turns)
+		r.mu.Unlock()
+
+		return nil, &harnesstype.ExecError{
+			Reason:  "execution_error",
+			Message: fmt.Sprintf("rehearsal transcript has %d prompt(s) and all have been replayed", count),
+		}
+	}
+
+	turn := r.turns[r.next]
+	r.next++
+	signalDir := r.opts.SignalDir
+	output := &harnesstype.OutputRecorder{}
+	r.output = output
+	r.usage = newTranscriptUsage(time.Now())
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		r.output = nil
+		r.usage = nil
+		r.mu.Unlock()
+	}()
+
+	logger := observability.FromContext(ctx).With(slog.String("component", "harness"))
+
+	r.completion.Reset()
+	removeSessionFile(signalDir)
+
+	startedAt := time.Now()
+
+	if err := r.replay(signalDir, prompt, &turn); err != nil {
+		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error()}
+	}
+
+	logger.Debug("rehearsal turn replayed",
+		slog.String("event.type", "harness.rehearsal.replay"),
+		slog.Int("rehearsal.entries", len(turn.Entries)),
+	)
+
+	if constraints := jobConstraints(job); constraints != nil {
+		if failure := r.checkConstraints(constraints); failure != nil {
+			return nil, failure.WithPartialOutput(output.String(), output.LastActivity())
+		}
+	}
+
+	// The Stop hook creates this file when a real turn ends.
+	if err := os.WriteFile(filepath.Join(signalDir, SignalFileName), nil, 0o600); err != nil {
+		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: fmt.Sprintf("signal completion: %v", err)}
+	}
+
+	if _, err := r.completion.Wait(ctx, r.done); err != nil {
+		reason := "execution_error"
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = "timeout"
+		}
+
+		failure := &harnesstype.ExecError{Reason: reason, Message: err.Error(), Retry: true}
+
+		return nil, failure.WithPartialOutput(output.String(), output.LastActivity())
+	}
+
+	result := harnesstype.NewClaudeJobOutput(ansi.Strip(output.String()), time.Since(startedAt))
+	if usage, ok := r.JobUsage(); ok {
+		result.SetUsage(usage)
+	}
+
+	return &harnesstype.ExecResult{Output: result}, nil
+}
+
+// replay prints turn and appends its entries, stamped with the current
+// time, to the rehearsal transcript, which the session file then names as
+// the UserPromptSubmit hook would.
+func (r *Rehearsal) replay(signalDir, prompt string, turn *RehearsalTurn) error {
+	transcriptPath := filepath.Join(signalDir, rehearsalTranscriptName)
+
+	transcript, err := safeio.OpenFile(transcriptPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open rehearsal transcript: %w", err)
+	}
+	defer transcript.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+
+	for _, line := range turn.Entries {
+		if _, err := transcript.Write(append(restamp(line, now), '\n')); err != nil {
+			return fmt.Errorf("write rehearsal transcript: %w", err)
+		}
+	}
+
+	session, err := json.Marshal(map[string]string{
+		"hook_event_name": "UserPromptSubmit",
+		"transcript_path": transcriptPath,
+		"prompt":          prompt,
+	})
+	if err != nil {
+		return fmt.Errorf("marshal session file: %w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(signalDir, SessionFileName), session, 0o600); err != nil {
+		return fmt.Errorf("write session file: %w", err)
+	}
+
+	r.emit([]byte("❯ " + strings.ReplaceAll(prompt, "\n", "\r\n  ") + "\r\n\r\n"))
+	r.emit([]byte(turn.Output))
+
+	return nil
+}
+
+// restamp returns a transcript line with its timestamp set to now.
+func restamp(line []byte, now string) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return line
+	}
+
+	stamp, err := json.Marshal(now)
+	if err != nil {
+		return line
+	}
+
+	fields["timestamp"] = stamp
+
+	restamped, err := json.Marshal(fields)
+	if err != nil {
+		return line
+	}
+
+	return restamped
+}
+
+// emit sends replayed output where a live session's PTY output goes.
+func (r *Rehearsal) emit(p []byte) {
+	r.mu.Lock()
+	opts := r.opts
+	output := r.output
+	r.mu.Unlock()
+
+	if opts.TermWriter != nil {
+		_, _ = opts.TermWriter.Write(p)
+	}
+
+	if opts.OnOutput != nil {
+		opts.OnOutput(p)
+	}
+
+	if output != nil {
+		_, _ = output.Write(p)
+	}
+}
+
+// checkConstraints returns the failure for the replayed turn's usage, or nil.
+func (r *Rehearsal) checkConstraints(constraints *client.HarnessConstraints) *harnesstype.ExecError {
+	usage, ok := r.JobUsage()
+	if !ok {
+		return nil
+	}
+
+	return exceededConstraint(usage, constraints)
+}
+
+// JobUsage reports the usage recorded for the turns replayed for the job.
+func (r *Rehearsal) JobUsage() (harnesstype.Usage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.usage == nil {
+		return harnesstype.Usage{}, false
+	}
+
+	path := sessionTranscriptPath(r.opts.SignalDir)
+	if path == "" {
+		return harnesstype.Usage{}, false
+	}
+
+	if err := r.usage.update(path); err != nil {
+		return harnesstype.Usage{}, false
+	}
+
+	return r.usage.total(), true
+}
+
+// Reset clears the previous job's session file.
+func (r *Rehearsal) Reset(context.Context) error {
+	r.mu.Lock()
+	signalDir := r.opts.SignalDir
+	r.mu.Unlock()
+
+	if signalDir != "" {
+		removeSessionFile(signalDir)
+	}
+
+	return nil
+}
+
+// Teardown stops any wait in progress.
+func (r *Rehearsal) Teardown() {
+	r.doneOnce.Do(func() { close(r.done) })
+}
+
+// WantsTranscript records replayed output in history like a live session's.
+func (r *Rehearsal) WantsTranscript() bool {
+	return true
+}
+
+var (
+	_ harnesstype.Executor         = (*Rehearsal)(nil)
+	_ harnesstype.UsageReporter    = (*Rehearsal)(nil)
+	_ harnesstype.TranscriptSource = (*Rehearsal)(nil)
+)
@@ -0,0 +1,145 @@
+//go:build unix
+
+package claude
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+const rehearsalSession = `{"type":"summary","summary":"Earlier session"}
+{"type":"user","timestamp":"2026-03-01T10:00:00Z","message":{"role":"user","content":"Fix the failing test"}}
+{"type":"assistant","timestamp":"2026-03-01T10:00:02Z","costUSD":0.5,"message":{"id":"msg_1","content":[{"type":"text","text":"Running the tests."},{"type":"tool_use","name":"Bash","input":{"command":"go test ./..."}}],"usage":{"input_tokens":10,"output_tokens":5}}}
+{"type":"user","timestamp":"2026-03-01T10:00:05Z","message":{"role":"user","content":[{"type":"tool_result","content":"FAIL"}]}}
+{"type":"assistant","timestamp":"2026-03-01T10:00:09Z","costUSD":0.25,"message":{"id":"msg_2","content":[{"type":"text","text":"Fixed the assertion."}],"usage":{"input_tokens":20,"output_tokens":7}}}
+{"type":"user","timestamp":"2026-03-01T10:01:00Z","message":{"role":"user","content":[{"type":"text","text":"Now update the changelog"}]}}
+{"type":"assistant","timestamp":"2026-03-01T10:01:03Z","message":{"id":"msg_3","content":[{"type":"text","text":"Changelog updated."}],"usage":{"input_tokens":5,"output_tokens":3}}}
+`
+
+func writeRehearsal(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(path, []byte(rehearsalSession), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func rehearsalJob(id string, constraints *client.HarnessConstraints) *client.Job {
+	return &client.Job{ID: id, Execution: &client.ExecutionConfig{RenderedInstruction: "do work", Constraints: constraints}}
+}
+
+func TestReadRehearsal(t *testing.T) {
+	turns, err := ReadRehearsal(writeRehearsal(t))
+	if err != nil {
+		t.Fatalf("ReadRehearsal() error = %v", err)
+	}
+
+	if len(turns) != 2 || turns[0].Prompt != "Fix the failing test" || turns[1].Prompt != "Now update the changelog" {
+		t.Fatalf("ReadRehearsal() = %+v, want one turn per prompt", turns)
+	}
+
+	if len(turns[0].Entries) != 4 {
+		t.Fatalf("first turn has %d entries, want the prompt, tool result, and responses", len(turns[0].Entries))
+	}
+
+	want := "⏺ Running the tests.\r\n⏺ Bash(go test ./...)\r\n⏺ Fixed the assertion.\r\n"
+	if turns[0].Output != want {
+		t.Fatalf("first turn output = %q, want %q", turns[0].Output, want)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.jsonl")
+	if err := os.WriteFile(empty, []byte(`{"type":"summary"}`+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ReadRehearsal(empty); err == nil || !strings.Contains(err.Error(), "no prompts") {
+		t.Fatalf("ReadRehearsal() without prompts error = %v", err)
+	}
+}
+
+func TestRehearsalReplaysTurnsInOrder(t *testing.T) {
+	var term, captured bytes.Buffer
+
+	signalDir := t.TempDir()
+	rehearsal := NewRehearsal(writeRehearsal(t))
+
+	ready := false
+
+	err := rehearsal.Setup(t.Context(), &harnesstype.SetupOptions{
+		TermWriter: &term,
+		SignalDir:  signalDir,
+		OnReady:    func() { ready = true },
+		OnOutput:   func(p []byte) { captured.Write(p) },
+	})
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer rehearsal.Teardown()
+
+	if !ready {
+		t.Fatal("Setup() did not report ready")
+	}
+
+	result, err := rehearsal.Execute(t.Context(), rehearsalJob("job-1", nil))
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	output, ok := result.Output.(*harnesstype.ClaudeJobOutput)
+	if !ok || !strings.Contains(output.Output, "Fixed the assertion.") || strings.Contains(output.Output, "Changelog") {
+		t.Fatalf("first job output = %+v, want the first recorded turn", result.Output)
+	}
+
+	if output.Usage == nil || output.Usage.Turns == 0 {
+		t.Fatalf("first job usage = %+v, want the recorded turn's usage", output.Usage)
+	}
+
+	if _, err := os.Stat(filepath.Join(signalDir, SignalFileName)); !os.IsNotExist(err) {
+		t.Fatalf("completion signal left behind: %v", err)
+	}
+
+	if err := rehearsal.Reset(t.Context()); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	if _, err := rehearsal.Execute(t.Context(), rehearsalJob("job-2", nil)); err != nil {
+		t.Fatalf("second Execute() error = %v", err)
+	}
+
+	if !strings.Contains(term.String(), "Changelog updated.") || term.String() != captured.String() {
+		t.Fatalf("terminal and captured output differ or miss the second turn:\n%q\n%q", term.String(), captured.String())
+	}
+
+	_, err = rehearsal.Execute(t.Context(), rehearsalJob("job-3", nil))
+
+	var execErr *harnesstype.ExecError
+	if !errors.As(err, &execErr) || execErr.Retry || !strings.Contains(execErr.Message, "all have been replayed") {
+		t.Fatalf("Execute() past the recording error = %v, want a permanent failure", err)
+	}
+}
+
+func TestRehearsalEnforcesConstraints(t *testing.T) {
+	rehearsal := NewRehearsal(writeRehearsal(t))
+
+	if err := rehearsal.Setup(t.Context(), &harnesstype.SetupOptions{SignalDir: t.TempDir()}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	defer rehearsal.Teardown()
+
+	_, err := rehearsal.Execute(t.Context(), rehearsalJob("job-1", &client.HarnessConstraints{MaxBudgetUSD: 0.5}))
+
+	var execErr *harnesstype.ExecError
+	if !errors.As(err, &execErr) || execErr.Reason != "budget_exceeded" || !strings.Contains(execErr.PartialOutput, "Fixed the assertion.") {
+		t.Fatalf("Execute() over budget error = %v, want budget_exceeded with partial output", err)
+	}
+}
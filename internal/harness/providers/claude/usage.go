@@ -0,0 +1,178 @@
+//go:build unix || windows
+
+package claude
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// SessionFileName is written by the UserPromptSubmit hook with the hook's
+// input, which names the session transcript.
+const SessionFileName = "session.json"
+
+// maxTranscriptLine bounds the transcript entries decoded; larger entries
+// are tool results, which carry no usage.
+const maxTranscriptLine = 8 << 20
+
+// transcriptEntry is the part of a Claude transcript line that carries usage.
+type transcriptEntry struct {
+	Type      string    `json:"type"`
+	Timestamp time.Time `json:"timestamp"`
+	CostUSD   *float64  `json:"costUSD"`
+	Message   struct {
+		ID    string `json:"id"`
+		Usage *struct {
+			InputTokens              int64 `json:"input_tokens"`
+			OutputTokens             int64 `json:"output_tokens"`
+			CacheCreationInputTokens int64 `json:"cache_creation_input_tokens"`
+			CacheReadInputTokens     int64 `json:"cache_read_input_tokens"`
+		} `json:"usage"`
+	} `json:"message"`
+}
+
+// messageUsage is the usage recorded for one model response.
+type messageUsage struct {
+	input, output int64
+	cost          float64
+	costKnown     bool
+}
+
+// transcriptUsage tallies the usage in a Claude session transcript since a
+// job started. It reads incrementally, so polling a growing file is cheap.
+type transcriptUsage struct {
+	since  time.Time
+	path   string
+	offset int64
+
+	// Claude writes one line per content block, each repeating the
+	// message's usage, so usage is keyed by message ID.
+	messages map[string]messageUsage
+	order    []string
+}
+
+func newTranscriptUsage(since time.Time) *transcriptUsage {
+	return &transcriptUsage{since: since, messages: make(map[string]messageUsage)}
+}
+
+// update reads the entries appended to the transcript at path since the
+// last call. A new path (after /clear starts a new session) is read from
+// the beginning; usage already counted is kept.
+func (u *transcriptUsage) update(path string) error {
+	if path != u.path {
+		u.path = path
+		u.offset = 0
+	}
+
+	file, err := safeio.Open(path)
+	if err != nil {
+		return fmt.Errorf("open transcript: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Seek(u.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek transcript: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// A partial line is still being written; read it next time.
+			return nil
+		}
+
+		u.offset += int64(len(line))
+		u.record(bytes.TrimSpace(line))
+	}
+}
+
+func (u *transcriptUsage) record(line []byte) {
+	if len(line) == 0 || len(line) > maxTranscriptLine {
+		return
+	}
+
+	var entry transcriptEntry
+	if err := json.Unmarshal(line, &entry); err != nil {
+		return
+	}
+
+	if entry.Type != "assistant" || entry.Message.Usage == nil || entry.Timestamp.Before(u.since) {
+		return
+	}
+
+	id := entry.Message.ID
+	if id == "" {
+		id = fmt.Sprintf("line-%d", u.offset)
+	}
+
+	usage := entry.Message.Usage
+	recorded := messageUsage{
+		input:  usage.InputTokens + usage.CacheCreationInputTokens + usage.CacheReadInputTokens,
+		output: usage.OutputTokens,
+	}
+
+	if entry.CostUSD != nil {
+		recorded.cost = *entry.CostUSD
+		recorded.costKnown = true
+	}
+
+	if _, seen := u.messages[id]; !seen {
+		u.order = append(u.order, id)
+	}
+
+	u.messages[id] = recorded
+}
+
+// total sums the usage of every response counted so far.
+func (u *transcriptUsage) total() harnesstype.Usage {
+	total := harnesstype.Usage{Turns: len(u.order)}
+
+	for _, id := range u.order {
+		message := u.messages[id]
+		total.InputTokens += message.input
+		total.OutputTokens += message.output
+
+		if message.costKnown {
+			total.CostUSD += message.cost
+			total.CostKnown = true
+		}
+	}
+
+	return total
+}
+
+// sessionTranscriptPath returns the transcript named by the session file in
+// signalDir, or "" when the hook hasn't written it yet.
+func sessionTranscriptPath(signalDir string) string {
+	data, err := safeio.ReadFile(filepath.Join(signalDir, SessionFileName))
+	if err != nil {
+		return ""
+	}
+
+	var session struct {
+		TranscriptPath string `json:"transcript_path"`
+	}
+
+	if err := json.Unmarshal(data, &session); err != nil {
+		return ""
+	}
+
+	return session.TranscriptPath
+}
+
+// removeSessionFile forgets the previous job's session so a stale
+// transcript path is never read.
+func removeSessionFile(signalDir string) {
+	_ = os.Remove(filepath.Join(signalDir, SessionFileName))
+}
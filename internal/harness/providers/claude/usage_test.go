@@ -0,0 +1,86 @@
+//go:build unix
+
+package claude
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func appendTranscript(t *testing.T, path, lines string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := file.WriteString(lines); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTranscriptUsage_CountsResponsesSinceJobStart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	since := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	appendTranscript(t, path,
+		// Before the job started: ignored.
+		`{"type":"assistant","timestamp":"2026-03-01T09:59:00Z","message":{"id":"msg_old","usage":{"input_tokens":999,"output_tokens":999}}}`+"\n"+
+			`{"type":"user","timestamp":"2026-03-01T10:00:01Z","message":{"role":"user"}}`+"\n"+
+			// Two content blocks of one response repeat its usage.
+			`{"type":"assistant","timestamp":"2026-03-01T10:00:02Z","costUSD":0.01,"message":{"id":"msg_1","usage":{"input_tokens":10,"cache_read_input_tokens":90,"output_tokens":5}}}`+"\n"+
+			`{"type":"assistant","timestamp":"2026-03-01T10:00:03Z","costUSD":0.01,"message":{"id":"msg_1","usage":{"input_tokens":10,"cache_read_input_tokens":90,"output_tokens":20}}}`+"\n"+
+			// A partial line still being written.
+			`{"type":"assistant","timestamp":"2026-03-01T10:00:04Z"`)
+
+	usage := newTranscriptUsage(since)
+	if err := usage.update(path); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	got := usage.total()
+	if got.Turns != 1 || got.InputTokens != 100 || got.OutputTokens != 20 || !got.CostKnown || got.CostUSD != 0.01 {
+		t.Fatalf("after first read: %+v", got)
+	}
+
+	appendTranscript(t, path, `,"message":{"id":"msg_2","usage":{"input_tokens":50,"output_tokens":30}}}`+"\n")
+
+	if err := usage.update(path); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+
+	got = usage.total()
+	if got.Turns != 2 || got.Tokens() != 200 {
+		t.Fatalf("after completed line: %+v, want 2 turns and 200 tokens", got)
+	}
+}
+
+func TestSessionTranscriptPath(t *testing.T) {
+	dir := t.TempDir()
+
+	if got := sessionTranscriptPath(dir); got != "" {
+		t.Fatalf("without session file: %q", got)
+	}
+
+	data := `{"session_id":"abc","transcript_path":"/home/u/.claude/projects/p/abc.jsonl","hook_event_name":"UserPromptSubmit"}`
+	if err := os.WriteFile(filepath.Join(dir, SessionFileName), []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sessionTranscriptPath(dir); got != "/home/u/.claude/projects/p/abc.jsonl" {
+		t.Fatalf("sessionTranscriptPath() = %q", got)
+	}
+
+	removeSessionFile(dir)
+
+	if got := sessionTranscriptPath(dir); got != "" {
+		t.Fatalf("after removal: %q", got)
+	}
+}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package codex
 
@@ -6,15 +6,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
-	"github.com/creack/pty"
-
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/executil"
@@ -29,7 +27,7 @@ type Executor struct {
 
 	mu         sync.Mutex
 	cmd        *exec.Cmd
-	ptmx       *os.File
+	ptmx       harnesstype.PTY
 	pgid       int
 	waitDoneCh chan struct{}
 }
@@ -97,7 +95,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error()}
 	}
 
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), e.opts.Env...)
 
 	if job.Execution != nil {
 		for k, v := range job.Execution.Environment {
@@ -109,14 +107,21 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		fmt.Sprintf("MUSHER_JOB_ID=%s", job.ID),
 		fmt.Sprintf("MUSHER_JOB_NAME=%s", job.GetDisplayName()),
 		fmt.Sprintf("MUSHER_JOB_QUEUE=%s", job.QueueID),
+		fmt.Sprintf("MUSHER_JOB_ATTEMPT=%d", job.AttemptNumber),
+		fmt.Sprintf("MUSHER_JOB_MAX_ATTEMPTS=%d", job.MaxAttempts),
 	)
 
-	// Pipe output to terminal.
+	// Pipe output to terminal, keeping a copy in case the run fails.
+	var transcript harnesstype.OutputRecorder
+
+	outWriter := io.Writer(&transcript)
 	if e.opts.TermWriter != nil {
-		cmd.Stdout = e.opts.TermWriter
-		cmd.Stderr = e.opts.TermWriter
+		outWriter = io.MultiWriter(e.opts.TermWriter, &transcript)
 	}
 
+	cmd.Stdout = outWriter
+	cmd.Stderr = outWriter
+
 	startedAt := time.Now()
 	runErr := cmd.Run()
 	duration := time.Since(startedAt)
@@ -124,10 +129,14 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	if runErr != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			if errors.Is(ctxErr, context.DeadlineExceeded) {
-				return nil, &harnesstype.ExecError{Reason: "timeout", Message: "codex execution timed out", Retry: true}
+				execErr := &harnesstype.ExecError{Reason: "timeout", Message: "codex execution timed out", Retry: true}
+
+				return nil, execErr.WithPartialOutput(transcript.String(), transcript.LastActivity())
 			}
 
-			return nil, &harnesstype.ExecError{Reason: "execution_error", Message: fmt.Sprintf("codex execution canceled: %v", ctxErr), Retry: true}
+			execErr := &harnesstype.ExecError{Reason: "execution_error", Message: fmt.Sprintf("codex execution canceled: %v", ctxErr), Retry: true}
+
+			return nil, execErr.WithPartialOutput(transcript.String(), transcript.LastActivity())
 		}
 
 		exitCode := 1
@@ -137,11 +146,14 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 			exitCode = exitErr.ExitCode()
 		}
 
-		return nil, &harnesstype.ExecError{
-			Reason:  "codex_error",
-			Message: fmt.Sprintf("codex exited with code %d: %v", exitCode, runErr),
-			Retry:   true,
+		execErr := &harnesstype.ExecError{
+			Reason:   "codex_error",
+			Message:  fmt.Sprintf("codex exited with code %d: %v", exitCode, runErr),
+			Retry:    true,
+			ExitCode: exitCode,
 		}
+
+		return nil, execErr.WithPartialOutput(transcript.String(), transcript.LastActivity())
 	}
 
 	// Read output from the output file.
@@ -153,11 +165,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	}
 
 	return &harnesstype.ExecResult{
-		OutputData: map[string]any{
-			"success":    true,
-			"output":     output,
-			"durationMs": int(duration / time.Millisecond),
-		},
+		Output: harnesstype.NewAgentJobOutput(output, duration),
 	}, nil
 }
 
@@ -196,8 +204,8 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 		args = append(args, "--sandbox", "workspace-write")
 	}
 
-	if opts.BundleDir != "" && spec != nil && spec.BundleDir != nil && spec.BundleDir.Flag != "" {
-		args = append(args, spec.BundleDir.Flag, opts.BundleDir)
+	if opts.BundleDir != "" && spec != nil {
+		args = append(args, spec.BundleDir.Args(opts.BundleDir, opts.ExtraDirs)...)
 	}
 
 	cmd, err := executil.CommandContext(ctx, "codex", args...)
@@ -213,12 +221,9 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 	}
 
 	// NOTE: cmd.Stdin/Stdout/Stderr must remain nil here.
-	// creack/pty.StartWithSize assigns the PTY tty to all three;
+	// harnesstype.StartPTY assigns the PTY tty to all three;
 	// pre-setting Stdin to a non-tty would break Setctty (fd 0 must be the tty).
-	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{
-		Rows: uint16(opts.TermHeight),
-		Cols: uint16(opts.TermWidth),
-	})
+	ptmx, err := harnesstype.StartPTY(cmd, opts.TermHeight, opts.TermWidth)
 	if err != nil {
 		return fmt.Errorf("start codex interactive session: %w", err)
 	}
@@ -226,13 +231,7 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 	e.mu.Lock()
 	e.cmd = cmd
 	e.ptmx = ptmx
-	e.pgid = 0
-
-	if cmd.Process != nil && cmd.Process.Pid > 0 {
-		if pgid, pgErr := syscall.Getpgid(cmd.Process.Pid); pgErr == nil {
-			e.pgid = pgid
-		}
-	}
+	e.pgid = harnesstype.ProcessGroupID(cmd)
 
 	e.waitDoneCh = make(chan struct{})
 	waitDoneCh := e.waitDoneCh
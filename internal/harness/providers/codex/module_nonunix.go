@@ -1,4 +1,4 @@
-//go:build !unix
+//go:build !unix && !windows
 
 package codex
 
@@ -13,7 +13,7 @@ var specData []byte
 
 var spec = harnesstype.MustParseSpec(specData)
 
-// Module exposes provider metadata on non-unix builds.
+// Module exposes provider metadata on platforms without a harness executor.
 var Module = harnesstype.Module{
 	Spec:    spec,
 	MCPSpec: nil,
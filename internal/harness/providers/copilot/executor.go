@@ -1,10 +1,9 @@
-//go:build unix
+//go:build unix || windows
 
 package copilot
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -30,7 +29,7 @@ type Executor struct {
 
 	mu         sync.Mutex
 	cmd        *exec.Cmd
-	ptmx       *os.File
+	ptmx       harnesstype.PTY
 	pgid       int
 	waitDoneCh chan struct{}
 
@@ -101,7 +100,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		cmd.Dir = job.Execution.WorkingDirectory
 	}
 
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), e.opts.Env...)
 
 	if job.Execution != nil {
 		for k, v := range job.Execution.Environment {
@@ -113,9 +112,11 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		fmt.Sprintf("MUSHER_JOB_ID=%s", job.ID),
 		fmt.Sprintf("MUSHER_JOB_NAME=%s", job.GetDisplayName()),
 		fmt.Sprintf("MUSHER_JOB_QUEUE=%s", job.QueueID),
+		fmt.Sprintf("MUSHER_JOB_ATTEMPT=%d", job.AttemptNumber),
+		fmt.Sprintf("MUSHER_JOB_MAX_ATTEMPTS=%d", job.MaxAttempts),
 	)
 
-	var output bytes.Buffer
+	var output harnesstype.OutputRecorder
 
 	outWriter := io.Writer(&output)
 	if e.opts.TermWriter != nil {
@@ -134,7 +135,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	fallbackOutput := ansi.Strip(rawOutput)
 
 	if runErr != nil {
-		return nil, copilotRunError(ctx, runErr, fallbackOutput)
+		return nil, copilotRunError(ctx, runErr, fallbackOutput).WithPartialOutput(rawOutput, output.LastActivity())
 	}
 
 	resultOutput := parsedOutput
@@ -143,11 +144,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	}
 
 	return &harnesstype.ExecResult{
-		OutputData: map[string]any{
-			"success":    true,
-			"output":     resultOutput,
-			"durationMs": int(duration / time.Millisecond),
-		},
+		Output: harnesstype.NewAgentJobOutput(resultOutput, duration),
 	}, nil
 }
 
@@ -172,9 +169,10 @@ func copilotRunError(ctx context.Context, runErr error, fallbackOutput string) *
 	}
 
 	return &harnesstype.ExecError{
-		Reason:  "execution_error",
-		Message: copilotExitMessage(exitCode, fallbackOutput),
-		Retry:   true,
+		Reason:   "execution_error",
+		Message:  copilotExitMessage(exitCode, fallbackOutput),
+		Retry:    true,
+		ExitCode: exitCode,
 	}
 }
 
@@ -256,8 +254,8 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 	spec := Module.Spec
 
 	var args []string
-	if opts.BundleDir != "" && spec != nil && spec.BundleDir != nil && spec.BundleDir.Flag != "" {
-		args = append(args, spec.BundleDir.Flag, opts.BundleDir)
+	if opts.BundleDir != "" && spec != nil {
+		args = append(args, spec.BundleDir.Args(opts.BundleDir, opts.ExtraDirs)...)
 	}
 
 	if mcpArgs := e.mcpConfigArgs(); len(mcpArgs) > 0 {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 )
 
 func TestCopilotSetup_BinaryNotFound(t *testing.T) {
@@ -95,7 +96,12 @@ sleep 30
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "first line\nsecond line" {
 		t.Fatalf("output = %q, want parsed text", output)
 	}
@@ -149,7 +155,12 @@ sleep 30
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "plain output from copilot" {
 		t.Fatalf("output = %q, want raw fallback output", output)
 	}
@@ -257,7 +268,7 @@ func fakeCopilotInteractiveStart(t *testing.T, exec *Executor) func(context.Cont
 		}
 
 		exec.mu.Lock()
-		exec.ptmx = ptmx
+		exec.ptmx = harnesstype.NewPTY(ptmx)
 		exec.waitDoneCh = make(chan struct{})
 		exec.mu.Unlock()
 
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package cursor
 
@@ -14,8 +14,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/creack/pty"
-
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/executil"
@@ -30,7 +28,7 @@ type Executor struct {
 
 	mu         sync.Mutex
 	cmd        *exec.Cmd
-	ptmx       *os.File
+	ptmx       harnesstype.PTY
 	pgid       int
 	waitDoneCh chan struct{}
 
@@ -100,7 +98,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error()}
 	}
 
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), e.opts.Env...)
 
 	if job.Execution != nil {
 		for k, v := range job.Execution.Environment {
@@ -112,6 +110,8 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		fmt.Sprintf("MUSHER_JOB_ID=%s", job.ID),
 		fmt.Sprintf("MUSHER_JOB_NAME=%s", job.GetDisplayName()),
 		fmt.Sprintf("MUSHER_JOB_QUEUE=%s", job.QueueID),
+		fmt.Sprintf("MUSHER_JOB_ATTEMPT=%d", job.AttemptNumber),
+		fmt.Sprintf("MUSHER_JOB_MAX_ATTEMPTS=%d", job.MaxAttempts),
 	)
 
 	cleanup, env, err := buildCursorConfigEnv(e.mcpConfigContent, workDir)
@@ -127,7 +127,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 
 	cmd.Env = append(cmd.Env, env...)
 
-	var output strings.Builder
+	var output harnesstype.OutputRecorder
 
 	outWriter := io.Writer(&output)
 	if e.opts.TermWriter != nil {
@@ -142,17 +142,13 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	duration := time.Since(startedAt)
 
 	if runErr != nil {
-		return nil, harnesstype.HandleOneShotRunError(ctx, runErr, output.String(), "cursor-agent")
+		return nil, harnesstype.HandleOneShotRunError(ctx, runErr, &output, "cursor-agent")
 	}
 
 	resultOutput := ansi.Strip(strings.TrimSpace(output.String()))
 
 	return &harnesstype.ExecResult{
-		OutputData: map[string]any{
-			"success":    true,
-			"output":     resultOutput,
-			"durationMs": int(duration / time.Millisecond),
-		},
+		Output: harnesstype.NewAgentJobOutput(resultOutput, duration),
 	}, nil
 }
 
@@ -171,10 +167,7 @@ func (e *Executor) Resize(rows, cols int) {
 		return
 	}
 
-	_ = pty.Setsize(ptmx, &pty.Winsize{
-		Rows: uint16(rows),
-		Cols: uint16(cols),
-	})
+	_ = ptmx.Resize(rows, cols)
 }
 
 // WriteInput forwards terminal input to the interactive Cursor process.
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 )
 
 func TestCursorSetup_BinaryNotFound(t *testing.T) {
@@ -40,7 +41,12 @@ func TestCursorExecute_Success(t *testing.T) {
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "cursor output for: prompt for cursor" {
 		t.Fatalf("output = %q, want cursor output", output)
 	}
@@ -136,7 +142,12 @@ func TestCursorExecute_MergesProjectConfigWithMCP(t *testing.T) {
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 
 	var parsed map[string]any
 	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
@@ -245,7 +256,7 @@ func fakeCursorInteractiveStart(t *testing.T, exec *Executor) func(context.Conte
 		}
 
 		exec.mu.Lock()
-		exec.ptmx = ptmx
+		exec.ptmx = harnesstype.NewPTY(ptmx)
 		exec.waitDoneCh = make(chan struct{})
 		exec.mu.Unlock()
 
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 )
 
 func TestGeminiSetup_BinaryNotFound(t *testing.T) {
@@ -94,7 +95,12 @@ sleep 30
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "gemini ok" {
 		t.Fatalf("output = %q, want gemini ok", output)
 	}
@@ -232,7 +238,7 @@ func fakeGeminiInteractiveStart(t *testing.T, exec *Executor) func(context.Conte
 		}
 
 		exec.mu.Lock()
-		exec.ptmx = ptmx
+		exec.ptmx = harnesstype.NewPTY(ptmx)
 		exec.waitDoneCh = make(chan struct{})
 		exec.mu.Unlock()
 
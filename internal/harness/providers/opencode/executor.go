@@ -1,10 +1,9 @@
-//go:build unix
+//go:build unix || windows
 
 package opencode
 
 import (
 	"bufio"
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -14,11 +13,8 @@ import (
 	"os/exec"
 	"strings"
 	"sync"
-	"syscall"
 	"time"
 
-	"github.com/creack/pty"
-
 	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/executil"
@@ -32,7 +28,7 @@ type Executor struct {
 
 	mu         sync.Mutex
 	cmd        *exec.Cmd
-	ptmx       *os.File
+	ptmx       harnesstype.PTY
 	pgid       int
 	waitDoneCh chan struct{}
 
@@ -93,7 +89,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		cmd.Dir = job.Execution.WorkingDirectory
 	}
 
-	cmd.Env = os.Environ()
+	cmd.Env = append(os.Environ(), e.opts.Env...)
 
 	if job.Execution != nil {
 		for k, v := range job.Execution.Environment {
@@ -105,6 +101,8 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 		fmt.Sprintf("MUSHER_JOB_ID=%s", job.ID),
 		fmt.Sprintf("MUSHER_JOB_NAME=%s", job.GetDisplayName()),
 		fmt.Sprintf("MUSHER_JOB_QUEUE=%s", job.QueueID),
+		fmt.Sprintf("MUSHER_JOB_ATTEMPT=%d", job.AttemptNumber),
+		fmt.Sprintf("MUSHER_JOB_MAX_ATTEMPTS=%d", job.MaxAttempts),
 	)
 
 	if e.mcpConfigContent != "" {
@@ -112,7 +110,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	}
 
 	var (
-		output    bytes.Buffer
+		output    harnesstype.OutputRecorder
 		outWriter io.Writer = &output
 	)
 
@@ -134,14 +132,18 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	if runErr != nil {
 		if ctxErr := ctx.Err(); ctxErr != nil {
 			if errors.Is(ctxErr, context.DeadlineExceeded) {
-				return nil, &harnesstype.ExecError{Reason: "timeout", Message: "opencode execution timed out", Retry: true}
+				execErr := &harnesstype.ExecError{Reason: "timeout", Message: "opencode execution timed out", Retry: true}
+
+				return nil, execErr.WithPartialOutput(rawOutput, output.LastActivity())
 			}
 
-			return nil, &harnesstype.ExecError{
+			execErr := &harnesstype.ExecError{
 				Reason:  "execution_error",
 				Message: fmt.Sprintf("opencode execution canceled: %v", ctxErr),
 				Retry:   true,
 			}
+
+			return nil, execErr.WithPartialOutput(rawOutput, output.LastActivity())
 		}
 
 		exitCode := 1
@@ -158,19 +160,24 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 			msg = fmt.Sprintf("%s: %s", msg, fallbackOutput)
 		}
 
-		return nil, &harnesstype.ExecError{
-			Reason:  "execution_error",
-			Message: msg,
-			Retry:   true,
+		execErr := &harnesstype.ExecError{
+			Reason:   "execution_error",
+			Message:  msg,
+			Retry:    true,
+			ExitCode: exitCode,
 		}
+
+		return nil, execErr.WithPartialOutput(rawOutput, output.LastActivity())
 	}
 
 	if len(eventErrs) > 0 {
-		return nil, &harnesstype.ExecError{
+		execErr := &harnesstype.ExecError{
 			Reason:  "execution_error",
 			Message: strings.Join(eventErrs, "; "),
 			Retry:   true,
 		}
+
+		return nil, execErr.WithPartialOutput(rawOutput, output.LastActivity())
 	}
 
 	resultOutput := parsedOutput
@@ -179,11 +186,7 @@ func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.E
 	}
 
 	return &harnesstype.ExecResult{
-		OutputData: map[string]any{
-			"success":    true,
-			"output":     resultOutput,
-			"durationMs": int(duration / time.Millisecond),
-		},
+		Output: harnesstype.NewAgentJobOutput(resultOutput, duration),
 	}, nil
 }
 
@@ -202,10 +205,7 @@ func (e *Executor) Resize(rows, cols int) {
 		return
 	}
 
-	_ = pty.Setsize(ptmx, &pty.Winsize{
-		Rows: uint16(rows),
-		Cols: uint16(cols),
-	})
+	_ = ptmx.Resize(rows, cols)
 }
 
 // WriteInput forwards terminal input to the interactive OpenCode process.
@@ -309,10 +309,7 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 		cmd.Env = append(cmd.Env, fmt.Sprintf("OPENCODE_CONFIG_CONTENT=%s", e.mcpConfigContent))
 	}
 
-	ptmx, err := pty.StartWithSize(cmd, &pty.Winsize{
-		Rows: uint16(opts.TermHeight),
-		Cols: uint16(opts.TermWidth),
-	})
+	ptmx, err := harnesstype.StartPTY(cmd, opts.TermHeight, opts.TermWidth)
 	if err != nil {
 		return fmt.Errorf("start opencode interactive session: %w", err)
 	}
@@ -321,12 +318,7 @@ func (e *Executor) startInteractive(ctx context.Context, opts *harnesstype.Setup
 	e.cmd = cmd
 	e.ptmx = ptmx
 
-	e.pgid = 0
-	if cmd.Process != nil && cmd.Process.Pid > 0 {
-		if pgid, pgErr := syscall.Getpgid(cmd.Process.Pid); pgErr == nil {
-			e.pgid = pgid
-		}
-	}
+	e.pgid = harnesstype.ProcessGroupID(cmd)
 
 	e.waitDoneCh = make(chan struct{})
 	waitDoneCh := e.waitDoneCh
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
 )
 
 func TestOpenCodeSetup_BinaryNotFound(t *testing.T) {
@@ -66,7 +67,12 @@ exit 0
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "first line\nsecond line" {
 		t.Fatalf("output = %q, want parsed text", output)
 	}
@@ -91,7 +97,12 @@ exit 0
 		t.Fatalf("Execute() error = %v", err)
 	}
 
-	output, _ := result.OutputData["output"].(string)
+	agentOutput, ok := result.Output.(*harnesstype.AgentJobOutput)
+	if !ok {
+		t.Fatalf("Output type = %T, want *harnesstype.AgentJobOutput", result.Output)
+	}
+
+	output := agentOutput.Output
 	if output != "plain output from tool" {
 		t.Fatalf("output = %q, want raw fallback output", output)
 	}
@@ -0,0 +1,466 @@
+//go:build unix || windows
+
+package shell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+// readyTimeout bounds the wait for the ready pattern when a session starts.
+const readyTimeout = 60 * time.Second
+
+// resetTimeout bounds the wait for the ready pattern between jobs.
+const resetTimeout = 10 * time.Second
+
+// readyWindowBytes is how much recent output the ready pattern is matched against.
+const readyWindowBytes = 4096
+
+// Executor runs jobs through a harness defined by a spec's shell block.
+//
+// In stdin and arg modes each job runs its own process. In pty mode one
+// process runs for the whole session, each prompt is typed into it, and the
+// spec's completion block says when the job is done. Bundle sessions start
+// the binary interactively whatever the mode.
+type Executor struct {
+	spec  *harnesstype.ProviderSpec
+	ready *regexp.Regexp
+	opts  harnesstype.SetupOptions
+
+	mu         sync.Mutex
+	cmd        *exec.Cmd
+	ptmx       harnesstype.PTY
+	pgid       int
+	waitDoneCh chan struct{}
+	completion harnesstype.CompletionDetector
+	output     *harnesstype.OutputRecorder
+
+	// readyWindow is recent pty output with escape sequences removed.
+	readyWindow []byte
+	stripper    ansi.Stripper
+	readySeen   chan struct{}
+
+	done     chan struct{}
+	doneOnce sync.Once
+}
+
+// NewExecutor returns an executor for spec, which must have a shell block.
+func NewExecutor(spec *harnesstype.ProviderSpec) *Executor {
+	executor := &Executor{
+		spec:      spec,
+		readySeen: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+
+	if spec.Shell.ReadyPattern != "" {
+		executor.ready = regexp.MustCompile(spec.Shell.ReadyPattern)
+	}
+
+	return executor
+}
+
+// Setup checks the binary and, for pty mode and bundle sessions, starts it.
+func (e *Executor) Setup(ctx context.Context, opts *harnesstype.SetupOptions) error {
+	e.opts = *opts
+
+	if _, err := executil.LookPath(e.spec.Binary); err != nil {
+		return fmt.Errorf("%s not found in PATH", e.spec.Binary)
+	}
+
+	if e.opts.BundleDir == "" && e.spec.Shell.Prompt == harnesstype.ShellPromptPTY {
+		detector, err := harnesstype.NewCompletionDetector(e.spec.Completion, opts.SignalDir)
+		if err != nil {
+			return fmt.Errorf("configure completion detection: %w", err)
+		}
+
+		e.completion = detector
+	}
+
+	if e.opts.BundleDir != "" || e.completion != nil {
+		if err := e.startSession(ctx); err != nil {
+			return err
+		}
+
+		if e.opts.BundleDir == "" {
+			if err := e.waitReady(ctx, readyTimeout); err != nil {
+				return err
+			}
+		}
+	}
+
+	if opts.OnReady != nil {
+		opts.OnReady()
+	}
+
+	return nil
+}
+
+// Execute runs the job's prompt and returns what the harness printed.
+func (e *Executor) Execute(ctx context.Context, job *client.Job) (*harnesstype.ExecResult, error) {
+	if e.opts.BundleDir != "" {
+		return nil, &harnesstype.ExecError{
+			Reason:  "execution_error",
+			Message: e.spec.Name + " interactive bundle mode does not support queued job execution",
+		}
+	}
+
+	prompt, err := harnesstype.GetPromptFromJob(job)
+	if err != nil {
+		return nil, &harnesstype.ExecError{Reason: "prompt_error", Message: err.Error()}
+	}
+
+	if e.completion != nil {
+		return e.executeInSession(ctx, job, prompt)
+	}
+
+	return e.executeOneShot(ctx, job, prompt)
+}
+
+func (e *Executor) executeOneShot(ctx context.Context, job *client.Job, prompt string) (*harnesstype.ExecResult, error) {
+	args := append([]string(nil), e.spec.Shell.Args...)
+
+	if e.spec.Shell.Prompt == harnesstype.ShellPromptArg {
+		if e.spec.Shell.PromptFlag != "" {
+			args = append(args, e.spec.Shell.PromptFlag)
+		}
+
+		args = append(args, prompt)
+	}
+
+	cmd, err := executil.CommandContext(ctx, e.spec.Binary, args...)
+	if err != nil {
+		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error()}
+	}
+
+	if e.spec.Shell.Prompt == harnesstype.ShellPromptStdin {
+		cmd.Stdin = strings.NewReader(prompt)
+	}
+
+	if job.Execution != nil && job.Execution.WorkingDirectory != "" {
+		cmd.Dir = job.Execution.WorkingDirectory
+	}
+
+	cmd.Env = append(os.Environ(), e.opts.Env...)
+
+	if job.Execution != nil {
+		for k, v := range job.Execution.Environment {
+			cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", k, v))
+		}
+	}
+
+	cmd.Env = append(cmd.Env,
+		fmt.Sprintf("MUSHER_JOB_ID=%s", job.ID),
+		fmt.Sprintf("MUSHER_JOB_NAME=%s", job.GetDisplayName()),
+		fmt.Sprintf("MUSHER_JOB_QUEUE=%s", job.QueueID),
+		fmt.Sprintf("MUSHER_JOB_ATTEMPT=%d", job.AttemptNumber),
+		fmt.Sprintf("MUSHER_JOB_MAX_ATTEMPTS=%d", job.MaxAttempts),
+	)
+
+	var output harnesstype.OutputRecorder
+
+	outWriter := io.Writer(&output)
+	if e.opts.TermWriter != nil {
+		outWriter = io.MultiWriter(e.opts.TermWriter, &output)
+	}
+
+	cmd.Stdout = outWriter
+	cmd.Stderr = outWriter
+
+	startedAt := time.Now()
+	runErr := cmd.Run()
+	duration := time.Since(startedAt)
+
+	if runErr != nil {
+		return nil, harnesstype.HandleOneShotRunError(ctx, runErr, &output, e.spec.Name)
+	}
+
+	return &harnesstype.ExecResult{
+		Output: harnesstype.NewAgentJobOutput(ansi.Strip(strings.TrimSpace(output.String())), duration),
+	}, nil
+}
+
+func (e *Executor) executeInSession(ctx context.Context, job *client.Job, prompt string) (*harnesstype.ExecResult, error) {
+	// The session's environment was fixed when it started, so the job
+	// directories are spelled out in the prompt instead.
+	if preamble := harnesstype.JobEnvPreamble(job); preamble != "" {
+		prompt = preamble + "\n" + prompt
+	}
+
+	output := &harnesstype.OutputRecorder{}
+
+	e.completion.Reset()
+	e.drainReady()
+
+	e.mu.Lock()
+	e.output = output
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		e.output = nil
+		e.mu.Unlock()
+	}()
+
+	if err := e.typeLine(prompt); err != nil {
+		return nil, &harnesstype.ExecError{Reason: "execution_error", Message: err.Error(), Retry: true}
+	}
+
+	startedAt := time.Now()
+	_, waitErr := e.completion.Wait(ctx, e.done)
+	duration := time.Since(startedAt)
+
+	if waitErr != nil {
+		reason := "execution_error"
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = "timeout"
+		}
+
+		failure := &harnesstype.ExecError{Reason: reason, Message: waitErr.Error(), Retry: true}
+
+		return nil, failure.WithPartialOutput(output.String(), output.LastActivity())
+	}
+
+	return &harnesstype.ExecResult{
+		Output: harnesstype.NewAgentJobOutput(ansi.Strip(strings.TrimSpace(output.String())), duration),
+	}, nil
+}
+
+// Reset types the spec's reset input into a pty session and waits for the
+// harness to be ready again. One-shot modes have nothing to reset.
+func (e *Executor) Reset(ctx context.Context) error {
+	if e.completion == nil {
+		return nil
+	}
+
+	e.completion.Reset()
+
+	if e.spec.Shell.ResetInput != "" {
+		e.drainReady()
+
+		if err := e.typeLine(e.spec.Shell.ResetInput); err != nil {
+			return err
+		}
+	}
+
+	// A harness that misses its ready pattern still gets the next prompt.
+	_ = e.waitReady(ctx, resetTimeout)
+
+	return nil
+}
+
+// Teardown stops the session process, if one is running.
+func (e *Executor) Teardown() {
+	e.doneOnce.Do(func() { close(e.done) })
+
+	e.mu.Lock()
+	cmd := e.cmd
+	ptmx := e.ptmx
+	pgid := e.pgid
+	waitDoneCh := e.waitDoneCh
+	e.cmd = nil
+	e.ptmx = nil
+	e.pgid = 0
+	e.waitDoneCh = nil
+	e.mu.Unlock()
+
+	harnesstype.StopInteractiveProcess(cmd, ptmx, pgid, waitDoneCh)
+}
+
+// Resize implements Resizable for pty sessions.
+func (e *Executor) Resize(rows, cols int) {
+	ptmx := e.activePTY()
+	if ptmx == nil {
+		return
+	}
+
+	_ = ptmx.Resize(rows, cols)
+}
+
+// WriteInput forwards terminal input to the session process.
+func (e *Executor) WriteInput(p []byte) (int, error) {
+	ptmx := e.activePTY()
+	if ptmx == nil {
+		return 0, nil
+	}
+
+	n, err := ptmx.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("write to %s pty: %w", e.spec.Name, err)
+	}
+
+	return n, nil
+}
+
+// Interrupt implements InterruptHandler for pty sessions.
+func (e *Executor) Interrupt() error {
+	ptmx := e.activePTY()
+	if ptmx == nil {
+		return nil
+	}
+
+	if _, err := ptmx.Write([]byte{0x03}); err != nil { // Ctrl+C
+		return fmt.Errorf("interrupt %s pty: %w", e.spec.Name, err)
+	}
+
+	return nil
+}
+
+func (e *Executor) startSession(ctx context.Context) error {
+	args := append([]string(nil), e.spec.Shell.Args...)
+	args = append(args, e.spec.BundleDir.Args(e.opts.BundleDir, e.opts.ExtraDirs)...)
+
+	cmd, err := executil.CommandContext(ctx, e.spec.Binary, args...)
+	if err != nil {
+		return fmt.Errorf("resolve %s command: %w", e.spec.Binary, err)
+	}
+
+	switch {
+	case e.opts.WorkingDir != "":
+		cmd.Dir = e.opts.WorkingDir
+	case e.opts.BundleDir != "" && e.spec.BundleDir != nil && e.spec.BundleDir.Mode == "cwd":
+		cmd.Dir = e.opts.BundleDir
+	}
+
+	cmd.Env = append(os.Environ(), "TERM=xterm-256color", "MUSHER_SIGNAL_DIR="+e.opts.SignalDir)
+	cmd.Env = append(cmd.Env, e.opts.Env...)
+
+	sessionOpts := e.opts
+	sessionOpts.OnOutput = e.observe
+
+	onExit := func() {
+		if e.completion != nil {
+			e.completion.Exited()
+		}
+
+		if e.opts.OnExit != nil {
+			e.opts.OnExit()
+		}
+	}
+
+	ptmx, pgid, waitDoneCh, err := harnesstype.StartInteractiveProcess(cmd, &sessionOpts, onExit)
+	if err != nil {
+		return fmt.Errorf("start %s session: %w", e.spec.Name, harnesstype.AnnotateStartPTYError(err, cmd.Path))
+	}
+
+	e.mu.Lock()
+	e.cmd = cmd
+	e.ptmx = ptmx
+	e.pgid = pgid
+	e.waitDoneCh = waitDoneCh
+	e.mu.Unlock()
+
+	return nil
+}
+
+// observe handles a chunk of session output after it reaches the terminal.
+func (e *Executor) observe(p []byte) {
+	if e.opts.OnOutput != nil {
+		e.opts.OnOutput(p)
+	}
+
+	if e.completion != nil {
+		e.completion.Observe(p)
+	}
+
+	e.mu.Lock()
+
+	if e.output != nil {
+		_, _ = e.output.Write(p)
+	}
+
+	matched := false
+
+	if e.ready != nil {
+		e.readyWindow = append(e.readyWindow, e.stripper.Strip(p)...)
+		if excess := len(e.readyWindow) - readyWindowBytes; excess > 0 {
+			e.readyWindow = e.readyWindow[excess:]
+		}
+
+		if e.ready.Match(e.readyWindow) {
+			matched = true
+			e.readyWindow = e.readyWindow[:0]
+		}
+	}
+
+	e.mu.Unlock()
+
+	if matched {
+		select {
+		case e.readySeen <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// waitReady waits for the ready pattern, or returns at once without one.
+func (e *Executor) waitReady(ctx context.Context, timeout time.Duration) error {
+	if e.ready == nil {
+		return nil
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-e.readySeen:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("wait for %s to be ready: %w", e.spec.Name, ctx.Err())
+	case <-e.done:
+		return harnesstype.ErrHarnessStopped
+	case <-timer.C:
+		return fmt.Errorf("%s did not print its ready pattern %q within %s", e.spec.Name, e.spec.Shell.ReadyPattern, timeout)
+	}
+}
+
+func (e *Executor) drainReady() {
+	e.mu.Lock()
+	e.readyWindow = e.readyWindow[:0]
+	e.mu.Unlock()
+
+	select {
+	case <-e.readySeen:
+	default:
+	}
+}
+
+// typeLine types text into the session and presses Enter.
+func (e *Executor) typeLine(text string) error {
+	ptmx := e.activePTY()
+	if ptmx == nil {
+		return fmt.Errorf("%s is not running", e.spec.Name)
+	}
+
+	if _, err := io.WriteString(ptmx, text+"\r"); err != nil {
+		return fmt.Errorf("write to %s pty: %w", e.spec.Name, err)
+	}
+
+	return nil
+}
+
+func (e *Executor) activePTY() harnesstype.PTY {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.ptmx
+}
+
+var (
+	_ harnesstype.Executor         = (*Executor)(nil)
+	_ harnesstype.InputReceiver    = (*Executor)(nil)
+	_ harnesstype.Resizable        = (*Executor)(nil)
+	_ harnesstype.InterruptHandler = (*Executor)(nil)
+)
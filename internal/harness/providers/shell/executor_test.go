@@ -0,0 +1,167 @@
+//go:build unix
+
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestShellSetup_BinaryNotFound(t *testing.T) {
+	t.Setenv("PATH", "")
+
+	exec := NewExecutor(parseSpec(t, "binary: fake-agent\nshell: {}\n"))
+
+	err := exec.Setup(t.Context(), &harnesstype.SetupOptions{})
+	if err == nil || !strings.Contains(err.Error(), "fake-agent not found") {
+		t.Fatalf("Setup() err = %v, want binary not found", err)
+	}
+}
+
+func TestShellExecute_OneShot(t *testing.T) {
+	installFakeAgent(t, `#!/bin/sh
+echo "args: $*"
+cat
+`)
+
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{
+			name: "prompt on stdin",
+			yaml: "shell:\n  args: [--yes]\n",
+			want: "args: --yes\nfix the bug",
+		},
+		{
+			name: "prompt as argument",
+			yaml: "shell:\n  args: [--yes]\n  prompt: arg\n  promptFlag: --message\n",
+			want: "args: --yes --message fix the bug",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exec := NewExecutor(parseSpec(t, "binary: fake-agent\n"+tt.yaml))
+			if err := exec.Setup(t.Context(), &harnesstype.SetupOptions{}); err != nil {
+				t.Fatalf("Setup() error = %v", err)
+			}
+
+			defer exec.Teardown()
+
+			result, err := exec.Execute(t.Context(), shellTestJob("fix the bug"))
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			if got := result.Output.(*harnesstype.AgentJobOutput).Output; got != tt.want {
+				t.Fatalf("output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShellExecute_OneShotFailure(t *testing.T) {
+	installFakeAgent(t, `#!/bin/sh
+echo "rate limited"
+exit 3
+`)
+
+	exec := NewExecutor(parseSpec(t, "binary: fake-agent\nshell: {}\n"))
+	if err := exec.Setup(t.Context(), &harnesstype.SetupOptions{}); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	_, err := exec.Execute(t.Context(), shellTestJob("hello"))
+
+	var execErr *harnesstype.ExecError
+	if !errors.As(err, &execErr) || execErr.ExitCode != 3 || !strings.Contains(execErr.Message, "rate limited") {
+		t.Fatalf("Execute() error = %#v, want exit code 3 with output", err)
+	}
+}
+
+func TestShellExecute_Session(t *testing.T) {
+	installFakeAgent(t, `#!/bin/sh
+printf 'agent> '
+while IFS= read -r line; do
+  echo "answer: $line"
+  echo "TURN OVER"
+  printf 'agent> '
+done
+`)
+
+	exec := NewExecutor(parseSpec(t, `binary: fake-agent
+shell:
+  readyPattern: 'agent> $'
+  resetInput: /clear
+completion:
+  mode: output_marker
+  marker: TURN OVER
+`))
+
+	opts := &harnesstype.SetupOptions{TermWidth: 120, TermHeight: 40}
+	if err := exec.Setup(t.Context(), opts); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+
+	defer exec.Teardown()
+
+	for _, prompt := range []string{"first", "second"} {
+		result, err := exec.Execute(t.Context(), shellTestJob(prompt))
+		if err != nil {
+			t.Fatalf("Execute(%q) error = %v", prompt, err)
+		}
+
+		if got := result.Output.(*harnesstype.AgentJobOutput).Output; !strings.Contains(got, "answer: "+prompt) {
+			t.Fatalf("Execute(%q) output = %q, want the answer", prompt, got)
+		}
+
+		if err := exec.Reset(t.Context()); err != nil {
+			t.Fatalf("Reset() error = %v", err)
+		}
+	}
+}
+
+func parseSpec(t *testing.T, body string) *harnesstype.ProviderSpec {
+	t.Helper()
+
+	spec, err := harnesstype.ParseSpec([]byte("name: fake-agent\n" + body))
+	if err != nil {
+		t.Fatalf("ParseSpec() error = %v", err)
+	}
+
+	return spec
+}
+
+func installFakeAgent(t *testing.T, script string) {
+	t.Helper()
+
+	binDir := t.TempDir()
+
+	path := filepath.Join(binDir, "fake-agent")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatalf("write fake agent: %v", err)
+	}
+
+	sep := string(os.PathListSeparator)
+	currentPath := os.Getenv("PATH")
+	t.Setenv("PATH", fmt.Sprintf("%s%s%s", binDir, sep, currentPath))
+}
+
+func shellTestJob(prompt string) *client.Job {
+	return &client.Job{
+		ID:      "job-1",
+		QueueID: "queue-1",
+		Execution: &client.ExecutionConfig{
+			RenderedInstruction: prompt,
+		},
+	}
+}
@@ -0,0 +1,15 @@
+//go:build unix || windows
+
+// Package shell runs custom harnesses that users define in YAML instead of
+// compiling a provider module into mush.
+package shell
+
+import "github.com/musher-dev/mush/internal/harness/harnesstype"
+
+// NewModule returns the provider module for a spec with a shell block.
+func NewModule(spec *harnesstype.ProviderSpec) harnesstype.Module {
+	return harnesstype.Module{
+		Spec:        spec,
+		NewExecutor: func() harnesstype.Executor { return NewExecutor(spec) },
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix && !windows
+
+// Package shell runs custom harnesses that users define in YAML instead of
+// compiling a provider module into mush.
+package shell
+
+import "github.com/musher-dev/mush/internal/harness/harnesstype"
+
+// NewModule exposes provider metadata on non-unix builds.
+func NewModule(spec *harnesstype.ProviderSpec) harnesstype.Module {
+	return harnesstype.Module{Spec: spec}
+}
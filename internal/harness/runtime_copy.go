@@ -0,0 +1,151 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/hinshun/vt10x"
+)
+
+// copyCommandTimeout bounds a native clipboard command such as xclip.
+const copyCommandTimeout = 2 * time.Second
+
+// maxCopyCount caps the line count typed before y.
+const maxCopyCount = 99999
+
+// handleCopyKey handles copy-mode keys while scrolled back. Digits build a
+// line count, y copies that many lines from the end of the output (or the
+// visible page without a count), and Y copies everything since the current
+// or most recent job started. It reports whether the key was consumed.
+func (r *embeddedRuntime) handleCopyKey(ev *tcell.EventKey) bool {
+	if ev.Key() != tcell.KeyRune || ev.Modifiers()&tcell.ModAlt != 0 {
+		return false
+	}
+
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	ch := ev.Rune()
+
+	switch {
+	case ch >= '0' && ch <= '9':
+		r.copyCount = min(r.copyCount*10+int(ch-'0'), maxCopyCount)
+	case ch == 'y':
+		end := r.liveEndLocked()
+		start := r.viewportTop
+
+		if r.copyCount > 0 {
+			start = end - r.copyCount
+		} else {
+			end = min(end, r.viewportTop+r.visibleRows())
+		}
+
+		r.copyLinesLocked(start, end, "lines")
+	case ch == 'Y':
+		r.copyLinesLocked(r.jobStartLine-r.scrollback.Offset(), r.liveEndLocked(), "lines of job output")
+	default:
+		return false
+	}
+
+	r.drawLocked()
+
+	return true
+}
+
+// copyLinesLocked copies logical lines [start, end) to the clipboard and
+// reports the result in the top bar.
+func (r *embeddedRuntime) copyLinesLocked(start, end int, what string) {
+	r.copyCount = 0
+
+	lines := r.logicalLinesLocked(max(start, 0), end)
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		r.copyNotice = "Nothing to copy"
+		return
+	}
+
+	copyFn := r.copyToClipboard
+	if copyFn == nil {
+		r.copyNotice = "Clipboard unavailable"
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.ctx, copyCommandTimeout)
+	defer cancel()
+
+	method, err := copyFn(ctx, strings.Join(lines, "\n")+"\n")
+	if err != nil {
+		r.copyNotice = fmt.Sprintf("Copy failed: %v", err)
+		return
+	}
+
+	r.copyNotice = fmt.Sprintf("Copied %d %s (%s)", len(lines), what, method)
+}
+
+// liveEndLocked returns the logical line just past the cursor row, so blank
+// rows below the cursor on the live screen are not copied.
+func (r *embeddedRuntime) liveEndLocked() int {
+	r.vt.Lock()
+	defer r.vt.Unlock()
+
+	return r.scrollback.Len() + r.vt.Cursor().Y + 1
+}
+
+// logicalLinesLocked returns the text of logical lines [start, end), where
+// scrollback lines come first and the live screen follows.
+func (r *embeddedRuntime) logicalLinesLocked(start, end int) []string {
+	rows := r.visibleRows()
+	cols := r.frame.ViewportWidth
+
+	r.vt.Lock()
+	defer r.vt.Unlock()
+
+	lines := make([]string, 0, max(end-start, 0))
+
+	for line := start; line < end; line++ {
+		if line < r.scrollback.Len() {
+			lines = append(lines, glyphText(r.scrollback.Line(line)))
+			continue
+		}
+
+		vtRow := line - r.scrollback.Len()
+		if vtRow >= rows {
+			break
+		}
+
+		cells := make([]vt10x.Glyph, cols)
+		for col := range cells {
+			cells[col] = r.vt.Cell(col, vtRow)
+		}
+
+		lines = append(lines, glyphText(cells))
+	}
+
+	return lines
+}
+
+// markJobStart records the absolute line where a job's output begins.
+func (r *embeddedRuntime) markJobStart() {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	r.jobStartLine = r.scrollback.Offset() + r.liveEndLocked() - 1
+}
+
+// glyphText returns the characters of a row without trailing blanks.
+func glyphText(cells []vt10x.Glyph) string {
+	runes := make([]rune, len(cells))
+	for i, glyph := range cells {
+		runes[i] = glyphRune(glyph)
+	}
+
+	return strings.TrimRight(string(runes), " ")
+}
@@ -0,0 +1,1009 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/google/uuid"
+	"github.com/hinshun/vt10x"
+	"golang.org/x/term"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	harnessstate "github.com/musher-dev/mush/internal/harness/state"
+	"github.com/musher-dev/mush/internal/harness/ui/layout"
+	statusui "github.com/musher-dev/mush/internal/harness/ui/status"
+	"github.com/musher-dev/mush/internal/netrecord"
+	"github.com/musher-dev/mush/internal/observability"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/terminal"
+	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/worker"
+)
+
+// Tokyo Night palette for harness chrome.
+// Dark-mode values matching internal/tui/nav/styles.go.
+var (
+	tnSurface = tcell.NewRGBColor(0x2A, 0x2E, 0x3A) // colorSurface dark
+	tnBorder  = tcell.NewRGBColor(0x3B, 0x42, 0x52) // colorBorder dark
+	tnAccent  = tcell.NewRGBColor(0x9D, 0x7C, 0xD8) // colorAccent dark
+	tnText    = tcell.NewRGBColor(0xC8, 0xCE, 0xDB) // colorText dark
+	tnMuted   = tcell.NewRGBColor(0x4E, 0x56, 0x68) // colorMuted dark
+	tnPTYBg   = tcell.NewRGBColor(0x1A, 0x1B, 0x26) // Tokyo Night storm base
+	tnSuccess = tcell.NewRGBColor(0x9E, 0xCE, 0x6A) // colorSuccess dark
+	tnWarning = tcell.NewRGBColor(0xE0, 0xAF, 0x68) // colorWarning dark
+	tnError   = tcell.NewRGBColor(0xF7, 0x76, 0x8E) // colorError dark
+)
+
+const (
+	defaultCtrlCExitWindow     = 2 * time.Second
+	defaultPTYShutdownDeadline = 3 * time.Second
+)
+
+type embeddedRuntime struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	screen tcell.Screen
+	vt     vt10x.Terminal
+
+	// termOutput queues harness output for the screen so a slow terminal
+	// does not block PTY reads.
+	termOutput *outputPump
+
+	uiMu sync.Mutex
+
+	width  int
+	height int
+	frame  layout.Frame
+
+	mouseCaptureEnabled bool
+	lastCtrlCAt         time.Time
+
+	// noSidebar and noMouse turn off the sidebar and mouse capture for
+	// terminals whose profile does without them.
+	noSidebar bool
+	noMouse   bool
+
+	scrollback        *scrollbackBuffer
+	viewportTop       int
+	followTail        bool
+	historyNotice     string
+	errorOverlay      bool
+	bundleOverlay     bool
+	scrollbarDragging bool
+	scrollbarDragY    int
+
+	// debugLogging is set while F4 has raised logging to debug;
+	// levelBeforeDebug is the level to return to.
+	debugLogging     bool
+	levelBeforeDebug string
+
+	// inputLock mirrors worker.input_lock; unlockedJob is the job F6 let
+	// keystrokes through for.
+	inputLock   atomic.Bool
+	unlockedJob string
+
+	// reloadRequests carries `mush worker reload` to signalLoop, which
+	// reloads the config as it does on SIGHUP.
+	reloadRequests chan struct{}
+
+	// title is the terminal window title last set; see statusui.Title.
+	title string
+
+	// Copy mode: a pending line count, the last copy result, and the
+	// absolute line where the current job's output starts.
+	copyCount       int
+	copyNotice      string
+	jobStartLine    int
+	copyToClipboard func(ctx context.Context, text string) (string, error)
+
+	client    *client.Client
+	eng       *engine.Engine
+	executors map[string]harnesstype.Executor
+	signalDir string
+
+	cfg                *config.Config
+	supportedHarnesses []string
+	habitatID          string
+	queueID            string
+	rehearsal          string
+	metricsAddr        string
+
+	transcriptEnabled bool
+	transcriptDir     string
+	transcriptLines   int
+	requireTranscript bool
+	transcriptStore   *transcript.Store
+	transcriptMu      sync.Mutex
+
+	// output copies the harness terminal to `mush worker observe`.
+	output *outputFanout
+
+	// network records the harnesses' outbound calls when
+	// worker.network_recording is on.
+	network *netrecord.Recorder
+
+	bundleLoadMode bool
+	bundleName     string
+	bundleVer      string
+	bundleDir      string
+	extraDirs      []string
+	bundleWorkDir  string
+	bundleEnv      []string
+	bundleSummary  BundleSummary
+
+	// bundleReload refreshes the bundle on F5; reloading is set while it runs.
+	bundleReload func(ctx context.Context) (*BundleReload, error)
+	reloading    atomic.Bool
+
+	// localMu guards the local task the user started by typing between
+	// jobs, and when the session last saw a keystroke or output.
+	localMu       sync.Mutex
+	localTask     *localTask
+	localActivity time.Time
+
+	// onWorkerExit receives the session results after the engine drains.
+	onWorkerExit func(WorkerSummary)
+
+	sidebarExpanded     map[string]bool
+	sidebarClickTargets []statusui.SidebarClickTarget
+
+	done      chan struct{}
+	closeOnce sync.Once
+
+	now             func() time.Time
+	ctrlCExitWindow time.Duration
+}
+
+func runEmbeddedHarness(ctx context.Context, cfg *Config) error {
+	r := newEmbeddedRuntime(ctx, cfg)
+
+	return r.Run()
+}
+
+func newEmbeddedRuntime(ctx context.Context, cfg *Config) *embeddedRuntime {
+	ctx, cancel := context.WithCancel(ctx)
+
+	initialStatus := engine.StatusConnecting
+	if cfg.BundleLoadMode {
+		initialStatus = engine.StatusStarting
+	}
+
+	executors := make(map[string]harnesstype.Executor)
+	loadedCfg := config.Load()
+
+	r := &embeddedRuntime{
+		ctx:                ctx,
+		cancel:             cancel,
+		client:             cfg.Client,
+		executors:          executors,
+		cfg:                loadedCfg,
+		supportedHarnesses: cfg.SupportedHarnesses,
+		habitatID:          cfg.HabitatID,
+		queueID:            cfg.QueueID,
+		rehearsal:          cfg.Rehearsal,
+		metricsAddr:        cfg.MetricsAddr,
+		transcriptEnabled:  cfg.TranscriptEnabled,
+		transcriptDir:      cfg.TranscriptDir,
+		transcriptLines:    cfg.TranscriptLines,
+		requireTranscript:  cfg.RequireTranscript,
+		bundleLoadMode:     cfg.BundleLoadMode,
+		bundleName:         cfg.BundleName,
+		bundleVer:          cfg.BundleVer,
+		bundleDir:          cfg.BundleDir,
+		extraDirs:          append([]string(nil), cfg.BundleExtraDirs...),
+		bundleWorkDir:      cfg.BundleWorkDir,
+		bundleEnv:          append([]string(nil), cfg.BundleEnv...),
+		bundleSummary:      cfg.BundleSummary,
+		bundleReload:       cfg.BundleReload,
+		onWorkerExit:       cfg.OnWorkerExit,
+		sidebarExpanded:    make(map[string]bool),
+		done:               make(chan struct{}),
+		reloadRequests:     make(chan struct{}, 1),
+		now:                time.Now,
+		ctrlCExitWindow:    defaultCtrlCExitWindow,
+		followTail:         true,
+		copyToClipboard:    (&terminal.Clipboard{TTY: os.Stdout}).Copy,
+	}
+
+	profile, profileErr := terminal.ResolveProfile(loadedCfg.TerminalProfile())
+	if profileErr != nil {
+		profile = terminal.DetectProfile(os.Getenv)
+	}
+
+	r.noSidebar = !profile.Sidebar && !cfg.ForceSidebar
+	r.noMouse = !profile.Mouse
+
+	network, netErr := openNetworkRecorder(loadedCfg, cfg.BundleLoadMode)
+	r.network = network
+
+	r.inputLock.Store(loadedCfg.InputLock())
+	r.eng = newWorkerEngine(cfg, loadedCfg, executors, initialStatus, r.now, r.recordJob, network)
+
+	if netErr != nil {
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Network recording disabled: %v", netErr))
+	}
+
+	if profileErr != nil {
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("terminal.profile ignored: %v", profileErr))
+	}
+
+	return r
+}
+
+// newWorkerEngine creates the job engine for cfg, claiming through executors
+// once the host has set them up. recordJob receives each job's start and end
+// records, and network, if set, records the calls each job makes.
+func newWorkerEngine(
+	cfg *Config,
+	loadedCfg *config.Config,
+	executors map[string]harnesstype.Executor,
+	initialStatus engine.Status,
+	now func() time.Time,
+	recordJob func(*transcript.JobRecord),
+	network *netrecord.Recorder,
+) *engine.Engine {
+	var refreshInterval time.Duration
+	if cfg.RunnerConfigStale {
+		refreshInterval = engine.MinRefreshInterval
+	}
+
+	// Without a state directory jobs are still logged to the worker log.
+	jobLogDir, _ := paths.JobLogsDir()
+
+	return engine.New(&engine.Options{
+		Client:             cfg.Client,
+		Config:             loadedCfg,
+		HabitatID:          cfg.HabitatID,
+		QueueID:            cfg.QueueID,
+		QueueSlug:          cfg.QueueSlug,
+		InstanceID:         cfg.InstanceID,
+		Executors:          executors,
+		SupportedHarnesses: cfg.SupportedHarnesses,
+		RunnerConfig:       cfg.RunnerConfig,
+		RefreshInterval:    refreshInterval,
+		MaxJobs:            cfg.MaxJobs,
+		MaxDuration:        cfg.MaxDuration,
+		IdleTimeout:        cfg.IdleTimeout,
+		InitialStatus:      initialStatus,
+		JobRecorder:        recordJob,
+		NetworkRecorder:    network,
+		JobLogDir:          jobLogDir,
+		Now:                now,
+	})
+}
+
+func (r *embeddedRuntime) Run() error {
+	if r.client == nil && !r.bundleLoadMode {
+		return fmt.Errorf("missing client in harness config")
+	}
+
+	if r.network != nil {
+		defer func() { _ = r.network.Close() }()
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("init terminal screen: %w", err)
+	}
+
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("initialize terminal screen: %w", err)
+	}
+
+	r.screen = screen
+
+	defer screen.Fini()
+
+	width, height := screen.Size()
+	width, height = clampTerminalSize(width, height)
+	r.width, r.height = width, height
+	r.frame = layout.ComputeFrame(width, height, !r.noSidebar)
+	r.vt = vt10x.New(vt10x.WithSize(r.frame.ViewportWidth, layout.PtyRowsForFrame(&r.frame)))
+	r.output = newOutputFanout(r.frame.ViewportWidth, layout.PtyRowsForFrame(&r.frame))
+
+	scrollbackCap := r.cfg.HarnessScrollbackLines()
+	if scrollbackCap <= 0 {
+		scrollbackCap = defaultScrollbackCapacity
+	}
+
+	r.scrollback = newScrollbackBuffer(scrollbackCap)
+
+	store, tErr := openTranscript(r.transcriptEnabled, r.transcriptDir, r.transcriptLines, r.cfg, r.supportedHarnesses)
+	switch {
+	case tErr != nil && r.requireTranscript:
+		return fmt.Errorf("%w: %w", ErrTranscriptUnavailable, tErr)
+	case tErr != nil:
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript disabled: %v", tErr))
+	case store != nil:
+		r.transcriptMu.Lock()
+		r.transcriptStore = store
+		r.transcriptMu.Unlock()
+
+		defer r.closeTranscript()
+	}
+
+	if needsSignalDir(r.supportedHarnesses) {
+		signalDir, mkErr := os.MkdirTemp("", "mush-signals-")
+		if mkErr != nil {
+			return fmt.Errorf("failed to create signal directory: %w", mkErr)
+		}
+
+		r.signalDir = signalDir
+
+		defer func() { _ = os.RemoveAll(signalDir) }()
+	}
+
+	r.termOutput = newOutputPump(r, defaultOutputPumpLimit, r.onTerminalOutputDropped)
+	defer r.termOutput.Close(defaultPTYShutdownDeadline)
+
+	if err := r.setupExecutors(); err != nil {
+		return err
+	}
+
+	r.saveSupervision()
+
+	defer func() {
+		r.saveSupervision()
+
+		for _, executor := range r.executors {
+			executor.Teardown()
+		}
+	}()
+
+	r.draw()
+
+	if r.bundleLoadMode {
+		return r.runBundleLoadMode()
+	}
+
+	return r.runWorkerMode()
+}
+
+func (r *embeddedRuntime) setupExecutors() error {
+	ptyRows := layout.PtyRowsForFrame(&r.frame)
+
+	var termWriter io.Writer = r
+	if r.termOutput != nil {
+		termWriter = r.termOutput
+	}
+
+	for _, harnessType := range r.supportedHarnesses {
+		info, ok := Lookup(harnessType)
+		if !ok {
+			continue
+		}
+
+		executor := newExecutor(&info, r.rehearsal)
+
+		setupOpts := harnesstype.SetupOptions{
+			TermWriter:     termWriter,
+			TermWidth:      r.frame.ViewportWidth,
+			TermHeight:     ptyRows,
+			SignalDir:      r.signalDir,
+			RunnerConfig:   r.eng.RunnerConfig(),
+			BundleDir:      r.bundleDir,
+			ExtraDirs:      append([]string(nil), r.extraDirs...),
+			WorkingDir:     r.bundleWorkDir,
+			Env:            r.harnessEnv(),
+			BundleLoadMode: r.bundleLoadMode,
+			OnOutput: func(p []byte) {
+				r.output.Write(p)
+				r.appendTranscript("pty", p)
+				r.noteLocalActivity()
+			},
+			OnReady: func() {
+				if r.bundleLoadMode {
+					r.eng.SetStatus(engine.StatusReady)
+					r.draw()
+				}
+			},
+			OnExit: r.signalDone,
+		}
+
+		if err := executor.Setup(r.ctx, &setupOpts); err != nil {
+			return fmt.Errorf("failed to setup %s executor: %w", harnessType, err)
+		}
+
+		r.executors[harnessType] = executor
+	}
+
+	return nil
+}
+
+func (r *embeddedRuntime) runWorkerMode() error {
+	if err := r.eng.Start(r.ctx); err != nil {
+		return fmt.Errorf("start worker: %w", err)
+	}
+
+	logger := observability.FromContext(r.ctx).With(slog.String("component", "harness"))
+	defer listenWorkerControl(r.ctx, logger, r.eng, r.output, r.reloadRequests, r.habitatID, r.queueID)()
+
+	if r.metricsAddr != "" {
+		stopMetrics, err := serveMetrics(r.ctx, logger, r.metricsAddr, r.eng, r.executors)
+		if err != nil {
+			r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Metrics endpoint unavailable: %v", err))
+		} else {
+			defer stopMetrics()
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.eventLoop() }()
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.updateStatusLoop() }()
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.engineEventLoop() }()
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.signalLoop() }()
+
+	go func() {
+		select {
+		case <-r.ctx.Done():
+			r.signalDone()
+		case <-r.eng.Finished():
+			r.signalDone()
+		case <-r.done:
+		}
+	}()
+
+	<-r.done
+	r.cancel()
+
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), defaultPTYShutdownDeadline)
+	defer cancelDrain()
+
+	if err := r.eng.Drain(drainCtx); err != nil {
+		observability.FromContext(r.ctx).Warn("job engine shutdown incomplete",
+			slog.String("component", "harness"),
+			slog.String("event.type", "worker.drain_error"),
+			slog.String("error", err.Error()),
+		)
+	}
+
+	if r.onWorkerExit != nil {
+		stats := r.eng.Stats()
+		r.onWorkerExit(WorkerSummary{
+			StopReason: string(stats.StopReason),
+			Completed:  stats.Completed,
+			Failed:     stats.Failed,
+			Requeued:   stats.Requeued,
+		})
+	}
+
+	waitDone := make(chan struct{})
+
+	go func() { wg.Wait(); close(waitDone) }()
+
+	select {
+	case <-waitDone:
+	case <-time.After(defaultPTYShutdownDeadline):
+	}
+
+	return nil
+}
+
+func (r *embeddedRuntime) runBundleLoadMode() error {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.eventLoop() }()
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.updateStatusLoop() }()
+
+	wg.Add(1)
+
+	go func() { defer wg.Done(); r.signalLoop() }()
+
+	go func() {
+		select {
+		case <-r.ctx.Done():
+			r.signalDone()
+		case <-r.done:
+		}
+	}()
+
+	<-r.done
+	r.cancel()
+
+	waitDone := make(chan struct{})
+
+	go func() { wg.Wait(); close(waitDone) }()
+
+	select {
+	case <-waitDone:
+	case <-time.After(defaultPTYShutdownDeadline):
+	}
+
+	return nil
+}
+
+func (r *embeddedRuntime) eventLoop() {
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		default:
+		}
+
+		ev := r.screen.PollEvent()
+		if ev == nil {
+			continue
+		}
+
+		switch msg := ev.(type) {
+		case *tcell.EventResize:
+			width, height := msg.Size()
+			r.handleResize(width, height)
+		case *tcell.EventKey:
+			if r.handleKey(msg) {
+				return
+			}
+		case *tcell.EventMouse:
+			r.handleMouse(msg)
+		}
+	}
+}
+
+func (r *embeddedRuntime) updateStatusLoop() {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.done:
+			return
+		case <-ticker.C:
+			r.draw()
+		}
+	}
+}
+
+// engineEventLoop redraws the status chrome whenever the engine reports a change.
+func (r *embeddedRuntime) engineEventLoop() {
+	events := r.eng.Events()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case ev, ok := <-events:
+			if !ok {
+				return
+			}
+
+			if ev.Type == engine.EventResumed {
+				r.reconcileTerminalSize()
+				continue
+			}
+
+			if ev.Type == engine.EventJobStarted {
+				r.markJobStart()
+			}
+
+			r.draw()
+		}
+	}
+}
+
+// signalLoop handles process signals while the harness runs.
+//
+// SIGCONT arrives after the process was stopped (Ctrl+Z, or some
+// suspend-to-RAM paths), so platform state and the terminal are resynced
+// immediately rather than when the next timer fires.
+//
+// SIGHUP reloads the config file, as does `mush worker reload`. A real
+// hangup (the terminal went away) is told apart by the controlling terminal
+// no longer answering, and shuts the harness down as the default SIGHUP
+// action would.
+//
+// Windows has neither signal, so there the loop only waits for shutdown.
+func (r *embeddedRuntime) signalLoop() {
+	sigCh := make(chan os.Signal, 1)
+	notifyRuntimeSignals(sigCh)
+
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return
+		case <-r.done:
+			return
+		case <-r.reloadRequests:
+			r.reloadConfig()
+		case sig := <-sigCh:
+			if isResumeSignal(sig) {
+				r.reconcileTerminalSize()
+				r.eng.Resume(r.ctx)
+
+				continue
+			}
+
+			if _, _, err := term.GetSize(int(os.Stdin.Fd())); err != nil {
+				r.signalDone()
+
+				return
+			}
+
+			r.reloadConfig()
+		}
+	}
+}
+
+// reloadConfig re-reads the config and applies the settings in
+// config.ReloadableKeys, logging each one that changed.
+func (r *embeddedRuntime) reloadConfig() {
+	logger := observability.FromContext(r.ctx).With(slog.String("component", "harness"))
+
+	next := config.Load()
+	changes := config.Diff(r.cfg, next, config.ReloadableKeys)
+
+	if level := reloadedLogLevel(r.cfg, next); level != "" {
+		r.uiMu.Lock()
+		debugLogging := r.debugLogging
+		if debugLogging {
+			// Keep debug on; the reloaded level applies when F4 turns it off.
+			r.levelBeforeDebug = level
+		}
+		r.uiMu.Unlock()
+
+		if !debugLogging {
+			if _, err := observability.SetLevel(level); err != nil {
+				r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Config reload: %v", err))
+			}
+		}
+	}
+
+	r.eng.Reload(next)
+	r.inputLock.Store(next.InputLock())
+	r.cfg = next
+
+	logConfigChanges(logger, changes)
+}
+
+// logConfigChanges logs each setting a reload changed, then a summary.
+func logConfigChanges(logger *slog.Logger, changes []config.Change) {
+	for _, change := range changes {
+		logger.Info("config setting changed",
+			slog.String("event.type", "config.reload.change"),
+			slog.String("config.key", change.Key),
+			slog.String("config.old", change.Old),
+			slog.String("config.new", change.New),
+		)
+	}
+
+	logger.Info("config reloaded",
+		slog.String("event.type", "config.reload"),
+		slog.Int("config.changed", len(changes)),
+	)
+}
+
+func (r *embeddedRuntime) statusSnapshot() harnessstate.Snapshot {
+	stats := r.eng.Stats()
+
+	nowFn := r.now
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+
+	now := nowFn()
+	frame := r.frame
+
+	snap := harnessstate.Snapshot{
+		Width:              r.width,
+		Height:             r.height,
+		SidebarVisible:     frame.SidebarVisible,
+		SidebarWidth:       frame.SidebarWidth,
+		PaneXStart:         frame.PaneXStart,
+		PaneWidth:          frame.ViewportWidth,
+		BundleLoadMode:     r.bundleLoadMode,
+		BundleName:         r.bundleName,
+		BundleVer:          r.bundleVer,
+		BundleLayers:       r.bundleSummary.TotalLayers,
+		BundleSkills:       append([]string(nil), r.bundleSummary.Skills...),
+		BundleAgents:       append([]string(nil), r.bundleSummary.Agents...),
+		BundleTools:        append([]string(nil), r.bundleSummary.ToolConfigs...),
+		BundleOther:        append([]string(nil), r.bundleSummary.Other...),
+		HabitatID:          r.habitatID,
+		QueueID:            r.queueID,
+		SupportedHarnesses: append([]string(nil), r.supportedHarnesses...),
+		StatusLabel:        stats.Status.String(),
+		JobID:              stats.JobID,
+		JobStartedAt:       stats.JobStartedAt,
+		LastHeartbeat:      stats.LastHeartbeat,
+		Completed:          stats.Completed,
+		Failed:             stats.Failed,
+		PollInterval:       stats.PollInterval,
+		PollBackedOff:      stats.PollBackedOff,
+		LastError:          stats.LastError,
+		LastErrorTime:      stats.LastErrorTime,
+		LastErrorSeverity:  stats.LastErrorSeverity.String(),
+		LastErrorCount:     stats.LastErrorCount,
+		LastErrorRequestID: stats.LastErrorRequestID,
+		Errors:             snapshotErrors(stats.Errors),
+		MCPServers:         buildMCPServerStatuses(r.eng, now),
+		ExpandedSections:   r.sidebarExpanded,
+		Now:                now,
+	}
+
+	if usage := stats.Usage; usage != nil {
+		snap.UsageKnown = true
+		snap.UsageTurns = usage.Turns
+		snap.UsageTokens = usage.Tokens()
+		snap.UsageCostUSD = usage.CostUSD
+		snap.UsageCostKnown = usage.CostKnown
+		snap.UsageMaxTurns = usage.MaxTurns
+		snap.UsageMaxBudgetUSD = usage.MaxBudgetUSD
+		snap.UsageNearLimit = usage.NearTurnLimit() || usage.NearBudgetLimit()
+	}
+
+	if r.client != nil {
+		if active := r.client.ActiveBaseURL(); active != r.client.BaseURL() {
+			snap.FallbackEndpoint = endpointHost(active)
+		}
+	}
+
+	if r.termOutput != nil {
+		snap.OutputDroppedBytes = r.termOutput.Dropped()
+	}
+
+	if supervision, ok := r.supervision(); ok {
+		total := harnesstype.Supervision{}
+		for _, s := range supervision {
+			total = total.Add(s)
+		}
+
+		snap.SupervisionKnown = true
+		snap.PTYRestarts = total.PTYRestarts()
+		snap.BypassAccepts = total.BypassAccepts
+		snap.ReadyTimeouts = total.ReadyTimeouts
+		snap.ReadyAverage = total.AverageReady()
+	}
+
+	return snap
+}
+
+// endpointHost returns the host of an API base URL for display.
+func endpointHost(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+
+	return baseURL
+}
+
+// supervision collects process supervision counters by harness type from
+// executors that report them. Bundle sessions are interactive, so only
+// worker startups are tracked.
+func (r *embeddedRuntime) supervision() (map[string]harnesstype.Supervision, bool) {
+	if r.bundleLoadMode {
+		return nil, false
+	}
+
+	out := make(map[string]harnesstype.Supervision)
+
+	for harnessType, executor := range r.executors {
+		if reporter, ok := executor.(harnesstype.SupervisionReporter); ok {
+			out[harnessType] = reporter.Supervision()
+		}
+	}
+
+	return out, len(out) > 0
+}
+
+// onTerminalOutputDropped reports that the terminal fell behind and some
+// harness output was not drawn. Transcripts and job capture still have it.
+func (r *embeddedRuntime) onTerminalOutputDropped() {
+	observability.FromContext(r.ctx).Warn("terminal output dropped",
+		slog.String("component", "harness"),
+		slog.String("event.type", "harness.output.drop"),
+		slog.Int64("output.dropped_bytes", r.termOutput.Dropped()),
+	)
+
+	r.eng.ReportError(engine.SeverityWarning, "Terminal is falling behind; some output was skipped")
+}
+
+// saveSupervision records the supervision counters for `mush doctor`.
+func (r *embeddedRuntime) saveSupervision() {
+	supervision, ok := r.supervision()
+	if !ok {
+		return
+	}
+
+	if err := worker.SaveSupervision(supervision, time.Now()); err != nil {
+		observability.FromContext(r.ctx).Debug("harness supervision not saved",
+			slog.String("component", "harness"),
+			slog.String("event.type", "harness.supervision.save_error"),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+func snapshotErrors(entries []engine.ErrorEntry) []harnessstate.ErrorEntry {
+	out := make([]harnessstate.ErrorEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, harnessstate.ErrorEntry{
+			Message:   entry.Message,
+			Severity:  entry.Severity.String(),
+			Count:     entry.Count,
+			LastSeen:  entry.LastSeen,
+			RequestID: entry.RequestID,
+		})
+	}
+
+	return out
+}
+
+func (r *embeddedRuntime) appendTranscript(stream string, chunk []byte) {
+	r.transcriptMu.Lock()
+	store := r.transcriptStore
+	r.transcriptMu.Unlock()
+
+	if store == nil || len(chunk) == 0 {
+		return
+	}
+
+	if err := store.Append(stream, chunk); err != nil {
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript write failed: %v", err))
+	}
+}
+
+// recordJob marks a job's start or end in the transcript.
+func (r *embeddedRuntime) recordJob(rec *transcript.JobRecord) {
+	r.transcriptMu.Lock()
+	store := r.transcriptStore
+	r.transcriptMu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	if err := store.AppendJob(rec); err != nil {
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript write failed: %v", err))
+	}
+}
+
+func (r *embeddedRuntime) closeTranscript() {
+	r.transcriptMu.Lock()
+	store := r.transcriptStore
+	r.transcriptStore = nil
+	r.transcriptMu.Unlock()
+
+	if store == nil {
+		return
+	}
+
+	if err := store.Close(); err != nil {
+		r.eng.ReportError(engine.SeverityWarning, fmt.Sprintf("Transcript close failed: %v", err))
+	}
+}
+
+func (r *embeddedRuntime) infof(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	_, _ = r.Write([]byte(msg + "\r\n"))
+}
+
+func (r *embeddedRuntime) signalDone() {
+	r.closeOnce.Do(func() { close(r.done) })
+}
+
+// openTranscript opens a transcript store for a new session when history is
+// enabled, by the harness config or the config file, and a supported harness
+// produces transcripts. It returns nil when there is nothing to record.
+func openTranscript(enabled bool, dir string, lines int, cfg *config.Config, supportedHarnesses []string) (*transcript.Store, error) {
+	if !enabled {
+		enabled = cfg.HistoryEnabled()
+	}
+
+	if !enabled || !hasTranscriptSource(supportedHarnesses) {
+		return nil, nil
+	}
+
+	if dir == "" {
+		dir = cfg.HistoryDir()
+	}
+
+	if lines <= 0 {
+		lines = cfg.HistoryScrollbackLines()
+	}
+
+	return transcript.NewStore(transcript.StoreOptions{
+		SessionID: uuid.NewString(),
+		Dir:       dir,
+		MaxLines:  lines,
+	})
+}
+
+// openNetworkRecorder starts the proxy that records the harnesses' outbound
+// calls, or returns nil when worker.network_recording is off. A bundle
+// session runs no jobs, so it records nothing.
+func openNetworkRecorder(cfg *config.Config, bundleLoadMode bool) (*netrecord.Recorder, error) {
+	if bundleLoadMode || !cfg.WorkerNetworkRecording() {
+		return nil, nil
+	}
+
+	return netrecord.Start() //nolint:wrapcheck // Start's errors name what failed
+}
+
+// harnessEnv returns the environment added to each harness process: the
+// bundle's, and the network recorder's proxy settings.
+func (r *embeddedRuntime) harnessEnv() []string {
+	env := append([]string(nil), r.bundleEnv...)
+	if r.network != nil {
+		env = append(env, r.network.Env()...)
+	}
+
+	return env
+}
+
+func clampTerminalSize(width, height int) (clampedWidth, clampedHeight int) {
+	return layout.ClampTerminalSize(width, height)
+}
+
+// needsSignalDir checks if any supported harness type implements
+// harnesstype.SignalDirConsumer or declares a file-based completion mode.
+func needsSignalDir(supportedHarnesses []string) bool {
+	for _, name := range supportedHarnesses {
+		if spec, ok := GetProvider(name); ok && spec.Completion.NeedsSignalDir() {
+			return true
+		}
+
+		info, ok := Lookup(name)
+		if !ok {
+			continue
+		}
+
+		executor := info.New()
+		if _, ok := executor.(harnesstype.SignalDirConsumer); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasTranscriptSource checks if any supported harness type implements harnesstype.TranscriptSource.
+func hasTranscriptSource(supportedHarnesses []string) bool {
+	for _, name := range supportedHarnesses {
+		info, ok := Lookup(name)
+		if !ok {
+			continue
+		}
+
+		executor := info.New()
+		if ts, ok := executor.(harnesstype.TranscriptSource); ok && ts.WantsTranscript() {
+			return true
+		}
+	}
+
+	return false
+}
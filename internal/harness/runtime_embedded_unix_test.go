@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
 	"github.com/musher-dev/mush/internal/harness/ui/layout"
 )
 
@@ -30,10 +31,7 @@ func TestEmbeddedSidebarLinesIncludeBundleAssetSections(t *testing.T) {
 			Other:       []string{"notes.txt"},
 		},
 		now: time.Now,
-		jobs: &JobLoop{
-			status:        StatusReady,
-			lastHeartbeat: time.Now(),
-		},
+		eng: engine.New(&engine.Options{InitialStatus: engine.StatusReady}),
 	}
 
 	lines := r.sidebarLines(layout.PtyRowsForFrame(&r.frame))
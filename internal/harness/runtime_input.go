@@ -0,0 +1,496 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/harness/ui/layout"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// inputMode selects the handler that sees a key after the global bindings.
+type inputMode int
+
+const (
+	// inputLive types keys into the child, apart from paging into scrollback.
+	inputLive inputMode = iota
+	// inputCopy is scrolled back: paging, Home/End, and copy keys are
+	// handled here, and any other key returns to the live tail.
+	inputCopy
+	// inputOverlay swallows keys while an F2 or F3 overlay is open.
+	inputOverlay
+	// inputPaused drops keys while a bundle reload restarts the child.
+	inputPaused
+)
+
+func (m inputMode) String() string {
+	switch m {
+	case inputLive:
+		return "live"
+	case inputCopy:
+		return "copy"
+	case inputOverlay:
+		return "overlay"
+	case inputPaused:
+		return "paused"
+	default:
+		return "unknown"
+	}
+}
+
+// keyResult is what a key handler did with a key.
+type keyResult int
+
+const (
+	// keyForward passes the key on to the child.
+	keyForward keyResult = iota
+	// keyHandled stops routing; the child does not see the key.
+	keyHandled
+	// keyQuit stops routing and exits watch mode.
+	keyQuit
+)
+
+type keyHandler func(r *embeddedRuntime, ev *tcell.EventKey) keyResult
+
+// globalKeys are bound in every mode. A binding may return keyForward to
+// let the mode handler see the key.
+var globalKeys = map[tcell.Key]keyHandler{
+	tcell.KeyCtrlQ: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		r.signalDone()
+
+		return keyQuit
+	},
+	tcell.KeyCtrlC: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		if r.handleCtrlC() {
+			return keyQuit
+		}
+
+		return keyHandled
+	},
+	tcell.KeyF2: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		r.toggleErrorOverlay()
+
+		return keyHandled
+	},
+	tcell.KeyF3: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		r.toggleBundleOverlay()
+
+		return keyHandled
+	},
+	tcell.KeyF4: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		r.toggleDebugLogging()
+
+		return keyHandled
+	},
+	tcell.KeyF5: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		if r.bundleReload == nil {
+			return keyForward
+		}
+
+		go r.reloadBundle()
+
+		return keyHandled
+	},
+	tcell.KeyF6: func(r *embeddedRuntime, _ *tcell.EventKey) keyResult {
+		if !r.inputLock.Load() {
+			return keyForward
+		}
+
+		r.toggleInputLock()
+
+		return keyHandled
+	},
+}
+
+// modeKeys handle keys that no global binding claimed.
+var modeKeys = map[inputMode]keyHandler{
+	inputLive:    (*embeddedRuntime).handleLiveKey,
+	inputCopy:    (*embeddedRuntime).handleCopyModeKey,
+	inputOverlay: (*embeddedRuntime).handleOverlayKey,
+	inputPaused:  func(*embeddedRuntime, *tcell.EventKey) keyResult { return keyHandled },
+}
+
+// handleKey routes a key through the global bindings, then the handler for
+// the current input mode, and types whatever is forwarded into the child.
+// It reports whether watch mode should exit.
+func (r *embeddedRuntime) handleKey(ev *tcell.EventKey) bool {
+	result := keyForward
+
+	if bound, ok := globalKeys[ev.Key()]; ok {
+		result = bound(r, ev)
+	}
+
+	if result == keyForward {
+		result = modeKeys[r.inputMode()](r, ev)
+	}
+
+	if result == keyQuit {
+		return true
+	}
+
+	if result == keyForward && r.inputLocked() && !passesInputLock(ev) {
+		result = keyHandled
+	}
+
+	if result == keyForward {
+		if keyBytes := encodeTCellKey(ev); len(keyBytes) > 0 {
+			r.noteLocalInput()
+			r.writeInput(keyBytes)
+		}
+	}
+
+	return false
+}
+
+// inputMode reports which handler receives keys. Overlays take precedence
+// over a reload, and a reload over scrollback.
+func (r *embeddedRuntime) inputMode() inputMode {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	switch {
+	case r.errorOverlay || r.bundleOverlay:
+		return inputOverlay
+	case r.reloading.Load():
+		return inputPaused
+	case !r.followTail && !r.isAltScreenActive():
+		return inputCopy
+	default:
+		return inputLive
+	}
+}
+
+// handleLiveKey pages into scrollback on PgUp/PgDn unless the child owns
+// the alternate screen, and forwards everything else.
+func (r *embeddedRuntime) handleLiveKey(ev *tcell.EventKey) keyResult {
+	if r.isAltScreenActive() {
+		return keyForward
+	}
+
+	page := max(layout.PtyRowsForFrame(&r.frame)-1, 1)
+
+	switch ev.Key() {
+	case tcell.KeyPgUp:
+		r.scrollUp(page)
+	case tcell.KeyPgDn:
+		r.scrollDown(page)
+	default:
+		return keyForward
+	}
+
+	return keyHandled
+}
+
+// handleCopyModeKey handles paging and copy keys while scrolled back. Home,
+// End, and Escape only act here; at the live tail they belong to the child.
+// Any other key that types something returns to the live tail first.
+func (r *embeddedRuntime) handleCopyModeKey(ev *tcell.EventKey) keyResult {
+	if r.handleLiveKey(ev) == keyHandled || r.handleCopyKey(ev) {
+		return keyHandled
+	}
+
+	switch ev.Key() {
+	case tcell.KeyHome:
+		r.scrollToTop()
+
+		return keyHandled
+	case tcell.KeyEnd, tcell.KeyEscape:
+		r.scrollToBottom()
+
+		return keyHandled
+	}
+
+	if len(encodeTCellKey(ev)) == 0 {
+		return keyHandled
+	}
+
+	r.scrollToBottom()
+
+	return keyForward
+}
+
+// handleOverlayKey makes overlays modal: Escape closes them and other keys
+// are swallowed so they don't reach a child the user can't see.
+func (r *embeddedRuntime) handleOverlayKey(ev *tcell.EventKey) keyResult {
+	if ev.Key() == tcell.KeyEscape {
+		r.closeOverlays()
+	}
+
+	return keyHandled
+}
+
+// inputLocked reports whether worker.input_lock is keeping keystrokes from
+// the running job's harness, unless F6 unlocked it for this job.
+func (r *embeddedRuntime) inputLocked() bool {
+	if !r.inputLock.Load() || r.bundleLoadMode {
+		return false
+	}
+
+	jobID := r.eng.CurrentJobID()
+	if jobID == "" {
+		return false
+	}
+
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	return jobID != r.unlockedJob
+}
+
+// toggleInputLock lets keystrokes through to the running job, or locks
+// them out again. The next job starts locked.
+func (r *embeddedRuntime) toggleInputLock() {
+	jobID := r.eng.CurrentJobID()
+	if jobID == "" {
+		return
+	}
+
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	if r.unlockedJob == jobID {
+		r.unlockedJob = ""
+	} else {
+		r.unlockedJob = jobID
+	}
+
+	r.drawLocked()
+}
+
+// passesInputLock reports whether a key still reaches a locked job:
+// Ctrl combinations and Escape, which stop or steer the harness rather
+// than type into its prompt.
+func passesInputLock(ev *tcell.EventKey) bool {
+	if ev.Key() == tcell.KeyEscape {
+		return true
+	}
+
+	return ev.Modifiers()&tcell.ModCtrl != 0 && ev.Key() >= tcell.KeyCtrlA && ev.Key() <= tcell.KeyCtrlZ
+}
+
+// toggleErrorOverlay shows or hides the error history overlay.
+func (r *embeddedRuntime) toggleErrorOverlay() {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	r.errorOverlay = !r.errorOverlay
+	r.bundleOverlay = false
+	r.drawLocked()
+}
+
+// toggleBundleOverlay shows or hides the bundle and MCP detail overlay.
+func (r *embeddedRuntime) toggleBundleOverlay() {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	r.bundleOverlay = !r.bundleOverlay
+	r.errorOverlay = false
+	r.drawLocked()
+}
+
+// toggleDebugLogging switches logging to debug, or back to the level it had
+// before, without restarting the worker.
+func (r *embeddedRuntime) toggleDebugLogging() {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	logger := observability.FromContext(r.ctx).With(slog.String("component", "harness"))
+
+	if r.debugLogging {
+		logger.Info("debug logging disabled", slog.String("event.type", "log.level.change"))
+
+		if _, err := observability.SetLevel(r.levelBeforeDebug); err != nil {
+			_, _ = observability.SetLevel("info")
+		}
+
+		r.debugLogging = false
+	} else {
+		r.levelBeforeDebug = strings.ToLower(observability.CurrentLevel().String())
+		_, _ = observability.SetLevel("debug")
+		r.debugLogging = true
+
+		logger.Info("debug logging enabled", slog.String("event.type", "log.level.change"))
+	}
+
+	r.drawLocked()
+}
+
+func (r *embeddedRuntime) closeOverlays() {
+	r.uiMu.Lock()
+	defer r.uiMu.Unlock()
+
+	r.errorOverlay = false
+	r.bundleOverlay = false
+	r.drawLocked()
+}
+
+func encodeTCellKey(ev *tcell.EventKey) []byte {
+	switch ev.Key() {
+	case tcell.KeyRune:
+		ch := ev.Rune()
+		buf := make([]byte, utf8.RuneLen(ch))
+		utf8.EncodeRune(buf, ch)
+
+		if ev.Modifiers()&tcell.ModAlt != 0 {
+			return append([]byte(ansi.ESC), buf...)
+		}
+
+		return buf
+	case tcell.KeyEnter:
+		return []byte{'\r'}
+	case tcell.KeyTab:
+		return []byte{'\t'}
+	case tcell.KeyBacktab:
+		return []byte(ansi.KeyBacktab)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		return []byte{0x7f}
+	case tcell.KeyEsc:
+		return []byte(ansi.ESC)
+	case tcell.KeyUp:
+		return []byte(ansi.KeyUp)
+	case tcell.KeyDown:
+		return []byte(ansi.KeyDown)
+	case tcell.KeyRight:
+		return []byte(ansi.KeyRight)
+	case tcell.KeyLeft:
+		return []byte(ansi.KeyLeft)
+	case tcell.KeyHome:
+		return []byte(ansi.KeyHome)
+	case tcell.KeyEnd:
+		return []byte(ansi.KeyEnd)
+	case tcell.KeyPgUp:
+		return []byte(ansi.KeyPageUp)
+	case tcell.KeyPgDn:
+		return []byte(ansi.KeyPageDown)
+	case tcell.KeyDelete:
+		return []byte(ansi.KeyDelete)
+	case tcell.KeyInsert:
+		return []byte(ansi.KeyInsert)
+	case tcell.KeyCtrlA:
+		return []byte{0x01}
+	case tcell.KeyCtrlB:
+		return []byte{0x02}
+	case tcell.KeyCtrlD:
+		return []byte{0x04}
+	case tcell.KeyCtrlE:
+		return []byte{0x05}
+	case tcell.KeyCtrlF:
+		return []byte{0x06}
+	case tcell.KeyCtrlH:
+		return []byte{0x08}
+	case tcell.KeyCtrlI:
+		return []byte{0x09}
+	case tcell.KeyCtrlJ:
+		return []byte{0x0a}
+	case tcell.KeyCtrlK:
+		return []byte{0x0b}
+	case tcell.KeyCtrlL:
+		return []byte{0x0c}
+	case tcell.KeyCtrlM:
+		return []byte{0x0d}
+	case tcell.KeyCtrlN:
+		return []byte{0x0e}
+	case tcell.KeyCtrlO:
+		return []byte{0x0f}
+	case tcell.KeyCtrlP:
+		return []byte{0x10}
+	case tcell.KeyCtrlR:
+		return []byte{0x12}
+	case tcell.KeyCtrlT:
+		return []byte{0x14}
+	case tcell.KeyCtrlU:
+		return []byte{0x15}
+	case tcell.KeyCtrlV:
+		return []byte{0x16}
+	case tcell.KeyCtrlW:
+		return []byte{0x17}
+	case tcell.KeyCtrlX:
+		return []byte{0x18}
+	case tcell.KeyCtrlY:
+		return []byte{0x19}
+	case tcell.KeyCtrlZ:
+		return []byte{0x1a}
+	}
+
+	return nil
+}
+
+func (r *embeddedRuntime) handleCtrlC() bool {
+	if !r.eng.HasActiveInterruptableJob() {
+		r.signalDone()
+
+		return true
+	}
+
+	nowFn := r.now
+	if nowFn == nil {
+		nowFn = time.Now
+	}
+
+	now := nowFn()
+	secondPress := !r.lastCtrlCAt.IsZero() && now.Sub(r.lastCtrlCAt) <= r.ctrlCExitWindow
+
+	if secondPress {
+		r.lastCtrlCAt = time.Time{}
+		r.infof("Second Ctrl+C received: exiting watch mode.")
+		r.signalDone()
+
+		return true
+	}
+
+	r.lastCtrlCAt = now
+
+	if executor, ok := r.executors[r.eng.CurrentJobHarnessType()]; ok {
+		if ih, ok := executor.(harnesstype.InterruptHandler); ok {
+			_ = ih.Interrupt()
+		}
+	}
+
+	r.infof("Interrupt sent to agent. Press Ctrl+C again within %s to exit watch mode.", r.ctrlCExitWindow.Round(time.Second))
+
+	return false
+}
+
+func (r *embeddedRuntime) writeInput(keyBytes []byte) {
+	for _, harnessType := range r.supportedHarnesses {
+		if executor, ok := r.executors[harnessType]; ok {
+			if ir, ok := executor.(harnesstype.InputReceiver); ok {
+				_, _ = ir.WriteInput(keyBytes)
+
+				return
+			}
+		}
+	}
+}
+
+func (r *embeddedRuntime) syncMouseCaptureLocked() {
+	if r.screen == nil {
+		return
+	}
+
+	// Capture mouse events unless the terminal profile leaves the mouse to
+	// the terminal — the runtime needs them for its own viewport
+	// interactions (wheel scroll, scrollbar drag, sidebar clicks)
+	// regardless of whether the child process owns the mouse. Child
+	// forwarding is gated inside handleMouse via childOwnsMouse().
+	shouldCapture := !r.noMouse
+	if shouldCapture == r.mouseCaptureEnabled {
+		return
+	}
+
+	if shouldCapture {
+		r.screen.EnableMouse(tcell.MouseButtonEvents | tcell.MouseDragEvents)
+	} else {
+		r.screen.DisableMouse()
+	}
+
+	r.mouseCaptureEnabled = shouldCapture
+}
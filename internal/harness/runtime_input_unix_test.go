@@ -0,0 +1,220 @@
+//go:build unix
+
+package harness
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestEncodeTCellKey_EscapeSequences(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   *tcell.EventKey
+		want string
+	}{
+		{name: "Escape alone", ev: tcell.NewEventKey(tcell.KeyEscape, 0, 0), want: ansi.ESC},
+		{name: "Alt prefixes ESC", ev: tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModAlt), want: "\x1bb"},
+		{name: "Alt with multibyte rune", ev: tcell.NewEventKey(tcell.KeyRune, 'é', tcell.ModAlt), want: "\x1b\xc3\xa9"},
+		{name: "Alt with bracket is not a CSI", ev: tcell.NewEventKey(tcell.KeyRune, '[', tcell.ModAlt), want: "\x1b["},
+		{name: "Backtab", ev: tcell.NewEventKey(tcell.KeyBacktab, 0, 0), want: ansi.KeyBacktab},
+		{name: "cursor key", ev: tcell.NewEventKey(tcell.KeyUp, 0, 0), want: ansi.KeyUp},
+		{name: "page key", ev: tcell.NewEventKey(tcell.KeyPgUp, 0, 0), want: ansi.KeyPageUp},
+		{name: "function key has no encoding", ev: tcell.NewEventKey(tcell.KeyF6, 0, 0), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(encodeTCellKey(tt.ev)); got != tt.want {
+				t.Fatalf("encodeTCellKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleKey_RoutesByInputMode(t *testing.T) {
+	scrolledBack := func(r *embeddedRuntime) {
+		seedScrollback(r, 30)
+		r.handleKey(tcell.NewEventKey(tcell.KeyPgUp, 0, 0))
+	}
+
+	tests := []struct {
+		name     string
+		setup    func(r *embeddedRuntime)
+		mode     inputMode
+		ev       *tcell.EventKey
+		want     string
+		wantTail bool
+	}{
+		{
+			name:     "live forwards Escape",
+			mode:     inputLive,
+			ev:       tcell.NewEventKey(tcell.KeyEscape, 0, 0),
+			want:     ansi.ESC,
+			wantTail: true,
+		},
+		{
+			name:     "live forwards Home",
+			mode:     inputLive,
+			ev:       tcell.NewEventKey(tcell.KeyHome, 0, 0),
+			want:     ansi.KeyHome,
+			wantTail: true,
+		},
+		{
+			name:     "live forwards Alt sequences",
+			mode:     inputLive,
+			ev:       tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModAlt),
+			want:     "\x1by",
+			wantTail: true,
+		},
+		{
+			name: "alternate screen forwards PgUp",
+			setup: func(r *embeddedRuntime) {
+				seedScrollback(r, 30)
+				_, _ = r.vt.Write([]byte("\x1b[?1049h"))
+			},
+			mode:     inputLive,
+			ev:       tcell.NewEventKey(tcell.KeyPgUp, 0, 0),
+			want:     ansi.KeyPageUp,
+			wantTail: true,
+		},
+		{
+			name:  "copy mode Escape returns to tail without forwarding",
+			setup: scrolledBack,
+			mode:  inputCopy,
+			ev:    tcell.NewEventKey(tcell.KeyEscape, 0, 0),
+			// Escape is consumed by the runtime, not typed into the child.
+			wantTail: true,
+		},
+		{
+			name:  "copy mode keeps copy keys",
+			setup: scrolledBack,
+			mode:  inputCopy,
+			ev:    tcell.NewEventKey(tcell.KeyRune, '5', 0),
+		},
+		{
+			name:     "copy mode Alt key leaves scrollback and forwards",
+			setup:    scrolledBack,
+			mode:     inputCopy,
+			ev:       tcell.NewEventKey(tcell.KeyRune, 'y', tcell.ModAlt),
+			want:     "\x1by",
+			wantTail: true,
+		},
+		{
+			name:  "copy mode ignores keys with no encoding",
+			setup: scrolledBack,
+			mode:  inputCopy,
+			ev:    tcell.NewEventKey(tcell.KeyF6, 0, 0),
+		},
+		{
+			name:     "overlay swallows Alt sequences",
+			setup:    func(r *embeddedRuntime) { r.errorOverlay = true },
+			mode:     inputOverlay,
+			ev:       tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt),
+			wantTail: true,
+		},
+		{
+			name:     "paused drops typing",
+			setup:    func(r *embeddedRuntime) { r.reloading.Store(true) },
+			mode:     inputPaused,
+			ev:       tcell.NewEventKey(tcell.KeyRune, 'a', 0),
+			wantTail: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRuntime(t)
+			exec := &testInputExecutor{}
+			r.executors["test"] = exec
+			r.copyToClipboard = func(context.Context, string) (string, error) { return "test", nil }
+
+			if tt.setup != nil {
+				tt.setup(r)
+			}
+
+			if got := r.inputMode(); got != tt.mode {
+				t.Fatalf("inputMode() = %v, want %v", got, tt.mode)
+			}
+
+			if r.handleKey(tt.ev) {
+				t.Fatal("handleKey() = true, want false")
+			}
+
+			var got string
+			for _, w := range exec.writes {
+				got += string(w)
+			}
+
+			if got != tt.want {
+				t.Fatalf("child input = %q, want %q", got, tt.want)
+			}
+
+			if r.followTail != tt.wantTail {
+				t.Fatalf("followTail = %v, want %v", r.followTail, tt.wantTail)
+			}
+		})
+	}
+}
+
+func TestHandleKey_GlobalKeysWorkInEveryMode(t *testing.T) {
+	r := newTestRuntime(t)
+	r.reloading.Store(true)
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF2, 0, 0))
+
+	if !r.errorOverlay {
+		t.Fatal("errorOverlay = false after F2 during a reload, want true")
+	}
+
+	if got := r.inputMode(); got != inputOverlay {
+		t.Fatalf("inputMode() = %v, want %v", got, inputOverlay)
+	}
+}
+
+func TestPassesInputLock(t *testing.T) {
+	tests := []struct {
+		name string
+		ev   *tcell.EventKey
+		want bool
+	}{
+		{name: "printable rune", ev: tcell.NewEventKey(tcell.KeyRune, 'a', 0), want: false},
+		{name: "Enter", ev: tcell.NewEventKey(tcell.KeyEnter, 0, 0), want: false},
+		{name: "Tab", ev: tcell.NewEventKey(tcell.KeyTab, 0, 0), want: false},
+		{name: "Backspace", ev: tcell.NewEventKey(tcell.KeyBackspace2, 0, 0), want: false},
+		{name: "Alt rune", ev: tcell.NewEventKey(tcell.KeyRune, 'b', tcell.ModAlt), want: false},
+		{name: "Escape", ev: tcell.NewEventKey(tcell.KeyEscape, 0, 0), want: true},
+		{name: "Ctrl+D", ev: tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModCtrl), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := passesInputLock(tt.ev); got != tt.want {
+				t.Fatalf("passesInputLock() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleKey_InputLockIdleBetweenJobs(t *testing.T) {
+	r := newTestRuntime(t)
+	exec := &testInputExecutor{}
+	r.executors = map[string]harnesstype.Executor{"test": exec}
+	r.inputLock.Store(true)
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF6, 0, 0))
+	r.handleKey(tcell.NewEventKey(tcell.KeyRune, 'a', 0))
+
+	if r.inputLocked() {
+		t.Fatal("inputLocked() = true with no job running")
+	}
+
+	if len(exec.writes) != 1 || string(exec.writes[0]) != "a" {
+		t.Fatalf("writes = %q, want only the typed key", exec.writes)
+	}
+}
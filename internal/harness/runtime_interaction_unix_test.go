@@ -4,6 +4,7 @@ package harness
 
 import (
 	"context"
+	"log/slog"
 	"strings"
 	"testing"
 	"time"
@@ -13,8 +14,10 @@ import (
 
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/engine"
 	"github.com/musher-dev/mush/internal/harness/harnesstype"
 	"github.com/musher-dev/mush/internal/harness/ui/layout"
+	"github.com/musher-dev/mush/internal/observability"
 )
 
 type testInputExecutor struct {
@@ -48,10 +51,13 @@ func newTestRuntime(t *testing.T) *embeddedRuntime {
 	t.Cleanup(screen.Fini)
 
 	frame := layout.ComputeFrame(140, 20, true)
+	screen.SetSize(frame.Width, frame.Height)
+
 	r := &embeddedRuntime{
 		ctx:                t.Context(),
 		screen:             screen,
 		vt:                 vt10x.New(vt10x.WithSize(frame.ViewportWidth, layout.PtyRowsForFrame(&frame))),
+		output:             newOutputFanout(frame.ViewportWidth, layout.PtyRowsForFrame(&frame)),
 		width:              frame.Width,
 		height:             frame.Height,
 		frame:              frame,
@@ -62,10 +68,7 @@ func newTestRuntime(t *testing.T) *embeddedRuntime {
 		executors:          map[string]harnesstype.Executor{},
 		sidebarExpanded:    make(map[string]bool),
 		now:                time.Now,
-		jobs: &JobLoop{
-			status:        StatusReady,
-			lastHeartbeat: time.Now(),
-		},
+		eng:                engine.New(&engine.Options{InitialStatus: engine.StatusReady}),
 	}
 
 	return r
@@ -170,6 +173,121 @@ func TestHandleKey_PageHomeEndUseViewportModel(t *testing.T) {
 	}
 }
 
+func TestHandleKey_CopyModeCopiesLastLinesAndJobOutput(t *testing.T) {
+	r := newTestRuntime(t)
+	seedScrollback(r, 20)
+	_, _ = r.vt.Write([]byte("live"))
+
+	var copied []string
+
+	r.copyToClipboard = func(_ context.Context, text string) (string, error) {
+		copied = append(copied, text)
+		return "test", nil
+	}
+
+	width := r.frame.ViewportWidth
+	r.handleKey(tcell.NewEventKey(tcell.KeyPgUp, 0, 0))
+
+	for _, ch := range "3y" {
+		r.handleKey(tcell.NewEventKey(tcell.KeyRune, ch, 0))
+	}
+
+	want := strings.Repeat("s", width) + "\n" + strings.Repeat("t", width) + "\nlive\n"
+	if len(copied) != 1 || copied[0] != want {
+		t.Fatalf("3y copied %q, want %q", copied, want)
+	}
+
+	if r.followTail {
+		t.Fatal("copy keys should stay in copy mode")
+	}
+
+	r.jobStartLine = r.scrollback.Offset() + 19
+	r.handleKey(tcell.NewEventKey(tcell.KeyRune, 'Y', 0))
+
+	if len(copied) != 2 || copied[1] != strings.Repeat("t", width)+"\nlive\n" {
+		t.Fatalf("Y copied %q, want the job's lines", copied[len(copied)-1])
+	}
+
+	if !strings.Contains(r.copyNotice, "Copied 2 lines of job output") {
+		t.Errorf("copyNotice = %q", r.copyNotice)
+	}
+}
+
+func TestHandleKey_F2TogglesErrorOverlay(t *testing.T) {
+	r := newTestRuntime(t)
+	r.eng.ReportError(engine.SeverityWarning, "Heartbeat failed: timeout")
+	r.eng.ReportError(engine.SeverityWarning, "Heartbeat failed: timeout")
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF2, 0, 0))
+
+	if !r.errorOverlay {
+		t.Fatal("errorOverlay = false after F2, want true")
+	}
+
+	sim, ok := r.screen.(tcell.SimulationScreen)
+	if !ok {
+		t.Fatal("screen is not a SimulationScreen")
+	}
+
+	cells, width, _ := sim.GetContents()
+	row := layout.TopBarHeight + 1
+
+	var line strings.Builder
+
+	for col := r.frame.PaneXStart - 1; col < width; col++ {
+		if runes := cells[row*width+col].Runes; len(runes) > 0 {
+			line.WriteRune(runes[0])
+		}
+	}
+
+	if !strings.Contains(line.String(), "x2 Heartbeat failed: timeout") {
+		t.Fatalf("overlay row = %q, want deduplicated heartbeat warning", line.String())
+	}
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyEscape, 0, 0))
+
+	if r.errorOverlay {
+		t.Fatal("errorOverlay = true after Escape, want false")
+	}
+}
+
+func TestHandleKey_F3TogglesBundleOverlay(t *testing.T) {
+	r := newTestRuntime(t)
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF2, 0, 0))
+	r.handleKey(tcell.NewEventKey(tcell.KeyF3, 0, 0))
+
+	if !r.bundleOverlay || r.errorOverlay {
+		t.Fatalf("after F3: bundleOverlay = %v, errorOverlay = %v, want only the bundle overlay", r.bundleOverlay, r.errorOverlay)
+	}
+
+	sim, ok := r.screen.(tcell.SimulationScreen)
+	if !ok {
+		t.Fatal("screen is not a SimulationScreen")
+	}
+
+	cells, width, _ := sim.GetContents()
+	row := layout.TopBarHeight
+
+	var line strings.Builder
+
+	for col := r.frame.PaneXStart - 1; col < width; col++ {
+		if runes := cells[row*width+col].Runes; len(runes) > 0 {
+			line.WriteRune(runes[0])
+		}
+	}
+
+	if !strings.Contains(line.String(), "Bundle details") {
+		t.Fatalf("overlay header = %q, want bundle details", line.String())
+	}
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyEscape, 0, 0))
+
+	if r.bundleOverlay {
+		t.Fatal("bundleOverlay = true after Escape, want false")
+	}
+}
+
 func TestHandleResize_InvalidatesHistoryOnWidthChange(t *testing.T) {
 	r := newTestRuntime(t)
 	exec := &testInputExecutor{}
@@ -306,3 +424,47 @@ func TestRenderViewport_HidesCursorWhenLiveCursorIsOffscreen(t *testing.T) {
 		t.Fatal("top cell looks software-cursor-highlighted, want no software cursor when live cursor is offscreen")
 	}
 }
+
+func TestHandleKey_F4TogglesDebugLogging(t *testing.T) {
+	r := newTestRuntime(t)
+
+	if _, err := observability.SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _, _ = observability.SetLevel("info") })
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF4, 0, 0))
+
+	if !r.debugLogging || observability.CurrentLevel() != slog.LevelDebug {
+		t.Fatalf("after F4: debugLogging = %v, level = %v, want debug", r.debugLogging, observability.CurrentLevel())
+	}
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyF4, 0, 0))
+
+	if r.debugLogging || observability.CurrentLevel() != slog.LevelWarn {
+		t.Fatalf("after second F4: debugLogging = %v, level = %v, want warn restored", r.debugLogging, observability.CurrentLevel())
+	}
+}
+
+func TestDraw_SetsTerminalTitle(t *testing.T) {
+	r := newTestRuntime(t)
+
+	sim, ok := r.screen.(tcell.SimulationScreen)
+	if !ok {
+		t.Fatal("screen is not a SimulationScreen")
+	}
+
+	r.draw()
+
+	if got := sim.GetTitle(); got != "mush: ready" {
+		t.Fatalf("title = %q, want the worker status", got)
+	}
+
+	r.eng.BeginLocalTask()
+	r.draw()
+
+	if got := sim.GetTitle(); got != "mush: local task" {
+		t.Fatalf("title = %q, want it to follow the status", got)
+	}
+}
@@ -0,0 +1,162 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+const (
+	// localTaskStream is the transcript stream for local task markers.
+	localTaskStream = "local"
+	// localTaskQuietTimeout ends a local task once the session has seen no
+	// keystrokes or output for this long, so a half-typed prompt or a slash
+	// command that never fires the Stop hook doesn't hold claims forever.
+	localTaskQuietTimeout = 5 * time.Minute
+)
+
+// localTask is a prompt the user typed into the session between jobs.
+type localTask struct {
+	runner  harnesstype.LocalTaskRunner
+	started time.Time
+}
+
+// noteLocalInput starts a local task when the user types into an idle
+// worker session. Claims stay held until the harness finishes the prompt.
+func (r *embeddedRuntime) noteLocalInput() {
+	if r.bundleLoadMode {
+		return
+	}
+
+	r.localMu.Lock()
+	defer r.localMu.Unlock()
+
+	now := r.clock()
+	r.localActivity = now
+
+	if r.localTask != nil {
+		return
+	}
+
+	runner := r.localTaskRunner()
+	if runner == nil || !r.eng.BeginLocalTask() {
+		return
+	}
+
+	runner.BeginLocalTask()
+
+	r.localTask = &localTask{runner: runner, started: now}
+	go r.runLocalTask(r.localTask)
+}
+
+// noteLocalActivity keeps a running local task from timing out while the
+// harness is still printing.
+func (r *embeddedRuntime) noteLocalActivity() {
+	r.localMu.Lock()
+	defer r.localMu.Unlock()
+
+	if r.localTask != nil {
+		r.localActivity = r.clock()
+	}
+}
+
+// localTaskRunner returns the executor that receives typed input when it
+// can run local tasks, or nil.
+func (r *embeddedRuntime) localTaskRunner() harnesstype.LocalTaskRunner {
+	for _, harnessType := range r.supportedHarnesses {
+		executor, ok := r.executors[harnessType]
+		if !ok {
+			continue
+		}
+
+		if _, ok := executor.(harnesstype.InputReceiver); !ok {
+			continue
+		}
+
+		runner, _ := executor.(harnesstype.LocalTaskRunner)
+
+		return runner
+	}
+
+	return nil
+}
+
+// runLocalTask waits for task to finish, records it in the transcript, and
+// resets the session before claims resume.
+func (r *embeddedRuntime) runLocalTask(task *localTask) {
+	ctx, cancel := context.WithCancel(r.ctx)
+	defer cancel()
+
+	go r.expireQuietLocalTask(ctx, cancel)
+
+	logger := observability.FromContext(r.ctx).With(slog.String("component", "harness"))
+	logger.Info("local task started", slog.String("event.type", "local_task.start"))
+	r.appendTranscript(localTaskStream, []byte("\r\n[mush] Local task started: claims paused\r\n"))
+
+	_, err := task.runner.WaitLocalTask(ctx)
+	duration := r.clock().Sub(task.started).Round(time.Second)
+
+	if err != nil {
+		logger.Info("local task ended without completing",
+			slog.String("event.type", "local_task.abandon"),
+			slog.String("error", err.Error()),
+		)
+		r.appendTranscript(localTaskStream, []byte(fmt.Sprintf("\r\n[mush] Local task ended without completing after %s: claims resumed\r\n", duration)))
+	} else {
+		logger.Info("local task finished",
+			slog.String("event.type", "local_task.finish"),
+			slog.Int64("duration_ms", duration.Milliseconds()),
+		)
+		r.appendTranscript(localTaskStream, []byte(fmt.Sprintf("\r\n[mush] Local task finished after %s: claims resumed\r\n", duration)))
+	}
+
+	// Jobs start from a clean session, as they do after another job.
+	if executor, ok := task.runner.(harnesstype.Executor); ok && r.ctx.Err() == nil {
+		_ = executor.Reset(r.ctx)
+	}
+
+	r.localMu.Lock()
+	r.localTask = nil
+	r.localMu.Unlock()
+
+	r.eng.EndLocalTask()
+}
+
+// expireQuietLocalTask cancels the running local task once the session has
+// been quiet for localTaskQuietTimeout.
+func (r *embeddedRuntime) expireQuietLocalTask(ctx context.Context, cancel context.CancelFunc) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.localMu.Lock()
+		quiet := r.clock().Sub(r.localActivity)
+		r.localMu.Unlock()
+
+		if quiet >= localTaskQuietTimeout {
+			cancel()
+			return
+		}
+	}
+}
+
+// clock returns the runtime's current time.
+func (r *embeddedRuntime) clock() time.Time {
+	if r.now == nil {
+		return time.Now()
+	}
+
+	return r.now()
+}
@@ -0,0 +1,99 @@
+//go:build unix
+
+package harness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+type testLocalTaskExecutor struct {
+	testInputExecutor
+
+	begun  chan struct{}
+	finish chan struct{}
+	resets chan struct{}
+}
+
+func (e *testLocalTaskExecutor) BeginLocalTask() { close(e.begun) }
+
+func (e *testLocalTaskExecutor) WaitLocalTask(ctx context.Context) (string, error) {
+	select {
+	case <-e.finish:
+		return "done", nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (e *testLocalTaskExecutor) Reset(context.Context) error {
+	e.resets <- struct{}{}
+
+	return nil
+}
+
+func TestHandleKey_TypingBetweenJobsRunsLocalTask(t *testing.T) {
+	r := newTestRuntime(t)
+	exec := &testLocalTaskExecutor{
+		begun:  make(chan struct{}),
+		finish: make(chan struct{}),
+		resets: make(chan struct{}, 1),
+	}
+	r.executors = map[string]harnesstype.Executor{"test": exec}
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyRune, 'h', 0))
+	r.handleKey(tcell.NewEventKey(tcell.KeyRune, 'i', 0))
+	r.handleKey(tcell.NewEventKey(tcell.KeyEnter, 0, 0))
+
+	select {
+	case <-exec.begun:
+	default:
+		t.Fatal("typing into an idle session did not begin a local task")
+	}
+
+	if len(exec.writes) != 3 {
+		t.Fatalf("WriteInput calls = %d, want every key forwarded", len(exec.writes))
+	}
+
+	if got := r.eng.Stats().Status; got != engine.StatusLocalTask {
+		t.Fatalf("Status = %s, want %s", got, engine.StatusLocalTask)
+	}
+
+	close(exec.finish)
+
+	select {
+	case <-exec.resets:
+	case <-time.After(5 * time.Second):
+		t.Fatal("session was not reset after the local task")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for r.eng.Stats().Status == engine.StatusLocalTask {
+		if time.Now().After(deadline) {
+			t.Fatal("claims still held after the local task finished")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHandleKey_BundleSessionHasNoLocalTasks(t *testing.T) {
+	r := newTestRuntime(t)
+	r.bundleLoadMode = true
+	exec := &testLocalTaskExecutor{begun: make(chan struct{})}
+	r.executors = map[string]harnesstype.Executor{"test": exec}
+
+	r.handleKey(tcell.NewEventKey(tcell.KeyRune, 'h', 0))
+
+	select {
+	case <-exec.begun:
+		t.Fatal("bundle session began a local task")
+	default:
+	}
+}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
@@ -8,6 +8,7 @@ import (
 	"github.com/gdamore/tcell/v2"
 	"github.com/hinshun/vt10x"
 
+	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/harness/ui/layout"
 )
 
@@ -216,14 +217,14 @@ func (r *embeddedRuntime) encodeMouseEvent(buttons tcell.ButtonMask, mouseX, mou
 			suffix = "m"
 		}
 
-		return []byte(fmt.Sprintf("\x1b[<%d;%d;%d%s", buttonCode, col, row, suffix))
+		return []byte(fmt.Sprintf(ansi.CSI+"<%d;%d;%d%s", buttonCode, col, row, suffix))
 	}
 
 	if release {
 		buttonCode = 3
 	}
 
-	return []byte{0x1b, '[', 'M', byte(buttonCode + 32), byte(col + 32), byte(row + 32)}
+	return append([]byte(ansi.CSI+"M"), byte(buttonCode+32), byte(col+32), byte(row+32))
 }
 
 func mouseButtonCode(buttons tcell.ButtonMask) (code int, release, ok bool) {
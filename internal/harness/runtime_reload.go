@@ -0,0 +1,74 @@
+//go:build unix || windows
+
+package harness
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/musher-dev/mush/internal/engine"
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/observability"
+)
+
+// reloadBundle re-resolves the session's bundle and refreshes its assets,
+// then restarts harnesses that only read them at startup. Presses while a
+// reload is running are ignored.
+func (r *embeddedRuntime) reloadBundle() {
+	if r.bundleReload == nil || !r.reloading.CompareAndSwap(false, true) {
+		return
+	}
+	defer r.reloading.Store(false)
+
+	logger := observability.FromContext(r.ctx).With(slog.String("component", "harness"))
+
+	r.infof("Reloading bundle %s...", r.bundleName)
+
+	reloaded, err := r.bundleReload(r.ctx)
+	if err != nil {
+		logger.Warn("bundle reload failed",
+			slog.String("event.type", "bundle.reload.error"),
+			slog.String("error", err.Error()),
+		)
+		r.eng.ReportError(engine.SeverityError, fmt.Sprintf("Bundle reload failed: %v", err))
+		r.draw()
+
+		return
+	}
+
+	r.uiMu.Lock()
+	r.bundleVer = reloaded.Version
+	r.bundleSummary = reloaded.Summary
+	r.uiMu.Unlock()
+
+	for _, warning := range reloaded.Warnings {
+		r.eng.ReportError(engine.SeverityWarning, warning)
+	}
+
+	restarted := 0
+
+	for harnessType, executor := range r.executors {
+		reloader, ok := executor.(harnesstype.BundleReloader)
+		if !ok {
+			continue
+		}
+
+		r.eng.SetStatus(engine.StatusStarting)
+
+		if err := reloader.ReloadBundle(r.ctx); err != nil {
+			r.eng.ReportError(engine.SeverityError, fmt.Sprintf("Restart %s after bundle reload: %v", harnessType, err))
+			continue
+		}
+
+		restarted++
+	}
+
+	logger.Info("bundle reloaded",
+		slog.String("event.type", "bundle.reload"),
+		slog.String("bundle.version", reloaded.Version),
+		slog.Int("bundle.asset_count", reloaded.Summary.TotalLayers),
+		slog.Int("harness.restarted", restarted),
+	)
+
+	r.draw()
+}
@@ -1,10 +1,9 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/hinshun/vt10x"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/musher-dev/mush/internal/harness/ui/layout"
 	statusui "github.com/musher-dev/mush/internal/harness/ui/status"
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 func (r *embeddedRuntime) draw() {
@@ -29,6 +29,8 @@ func (r *embeddedRuntime) drawLocked() {
 	r.renderTopBar()
 	r.renderSidebar()
 	r.renderViewport()
+	r.renderErrorOverlay()
+	r.renderBundleOverlay()
 	r.screen.Show()
 }
 
@@ -53,9 +55,21 @@ func (r *embeddedRuntime) renderTopBar() {
 	mode := "LIVE"
 	modeStyle := barStyle.Foreground(tnSuccess)
 
+	right := "F2 Errors | F3 Bundle | F4 Debug | ^C Int | ^Q Quit"
+	if r.bundleReload != nil {
+		right = "F2 Errors | F3 Bundle | F4 Debug | F5 Reload | ^C Int | ^Q Quit"
+	} else if r.inputLock.Load() {
+		right = "F2 Errors | F3 Bundle | F4 Debug | F6 Lock | ^C Int | ^Q Quit"
+	}
+
 	if !r.followTail {
 		mode = fmt.Sprintf("SCROLL @%d", r.viewportTop)
 		modeStyle = barStyle.Foreground(tnAccent)
+		right = "[N]y Copy | Y Copy job | Esc Live"
+
+		if r.copyCount > 0 {
+			mode += fmt.Sprintf(" [%d]", r.copyCount)
+		}
 	}
 
 	spans := []styledSpan{
@@ -67,15 +81,44 @@ func (r *embeddedRuntime) renderTopBar() {
 		{fmt.Sprintf("  OK:%d Fail:%d", snap.Completed, snap.Failed), barStyle},
 	}
 
+	for _, segment := range []string{statusui.BundleSegment(&snap), statusui.MCPSegment(&snap)} {
+		if segment != "" {
+			spans = append(spans, styledSpan{"  " + segment, barStyle})
+		}
+	}
+
+	if usage := statusui.UsageSegment(&snap); usage != "" {
+		usageStyle := barStyle
+		if snap.UsageNearLimit {
+			usageStyle = barStyle.Foreground(tnWarning).Bold(true)
+		}
+
+		spans = append(spans, styledSpan{"  " + usage, usageStyle})
+	}
+
 	if snap.JobID != "" {
 		spans = append(spans, styledSpan{"  Job: " + snap.JobID, barStyle})
 	}
 
+	if r.debugLogging {
+		spans = append(spans, styledSpan{"  DEBUG LOG", barStyle.Foreground(tnWarning).Bold(true)})
+	}
+
+	if r.inputLock.Load() && snap.JobID != "" {
+		if snap.JobID == r.unlockedJob {
+			spans = append(spans, styledSpan{"  UNLOCKED", barStyle.Foreground(tnAccent)})
+		} else {
+			spans = append(spans, styledSpan{"  LOCKED", barStyle.Foreground(tnWarning).Bold(true)})
+		}
+	}
+
 	if r.historyNotice != "" {
 		spans = append(spans, styledSpan{"  " + r.historyNotice, barStyle.Foreground(tnWarning)})
 	}
 
-	right := "^C Int | ^Q Quit"
+	if r.copyNotice != "" && !r.followTail {
+		spans = append(spans, styledSpan{"  " + r.copyNotice, barStyle.Foreground(tnAccent)})
+	}
 
 	leftWidth := 0
 	for _, span := range spans {
@@ -83,40 +126,48 @@ func (r *embeddedRuntime) renderTopBar() {
 	}
 
 	col := 0
-
 	for _, span := range spans {
-		for _, ch := range span.text {
-			if col >= r.width {
-				break
-			}
-
-			r.screen.SetContent(col, 0, ch, nil, span.style)
-			col += runewidth.RuneWidth(ch)
-		}
+		col = r.drawText(col, 0, r.width, span.text, span.style)
 	}
 
-	rightWidth := runewidth.StringWidth(right)
-	rightStart := r.width - rightWidth
-
+	rightStart := r.width - runewidth.StringWidth(right)
 	if rightStart > leftWidth {
-		hintCol := rightStart
+		r.drawText(rightStart, 0, r.width, right, barStyle)
+	}
+
+	// tcell saves the terminal's own title on start and restores it on exit.
+	if title := statusui.Title(&snap); title != r.title {
+		r.title = title
+		r.screen.SetTitle(title)
+	}
+}
 
-		for _, ch := range right {
-			if hintCol >= r.width {
-				break
-			}
+// drawText draws text from column x, stopping before limit, and returns the
+// column after the last cell drawn. A wide character that would straddle
+// limit is left out rather than cut in half.
+func (r *embeddedRuntime) drawText(x, y, limit int, text string, style tcell.Style) int {
+	return drawScreenText(r.screen, x, y, limit, text, style)
+}
 
-			r.screen.SetContent(hintCol, 0, ch, nil, barStyle)
-			hintCol += runewidth.RuneWidth(ch)
+func drawScreenText(screen tcell.Screen, x, y, limit int, text string, style tcell.Style) int {
+	for _, ch := range text {
+		width := runewidth.RuneWidth(ch)
+		if x+width > limit {
+			break
 		}
+
+		screen.SetContent(x, y, ch, nil, style)
+		x += width
 	}
+
+	return x
 }
 
 func statusTCellColor(label string) tcell.Color {
 	switch label {
 	case "Ready", "Connected":
 		return tnSuccess
-	case "Starting...", "Processing":
+	case "Starting...", "Processing", "Paused", "Local task":
 		return tnWarning
 	case "Error":
 		return tnError
@@ -147,18 +198,8 @@ func (r *embeddedRuntime) renderSidebar() {
 			line = lines[row]
 		}
 
-		line = runewidth.Truncate(line, r.frame.SidebarWidth-1, "")
-		line += strings.Repeat(" ", max(0, r.frame.SidebarWidth-runewidth.StringWidth(line)))
-
-		col := 0
-		for _, ch := range line {
-			if col >= r.frame.SidebarWidth {
-				break
-			}
-
-			r.screen.SetContent(col, screenY, ch, nil, sideStyle)
-			col += runewidth.RuneWidth(ch)
-		}
+		line = render.PadRightVisible(render.TruncateVisible(line, r.frame.SidebarWidth-1), r.frame.SidebarWidth)
+		r.drawText(0, screenY, r.frame.SidebarWidth, line, sideStyle)
 
 		r.screen.SetContent(r.frame.SidebarWidth, screenY, '│', nil, borderStyle)
 	}
@@ -239,6 +280,75 @@ func (r *embeddedRuntime) renderViewport() {
 	r.screen.HideCursor()
 }
 
+// renderErrorOverlay draws the error history over the viewport when open.
+func (r *embeddedRuntime) renderErrorOverlay() {
+	if !r.errorOverlay {
+		return
+	}
+
+	snap := r.statusSnapshot()
+	lines := statusui.ErrorHistoryLines(&snap, max(r.frame.ViewportWidth-2, 0), layout.PtyRowsForFrame(&r.frame))
+
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = line.Text
+	}
+
+	r.renderOverlay(texts, func(base tcell.Style, row int) tcell.Style {
+		switch lines[row].Severity {
+		case "error":
+			return base.Foreground(tnError)
+		case "warning":
+			return base.Foreground(tnWarning)
+		default:
+			return base
+		}
+	})
+}
+
+// renderBundleOverlay draws the bundle and MCP details over the viewport
+// when open.
+func (r *embeddedRuntime) renderBundleOverlay() {
+	if !r.bundleOverlay {
+		return
+	}
+
+	snap := r.statusSnapshot()
+	lines := statusui.BundleDetailLines(&snap, max(r.frame.ViewportWidth-2, 0), layout.PtyRowsForFrame(&r.frame))
+
+	r.renderOverlay(lines, func(base tcell.Style, _ int) tcell.Style { return base })
+}
+
+// renderOverlay fills the viewport with lines, styling the first as a
+// header and the rest with rowStyle.
+func (r *embeddedRuntime) renderOverlay(lines []string, rowStyle func(base tcell.Style, row int) tcell.Style) {
+	rows := layout.PtyRowsForFrame(&r.frame)
+	paneX := r.frame.PaneXStart - 1
+	paneY := r.frame.ContentTop - 1
+	width := r.frame.ViewportWidth
+
+	baseStyle := tcell.StyleDefault.Background(tnSurface).Foreground(tnText)
+
+	for row := 0; row < rows; row++ {
+		for col := 0; col < width; col++ {
+			r.screen.SetContent(paneX+col, paneY+row, ' ', nil, baseStyle)
+		}
+
+		if row >= len(lines) {
+			continue
+		}
+
+		style := baseStyle.Foreground(tnAccent).Bold(true)
+		if row > 0 {
+			style = rowStyle(baseStyle, row)
+		}
+
+		r.drawText(paneX+1, paneY+row, paneX+width, lines[row], style)
+	}
+
+	r.screen.HideCursor()
+}
+
 func (r *embeddedRuntime) renderGlyphRow(screenX, screenY int, cells []vt10x.Glyph) {
 	for col := 0; col < r.frame.ViewportWidth; col++ {
 		glyph := vt10x.Glyph{}
@@ -1,4 +1,4 @@
-//go:build unix
+//go:build unix || windows
 
 package harness
 
@@ -17,10 +17,11 @@ func (r *embeddedRuntime) handleResize(width, height int) {
 	oldViewportWidth := r.frame.ViewportWidth
 
 	r.width, r.height = width, height
-	r.frame = layout.ComputeFrame(width, height, true)
+	r.frame = layout.ComputeFrame(width, height, !r.noSidebar)
 	r.vt.Resize(r.frame.ViewportWidth, layout.PtyRowsForFrame(&r.frame))
 
 	rows := layout.PtyRowsForFrame(&r.frame)
+	r.output.Resize(r.frame.ViewportWidth, rows)
 	for _, executor := range r.executors {
 		if rs, ok := executor.(harnesstype.Resizable); ok {
 			rs.Resize(rows, r.frame.ViewportWidth)
@@ -36,6 +37,22 @@ func (r *embeddedRuntime) handleResize(width, height int) {
 	r.drawLocked()
 }
 
+// reconcileTerminalSize re-reads the terminal size and repaints every cell.
+// A resize or another program's output while suspended can leave both the
+// layout and the physical screen out of date.
+func (r *embeddedRuntime) reconcileTerminalSize() {
+	if r.screen == nil {
+		return
+	}
+
+	width, height := r.screen.Size()
+	r.handleResize(width, height)
+
+	r.uiMu.Lock()
+	r.screen.Sync()
+	r.uiMu.Unlock()
+}
+
 func (r *embeddedRuntime) Write(p []byte) (int, error) {
 	if len(p) == 0 {
 		return 0, nil
@@ -169,6 +186,8 @@ func (r *embeddedRuntime) scrollToBottom() {
 func (r *embeddedRuntime) endScrollLocked() {
 	r.followTail = true
 	r.viewportTop = r.maxViewportTop()
+	r.copyCount = 0
+	r.copyNotice = ""
 }
 
 func (r *embeddedRuntime) invalidateHistoryLocked(notice string) {
@@ -207,8 +226,7 @@ func (r *embeddedRuntime) clampViewportLocked() {
 	}
 
 	if r.viewportTop >= maxTop {
-		r.viewportTop = maxTop
-		r.followTail = true
+		r.endScrollLocked()
 	}
 }
 
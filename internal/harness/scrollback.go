@@ -13,6 +13,7 @@ type scrollbackBuffer struct {
 	capacity int
 	head     int // next write position
 	count    int
+	pushed   int // lines pushed since creation, including dropped ones
 }
 
 const defaultScrollbackCapacity = 1000
@@ -37,6 +38,8 @@ func (b *scrollbackBuffer) Push(cells []vt10x.Glyph) {
 	b.lines[b.head] = scrollbackLine{cells: cp}
 	b.head = (b.head + 1) % b.capacity
 
+	b.pushed++
+
 	if b.count < b.capacity {
 		b.count++
 	}
@@ -60,6 +63,13 @@ func (b *scrollbackBuffer) Len() int {
 	return b.count
 }
 
+// Offset returns the absolute line number of Line(0): how many lines have
+// been evicted or cleared since the buffer was created. Absolute numbers
+// stay valid as the ring wraps.
+func (b *scrollbackBuffer) Offset() int {
+	return b.pushed - b.count
+}
+
 // Clear drops all retained lines without reallocating the buffer.
 func (b *scrollbackBuffer) Clear() {
 	b.head = 0
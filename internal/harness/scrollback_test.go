@@ -196,3 +196,21 @@ func TestScrollbackBuffer_Clear(t *testing.T) {
 		t.Fatalf("expected nil after Clear(), got %v", line)
 	}
 }
+
+func TestScrollbackBuffer_OffsetCountsEvictedLines(t *testing.T) {
+	buf := newScrollbackBuffer(3)
+
+	for i := 0; i < 5; i++ {
+		buf.Push(makeGlyphs("line"))
+	}
+
+	if got := buf.Offset(); got != 2 {
+		t.Fatalf("Offset() = %d, want 2 after two evictions", got)
+	}
+
+	buf.Clear()
+
+	if got := buf.Offset(); got != 5 {
+		t.Fatalf("Offset() = %d, want 5 after Clear()", got)
+	}
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package harness
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyRuntimeSignals relays to ch the signals a running worker handles
+// besides shutdown: SIGHUP, which reloads the config, and SIGCONT, which
+// resyncs after the process was stopped.
+func notifyRuntimeSignals(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGHUP, syscall.SIGCONT)
+}
+
+// isResumeSignal reports whether sig is SIGCONT rather than SIGHUP.
+func isResumeSignal(sig os.Signal) bool {
+	return sig == syscall.SIGCONT
+}
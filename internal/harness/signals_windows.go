@@ -0,0 +1,14 @@
+//go:build windows
+
+package harness
+
+import "os"
+
+// notifyRuntimeSignals relays nothing: Windows has no SIGHUP to reload the
+// config with, nor SIGCONT, as a console process cannot be stopped and
+// continued.
+func notifyRuntimeSignals(chan<- os.Signal) {}
+
+func isResumeSignal(os.Signal) bool {
+	return false
+}
@@ -10,6 +10,17 @@ type MCPServerStatus struct {
 	Expired       bool
 }
 
+// ErrorEntry is one deduplicated entry in the runtime's error history.
+type ErrorEntry struct {
+	Message  string
+	Severity string // "warning" or "error"
+	Count    int
+	LastSeen time.Time
+
+	// RequestID is the API request ID of the latest occurrence, if any.
+	RequestID string
+}
+
 // Snapshot is an immutable status view consumed by UI renderers.
 type Snapshot struct {
 	Width  int
@@ -33,16 +44,59 @@ type Snapshot struct {
 	QueueID            string
 	SupportedHarnesses []string
 
+	// FallbackEndpoint is the API host in use after failing over from the
+	// configured API URL, or "" while that URL is in use.
+	FallbackEndpoint string
+
 	StatusLabel string
 
 	JobID string
 
+	// JobStartedAt is when the running job started, or zero.
+	JobStartedAt time.Time
+
+	// Usage of the running job. UsageKnown is false until the executor
+	// reports it; zero limits mean the job has none.
+	UsageKnown        bool
+	UsageTurns        int
+	UsageTokens       int64
+	UsageCostUSD      float64
+	UsageCostKnown    bool
+	UsageMaxTurns     int
+	UsageMaxBudgetUSD float64
+	UsageNearLimit    bool
+
+	// Harness process supervision, summed across executors. SupervisionKnown
+	// is false when no executor supervises a long-running process.
+	SupervisionKnown bool
+	PTYRestarts      int
+	BypassAccepts    int
+	ReadyTimeouts    int
+	ReadyAverage     time.Duration
+
+	// OutputDroppedBytes counts terminal output skipped because the
+	// terminal could not keep up. Transcripts and capture still have it.
+	OutputDroppedBytes int64
+
 	LastHeartbeat time.Time
 	Completed     int
 	Failed        int
 
-	LastError     string
-	LastErrorTime time.Time
+	// PollInterval is the engine's claim poll interval; PollBackedOff means
+	// it was lengthened because the queue has been empty.
+	PollInterval  time.Duration
+	PollBackedOff bool
+
+	LastError         string
+	LastErrorTime     time.Time
+	LastErrorSeverity string
+	LastErrorCount    int
+
+	// LastErrorRequestID is the API request ID behind LastError, if any.
+	LastErrorRequestID string
+
+	// Errors is the error history, newest first.
+	Errors []ErrorEntry
 
 	MCPServers []MCPServerStatus
 
@@ -1,6 +1,6 @@
 package layout
 
-import "github.com/musher-dev/mush/internal/tui/ansi"
+import "github.com/musher-dev/mush/internal/ansi"
 
 const (
 	// TopBarHeight is the number of lines reserved for the top status bar.
@@ -1,10 +1,12 @@
 package layout
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
-	"github.com/musher-dev/mush/internal/tui/ansi"
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/testutil"
 )
 
 func TestClampTerminalSize(t *testing.T) {
@@ -72,3 +74,40 @@ func TestSetupSequenceIncludesPaneMove(t *testing.T) {
 func contains(s, sub string) bool {
 	return strings.Contains(s, sub)
 }
+
+// goldenSizes is the terminal size matrix snapshotted by the golden tests:
+// below the minimum, too narrow for a sidebar, the smallest sidebar, and
+// common and very large terminals.
+var goldenSizes = []struct{ width, height int }{
+	{10, 1},
+	{64, 20},
+	{65, 20},
+	{80, 24},
+	{120, 40},
+	{240, 70},
+}
+
+func TestSequencesGolden(t *testing.T) {
+	var b strings.Builder
+
+	for _, size := range goldenSizes {
+		for _, allowSidebar := range []bool{false, true} {
+			frame := ComputeFrame(size.width, size.height, allowSidebar)
+
+			fmt.Fprintf(&b, "== %dx%d allowSidebar=%t ==\n", size.width, size.height, allowSidebar)
+			fmt.Fprintf(&b, "frame: %+v\n", frame)
+			fmt.Fprintf(&b, "ptyRows: %d\n", PtyRowsForFrame(&frame))
+
+			for _, useLRMargins := range []bool{false, true} {
+				fmt.Fprintf(&b, "-- setup lr=%t --\n%s", useLRMargins, testutil.DescribeTerminalOutput(SetupSequence(&frame, useLRMargins)))
+				fmt.Fprintf(&b, "-- resize lr=%t --\n%s", useLRMargins, testutil.DescribeTerminalOutput(ResizeSequence(&frame, useLRMargins)))
+				fmt.Fprintf(&b, "-- resize lr=%t keepCursor --\n%s", useLRMargins,
+					testutil.DescribeTerminalOutput(ResizeSequenceWithCursor(&frame, useLRMargins, false)))
+			}
+
+			b.WriteString("\n")
+		}
+	}
+
+	testutil.AssertGolden(t, b.String(), "sequences.golden")
+}
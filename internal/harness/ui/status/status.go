@@ -3,13 +3,12 @@ package status
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/mattn/go-runewidth"
-
+	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/harness/state"
-	"github.com/musher-dev/mush/internal/tui/ansi"
 	"github.com/musher-dev/mush/internal/tui/render"
 )
 
@@ -39,25 +38,25 @@ func Render(s *state.Snapshot) string {
 
 const (
 	// Status bar palette.
-	barBG    = "\x1b[48;5;236m"              // bar background
-	barFG    = "\x1b[38;5;252m"              // bar foreground
-	barReset = "\x1b[22;39m" + barBG + barFG // clear bold, reset FG, re-apply BG+FG
+	barBG    = ansi.CSI + "48;5;236m"              // bar background
+	barFG    = ansi.CSI + "38;5;252m"              // bar foreground
+	barReset = ansi.CSI + "22;39m" + barBG + barFG // clear bold, reset FG, re-apply BG+FG
 
 	// Accent colors (applied over bar background).
-	bold     = "\x1b[1m"
-	dimGray  = "\x1b[90m"
-	green    = "\x1b[38;5;149m" // matches colorSuccess (#9ECE6A)
-	yellow   = "\x1b[38;5;179m" // matches colorWarning (#E0AF68)
-	red      = "\x1b[38;5;210m" // matches colorError (#F7768E)
-	accentFG = "\x1b[38;5;140m" // matches colorAccent (#9D7CD8)
+	bold     = ansi.CSI + "1m"
+	dimGray  = ansi.CSI + "90m"
+	green    = ansi.CSI + "38;5;149m" // matches colorSuccess (#9ECE6A)
+	yellow   = ansi.CSI + "38;5;179m" // matches colorWarning (#E0AF68)
+	red      = ansi.CSI + "38;5;210m" // matches colorError (#F7768E)
+	accentFG = ansi.CSI + "38;5;140m" // matches colorAccent (#9D7CD8)
 
 	// Sidebar palette.
-	sidebarBG     = "\x1b[48;5;238m"
-	sidebarFG     = "\x1b[38;5;252m"
-	sidebarBorder = "\x1b[48;5;236m\x1b[38;5;240m" // muted border
+	sidebarBG     = ansi.CSI + "48;5;238m"
+	sidebarFG     = ansi.CSI + "38;5;252m"
+	sidebarBorder = ansi.CSI + "48;5;236m" + ansi.CSI + "38;5;240m" // muted border
 
 	// Reset.
-	resetAll = "\x1b[0m"
+	resetAll = ansi.Reset
 )
 
 func topBarLine(s *state.Snapshot) string {
@@ -67,12 +66,27 @@ func topBarLine(s *state.Snapshot) string {
 		accentFG + bold + "MUSH" + barReset,
 		fmt.Sprintf("Status: %s", styleStatus(s.StatusLabel)),
 		"Mode: " + green + "LIVE" + barReset,
-		dimGray + "^C Int  ^Q Quit" + barReset, // keyboard hints
 	}
 
+	for _, segment := range []string{BundleSegment(s), MCPSegment(s)} {
+		if segment != "" {
+			parts = append(parts, segment)
+		}
+	}
+
+	if usage := UsageSegment(s); usage != "" {
+		if s.UsageNearLimit {
+			usage = yellow + bold + usage + barReset
+		}
+
+		parts = append(parts, usage)
+	}
+
+	parts = append(parts, dimGray+"F2 Errors  F3 Bundle  F4 Debug  ^C Int  ^Q Quit"+barReset) // keyboard hints
+
 	line := strings.Join(parts, sep)
 	line = barBG + barFG + " " + line
-	line = render.PadRightVisible(line, s.Width-1)
+	line = render.PadRightVisible(render.TruncateVisible(line, s.Width-1), s.Width-1)
 
 	return line + " " + resetAll
 }
@@ -80,9 +94,13 @@ func topBarLine(s *state.Snapshot) string {
 // SidebarClickTarget identifies a clickable row in the sidebar.
 type SidebarClickTarget struct {
 	Row     int    // 0-based index into returned lines
-	Section string // "Agents", "Skills", or "Tools"
+	Section string // "Agents", "Skills", "Tools", or HeartbeatSection
 }
 
+// HeartbeatSection is the ExpandedSections key that switches the heartbeat
+// row from a relative age to an absolute timestamp.
+const HeartbeatSection = "Heartbeat"
+
 // SidebarLines builds plain-text sidebar rows for a snapshot.
 // It dynamically sizes lists based on the available rows and returns
 // click targets for expandable/collapsible list sections.
@@ -95,13 +113,8 @@ func SidebarLines(s *state.Snapshot, rows int) ([]string, []SidebarClickTarget)
 	if s.BundleName == "" {
 		lines = append(lines, "  none loaded")
 	} else {
-		bundle := s.BundleName
-		if s.BundleVer != "" {
-			bundle += " v" + s.BundleVer
-		}
-
 		lines = append(lines,
-			"  "+bundle,
+			"  "+bundleLabel(s),
 			fmt.Sprintf("  layers: %d", s.BundleLayers),
 			fmt.Sprintf("  agents: %d", len(s.BundleAgents)),
 			fmt.Sprintf("  skills: %d", len(s.BundleSkills)),
@@ -138,10 +151,28 @@ func SidebarLines(s *state.Snapshot, rows int) ([]string, []SidebarClickTarget)
 		interactionLines++
 	}
 
-	if s.LastError != "" && s.Now.Sub(s.LastErrorTime) < 30*time.Second {
+	if s.FallbackEndpoint != "" {
 		interactionLines++
 	}
 
+	hbLine := heartbeatLine(s)
+	if hbLine != "" {
+		interactionLines++
+	}
+
+	pLine := pollLine(s)
+	if pLine != "" {
+		interactionLines++
+	}
+
+	errLine := recentErrorLine(s)
+	if errLine != "" {
+		interactionLines++
+	}
+
+	supervision := supervisionLines(s)
+	interactionLines += len(supervision)
+
 	lists := []listInfo{
 		{"Agents", agents},
 		{"Skills", skills},
@@ -184,24 +215,7 @@ func SidebarLines(s *state.Snapshot, rows int) ([]string, []SidebarClickTarget)
 		lines = append(lines, "  none")
 	} else {
 		for _, server := range s.MCPServers {
-			flags := []string{}
-
-			if server.Loaded {
-				flags = append(flags, "loaded")
-			} else {
-				flags = append(flags, "off")
-			}
-
-			switch {
-			case server.Authenticated:
-				flags = append(flags, "auth")
-			case server.Expired:
-				flags = append(flags, "expired")
-			default:
-				flags = append(flags, "no-auth")
-			}
-
-			lines = append(lines, fmt.Sprintf("  %s (%s)", server.Name, strings.Join(flags, ",")))
+			lines = append(lines, mcpServerLine(server))
 		}
 	}
 
@@ -215,13 +229,23 @@ func SidebarLines(s *state.Snapshot, rows int) ([]string, []SidebarClickTarget)
 		lines = append(lines, "  harness: "+strings.Join(s.SupportedHarnesses, ", "))
 	}
 
-	if s.LastError != "" && s.Now.Sub(s.LastErrorTime) < 30*time.Second {
-		msg := s.LastError
-		if runewidth.StringWidth(msg) > 30 {
-			msg = runewidth.Truncate(msg, 30, "...")
-		}
+	if s.FallbackEndpoint != "" {
+		lines = append(lines, "  api: "+s.FallbackEndpoint+" (fallback)")
+	}
+
+	if hbLine != "" {
+		targets = append(targets, SidebarClickTarget{Row: len(lines), Section: HeartbeatSection})
+		lines = append(lines, hbLine)
+	}
 
-		lines = append(lines, "  err: "+msg)
+	if pLine != "" {
+		lines = append(lines, pLine)
+	}
+
+	lines = append(lines, supervision...)
+
+	if errLine != "" {
+		lines = append(lines, errLine)
 	}
 
 	for len(lines) < rows {
@@ -246,6 +270,369 @@ func SidebarLines(s *state.Snapshot, rows int) ([]string, []SidebarClickTarget)
 	return lines, targets
 }
 
+// BundleSegment returns the top bar segment naming the active bundle, or ""
+// when no bundle is loaded.
+func BundleSegment(s *state.Snapshot) string {
+	if s.BundleName == "" {
+		return ""
+	}
+
+	return "Bundle: " + bundleLabel(s)
+}
+
+// MCPSegment returns the top bar segment counting loaded MCP servers, or ""
+// when none are configured.
+func MCPSegment(s *state.Snapshot) string {
+	if len(s.MCPServers) == 0 {
+		return ""
+	}
+
+	loaded := 0
+
+	for _, server := range s.MCPServers {
+		if server.Loaded {
+			loaded++
+		}
+	}
+
+	return fmt.Sprintf("MCP: %d/%d", loaded, len(s.MCPServers))
+}
+
+// UsageSegment returns the top bar segment with the running job's turns,
+// tokens, and cost against its limits, or "" when usage is unknown.
+func UsageSegment(s *state.Snapshot) string {
+	if !s.UsageKnown {
+		return ""
+	}
+
+	turns := strconv.Itoa(s.UsageTurns)
+	if s.UsageMaxTurns > 0 {
+		turns += "/" + strconv.Itoa(s.UsageMaxTurns)
+	}
+
+	parts := []string{"Usage: " + turns + " turns", formatTokenCount(s.UsageTokens) + " tok"}
+
+	if s.UsageCostKnown {
+		cost := fmt.Sprintf("$%.2f", s.UsageCostUSD)
+		if s.UsageMaxBudgetUSD > 0 {
+			cost += fmt.Sprintf("/$%.2f", s.UsageMaxBudgetUSD)
+		}
+
+		parts = append(parts, cost)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Title returns the terminal window title summarizing the worker, such as
+// "mush: processing job 3f2a1b2c… (2m)". Elapsed time is whole minutes, so
+// the title changes at most once a minute while a job runs.
+func Title(s *state.Snapshot) string {
+	if s.JobID != "" {
+		title := "mush: processing job " + render.TruncateVisibleTail(s.JobID, 9, "…")
+
+		if !s.JobStartedAt.IsZero() {
+			elapsed := render.Age(s.JobStartedAt, s.Now)
+			if elapsed < time.Minute {
+				title += " (<1m)"
+			} else {
+				title += " (" + render.FormatDuration(elapsed.Truncate(time.Minute)) + ")"
+			}
+		}
+
+		return title
+	}
+
+	if s.BundleLoadMode && s.BundleName != "" {
+		return "mush: " + s.BundleName
+	}
+
+	return "mush: " + strings.ToLower(s.StatusLabel)
+}
+
+// formatTokenCount abbreviates a token count: 950, 45.2k, 1.3M.
+func formatTokenCount(n int64) string {
+	switch {
+	case n >= 1_000_000:
+		return fmt.Sprintf("%.1fM", float64(n)/1_000_000)
+	case n >= 1_000:
+		return fmt.Sprintf("%.1fk", float64(n)/1_000)
+	default:
+		return strconv.FormatInt(n, 10)
+	}
+}
+
+// BundleDetailLines builds the bundle overlay rows: the bundle identity,
+// every injected asset by kind, and the MCP servers with their state. Each
+// row fits within width, and at most rows lines are returned.
+func BundleDetailLines(s *state.Snapshot, width, rows int) []string {
+	if rows <= 0 {
+		return nil
+	}
+
+	lines := []string{"Bundle details - F3 to close"}
+
+	if s.BundleName == "" {
+		lines = append(lines, "  no bundle loaded")
+	} else {
+		lines = append(lines, fmt.Sprintf("  %s (%d layers)", bundleLabel(s), s.BundleLayers))
+	}
+
+	for _, list := range []listInfo{
+		{"Agents", s.BundleAgents},
+		{"Skills", s.BundleSkills},
+		{"Tools", s.BundleTools},
+		{"Other", s.BundleOther},
+	} {
+		if len(list.items) == 0 {
+			continue
+		}
+
+		items := append([]string(nil), list.items...)
+		sort.Strings(items)
+
+		lines = append(lines, "", fmt.Sprintf("%s (%d)", list.title, len(items)))
+
+		for _, item := range items {
+			lines = append(lines, "  - "+item)
+		}
+	}
+
+	lines = append(lines, "", fmt.Sprintf("MCP servers (%d)", len(s.MCPServers)))
+	if len(s.MCPServers) == 0 {
+		lines = append(lines, "  none")
+	}
+
+	for _, server := range s.MCPServers {
+		lines = append(lines, mcpServerLine(server))
+	}
+
+	if len(lines) > rows {
+		lines = lines[:rows]
+	}
+
+	for i, line := range lines {
+		lines[i] = render.TruncateVisibleTail(line, width, "...")
+	}
+
+	return lines
+}
+
+// bundleLabel returns the bundle name with its version, if known.
+func bundleLabel(s *state.Snapshot) string {
+	if s.BundleVer == "" {
+		return s.BundleName
+	}
+
+	return s.BundleName + " v" + s.BundleVer
+}
+
+// mcpServerLine describes an MCP server and its load and auth state.
+func mcpServerLine(server state.MCPServerStatus) string {
+	flags := []string{}
+
+	if server.Loaded {
+		flags = append(flags, "loaded")
+	} else {
+		flags = append(flags, "off")
+	}
+
+	switch {
+	case server.Authenticated:
+		flags = append(flags, "auth")
+	case server.Expired:
+		flags = append(flags, "expired")
+	default:
+		flags = append(flags, "no-auth")
+	}
+
+	return fmt.Sprintf("  %s (%s)", server.Name, strings.Join(flags, ","))
+}
+
+const (
+	// errorDisplayWindow is how long the latest error stays in the sidebar.
+	errorDisplayWindow = 30 * time.Second
+	// warningDisplayWindow is shorter so a transient blip clears quickly.
+	warningDisplayWindow = 10 * time.Second
+)
+
+// recentErrorLine returns the sidebar row for the latest error, or "" once it
+// has aged out. Warnings and errors are labeled differently.
+func recentErrorLine(s *state.Snapshot) string {
+	if s.LastError == "" {
+		return ""
+	}
+
+	label, window := "err", errorDisplayWindow
+	if s.LastErrorSeverity == "warning" {
+		label, window = "warn", warningDisplayWindow
+	}
+
+	if s.Now.Sub(s.LastErrorTime) >= window {
+		return ""
+	}
+
+	msg := render.TruncateVisibleTail(s.LastError, 30, "...")
+
+	if s.LastErrorCount > 1 {
+		msg += fmt.Sprintf(" (x%d)", s.LastErrorCount)
+	}
+
+	line := "  " + label + ": " + msg
+
+	// A short request ID is enough to find the full one in the F2 overlay.
+	if id := s.LastErrorRequestID; id != "" {
+		line += " [req " + render.TruncateVisible(id, 8) + "]"
+	}
+
+	return line
+}
+
+// heartbeatLine returns the sidebar row for the current job's last heartbeat,
+// or "" when no job is running.
+func heartbeatLine(s *state.Snapshot) string {
+	if s.JobID == "" || s.LastHeartbeat.IsZero() {
+		return ""
+	}
+
+	if s.ExpandedSections[HeartbeatSection] {
+		return "  heartbeat: " + render.FormatTimestamp(s.LastHeartbeat, s.Now)
+	}
+
+	age := render.Age(s.LastHeartbeat, s.Now)
+	if age < time.Second {
+		return "  heartbeat: just now"
+	}
+
+	return "  heartbeat: " + render.FormatDuration(age) + " ago"
+}
+
+// pollLine returns the sidebar row for the claim poll interval while the
+// worker waits for jobs, or "" while a job runs or before the first claim.
+func pollLine(s *state.Snapshot) string {
+	if s.JobID != "" || s.PollInterval <= 0 {
+		return ""
+	}
+
+	line := "  poll: every " + render.FormatDuration(s.PollInterval)
+	if s.PollBackedOff {
+		line += " (idle)"
+	}
+
+	return line
+}
+
+// supervisionLines summarizes harness process restarts and readiness, and
+// terminal output dropped because the terminal fell behind. The timeout,
+// bypass, and drop rows only appear once something happened.
+func supervisionLines(s *state.Snapshot) []string {
+	var lines []string
+
+	if s.SupervisionKnown {
+		lines = append(lines, fmt.Sprintf("  pty: %d restarts, ready %.1fs", s.PTYRestarts, s.ReadyAverage.Seconds()))
+
+		if s.ReadyTimeouts > 0 {
+			lines = append(lines, fmt.Sprintf("  ready timeouts: %d", s.ReadyTimeouts))
+		}
+
+		if s.BypassAccepts > 0 {
+			lines = append(lines, fmt.Sprintf("  bypass accepted: %d", s.BypassAccepts))
+		}
+	}
+
+	if s.OutputDroppedBytes > 0 {
+		lines = append(lines, "  output dropped: "+render.FormatBytes(s.OutputDroppedBytes))
+	}
+
+	return lines
+}
+
+// ErrorHistoryLine is one row of the error history overlay.
+type ErrorHistoryLine struct {
+	Text     string
+	Severity string // empty for the header and placeholder rows
+}
+
+// ErrorHistoryLines builds the error history overlay rows, newest first.
+// Each row fits within width, and at most rows lines are returned.
+func ErrorHistoryLines(s *state.Snapshot, width, rows int) []ErrorHistoryLine {
+	if rows <= 0 {
+		return nil
+	}
+
+	fit := func(text string) string {
+		return render.TruncateVisibleTail(text, width, "...")
+	}
+
+	lines := []ErrorHistoryLine{
+		{Text: fit(fmt.Sprintf("Error history (%d) - F2 to close", len(s.Errors)))},
+	}
+
+	if len(s.Errors) == 0 {
+		lines = append(lines, ErrorHistoryLine{Text: fit("  no errors recorded")})
+	}
+
+	for _, entry := range s.Errors {
+		if len(lines) >= rows {
+			break
+		}
+
+		label := "ERROR"
+		if entry.Severity == "warning" {
+			label = "WARN "
+		}
+
+		// The repeat count goes before the message so truncation keeps it.
+		count := ""
+		if entry.Count > 1 {
+			count = fmt.Sprintf("x%d ", entry.Count)
+		}
+
+		text := fmt.Sprintf("%s  %s  %s%s", entry.LastSeen.Format("15:04:05"), label, count, entry.Message)
+
+		for _, row := range wrapErrorHistoryEntry(text, width) {
+			lines = append(lines, ErrorHistoryLine{Text: row, Severity: entry.Severity})
+		}
+
+		if entry.RequestID != "" && len(lines) < rows {
+			lines = append(lines, ErrorHistoryLine{Text: fit(errorHistoryIndent + "request " + entry.RequestID), Severity: entry.Severity})
+		}
+	}
+
+	if len(lines) > rows {
+		lines = lines[:rows]
+	}
+
+	return lines
+}
+
+// errorHistoryIndent lines up wrapped message rows and request IDs under
+// the entry's severity label.
+const errorHistoryIndent = "          "
+
+// wrapErrorHistoryEntry fits an entry within width, wrapping a long
+// message onto indented rows rather than cutting it off.
+func wrapErrorHistoryEntry(text string, width int) []string {
+	indentWidth := len(errorHistoryIndent)
+	if width <= indentWidth {
+		return []string{render.TruncateVisibleTail(text, width, "...")}
+	}
+
+	first := render.WrapVisible(text, width)[0]
+	rows := []string{first}
+
+	rest := strings.TrimLeft(text[len(first):], " ")
+	if rest == "" {
+		return rows
+	}
+
+	for _, row := range render.WrapVisible(rest, width-indentWidth) {
+		rows = append(rows, errorHistoryIndent+row)
+	}
+
+	return rows
+}
+
 type listInfo struct {
 	title string
 	items []string
@@ -365,7 +752,7 @@ func distributeListSlots(lists []listInfo, expanded map[string]bool, bundleLines
 func sidebarRow(content string, sidebarWidth int) string {
 	maxContent := max(0, sidebarWidth-2)
 
-	content = runewidth.Truncate(content, maxContent, "")
+	content = render.TruncateVisible(content, maxContent)
 
 	body := " " + content
 	body = render.PadRightVisible(body, sidebarWidth)
@@ -383,6 +770,10 @@ func styleStatus(label string) string {
 		return green + bold + "Connected" + barReset
 	case "Processing":
 		return yellow + bold + "Processing" + barReset
+	case "Paused":
+		return yellow + bold + "Paused" + barReset
+	case "Local task":
+		return yellow + bold + "Local task" + barReset
 	case "Error":
 		return red + bold + "Error" + barReset
 	default:
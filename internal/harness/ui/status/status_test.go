@@ -1,13 +1,19 @@
 package status
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
+	"unicode/utf8"
 
 	"github.com/mattn/go-runewidth"
 
+	"github.com/musher-dev/mush/internal/ansi"
 	"github.com/musher-dev/mush/internal/harness/state"
+	"github.com/musher-dev/mush/internal/harness/ui/layout"
+	"github.com/musher-dev/mush/internal/testutil"
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 func TestTopBarLineNoBareResetExceptEnd(t *testing.T) {
@@ -107,7 +113,7 @@ func TestTopBarShowsKeyboardHints(t *testing.T) {
 
 	line := topBarLine(&s)
 
-	for _, hint := range []string{"^C Int", "^Q Quit"} {
+	for _, hint := range []string{"F2 Errors", "^C Int", "^Q Quit"} {
 		if !strings.Contains(line, hint) {
 			t.Fatalf("topBarLine missing hint %q in: %q", hint, line)
 		}
@@ -331,3 +337,639 @@ func TestSidebarLines_ClickTargetRowIndex(t *testing.T) {
 		}
 	}
 }
+
+func TestSidebarLines_ErrorSeverityAndCount(t *testing.T) {
+	now := time.Unix(1000, 0)
+
+	tests := []struct {
+		name     string
+		severity string
+		age      time.Duration
+		want     string
+	}{
+		{name: "recent error", severity: "error", age: 20 * time.Second, want: "  err: Complete failed (x3)"},
+		{name: "recent warning", severity: "warning", age: 5 * time.Second, want: "  warn: Complete failed (x3)"},
+		{name: "stale warning", severity: "warning", age: 15 * time.Second},
+		{name: "stale error", severity: "error", age: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := state.Snapshot{
+				LastError:         "Complete failed",
+				LastErrorTime:     now.Add(-tt.age),
+				LastErrorSeverity: tt.severity,
+				LastErrorCount:    3,
+				Now:               now,
+			}
+
+			lines, _ := SidebarLines(&s, 20)
+			joined := strings.Join(lines, "\n")
+
+			if tt.want == "" {
+				if strings.Contains(joined, "Complete failed") {
+					t.Fatalf("expected aged-out error to be hidden:\n%s", joined)
+				}
+
+				return
+			}
+
+			if !strings.Contains(joined, tt.want) {
+				t.Fatalf("expected %q in sidebar:\n%s", tt.want, joined)
+			}
+		})
+	}
+}
+
+func TestSidebarLines_Supervision(t *testing.T) {
+	s := state.Snapshot{
+		SupervisionKnown: true,
+		PTYRestarts:      2,
+		ReadyTimeouts:    1,
+		ReadyAverage:     3200 * time.Millisecond,
+	}
+
+	lines, _ := SidebarLines(&s, 20)
+	joined := strings.Join(lines, "\n")
+
+	for _, want := range []string{"  pty: 2 restarts, ready 3.2s", "  ready timeouts: 1"} {
+		if !strings.Contains(joined, want) {
+			t.Fatalf("expected %q in sidebar:\n%s", want, joined)
+		}
+	}
+
+	if strings.Contains(joined, "bypass accepted") {
+		t.Fatalf("expected no bypass row without accepts:\n%s", joined)
+	}
+
+	s.SupervisionKnown = false
+	lines, _ = SidebarLines(&s, 20)
+
+	if joined := strings.Join(lines, "\n"); strings.Contains(joined, "pty:") {
+		t.Fatalf("expected no supervision rows when unknown:\n%s", joined)
+	}
+
+	if strings.Contains(joined, "output dropped") {
+		t.Fatalf("expected no drop row before output is dropped:\n%s", joined)
+	}
+
+	s.OutputDroppedBytes = 3 << 20
+	lines, _ = SidebarLines(&s, 20)
+
+	if joined := strings.Join(lines, "\n"); !strings.Contains(joined, "  output dropped: 3.0 MB") {
+		t.Fatalf("expected dropped output in sidebar:\n%s", joined)
+	}
+}
+
+func TestSidebarLines_ErrorRequestID(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := state.Snapshot{
+		LastError:          "Claim failed",
+		LastErrorTime:      now,
+		LastErrorSeverity:  "warning",
+		LastErrorCount:     1,
+		LastErrorRequestID: "0f8e1c2a-9b7d-4c1e",
+		Now:                now,
+	}
+
+	lines, _ := SidebarLines(&s, 20)
+
+	if joined := strings.Join(lines, "\n"); !strings.Contains(joined, "  warn: Claim failed [req 0f8e1c2a]") {
+		t.Fatalf("expected short request ID on the error row:\n%s", joined)
+	}
+}
+
+func TestErrorHistoryLines(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	s := state.Snapshot{
+		Errors: []state.ErrorEntry{
+			{Message: "Heartbeat failed: timeout", Severity: "warning", Count: 4, LastSeen: seen},
+			{Message: "Complete failed: 500", Severity: "error", Count: 1, LastSeen: seen},
+		},
+	}
+
+	lines := ErrorHistoryLines(&s, 80, 10)
+	if len(lines) != 3 {
+		t.Fatalf("len(lines) = %d, want 3", len(lines))
+	}
+
+	if lines[0].Text != "Error history (2) - F2 to close" || lines[0].Severity != "" {
+		t.Fatalf("header = %+v", lines[0])
+	}
+
+	if got, want := lines[1].Text, "15:04:05  WARN   x4 Heartbeat failed: timeout"; got != want {
+		t.Fatalf("lines[1] = %q, want %q", got, want)
+	}
+
+	if got, want := lines[2].Text, "15:04:05  ERROR  Complete failed: 500"; got != want {
+		t.Fatalf("lines[2] = %q, want %q", got, want)
+	}
+
+	if lines[2].Severity != "error" {
+		t.Fatalf("lines[2].Severity = %q, want error", lines[2].Severity)
+	}
+
+	if got := ErrorHistoryLines(&s, 80, 2); len(got) != 2 {
+		t.Fatalf("len(lines) with rows=2 = %d, want 2", len(got))
+	}
+
+	for _, line := range ErrorHistoryLines(&s, 20, 10) {
+		if w := runewidth.StringWidth(line.Text); w > 20 {
+			t.Fatalf("line %q width %d exceeds 20", line.Text, w)
+		}
+	}
+}
+
+func TestErrorHistoryLines_WrapsLongMessages(t *testing.T) {
+	seen := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+	s := state.Snapshot{
+		Errors: []state.ErrorEntry{
+			{Message: "任务 修复登录页面 失败: 构建超时 after 30 minutes", Severity: "error", Count: 1, LastSeen: seen, RequestID: "req-1"},
+		},
+	}
+
+	lines := ErrorHistoryLines(&s, 32, 10)
+
+	want := []string{
+		"Error history (1) - F2 to close",
+		"15:04:05  ERROR  任务",
+		"          修复登录页面 失败:",
+		"          构建超时 after 30",
+		"          minutes",
+		"          request req-1",
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("lines = %+v, want %q", lines, want)
+	}
+
+	for i, line := range lines {
+		if line.Text != want[i] {
+			t.Fatalf("lines[%d] = %q, want %q", i, line.Text, want[i])
+		}
+
+		if w := runewidth.StringWidth(line.Text); w > 32 {
+			t.Fatalf("line %q width %d exceeds 32", line.Text, w)
+		}
+
+		if i > 0 && line.Severity != "error" {
+			t.Fatalf("lines[%d].Severity = %q, want error", i, line.Severity)
+		}
+	}
+}
+
+func TestSidebarLines_WideErrorTextFitsWidth(t *testing.T) {
+	now := time.Unix(1000, 0)
+	s := state.Snapshot{
+		LastError:         "部署失败 🚀 deploy 任务名称很长很长很长",
+		LastErrorTime:     now,
+		LastErrorSeverity: "error",
+		LastErrorCount:    1,
+		Now:               now,
+	}
+
+	lines, _ := SidebarLines(&s, 20)
+
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "  err: ") {
+			continue
+		}
+
+		if !utf8.ValidString(line) {
+			t.Fatalf("error line %q is not valid UTF-8", line)
+		}
+
+		if w := runewidth.StringWidth(strings.TrimPrefix(line, "  err: ")); w > 30 {
+			t.Fatalf("error message %q width %d exceeds 30", line, w)
+		}
+
+		return
+	}
+
+	t.Fatalf("no error line in sidebar:\n%s", strings.Join(lines, "\n"))
+}
+
+func TestErrorHistoryLines_RequestID(t *testing.T) {
+	s := state.Snapshot{
+		Errors: []state.ErrorEntry{
+			{Message: "Claim failed: status 503", Severity: "warning", Count: 1, RequestID: "0f8e1c2a-req"},
+		},
+	}
+
+	lines := ErrorHistoryLines(&s, 80, 10)
+	if len(lines) != 3 || lines[2].Text != "          request 0f8e1c2a-req" {
+		t.Fatalf("lines = %+v, want the request ID under the entry", lines)
+	}
+}
+
+func TestErrorHistoryLines_Empty(t *testing.T) {
+	lines := ErrorHistoryLines(&state.Snapshot{}, 80, 10)
+
+	if len(lines) != 2 || !strings.Contains(lines[1].Text, "no errors recorded") {
+		t.Fatalf("lines = %+v, want header and placeholder", lines)
+	}
+}
+
+func TestTopBarSegments(t *testing.T) {
+	s := state.Snapshot{
+		BundleName: "acme/kit",
+		BundleVer:  "2.0.0",
+		MCPServers: []state.MCPServerStatus{
+			{Name: "linear", Loaded: true},
+			{Name: "github"},
+		},
+	}
+
+	if got := BundleSegment(&s); got != "Bundle: acme/kit v2.0.0" {
+		t.Errorf("BundleSegment() = %q", got)
+	}
+
+	if got := MCPSegment(&s); got != "MCP: 1/2" {
+		t.Errorf("MCPSegment() = %q", got)
+	}
+
+	if BundleSegment(&state.Snapshot{}) != "" || MCPSegment(&state.Snapshot{}) != "" {
+		t.Error("segments should be empty without a bundle or MCP servers")
+	}
+}
+
+func TestUsageSegment(t *testing.T) {
+	tests := []struct {
+		name string
+		snap state.Snapshot
+		want string
+	}{
+		{name: "unknown", snap: state.Snapshot{}, want: ""},
+		{
+			name: "no limits or cost",
+			snap: state.Snapshot{UsageKnown: true, UsageTurns: 3, UsageTokens: 950},
+			want: "Usage: 3 turns 950 tok",
+		},
+		{
+			name: "limits and cost",
+			snap: state.Snapshot{
+				UsageKnown: true, UsageTurns: 12, UsageMaxTurns: 40, UsageTokens: 45_230,
+				UsageCostKnown: true, UsageCostUSD: 0.42, UsageMaxBudgetUSD: 5,
+			},
+			want: "Usage: 12/40 turns 45.2k tok $0.42/$5.00",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := UsageSegment(&tt.snap); got != tt.want {
+			t.Errorf("%s: UsageSegment() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTitle(t *testing.T) {
+	now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		snap state.Snapshot
+		want string
+	}{
+		{name: "idle", snap: state.Snapshot{StatusLabel: "Ready"}, want: "mush: ready"},
+		{name: "local task", snap: state.Snapshot{StatusLabel: "Local task"}, want: "mush: local task"},
+		{
+			name: "bundle session",
+			snap: state.Snapshot{StatusLabel: "Ready", BundleLoadMode: true, BundleName: "acme/kit"},
+			want: "mush: acme/kit",
+		},
+		{
+			name: "job just claimed",
+			snap: state.Snapshot{JobID: "3f2a1b2c-9d8e-4f00-a1b2-c3d4e5f60718", Now: now},
+			want: "mush: processing job 3f2a1b2c…",
+		},
+		{
+			name: "job under a minute",
+			snap: state.Snapshot{JobID: "job-1", JobStartedAt: now.Add(-40 * time.Second), Now: now},
+			want: "mush: processing job job-1 (<1m)",
+		},
+		{
+			name: "job in whole minutes",
+			snap: state.Snapshot{JobID: "3f2a1b2c-9d8e", JobStartedAt: now.Add(-2*time.Minute - 50*time.Second), Now: now},
+			want: "mush: processing job 3f2a1b2c… (2m)",
+		},
+		{
+			name: "long job",
+			snap: state.Snapshot{JobID: "job-1", JobStartedAt: now.Add(-75 * time.Minute), Now: now},
+			want: "mush: processing job job-1 (1h 15m)",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := Title(&tt.snap); got != tt.want {
+			t.Errorf("%s: Title() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBundleDetailLines(t *testing.T) {
+	s := state.Snapshot{
+		BundleName:   "acme/kit",
+		BundleVer:    "2.0.0",
+		BundleLayers: 3,
+		BundleAgents: []string{"reviewer.md", "planner.md"},
+		BundleTools:  []string{".mcp.json"},
+		MCPServers:   []state.MCPServerStatus{{Name: "linear", Loaded: true, Authenticated: true}},
+	}
+
+	got := strings.Join(BundleDetailLines(&s, 80, 20), "\n")
+	want := strings.Join([]string{
+		"Bundle details - F3 to close",
+		"  acme/kit v2.0.0 (3 layers)",
+		"",
+		"Agents (2)",
+		"  - planner.md",
+		"  - reviewer.md",
+		"",
+		"Tools (1)",
+		"  - .mcp.json",
+		"",
+		"MCP servers (1)",
+		"  linear (loaded,auth)",
+	}, "\n")
+
+	if got != want {
+		t.Fatalf("BundleDetailLines() =\n%s\nwant\n%s", got, want)
+	}
+
+	if lines := BundleDetailLines(&s, 80, 3); len(lines) != 3 {
+		t.Fatalf("len(lines) with rows=3 = %d, want 3", len(lines))
+	}
+
+	for _, line := range BundleDetailLines(&s, 12, 20) {
+		if w := runewidth.StringWidth(line); w > 12 {
+			t.Fatalf("line %q width %d exceeds 12", line, w)
+		}
+	}
+}
+
+func TestSidebarLines_HeartbeatAge(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		jobID    string
+		age      time.Duration
+		expanded bool
+		want     string
+	}{
+		{name: "idle worker", age: time.Minute},
+		{name: "seconds", jobID: "job-1", age: 12 * time.Second, want: "  heartbeat: 12s ago"},
+		{name: "hours", jobID: "job-1", age: 2*time.Hour + 5*time.Minute, want: "  heartbeat: 2h 5m ago"},
+		{name: "days", jobID: "job-1", age: 50 * time.Hour, want: "  heartbeat: 2d 2h ago"},
+		{name: "clock skew", jobID: "job-1", age: -time.Hour, want: "  heartbeat: just now"},
+		{
+			name: "absolute", jobID: "job-1", age: time.Second, expanded: true,
+			want: "  heartbeat: " + render.FormatTimestamp(now.Add(-time.Second), now),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := state.Snapshot{
+				JobID:            tt.jobID,
+				LastHeartbeat:    now.Add(-tt.age),
+				ExpandedSections: map[string]bool{HeartbeatSection: tt.expanded},
+				Now:              now,
+			}
+
+			lines, targets := SidebarLines(&s, 30)
+
+			if tt.want == "" {
+				if strings.Contains(strings.Join(lines, "\n"), "heartbeat") {
+					t.Fatalf("expected no heartbeat row:\n%s", strings.Join(lines, "\n"))
+				}
+
+				return
+			}
+
+			var target *SidebarClickTarget
+
+			for i := range targets {
+				if targets[i].Section == HeartbeatSection {
+					target = &targets[i]
+				}
+			}
+
+			if target == nil {
+				t.Fatal("missing heartbeat click target")
+			}
+
+			if got := lines[target.Row]; got != tt.want {
+				t.Fatalf("heartbeat row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSidebarLines_PollInterval(t *testing.T) {
+	tests := []struct {
+		name string
+		snap state.Snapshot
+		want string
+	}{
+		{name: "before first claim"},
+		{name: "running job", snap: state.Snapshot{JobID: "job-1", PollInterval: 30 * time.Second}},
+		{name: "base interval", snap: state.Snapshot{PollInterval: 30 * time.Second}, want: "  poll: every 30s"},
+		{name: "backed off", snap: state.Snapshot{PollInterval: 4 * time.Minute, PollBackedOff: true}, want: "  poll: every 4m (idle)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, _ := SidebarLines(&tt.snap, 30)
+
+			var got string
+
+			for _, line := range lines {
+				if strings.HasPrefix(line, "  poll:") {
+					got = line
+				}
+			}
+
+			if got != tt.want {
+				t.Fatalf("poll row = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSidebarLines_FallbackEndpoint(t *testing.T) {
+	s := state.Snapshot{QueueID: "q-1"}
+
+	lines, _ := SidebarLines(&s, 20)
+	if joined := strings.Join(lines, "\n"); strings.Contains(joined, "api:") {
+		t.Fatalf("expected no api row on the configured endpoint:\n%s", joined)
+	}
+
+	s.FallbackEndpoint = "gw2.example.com"
+	lines, _ = SidebarLines(&s, 20)
+
+	if joined := strings.Join(lines, "\n"); !strings.Contains(joined, "  api: gw2.example.com (fallback)") {
+		t.Fatalf("expected fallback endpoint in sidebar:\n%s", joined)
+	}
+}
+
+// goldenNow anchors the golden snapshots so ages render deterministically.
+var goldenNow = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+// goldenStates are the worker and bundle states snapshotted by
+// TestRenderGolden, each rendered at every size in goldenSizes.
+var goldenStates = []struct {
+	name string
+	snap state.Snapshot
+}{
+	{
+		name: "starting",
+		snap: state.Snapshot{StatusLabel: "Starting..."},
+	},
+	{
+		name: "idle",
+		snap: state.Snapshot{
+			StatusLabel:        "Ready",
+			HabitatID:          "hab-local",
+			QueueID:            "queue-main",
+			SupportedHarnesses: []string{"claude", "codex"},
+			Completed:          12,
+			Failed:             1,
+			PollInterval:       30 * time.Second,
+			PollBackedOff:      true,
+		},
+	},
+	{
+		name: "processing",
+		snap: state.Snapshot{
+			StatusLabel:       "Processing",
+			HabitatID:         "hab-local",
+			QueueID:           "queue-main",
+			JobID:             "job-7f3a9c21",
+			LastHeartbeat:     goldenNow.Add(-5 * time.Second),
+			UsageKnown:        true,
+			UsageTurns:        18,
+			UsageTokens:       1_254_000,
+			UsageCostUSD:      4.5,
+			UsageCostKnown:    true,
+			UsageMaxTurns:     20,
+			UsageMaxBudgetUSD: 5,
+			UsageNearLimit:    true,
+			SupervisionKnown:  true,
+			PTYRestarts:       1,
+			BypassAccepts:     1,
+			ReadyAverage:      1500 * time.Millisecond,
+			MCPServers: []state.MCPServerStatus{
+				{Name: "linear", Loaded: true, Authenticated: true},
+				{Name: "github", Loaded: true, Expired: true},
+			},
+		},
+	},
+	{
+		name: "error",
+		snap: state.Snapshot{
+			StatusLabel:        "Error",
+			QueueID:            "queue-main",
+			Failed:             3,
+			LastError:          "claim job: 503 Service Unavailable from the platform API",
+			LastErrorTime:      goldenNow.Add(-2 * time.Second),
+			LastErrorSeverity:  "error",
+			LastErrorCount:     3,
+			LastErrorRequestID: "req_0123456789abcdef",
+		},
+	},
+	{
+		name: "bundle",
+		snap: state.Snapshot{
+			StatusLabel:    "Ready",
+			BundleLoadMode: true,
+			BundleName:     "my-kit",
+			BundleVer:      "1.2.3",
+			BundleLayers:   4,
+			BundleSkills:   []string{"review/SKILL.md", "triage/SKILL.md", "你好世界/SKILL.md"},
+			BundleAgents:   []string{"planner.md"},
+			BundleTools:    []string{"lint.sh"},
+			MCPServers: []state.MCPServerStatus{
+				{Name: "linear", Loaded: false},
+			},
+		},
+	},
+}
+
+var goldenSizes = []struct{ width, height int }{
+	{64, 20},
+	{80, 24},
+	{120, 40},
+	{240, 12},
+}
+
+// TestRenderGolden snapshots the exact escape sequences Render writes for
+// each state at each terminal size, and checks that no row is drawn
+// outside the terminal.
+func TestRenderGolden(t *testing.T) {
+	for _, tt := range goldenStates {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+
+			for _, size := range goldenSizes {
+				frame := layout.ComputeFrame(size.width, size.height, true)
+
+				snap := tt.snap
+				snap.Width = frame.Width
+				snap.Height = frame.Height
+				snap.SidebarVisible = frame.SidebarVisible
+				snap.SidebarWidth = frame.SidebarWidth
+				snap.PaneXStart = frame.PaneXStart
+				snap.PaneWidth = frame.PaneWidth
+				snap.Now = goldenNow
+
+				out := Render(&snap)
+				assertRowsWithinTerminal(t, out, &frame)
+
+				fmt.Fprintf(&b, "== %dx%d sidebar=%t ==\n%s\n", size.width, size.height, frame.SidebarVisible,
+					testutil.DescribeTerminalOutput(out))
+			}
+
+			testutil.AssertGolden(t, b.String(), "render_"+tt.name+".golden")
+		})
+	}
+}
+
+// assertRowsWithinTerminal fails when out positions the cursor outside the
+// frame, draws a row more than once (which shows up as flicker), or writes
+// past the right edge, which wraps into the row below.
+func assertRowsWithinTerminal(t *testing.T, out string, frame *layout.Frame) {
+	t.Helper()
+
+	drawn := map[int]bool{}
+	row, col := 0, 0
+
+	for _, tok := range ansi.Tokenize(out) {
+		if tok.Kind == ansi.KindText {
+			col += runewidth.StringWidth(tok.Raw)
+			if col-1 > frame.Width {
+				t.Fatalf("row %d is %d cells wide in a %d-column terminal", row, col-1, frame.Width)
+			}
+
+			continue
+		}
+
+		if tok.Kind != ansi.KindCSI || tok.Final != 'H' {
+			continue
+		}
+
+		if _, err := fmt.Sscanf(tok.Params, "%d;%d", &row, &col); err != nil {
+			t.Fatalf("cursor move %q: %v", tok.Raw, err)
+		}
+
+		if row < 1 || row > frame.Height || col < 1 || col > frame.Width {
+			t.Fatalf("cursor move to %d;%d outside %dx%d terminal", row, col, frame.Width, frame.Height)
+		}
+
+		if drawn[row] {
+			t.Fatalf("row %d drawn more than once", row)
+		}
+
+		drawn[row] = true
+	}
+}
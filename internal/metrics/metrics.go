@@ -0,0 +1,280 @@
+// Package metrics keeps a running worker's counters and histograms and
+// writes them in the Prometheus text exposition format, so a worker can be
+// scraped without a metrics client library.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ContentType is the media type of the text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DurationBuckets are histogram bucket bounds in seconds suited to API
+// requests, including claims that long-poll for up to a few minutes.
+var DurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}
+
+// Registry holds metrics and writes them for a scrape.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// family is the series sharing one metric name, help text, and type.
+type family struct {
+	name   string
+	help   string
+	typ    string
+	series []series
+}
+
+// series writes one labeled time series, or for a collector, several.
+type series interface {
+	write(w io.Writer, name string)
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Counter registers a counter. labels are name and value pairs that tell it
+// apart from other series with the same name.
+func (r *Registry) Counter(name, help string, labels ...string) *Counter {
+	c := &Counter{labels: formatLabels(labels)}
+	r.register(name, help, "counter", c)
+
+	return c
+}
+
+// Histogram registers a histogram with the given upper bucket bounds, in
+// ascending order. labels are name and value pairs, as for Counter.
+func (r *Registry) Histogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	h := &Histogram{
+		labels:  labels,
+		buckets: append([]float64(nil), buckets...),
+		counts:  make([]uint64, len(buckets)),
+	}
+	r.register(name, help, "histogram", h)
+
+	return h
+}
+
+// CounterFunc registers counters read from collect at each scrape, one
+// series per key of the map it returns, labeled label. It suits totals that
+// something else already keeps.
+func (r *Registry) CounterFunc(name, help, label string, collect func() map[string]float64) {
+	r.register(name, help, "counter", &counterFunc{label: label, collect: collect})
+}
+
+func (r *Registry) register(name, help, typ string, s series) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, typ: typ}
+		r.families[name] = f
+	}
+
+	f.series = append(f.series, s)
+}
+
+// Write writes every metric in the text exposition format, ordered by name.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	var b strings.Builder
+
+	for _, f := range families {
+		fmt.Fprintf(&b, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(&b, "# TYPE %s %s\n", f.name, f.typ)
+
+		for _, s := range f.series {
+			s.write(&b, f.name)
+		}
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("write metrics: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP answers a scrape.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+
+	if req.Method == http.MethodHead {
+		return
+	}
+
+	_ = r.Write(w)
+}
+
+// Counter is a count that only goes up.
+type Counter struct {
+	labels string
+	value  atomic.Uint64
+}
+
+// Inc adds one to the counter.
+func (c *Counter) Inc() {
+	c.value.Add(1)
+}
+
+// Value returns the count.
+func (c *Counter) Value() uint64 {
+	return c.value.Load()
+}
+
+func (c *Counter) write(w io.Writer, name string) {
+	fmt.Fprintf(w, "%s%s %d\n", name, c.labels, c.value.Load())
+}
+
+// Histogram counts observations in buckets by value.
+type Histogram struct {
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// Observe records v.
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+
+	h.count++
+	h.sum += v
+}
+
+// ObserveDuration records d in seconds.
+func (h *Histogram) ObserveDuration(d time.Duration) {
+	h.Observe(d.Seconds())
+}
+
+func (h *Histogram) write(w io.Writer, name string) {
+	h.mu.Lock()
+	counts := append([]uint64(nil), h.counts...)
+	count, sum := h.count, h.sum
+	h.mu.Unlock()
+
+	bucket := func(le string) string {
+		return formatLabels(append(slices.Clone(h.labels), "le", le))
+	}
+
+	var cumulative uint64
+
+	for i, bound := range h.buckets {
+		cumulative += counts[i]
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, bucket(formatFloat(bound)), cumulative)
+	}
+
+	fmt.Fprintf(w, "%s_bucket%s %d\n", name, bucket("+Inf"), count)
+	fmt.Fprintf(w, "%s_sum%s %s\n", name, formatLabels(h.labels), formatFloat(sum))
+	fmt.Fprintf(w, "%s_count%s %d\n", name, formatLabels(h.labels), count)
+}
+
+type counterFunc struct {
+	label   string
+	collect func() map[string]float64
+}
+
+func (c *counterFunc) write(w io.Writer, name string) {
+	values := c.collect()
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s%s %s\n", name, formatLabels([]string{c.label, key}), formatFloat(values[key]))
+	}
+}
+
+// formatLabels renders name and value pairs as {name="value",...}, or ""
+// for none.
+func formatLabels(pairs []string) string {
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+
+	b.WriteByte('{')
+
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(pairs[i])
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(pairs[i+1]))
+		b.WriteByte('"')
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+var (
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabelValue(s string) string { return labelValueEscaper.Replace(s) }
+
+func escapeHelp(s string) string { return helpEscaper.Replace(s) }
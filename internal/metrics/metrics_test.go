@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	reg := NewRegistry()
+
+	claimed := reg.Counter("mush_jobs_total", "Jobs by outcome.", "outcome", "claimed")
+	failed := reg.Counter("mush_jobs_total", "Jobs by outcome.", "outcome", "failed")
+	latency := reg.Histogram("mush_claim_duration_seconds", "Claim latency.", []float64{0.5, 1})
+	reg.CounterFunc("mush_restarts_total", "Restarts by harness.", "harness", func() map[string]float64 {
+		return map[string]float64{"codex": 0, "claude": 2}
+	})
+
+	claimed.Inc()
+	claimed.Inc()
+	failed.Inc()
+	latency.ObserveDuration(200 * time.Millisecond)
+	latency.Observe(0.75)
+	latency.Observe(3)
+
+	var b strings.Builder
+	if err := reg.Write(&b); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := `# HELP mush_claim_duration_seconds Claim latency.
+# TYPE mush_claim_duration_seconds histogram
+mush_claim_duration_seconds_bucket{le="0.5"} 1
+mush_claim_duration_seconds_bucket{le="1"} 2
+mush_claim_duration_seconds_bucket{le="+Inf"} 3
+mush_claim_duration_seconds_sum 3.95
+mush_claim_duration_seconds_count 3
+# HELP mush_jobs_total Jobs by outcome.
+# TYPE mush_jobs_total counter
+mush_jobs_total{outcome="claimed"} 2
+mush_jobs_total{outcome="failed"} 1
+# HELP mush_restarts_total Restarts by harness.
+# TYPE mush_restarts_total counter
+mush_restarts_total{harness="claude"} 2
+mush_restarts_total{harness="codex"} 0
+`
+	if b.String() != want {
+		t.Fatalf("Write() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestRegistryServeHTTP(t *testing.T) {
+	reg := NewRegistry()
+	reg.Counter("mush_up", "Up.").Inc()
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != ContentType || !strings.Contains(rec.Body.String(), "mush_up 1\n") {
+		t.Fatalf("GET = %d %q %q, want the metrics", rec.Code, rec.Header().Get("Content-Type"), rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/metrics", http.NoBody))
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("POST = %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestFormatLabelsEscapes(t *testing.T) {
+	if got, want := formatLabels([]string{"path", "a\\b \"c\"\n"}), `{path="a\\b \"c\"\n"}`; got != want {
+		t.Fatalf("formatLabels() = %s, want %s", got, want)
+	}
+}
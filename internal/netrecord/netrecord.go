@@ -0,0 +1,457 @@
+// Package netrecord runs a local HTTP proxy that records the outbound calls
+// made through it, so a job's summary can show which external systems its
+// agent and MCP servers reached. Only hosts, methods, and byte counts are
+// recorded; request and response bodies and headers never are.
+package netrecord
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dialTimeout bounds connecting to a host on a client's behalf.
+const dialTimeout = 30 * time.Second
+
+// noProxyHosts are left out of Env's proxy, so local services such as the
+// worker's own hook endpoints are reached directly.
+const noProxyHosts = "localhost,127.0.0.1,::1"
+
+// hopHeaders are meaningful only between a client and its proxy and are not
+// forwarded.
+var hopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Call totals the requests made with one method to one host.
+type Call struct {
+	// Host is the host called, with its port unless it is the default for
+	// the scheme.
+	Host string `json:"host"`
+
+	// Method is the HTTP method, or CONNECT for HTTPS, whose requests pass
+	// through the proxy encrypted.
+	Method string `json:"method"`
+
+	// Count is the number of requests, or for CONNECT of tunnels opened.
+	Count int `json:"count"`
+
+	// Errors counts the requests that could not reach the host.
+	Errors int `json:"errors,omitempty"`
+
+	BytesSent     int64 `json:"bytesSent"`
+	BytesReceived int64 `json:"bytesReceived"`
+}
+
+// Summary describes the calls recorded since a Recorder was started or
+// last reset.
+type Summary struct {
+	// Calls are ordered by host, then method.
+	Calls []Call `json:"calls"`
+
+	Requests      int   `json:"requests"`
+	BytesSent     int64 `json:"bytesSent"`
+	BytesReceived int64 `json:"bytesReceived"`
+}
+
+// Hosts returns the hosts called, in order, each once.
+func (s *Summary) Hosts() []string {
+	var hosts []string
+
+	for _, call := range s.Calls {
+		if len(hosts) == 0 || hosts[len(hosts)-1] != call.Host {
+			hosts = append(hosts, call.Host)
+		}
+	}
+
+	return hosts
+}
+
+type callKey struct {
+	host   string
+	method string
+}
+
+// Recorder is a forward proxy on the loopback interface that records the
+// calls made through it.
+type Recorder struct {
+	ln        net.Listener
+	srv       *http.Server
+	transport *http.Transport
+	dialer    net.Dialer
+
+	mu      sync.Mutex
+	calls   map[callKey]*Call
+	tunnels map[net.Conn]struct{}
+	closed  bool
+}
+
+// Start starts a Recorder on a free loopback port. Calls through it go out
+// directly, never through another proxy.
+func Start() (*Recorder, error) {
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(context.Background(), "tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("listen for network recording: %w", err)
+	}
+
+	r := &Recorder{
+		ln:      ln,
+		calls:   make(map[callKey]*Call),
+		tunnels: make(map[net.Conn]struct{}),
+		dialer:  net.Dialer{Timeout: dialTimeout},
+	}
+
+	r.transport = &http.Transport{
+		DialContext:         r.dialer.DialContext,
+		MaxIdleConnsPerHost: 4,
+		IdleConnTimeout:     90 * time.Second,
+	}
+
+	r.srv = &http.Server{
+		Handler:           r,
+		ReadHeaderTimeout: dialTimeout,
+	}
+
+	go func() { _ = r.srv.Serve(ln) }()
+
+	return r, nil
+}
+
+// URL returns the proxy's URL.
+func (r *Recorder) URL() string {
+	return "http://" + r.ln.Addr().String()
+}
+
+// Env returns the environment variables that send a process's HTTP and
+// HTTPS calls through the recorder. Only clients that honor the standard
+// proxy variables are recorded.
+func (r *Recorder) Env() []string {
+	url := r.URL()
+
+	noProxy := noProxyHosts
+	if existing := os.Getenv("NO_PROXY"); existing != "" {
+		noProxy = existing + "," + noProxy
+	}
+
+	return []string{
+		"HTTP_PROXY=" + url,
+		"HTTPS_PROXY=" + url,
+		"http_proxy=" + url,
+		"https_proxy=" + url,
+		"NO_PROXY=" + noProxy,
+		"no_proxy=" + noProxy,
+	}
+}
+
+// Reset discards the calls recorded so far. Bytes still flowing through a
+// tunnel opened earlier count toward the next summary.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.calls = make(map[callKey]*Call)
+}
+
+// Summary returns the calls recorded since the recorder started or was last
+// reset, or nil when there were none.
+func (r *Recorder) Summary() *Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.calls) == 0 {
+		return nil
+	}
+
+	summary := &Summary{Calls: make([]Call, 0, len(r.calls))}
+
+	for _, call := range r.calls {
+		summary.Calls = append(summary.Calls, *call)
+		summary.Requests += call.Count
+		summary.BytesSent += call.BytesSent
+		summary.BytesReceived += call.BytesReceived
+	}
+
+	sort.Slice(summary.Calls, func(i, j int) bool {
+		a, b := summary.Calls[i], summary.Calls[j]
+		if a.Host != b.Host {
+			return a.Host < b.Host
+		}
+
+		return a.Method < b.Method
+	})
+
+	return summary
+}
+
+// Close stops the proxy and ends the tunnels open through it.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	r.closed = true
+
+	for conn := range r.tunnels {
+		_ = conn.Close()
+	}
+	r.mu.Unlock()
+
+	r.transport.CloseIdleConnections()
+
+	if err := r.srv.Close(); err != nil {
+		return fmt.Errorf("close network recorder: %w", err)
+	}
+
+	return nil
+}
+
+// ServeHTTP proxies a request: CONNECT opens a tunnel, and a request with
+// an absolute URL is forwarded.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.Method == http.MethodConnect:
+		r.tunnel(w, req)
+	case req.URL.IsAbs():
+		r.forward(w, req)
+	default:
+		http.Error(w, "this is a proxy; send requests with an absolute URL", http.StatusBadRequest)
+	}
+}
+
+// tunnel relays a CONNECT request's bytes between the client and the host.
+func (r *Recorder) tunnel(w http.ResponseWriter, req *http.Request) {
+	key := callKey{host: hostName(req.Host, "443"), method: http.MethodConnect}
+
+	upstream, err := r.dialer.DialContext(req.Context(), "tcp", req.Host)
+	if err != nil {
+		r.record(key, true)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		_ = upstream.Close()
+
+		r.record(key, true)
+		http.Error(w, "tunneling is not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	client, buffered, err := hijacker.Hijack()
+	if err != nil {
+		_ = upstream.Close()
+
+		r.record(key, true)
+
+		return
+	}
+
+	if !r.track(client, upstream) {
+		_ = client.Close()
+		_ = upstream.Close()
+
+		return
+	}
+
+	defer r.untrack(client, upstream)
+
+	r.record(key, false)
+
+	if _, err := client.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+		return
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		_, _ = io.Copy(&countingWriter{w: client, r: r, key: key}, upstream)
+		_ = client.Close()
+	}()
+
+	// The client may have sent bytes the server already buffered.
+	var src io.Reader = client
+	if buffered != nil && buffered.Reader.Buffered() > 0 {
+		src = io.MultiReader(io.LimitReader(buffered.Reader, int64(buffered.Reader.Buffered())), client)
+	}
+
+	_, _ = io.Copy(&countingWriter{w: upstream, r: r, key: key, sent: true}, src)
+	_ = upstream.Close()
+
+	<-done
+}
+
+// forward sends a plain HTTP request on to its host and relays the
+// response.
+func (r *Recorder) forward(w http.ResponseWriter, req *http.Request) {
+	key := callKey{host: hostName(req.URL.Host, "80"), method: req.Method}
+
+	out := req.Clone(req.Context())
+	out.RequestURI = ""
+	removeHopHeaders(out.Header)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		out.Body = &countingReader{rc: req.Body, r: r, key: key}
+	}
+
+	resp, err := r.transport.RoundTrip(out)
+	if err != nil {
+		r.record(key, true)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+
+		return
+	}
+	defer resp.Body.Close()
+
+	r.record(key, false)
+
+	removeHopHeaders(resp.Header)
+
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	w.WriteHeader(resp.StatusCode)
+
+	_, _ = io.Copy(&countingWriter{w: w, r: r, key: key}, resp.Body)
+}
+
+// call returns the entry for key, creating it. The caller holds r.mu.
+func (r *Recorder) call(key callKey) *Call {
+	call, ok := r.calls[key]
+	if !ok {
+		call = &Call{Host: key.host, Method: key.method}
+		r.calls[key] = call
+	}
+
+	return call
+}
+
+func (r *Recorder) record(key callKey, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call := r.call(key)
+	call.Count++
+
+	if failed {
+		call.Errors++
+	}
+}
+
+func (r *Recorder) addBytes(key callKey, n int, sent bool) {
+	if n <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	call := r.call(key)
+	if sent {
+		call.BytesSent += int64(n)
+	} else {
+		call.BytesReceived += int64(n)
+	}
+}
+
+// track registers a tunnel's connections so Close can end them, reporting
+// false once the recorder is closed.
+func (r *Recorder) track(conns ...net.Conn) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return false
+	}
+
+	for _, conn := range conns {
+		r.tunnels[conn] = struct{}{}
+	}
+
+	return true
+}
+
+func (r *Recorder) untrack(conns ...net.Conn) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, conn := range conns {
+		delete(r.tunnels, conn)
+	}
+}
+
+// countingWriter counts the bytes written through it toward key.
+type countingWriter struct {
+	w    io.Writer
+	r    *Recorder
+	key  callKey
+	sent bool
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.r.addBytes(c.key, n, c.sent)
+
+	return n, err //nolint:wrapcheck // passed through unchanged
+}
+
+// countingReader counts a request body's bytes toward key as sent.
+type countingReader struct {
+	rc  io.ReadCloser
+	r   *Recorder
+	key callKey
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.rc.Read(p)
+	c.r.addBytes(c.key, n, true)
+
+	return n, err //nolint:wrapcheck // passed through unchanged
+}
+
+func (c *countingReader) Close() error {
+	return c.rc.Close() //nolint:wrapcheck // passed through unchanged
+}
+
+// hostName returns hostport without the scheme's default port.
+func hostName(hostport, defaultPort string) string {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil || port != defaultPort {
+		return strings.ToLower(hostport)
+	}
+
+	return strings.ToLower(host)
+}
+
+func removeHopHeaders(header http.Header) {
+	for _, field := range header.Values("Connection") {
+		for _, name := range strings.Split(field, ",") {
+			header.Del(strings.TrimSpace(name))
+		}
+	}
+
+	for _, name := range hopHeaders {
+		header.Del(name)
+	}
+}
@@ -0,0 +1,133 @@
+package netrecord
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestRecorderRecordsCalls(t *testing.T) {
+	rec, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer rec.Close()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_, _ = io.WriteString(w, "hello")
+	})
+
+	plain := httptest.NewServer(handler)
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(handler)
+	defer secure.Close()
+
+	proxyURL, err := url.Parse(rec.URL())
+	if err != nil {
+		t.Fatalf("parse proxy URL: %v", err)
+	}
+
+	transport := secure.Client().Transport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	client := &http.Client{Transport: transport}
+
+	// A call made before Reset is left out of the summary.
+	get(t, client, plain.URL)
+	rec.Reset()
+
+	resp, err := client.Post(plain.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatalf("POST through proxy error = %v", err)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	get(t, client, secure.URL)
+
+	summary := rec.Summary()
+	if summary == nil || len(summary.Calls) != 2 {
+		t.Fatalf("Summary() = %+v, want a POST and a CONNECT", summary)
+	}
+
+	byMethod := map[string]Call{}
+	for _, call := range summary.Calls {
+		byMethod[call.Method] = call
+	}
+
+	post := byMethod[http.MethodPost]
+	if post.Host != strings.TrimPrefix(plain.URL, "http://") || post.Count != 1 || post.BytesSent != int64(len("payload")) || post.BytesReceived != int64(len("hello")) {
+		t.Fatalf("POST call = %+v, want one call with its body sizes", post)
+	}
+
+	connect := byMethod[http.MethodConnect]
+	if connect.Count != 1 || connect.BytesSent == 0 || connect.BytesReceived == 0 {
+		t.Fatalf("CONNECT call = %+v, want one tunnel with bytes both ways", connect)
+	}
+
+	if summary.Requests != 2 || len(summary.Hosts()) != 2 {
+		t.Fatalf("Summary() = %+v, want 2 requests to 2 hosts", summary)
+	}
+}
+
+func TestRecorderRecordsUnreachableHost(t *testing.T) {
+	rec, err := Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer rec.Close()
+
+	// A closed server's address refuses connections.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	proxyURL, _ := url.Parse(rec.URL())
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	resp, err := client.Get(closed.URL)
+	if err != nil {
+		t.Fatalf("GET through proxy error = %v", err)
+	}
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("status = %d, want 502", resp.StatusCode)
+	}
+
+	if summary := rec.Summary(); summary == nil || summary.Calls[0].Errors != 1 {
+		t.Fatalf("Summary() = %+v, want the failed call counted", summary)
+	}
+}
+
+func TestHostName(t *testing.T) {
+	tests := []struct {
+		hostport, defaultPort, want string
+	}{
+		{"API.github.com:443", "443", "api.github.com"},
+		{"example.com:8443", "443", "example.com:8443"},
+		{"example.com", "80", "example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := hostName(tt.hostport, tt.defaultPort); got != tt.want {
+			t.Errorf("hostName(%q, %q) = %q, want %q", tt.hostport, tt.defaultPort, got, tt.want)
+		}
+	}
+}
+
+func get(t *testing.T, client *http.Client, target string) {
+	t.Helper()
+
+	resp, err := client.Get(target)
+	if err != nil {
+		t.Fatalf("GET %s through proxy error = %v", target, err)
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
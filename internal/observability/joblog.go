@@ -0,0 +1,145 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// JobLogPath returns the file under dir that holds the log of the job with
+// jobID, one JSON record per line.
+func JobLogPath(dir, jobID string) string {
+	name := strings.NewReplacer("/", "_", "\\", "_", ":", "_").Replace(jobID)
+	if name == "" || strings.Trim(name, ".") == "" {
+		name = "_" + name
+	}
+
+	return filepath.Join(dir, name+".jsonl")
+}
+
+// OldJobLogs returns the job logs under dir last written before cutoff. A
+// missing dir holds none.
+func OldJobLogs(dir string, cutoff time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read job logs directory: %w", err)
+	}
+
+	var paths []string
+
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || filepath.Ext(entry.Name()) != ".jsonl" {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		if info.ModTime().Before(cutoff) {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+
+	return paths, nil
+}
+
+// PruneJobLogs removes the job logs under dir last written before cutoff,
+// as history prune does for transcript sessions, and returns how many it
+// removed.
+func PruneJobLogs(dir string, cutoff time.Time) (int, error) {
+	paths, err := OldJobLogs(dir, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, fmt.Errorf("prune job log: %w", err)
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
+
+// WithJobLog returns a context whose logger also appends every record to the
+// job log at path, in the JSON form NewLogger writes, so one job's records
+// can be read without the rest of the worker's log. Call it before WithJob
+// so the job attributes reach the file too. The returned function closes
+// the file.
+func WithJobLog(ctx context.Context, path string) (context.Context, func() error, error) {
+	file, err := openLogFile(path)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	fileHandler := slog.NewJSONHandler(file, &slog.HandlerOptions{
+		Level:       logLevel,
+		ReplaceAttr: redactAttr,
+	})
+
+	logger := slog.New(teeHandler{FromContext(ctx).Handler(), fileHandler})
+
+	return WithLogger(ctx, logger), file.Close, nil
+}
+
+// teeHandler sends each record to every handler that accepts its level.
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, record slog.Record) error {
+	var errs []error
+
+	for _, h := range t {
+		if !h.Enabled(ctx, record.Level) {
+			continue
+		}
+
+		if err := h.Handle(ctx, record.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+
+	return handlers
+}
@@ -0,0 +1,103 @@
+package observability
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJobLogPath(t *testing.T) {
+	dir := filepath.Join("logs", "jobs")
+
+	tests := map[string]string{
+		"job-123":   "job-123.jsonl",
+		"../escape": ".._escape.jsonl",
+		"a:b\\c":    "a_b_c.jsonl",
+		"..":        "_...jsonl",
+		"":          "_.jsonl",
+	}
+
+	for jobID, want := range tests {
+		if got := JobLogPath(dir, jobID); got != filepath.Join(dir, want) {
+			t.Errorf("JobLogPath(%q) = %q, want %q", jobID, got, filepath.Join(dir, want))
+		}
+	}
+}
+
+func TestWithJobLog_WritesJobRecordsToBothLogs(t *testing.T) {
+	var buf bytes.Buffer
+
+	base := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: logLevel, ReplaceAttr: redactAttr}))
+	path := filepath.Join(t.TempDir(), "jobs", "job-123.jsonl")
+
+	ctx, closeLog, err := WithJobLog(WithLogger(t.Context(), base), path)
+	if err != nil {
+		t.Fatalf("WithJobLog() error = %v", err)
+	}
+
+	ctx = WithJob(ctx, JobScope{JobID: "job-123", Attempt: 2})
+	FromContext(ctx).Info("executing", "api_key", "sk-secret")
+	FromContext(ctx).Debug("below the level")
+
+	if err := closeLog(); err != nil {
+		t.Fatalf("close job log: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	for name, got := range map[string][]byte{"worker log": buf.Bytes(), "job log": data} {
+		rec := ParseLogRecord(bytes.TrimSpace(got))
+		if rec.Message != "executing" || rec.Attr("job.id") != "job-123" || rec.Attr("job.attempt_number") != "2" {
+			t.Errorf("%s = %s, want the job-scoped record", name, got)
+		}
+
+		if bytes.Contains(got, []byte("sk-secret")) || bytes.Contains(got, []byte("below the level")) {
+			t.Errorf("%s = %s, want secrets redacted and debug records left out", name, got)
+		}
+	}
+}
+
+func TestPruneJobLogs(t *testing.T) {
+	dir := t.TempDir()
+	cutoff := time.Now().Add(-time.Hour)
+
+	for name, modTime := range map[string]time.Time{
+		"old.jsonl":   cutoff.Add(-time.Minute),
+		"fresh.jsonl": cutoff.Add(time.Minute),
+		"old.txt":     cutoff.Add(-time.Minute),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("{}\n"), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("Chtimes() error = %v", err)
+		}
+	}
+
+	removed, err := PruneJobLogs(dir, cutoff)
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneJobLogs() = %d, %v; want 1 removed", removed, err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "old.jsonl")); !os.IsNotExist(err) {
+		t.Fatalf("old job log still exists: %v", err)
+	}
+
+	for _, name := range []string{"fresh.jsonl", "old.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("%s was removed: %v", name, err)
+		}
+	}
+
+	if removed, err := PruneJobLogs(filepath.Join(dir, "missing"), cutoff); err != nil || removed != 0 {
+		t.Fatalf("PruneJobLogs(missing dir) = %d, %v; want nothing removed", removed, err)
+	}
+}
@@ -21,6 +21,10 @@ const (
 
 type contextKey struct{}
 
+// logLevel is shared by every logger from NewLogger so SetLevel can change
+// verbosity while the process runs.
+var logLevel = new(slog.LevelVar)
+
 // Config holds the configuration for the observability logger.
 type Config struct {
 	Level       string
@@ -47,6 +51,39 @@ func FromContext(ctx context.Context) *slog.Logger {
 	return slog.Default()
 }
 
+// JobScope identifies the job that log lines belong to.
+type JobScope struct {
+	JobID       string
+	QueueID     string
+	HarnessType string
+	Attempt     int
+}
+
+// WithJob returns a context whose logger tags every record with the job
+// scope, so lines from executors and API calls can be filtered per job.
+// Empty fields are omitted.
+func WithJob(ctx context.Context, scope JobScope) context.Context {
+	attrs := make([]any, 0, 4)
+
+	if scope.JobID != "" {
+		attrs = append(attrs, slog.String("job.id", scope.JobID))
+	}
+
+	if scope.QueueID != "" {
+		attrs = append(attrs, slog.String("job.queue_id", scope.QueueID))
+	}
+
+	if scope.HarnessType != "" {
+		attrs = append(attrs, slog.String("job.harness_type", scope.HarnessType))
+	}
+
+	if scope.Attempt > 0 {
+		attrs = append(attrs, slog.Int("job.attempt_number", scope.Attempt))
+	}
+
+	return WithLogger(ctx, FromContext(ctx).With(attrs...))
+}
+
 // NewLogger creates a structured logger from the given configuration.
 func NewLogger(cfg *Config) (*slog.Logger, func() error, error) {
 	level, err := parseLevel(cfg.Level)
@@ -54,6 +91,8 @@ func NewLogger(cfg *Config) (*slog.Logger, func() error, error) {
 		return nil, nil, err
 	}
 
+	logLevel.Set(level)
+
 	stderrEnabled, err := shouldEnableStderr(cfg.StderrMode)
 	if err != nil {
 		return nil, nil, err
@@ -96,7 +135,7 @@ func NewLogger(cfg *Config) (*slog.Logger, func() error, error) {
 	}
 
 	handlerOpts := &slog.HandlerOptions{
-		Level:       level,
+		Level:       logLevel,
 		ReplaceAttr: redactAttr,
 	}
 
@@ -217,7 +256,26 @@ func shouldEnableStderr(mode string) (bool, error) {
 	}
 }
 
-func parseLevel(level string) (slog.Leveler, error) {
+// CurrentLevel returns the level of loggers created by NewLogger.
+func CurrentLevel() slog.Level {
+	return logLevel.Level()
+}
+
+// SetLevel changes the level of loggers created by NewLogger and returns the
+// previous level.
+func SetLevel(level string) (slog.Level, error) {
+	parsed, err := parseLevel(level)
+	if err != nil {
+		return logLevel.Level(), err
+	}
+
+	previous := logLevel.Level()
+	logLevel.Set(parsed)
+
+	return previous, nil
+}
+
+func parseLevel(level string) (slog.Level, error) {
 	switch strings.ToLower(strings.TrimSpace(level)) {
 	case "", "info":
 		return slog.LevelInfo, nil
@@ -228,7 +286,7 @@ func parseLevel(level string) (slog.Leveler, error) {
 	case "error":
 		return slog.LevelError, nil
 	default:
-		return nil, fmt.Errorf("invalid log level: %q (allowed: error, warn, info, debug)", level)
+		return 0, fmt.Errorf("invalid log level: %q (allowed: error, warn, info, debug)", level)
 	}
 }
 
@@ -1,6 +1,9 @@
 package observability
 
 import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
@@ -97,3 +100,94 @@ func TestRotateLogFile_RotatesAndKeepsBoundedBackups(t *testing.T) {
 		t.Fatalf("backup retention ordering wrong: .3 = %q, want %q", string(data3), "two")
 	}
 }
+
+func TestWithJob_TagsRecordsWithJobScope(t *testing.T) {
+	var buf bytes.Buffer
+
+	base := slog.New(slog.NewJSONHandler(&buf, nil))
+	ctx := WithJob(WithLogger(t.Context(), base), JobScope{
+		JobID:       "job-123",
+		QueueID:     "queue-1",
+		HarnessType: "claude",
+		Attempt:     2,
+	})
+
+	FromContext(ctx).Info("executing")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("parse log record: %v (%s)", err, buf.String())
+	}
+
+	want := map[string]any{
+		"job.id":             "job-123",
+		"job.queue_id":       "queue-1",
+		"job.harness_type":   "claude",
+		"job.attempt_number": float64(2),
+	}
+
+	for key, value := range want {
+		if record[key] != value {
+			t.Errorf("record[%q] = %v, want %v", key, record[key], value)
+		}
+	}
+}
+
+func TestWithJob_OmitsEmptyFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	base := slog.New(slog.NewJSONHandler(&buf, nil))
+	ctx := WithJob(WithLogger(t.Context(), base), JobScope{JobID: "job-123"})
+
+	FromContext(ctx).Info("executing")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("parse log record: %v", err)
+	}
+
+	for _, key := range []string{"job.queue_id", "job.harness_type", "job.attempt_number"} {
+		if _, ok := record[key]; ok {
+			t.Errorf("record has %q, want it omitted", key)
+		}
+	}
+}
+
+func TestSetLevel_ChangesExistingLoggers(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "mush.log")
+
+	logger, cleanup, err := NewLogger(&Config{Level: "info", Format: "json", LogFile: logPath, StderrMode: "off"})
+	if err != nil {
+		t.Fatalf("NewLogger() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = cleanup() })
+
+	logger.Debug("hidden")
+
+	previous, err := SetLevel("debug")
+	if err != nil {
+		t.Fatalf("SetLevel() error = %v", err)
+	}
+
+	t.Cleanup(func() { logLevel.Set(slog.LevelInfo) })
+
+	if previous != slog.LevelInfo {
+		t.Fatalf("previous level = %v, want info", previous)
+	}
+
+	logger.Debug("visible")
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	if bytes.Contains(data, []byte("hidden")) || !bytes.Contains(data, []byte("visible")) {
+		t.Fatalf("log output = %s, want only the record after SetLevel", data)
+	}
+
+	if _, err := SetLevel("loud"); err == nil {
+		t.Fatal("SetLevel(loud) error = nil, want error")
+	}
+}
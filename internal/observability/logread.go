@@ -0,0 +1,334 @@
+package observability
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// logFollowInterval is how often a followed log file is checked for new
+// lines.
+const logFollowInterval = 250 * time.Millisecond
+
+// LogRecord is one line of a structured log file.
+type LogRecord struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+
+	// Attrs holds the line's other fields, keyed as written.
+	Attrs map[string]any
+
+	// Raw is the line as written, without its newline.
+	Raw []byte
+
+	// Structured is false for a line that is not a JSON object, such as
+	// one written with --log-format text.
+	Structured bool
+}
+
+// Attr returns the string form of the field key, or "".
+func (r *LogRecord) Attr(key string) string {
+	value, ok := r.Attrs[key]
+	if !ok || value == nil {
+		return ""
+	}
+
+	if s, isString := value.(string); isString {
+		return s
+	}
+
+	return fmt.Sprint(value)
+}
+
+// ParseLogRecord decodes one log line written by the JSON handler.
+func ParseLogRecord(line []byte) LogRecord {
+	rec := LogRecord{Raw: line}
+
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return rec
+	}
+
+	rec.Structured = true
+
+	if raw, ok := fields[slog.TimeKey].(string); ok {
+		rec.Time, _ = time.Parse(time.RFC3339Nano, raw)
+	}
+
+	if raw, ok := fields[slog.LevelKey].(string); ok {
+		_ = rec.Level.UnmarshalText([]byte(raw))
+	}
+
+	rec.Message, _ = fields[slog.MessageKey].(string)
+
+	delete(fields, slog.TimeKey)
+	delete(fields, slog.LevelKey)
+	delete(fields, slog.MessageKey)
+	rec.Attrs = fields
+
+	return rec
+}
+
+// LogFilter selects log records. Zero fields match everything.
+type LogFilter struct {
+	// MinLevel drops records below it.
+	MinLevel slog.Level
+
+	// Components and EventTypes keep records whose component or event.type
+	// is one of the values, or, for event types, is under one: "job"
+	// matches job.start and job.fail.
+	Components []string
+	EventTypes []string
+
+	// ExcludeCommand drops records logged by this command path, such as
+	// the command reading the log.
+	ExcludeCommand string
+}
+
+// ParseLogLevel parses a --log-level style name.
+func ParseLogLevel(level string) (slog.Level, error) {
+	return parseLevel(level)
+}
+
+// Match reports whether rec passes the filter. A line that is not JSON
+// passes only a filter that selects nothing.
+func (f *LogFilter) Match(rec *LogRecord) bool {
+	if !rec.Structured {
+		return f.MinLevel <= slog.LevelDebug && len(f.Components) == 0 && len(f.EventTypes) == 0
+	}
+
+	if rec.Level < f.MinLevel {
+		return false
+	}
+
+	if f.ExcludeCommand != "" && rec.Attr("command.path") == f.ExcludeCommand {
+		return false
+	}
+
+	if len(f.Components) > 0 && !containsFold(f.Components, rec.Attr("component")) {
+		return false
+	}
+
+	if len(f.EventTypes) > 0 {
+		eventType := rec.Attr("event.type")
+
+		matched := false
+
+		for _, want := range f.EventTypes {
+			if eventType == want || strings.HasPrefix(eventType, want+".") {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			return false
+		}
+	}
+
+	return true
+}
+
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// LogFilePath returns the log file a worker writes to: MUSH_LOG_FILE, or
+// the default log file.
+func LogFilePath() (string, error) {
+	if path := strings.TrimSpace(os.Getenv("MUSH_LOG_FILE")); path != "" {
+		return path, nil
+	}
+
+	path, err := paths.DefaultLogFile()
+	if err != nil {
+		return "", fmt.Errorf("resolve default log file: %w", err)
+	}
+
+	return path, nil
+}
+
+// TailOptions controls TailLog.
+type TailOptions struct {
+	// Lines is how many matching records to show from the end of the file
+	// before following; zero shows none and a negative value shows all.
+	Lines int
+
+	// Follow keeps reading as lines are appended, across rotation, until
+	// the context is canceled.
+	Follow bool
+
+	Filter LogFilter
+}
+
+// maxFollowedLogs bounds how many rotated log files are still read while
+// following. A worker keeps writing to its file after another command
+// rotates it, so rotated files stay open rather than being dropped.
+const maxFollowedLogs = 3
+
+// followedLog is an open log file and how far into it has been read.
+type followedLog struct {
+	file   *os.File
+	offset int64
+}
+
+// TailLog calls fn with the matching records at the end of the log file at
+// path and, with Follow, each one appended after.
+func TailLog(ctx context.Context, path string, opts *TailOptions, fn func(*LogRecord) error) error {
+	file, err := safeio.Open(path)
+	if err != nil {
+		return fmt.Errorf("open log file: %w", err)
+	}
+
+	current := &followedLog{file: file}
+	followed := []*followedLog{current}
+
+	defer func() {
+		for _, log := range followed {
+			_ = log.file.Close()
+		}
+	}()
+
+	var recent []LogRecord
+
+	err = current.read(func(rec *LogRecord) error {
+		if opts.Lines == 0 || !opts.Filter.Match(rec) {
+			return nil
+		}
+
+		recent = append(recent, *rec)
+		if opts.Lines > 0 && len(recent) > opts.Lines {
+			recent = recent[1:]
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range recent {
+		if err := fn(&recent[i]); err != nil {
+			return err
+		}
+	}
+
+	if !opts.Follow {
+		return nil
+	}
+
+	emit := func(rec *LogRecord) error {
+		if !opts.Filter.Match(rec) {
+			return nil
+		}
+
+		return fn(rec)
+	}
+
+	ticker := time.NewTicker(logFollowInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if next := current.replacement(path); next != nil {
+			current = next
+			followed = append(followed, next)
+
+			if len(followed) > maxFollowedLogs {
+				_ = followed[0].file.Close()
+				followed = followed[1:]
+			}
+		}
+
+		for _, log := range followed {
+			if err := log.read(emit); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// replacement returns the file now at path when it is no longer l's file,
+// as after rotation. A file truncated in place is read again from its start.
+func (l *followedLog) replacement(path string) *followedLog {
+	pathInfo, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+
+	info, err := l.file.Stat()
+	if err != nil {
+		return nil
+	}
+
+	if os.SameFile(info, pathInfo) {
+		if info.Size() < l.offset {
+			l.offset = 0
+		}
+
+		return nil
+	}
+
+	next, err := safeio.Open(path)
+	if err != nil {
+		return nil
+	}
+
+	return &followedLog{file: next}
+}
+
+// read calls fn for each complete line after l.offset. A partial last line
+// is left for the next call.
+func (l *followedLog) read(fn func(*LogRecord) error) error {
+	if _, err := l.file.Seek(l.offset, io.SeekStart); err != nil {
+		return fmt.Errorf("seek log file: %w", err)
+	}
+
+	reader := bufio.NewReader(l.file)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+
+			return fmt.Errorf("read log file: %w", err)
+		}
+
+		l.offset += int64(len(line))
+
+		line = bytes.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			continue
+		}
+
+		rec := ParseLogRecord(line)
+		if err := fn(&rec); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package observability
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogFilter_Match(t *testing.T) {
+	line := `{"time":"2026-03-04T05:06:07.123Z","level":"DEBUG","msg":"claimed","component":"worker","event.type":"job.claim","command.path":"mush worker start"}`
+	rec := ParseLogRecord([]byte(line))
+
+	if !rec.Structured || rec.Level != slog.LevelDebug || rec.Message != "claimed" || rec.Attr("component") != "worker" {
+		t.Fatalf("ParseLogRecord() = %+v", rec)
+	}
+
+	tests := []struct {
+		name   string
+		filter LogFilter
+		want   bool
+	}{
+		{name: "empty", filter: LogFilter{}, want: false},
+		{name: "debug", filter: LogFilter{MinLevel: slog.LevelDebug}, want: true},
+		{name: "component", filter: LogFilter{MinLevel: slog.LevelDebug, Components: []string{"Worker"}}, want: true},
+		{name: "other component", filter: LogFilter{MinLevel: slog.LevelDebug, Components: []string{"harness"}}, want: false},
+		{name: "event prefix", filter: LogFilter{MinLevel: slog.LevelDebug, EventTypes: []string{"job"}}, want: true},
+		{name: "partial event prefix", filter: LogFilter{MinLevel: slog.LevelDebug, EventTypes: []string{"jo"}}, want: false},
+		{name: "excluded command", filter: LogFilter{MinLevel: slog.LevelDebug, ExcludeCommand: "mush worker start"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.Match(&rec); got != tt.want {
+				t.Fatalf("Match() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	text := ParseLogRecord([]byte("time=2026-03-04 level=INFO msg=hello"))
+	if text.Structured {
+		t.Fatal("ParseLogRecord() on a text line is structured")
+	}
+
+	if !(&LogFilter{MinLevel: slog.LevelDebug}).Match(&text) {
+		t.Fatal("Match() dropped a text line with no filter")
+	}
+
+	if (&LogFilter{MinLevel: slog.LevelDebug, Components: []string{"worker"}}).Match(&text) {
+		t.Fatal("Match() kept a text line with a component filter")
+	}
+}
+
+func TestTailLog_LastLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mush.log")
+	writeLogLines(t, path, "one", "two", "three", "four")
+
+	// A partial line being written is not read.
+	appendLog(t, path, `{"level":"INFO","msg":"fi`)
+
+	got := tailMessages(t, path, &TailOptions{Lines: 2})
+	if strings.Join(got, ",") != "three,four" {
+		t.Fatalf("TailLog() = %v, want the last two lines", got)
+	}
+
+	got = tailMessages(t, path, &TailOptions{Lines: -1, Filter: LogFilter{EventTypes: []string{"test.odd"}}})
+	if strings.Join(got, ",") != "one,three" {
+		t.Fatalf("TailLog() = %v, want every matching line", got)
+	}
+}
+
+func TestTailLog_FollowsAcrossRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mush.log")
+	writeLogLines(t, path, "old")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var (
+		mu  sync.Mutex
+		got []string
+	)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- TailLog(ctx, path, &TailOptions{Lines: -1, Follow: true}, func(rec *LogRecord) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			got = append(got, rec.Message)
+
+			return nil
+		})
+	}()
+
+	waitForMessages := func(want string) {
+		t.Helper()
+
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			mu.Lock()
+			joined := strings.Join(got, ",")
+			mu.Unlock()
+
+			if joined == want {
+				return
+			}
+
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		t.Fatalf("followed messages = %v, want %s", got, want)
+	}
+
+	appendLog(t, path, `{"level":"INFO","msg":"appended"}`+"\n")
+	waitForMessages("old,appended")
+
+	// The worker keeps writing to the rotated file; new commands write to
+	// a fresh one.
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+
+	writeLogLines(t, path, "fresh")
+	waitForMessages("old,appended,fresh")
+
+	appendLog(t, path+".1", `{"level":"INFO","msg":"rotated"}`+"\n")
+	waitForMessages("old,appended,fresh,rotated")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("TailLog() error = %v", err)
+	}
+}
+
+func tailMessages(t *testing.T, path string, opts *TailOptions) []string {
+	t.Helper()
+
+	var got []string
+
+	err := TailLog(t.Context(), path, opts, func(rec *LogRecord) error {
+		got = append(got, rec.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("TailLog() error = %v", err)
+	}
+
+	return got
+}
+
+func writeLogLines(t *testing.T, path string, messages ...string) {
+	t.Helper()
+
+	var b strings.Builder
+
+	for i, msg := range messages {
+		event := "test.even"
+		if i%2 == 0 {
+			event = "test.odd"
+		}
+
+		b.WriteString(`{"time":"2026-03-04T05:06:07Z","level":"INFO","msg":"` + msg + `","event.type":"` + event + `"}` + "\n")
+	}
+
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("write log: %v", err)
+	}
+}
+
+func appendLog(t *testing.T, path, data string) {
+	t.Helper()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+
+	defer file.Close()
+
+	if _, err := file.WriteString(data); err != nil {
+		t.Fatalf("append log: %v", err)
+	}
+}
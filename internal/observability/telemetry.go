@@ -9,6 +9,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
@@ -130,6 +131,12 @@ func Tracer(name string) trace.Tracer {
 	return otel.GetTracerProvider().Tracer(name)
 }
 
+// Meter returns a named meter from the global MeterProvider. Instruments
+// record nothing until a provider is installed.
+func Meter(name string) metric.Meter {
+	return otel.GetMeterProvider().Meter(name)
+}
+
 // IsTelemetryEnabled checks the OTEL_ENABLED env var.
 func IsTelemetryEnabled() bool {
 	v := strings.ToLower(strings.TrimSpace(os.Getenv("OTEL_ENABLED")))
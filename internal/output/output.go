@@ -7,11 +7,14 @@
 //   - Golden file testing
 //   - Colored output with TTY detection
 //   - Spinner animations for long operations
+//
+// Everything a Writer emits goes through a Sink: the terminal sink by
+// default, a JSON stream in --json mode, a quiet sink in --quiet mode, or a
+// BufferSink installed by tests.
 package output
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -35,6 +38,7 @@ type Writer struct {
 	Quiet    bool
 	NoInput  bool
 	terminal *terminal.Info
+	sink     Sink
 
 	// Color functions
 	successColor *color.Color
@@ -82,6 +86,16 @@ func (w *Writer) WithContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, contextKey{}, w)
 }
 
+// NewCapture returns a Writer that records all output in the returned
+// BufferSink, for tests that assert on what a command printed.
+func NewCapture() (*Writer, *BufferSink) {
+	buf := &BufferSink{}
+	w := newWriter(buf, buf, &terminal.Info{NoColor: true, Width: 80, Height: 24})
+	w.sink = buf
+
+	return w, buf
+}
+
 // FromContext retrieves the Writer from context, or returns Default().
 func FromContext(ctx context.Context) *Writer {
 	if w, ok := ctx.Value(contextKey{}).(*Writer); ok {
@@ -96,6 +110,26 @@ func (w *Writer) Terminal() *terminal.Info {
 	return w.terminal
 }
 
+// SetSink routes all output through sink, overriding the sink implied by
+// the JSON and Quiet flags. A nil sink restores the default selection.
+func (w *Writer) SetSink(sink Sink) {
+	w.sink = sink
+}
+
+// Sink returns the sink output is currently routed through.
+func (w *Writer) Sink() Sink {
+	switch {
+	case w.sink != nil:
+		return w.sink
+	case w.Quiet:
+		return quietSink{w: w}
+	case w.JSON:
+		return jsonStreamSink{w: w}
+	default:
+		return terminalSink{w: w}
+	}
+}
+
 // SetNoColor disables colored output.
 func (w *Writer) SetNoColor(disabled bool) {
 	w.terminal.ForceFlag = disabled
@@ -106,28 +140,17 @@ func (w *Writer) SetNoColor(disabled bool) {
 
 // Print writes to stdout (respects quiet mode).
 func (w *Writer) Print(format string, args ...interface{}) {
-	if !w.Quiet {
-		fmt.Fprintf(w.Out, format, args...)
-	}
+	w.Sink().Text(fmt.Sprintf(format, args...))
 }
 
 // Println writes a line to stdout (respects quiet mode).
 func (w *Writer) Println(args ...interface{}) {
-	if !w.Quiet {
-		fmt.Fprintln(w.Out, args...)
-	}
+	w.Sink().Text(fmt.Sprintln(args...))
 }
 
 // PrintJSON outputs structured data as JSON.
 func (w *Writer) PrintJSON(v interface{}) error {
-	enc := json.NewEncoder(w.Out)
-	enc.SetIndent("", "  ")
-
-	if err := enc.Encode(v); err != nil {
-		return fmt.Errorf("encode json output: %w", err)
-	}
-
-	return nil
+	return w.Sink().JSON(v) //nolint:wrapcheck // sinks wrap their own errors
 }
 
 // Error writes to stderr.
@@ -140,18 +163,17 @@ func (w *Writer) Errorln(args ...interface{}) {
 	fmt.Fprintln(w.Err, args...)
 }
 
-// Write implements io.Writer, writing to Out.
+// Write implements io.Writer, writing to Out (respects quiet mode).
 func (w *Writer) Write(p []byte) (n int, err error) {
-	if w.Quiet {
-		return len(p), nil
-	}
+	w.Sink().Text(string(p))
 
-	written, writeErr := w.Out.Write(p)
-	if writeErr != nil {
-		return written, fmt.Errorf("write output: %w", writeErr)
-	}
+	return len(p), nil
+}
 
-	return written, nil
+// Raw returns the underlying stdout writer for output that must not be
+// altered or suppressed, such as generated shell completion scripts.
+func (w *Writer) Raw() io.Writer {
+	return w.Out
 }
 
 // Debug emits a structured debug log record.
@@ -170,52 +192,28 @@ func (w *Writer) writeStatus(writer io.Writer, tone *color.Color, prefix, messag
 
 // Success writes a success message with a checkmark.
 func (w *Writer) Success(format string, args ...interface{}) {
-	if w.Quiet {
-		return
-	}
-
-	msg := fmt.Sprintf(format, args...)
-	w.writeStatus(w.Err, w.successColor, CheckMark, msg)
+	w.Sink().Status(LevelSuccess, fmt.Sprintf(format, args...))
 }
 
-// Failure writes an error message with an X mark.
+// Failure writes an error message with an X mark. Failures are shown even
+// in quiet mode.
 func (w *Writer) Failure(format string, args ...interface{}) {
-	msg := fmt.Sprintf(format, args...)
-	w.writeStatus(w.Err, w.errorColor, XMark, msg)
+	w.Sink().Status(LevelError, fmt.Sprintf(format, args...))
 }
 
 // Warning writes a warning message.
 func (w *Writer) Warning(format string, args ...interface{}) {
-	if w.Quiet {
-		return
-	}
-
-	msg := fmt.Sprintf(format, args...)
-	w.writeStatus(w.Err, w.warningColor, WarningMark, msg)
+	w.Sink().Status(LevelWarning, fmt.Sprintf(format, args...))
 }
 
 // Info writes an info message.
 func (w *Writer) Info(format string, args ...interface{}) {
-	if w.Quiet {
-		return
-	}
-
-	msg := fmt.Sprintf(format, args...)
-	w.writeStatus(w.Err, w.infoColor, InfoMark, msg)
+	w.Sink().Status(LevelInfo, fmt.Sprintf(format, args...))
 }
 
 // Muted writes muted/gray text.
 func (w *Writer) Muted(format string, args ...interface{}) {
-	if w.Quiet {
-		return
-	}
-
-	msg := fmt.Sprintf(format, args...)
-	if w.terminal.ColorEnabled() {
-		w.mutedColor.Fprintln(w.Err, msg)
-	} else {
-		fmt.Fprintln(w.Err, msg)
-	}
+	w.Sink().Status(LevelMuted, fmt.Sprintf(format, args...))
 }
 
 // Status symbols.
@@ -227,8 +225,13 @@ const (
 )
 
 // Spinner creates a new spinner for long operations.
-// Returns nil if spinners are disabled (non-TTY or quiet mode).
+// The spinner is disabled on non-TTYs and in quiet mode, and silent in JSON
+// mode or with an explicit sink so progress text never mixes into results.
 func (w *Writer) Spinner(message string) *Spinner {
+	if w.JSON || w.sink != nil {
+		return &Spinner{disabled: true, silent: true, message: message, writer: w}
+	}
+
 	if w.Quiet || !w.terminal.SpinnersEnabled() {
 		return &Spinner{disabled: true, message: message, writer: w}
 	}
@@ -250,12 +253,16 @@ type Spinner struct {
 	message  string
 	writer   *Writer
 	disabled bool
+	silent   bool
 }
 
 // Start begins the spinner animation.
 func (s *Spinner) Start() {
 	if s.disabled {
-		s.writer.Print("%s... ", s.message)
+		if !s.silent {
+			s.writer.Print("%s... ", s.message)
+		}
+
 		return
 	}
 
@@ -274,7 +281,9 @@ func (s *Spinner) Stop() {
 // StopWithSuccess stops spinner and shows success message.
 func (s *Spinner) StopWithSuccess(message string) {
 	if s.disabled {
-		s.writer.Println("done")
+		if !s.silent {
+			s.writer.Println("done")
+		}
 
 		if message != "" {
 			s.writer.Success("%s", message)
@@ -293,7 +302,9 @@ func (s *Spinner) StopWithSuccess(message string) {
 // StopWithFailure stops spinner and shows failure message.
 func (s *Spinner) StopWithFailure(message string) {
 	if s.disabled {
-		s.writer.Println("failed")
+		if !s.silent {
+			s.writer.Println("failed")
+		}
 
 		if message != "" {
 			s.writer.Failure("%s", message)
@@ -312,7 +323,9 @@ func (s *Spinner) StopWithFailure(message string) {
 // StopWithWarning stops spinner and shows warning message.
 func (s *Spinner) StopWithWarning(message string) {
 	if s.disabled {
-		s.writer.Println("warning")
+		if !s.silent {
+			s.writer.Println("warning")
+		}
 
 		if message != "" {
 			s.writer.Warning("%s", message)
@@ -328,10 +341,13 @@ func (s *Spinner) StopWithWarning(message string) {
 	}
 }
 
-// UpdateMessage changes the spinner message.
+// UpdateMessage changes the spinner message. It is safe to call while the
+// spinner is animating.
 func (s *Spinner) UpdateMessage(message string) {
 	s.message = message
 	if !s.disabled {
+		s.spinner.Lock()
 		s.spinner.Suffix = " " + message
+		s.spinner.Unlock()
 	}
 }
@@ -0,0 +1,185 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// progressRedrawInterval throttles intermediate progress updates.
+const progressRedrawInterval = 100 * time.Millisecond
+
+// progressBarWidth is the number of cells in a rendered progress bar.
+const progressBarWidth = 24
+
+// ProgressKind identifies a progress event.
+type ProgressKind string
+
+// Progress event kinds.
+const (
+	ProgressStart  ProgressKind = "progress.start"
+	ProgressUpdate ProgressKind = "progress.update"
+	ProgressDone   ProgressKind = "progress.done"
+	ProgressFail   ProgressKind = "progress.fail"
+)
+
+// ProgressEvent describes a change in a long operation's progress. Total is
+// zero for step lists; Steps is zero for determinate bars.
+type ProgressEvent struct {
+	Kind    ProgressKind `json:"event"`
+	Label   string       `json:"label"`
+	Current int64        `json:"current,omitempty"`
+	Total   int64        `json:"total,omitempty"`
+	Step    int          `json:"step,omitempty"`
+	Steps   int          `json:"steps,omitempty"`
+	Message string       `json:"message,omitempty"`
+}
+
+// Progress reports the progress of a long operation: a determinate bar from
+// Writer.Progress or a numbered step list from Writer.Steps. On a terminal it
+// redraws a single stderr line; otherwise it degrades to plain lines, or to
+// JSON events in JSON mode.
+type Progress struct {
+	sink Sink
+	now  func() time.Time
+
+	mu       sync.Mutex
+	event    ProgressEvent
+	lastDraw time.Time
+	finished bool
+}
+
+// Progress starts a determinate progress bar counting up to total.
+func (w *Writer) Progress(label string, total int64) *Progress {
+	p := &Progress{sink: w.Sink(), now: time.Now}
+	p.event = ProgressEvent{Label: label, Total: total}
+	p.emit(ProgressStart)
+
+	return p
+}
+
+// Steps starts a numbered list of steps. Call Step to begin each one.
+func (w *Writer) Steps(steps int) *Progress {
+	return &Progress{sink: w.Sink(), now: time.Now, event: ProgressEvent{Steps: steps}}
+}
+
+// Step begins the next step of a step list.
+func (p *Progress) Step(label string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+
+	p.event.Step++
+	p.event.Label = label
+	p.emit(ProgressStart)
+}
+
+// Add advances a progress bar by n.
+func (p *Progress) Add(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.set(p.event.Current + n)
+}
+
+// Set moves a progress bar to current.
+func (p *Progress) Set(current int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.set(current)
+}
+
+// Write implements io.Writer so a progress bar can count bytes copied
+// through io.TeeReader or io.MultiWriter.
+func (p *Progress) Write(b []byte) (int, error) {
+	p.Add(int64(len(b)))
+
+	return len(b), nil
+}
+
+// Done finishes the operation, reporting message as a success if non-empty.
+func (p *Progress) Done(message string) {
+	p.finish(ProgressDone, message)
+}
+
+// Fail finishes the operation, reporting message as a failure.
+func (p *Progress) Fail(message string) {
+	p.finish(ProgressFail, message)
+}
+
+func (p *Progress) set(current int64) {
+	if p.finished {
+		return
+	}
+
+	p.event.Current = current
+
+	now := p.now()
+	if current < p.event.Total && now.Sub(p.lastDraw) < progressRedrawInterval {
+		return
+	}
+
+	p.lastDraw = now
+	p.emit(ProgressUpdate)
+}
+
+func (p *Progress) finish(kind ProgressKind, message string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.finished {
+		return
+	}
+
+	p.finished = true
+	p.event.Message = message
+	p.emit(kind)
+}
+
+func (p *Progress) emit(kind ProgressKind) {
+	event := p.event
+	event.Kind = kind
+
+	p.sink.Progress(event)
+}
+
+// renderProgress formats event as a single terminal line.
+func renderProgress(event ProgressEvent, width int) string {
+	var line string
+
+	switch {
+	case event.Steps > 0:
+		line = fmt.Sprintf("[%d/%d] %s...", event.Step, event.Steps, event.Label)
+	case event.Total > 0:
+		filled := int(min(event.Current, event.Total) * progressBarWidth / event.Total)
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		line = fmt.Sprintf("%s [%s] %3d%% (%d/%d)", event.Label, bar, event.Current*100/event.Total, event.Current, event.Total)
+	default:
+		line = event.Label + "..."
+	}
+
+	if runes := []rune(line); width > 0 && len(runes) >= width {
+		line = string(runes[:width-1])
+	}
+
+	return line
+}
+
+// plainProgressLine returns the line a non-terminal sink prints for event,
+// or "" if the event is only shown on terminals.
+func plainProgressLine(event ProgressEvent) string {
+	if event.Kind != ProgressStart {
+		return ""
+	}
+
+	if event.Steps > 0 {
+		return fmt.Sprintf("[%d/%d] %s", event.Step, event.Steps, event.Label)
+	}
+
+	return event.Label + "..."
+}
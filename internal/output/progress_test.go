@@ -0,0 +1,155 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProgress_NonTTYPrintsPlainLines(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+
+	steps := w.Steps(2)
+	steps.Step("Resolve")
+	steps.Step("Download")
+	steps.Done("Finished")
+
+	bar := w.Progress("Copying", 3)
+	bar.Add(3)
+	bar.Fail("Copy failed")
+
+	want := "[1/2] Resolve\n[2/2] Download\n" + CheckMark + " Finished\nCopying...\n" + XMark + " Copy failed\n"
+	if got := errBuf.String(); got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+
+	if outBuf.Len() > 0 {
+		t.Errorf("progress should not write to stdout, got %q", outBuf.String())
+	}
+}
+
+func TestProgress_TTYRedrawsOneLine(t *testing.T) {
+	var errBuf bytes.Buffer
+
+	w := NewWriter(&bytes.Buffer{}, &errBuf, testTerminal())
+	w.terminal.IsTTY = true
+	w.terminal.NoColor = false
+	w.terminal.ForceFlag = true
+
+	bar := w.Progress("Downloading", 4)
+	bar.Add(4)
+	bar.Done("")
+
+	got := errBuf.String()
+	if strings.Contains(got, "\n") {
+		t.Errorf("progress bar should redraw in place, got %q", got)
+	}
+
+	if !strings.Contains(got, "100% (4/4)") {
+		t.Errorf("progress bar never reached 100%%: %q", got)
+	}
+
+	if !strings.HasSuffix(got, "\r\x1b[K") {
+		t.Errorf("progress bar line should be cleared when done: %q", got)
+	}
+}
+
+func TestProgress_JSONModeEmitsEvents(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+	w.JSON = true
+
+	bar := w.Progress("Downloading", 2)
+	bar.Add(2)
+	bar.Done("Downloaded")
+
+	var kinds []ProgressKind
+
+	for _, line := range strings.Split(strings.TrimSpace(errBuf.String()), "\n") {
+		var event ProgressEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("stderr line is not JSON: %q", line)
+		}
+
+		kinds = append(kinds, event.Kind)
+	}
+
+	want := []ProgressKind{ProgressStart, ProgressUpdate, ProgressDone}
+	if len(kinds) != len(want) || kinds[0] != want[0] || kinds[1] != want[1] || kinds[2] != want[2] {
+		t.Errorf("events = %v, want %v", kinds, want)
+	}
+
+	if outBuf.Len() > 0 {
+		t.Errorf("progress should not write to stdout in JSON mode, got %q", outBuf.String())
+	}
+}
+
+func TestProgress_ThrottlesIntermediateUpdates(t *testing.T) {
+	w, buf := NewCapture()
+
+	now := time.Unix(0, 0)
+	bar := w.Progress("Copying", 100)
+	bar.now = func() time.Time { return now }
+
+	bar.Add(10)
+	bar.Add(10)
+
+	now = now.Add(progressRedrawInterval)
+	bar.Add(10)
+	bar.Add(70)
+	bar.Done("")
+
+	var currents []int64
+
+	for _, event := range buf.ProgressEvents() {
+		if event.Kind == ProgressUpdate {
+			currents = append(currents, event.Current)
+		}
+	}
+
+	// The first update draws, the second is throttled, and the final one
+	// always draws.
+	want := []int64{10, 30, 100}
+	if len(currents) != len(want) || currents[0] != want[0] || currents[1] != want[1] || currents[2] != want[2] {
+		t.Errorf("updates = %v, want %v", currents, want)
+	}
+}
+
+func TestRenderProgress(t *testing.T) {
+	tests := []struct {
+		name  string
+		event ProgressEvent
+		width int
+		want  string
+	}{
+		{
+			name:  "step",
+			event: ProgressEvent{Label: "Resolve", Step: 1, Steps: 3},
+			want:  "[1/3] Resolve...",
+		},
+		{
+			name:  "bar",
+			event: ProgressEvent{Label: "Copy", Current: 1, Total: 2},
+			want:  "Copy [============            ]  50% (1/2)",
+		},
+		{
+			name:  "truncated to width",
+			event: ProgressEvent{Label: "Resolving bundle", Step: 1, Steps: 3},
+			width: 12,
+			want:  "[1/3] Resol",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderProgress(tt.event, tt.width); got != tt.want {
+				t.Errorf("renderProgress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
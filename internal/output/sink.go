@@ -0,0 +1,292 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+// Level classifies a status message.
+type Level string
+
+// Status message levels.
+const (
+	LevelSuccess Level = "success"
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+	LevelInfo    Level = "info"
+	LevelMuted   Level = "muted"
+)
+
+// Sink renders everything a Writer emits. Writer picks the terminal,
+// JSON-stream, or quiet sink from its JSON and Quiet flags unless one has
+// been installed with SetSink.
+type Sink interface {
+	// Text writes primary human-readable output.
+	Text(text string)
+	// Status writes a status message such as a success or warning line.
+	Status(level Level, message string)
+	// JSON writes a structured result document.
+	JSON(v any) error
+	// Progress reports a change in a long operation's progress.
+	Progress(event ProgressEvent)
+}
+
+// terminalSink writes text to stdout and colored status lines to stderr.
+type terminalSink struct {
+	w *Writer
+}
+
+func (s terminalSink) Text(text string) {
+	_, _ = io.WriteString(s.w.Out, text)
+}
+
+func (s terminalSink) Status(level Level, message string) {
+	w := s.w
+
+	switch level {
+	case LevelSuccess:
+		w.writeStatus(w.Err, w.successColor, CheckMark, message)
+	case LevelError:
+		w.writeStatus(w.Err, w.errorColor, XMark, message)
+	case LevelWarning:
+		w.writeStatus(w.Err, w.warningColor, WarningMark, message)
+	case LevelInfo:
+		w.writeStatus(w.Err, w.infoColor, InfoMark, message)
+	case LevelMuted:
+		if w.terminal.ColorEnabled() {
+			w.mutedColor.Fprintln(w.Err, message)
+		} else {
+			fmt.Fprintln(w.Err, message)
+		}
+	}
+}
+
+func (s terminalSink) JSON(v any) error {
+	return encodeJSON(s.w.Out, v)
+}
+
+func (s terminalSink) Progress(event ProgressEvent) {
+	w := s.w
+
+	if w.terminal.SpinnersEnabled() {
+		fmt.Fprint(w.Err, "\r"+ansi.ClearToEOL)
+
+		if event.Kind == ProgressStart || event.Kind == ProgressUpdate {
+			fmt.Fprint(w.Err, renderProgress(event, w.terminal.Width))
+		}
+	} else if line := plainProgressLine(event); line != "" {
+		fmt.Fprintln(w.Err, line)
+	}
+
+	finishProgress(s, event)
+}
+
+// jsonStreamSink keeps stdout for the JSON document and reports status
+// messages as one JSON object per line on stderr, so scripts can parse
+// both streams.
+type jsonStreamSink struct {
+	w *Writer
+}
+
+// StatusRecord is the JSON-stream form of a status message.
+type StatusRecord struct {
+	Level   Level  `json:"level"`
+	Message string `json:"message"`
+}
+
+func (s jsonStreamSink) Text(text string) {
+	_, _ = io.WriteString(s.w.Out, text)
+}
+
+func (s jsonStreamSink) Status(level Level, message string) {
+	line, err := json.Marshal(StatusRecord{Level: level, Message: message})
+	if err != nil {
+		return
+	}
+
+	_, _ = s.w.Err.Write(append(line, '\n'))
+}
+
+func (s jsonStreamSink) JSON(v any) error {
+	return encodeJSON(s.w.Out, v)
+}
+
+func (s jsonStreamSink) Progress(event ProgressEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	_, _ = s.w.Err.Write(append(line, '\n'))
+}
+
+// quietSink drops text and everything but errors; JSON results still print.
+type quietSink struct {
+	w *Writer
+}
+
+func (quietSink) Text(string) {}
+
+func (s quietSink) Status(level Level, message string) {
+	if level != LevelError {
+		return
+	}
+
+	if s.w.JSON {
+		jsonStreamSink(s).Status(level, message)
+		return
+	}
+
+	terminalSink(s).Status(level, message)
+}
+
+func (s quietSink) JSON(v any) error {
+	return encodeJSON(s.w.Out, v)
+}
+
+func (s quietSink) Progress(event ProgressEvent) {
+	finishProgress(s, event)
+}
+
+// BufferSink records output in memory. Tests install it with NewCapture to
+// assert on what a command printed without parsing terminal formatting.
+type BufferSink struct {
+	mu       sync.Mutex
+	text     bytes.Buffer
+	statuses []StatusRecord
+	json     []json.RawMessage
+	progress []ProgressEvent
+}
+
+// Text records primary output.
+func (b *BufferSink) Text(text string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.text.WriteString(text)
+}
+
+// Status records a status message.
+func (b *BufferSink) Status(level Level, message string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.statuses = append(b.statuses, StatusRecord{Level: level, Message: message})
+}
+
+// JSON records a structured result document.
+func (b *BufferSink) JSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode json output: %w", err)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.json = append(b.json, data)
+
+	return nil
+}
+
+// Progress records a progress event.
+func (b *BufferSink) Progress(event ProgressEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.progress = append(b.progress, event)
+}
+
+// Write records raw writes, such as Error output, as text.
+func (b *BufferSink) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.text.Write(p) //nolint:wrapcheck // bytes.Buffer writes never fail
+}
+
+// String returns all recorded text.
+func (b *BufferSink) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.text.String()
+}
+
+// Statuses returns the recorded status messages in order.
+func (b *BufferSink) Statuses() []StatusRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]StatusRecord(nil), b.statuses...)
+}
+
+// Messages returns the recorded status messages at level.
+func (b *BufferSink) Messages(level Level) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var messages []string
+
+	for _, status := range b.statuses {
+		if status.Level == level {
+			messages = append(messages, status.Message)
+		}
+	}
+
+	return messages
+}
+
+// JSONDocuments returns the recorded JSON results, compactly encoded.
+func (b *BufferSink) JSONDocuments() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	docs := make([]string, 0, len(b.json))
+	for _, doc := range b.json {
+		docs = append(docs, strings.TrimSpace(string(doc)))
+	}
+
+	return docs
+}
+
+// ProgressEvents returns the recorded progress events in order.
+func (b *BufferSink) ProgressEvents() []ProgressEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]ProgressEvent(nil), b.progress...)
+}
+
+// finishProgress reports the closing message of a finished operation as a
+// status message on sink.
+func finishProgress(sink Sink, event ProgressEvent) {
+	if event.Message == "" {
+		return
+	}
+
+	switch event.Kind {
+	case ProgressDone:
+		sink.Status(LevelSuccess, event.Message)
+	case ProgressFail:
+		sink.Status(LevelError, event.Message)
+	case ProgressStart, ProgressUpdate:
+	}
+}
+
+func encodeJSON(out io.Writer, v any) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode json output: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,146 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestJSONMode_StatusMessagesStreamAsJSON(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+	w.JSON = true
+
+	w.Success("Saved %s", "config")
+	w.Warning("Disk almost full")
+	w.Failure("Upload failed")
+
+	if outBuf.Len() > 0 {
+		t.Fatalf("status messages should not write to stdout in JSON mode, got %q", outBuf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(errBuf.String()), "\n")
+
+	want := []StatusRecord{
+		{Level: LevelSuccess, Message: "Saved config"},
+		{Level: LevelWarning, Message: "Disk almost full"},
+		{Level: LevelError, Message: "Upload failed"},
+	}
+
+	if len(lines) != len(want) {
+		t.Fatalf("got %d stderr lines, want %d: %q", len(lines), len(want), errBuf.String())
+	}
+
+	for i, line := range lines {
+		var got StatusRecord
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d is not JSON: %q", i, line)
+		}
+
+		if got != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestJSONMode_SpinnerIsSilent(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+	w.JSON = true
+
+	s := w.Spinner("Loading")
+	s.Start()
+	s.StopWithSuccess("")
+
+	if err := w.PrintJSON(map[string]int{"count": 1}); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	var doc map[string]int
+	if err := json.Unmarshal(outBuf.Bytes(), &doc); err != nil {
+		t.Fatalf("stdout is not a single JSON document: %q", outBuf.String())
+	}
+
+	if errBuf.Len() > 0 {
+		t.Errorf("spinner wrote to stderr in JSON mode: %q", errBuf.String())
+	}
+}
+
+func TestQuietMode_KeepsFailuresAndJSON(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+	w.Quiet = true
+
+	w.Print("hidden")
+	w.Info("hidden")
+	w.Failure("shown")
+
+	if err := w.PrintJSON(true); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	if got := outBuf.String(); got != "true\n" {
+		t.Errorf("stdout = %q, want only the JSON result", got)
+	}
+
+	if got := errBuf.String(); got != XMark+" shown\n" {
+		t.Errorf("stderr = %q, want only the failure", got)
+	}
+}
+
+func TestNewCapture(t *testing.T) {
+	w, buf := NewCapture()
+
+	w.Print("hello %s\n", "world")
+	w.Error("oops\n")
+	w.Success("done")
+	w.Warning("careful")
+	w.Success("again")
+
+	if err := w.PrintJSON(map[string]string{"name": "mush"}); err != nil {
+		t.Fatalf("PrintJSON() error = %v", err)
+	}
+
+	if got := buf.String(); got != "hello world\noops\n" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if got := buf.Messages(LevelSuccess); !slices.Equal(got, []string{"done", "again"}) {
+		t.Errorf("Messages(success) = %q", got)
+	}
+
+	if got := buf.Statuses(); len(got) != 3 || got[1] != (StatusRecord{Level: LevelWarning, Message: "careful"}) {
+		t.Errorf("Statuses() = %+v", got)
+	}
+
+	if got := buf.JSONDocuments(); !slices.Equal(got, []string{`{"name":"mush"}`}) {
+		t.Errorf("JSONDocuments() = %q", got)
+	}
+}
+
+func TestSetSink_OverridesModeFlags(t *testing.T) {
+	var outBuf, errBuf bytes.Buffer
+
+	w := NewWriter(&outBuf, &errBuf, testTerminal())
+	w.Quiet = true
+
+	buf := &BufferSink{}
+	w.SetSink(buf)
+	w.Info("recorded")
+
+	if got := buf.Messages(LevelInfo); !slices.Equal(got, []string{"recorded"}) {
+		t.Errorf("Messages(info) = %q", got)
+	}
+
+	w.SetSink(nil)
+	w.Info("dropped")
+
+	if errBuf.Len() > 0 {
+		t.Errorf("quiet mode should apply again after SetSink(nil), got %q", errBuf.String())
+	}
+}
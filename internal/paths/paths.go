@@ -11,6 +11,14 @@ import (
 
 const appName = "musher"
 
+const (
+	// ProfileEnv selects the config profile, like the --profile flag.
+	ProfileEnv = "MUSH_PROFILE"
+	// DefaultProfile names the settings and credentials outside any named
+	// profile.
+	DefaultProfile = "default"
+)
+
 // rootWithFallback resolves a directory root using a 4-tier priority:
 //  1. Branded env var (e.g., MUSHER_CONFIG_HOME) — must be absolute, silently ignored otherwise
 //  2. MUSHER_HOME umbrella — must be absolute, silently ignored otherwise
@@ -143,6 +151,16 @@ func DefaultLogFile() (string, error) {
 	return filepath.Join(logsDir, "mush.log"), nil
 }
 
+// JobLogsDir returns the directory workers write each job's log to.
+func JobLogsDir() (string, error) {
+	logsDir, err := LogsDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(logsDir, "jobs"), nil
+}
+
 // UpdateStateFile returns the update state file path.
 func UpdateStateFile() (string, error) {
 	root, err := stateRoot()
@@ -153,15 +171,40 @@ func UpdateStateFile() (string, error) {
 	return filepath.Join(root, "update-check.json"), nil
 }
 
-// CredentialFilePath returns the host-scoped credential fallback file path.
-// The hostID should come from HostIDFromURL.
+// RunnerConfigCacheFile returns the host-scoped last-known-good runner config
+// path, kept apart for each named profile. The hostID should come from
+// HostIDFromURL.
+func RunnerConfigCacheFile(hostID string) (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "runner-config", ProfileScoped(hostID)+".json"), nil
+}
+
+// HabitatRunnerConfigCacheFile returns the last-known-good runner config path
+// for a habitat, which carries habitat-scoped providers and credentials on
+// top of the organization-wide ones. The hostID should come from HostIDFromURL.
+func HabitatRunnerConfigCacheFile(hostID, habitatID string) (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "runner-config", ProfileScoped(hostID), sanitizeHostID(habitatID)+".json"), nil
+}
+
+// CredentialFilePath returns the host-scoped credential fallback file path,
+// kept apart for each named profile. The hostID should come from
+// HostIDFromURL.
 func CredentialFilePath(hostID string) (string, error) {
 	root, err := dataRoot()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(root, "credentials", hostID, "api-key"), nil
+	return filepath.Join(root, "credentials", ProfileScoped(hostID), "api-key"), nil
 }
 
 // HistoryDir returns the default transcript history directory.
@@ -174,6 +217,116 @@ func HistoryDir() (string, error) {
 	return filepath.Join(root, "history"), nil
 }
 
+// WorkerRegistryDir returns the directory that records registered workers
+// until they deregister.
+func WorkerRegistryDir() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "workers"), nil
+}
+
+// WorkerControlSocket returns the Unix socket a running worker listens on
+// for `mush worker status` and `mush worker stop`.
+func WorkerControlSocket() (string, error) {
+	root, err := runtimeRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "worker.sock"), nil
+}
+
+// WorkerPIDFile returns the file recording the process ID of the worker
+// `mush worker start --detach` started.
+func WorkerPIDFile() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "run", "worker.pid"), nil
+}
+
+// WorkerConsoleFile returns the file a detached worker's stdout and stderr
+// are written to.
+func WorkerConsoleFile() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "run", "worker.out"), nil
+}
+
+// TelemetryDir returns the directory holding the opt-in usage telemetry
+// queue and anonymous install ID.
+func TelemetryDir() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "telemetry"), nil
+}
+
+// HarnessSupervisionFile returns the path recording how the last worker's
+// harness processes started up, for `mush doctor`.
+func HarnessSupervisionFile() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "harness-supervision.json"), nil
+}
+
+// WorkerStatusFile returns the JSON file a running worker keeps up to date
+// for status bars and other tools that poll it.
+func WorkerStatusFile() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "worker-status.json"), nil
+}
+
+// WorkspaceCacheDir returns the directory holding cached repository clones
+// and per-job worktrees.
+func WorkspaceCacheDir() (string, error) {
+	root, err := stateRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "workspaces"), nil
+}
+
+// ResultSummaryTemplateFile returns the path of the optional user template
+// that renders job result summaries.
+func ResultSummaryTemplateFile() (string, error) {
+	root, err := configRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "templates", "result-summary.tmpl"), nil
+}
+
+// CustomHarnessesDir returns the directory of user-defined shell harness
+// definitions.
+func CustomHarnessesDir() (string, error) {
+	root, err := configRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "harnesses"), nil
+}
+
 // BundleCacheDir returns the bundle cache directory.
 func BundleCacheDir() (string, error) {
 	root, err := cacheRoot()
@@ -184,6 +337,40 @@ func BundleCacheDir() (string, error) {
 	return filepath.Join(root, "bundles"), nil
 }
 
+// IdentityCacheFile returns the host-scoped cached runner identity path,
+// kept apart for each named profile. The hostID should come from
+// HostIDFromURL.
+func IdentityCacheFile(hostID string) (string, error) {
+	root, err := cacheRoot()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(root, "identity", ProfileScoped(hostID)+".json"), nil
+}
+
+// ActiveProfile returns the profile MUSH_PROFILE selects, lowercased like
+// config keys, or "" for the default profile.
+func ActiveProfile() string {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv(ProfileEnv)))
+	if name == DefaultProfile {
+		return ""
+	}
+
+	return name
+}
+
+// ProfileScoped returns id, a host ID or keyring service, suffixed with
+// "@<profile>" under a named profile, so each profile keeps its own
+// credentials for a host.
+func ProfileScoped(id string) string {
+	if profile := ActiveProfile(); profile != "" {
+		return id + "@" + sanitizeHostID(profile)
+	}
+
+	return id
+}
+
 // HostIDFromURL returns a filesystem-safe host identifier from an API URL.
 // Default ports (443 for HTTPS, 80 for HTTP) are omitted.
 // Non-default ports are appended with an underscore separator.
@@ -16,6 +16,7 @@ func clearEnv(t *testing.T) {
 		"MUSHER_CACHE_HOME", "MUSHER_RUNTIME_DIR",
 		"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME",
 		"XDG_CACHE_HOME", "XDG_RUNTIME_DIR",
+		ProfileEnv,
 	} {
 		t.Setenv(env, "")
 	}
@@ -158,6 +159,23 @@ func TestRuntimeRoot_FallsBackToTemp(t *testing.T) {
 	}
 }
 
+func TestWorkerControlSocket_UsesRuntimeRoot(t *testing.T) {
+	clearEnv(t)
+
+	tmp := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", tmp)
+
+	got, err := WorkerControlSocket()
+	if err != nil {
+		t.Fatalf("WorkerControlSocket() error = %v", err)
+	}
+
+	want := filepath.Join(tmp, "musher", "worker.sock")
+	if got != want {
+		t.Fatalf("WorkerControlSocket() = %q, want %q", got, want)
+	}
+}
+
 func TestDerivedPaths(t *testing.T) {
 	clearEnv(t)
 
@@ -190,6 +208,26 @@ func TestDerivedPaths(t *testing.T) {
 		t.Fatalf("UpdateStateFile() = %q, want %q", stateFile, wantState)
 	}
 
+	runnerConfigFile, err := RunnerConfigCacheFile("api.musher.dev")
+	if err != nil {
+		t.Fatalf("RunnerConfigCacheFile() error = %v", err)
+	}
+
+	wantRunnerConfig := filepath.Join(state, "musher", "runner-config", "api.musher.dev.json")
+	if runnerConfigFile != wantRunnerConfig {
+		t.Fatalf("RunnerConfigCacheFile() = %q, want %q", runnerConfigFile, wantRunnerConfig)
+	}
+
+	habitatRunnerConfigFile, err := HabitatRunnerConfigCacheFile("api.musher.dev", "hab-1")
+	if err != nil {
+		t.Fatalf("HabitatRunnerConfigCacheFile() error = %v", err)
+	}
+
+	wantHabitatRunnerConfig := filepath.Join(state, "musher", "runner-config", "api.musher.dev", "hab-1.json")
+	if habitatRunnerConfigFile != wantHabitatRunnerConfig {
+		t.Fatalf("HabitatRunnerConfigCacheFile() = %q, want %q", habitatRunnerConfigFile, wantHabitatRunnerConfig)
+	}
+
 	credFile, err := CredentialFilePath("api.musher.dev")
 	if err != nil {
 		t.Fatalf("CredentialFilePath() error = %v", err)
@@ -210,6 +248,96 @@ func TestDerivedPaths(t *testing.T) {
 		t.Fatalf("HistoryDir() = %q, want %q", historyDir, wantHistory)
 	}
 
+	jobLogsDir, err := JobLogsDir()
+	if err != nil {
+		t.Fatalf("JobLogsDir() error = %v", err)
+	}
+
+	wantJobLogs := filepath.Join(state, "musher", "logs", "jobs")
+	if jobLogsDir != wantJobLogs {
+		t.Fatalf("JobLogsDir() = %q, want %q", jobLogsDir, wantJobLogs)
+	}
+
+	workerRegistryDir, err := WorkerRegistryDir()
+	if err != nil {
+		t.Fatalf("WorkerRegistryDir() error = %v", err)
+	}
+
+	wantWorkerRegistry := filepath.Join(state, "musher", "workers")
+	if workerRegistryDir != wantWorkerRegistry {
+		t.Fatalf("WorkerRegistryDir() = %q, want %q", workerRegistryDir, wantWorkerRegistry)
+	}
+
+	telemetryDir, err := TelemetryDir()
+	if err != nil {
+		t.Fatalf("TelemetryDir() error = %v", err)
+	}
+
+	wantTelemetry := filepath.Join(state, "musher", "telemetry")
+	if telemetryDir != wantTelemetry {
+		t.Fatalf("TelemetryDir() = %q, want %q", telemetryDir, wantTelemetry)
+	}
+
+	supervisionFile, err := HarnessSupervisionFile()
+	if err != nil {
+		t.Fatalf("HarnessSupervisionFile() error = %v", err)
+	}
+
+	wantSupervision := filepath.Join(state, "musher", "harness-supervision.json")
+	if supervisionFile != wantSupervision {
+		t.Fatalf("HarnessSupervisionFile() = %q, want %q", supervisionFile, wantSupervision)
+	}
+
+	statusFile, err := WorkerStatusFile()
+	if err != nil {
+		t.Fatalf("WorkerStatusFile() error = %v", err)
+	}
+
+	wantStatusFile := filepath.Join(state, "musher", "worker-status.json")
+	if statusFile != wantStatusFile {
+		t.Fatalf("WorkerStatusFile() = %q, want %q", statusFile, wantStatusFile)
+	}
+
+	pidFile, err := WorkerPIDFile()
+	if err != nil {
+		t.Fatalf("WorkerPIDFile() error = %v", err)
+	}
+
+	wantPIDFile := filepath.Join(state, "musher", "run", "worker.pid")
+	if pidFile != wantPIDFile {
+		t.Fatalf("WorkerPIDFile() = %q, want %q", pidFile, wantPIDFile)
+	}
+
+	workspaceCacheDir, err := WorkspaceCacheDir()
+	if err != nil {
+		t.Fatalf("WorkspaceCacheDir() error = %v", err)
+	}
+
+	wantWorkspaceCache := filepath.Join(state, "musher", "workspaces")
+	if workspaceCacheDir != wantWorkspaceCache {
+		t.Fatalf("WorkspaceCacheDir() = %q, want %q", workspaceCacheDir, wantWorkspaceCache)
+	}
+
+	summaryTemplate, err := ResultSummaryTemplateFile()
+	if err != nil {
+		t.Fatalf("ResultSummaryTemplateFile() error = %v", err)
+	}
+
+	wantSummaryTemplate := filepath.Join(cfg, "musher", "templates", "result-summary.tmpl")
+	if summaryTemplate != wantSummaryTemplate {
+		t.Fatalf("ResultSummaryTemplateFile() = %q, want %q", summaryTemplate, wantSummaryTemplate)
+	}
+
+	harnessesDir, err := CustomHarnessesDir()
+	if err != nil {
+		t.Fatalf("CustomHarnessesDir() error = %v", err)
+	}
+
+	wantHarnesses := filepath.Join(cfg, "musher", "harnesses")
+	if harnessesDir != wantHarnesses {
+		t.Fatalf("CustomHarnessesDir() = %q, want %q", harnessesDir, wantHarnesses)
+	}
+
 	bundleCacheDir, err := BundleCacheDir()
 	if err != nil {
 		t.Fatalf("BundleCacheDir() error = %v", err)
@@ -219,6 +347,16 @@ func TestDerivedPaths(t *testing.T) {
 	if bundleCacheDir != wantBundleCache {
 		t.Fatalf("BundleCacheDir() = %q, want %q", bundleCacheDir, wantBundleCache)
 	}
+
+	identityFile, err := IdentityCacheFile("api.musher.dev")
+	if err != nil {
+		t.Fatalf("IdentityCacheFile() error = %v", err)
+	}
+
+	wantIdentity := filepath.Join(cache, "musher", "identity", "api.musher.dev.json")
+	if identityFile != wantIdentity {
+		t.Fatalf("IdentityCacheFile() = %q, want %q", identityFile, wantIdentity)
+	}
 }
 
 func TestXDGRelativePathIgnored(t *testing.T) {
@@ -462,3 +600,39 @@ func TestCredentialFilePath_HostScoped(t *testing.T) {
 		t.Fatal("CredentialFilePath() should produce different paths for different hosts")
 	}
 }
+
+func TestCredentialFilePath_ProfileScoped(t *testing.T) {
+	clearEnv(t)
+
+	data := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", data)
+	t.Setenv(ProfileEnv, "staging")
+
+	got, err := CredentialFilePath("api.musher.dev")
+	if err != nil {
+		t.Fatalf("CredentialFilePath() error = %v", err)
+	}
+
+	want := filepath.Join(data, "musher", "credentials", "api.musher.dev@staging", "api-key")
+	if got != want {
+		t.Fatalf("CredentialFilePath() = %q, want %q", got, want)
+	}
+
+	t.Setenv("XDG_STATE_HOME", filepath.Join(data, "state"))
+
+	got, err = HabitatRunnerConfigCacheFile("api.musher.dev", "hab-1")
+	if err != nil {
+		t.Fatalf("HabitatRunnerConfigCacheFile() error = %v", err)
+	}
+
+	want = filepath.Join(data, "state", "musher", "runner-config", "api.musher.dev@staging", "hab-1.json")
+	if got != want {
+		t.Fatalf("HabitatRunnerConfigCacheFile() = %q, want %q", got, want)
+	}
+
+	t.Setenv(ProfileEnv, DefaultProfile)
+
+	if got := ProfileScoped("musher/api.musher.dev"); got != "musher/api.musher.dev" {
+		t.Fatalf("ProfileScoped() = %q under the default profile, want it unchanged", got)
+	}
+}
@@ -22,6 +22,9 @@ var (
 		moduleRoot + "/internal/prompt":  true,
 		moduleRoot + "/internal/output":  true,
 		moduleRoot + "/internal/bundle":  true,
+		moduleRoot + "/internal/engine":  true,
+		moduleRoot + "/internal/bench":   true,
+		moduleRoot + "/internal/resolve": true,
 	}
 
 	platformCore = map[string]bool{
@@ -38,12 +41,17 @@ var (
 		moduleRoot + "/internal/tui":           true,
 		moduleRoot + "/internal/observability": true,
 		moduleRoot + "/internal/transcript":    true,
+		moduleRoot + "/internal/telemetry":     true,
 		moduleRoot + "/internal/testutil":      true,
 		moduleRoot + "/internal/safeio":        true,
 		moduleRoot + "/internal/executil":      true,
 		moduleRoot + "/internal/devhooks":      true,
 		moduleRoot + "/internal/policy":        true,
 		moduleRoot + "/internal/validate":      true,
+		moduleRoot + "/internal/workspace":     true,
+		moduleRoot + "/internal/secrets":       true,
+		moduleRoot + "/internal/netrecord":     true,
+		moduleRoot + "/internal/metrics":       true,
 	}
 
 	presentationPkgs = map[string]bool{
@@ -415,10 +423,12 @@ func TestNoCrossLayerFeatureImports(t *testing.T) {
 	allowed := map[string]map[string]bool{
 		moduleRoot + "/internal/harness": {
 			moduleRoot + "/internal/output": true,
+			moduleRoot + "/internal/engine": true,
 		},
 		moduleRoot + "/internal/wizard": {
-			moduleRoot + "/internal/prompt": true,
-			moduleRoot + "/internal/output": true,
+			moduleRoot + "/internal/prompt":  true,
+			moduleRoot + "/internal/output":  true,
+			moduleRoot + "/internal/resolve": true,
 		},
 		moduleRoot + "/internal/bundle": {
 			moduleRoot + "/internal/output":  true,
@@ -430,6 +440,17 @@ func TestNoCrossLayerFeatureImports(t *testing.T) {
 		moduleRoot + "/internal/prompt": {
 			moduleRoot + "/internal/output": true,
 		},
+		moduleRoot + "/internal/engine": {
+			moduleRoot + "/internal/harness": true,
+		},
+		moduleRoot + "/internal/bench": {
+			moduleRoot + "/internal/engine":  true,
+			moduleRoot + "/internal/harness": true,
+		},
+		moduleRoot + "/internal/resolve": {
+			moduleRoot + "/internal/output": true,
+			moduleRoot + "/internal/prompt": true,
+		},
 	}
 
 	pkgs := loadAllPackages(t)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -14,8 +15,6 @@ import (
 	"golang.org/x/term"
 )
 
-const escapeSequenceBufferSize = 3
-
 // Prompter handles interactive prompts.
 type Prompter struct {
 	out    *output.Writer
@@ -114,8 +113,25 @@ func (p *Prompter) Password(prompt string) (string, error) {
 	}
 }
 
-// Select prompts the user to select from a list of options.
+// Select prompts the user to select from a list of options. On a terminal
+// the list can be fuzzy-filtered and navigated with the arrow keys;
+// otherwise the user enters the option's number.
 func (p *Prompter) Select(message string, options []string) (int, error) {
+	if shouldUseArrowKeySelection() {
+		sel := newSelector(message, labelOptions(options), false, nil)
+
+		err := runSelector(sel, p.out.Raw())
+		if err == nil {
+			idx, _ := sel.current()
+			return idx, nil
+		}
+
+		if IsCanceled(err) {
+			return -1, err
+		}
+		// Fall through to numbered input on any error (e.g. raw mode failure).
+	}
+
 	p.out.Println(message)
 
 	for i, opt := range options {
@@ -147,10 +163,104 @@ func (p *Prompter) Select(message string, options []string) (int, error) {
 	}
 }
 
+// MultiSelect prompts the user to choose any number of options, starting
+// with preselected chosen. It returns the chosen indices in option order.
+// On a terminal, Space toggles the highlighted option; otherwise the user
+// enters a comma-separated list of numbers.
+func (p *Prompter) MultiSelect(message string, options []SelectOption, preselected []int) ([]int, error) {
+	if shouldUseArrowKeySelection() {
+		sel := newSelector(message, options, true, preselected)
+
+		err := runSelector(sel, p.out.Raw())
+		if err == nil {
+			return sel.selected(), nil
+		}
+
+		if IsCanceled(err) {
+			return nil, err
+		}
+		// Fall through to numbered input on any error (e.g. raw mode failure).
+	}
+
+	p.out.Println(message)
+
+	for i, opt := range options {
+		mark := " "
+		if slices.Contains(preselected, i) {
+			mark = "x"
+		}
+
+		p.out.Print("  [%d] [%s] %s\n", i+1, mark, opt.Label)
+	}
+
+	p.out.Println()
+
+	for {
+		p.out.Print("Select numbers separated by commas, 'all', or Enter to keep [x]: ")
+
+		input, err := p.reader.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		indices, ok := parseMultiSelection(strings.TrimSpace(input), len(options), preselected)
+		if !ok {
+			p.out.Warning("Invalid selection. Please enter numbers between 1 and %d", len(options))
+			continue
+		}
+
+		return indices, nil
+	}
+}
+
+// parseMultiSelection parses numbered multi-select input such as "1, 3".
+// Empty input keeps preselected.
+func parseMultiSelection(input string, count int, preselected []int) ([]int, bool) {
+	switch strings.ToLower(input) {
+	case "":
+		indices := slices.Clone(preselected)
+		slices.Sort(indices)
+
+		return slices.Compact(indices), true
+	case "all":
+		indices := make([]int, count)
+		for i := range indices {
+			indices[i] = i
+		}
+
+		return indices, true
+	}
+
+	var indices []int
+
+	for _, field := range strings.Split(input, ",") {
+		num, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || num < 1 || num > count {
+			return nil, false
+		}
+
+		indices = append(indices, num-1)
+	}
+
+	slices.Sort(indices)
+
+	return slices.Compact(indices), true
+}
+
+func labelOptions(labels []string) []SelectOption {
+	options := make([]SelectOption, len(labels))
+	for i, label := range labels {
+		options[i] = SelectOption{Label: label}
+	}
+
+	return options
+}
+
 type selectableSummary interface {
 	GetSlug() string
 	GetName() string
 	GetStatus() string
+	GetDetail() string
 }
 
 type habitatOption struct {
@@ -165,6 +275,14 @@ func (o *habitatOption) GetName() string {
 	return o.Name
 }
 
+func (o *habitatOption) GetDetail() string {
+	if o.HabitatType == "" {
+		return "ID: " + o.ID
+	}
+
+	return fmt.Sprintf("ID: %s · Type: %s", o.ID, o.HabitatType)
+}
+
 func (o *habitatOption) GetStatus() string {
 	switch o.Status {
 	case "online":
@@ -190,6 +308,10 @@ func (o *queueOption) GetName() string {
 	return o.Name
 }
 
+func (o *queueOption) GetDetail() string {
+	return "ID: " + o.ID
+}
+
 func (o *queueOption) GetStatus() string {
 	switch o.Status {
 	case "active":
@@ -204,10 +326,13 @@ func (o *queueOption) GetStatus() string {
 }
 
 func selectSummary[T selectableSummary](title, itemLabel string, entries []T, out *output.Writer) (int, error) {
-	// Try arrow-key selection when stdin is a terminal.
+	// Try the interactive selector when stdin is a terminal.
 	if shouldUseArrowKeySelection() {
-		idx, err := selectArrowKey(title, entries)
+		sel := newSelector("Available "+title+":", summaryOptions(entries), false, nil)
+
+		err := runSelector(sel, out.Raw())
 		if err == nil {
+			idx, _ := sel.current()
 			return idx, nil
 		}
 
@@ -260,7 +385,7 @@ func selectNumbered[T selectableSummary](title, itemLabel string, entries []T, o
 	}
 }
 
-// errCanceled is returned when the user cancels arrow-key selection.
+// errCanceled is returned when the user cancels interactive selection.
 var errCanceled = fmt.Errorf("selection canceled")
 
 // shouldUseArrowKeySelection gates raw-mode selector usage.
@@ -273,120 +398,16 @@ func IsCanceled(err error) bool {
 	return errors.Is(err, errCanceled)
 }
 
-type arrowSelectionAction int
-
-const (
-	arrowSelectionNone arrowSelectionAction = iota
-	arrowSelectionConfirm
-	arrowSelectionCancel
-	arrowSelectionUp
-	arrowSelectionDown
-)
-
-// selectArrowKey provides arrow-key navigation for TTY selection.
-// Up/Down moves the cursor, Enter confirms, Esc/Ctrl+C cancels.
-func selectArrowKey[T selectableSummary](title string, entries []T) (int, error) {
-	stdinFd := int(os.Stdin.Fd())
-
-	oldState, err := term.MakeRaw(stdinFd)
-	if err != nil {
-		return -1, fmt.Errorf("raw mode: %w", err)
-	}
-
-	defer func() { _ = term.Restore(stdinFd, oldState) }()
-
-	selected := 0
-	totalItems := len(entries)
-	lines := buildArrowSelectionLines(entries)
-
-	// Write initial header + list.
-	writeStr := func(s string) { _, _ = os.Stdout.WriteString(s) }
-
-	drawArrowSelection(title, lines, selected, writeStr)
-
-	// Move cursor up to redraw position (totalItems lines + 1 hint line).
-	moveUp := func() {
-		writeStr(fmt.Sprintf("\x1b[%dA\r", totalItems+1))
-	}
-
-	buf := make([]byte, escapeSequenceBufferSize)
-
-	for {
-		n, readErr := os.Stdin.Read(buf)
-		if readErr != nil {
-			return -1, fmt.Errorf("read input: %w", readErr)
-		}
-
-		switch readArrowSelectionAction(buf, n) {
-		case arrowSelectionConfirm:
-			// Move past the list to avoid overwriting.
-			writeStr("\r\n\r\n")
-
-			return selected, nil
-
-		case arrowSelectionCancel:
-			writeStr("\r\n\r\n")
-
-			return -1, errCanceled
-
-		case arrowSelectionUp:
-			if selected > 0 {
-				selected--
-
-				moveUp()
-				drawArrowSelectionList(lines, selected, writeStr)
-			}
-
-		case arrowSelectionDown:
-			if selected < totalItems-1 {
-				selected++
-
-				moveUp()
-				drawArrowSelectionList(lines, selected, writeStr)
-			}
-		}
-	}
-}
-
-func buildArrowSelectionLines[T selectableSummary](entries []T) []string {
-	lines := make([]string, len(entries))
+func summaryOptions[T selectableSummary](entries []T) []SelectOption {
+	options := make([]SelectOption, len(entries))
 	for i, entry := range entries {
-		lines[i] = fmt.Sprintf("%-20s %s %s", entry.GetSlug(), entry.GetName(), entry.GetStatus())
-	}
-
-	return lines
-}
-
-func drawArrowSelection(title string, lines []string, selected int, writeStr func(string)) {
-	writeStr(fmt.Sprintf("\r\nAvailable %s:\r\n\r\n", title))
-	drawArrowSelectionList(lines, selected, writeStr)
-}
-
-func drawArrowSelectionList(lines []string, selected int, writeStr func(string)) {
-	for i := range lines {
-		if i == selected {
-			writeStr(fmt.Sprintf("  \x1b[1m> %s\x1b[0m\r\n", lines[i]))
-		} else {
-			writeStr(fmt.Sprintf("    %s\r\n", lines[i]))
+		options[i] = SelectOption{
+			Label:  fmt.Sprintf("%-20s %s %s", entry.GetSlug(), entry.GetName(), entry.GetStatus()),
+			Detail: entry.GetDetail(),
 		}
 	}
 
-	writeStr("\r\n  Use \x1b[1m↑/↓\x1b[0m to navigate, \x1b[1mEnter\x1b[0m to confirm, \x1b[1mEsc\x1b[0m to cancel")
-}
-
-func readArrowSelectionAction(buf []byte, n int) arrowSelectionAction {
-	switch {
-	case n == 1 && buf[0] == '\r':
-		return arrowSelectionConfirm
-	case n == 1 && (buf[0] == 0x1b || buf[0] == 0x03):
-		return arrowSelectionCancel
-	case n == 3 && buf[0] == 0x1b && buf[1] == '[' && buf[2] == 'A':
-		return arrowSelectionUp
-	case n == 3 && buf[0] == 0x1b && buf[1] == '[' && buf[2] == 'B':
-		return arrowSelectionDown
-	default:
-		return arrowSelectionNone
-	}
+	return options
 }
 
 // SelectHabitat prompts the user to select a habitat from a list.
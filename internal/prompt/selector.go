@@ -0,0 +1,385 @@
+package prompt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// selectorVisibleRows caps how many options are drawn at once; the list
+// scrolls to keep the cursor in view.
+const selectorVisibleRows = 10
+
+// selectorReadBufferSize fits an escape sequence or a short paste.
+const selectorReadBufferSize = 64
+
+// SelectOption is one entry in an interactive selector.
+type SelectOption struct {
+	// Label is shown in the list and matched by the fuzzy filter.
+	Label string
+	// Detail is previewed below the list while the option is highlighted.
+	Detail string
+}
+
+type selectorKey int
+
+const (
+	keyNone selectorKey = iota
+	keyRune
+	keyUp
+	keyDown
+	keyBackspace
+	keyToggle
+	keyToggleAll
+	keyConfirm
+	keyCancel
+)
+
+// selector holds the state of a fuzzy-filtered list. It is independent of
+// the terminal so navigation and filtering can be tested directly.
+type selector struct {
+	title   string
+	options []SelectOption
+	multi   bool
+
+	query   []rune
+	matches []int
+	cursor  int
+	offset  int
+	chosen  map[int]bool
+}
+
+func newSelector(title string, options []SelectOption, multi bool, preselected []int) *selector {
+	s := &selector{title: title, options: options, multi: multi, chosen: make(map[int]bool)}
+	for _, idx := range preselected {
+		if idx >= 0 && idx < len(options) {
+			s.chosen[idx] = true
+		}
+	}
+
+	s.refilter()
+
+	return s
+}
+
+// refilter recomputes matches for the current query, best match first.
+func (s *selector) refilter() {
+	type scored struct {
+		index int
+		score int
+	}
+
+	query := string(s.query)
+	ranked := make([]scored, 0, len(s.options))
+
+	for i, opt := range s.options {
+		if score, ok := fuzzyScore(query, opt.Label); ok {
+			ranked = append(ranked, scored{index: i, score: score})
+		}
+	}
+
+	slices.SortStableFunc(ranked, func(a, b scored) int { return b.score - a.score })
+
+	s.matches = s.matches[:0]
+	for _, r := range ranked {
+		s.matches = append(s.matches, r.index)
+	}
+
+	s.cursor = 0
+	s.offset = 0
+}
+
+// handle applies a key and reports whether the selection is finished.
+func (s *selector) handle(key selectorKey, r rune) (done bool, err error) {
+	switch key {
+	case keyUp:
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case keyDown:
+		if s.cursor < len(s.matches)-1 {
+			s.cursor++
+		}
+	case keyRune:
+		s.query = append(s.query, r)
+		s.refilter()
+	case keyBackspace:
+		if len(s.query) > 0 {
+			s.query = s.query[:len(s.query)-1]
+			s.refilter()
+		}
+	case keyToggle:
+		if !s.multi {
+			return s.handle(keyRune, ' ')
+		}
+
+		if idx, ok := s.current(); ok {
+			s.chosen[idx] = !s.chosen[idx]
+		}
+	case keyToggleAll:
+		if s.multi {
+			s.toggleAllMatches()
+		}
+	case keyConfirm:
+		if s.multi {
+			return true, nil
+		}
+
+		_, ok := s.current()
+
+		return ok, nil
+	case keyCancel:
+		return true, errCanceled
+	case keyNone:
+	}
+
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	} else if s.cursor >= s.offset+selectorVisibleRows {
+		s.offset = s.cursor - selectorVisibleRows + 1
+	}
+
+	return false, nil
+}
+
+// toggleAllMatches selects every visible match, or clears them if all are
+// already selected.
+func (s *selector) toggleAllMatches() {
+	all := true
+
+	for _, idx := range s.matches {
+		if !s.chosen[idx] {
+			all = false
+			break
+		}
+	}
+
+	for _, idx := range s.matches {
+		s.chosen[idx] = !all
+	}
+}
+
+func (s *selector) current() (int, bool) {
+	if s.cursor >= len(s.matches) {
+		return -1, false
+	}
+
+	return s.matches[s.cursor], true
+}
+
+// selected returns the chosen option indices in their original order.
+func (s *selector) selected() []int {
+	indices := make([]int, 0, len(s.chosen))
+
+	for idx, ok := range s.chosen {
+		if ok {
+			indices = append(indices, idx)
+		}
+	}
+
+	slices.Sort(indices)
+
+	return indices
+}
+
+// render returns the selector's lines, without trailing newlines.
+func (s *selector) render() []string {
+	lines := []string{fmt.Sprintf("  Filter: %s\x1b[7m \x1b[0m", string(s.query))}
+
+	if len(s.matches) == 0 {
+		lines = append(lines, "    (no matches)")
+	}
+
+	end := min(s.offset+selectorVisibleRows, len(s.matches))
+	for pos := s.offset; pos < end; pos++ {
+		idx := s.matches[pos]
+
+		mark := ""
+		if s.multi {
+			mark = "[ ] "
+			if s.chosen[idx] {
+				mark = "[x] "
+			}
+		}
+
+		if pos == s.cursor {
+			lines = append(lines, fmt.Sprintf("  \x1b[1m> %s%s\x1b[0m", mark, s.options[idx].Label))
+		} else {
+			lines = append(lines, fmt.Sprintf("    %s%s", mark, s.options[idx].Label))
+		}
+	}
+
+	if hidden := len(s.matches) - end; hidden > 0 {
+		lines = append(lines, fmt.Sprintf("    … %d more", hidden))
+	}
+
+	lines = append(lines, "")
+
+	if idx, ok := s.current(); ok && s.options[idx].Detail != "" {
+		lines = append(lines, "  \x1b[2m"+s.options[idx].Detail+"\x1b[0m")
+	}
+
+	hint := "  Type to filter, \x1b[1m↑/↓\x1b[0m to navigate, \x1b[1mEnter\x1b[0m to confirm, \x1b[1mEsc\x1b[0m to cancel"
+	if s.multi {
+		hint = "  Type to filter, \x1b[1m↑/↓\x1b[0m to navigate, \x1b[1mSpace\x1b[0m to toggle, " +
+			"\x1b[1mCtrl+A\x1b[0m for all, \x1b[1mEnter\x1b[0m to confirm, \x1b[1mEsc\x1b[0m to cancel"
+	}
+
+	return append(lines, hint)
+}
+
+// fuzzyScore reports whether every rune of query appears in text in order,
+// ignoring case, and scores the match: consecutive runs and matches at word
+// starts rank higher, as do matches nearer the start of text.
+func fuzzyScore(query, text string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+
+	needle := []rune(strings.ToLower(query))
+	haystack := []rune(strings.ToLower(text))
+
+	score := 0
+	qi := 0
+	prev := -2
+
+	for ti, r := range haystack {
+		if qi == len(needle) {
+			break
+		}
+
+		if r != needle[qi] {
+			continue
+		}
+
+		score++
+
+		if ti == prev+1 {
+			score += 3
+		}
+
+		if ti == 0 || !unicode.IsLetter(haystack[ti-1]) && !unicode.IsDigit(haystack[ti-1]) {
+			score += 2
+		}
+
+		if qi == 0 {
+			score -= min(ti, 10)
+		}
+
+		prev = ti
+		qi++
+	}
+
+	return score, qi == len(needle)
+}
+
+// decodeSelectorKeys splits raw terminal input into keys.
+func decodeSelectorKeys(buf []byte, emit func(selectorKey, rune)) {
+	for len(buf) > 0 {
+		switch {
+		case len(buf) >= 3 && buf[0] == 0x1b && (buf[1] == '[' || buf[1] == 'O'):
+			switch buf[2] {
+			case 'A':
+				emit(keyUp, 0)
+			case 'B':
+				emit(keyDown, 0)
+			}
+
+			buf = buf[3:]
+
+			continue
+		case buf[0] == 0x1b || buf[0] == 0x03:
+			emit(keyCancel, 0)
+			return
+		case buf[0] == '\r' || buf[0] == '\n':
+			emit(keyConfirm, 0)
+			return
+		case buf[0] == 0x7f || buf[0] == 0x08:
+			emit(keyBackspace, 0)
+		case buf[0] == 0x10: // Ctrl+P
+			emit(keyUp, 0)
+		case buf[0] == 0x0e: // Ctrl+N
+			emit(keyDown, 0)
+		case buf[0] == ' ' || buf[0] == '\t':
+			emit(keyToggle, 0)
+		case buf[0] == 0x01: // Ctrl+A
+			emit(keyToggleAll, 0)
+		case buf[0] >= 0x20:
+			r, size := utf8.DecodeRune(buf)
+			if r != utf8.RuneError {
+				emit(keyRune, r)
+			}
+
+			buf = buf[size:]
+
+			continue
+		}
+
+		buf = buf[1:]
+	}
+}
+
+// runSelector drives s in raw mode on the terminal until the user confirms
+// or cancels.
+func runSelector(s *selector, w io.Writer) error {
+	stdinFd := int(os.Stdin.Fd())
+
+	oldState, err := term.MakeRaw(stdinFd)
+	if err != nil {
+		return fmt.Errorf("raw mode: %w", err)
+	}
+
+	defer func() { _ = term.Restore(stdinFd, oldState) }()
+
+	writeStr := func(str string) { _, _ = io.WriteString(w, str) }
+
+	writeStr(fmt.Sprintf("\r\n%s\r\n\r\n", s.title))
+
+	drawn := 0
+	draw := func() {
+		if drawn > 1 {
+			writeStr(fmt.Sprintf("\x1b[%dA", drawn-1))
+		}
+
+		writeStr("\r\x1b[J")
+
+		lines := s.render()
+		writeStr(strings.Join(lines, "\r\n"))
+		drawn = len(lines)
+	}
+
+	draw()
+
+	buf := make([]byte, selectorReadBufferSize)
+
+	for {
+		n, readErr := os.Stdin.Read(buf)
+		if readErr != nil {
+			return fmt.Errorf("read input: %w", readErr)
+		}
+
+		var (
+			done      bool
+			handleErr error
+		)
+
+		decodeSelectorKeys(buf[:n], func(key selectorKey, r rune) {
+			if !done {
+				done, handleErr = s.handle(key, r)
+			}
+		})
+
+		if done {
+			writeStr("\r\n\r\n")
+			return handleErr
+		}
+
+		draw()
+	}
+}
@@ -0,0 +1,209 @@
+package prompt
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testOptions(labels ...string) []SelectOption {
+	return labelOptions(labels)
+}
+
+func typeQuery(s *selector, query string) {
+	for _, r := range query {
+		_, _ = s.handle(keyRune, r)
+	}
+}
+
+func matchedLabels(s *selector) []string {
+	labels := make([]string, 0, len(s.matches))
+	for _, idx := range s.matches {
+		labels = append(labels, s.options[idx].Label)
+	}
+
+	return labels
+}
+
+func TestFuzzyScore(t *testing.T) {
+	tests := []struct {
+		query string
+		text  string
+		match bool
+	}{
+		{query: "", text: "anything", match: true},
+		{query: "prd", text: "production", match: true},
+		{query: "PROD", text: "production", match: true},
+		{query: "dorp", text: "production", match: false},
+		{query: "stg-q", text: "staging-queue", match: true},
+	}
+
+	for _, tt := range tests {
+		if _, ok := fuzzyScore(tt.query, tt.text); ok != tt.match {
+			t.Errorf("fuzzyScore(%q, %q) match = %v, want %v", tt.query, tt.text, ok, tt.match)
+		}
+	}
+
+	prefix, _ := fuzzyScore("api", "api-gateway")
+	scattered, _ := fuzzyScore("api", "a-pending-item")
+
+	if prefix <= scattered {
+		t.Errorf("contiguous prefix score %d should beat scattered score %d", prefix, scattered)
+	}
+}
+
+func TestSelector_FilterRanksBestMatchFirst(t *testing.T) {
+	s := newSelector("Pick", testOptions("staging-api", "prod-web", "api-gateway"), false, nil)
+
+	typeQuery(s, "api")
+
+	if got := matchedLabels(s); !slices.Equal(got, []string{"api-gateway", "staging-api"}) {
+		t.Fatalf("matches = %q", got)
+	}
+
+	_, _ = s.handle(keyBackspace, 0)
+	_, _ = s.handle(keyBackspace, 0)
+	_, _ = s.handle(keyBackspace, 0)
+
+	if len(s.matches) != 3 {
+		t.Fatalf("clearing the filter should show all options, got %q", matchedLabels(s))
+	}
+}
+
+func TestSelector_SingleConfirmReturnsHighlighted(t *testing.T) {
+	s := newSelector("Pick", testOptions("one", "two", "three"), false, nil)
+
+	_, _ = s.handle(keyDown, 0)
+	_, _ = s.handle(keyDown, 0)
+	_, _ = s.handle(keyDown, 0) // clamped at the last option
+
+	done, err := s.handle(keyConfirm, 0)
+	if !done || err != nil {
+		t.Fatalf("confirm = (%v, %v), want (true, nil)", done, err)
+	}
+
+	if idx, _ := s.current(); idx != 2 {
+		t.Fatalf("current = %d, want 2", idx)
+	}
+}
+
+func TestSelector_ConfirmIgnoredWithoutMatches(t *testing.T) {
+	s := newSelector("Pick", testOptions("one"), false, nil)
+
+	typeQuery(s, "zzz")
+
+	if done, _ := s.handle(keyConfirm, 0); done {
+		t.Fatal("confirm with no matches should not finish a single selection")
+	}
+}
+
+func TestSelector_MultiToggle(t *testing.T) {
+	s := newSelector("Pick", testOptions("claude", "codex", "gemini"), true, []int{0})
+
+	_, _ = s.handle(keyDown, 0)
+	_, _ = s.handle(keyToggle, 0)
+	_, _ = s.handle(keyUp, 0)
+	_, _ = s.handle(keyToggle, 0)
+
+	if got := s.selected(); !slices.Equal(got, []int{1}) {
+		t.Fatalf("selected = %v, want [1]", got)
+	}
+
+	_, _ = s.handle(keyToggleAll, 0)
+
+	if got := s.selected(); !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("after toggle all, selected = %v, want all", got)
+	}
+
+	_, _ = s.handle(keyToggleAll, 0)
+
+	if got := s.selected(); len(got) != 0 {
+		t.Fatalf("second toggle all should clear, got %v", got)
+	}
+}
+
+func TestSelector_CancelReturnsCanceled(t *testing.T) {
+	s := newSelector("Pick", testOptions("one"), false, nil)
+
+	done, err := s.handle(keyCancel, 0)
+	if !done || !IsCanceled(err) {
+		t.Fatalf("cancel = (%v, %v), want canceled", done, err)
+	}
+}
+
+func TestSelector_RenderScrollsAndPreviews(t *testing.T) {
+	labels := make([]string, 15)
+	for i := range labels {
+		labels[i] = "item-" + string(rune('a'+i))
+	}
+
+	options := testOptions(labels...)
+	options[12].Detail = "ID: item-m"
+
+	s := newSelector("Pick", options, false, nil)
+	for range 12 {
+		_, _ = s.handle(keyDown, 0)
+	}
+
+	rendered := strings.Join(s.render(), "\n")
+
+	if strings.Contains(rendered, "item-a") {
+		t.Error("list should scroll past the first option")
+	}
+
+	if !strings.Contains(rendered, "> item-m") {
+		t.Error("highlighted option should be marked")
+	}
+
+	if !strings.Contains(rendered, "ID: item-m") {
+		t.Error("highlighted option's detail should be previewed")
+	}
+
+	if !strings.Contains(rendered, "… 2 more") {
+		t.Errorf("hidden options should be counted, got:\n%s", rendered)
+	}
+}
+
+func TestDecodeSelectorKeys(t *testing.T) {
+	var keys []selectorKey
+
+	var runes []rune
+
+	decodeSelectorKeys([]byte("ab\x1b[B\x7f \x01é\r"), func(key selectorKey, r rune) {
+		keys = append(keys, key)
+
+		if key == keyRune {
+			runes = append(runes, r)
+		}
+	})
+
+	want := []selectorKey{keyRune, keyRune, keyDown, keyBackspace, keyToggle, keyToggleAll, keyRune, keyConfirm}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("keys = %v, want %v", keys, want)
+	}
+
+	if string(runes) != "abé" {
+		t.Fatalf("runes = %q, want %q", string(runes), "abé")
+	}
+}
+
+func TestParseMultiSelection(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []int
+		ok    bool
+	}{
+		{input: "", want: []int{0, 2}, ok: true},
+		{input: "all", want: []int{0, 1, 2}, ok: true},
+		{input: "3, 1, 3", want: []int{0, 2}, ok: true},
+		{input: "4", ok: false},
+		{input: "x", ok: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseMultiSelection(tt.input, 3, []int{2, 0})
+		if ok != tt.ok || (ok && !slices.Equal(got, tt.want)) {
+			t.Errorf("parseMultiSelection(%q) = (%v, %v), want (%v, %v)", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
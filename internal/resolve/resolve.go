@@ -0,0 +1,262 @@
+// Package resolve resolves the habitat and queue a worker connects to from a
+// flag value, or by prompting when none is given.
+//
+// Input may be an ID, a UUID in any letter case, a slug, or a display name.
+// Exact IDs and slugs win; a case-insensitive slug or name, or an ID prefix
+// of at least MinIDPrefix characters, may match several entries, which are
+// offered in a prompt or reported as ambiguous when prompts are unavailable.
+package resolve
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+	"github.com/musher-dev/mush/internal/output"
+	"github.com/musher-dev/mush/internal/prompt"
+)
+
+// MinIDPrefix is the shortest ID prefix accepted as input, so a short word
+// never resolves to an ID by accident.
+const MinIDPrefix = 8
+
+// Lister lists habitats and queues. *client.Client satisfies it.
+type Lister interface {
+	ListHabitats(ctx context.Context) ([]client.HabitatSummary, error)
+	ListQueues(ctx context.Context, habitatID string) ([]client.QueueSummary, error)
+}
+
+// Prompter picks one habitat or queue interactively.
+type Prompter interface {
+	// CanPrompt reports whether the user can be asked.
+	CanPrompt() bool
+	SelectHabitat(habitats []client.HabitatSummary) (*client.HabitatSummary, error)
+	SelectQueue(queues []client.QueueSummary) (*client.QueueSummary, error)
+}
+
+// TerminalPrompter prompts on out's terminal unless out.NoInput is set.
+type TerminalPrompter struct {
+	Out *output.Writer
+}
+
+// CanPrompt reports whether prompts are allowed.
+func (p TerminalPrompter) CanPrompt() bool {
+	return !p.Out.NoInput
+}
+
+// SelectHabitat prompts for a habitat.
+func (p TerminalPrompter) SelectHabitat(habitats []client.HabitatSummary) (*client.HabitatSummary, error) {
+	return prompt.SelectHabitat(habitats, p.Out)
+}
+
+// SelectQueue prompts for a queue.
+func (p TerminalPrompter) SelectQueue(queues []client.QueueSummary) (*client.QueueSummary, error) {
+	return prompt.SelectQueue(queues, p.Out)
+}
+
+// Resolver resolves habitats and queues through a Lister and a Prompter.
+type Resolver struct {
+	Lister   Lister
+	Prompter Prompter
+}
+
+// NewResolver returns a Resolver that lists through c and prompts on out.
+func NewResolver(c *client.Client, out *output.Writer) *Resolver {
+	return &Resolver{Lister: c, Prompter: TerminalPrompter{Out: out}}
+}
+
+// Habitat lists the available habitats and picks one by input.
+func (r *Resolver) Habitat(ctx context.Context, input string) (client.HabitatSummary, error) {
+	habitats, err := r.ListHabitats(ctx)
+	if err != nil {
+		return client.HabitatSummary{}, err
+	}
+
+	return r.SelectHabitat(habitats, input)
+}
+
+// ListHabitats fetches the habitats available to the credential.
+func (r *Resolver) ListHabitats(ctx context.Context) ([]client.HabitatSummary, error) {
+	habitats, err := r.Lister.ListHabitats(ctx)
+	if err != nil {
+		return nil, clierrors.NetworkFailed("Failed to fetch habitats", err).
+			WithHint("Check your network connection and API credentials")
+	}
+
+	return habitats, nil
+}
+
+// MatchHabitats returns the habitats input could refer to, using the
+// package's matching rules.
+func MatchHabitats(habitats []client.HabitatSummary, input string) []client.HabitatSummary {
+	return match(habitats, input, habitatKey)
+}
+
+// SelectHabitat picks a habitat from habitats by input, or by prompt when
+// input is empty.
+func (r *Resolver) SelectHabitat(habitats []client.HabitatSummary, input string) (client.HabitatSummary, error) {
+	return selectOne(r.Prompter, input, habitats, chooser[client.HabitatSummary]{
+		key:           habitatKey,
+		prompt:        r.Prompter.SelectHabitat,
+		notFound:      clierrors.HabitatNotFound,
+		ambiguous:     clierrors.HabitatAmbiguous,
+		noneAvailable: clierrors.NoHabitats,
+		required:      clierrors.HabitatRequired,
+		cancelMessage: "Habitat selection canceled",
+		cancelHint:    "Pass --habitat to select non-interactively",
+		selectError:   "Failed to select habitat",
+	})
+}
+
+// Queue lists the queues of a habitat and picks one by input.
+func (r *Resolver) Queue(ctx context.Context, habitatID, input string) (client.QueueSummary, error) {
+	queues, err := r.Lister.ListQueues(ctx, habitatID)
+	if err != nil {
+		return client.QueueSummary{}, clierrors.NetworkFailed("Failed to fetch queues", err).
+			WithHint("Check your network connection and API credentials")
+	}
+
+	return selectOne(r.Prompter, input, queues, chooser[client.QueueSummary]{
+		key:           queueKey,
+		prompt:        r.Prompter.SelectQueue,
+		notFound:      clierrors.QueueNotFound,
+		ambiguous:     clierrors.QueueAmbiguous,
+		noneAvailable: clierrors.NoQueuesForHabitat,
+		required:      clierrors.QueueRequired,
+		cancelMessage: "Queue selection canceled",
+		cancelHint:    "Pass --queue to select non-interactively",
+		selectError:   "Failed to select queue",
+	})
+}
+
+// entryKey is what input is matched against.
+type entryKey struct {
+	id, slug, name string
+}
+
+func habitatKey(h client.HabitatSummary) entryKey {
+	return entryKey{id: h.ID, slug: h.Slug, name: h.Name}
+}
+
+func queueKey(q client.QueueSummary) entryKey {
+	return entryKey{id: q.ID, slug: q.Slug, name: q.Name}
+}
+
+// chooser adapts selectOne to one kind of entry.
+type chooser[T any] struct {
+	key           func(T) entryKey
+	prompt        func([]T) (*T, error)
+	notFound      func(input string) *clierrors.CLIError
+	ambiguous     func(input string, candidates []string) *clierrors.CLIError
+	noneAvailable func() *clierrors.CLIError
+	required      func() *clierrors.CLIError
+	cancelMessage string
+	cancelHint    string
+	selectError   string
+}
+
+func selectOne[T any](p Prompter, input string, entries []T, c chooser[T]) (T, error) {
+	var zero T
+
+	candidates := entries
+
+	if input != "" {
+		candidates = match(entries, input, c.key)
+
+		switch len(candidates) {
+		case 0:
+			return zero, c.notFound(input)
+		case 1:
+			return candidates[0], nil
+		}
+
+		if !p.CanPrompt() {
+			labels := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				key := c.key(candidate)
+				labels = append(labels, fmt.Sprintf("%s (%s)", key.slug, key.id))
+			}
+
+			return zero, c.ambiguous(input, labels)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return zero, c.noneAvailable()
+	}
+
+	if !p.CanPrompt() {
+		if len(candidates) == 1 {
+			return candidates[0], nil
+		}
+
+		return zero, c.required()
+	}
+
+	selected, err := c.prompt(candidates)
+	if err != nil {
+		if prompt.IsCanceled(err) {
+			return zero, clierrors.New(clierrors.ExitUsage, c.cancelMessage).WithHint(c.cancelHint)
+		}
+
+		return zero, clierrors.Wrap(clierrors.ExitGeneral, c.selectError, err)
+	}
+
+	return *selected, nil
+}
+
+// match returns the entries input refers to, strongest match first: an
+// exact ID or slug, a UUID in another letter case, a case-insensitive slug
+// or name, and finally an ID prefix of at least MinIDPrefix characters.
+// Only the strongest kind that matches anything is returned.
+func match[T any](entries []T, input string, key func(T) entryKey) []T {
+	rules := []func(entryKey) bool{
+		func(k entryKey) bool { return k.id == input },
+		func(k entryKey) bool { return k.slug == input },
+		func(k entryKey) bool { return isUUID(input) && strings.EqualFold(k.id, input) },
+		func(k entryKey) bool { return strings.EqualFold(k.slug, input) || strings.EqualFold(k.name, input) },
+		func(k entryKey) bool {
+			return len(input) >= MinIDPrefix && strings.HasPrefix(strings.ToLower(k.id), strings.ToLower(input))
+		},
+	}
+
+	for _, rule := range rules {
+		var matches []T
+
+		for _, entry := range entries {
+			if rule(key(entry)) {
+				matches = append(matches, entry)
+			}
+		}
+
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+
+	return nil
+}
+
+// isUUID reports whether s has the 8-4-4-4-12 hex layout of a UUID.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+
+	for i, r := range s {
+		switch i {
+		case 8, 13, 18, 23:
+			if r != '-' {
+				return false
+			}
+		default:
+			if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
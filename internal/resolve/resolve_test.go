@@ -0,0 +1,246 @@
+package resolve
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/musher-dev/mush/internal/client"
+	clierrors "github.com/musher-dev/mush/internal/errors"
+)
+
+type fakeLister struct {
+	habitats []client.HabitatSummary
+	queues   map[string][]client.QueueSummary
+	err      error
+}
+
+func (l *fakeLister) ListHabitats(context.Context) ([]client.HabitatSummary, error) {
+	return l.habitats, l.err
+}
+
+func (l *fakeLister) ListQueues(_ context.Context, habitatID string) ([]client.QueueSummary, error) {
+	return l.queues[habitatID], l.err
+}
+
+// fakePrompter picks the entry with slug pick and records what it offered.
+type fakePrompter struct {
+	interactive bool
+	pick        string
+	offered     []string
+}
+
+func (p *fakePrompter) CanPrompt() bool {
+	return p.interactive
+}
+
+func (p *fakePrompter) SelectHabitat(habitats []client.HabitatSummary) (*client.HabitatSummary, error) {
+	for i := range habitats {
+		p.offered = append(p.offered, habitats[i].Slug)
+	}
+
+	for i := range habitats {
+		if habitats[i].Slug == p.pick {
+			return &habitats[i], nil
+		}
+	}
+
+	return nil, errors.New("no such option")
+}
+
+func (p *fakePrompter) SelectQueue(queues []client.QueueSummary) (*client.QueueSummary, error) {
+	for i := range queues {
+		p.offered = append(p.offered, queues[i].Slug)
+	}
+
+	for i := range queues {
+		if queues[i].Slug == p.pick {
+			return &queues[i], nil
+		}
+	}
+
+	return nil, errors.New("no such option")
+}
+
+var testHabitats = []client.HabitatSummary{
+	{ID: "0b1e4f7a-5c3d-4e2f-9a8b-7c6d5e4f3a2b", Slug: "web-prod", Name: "Web"},
+	{ID: "0b1e4f7a-ffff-4e2f-9a8b-000000000000", Slug: "web-staging", Name: "Web"},
+	{ID: "hab-local", Slug: "local", Name: "Local Machine"},
+}
+
+func TestResolverSelectHabitat(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		prompter *fakePrompter
+		wantID   string
+		wantCode int
+		offered  []string
+	}{
+		{
+			name:     "exact id",
+			input:    "hab-local",
+			prompter: &fakePrompter{},
+			wantID:   "hab-local",
+		},
+		{
+			name:     "exact slug",
+			input:    "web-staging",
+			prompter: &fakePrompter{},
+			wantID:   "0b1e4f7a-ffff-4e2f-9a8b-000000000000",
+		},
+		{
+			name:     "uuid in upper case",
+			input:    "0B1E4F7A-5C3D-4E2F-9A8B-7C6D5E4F3A2B",
+			prompter: &fakePrompter{},
+			wantID:   "0b1e4f7a-5c3d-4e2f-9a8b-7c6d5e4f3a2b",
+		},
+		{
+			name:     "name ignoring case",
+			input:    "local machine",
+			prompter: &fakePrompter{},
+			wantID:   "hab-local",
+		},
+		{
+			name:     "unique id prefix",
+			input:    "0b1e4f7a-5c",
+			prompter: &fakePrompter{},
+			wantID:   "0b1e4f7a-5c3d-4e2f-9a8b-7c6d5e4f3a2b",
+		},
+		{
+			name:     "short id prefix is not a match",
+			input:    "0b1e",
+			prompter: &fakePrompter{interactive: true},
+			wantCode: clierrors.ExitConfig,
+		},
+		{
+			name:     "unknown input",
+			input:    "nope",
+			prompter: &fakePrompter{interactive: true},
+			wantCode: clierrors.ExitConfig,
+		},
+		{
+			name:     "ambiguous name without prompts",
+			input:    "web",
+			prompter: &fakePrompter{},
+			wantCode: clierrors.ExitUsage,
+		},
+		{
+			name:     "ambiguous name prompts among matches",
+			input:    "web",
+			prompter: &fakePrompter{interactive: true, pick: "web-staging"},
+			wantID:   "0b1e4f7a-ffff-4e2f-9a8b-000000000000",
+			offered:  []string{"web-prod", "web-staging"},
+		},
+		{
+			name:     "ambiguous id prefix without prompts",
+			input:    "0b1e4f7a",
+			prompter: &fakePrompter{},
+			wantCode: clierrors.ExitUsage,
+		},
+		{
+			name:     "no input prompts among all",
+			prompter: &fakePrompter{interactive: true, pick: "local"},
+			wantID:   "hab-local",
+			offered:  []string{"web-prod", "web-staging", "local"},
+		},
+		{
+			name:     "no input without prompts",
+			prompter: &fakePrompter{},
+			wantCode: clierrors.ExitConfig,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resolver{Lister: &fakeLister{}, Prompter: tt.prompter}
+
+			got, err := r.SelectHabitat(testHabitats, tt.input)
+			if tt.wantCode != 0 {
+				var cliErr *clierrors.CLIError
+				if !errors.As(err, &cliErr) || cliErr.Code != tt.wantCode {
+					t.Fatalf("SelectHabitat(%q) error = %v, want CLIError with code %d", tt.input, err, tt.wantCode)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("SelectHabitat(%q) error = %v", tt.input, err)
+			}
+
+			if got.ID != tt.wantID {
+				t.Fatalf("SelectHabitat(%q).ID = %q, want %q", tt.input, got.ID, tt.wantID)
+			}
+
+			if len(tt.offered) > 0 && !slices.Equal(tt.prompter.offered, tt.offered) {
+				t.Fatalf("prompt offered %v, want %v", tt.prompter.offered, tt.offered)
+			}
+		})
+	}
+}
+
+func TestResolverHabitatSingleWithoutPrompts(t *testing.T) {
+	r := &Resolver{
+		Lister:   &fakeLister{habitats: testHabitats[2:]},
+		Prompter: &fakePrompter{},
+	}
+
+	got, err := r.Habitat(t.Context(), "")
+	if err != nil || got.ID != "hab-local" {
+		t.Fatalf("Habitat() = %+v, %v; want the only habitat", got, err)
+	}
+}
+
+func TestResolverHabitatErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		lister   *fakeLister
+		wantCode int
+	}{
+		{"list fails", &fakeLister{err: errors.New("connection refused")}, clierrors.ExitTempFail},
+		{"none available", &fakeLister{}, clierrors.ExitConfig},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resolver{Lister: tt.lister, Prompter: &fakePrompter{interactive: true}}
+
+			_, err := r.Habitat(t.Context(), "")
+
+			var cliErr *clierrors.CLIError
+			if !errors.As(err, &cliErr) || cliErr.Code != tt.wantCode {
+				t.Fatalf("Habitat() error = %v, want CLIError with code %d", err, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestResolverQueue(t *testing.T) {
+	lister := &fakeLister{queues: map[string][]client.QueueSummary{
+		"hab-local": {
+			{ID: "q-1", Slug: "triage", Name: "Triage"},
+			{ID: "q-2", Slug: "review", Name: "Code Review"},
+		},
+	}}
+
+	r := &Resolver{Lister: lister, Prompter: &fakePrompter{}}
+
+	got, err := r.Queue(t.Context(), "hab-local", "code review")
+	if err != nil || got.ID != "q-2" {
+		t.Fatalf("Queue() = %+v, %v; want q-2", got, err)
+	}
+
+	_, err = r.Queue(t.Context(), "hab-local", "")
+
+	var cliErr *clierrors.CLIError
+	if !errors.As(err, &cliErr) || cliErr.Code != clierrors.ExitUsage {
+		t.Fatalf("Queue() with no input error = %v, want QueueRequired", err)
+	}
+
+	_, err = r.Queue(t.Context(), "hab-other", "triage")
+	if !errors.As(err, &cliErr) || cliErr.Code != clierrors.ExitConfig {
+		t.Fatalf("Queue() in another habitat error = %v, want QueueNotFound", err)
+	}
+}
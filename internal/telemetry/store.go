@@ -0,0 +1,279 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/musher-dev/mush/internal/buildinfo"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+const (
+	queueFileName = "queue.json"
+	idFileName    = "id"
+
+	// retryDelay spaces out attempts to send a report that failed, so an
+	// unreachable API does not slow down every command.
+	retryDelay = time.Hour
+)
+
+// queue is the on-disk aggregate of events not yet sent.
+type queue struct {
+	Since    time.Time                `json:"since"`
+	RetryAt  time.Time                `json:"retryAt,omitempty"`
+	Commands map[string]*CommandStats `json:"commands"`
+}
+
+// Store is the local telemetry queue and anonymous install ID.
+type Store struct {
+	dir string
+	now func() time.Time
+}
+
+// NewStore returns a store kept in dir.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir, now: time.Now}
+}
+
+// DefaultStore returns the store in the Mush state directory.
+func DefaultStore() (*Store, error) {
+	dir, err := paths.TelemetryDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve telemetry directory: %w", err)
+	}
+
+	return NewStore(dir), nil
+}
+
+// Dir returns the directory holding the queue and install ID.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Record adds an event to the local queue.
+func (s *Store) Record(event Event) error {
+	command := strings.TrimSpace(event.Command)
+	if command == "" {
+		return nil
+	}
+
+	q, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	stats, ok := q.Commands[command]
+	if !ok {
+		stats = &CommandStats{Command: command}
+		q.Commands[command] = stats
+	}
+
+	stats.Count++
+	stats.TotalDurationMS += event.Duration.Milliseconds()
+
+	if class := ErrorClass(event.ExitCode); class != "" {
+		if stats.Errors == nil {
+			stats.Errors = make(map[string]int)
+		}
+
+		stats.Errors[class]++
+	}
+
+	return s.save(q)
+}
+
+// Pending returns the report that the next flush would send. It does not
+// create an install ID; InstallID is empty until the first flush.
+func (s *Store) Pending() (*Report, error) {
+	q, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	installID, _, err := s.readInstallID()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.report(q, installID), nil
+}
+
+// Due reports whether the queue has aggregated for FlushInterval and holds
+// events to send.
+func (s *Store) Due() bool {
+	q, err := s.load()
+	if err != nil {
+		return false
+	}
+
+	now := s.now()
+
+	return len(q.Commands) > 0 && now.Sub(q.Since) >= FlushInterval && !now.Before(q.RetryAt)
+}
+
+// Flush sends the queued report with send and starts a new period. When
+// send fails the queue is kept and the next attempt waits for retryDelay.
+func (s *Store) Flush(ctx context.Context, send func(context.Context, *Report) error) error {
+	q, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if len(q.Commands) == 0 {
+		return nil
+	}
+
+	installID, err := s.InstallID()
+	if err != nil {
+		return err
+	}
+
+	if sendErr := send(ctx, s.report(q, installID)); sendErr != nil {
+		q.RetryAt = s.now().Add(retryDelay)
+
+		return errors.Join(fmt.Errorf("send usage report: %w", sendErr), s.save(q))
+	}
+
+	return s.save(&queue{Since: s.now(), Commands: map[string]*CommandStats{}})
+}
+
+// Reset deletes the queue and install ID, so re-enabling telemetry starts
+// with a new anonymous ID.
+func (s *Store) Reset() error {
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("remove telemetry data: %w", err)
+	}
+
+	return nil
+}
+
+// InstallID returns the random ID identifying this install's reports,
+// creating it on first use. It is not derived from the machine or account.
+func (s *Store) InstallID() (string, error) {
+	id, exists, err := s.readInstallID()
+	if err != nil || exists {
+		return id, err
+	}
+
+	if err := safeio.MkdirAll(s.dir, 0o700); err != nil {
+		return "", fmt.Errorf("create telemetry directory: %w", err)
+	}
+
+	id = uuid.NewString()
+	if err := safeio.WriteFile(filepath.Join(s.dir, idFileName), []byte(id+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("write telemetry install ID: %w", err)
+	}
+
+	return id, nil
+}
+
+func (s *Store) readInstallID() (id string, exists bool, err error) {
+	data, exists, err := safeio.ReadFileIfExists(filepath.Join(s.dir, idFileName))
+	if err != nil {
+		return "", false, fmt.Errorf("read telemetry install ID: %w", err)
+	}
+
+	id = strings.TrimSpace(string(data))
+
+	return id, exists && id != "", nil
+}
+
+func (s *Store) report(q *queue, installID string) *Report {
+	commands := make([]CommandStats, 0, len(q.Commands))
+	for _, stats := range q.Commands {
+		commands = append(commands, *stats)
+	}
+
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].Command < commands[j].Command
+	})
+
+	return &Report{
+		SchemaVersion: SchemaVersion,
+		InstallID:     installID,
+		Version:       buildinfo.Version,
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		PeriodStart:   q.Since.UTC(),
+		PeriodEnd:     s.now().UTC(),
+		Commands:      commands,
+	}
+}
+
+// load reads the queue, starting a new one when none exists or the file is
+// unreadable; usage data is not worth failing a command over.
+func (s *Store) load() (*queue, error) {
+	data, exists, err := safeio.ReadFileIfExists(filepath.Join(s.dir, queueFileName))
+	if err != nil {
+		return nil, fmt.Errorf("read telemetry queue: %w", err)
+	}
+
+	q := &queue{}
+	if !exists || json.Unmarshal(data, q) != nil || q.Since.IsZero() {
+		q = &queue{Since: s.now()}
+	}
+
+	if q.Commands == nil {
+		q.Commands = map[string]*CommandStats{}
+	}
+
+	return q, nil
+}
+
+// save writes the queue atomically so concurrent commands never leave a
+// partial file behind.
+func (s *Store) save(q *queue) error {
+	if err := safeio.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create telemetry directory: %w", err)
+	}
+
+	data, err := json.Marshal(q)
+	if err != nil {
+		return fmt.Errorf("marshal telemetry queue: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(s.dir, queueFileName+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp telemetry queue: %w", err)
+	}
+
+	tmp := tmpFile.Name()
+	if _, writeErr := tmpFile.Write(data); writeErr != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("write temp telemetry queue: %w", writeErr)
+	}
+
+	if closeErr := tmpFile.Close(); closeErr != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close temp telemetry queue: %w", closeErr)
+	}
+
+	path := filepath.Join(s.dir, queueFileName)
+	if err := os.Rename(tmp, path); err != nil {
+		// Fallback for Windows: remove dest then retry rename
+		if removeErr := os.Remove(path); removeErr != nil && !os.IsNotExist(removeErr) {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("remove existing telemetry queue: %w", removeErr)
+		}
+
+		if retryErr := os.Rename(tmp, path); retryErr != nil {
+			_ = os.Remove(tmp)
+			return fmt.Errorf("replace telemetry queue: %w", retryErr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+)
+
+func newTestStore(t *testing.T, now *time.Time) *Store {
+	t.Helper()
+
+	store := NewStore(filepath.Join(t.TempDir(), "telemetry"))
+	store.now = func() time.Time { return *now }
+
+	return store
+}
+
+func TestStore_RecordAggregates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(t, &now)
+
+	events := []Event{
+		{Command: "bundle load", Duration: 2 * time.Second},
+		{Command: "bundle load", ExitCode: clierrors.ExitNetwork, Duration: time.Second},
+		{Command: "worker start", ExitCode: clierrors.ExitUsage},
+		{Command: ""},
+	}
+
+	for _, event := range events {
+		if err := store.Record(event); err != nil {
+			t.Fatalf("Record(%+v) error = %v", event, err)
+		}
+	}
+
+	report, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+
+	if len(report.Commands) != 2 {
+		t.Fatalf("Pending() commands = %+v, want 2 entries", report.Commands)
+	}
+
+	load := report.Commands[0]
+	if load.Command != "bundle load" || load.Count != 2 || load.TotalDurationMS != 3000 || load.Errors[ErrorNetwork] != 1 {
+		t.Fatalf("bundle load stats = %+v", load)
+	}
+
+	if start := report.Commands[1]; start.Command != "worker start" || start.Errors[ErrorUsage] != 1 {
+		t.Fatalf("worker start stats = %+v", start)
+	}
+
+	if report.InstallID != "" {
+		t.Fatalf("Pending() InstallID = %q, want none before the first flush", report.InstallID)
+	}
+}
+
+func TestStore_FlushWhenDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store := newTestStore(t, &now)
+
+	if err := store.Record(Event{Command: "doctor"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if store.Due() {
+		t.Fatal("Due() = true before FlushInterval elapsed")
+	}
+
+	now = now.Add(FlushInterval)
+
+	if !store.Due() {
+		t.Fatal("Due() = false after FlushInterval elapsed")
+	}
+
+	sendErr := errors.New("unreachable")
+	if err := store.Flush(context.Background(), func(context.Context, *Report) error { return sendErr }); !errors.Is(err, sendErr) {
+		t.Fatalf("Flush() error = %v, want %v", err, sendErr)
+	}
+
+	if store.Due() {
+		t.Fatal("Due() = true right after a failed flush")
+	}
+
+	now = now.Add(retryDelay)
+
+	var sent *Report
+
+	err := store.Flush(context.Background(), func(_ context.Context, report *Report) error {
+		sent = report
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if sent == nil || sent.InstallID == "" || len(sent.Commands) != 1 || sent.Commands[0].Count != 1 {
+		t.Fatalf("Flush() sent %+v", sent)
+	}
+
+	pending, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+
+	if len(pending.Commands) != 0 || pending.InstallID != sent.InstallID {
+		t.Fatalf("Pending() after flush = %+v, want an empty queue with the same install ID", pending)
+	}
+}
+
+func TestStore_ResetRemovesData(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(t, &now)
+
+	id, err := store.InstallID()
+	if err != nil {
+		t.Fatalf("InstallID() error = %v", err)
+	}
+
+	if err := store.Record(Event{Command: "version"}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	if err := store.Reset(); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+
+	if _, err := os.Stat(store.Dir()); !os.IsNotExist(err) {
+		t.Fatalf("Stat(%s) error = %v, want not exist", store.Dir(), err)
+	}
+
+	newID, err := store.InstallID()
+	if err != nil {
+		t.Fatalf("InstallID() error = %v", err)
+	}
+
+	if newID == id {
+		t.Fatal("InstallID() reused the ID after Reset()")
+	}
+}
+
+func TestReport_OmitsUnlistedFields(t *testing.T) {
+	now := time.Now()
+	store := newTestStore(t, &now)
+
+	if err := store.Record(Event{Command: "bundle run", ExitCode: clierrors.ExitExecution}); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+
+	report, err := store.Pending()
+	if err != nil {
+		t.Fatalf("Pending() error = %v", err)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	want := []string{"arch", "commands", "installId", "os", "periodEnd", "periodStart", "schemaVersion", "version"}
+	if len(fields) != len(want) {
+		t.Fatalf("report fields = %v, want only %s (update docs/telemetry.md when adding fields)", fields, strings.Join(want, ", "))
+	}
+
+	for _, key := range want {
+		if _, ok := fields[key]; !ok {
+			t.Fatalf("report is missing %q: %s", key, data)
+		}
+	}
+}
+
+func TestDoNotTrack(t *testing.T) {
+	for value, want := range map[string]bool{"": false, "0": false, "false": false, "1": true, "true": true} {
+		t.Setenv("DO_NOT_TRACK", value)
+
+		if got := DoNotTrack(); got != want {
+			t.Fatalf("DoNotTrack() with %q = %v, want %v", value, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+// Package telemetry records opt-in, anonymous command usage for Mush.
+//
+// Only which command ran, how it exited, and how long it took are recorded,
+// along with the Mush version and OS/architecture. Arguments, flag values,
+// prompts, job output, and account details are never recorded. Events are
+// aggregated in a local queue and sent at most once per FlushInterval. See
+// docs/telemetry.md for the exact payload.
+package telemetry
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	clierrors "github.com/musher-dev/mush/internal/errors"
+)
+
+// SchemaVersion identifies the Report payload format.
+const SchemaVersion = 1
+
+// FlushInterval is how long usage is aggregated locally before it is sent.
+const FlushInterval = 24 * time.Hour
+
+// Error classes reported in place of error messages, which may contain
+// paths, URLs, or other identifying details.
+const (
+	ErrorGeneral   = "general"
+	ErrorAuth      = "auth"
+	ErrorNetwork   = "network"
+	ErrorConfig    = "config"
+	ErrorTimeout   = "timeout"
+	ErrorExecution = "execution"
+	ErrorUsage     = "usage"
+	ErrorTemporary = "temporary"
+)
+
+// Event is a single command invocation.
+type Event struct {
+	// Command is the command path without the binary name, e.g. "bundle load".
+	Command  string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Report is the payload sent to the Musher API.
+type Report struct {
+	SchemaVersion int            `json:"schemaVersion"`
+	InstallID     string         `json:"installId"`
+	Version       string         `json:"version"`
+	OS            string         `json:"os"`
+	Arch          string         `json:"arch"`
+	PeriodStart   time.Time      `json:"periodStart"`
+	PeriodEnd     time.Time      `json:"periodEnd"`
+	Commands      []CommandStats `json:"commands"`
+}
+
+// CommandStats aggregates the invocations of one command.
+type CommandStats struct {
+	Command         string         `json:"command"`
+	Count           int            `json:"count"`
+	Errors          map[string]int `json:"errors,omitempty"`
+	TotalDurationMS int64          `json:"totalDurationMs"`
+}
+
+// ErrorClass maps a CLI exit code to the error class reported for it. It
+// returns "" for a successful exit.
+func ErrorClass(exitCode int) string {
+	switch exitCode {
+	case clierrors.ExitSuccess:
+		return ""
+	case clierrors.ExitAuth:
+		return ErrorAuth
+	case clierrors.ExitNetwork:
+		return ErrorNetwork
+	case clierrors.ExitConfig:
+		return ErrorConfig
+	case clierrors.ExitTimeout:
+		return ErrorTimeout
+	case clierrors.ExitExecution:
+		return ErrorExecution
+	case clierrors.ExitUsage:
+		return ErrorUsage
+	case clierrors.ExitTempFail:
+		return ErrorTemporary
+	default:
+		return ErrorGeneral
+	}
+}
+
+// DoNotTrack reports whether the DO_NOT_TRACK convention
+// (https://consoledonottrack.com) is set, which overrides an opt-in.
+func DoNotTrack() bool {
+	value := strings.TrimSpace(os.Getenv("DO_NOT_TRACK"))
+
+	return value != "" && value != "0" && !strings.EqualFold(value, "false")
+}
@@ -0,0 +1,144 @@
+package terminal
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/executil"
+)
+
+// maxOSC52Payload is the largest base64 payload sent over OSC 52. Several
+// terminals (hterm, tmux's default) silently drop longer sequences.
+const maxOSC52Payload = 100000
+
+// ErrNoClipboard is returned when neither OSC 52 nor a clipboard command is
+// available.
+var ErrNoClipboard = errors.New("no clipboard available")
+
+// clipboardCommand is a native clipboard tool that reads the text on stdin.
+type clipboardCommand struct {
+	name string
+	args []string
+	// needsEnv restricts the tool to sessions where the variable is set.
+	needsEnv string
+}
+
+// clipboardCommands are tried in order when OSC 52 can't be used.
+var clipboardCommands = []clipboardCommand{
+	{name: "pbcopy"},
+	{name: "wl-copy", needsEnv: "WAYLAND_DISPLAY"},
+	{name: "xclip", args: []string{"-selection", "clipboard"}, needsEnv: "DISPLAY"},
+	{name: "xsel", args: []string{"--clipboard", "--input"}, needsEnv: "DISPLAY"},
+}
+
+// Clipboard copies text to the system clipboard. It prefers OSC 52, which
+// works over SSH and inside tmux, and falls back to a native clipboard
+// command when the terminal is known not to support it or the text is too
+// large for an escape sequence.
+type Clipboard struct {
+	// TTY receives the OSC 52 sequence.
+	TTY io.Writer
+
+	// Test seams; nil means the real environment and PATH.
+	getenvFn func(string) string
+	lookPath func(string) (string, error)
+	run      func(ctx context.Context, path string, args []string, text string) error
+}
+
+// Copy places text on the clipboard and returns how it was delivered:
+// "OSC 52" or the name of the clipboard command.
+func (c *Clipboard) Copy(ctx context.Context, text string) (string, error) {
+	payload := base64.StdEncoding.EncodeToString([]byte(text))
+
+	if c.TTY != nil && len(payload) <= maxOSC52Payload && supportsOSC52(c.getenv) {
+		if _, err := io.WriteString(c.TTY, osc52Sequence(payload, c.getenv("TMUX") != "")); err != nil {
+			return "", fmt.Errorf("write OSC 52 sequence: %w", err)
+		}
+
+		return "OSC 52", nil
+	}
+
+	lookPath := c.lookPath
+	if lookPath == nil {
+		lookPath = executil.LookPath
+	}
+
+	run := c.run
+	if run == nil {
+		run = runClipboardCommand
+	}
+
+	for _, cmd := range clipboardCommands {
+		if cmd.needsEnv != "" && c.getenv(cmd.needsEnv) == "" {
+			continue
+		}
+
+		path, err := lookPath(cmd.name)
+		if err != nil {
+			continue
+		}
+
+		if err := run(ctx, path, cmd.args, text); err != nil {
+			return "", fmt.Errorf("copy with %s: %w", cmd.name, err)
+		}
+
+		return cmd.name, nil
+	}
+
+	return "", ErrNoClipboard
+}
+
+func (c *Clipboard) getenv(key string) string {
+	if c.getenvFn != nil {
+		return c.getenvFn(key)
+	}
+
+	return os.Getenv(key)
+}
+
+// osc52Sequence returns the escape sequence that sets the clipboard to the
+// base64-encoded payload. Inside tmux the sequence is wrapped in a DCS
+// passthrough so it reaches the outer terminal.
+func osc52Sequence(payload string, tmux bool) string {
+	seq := ansi.OSC + "52;c;" + payload + ansi.BEL
+	if !tmux {
+		return seq
+	}
+
+	return ansi.DCS + "tmux;" + strings.ReplaceAll(seq, ansi.ESC, ansi.ESC+ansi.ESC) + ansi.ST
+}
+
+// supportsOSC52 reports whether the terminal is expected to honor OSC 52.
+// Only terminals known to ignore it are excluded; most modern emulators and
+// multiplexers accept it.
+func supportsOSC52(getenv func(string) string) bool {
+	switch {
+	case getenv("TERM") == "dumb", getenv("TERM") == "linux":
+		return false
+	case getenv("TERM_PROGRAM") == "Apple_Terminal":
+		return false
+	}
+
+	return true
+}
+
+func runClipboardCommand(ctx context.Context, path string, args []string, text string) error {
+	cmd, err := executil.AbsoluteCommandContext(ctx, path, args...)
+	if err != nil {
+		return err //nolint:wrapcheck // executil errors already describe the path
+	}
+
+	cmd.Stdin = strings.NewReader(text)
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run %s: %w", path, err)
+	}
+
+	return nil
+}
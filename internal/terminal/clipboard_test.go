@@ -0,0 +1,103 @@
+package terminal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func envFrom(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestOSC52Sequence(t *testing.T) {
+	if got := osc52Sequence("aGk=", false); got != "\x1b]52;c;aGk=\a" {
+		t.Errorf("plain sequence = %q", got)
+	}
+
+	want := "\x1bPtmux;\x1b\x1b]52;c;aGk=\a\x1b\\"
+	if got := osc52Sequence("aGk=", true); got != want {
+		t.Errorf("tmux sequence = %q, want %q", got, want)
+	}
+}
+
+func TestClipboard_PrefersOSC52(t *testing.T) {
+	var tty bytes.Buffer
+
+	c := &Clipboard{
+		TTY:      &tty,
+		getenvFn: envFrom(map[string]string{"TERM": "xterm-256color"}),
+		run: func(context.Context, string, []string, string) error {
+			t.Fatal("clipboard command should not run when OSC 52 is available")
+			return nil
+		},
+	}
+
+	method, err := c.Copy(t.Context(), "hi")
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	if method != "OSC 52" || tty.String() != "\x1b]52;c;aGk=\a" {
+		t.Errorf("Copy() = %q, tty = %q", method, tty.String())
+	}
+}
+
+func TestClipboard_FallsBackToCommand(t *testing.T) {
+	var (
+		tty     bytes.Buffer
+		ranPath string
+		ranText string
+	)
+
+	c := &Clipboard{
+		TTY:      &tty,
+		getenvFn: envFrom(map[string]string{"TERM_PROGRAM": "Apple_Terminal", "DISPLAY": ":0"}),
+		lookPath: func(name string) (string, error) {
+			if name == "xclip" {
+				return "/usr/bin/xclip", nil
+			}
+
+			return "", errors.New("not found")
+		},
+		run: func(_ context.Context, path string, _ []string, text string) error {
+			ranPath, ranText = path, text
+
+			return nil
+		},
+	}
+
+	method, err := c.Copy(t.Context(), "copied")
+	if err != nil {
+		t.Fatalf("Copy() error = %v", err)
+	}
+
+	if method != "xclip" || ranPath != "/usr/bin/xclip" || ranText != "copied" {
+		t.Errorf("Copy() = %q, ran %q with %q", method, ranPath, ranText)
+	}
+
+	if tty.Len() > 0 {
+		t.Errorf("OSC 52 written to a terminal that ignores it: %q", tty.String())
+	}
+}
+
+func TestClipboard_LargeTextSkipsOSC52(t *testing.T) {
+	var tty bytes.Buffer
+
+	c := &Clipboard{
+		TTY:      &tty,
+		getenvFn: envFrom(nil),
+		lookPath: func(string) (string, error) { return "", errors.New("not found") },
+	}
+
+	_, err := c.Copy(t.Context(), strings.Repeat("x", maxOSC52Payload))
+	if !errors.Is(err, ErrNoClipboard) {
+		t.Fatalf("Copy() error = %v, want ErrNoClipboard", err)
+	}
+
+	if tty.Len() > 0 {
+		t.Error("oversized payload should not be sent over OSC 52")
+	}
+}
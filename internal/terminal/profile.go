@@ -0,0 +1,135 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ProfileAuto selects a profile from the environment with DetectProfile.
+const ProfileAuto = "auto"
+
+// Profile bundles the settings the harness TUI uses for one kind of
+// terminal, so a terminal that misbehaves can be fixed with one switch.
+type Profile struct {
+	// Name selects the profile with --terminal-profile or terminal.profile.
+	Name string
+
+	// Description says what the profile is for.
+	Description string
+
+	// Sidebar shows the status sidebar beside the harness when the
+	// terminal is wide enough.
+	Sidebar bool
+
+	// Color enables colored output. A profile without it sets NO_COLOR for
+	// the session.
+	Color bool
+
+	// Mouse captures mouse events for scrolling and sidebar clicks. Without
+	// it the terminal keeps the mouse for its own text selection.
+	Mouse bool
+}
+
+// profiles are the built-in profiles; the first is the fallback when
+// detection finds nothing more specific.
+var profiles = []Profile{
+	{
+		Name:        "default",
+		Description: "Full TUI for terminals with no known problems",
+		Sidebar:     true,
+		Color:       true,
+		Mouse:       true,
+	},
+	{
+		Name:        "iterm2",
+		Description: "iTerm2",
+		Sidebar:     true,
+		Color:       true,
+		Mouse:       true,
+	},
+	{
+		Name:        "vscode",
+		Description: "VS Code's integrated terminal: no sidebar in the narrow panel, and the mouse left for selection",
+		Sidebar:     false,
+		Color:       true,
+		Mouse:       false,
+	},
+	{
+		Name:        "tmux",
+		Description: "tmux and screen, where mouse capture conflicts with the multiplexer's own",
+		Sidebar:     true,
+		Color:       true,
+		Mouse:       false,
+	},
+	{
+		Name:        "ssh-dumb",
+		Description: "Minimal terminals, such as TERM=dumb or a bare SSH session: no sidebar, color, or mouse",
+		Sidebar:     false,
+		Color:       false,
+		Mouse:       false,
+	},
+}
+
+// Profiles returns the built-in profiles.
+func Profiles() []Profile {
+	return append([]Profile(nil), profiles...)
+}
+
+// ProfileNames returns the names --terminal-profile accepts, auto first.
+func ProfileNames() []string {
+	names := []string{ProfileAuto}
+	for _, p := range profiles {
+		names = append(names, p.Name)
+	}
+
+	return names
+}
+
+// LookupProfile returns the built-in profile with name, ignoring case.
+func LookupProfile(name string) (Profile, bool) {
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, strings.TrimSpace(name)) {
+			return p, true
+		}
+	}
+
+	return Profile{}, false
+}
+
+// ResolveProfile returns the profile named name, or the detected one for
+// "" or "auto".
+func ResolveProfile(name string) (Profile, error) {
+	if name = strings.TrimSpace(name); name == "" || strings.EqualFold(name, ProfileAuto) {
+		return DetectProfile(os.Getenv), nil
+	}
+
+	if p, ok := LookupProfile(name); ok {
+		return p, nil
+	}
+
+	return Profile{}, fmt.Errorf("unknown terminal profile %q (want one of %s)", name, strings.Join(ProfileNames(), ", "))
+}
+
+// DetectProfile picks a profile from TERM, TERM_PROGRAM, and the
+// multiplexer variables in the environment getenv reads.
+func DetectProfile(getenv func(string) string) Profile {
+	name := "default"
+
+	term := getenv("TERM")
+
+	switch {
+	case term == "dumb" || (term == "" && getenv("SSH_CONNECTION") != ""):
+		name = "ssh-dumb"
+	case getenv("TMUX") != "" || getenv("TERM_PROGRAM") == "tmux" || strings.HasPrefix(term, "screen") || strings.HasPrefix(term, "tmux"):
+		name = "tmux"
+	case getenv("TERM_PROGRAM") == "vscode":
+		name = "vscode"
+	case getenv("TERM_PROGRAM") == "iTerm.app":
+		name = "iterm2"
+	}
+
+	p, _ := LookupProfile(name)
+
+	return p
+}
@@ -0,0 +1,49 @@
+package terminal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectProfile(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{name: "unknown terminal", env: map[string]string{"TERM": "xterm-256color"}, want: "default"},
+		{name: "iterm2", env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, want: "iterm2"},
+		{name: "vscode", env: map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "vscode"}, want: "vscode"},
+		{name: "tmux inside vscode", env: map[string]string{"TERM": "tmux-256color", "TERM_PROGRAM": "vscode", "TMUX": "/tmp/tmux-1/default"}, want: "tmux"},
+		{name: "screen", env: map[string]string{"TERM": "screen"}, want: "tmux"},
+		{name: "dumb", env: map[string]string{"TERM": "dumb", "TERM_PROGRAM": "iTerm.app"}, want: "ssh-dumb"},
+		{name: "ssh without TERM", env: map[string]string{"SSH_CONNECTION": "10.0.0.1 22 10.0.0.2 22"}, want: "ssh-dumb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectProfile(envFrom(tt.env)); got.Name != tt.want {
+				t.Errorf("DetectProfile() = %q, want %q", got.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveProfile(t *testing.T) {
+	t.Setenv("TERM", "dumb")
+
+	for _, name := range []string{"", "auto", " AUTO "} {
+		if got, err := ResolveProfile(name); err != nil || got.Name != "ssh-dumb" {
+			t.Errorf("ResolveProfile(%q) = %q, %v; want the detected ssh-dumb", name, got.Name, err)
+		}
+	}
+
+	got, err := ResolveProfile("VSCode")
+	if err != nil || got.Name != "vscode" || got.Sidebar || got.Mouse || !got.Color {
+		t.Errorf("ResolveProfile(VSCode) = %+v, %v; want vscode without sidebar or mouse", got, err)
+	}
+
+	if _, err := ResolveProfile("kitty"); err == nil || !strings.Contains(err.Error(), "auto, default, iterm2") {
+		t.Errorf("ResolveProfile(kitty) error = %v, want the accepted names", err)
+	}
+}
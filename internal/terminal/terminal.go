@@ -4,6 +4,8 @@
 //   - TTY detection for stdout/stderr
 //   - NO_COLOR environment variable support
 //   - Terminal dimensions
+//   - Clipboard writes via OSC 52 or a native clipboard command
+//   - Named profiles of TUI settings for terminals with known problems
 package terminal
 
 import (
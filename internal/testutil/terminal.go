@@ -0,0 +1,49 @@
+package testutil
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+// DescribeTerminalOutput renders raw terminal output as readable text for
+// golden files. Escape sequences are shown in angle brackets, e.g.
+// <CSI 48;5;236 m>, and each cursor position, save, or restore starts a new
+// line, so a diff points at the screen row that changed. Control characters
+// in text are quoted Go-style.
+func DescribeTerminalOutput(s string) string {
+	var b strings.Builder
+
+	for _, tok := range ansi.Tokenize(s) {
+		switch tok.Kind {
+		case ansi.KindText:
+			quoted := strconv.Quote(tok.Raw)
+			b.WriteString(quoted[1 : len(quoted)-1])
+		case ansi.KindCSI:
+			if tok.Final == 'H' {
+				startLine(&b)
+			}
+
+			b.WriteString("<CSI " + tok.Params + tok.Intermediate + " " + string(tok.Final) + ">")
+		case ansi.KindEscape:
+			if tok.Final == '7' || tok.Final == '8' {
+				startLine(&b)
+			}
+
+			b.WriteString("<ESC " + tok.Intermediate + string(tok.Final) + ">")
+		default:
+			b.WriteString("<" + strings.ToUpper(tok.Kind.String()) + " " + strconv.Quote(tok.Data) + ">")
+		}
+	}
+
+	startLine(&b)
+
+	return b.String()
+}
+
+func startLine(b *strings.Builder) {
+	if b.Len() > 0 && !strings.HasSuffix(b.String(), "\n") {
+		b.WriteByte('\n')
+	}
+}
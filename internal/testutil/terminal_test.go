@@ -0,0 +1,15 @@
+package testutil
+
+import "testing"
+
+func TestDescribeTerminalOutput(t *testing.T) {
+	in := "\x1b7\x1b[1;1H\x1b[2K\x1b[48;5;236m MUSH\t\x1b[0m\x1b]0;title\a\x1b[2;1Hrow\x1b8"
+	want := "<ESC 7>\n" +
+		"<CSI 1;1 H><CSI 2 K><CSI 48;5;236 m> MUSH\\t<CSI 0 m><OSC \"0;title\">\n" +
+		"<CSI 2;1 H>row\n" +
+		"<ESC 8>\n"
+
+	if got := DescribeTerminalOutput(in); got != want {
+		t.Fatalf("DescribeTerminalOutput() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,166 @@
+package transcript
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/musher-dev/mush/internal/ansi"
+)
+
+// Default terminal size written to an asciicast. Transcripts do not record
+// the size of the terminal the harness drew for.
+const (
+	DefaultAsciicastWidth  = 120
+	DefaultAsciicastHeight = 40
+)
+
+// AsciicastOptions describes the recording's header.
+type AsciicastOptions struct {
+	// Width and Height are the terminal size in columns and rows.
+	Width  int
+	Height int
+
+	// Title is shown by players, if set.
+	Title string
+}
+
+// asciicastHeader is the first line of an asciicast v2 file.
+type asciicastHeader struct {
+	Version       int               `json:"version"`
+	Width         int               `json:"width"`
+	Height        int               `json:"height"`
+	Timestamp     int64             `json:"timestamp,omitempty"`
+	IdleTimeLimit float64           `json:"idle_time_limit,omitempty"`
+	Title         string            `json:"title,omitempty"`
+	Env           map[string]string `json:"env,omitempty"`
+}
+
+// ExportText writes the terminal output of events as plain text: escape
+// sequences removed, carriage returns treated as line breaks, and blank runs
+// collapsed.
+func ExportText(w io.Writer, events []Event) error {
+	var (
+		stripper ansi.Stripper
+		text     strings.Builder
+	)
+
+	for i := range events {
+		text.Write(stripper.Strip(events[i].Raw()))
+	}
+
+	lines := plainLines(text.String())
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\n")+"\n"); err != nil {
+		return fmt.Errorf("write text export: %w", err)
+	}
+
+	return nil
+}
+
+// ExportAsciicast writes events as an asciinema v2 recording, timed from
+// the first event. Players shorten pauses longer than DefaultReplayMaxIdle,
+// as Replay does.
+func ExportAsciicast(w io.Writer, events []Event, opts AsciicastOptions) error {
+	header := asciicastHeader{
+		Version:       2,
+		Width:         opts.Width,
+		Height:        opts.Height,
+		IdleTimeLimit: DefaultReplayMaxIdle.Seconds(),
+		Title:         opts.Title,
+		Env:           map[string]string{"TERM": "xterm-256color"},
+	}
+
+	if header.Width <= 0 {
+		header.Width = DefaultAsciicastWidth
+	}
+
+	if header.Height <= 0 {
+		header.Height = DefaultAsciicastHeight
+	}
+
+	if len(events) > 0 {
+		header.Timestamp = events[0].TS.Unix()
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(header); err != nil {
+		return fmt.Errorf("write asciicast header: %w", err)
+	}
+
+	// A chunk may end partway through a UTF-8 character; the rest of it
+	// arrives with the next one.
+	var pending []byte
+
+	for i := range events {
+		data, rest := splitIncompleteUTF8(append(pending, events[i].Raw()...))
+		pending = rest
+
+		if len(data) == 0 {
+			continue
+		}
+
+		elapsed := max(events[i].TS.Sub(events[0].TS).Seconds(), 0)
+		if err := enc.Encode([]any{roundSeconds(elapsed), "o", string(data)}); err != nil {
+			return fmt.Errorf("write asciicast event: %w", err)
+		}
+	}
+
+	if len(pending) > 0 {
+		elapsed := max(events[len(events)-1].TS.Sub(events[0].TS).Seconds(), 0)
+		if err := enc.Encode([]any{roundSeconds(elapsed), "o", string(pending)}); err != nil {
+			return fmt.Errorf("write asciicast event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ExportJSON writes events as JSON Lines, one event per line, in the form
+// they are stored.
+func ExportJSON(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	for i := range events {
+		if err := enc.Encode(&events[i]); err != nil {
+			return fmt.Errorf("write json export: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// splitIncompleteUTF8 splits off a UTF-8 character cut short at the end of
+// b. Invalid bytes elsewhere are left for the encoder to replace.
+func splitIncompleteUTF8(b []byte) (complete, rest []byte) {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+
+		if !utf8.FullRune(b[i:]) {
+			return b[:i], append([]byte(nil), b[i:]...)
+		}
+
+		break
+	}
+
+	return b, nil
+}
+
+// roundSeconds rounds to microseconds, the precision asciinema records.
+func roundSeconds(s float64) float64 {
+	return float64(int64(s*1e6+0.5)) / 1e6
+}
@@ -0,0 +1,97 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportText(t *testing.T) {
+	start := time.Now()
+	events := []Event{
+		replayEvent(start, "\x1b[1mBuilding\x1b[0m  \r\n\r\n\r\n"),
+		replayEvent(start, "50%\r100%\x1b["),
+		replayEvent(start, "32m done\x1b[0m\r\n\r\n"),
+	}
+
+	var buf bytes.Buffer
+	if err := ExportText(&buf, events); err != nil {
+		t.Fatalf("ExportText() error = %v", err)
+	}
+
+	if got, want := buf.String(), "Building\n\n50%\n100% done\n"; got != want {
+		t.Fatalf("ExportText() = %q, want %q", got, want)
+	}
+}
+
+func TestExportAsciicast(t *testing.T) {
+	start := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	check := "✓"
+	events := []Event{
+		replayEvent(start, "\x1b[32m"+check[:1]),
+		replayEvent(start.Add(1500*time.Millisecond), check[1:]+" ok\r\n"),
+	}
+
+	var buf bytes.Buffer
+	if err := ExportAsciicast(&buf, events, AsciicastOptions{Width: 100, Title: "job-1"}); err != nil {
+		t.Fatalf("ExportAsciicast() error = %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	if len(lines) != 3 {
+		t.Fatalf("asciicast = %q, want a header and 2 events", lines)
+	}
+
+	var header asciicastHeader
+	if err := json.Unmarshal([]byte(lines[0]), &header); err != nil {
+		t.Fatalf("decode header %q: %v", lines[0], err)
+	}
+
+	if header.Version != 2 || header.Width != 100 || header.Height != DefaultAsciicastHeight ||
+		header.Timestamp != start.Unix() || header.Title != "job-1" || header.IdleTimeLimit != 2 {
+		t.Fatalf("header = %+v, want version 2, 100x%d, the first event's time, and the title", header, DefaultAsciicastHeight)
+	}
+
+	want := []string{
+		`[0,"o","\u001b[32m"]`,
+		`[1.5,"o","✓ ok\r\n"]`,
+	}
+	for i, line := range lines[1:] {
+		if line != want[i] {
+			t.Fatalf("event %d = %s, want %s", i, line, want[i])
+		}
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	start := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	events := []Event{
+		{SessionID: "s", Seq: 1, TS: start, Stream: "pty", RawBase64: base64.StdEncoding.EncodeToString([]byte("<b>")), Text: "<b>"},
+		{SessionID: "s", Seq: 2, TS: start, Stream: JobStream, Job: &JobRecord{Event: JobStarted, JobID: "job-1"}},
+	}
+
+	var buf bytes.Buffer
+	if err := ExportJSON(&buf, events); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"text":"<b>"`) {
+		t.Fatalf("ExportJSON() = %q, want one unescaped event per line", buf.String())
+	}
+
+	var got Event
+	if err := json.Unmarshal([]byte(lines[1]), &got); err != nil || got.Job == nil || got.Job.JobID != "job-1" {
+		t.Fatalf("decode %q = %+v, %v; want the job record", lines[1], got, err)
+	}
+}
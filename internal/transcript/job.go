@@ -0,0 +1,72 @@
+package transcript
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/musher-dev/mush/internal/netrecord"
+)
+
+// JobStream is the transcript stream job start and end records are on.
+const JobStream = "job"
+
+// JobRecord events.
+const (
+	JobStarted  = "start"
+	JobFinished = "end"
+)
+
+// JobRecord marks where a job starts or finishes in a session, with what a
+// report needs to describe it.
+type JobRecord struct {
+	// Event is JobStarted or JobFinished.
+	Event string `json:"event"`
+
+	JobID   string `json:"jobId"`
+	Name    string `json:"name,omitempty"`
+	Harness string `json:"harness,omitempty"`
+	QueueID string `json:"queueId,omitempty"`
+	Attempt int    `json:"attempt,omitempty"`
+
+	// Prompt is the instruction the job was started with.
+	Prompt string `json:"prompt,omitempty"`
+
+	// Status is "completed" or "failed" when the job finishes.
+	Status     string    `json:"status,omitempty"`
+	StartedAt  time.Time `json:"startedAt"`
+	DurationMs int64     `json:"durationMs,omitempty"`
+
+	// Output is the result reported for a completed job.
+	Output string `json:"output,omitempty"`
+
+	// ErrorCode and ErrorMessage describe a failed job.
+	ErrorCode    string `json:"errorCode,omitempty"`
+	ErrorMessage string `json:"errorMessage,omitempty"`
+
+	// Turns and CostUSD are the harness's usage, when it reports any.
+	Turns   int      `json:"turns,omitempty"`
+	CostUSD *float64 `json:"costUsd,omitempty"`
+
+	// Network summarizes the outbound calls recorded during the job, when
+	// worker.network_recording is on and the job made any.
+	Network *netrecord.Summary `json:"network,omitempty"`
+}
+
+// Duration returns how long a finished job ran.
+func (r *JobRecord) Duration() time.Duration {
+	return time.Duration(r.DurationMs) * time.Millisecond
+}
+
+func (r *JobRecord) marker() string {
+	if r.Event == JobStarted {
+		return fmt.Sprintf("\r\n[mush] Job %s started\r\n", r.JobID)
+	}
+
+	duration := r.Duration().Round(time.Second)
+
+	if r.ErrorCode != "" {
+		return fmt.Sprintf("\r\n[mush] Job %s %s after %s (%s)\r\n", r.JobID, r.Status, duration, r.ErrorCode)
+	}
+
+	return fmt.Sprintf("\r\n[mush] Job %s %s after %s\r\n", r.JobID, r.Status, duration)
+}
@@ -0,0 +1,174 @@
+package transcript
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Job statuses a finished job record carries.
+const (
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// JobEntry is one job recorded in a stored session.
+type JobEntry struct {
+	SessionID string
+	JobReport
+}
+
+// ListJobs returns the jobs recorded across stored sessions, newest first.
+// Sessions without job records, or whose events cannot be read, are
+// skipped.
+func ListJobs(rootDir string) ([]JobEntry, error) {
+	sessions, err := ListSessions(rootDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []JobEntry
+
+	for _, session := range sessions {
+		events, err := ReadEvents(rootDir, session.SessionID)
+		if err != nil {
+			continue
+		}
+
+		report := BuildReport(session.SessionID, events)
+
+		for i := range report.Jobs {
+			if report.Jobs[i].JobID == "" {
+				continue
+			}
+
+			jobs = append(jobs, JobEntry{SessionID: session.SessionID, JobReport: report.Jobs[i]})
+		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].StartedAt.After(jobs[j].StartedAt)
+	})
+
+	return jobs, nil
+}
+
+// JobFilter selects entries from ListJobs. Its zero value matches every
+// job.
+type JobFilter struct {
+	// Status is JobCompleted, JobFailed, or JobUnfinished.
+	Status  string
+	QueueID string
+
+	// Since drops jobs started before it.
+	Since time.Time
+
+	// Words must each appear, ignoring case, in the job's ID or name.
+	Words []string
+
+	// since is the term Since was parsed from, kept for String.
+	since string
+}
+
+// ParseJobFilter parses a space-separated filter query. It takes
+// status:<status>, queue:<queue-id>, and since:<duration or YYYY-MM-DD>
+// terms, with durations counted back from now; any other word is matched
+// against job IDs and names.
+func ParseJobFilter(query string, now time.Time) (JobFilter, error) {
+	var filter JobFilter
+
+	for _, term := range strings.Fields(query) {
+		name, value, found := strings.Cut(term, ":")
+		if !found || value == "" {
+			filter.Words = append(filter.Words, term)
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "status":
+			status := strings.ToLower(value)
+			if status != JobCompleted && status != JobFailed && status != JobUnfinished {
+				return JobFilter{}, fmt.Errorf("unknown job status %q (want %s, %s, or %s)", value, JobCompleted, JobFailed, JobUnfinished)
+			}
+
+			filter.Status = status
+		case "queue":
+			filter.QueueID = value
+		case "since":
+			since, err := parseSince(value, now)
+			if err != nil {
+				return JobFilter{}, err
+			}
+
+			filter.Since = since
+			filter.since = value
+		default:
+			filter.Words = append(filter.Words, term)
+		}
+	}
+
+	return filter, nil
+}
+
+// parseSince reads a since: term as a duration before now or a local date.
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	if day, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return day, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid since %q (want a duration such as 24h or a date such as 2006-01-02)", value)
+}
+
+// Match reports whether the job passes the filter.
+func (f *JobFilter) Match(job *JobEntry) bool {
+	if f.Status != "" && job.Status != f.Status {
+		return false
+	}
+
+	if f.QueueID != "" && job.QueueID != f.QueueID {
+		return false
+	}
+
+	if !f.Since.IsZero() && job.StartedAt.Before(f.Since) {
+		return false
+	}
+
+	haystack := strings.ToLower(job.JobID + " " + job.Name)
+
+	for _, word := range f.Words {
+		if !strings.Contains(haystack, strings.ToLower(word)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the filter as a query ParseJobFilter reads back.
+func (f *JobFilter) String() string {
+	var terms []string
+
+	if f.Status != "" {
+		terms = append(terms, "status:"+f.Status)
+	}
+
+	if f.QueueID != "" {
+		terms = append(terms, "queue:"+f.QueueID)
+	}
+
+	switch {
+	case f.since != "":
+		terms = append(terms, "since:"+f.since)
+	case !f.Since.IsZero():
+		terms = append(terms, "since:"+f.Since.Format(time.DateOnly))
+	}
+
+	terms = append(terms, f.Words...)
+
+	return strings.Join(terms, " ")
+}
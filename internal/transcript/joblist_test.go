@@ -0,0 +1,109 @@
+package transcript
+
+import (
+	"testing"
+	"time"
+)
+
+func TestListJobs(t *testing.T) {
+	dir := t.TempDir()
+	recordJobSession(t, dir)
+
+	s, err := NewStore(StoreOptions{SessionID: "s-2", Dir: dir})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	started := time.Date(2026, 5, 7, 7, 8, 9, 0, time.UTC)
+
+	if err := s.AppendJob(&JobRecord{Event: JobStarted, JobID: "job-2", QueueID: "q-1", StartedAt: started}); err != nil {
+		t.Fatalf("AppendJob() error = %v", err)
+	}
+
+	if err := s.AppendJob(&JobRecord{Event: JobFinished, JobID: "job-2", QueueID: "q-1", Status: JobCompleted, StartedAt: started, Output: "done"}); err != nil {
+		t.Fatalf("AppendJob() error = %v", err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	jobs, err := ListJobs(dir)
+	if err != nil {
+		t.Fatalf("ListJobs() error = %v", err)
+	}
+
+	if len(jobs) != 2 {
+		t.Fatalf("ListJobs() = %d jobs, want 2", len(jobs))
+	}
+
+	if jobs[0].JobID != "job-2" || jobs[0].SessionID != "s-2" || jobs[0].Output != "done" {
+		t.Errorf("jobs[0] = %+v, want job-2 from s-2 first", jobs[0])
+	}
+
+	if jobs[1].JobID != "job-1" || jobs[1].SessionID != "s-1" || jobs[1].Status != JobFailed {
+		t.Errorf("jobs[1] = %+v, want the failed job-1 from s-1", jobs[1])
+	}
+}
+
+func TestParseJobFilter(t *testing.T) {
+	now := time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC)
+
+	filter, err := ParseJobFilter("status:FAILED queue:q-1 since:24h build", now)
+	if err != nil {
+		t.Fatalf("ParseJobFilter() error = %v", err)
+	}
+
+	if filter.Status != JobFailed || filter.QueueID != "q-1" || !filter.Since.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("ParseJobFilter() = %+v", filter)
+	}
+
+	if got := filter.String(); got != "status:failed queue:q-1 since:24h build" {
+		t.Errorf("String() = %q", got)
+	}
+
+	dated, err := ParseJobFilter("since:2026-05-01", now)
+	if err != nil || !dated.Since.Equal(time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseJobFilter(since date) = %v, %v", dated.Since, err)
+	}
+
+	for _, query := range []string{"status:lost", "since:yesterday", "since:-1h"} {
+		if _, err := ParseJobFilter(query, now); err == nil {
+			t.Errorf("ParseJobFilter(%q) error = nil, want an error", query)
+		}
+	}
+}
+
+func TestJobFilterMatch(t *testing.T) {
+	now := time.Date(2026, 5, 8, 12, 0, 0, 0, time.UTC)
+	job := &JobEntry{JobReport: JobReport{
+		JobID: "job-1", Name: "Fix the build", QueueID: "q-1", Status: JobFailed, StartedAt: now.Add(-time.Hour),
+	}}
+
+	tests := map[string]bool{
+		"":                true,
+		"status:failed":   true,
+		"status:complete": false,
+		"queue:q-1":       true,
+		"queue:q-2":       false,
+		"since:30m":       false,
+		"since:2h":        true,
+		"BUILD job-1":     true,
+		"build deploy":    false,
+	}
+
+	for query, want := range tests {
+		filter, err := ParseJobFilter(query, now)
+		if err != nil {
+			if want {
+				t.Errorf("ParseJobFilter(%q) error = %v", query, err)
+			}
+
+			continue
+		}
+
+		if got := filter.Match(job); got != want {
+			t.Errorf("Match(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package transcript
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/netrecord"
+	"github.com/musher-dev/mush/internal/tui/render"
+)
+
+// reportTimeFormat is how reports show times, always in UTC so a shared
+// report reads the same everywhere.
+const reportTimeFormat = "2006-01-02 15:04:05 UTC"
+
+// reportField is one row of a job's summary table.
+type reportField struct {
+	Name  string
+	Value string
+}
+
+// title returns the job's heading.
+func (j *JobReport) title() string {
+	switch {
+	case j.JobID == "":
+		return "Session output"
+	case j.Name == "" || j.Name == "Job":
+		return "Job " + j.JobID
+	default:
+		return j.Name
+	}
+}
+
+// fields returns the job's summary rows, leaving out unknown values.
+func (j *JobReport) fields() []reportField {
+	var fields []reportField
+
+	add := func(name, value string) {
+		if value != "" {
+			fields = append(fields, reportField{Name: name, Value: value})
+		}
+	}
+
+	add("Job", j.JobID)
+	add("Status", j.Status)
+	add("Harness", j.Harness)
+	add("Queue", j.QueueID)
+
+	if j.Attempt > 0 {
+		add("Attempt", strconv.Itoa(j.Attempt))
+	}
+
+	if !j.StartedAt.IsZero() {
+		add("Started", j.StartedAt.UTC().Format(reportTimeFormat))
+	}
+
+	if j.Duration > 0 {
+		add("Duration", j.Duration.Round(time.Second).String())
+	}
+
+	if j.Turns > 0 {
+		add("Turns", strconv.Itoa(j.Turns))
+	}
+
+	if j.CostUSD != nil {
+		add("Cost", fmt.Sprintf("$%.2f", *j.CostUSD))
+	}
+
+	if j.Network != nil {
+		add("Network", fmt.Sprintf("%d request(s) to %d host(s)", j.Network.Requests, len(j.Network.Hosts())))
+	}
+
+	if j.ErrorCode != "" && j.ErrorMessage != "" {
+		add("Error", j.ErrorCode+": "+j.ErrorMessage)
+	} else {
+		add("Error", j.ErrorCode+j.ErrorMessage)
+	}
+
+	return fields
+}
+
+// networkCallText describes a recorded call's totals, without its host.
+func networkCallText(call *netrecord.Call) string {
+	text := fmt.Sprintf("%s ×%d, %s sent, %s received", call.Method, call.Count,
+		render.FormatBytes(call.BytesSent), render.FormatBytes(call.BytesReceived))
+
+	if call.Errors > 0 {
+		text += fmt.Sprintf(", %d failed", call.Errors)
+	}
+
+	return text
+}
+
+// RenderMarkdown writes the report as a Markdown document.
+func RenderMarkdown(w io.Writer, report *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Session %s\n\n", report.SessionID)
+
+	if !report.StartedAt.IsZero() {
+		fmt.Fprintf(&b, "Recorded %s to %s.\n\n", report.StartedAt.UTC().Format(reportTimeFormat), report.EndedAt.UTC().Format(reportTimeFormat))
+	}
+
+	for i := range report.Jobs {
+		job := &report.Jobs[i]
+
+		fmt.Fprintf(&b, "## %s\n\n", job.title())
+
+		if fields := job.fields(); len(fields) > 0 {
+			b.WriteString("| Field | Value |\n| --- | --- |\n")
+
+			for _, field := range fields {
+				fmt.Fprintf(&b, "| %s | %s |\n", field.Name, markdownCell(field.Value))
+			}
+
+			b.WriteString("\n")
+		}
+
+		if job.Prompt != "" {
+			fmt.Fprintf(&b, "### Prompt\n\n%s\n", markdownBlock(job.Prompt))
+		}
+
+		if len(job.ToolCalls) > 0 {
+			b.WriteString("### Tool calls\n\n")
+
+			for _, call := range job.ToolCalls {
+				fmt.Fprintf(&b, "- %s\n", markdownCode(call.String()))
+			}
+
+			b.WriteString("\n")
+		}
+
+		if job.Network != nil {
+			b.WriteString("### Network calls\n\n")
+
+			for i := range job.Network.Calls {
+				call := &job.Network.Calls[i]
+				fmt.Fprintf(&b, "- %s %s\n", markdownCode(call.Host), networkCallText(call))
+			}
+
+			b.WriteString("\n")
+		}
+
+		if job.Output != "" {
+			fmt.Fprintf(&b, "### Output\n\n%s\n", markdownBlock(job.Output))
+		}
+
+		if job.Terminal != "" {
+			fmt.Fprintf(&b, "<details>\n<summary>Terminal output</summary>\n\n%s\n</details>\n\n", markdownBlock(job.Terminal))
+		}
+	}
+
+	if _, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n"); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	return nil
+}
+
+// markdownBlock fences text as a code block, with a fence longer than any
+// backtick run in it.
+func markdownBlock(text string) string {
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+
+	return fence + "text\n" + strings.TrimRight(text, "\n") + "\n" + fence + "\n"
+}
+
+// markdownCode returns text as inline code.
+func markdownCode(text string) string {
+	ticks := strings.Repeat("`", longestRun(text, '`')+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+
+	return ticks + text + ticks
+}
+
+// markdownCell escapes text for a table cell.
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	text = strings.ReplaceAll(text, "\r\n", "<br>")
+
+	return strings.ReplaceAll(text, "\n", "<br>")
+}
+
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+
+	for i := range len(s) {
+		if s[i] != c {
+			run = 0
+			continue
+		}
+
+		run++
+		longest = max(longest, run)
+	}
+
+	return longest
+}
+
+var reportHTML = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time":        func(t time.Time) string { return t.UTC().Format(reportTimeFormat) },
+	"networkCall": func(call netrecord.Call) string { return networkCallText(&call) },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Session {{.SessionID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 960px; margin: 2rem auto; padding: 0 1rem; color: #1f2328; line-height: 1.5; }
+h1 { font-size: 1.6rem; border-bottom: 1px solid #d0d7de; padding-bottom: .3rem; }
+h2 { font-size: 1.3rem; margin-top: 2.5rem; }
+h3 { font-size: 1rem; margin-top: 1.5rem; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: .25rem .75rem; border: 1px solid #d0d7de; vertical-align: top; }
+th { background: #f6f8fa; font-weight: 600; }
+pre { background: #f6f8fa; padding: .75rem; overflow-x: auto; white-space: pre-wrap; word-break: break-word; border-radius: 6px; }
+code { font-family: ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; font-size: .9em; }
+.status-completed { color: #1a7f37; font-weight: 600; }
+.status-failed { color: #cf222e; font-weight: 600; }
+.status-unfinished { color: #9a6700; font-weight: 600; }
+.muted { color: #59636e; }
+</style>
+</head>
+<body>
+<h1>Session <code>{{.SessionID}}</code></h1>
+{{- if not .StartedAt.IsZero}}
+<p class="muted">Recorded {{time .StartedAt}} to {{time .EndedAt}}.</p>
+{{- end}}
+{{- range .Jobs}}
+<section>
+<h2>{{.Title}}</h2>
+{{- if .Fields}}
+<table>
+{{- range .Fields}}
+<tr><th>{{.Name}}</th><td{{if eq .Name "Status"}} class="status-{{.Value}}"{{end}}>{{.Value}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Prompt}}
+<h3>Prompt</h3>
+<pre>{{.Prompt}}</pre>
+{{- end}}
+{{- if .ToolCalls}}
+<h3>Tool calls</h3>
+<ul>
+{{- range .ToolCalls}}
+<li><code>{{.String}}</code></li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Network}}
+<h3>Network calls</h3>
+<ul>
+{{- range .Network.Calls}}
+<li><code>{{.Host}}</code> {{networkCall .}}</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- if .Output}}
+<h3>Output</h3>
+<pre>{{.Output}}</pre>
+{{- end}}
+{{- if .Terminal}}
+<details>
+<summary>Terminal output</summary>
+<pre>{{.Terminal}}</pre>
+</details>
+{{- end}}
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// htmlJob is a JobReport with its computed parts, for the template.
+type htmlJob struct {
+	*JobReport
+	Title  string
+	Fields []reportField
+}
+
+// RenderHTML writes the report as a standalone HTML page.
+func RenderHTML(w io.Writer, report *Report) error {
+	jobs := make([]htmlJob, len(report.Jobs))
+	for i := range report.Jobs {
+		job := &report.Jobs[i]
+		jobs[i] = htmlJob{JobReport: job, Title: job.title(), Fields: job.fields()}
+	}
+
+	data := struct {
+		SessionID string
+		StartedAt time.Time
+		EndedAt   time.Time
+		Jobs      []htmlJob
+	}{report.SessionID, report.StartedAt, report.EndedAt, jobs}
+
+	if err := reportHTML.Execute(w, data); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+
+	return nil
+}
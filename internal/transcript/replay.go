@@ -0,0 +1,126 @@
+package transcript
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultReplayMaxIdle caps the pause between replayed events, so a session
+// that sat idle between jobs does not replay its idle time.
+const DefaultReplayMaxIdle = 2 * time.Second
+
+// ReplayOptions controls how Replay paces a session's output.
+type ReplayOptions struct {
+	// Speed multiplies the recorded pace. Zero or less writes every event
+	// at once.
+	Speed float64
+
+	// MaxIdle caps the pause between two events, before Speed applies. Zero
+	// means no cap.
+	MaxIdle time.Duration
+}
+
+// Replay writes the terminal output of events to w, pausing between events
+// as they were recorded. It returns ctx's error if canceled between events.
+func Replay(ctx context.Context, w io.Writer, events []Event, opts ReplayOptions) error {
+	return replay(ctx, w, events, opts, sleepContext)
+}
+
+func replay(ctx context.Context, w io.Writer, events []Event, opts ReplayOptions, sleep func(context.Context, time.Duration) error) error {
+	var last time.Time
+
+	for i := range events {
+		event := &events[i]
+
+		if opts.Speed > 0 && !last.IsZero() {
+			if err := sleep(ctx, replayDelay(event.TS.Sub(last), opts)); err != nil {
+				return err
+			}
+		}
+
+		last = event.TS
+
+		if _, err := w.Write(event.Raw()); err != nil {
+			return fmt.Errorf("write replayed output: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// replayDelay returns the pause before an event recorded gap after the one
+// before it.
+func replayDelay(gap time.Duration, opts ReplayOptions) time.Duration {
+	if gap <= 0 {
+		return 0
+	}
+
+	if opts.MaxIdle > 0 && gap > opts.MaxIdle {
+		gap = opts.MaxIdle
+	}
+
+	return time.Duration(float64(gap) / opts.Speed)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err() //nolint:wrapcheck // canceled replay
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err() //nolint:wrapcheck // canceled replay
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Raw returns the bytes the event recorded, escape sequences included.
+// Events whose raw bytes cannot be decoded fall back to their text.
+func (e *Event) Raw() []byte {
+	if e.RawBase64 != "" {
+		if raw, err := base64.StdEncoding.DecodeString(e.RawBase64); err == nil {
+			return raw
+		}
+	}
+
+	return []byte(e.Text)
+}
+
+// JobEvents returns the events from each of the job's start records through
+// its matching end record, or through the next job's start when it has
+// none. It returns nil when the events do not record the job.
+func JobEvents(events []Event, jobID string) []Event {
+	var (
+		selected []Event
+		inJob    bool
+	)
+
+	for i := range events {
+		event := &events[i]
+
+		if event.Stream == JobStream && event.Job != nil {
+			switch {
+			case event.Job.JobID == jobID:
+				selected = append(selected, *event)
+				inJob = event.Job.Event == JobStarted
+			case event.Job.Event == JobStarted:
+				inJob = false
+			}
+
+			continue
+		}
+
+		if inJob {
+			selected = append(selected, *event)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,101 @@
+package transcript
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func replayEvent(ts time.Time, raw string) Event {
+	return Event{TS: ts, Stream: "pty", RawBase64: base64.StdEncoding.EncodeToString([]byte(raw)), Text: raw}
+}
+
+func TestReplayPacesEvents(t *testing.T) {
+	start := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	events := []Event{
+		replayEvent(start, "\x1b[1mone\x1b[0m"),
+		replayEvent(start.Add(500*time.Millisecond), " two"),
+		replayEvent(start.Add(time.Hour), " three"),
+		{TS: start.Add(time.Hour), Stream: "pty", RawBase64: "not base64", Text: " four"},
+	}
+
+	var (
+		buf    bytes.Buffer
+		pauses []time.Duration
+	)
+
+	sleep := func(_ context.Context, d time.Duration) error {
+		pauses = append(pauses, d)
+		return nil
+	}
+
+	if err := replay(t.Context(), &buf, events, ReplayOptions{Speed: 2, MaxIdle: 2 * time.Second}, sleep); err != nil {
+		t.Fatalf("replay() error = %v", err)
+	}
+
+	if got, want := buf.String(), "\x1b[1mone\x1b[0m two three four"; got != want {
+		t.Fatalf("output = %q, want %q", got, want)
+	}
+
+	if want := []time.Duration{250 * time.Millisecond, time.Second, 0}; !slices.Equal(pauses, want) {
+		t.Fatalf("pauses = %v, want %v", pauses, want)
+	}
+}
+
+func TestReplayWithoutTiming(t *testing.T) {
+	start := time.Now()
+	events := []Event{replayEvent(start, "a"), replayEvent(start.Add(time.Minute), "b")}
+
+	sleep := func(context.Context, time.Duration) error {
+		t.Fatal("replay paused without timing")
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := replay(t.Context(), &buf, events, ReplayOptions{}, sleep); err != nil || buf.String() != "ab" {
+		t.Fatalf("replay() = %q, %v; want %q", buf.String(), err, "ab")
+	}
+}
+
+func TestReplayStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	start := time.Now()
+	events := []Event{replayEvent(start, "a"), replayEvent(start.Add(time.Minute), "b")}
+
+	var buf bytes.Buffer
+
+	err := Replay(ctx, &buf, events, ReplayOptions{Speed: 1})
+	if !errors.Is(err, context.Canceled) || buf.String() != "a" {
+		t.Fatalf("Replay() = %q, %v; want %q and context.Canceled", buf.String(), err, "a")
+	}
+}
+
+func TestJobEvents(t *testing.T) {
+	events := recordJobSession(t, t.TempDir())
+
+	got := JobEvents(events, "job-1")
+	if len(got) != 5 {
+		t.Fatalf("JobEvents() = %d events, want the job's start, 3 output events, and end", len(got))
+	}
+
+	if got[0].Job == nil || got[0].Job.Event != JobStarted || got[len(got)-1].Job == nil || got[len(got)-1].Job.Event != JobFinished {
+		t.Fatalf("JobEvents() = %+v, want it to start and end with the job's records", got)
+	}
+
+	for _, event := range got {
+		if strings.Contains(event.Text, "idle prompt") || strings.Contains(event.Text, "after the job") {
+			t.Fatalf("JobEvents() includes output outside the job: %q", event.Text)
+		}
+	}
+
+	if got := JobEvents(events, "job-2"); got != nil {
+		t.Fatalf("JobEvents(unknown) = %+v, want nil", got)
+	}
+}
@@ -0,0 +1,293 @@
+package transcript
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/ansi"
+	"github.com/musher-dev/mush/internal/netrecord"
+)
+
+// JobUnfinished is a report's status for a job with a start record but no
+// end record, such as one released back to the queue or cut off by a crash.
+const JobUnfinished = "unfinished"
+
+// reportTerminalLines bounds how much of a job's terminal output a report
+// keeps, from the end.
+const reportTerminalLines = 200
+
+// ErrJobNotFound is returned by FindJobSession when no stored session
+// recorded the job.
+var ErrJobNotFound = errors.New("job not found in transcript history")
+
+// toolCallPattern matches the line an agent TUI draws for a tool call, such
+// as "⏺ Bash(go test ./...)" or "⏺ github - create_issue (MCP)(title: ...)".
+var toolCallPattern = regexp.MustCompile(`^[⏺●]\s+([A-Za-z][\w.:-]*(?: - [\w.:-]+ \(MCP\))?)\((.*)\)$`)
+
+// Report is a readable summary of a session's jobs, built from its
+// transcript.
+type Report struct {
+	SessionID string
+	StartedAt time.Time
+	EndedAt   time.Time
+
+	// Jobs holds a section per recorded job, in order. A session recorded
+	// without job records has a single section with no JobID covering all
+	// of its output.
+	Jobs []JobReport
+}
+
+// JobReport describes one job in a Report.
+type JobReport struct {
+	JobID   string
+	Name    string
+	Harness string
+	QueueID string
+	Attempt int
+	Prompt  string
+
+	// Status is "completed", "failed", or JobUnfinished.
+	Status    string
+	StartedAt time.Time
+	Duration  time.Duration
+
+	// Output is the result reported for a completed job.
+	Output string
+
+	ErrorCode    string
+	ErrorMessage string
+	Turns        int
+	CostUSD      *float64
+
+	// Network summarizes the job's recorded outbound calls, if any.
+	Network *netrecord.Summary
+
+	// ToolCalls are the tool calls drawn in the job's terminal output. A
+	// call the terminal redraws is listed once.
+	ToolCalls []ToolCall
+
+	// Terminal is the end of the job's terminal output as plain text.
+	Terminal string
+}
+
+// ToolCall is one tool call seen in a job's terminal output.
+type ToolCall struct {
+	Name string
+	Args string
+}
+
+// String returns the call as drawn, "Name(args)".
+func (c ToolCall) String() string {
+	return c.Name + "(" + c.Args + ")"
+}
+
+// BuildReport summarizes the session's events. Terminal output between a
+// job's start and end records belongs to that job; output between jobs is
+// left out.
+func BuildReport(sessionID string, events []Event) *Report {
+	report := &Report{SessionID: sessionID}
+
+	if len(events) > 0 {
+		report.StartedAt = events[0].TS
+		report.EndedAt = events[len(events)-1].TS
+	}
+
+	var (
+		current  *JobReport
+		terminal sectionText
+	)
+
+	finish := func(end time.Time) {
+		if current == nil {
+			return
+		}
+
+		current.ToolCalls, current.Terminal = terminal.result()
+
+		if current.Status == JobUnfinished && !end.IsZero() && end.After(current.StartedAt) {
+			current.Duration = end.Sub(current.StartedAt)
+		}
+
+		report.Jobs = append(report.Jobs, *current)
+		current = nil
+		terminal = sectionText{}
+	}
+
+	for i := range events {
+		event := &events[i]
+
+		if event.Stream != JobStream || event.Job == nil {
+			if current != nil {
+				terminal.write(event.Text)
+			}
+
+			continue
+		}
+
+		rec := event.Job
+
+		switch rec.Event {
+		case JobStarted:
+			finish(event.TS)
+
+			current = &JobReport{
+				JobID:     rec.JobID,
+				Name:      rec.Name,
+				Harness:   rec.Harness,
+				QueueID:   rec.QueueID,
+				Attempt:   rec.Attempt,
+				Prompt:    rec.Prompt,
+				Status:    JobUnfinished,
+				StartedAt: rec.StartedAt,
+			}
+		case JobFinished:
+			if current == nil || current.JobID != rec.JobID {
+				finish(event.TS)
+
+				current = &JobReport{JobID: rec.JobID, Name: rec.Name, Harness: rec.Harness, QueueID: rec.QueueID, Attempt: rec.Attempt}
+			}
+
+			current.Status = rec.Status
+			current.StartedAt = rec.StartedAt
+			current.Duration = rec.Duration()
+			current.Output = rec.Output
+			current.ErrorCode = rec.ErrorCode
+			current.ErrorMessage = rec.ErrorMessage
+			current.Turns = rec.Turns
+			current.CostUSD = rec.CostUSD
+			current.Network = rec.Network
+
+			finish(event.TS)
+		}
+	}
+
+	finish(report.EndedAt)
+
+	if len(report.Jobs) == 0 {
+		for i := range events {
+			terminal.write(events[i].Text)
+		}
+
+		section := JobReport{StartedAt: report.StartedAt, Duration: report.EndedAt.Sub(report.StartedAt)}
+		section.ToolCalls, section.Terminal = terminal.result()
+		report.Jobs = append(report.Jobs, section)
+	}
+
+	return report
+}
+
+// OnlyJob narrows the report to the job's sections, reporting whether it
+// has any.
+func (r *Report) OnlyJob(jobID string) bool {
+	jobs := r.Jobs[:0:0]
+
+	for i := range r.Jobs {
+		if r.Jobs[i].JobID == jobID {
+			jobs = append(jobs, r.Jobs[i])
+		}
+	}
+
+	if len(jobs) == 0 {
+		return false
+	}
+
+	r.Jobs = jobs
+
+	return true
+}
+
+// FindJobSession returns the newest stored session that recorded the job.
+func FindJobSession(rootDir, jobID string) (string, error) {
+	sessions, err := ListSessions(rootDir)
+	if err != nil {
+		return "", err
+	}
+
+	for _, session := range sessions {
+		events, err := ReadEvents(rootDir, session.SessionID)
+		if err != nil {
+			continue
+		}
+
+		for i := range events {
+			if events[i].Job != nil && events[i].Job.JobID == jobID {
+				return session.SessionID, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrJobNotFound, jobID)
+}
+
+// sectionText collects a section's terminal output as plain text.
+type sectionText struct {
+	stripper ansi.Stripper
+	text     strings.Builder
+}
+
+func (s *sectionText) write(text string) {
+	s.text.Write(s.stripper.Strip([]byte(text)))
+}
+
+// result returns the tool calls in the text and its last lines, with
+// carriage returns treated as line breaks and blank runs collapsed.
+func (s *sectionText) result() ([]ToolCall, string) {
+	var (
+		calls []ToolCall
+		seen  = make(map[ToolCall]bool)
+	)
+
+	lines := plainLines(s.text.String())
+
+	for _, line := range lines {
+		if match := toolCallPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			call := ToolCall{Name: match[1], Args: match[2]}
+			if !seen[call] {
+				seen[call] = true
+				calls = append(calls, call)
+			}
+		}
+	}
+
+	if len(lines) > reportTerminalLines {
+		lines = lines[len(lines)-reportTerminalLines:]
+	}
+
+	return calls, strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// plainLines splits terminal output stripped of escape sequences into
+// lines, with carriage returns treated as line breaks, trailing spaces
+// trimmed, leading blank lines dropped, and blank runs collapsed to one.
+func plainLines(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	var (
+		lines []string
+		blank bool
+	)
+
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t")
+
+		if strings.TrimSpace(line) == "" {
+			if !blank && len(lines) > 0 {
+				lines = append(lines, "")
+			}
+
+			blank = true
+
+			continue
+		}
+
+		blank = false
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
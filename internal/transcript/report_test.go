@@ -0,0 +1,166 @@
+package transcript
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/netrecord"
+)
+
+// recordJobSession writes a session with one failed job between idle
+// output, returning its events.
+func recordJobSession(t *testing.T, dir string) []Event {
+	t.Helper()
+
+	s, err := NewStore(StoreOptions{SessionID: "s-1", Dir: dir})
+	if err != nil {
+		t.Fatalf("NewStore() error = %v", err)
+	}
+
+	started := time.Date(2026, 5, 6, 7, 8, 9, 0, time.UTC)
+	cost := 0.42
+	network := &netrecord.Summary{
+		Calls:    []netrecord.Call{{Host: "api.github.com", Method: "CONNECT", Count: 2, Errors: 1, BytesSent: 512, BytesReceived: 2048}},
+		Requests: 2, BytesSent: 512, BytesReceived: 2048,
+	}
+
+	appends := []func() error{
+		func() error { return s.Append("pty", []byte("idle prompt\r\n")) },
+		func() error {
+			return s.AppendJob(&JobRecord{Event: JobStarted, JobID: "job-1", Name: "Fix the build", Harness: "claude", Attempt: 2, Prompt: "Fix `go test`", StartedAt: started})
+		},
+		func() error { return s.Append("pty", []byte("\x1b[1m⏺ Bash(go test ./...)\x1b[0m\r\n")) },
+		func() error { return s.Append("pty", []byte("\x1b[2J⏺ Bash(go test ./...)\r\n\r\n\r\n  FAIL\r\n")) },
+		func() error { return s.Append("pty", []byte("⏺ github - create_issue (MCP)(title: \"flaky\")\r\n")) },
+		func() error {
+			return s.AppendJob(&JobRecord{
+				Event: JobFinished, JobID: "job-1", Status: "failed", StartedAt: started, DurationMs: 61_400,
+				ErrorCode: "execution_error", ErrorMessage: "tests | still fail", Turns: 3, CostUSD: &cost,
+				Network: network,
+			})
+		},
+		func() error { return s.Append("pty", []byte("after the job\r\n")) },
+	}
+
+	for _, appendEvent := range appends {
+		if err := appendEvent(); err != nil {
+			t.Fatalf("append error = %v", err)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	events, err := ReadEvents(dir, "s-1")
+	if err != nil {
+		t.Fatalf("ReadEvents() error = %v", err)
+	}
+
+	return events
+}
+
+func TestBuildReport(t *testing.T) {
+	report := BuildReport("s-1", recordJobSession(t, t.TempDir()))
+
+	if len(report.Jobs) != 1 {
+		t.Fatalf("Jobs = %+v, want one job", report.Jobs)
+	}
+
+	job := report.Jobs[0]
+	if job.JobID != "job-1" || job.Status != "failed" || job.Prompt != "Fix `go test`" || job.Duration != 61400*time.Millisecond || job.Turns != 3 {
+		t.Fatalf("job = %+v, want job-1 failed with its prompt and usage", job)
+	}
+
+	want := []ToolCall{
+		{Name: "Bash", Args: "go test ./..."},
+		{Name: "github - create_issue (MCP)", Args: `title: "flaky"`},
+	}
+	if len(job.ToolCalls) != len(want) || job.ToolCalls[0] != want[0] || job.ToolCalls[1] != want[1] {
+		t.Fatalf("ToolCalls = %+v, want %+v", job.ToolCalls, want)
+	}
+
+	if strings.Contains(job.Terminal, "\x1b") || strings.Contains(job.Terminal, "\n\n\n") || !strings.Contains(job.Terminal, "FAIL") {
+		t.Fatalf("Terminal = %q, want plain text with blank runs collapsed", job.Terminal)
+	}
+
+	if strings.Contains(job.Terminal, "idle prompt") || strings.Contains(job.Terminal, "after the job") {
+		t.Fatalf("Terminal = %q, want only the job's output", job.Terminal)
+	}
+}
+
+func TestBuildReportWithoutJobRecords(t *testing.T) {
+	events := []Event{
+		{Seq: 1, Stream: "pty", Text: "⏺ Read(main.go)\r\n"},
+		{Seq: 2, Stream: "pty", Text: "done\r\n"},
+	}
+
+	report := BuildReport("s-1", events)
+	if len(report.Jobs) != 1 || report.Jobs[0].JobID != "" || len(report.Jobs[0].ToolCalls) != 1 || !strings.Contains(report.Jobs[0].Terminal, "done") {
+		t.Fatalf("Jobs = %+v, want one section covering the session", report.Jobs)
+	}
+
+	if report.OnlyJob("job-1") {
+		t.Fatal("OnlyJob() = true, want false for a job not in the session")
+	}
+}
+
+func TestRenderReport(t *testing.T) {
+	report := BuildReport("s-1", recordJobSession(t, t.TempDir()))
+
+	var md bytes.Buffer
+	if err := RenderMarkdown(&md, report); err != nil {
+		t.Fatalf("RenderMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"# Session s-1\n",
+		"## Fix the build\n",
+		"| Status | failed |\n",
+		"| Duration | 1m1s |\n",
+		"| Cost | $0.42 |\n",
+		`| Error | execution_error: tests \| still fail |`,
+		"```text\nFix `go test`\n```\n",
+		"- `Bash(go test ./...)`\n",
+		"| Network | 2 request(s) to 1 host(s) |\n",
+		"### Network calls\n\n- `api.github.com` CONNECT ×2, 512 B sent, 2.0 KB received, 1 failed\n",
+		"<summary>Terminal output</summary>",
+	} {
+		if !strings.Contains(md.String(), want) {
+			t.Fatalf("Markdown report missing %q:\n%s", want, md.String())
+		}
+	}
+
+	var page bytes.Buffer
+	if err := RenderHTML(&page, report); err != nil {
+		t.Fatalf("RenderHTML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"<h2>Fix the build</h2>",
+		`<td class="status-failed">failed</td>`,
+		"<code>github - create_issue (MCP)(title: &#34;flaky&#34;)</code>",
+		"<li><code>api.github.com</code> CONNECT ×2, 512 B sent, 2.0 KB received, 1 failed</li>",
+	} {
+		if !strings.Contains(page.String(), want) {
+			t.Fatalf("HTML report missing %q:\n%s", want, page.String())
+		}
+	}
+}
+
+func TestFindJobSession(t *testing.T) {
+	dir := t.TempDir()
+	recordJobSession(t, dir)
+
+	sessionID, err := FindJobSession(dir, "job-1")
+	if err != nil || sessionID != "s-1" {
+		t.Fatalf("FindJobSession() = %q, %v, want s-1", sessionID, err)
+	}
+
+	if _, err := FindJobSession(dir, "job-2"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("FindJobSession(job-2) error = %v, want ErrJobNotFound", err)
+	}
+}
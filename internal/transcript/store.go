@@ -34,6 +34,9 @@ type Event struct {
 	Stream    string    `json:"stream"`
 	RawBase64 string    `json:"rawBase64"`
 	Text      string    `json:"text,omitempty"`
+
+	// Job is set for events on JobStream.
+	Job *JobRecord `json:"job,omitempty"`
 }
 
 // Meta stores session metadata for discovery and pruning.
@@ -153,6 +156,16 @@ func (s *Store) Append(stream string, chunk []byte) error {
 		return nil
 	}
 
+	return s.appendEvent(stream, chunk, nil)
+}
+
+// AppendJob records a job starting or finishing on JobStream. The event's
+// text is a one-line marker, so the job also shows in a plain view.
+func (s *Store) AppendJob(rec *JobRecord) error {
+	return s.appendEvent(JobStream, []byte(rec.marker()), rec)
+}
+
+func (s *Store) appendEvent(stream string, chunk []byte, job *JobRecord) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -169,6 +182,7 @@ func (s *Store) Append(stream string, chunk []byte) error {
 		Stream:    stream,
 		RawBase64: base64.StdEncoding.EncodeToString(chunk),
 		Text:      text,
+		Job:       job,
 	}
 
 	line, err := json.Marshal(&ev)
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 // renderBundleInput renders the bundle reference input screen.
@@ -436,6 +437,9 @@ var assetTypeInfo = map[string]struct {
 	"skill":            {label: "Skills", order: 1},
 	"agent_definition": {label: "Agents", order: 2},
 	"tool_config":      {label: "Tools", order: 3},
+	"command":          {label: "Commands", order: 4},
+	"prompt":           {label: "Prompts", order: 5},
+	"hook":             {label: "Hooks", order: 6},
 }
 
 // groupLayers groups bundle layers by asset type, sorted by fixed order.
@@ -478,23 +482,6 @@ func groupLayers(layers []client.BundleLayer) []assetGroup {
 	return result
 }
 
-// formatBytes returns a human-readable size string.
-func formatBytes(n int64) string {
-	const (
-		kiloByte = 1024
-		megaByte = 1024 * 1024
-	)
-
-	switch {
-	case n >= megaByte:
-		return fmt.Sprintf("%.1f MB", float64(n)/float64(megaByte))
-	case n >= kiloByte:
-		return fmt.Sprintf("%.1f KB", float64(n)/float64(kiloByte))
-	default:
-		return fmt.Sprintf("%d B", n)
-	}
-}
-
 // maxPathsShown controls path truncation: when a group has more paths than this,
 // only maxPathsShown-1 paths are shown followed by a "+N more" line.
 const maxPathsShown = 3
@@ -519,7 +506,7 @@ func renderBundleContents(styles *theme, layers []client.BundleLayer, layout lay
 	for _, group := range groups {
 		// Group header: "Skills (2)                   1.8 KB"
 		header := fmt.Sprintf("%s (%d)", group.label, group.count)
-		sizeStr := formatBytes(group.size)
+		sizeStr := render.FormatBytes(group.size)
 
 		pad := contentWidth - lipgloss.Width(header) - lipgloss.Width(sizeStr)
 		if pad < 1 {
@@ -708,30 +708,6 @@ func TestBundleScreenViews(t *testing.T) {
 	}
 }
 
-func TestFormatBytes(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		input int64
-		want  string
-	}{
-		{0, "0 B"},
-		{512, "512 B"},
-		{1023, "1023 B"},
-		{1024, "1.0 KB"},
-		{1843, "1.8 KB"},
-		{1048576, "1.0 MB"},
-		{2200000, "2.1 MB"},
-	}
-
-	for _, tt := range tests {
-		got := formatBytes(tt.input)
-		if got != tt.want {
-			t.Errorf("formatBytes(%d) = %q, want %q", tt.input, got, tt.want)
-		}
-	}
-}
-
 func TestGroupLayers(t *testing.T) {
 	t.Parallel()
 
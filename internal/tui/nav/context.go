@@ -48,9 +48,9 @@ func cmdLoadContext(ctx context.Context, deps *Dependencies) tea.Cmd {
 			msg.authStatus = "authenticated"
 		}
 
-		// 2. If authed and client available, validate key to get organization info.
+		// 2. If authed and client available, look up the (cached) identity for organization info.
 		if msg.authStatus == "authenticated" && deps.Client != nil {
-			identity, err := deps.Client.ValidateKey(navBaseCtx(ctx))
+			identity, err := deps.Client.CachedIdentity(navBaseCtx(ctx))
 			if err == nil {
 				msg.organizationName = identity.OrganizationName
 				msg.organizationID = identity.OrganizationID
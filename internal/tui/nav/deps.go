@@ -3,6 +3,7 @@ package nav
 import (
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
+	"github.com/musher-dev/mush/internal/transcript"
 )
 
 // BundleSeed provides pre-resolved bundle data so the TUI can start
@@ -14,13 +15,21 @@ type BundleSeed struct {
 	CachePath string
 }
 
+// HistorySeed starts the TUI at the job history browser.
+type HistorySeed struct {
+	Dir    string // transcript root; empty uses the default
+	Filter transcript.JobFilter
+	JobID  string // job to select first, if it matches the filter
+}
+
 // Dependencies holds external services needed by the TUI.
 type Dependencies struct {
-	Client        *client.Client // nil if unauthenticated
-	Config        *config.Config
-	WorkDir       string
-	InitialBundle *BundleSeed // nil = start at home screen
-	Experimental  bool        // true when experimental features are enabled
+	Client         *client.Client // nil if unauthenticated
+	Config         *config.Config
+	WorkDir        string
+	InitialBundle  *BundleSeed  // nil = start at home screen
+	InitialHistory *HistorySeed // nil = start at home screen
+	Experimental   bool         // true when experimental features are enabled
 }
 
 // Action identifies what the TUI wants the caller to do after exit.
@@ -39,6 +48,8 @@ const (
 	ActionBareRun
 	// ActionBundleInstall means the user wants to install bundle assets into the working directory.
 	ActionBundleInstall
+	// ActionHistoryReplay means the user wants to replay a job from the history browser.
+	ActionHistoryReplay
 )
 
 // Result carries the TUI's chosen action and associated parameters back to the caller.
@@ -61,6 +72,12 @@ type Result struct {
 
 	// Harness install fields
 	InstallCommands [][]string
+
+	// History replay fields; HistoryFilter is the browser's filter, to
+	// restore when it is opened again.
+	SessionID     string
+	JobID         string
+	HistoryFilter transcript.JobFilter
 }
 
 func depsConfig(deps *Dependencies) *config.Config {
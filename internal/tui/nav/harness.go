@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/x/ansi"
 
 	"github.com/musher-dev/mush/internal/harness"
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 // harnessCollapseIndicator is the prefix for a collapsed harness row.
@@ -131,9 +132,7 @@ func renderHarnessExpandedDetail(mdl *model, status harnessQuickStatus, contentW
 		checkLabel := padRight(r.Check, checkColWidth)
 
 		msg := cleanHealthMessage(r.Check, r.Message)
-		if ansi.StringWidth(msg) > msgMaxWidth {
-			msg = msg[:msgMaxWidth-1] + "…"
-		}
+		msg = render.TruncateVisibleTail(msg, msgMaxWidth, "…")
 
 		line := indent + symbol + " " + mdl.styles.sectionTitle.Render(checkLabel) + mdl.styles.progressText.Render(msg)
 		lines = append(lines, line)
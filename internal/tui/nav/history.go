@@ -3,11 +3,11 @@ package nav
 import (
 	"fmt"
 	"strings"
-	"time"
 
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 // renderHistoryList renders the transcript session list screen.
@@ -100,7 +100,7 @@ func renderHistoryRow(mdl *model, idx int, s transcript.Session) string {
 	// Duration or status indicator.
 	var durStr string
 	if s.ClosedAt != nil {
-		durStr = formatDuration(s.ClosedAt.Sub(s.StartedAt))
+		durStr = render.FormatDuration(s.ClosedAt.Sub(s.StartedAt))
 	} else {
 		durStr = "running"
 	}
@@ -202,7 +202,7 @@ func renderHistoryDetailHeader(mdl *model) string {
 	var statusPart string
 
 	if s.ClosedAt != nil {
-		durStr := formatDuration(s.ClosedAt.Sub(s.StartedAt))
+		durStr := render.FormatDuration(s.ClosedAt.Sub(s.StartedAt))
 		statusPart = mdl.styles.statusOK.Render("\u25CF") + " " +
 			mdl.styles.placeholder.Render("closed") + "  " +
 			mdl.styles.placeholder.Render(durStr)
@@ -220,31 +220,3 @@ func renderHistoryDetailHeader(mdl *model) string {
 		statusPart + "\n" +
 		mdl.styles.placeholder.Render(stats)
 }
-
-// formatDuration renders a duration as a compact human-friendly string.
-func formatDuration(dur time.Duration) string {
-	switch {
-	case dur < time.Second:
-		return "<1s"
-	case dur < time.Minute:
-		return fmt.Sprintf("%ds", int(dur.Seconds()))
-	case dur < time.Hour:
-		mins := int(dur.Minutes())
-		secs := int(dur.Seconds()) % secondsPerMinute
-
-		if secs == 0 {
-			return fmt.Sprintf("%dm", mins)
-		}
-
-		return fmt.Sprintf("%dm %ds", mins, secs)
-	default:
-		hours := int(dur.Hours())
-		mins := int(dur.Minutes()) % secondsPerMinute
-
-		if mins == 0 {
-			return fmt.Sprintf("%dh", hours)
-		}
-
-		return fmt.Sprintf("%dh %dm", hours, mins)
-	}
-}
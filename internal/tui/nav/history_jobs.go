@@ -0,0 +1,274 @@
+package nav
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/musher-dev/mush/internal/transcript"
+	"github.com/musher-dev/mush/internal/tui/render"
+)
+
+// renderHistoryJobs renders the job history browser: the filter, the jobs
+// that pass it, and a preview of the selected job.
+func renderHistoryJobs(mdl *model) string {
+	crumbs := renderBreadcrumb(&mdl.styles, []string{"History", "Jobs"})
+	state := &mdl.historyJobs
+	width := mdl.styles.hubWidth - panelInnerWidthOffset
+
+	filterLabel := mdl.styles.hintKey.Render(primaryHelpKey(mdl.keys.Search)) + " "
+
+	var filterView string
+
+	switch {
+	case state.editing:
+		filterView = filterLabel + state.filterInput.View()
+	case state.filter.String() != "":
+		filterView = filterLabel + mdl.styles.progressText.Render(state.filter.String())
+	default:
+		filterView = filterLabel + mdl.styles.placeholder.Render("All jobs")
+	}
+
+	if state.filterErr != "" {
+		filterView += "\n" + mdl.styles.statusError.Render(state.filterErr)
+	}
+
+	var body string
+
+	switch {
+	case state.loading:
+		body = state.spinner.View() + " " + mdl.styles.spinnerText.Render("Loading jobs...")
+
+	case state.errorMsg != "":
+		body = renderStatusDot(&mdl.styles.statusError, state.errorMsg)
+
+	case len(state.jobs) == 0:
+		body = mdl.styles.placeholder.Render("No jobs recorded in transcript history")
+
+	case len(state.matches) == 0:
+		body = mdl.styles.placeholder.Render(fmt.Sprintf("No jobs match the filter (%d recorded)", len(state.jobs)))
+
+	default:
+		body = renderHistoryJobRows(mdl, width) + "\n\n" +
+			mdl.styles.placeholder.Render(historyJobsSummary(state)) + "\n\n" +
+			renderHistoryJobPreview(mdl, mdl.selectedHistoryJob(), width)
+	}
+
+	if state.notice != "" {
+		style := &mdl.styles.statusOK
+		if state.noticeErr {
+			style = &mdl.styles.statusError
+		}
+
+		body += "\n\n" + style.Render(render.TruncateVisibleTail(state.notice, width, "..."))
+	}
+
+	panel := renderPanel(&mdl.styles, "Jobs", filterView+"\n\n"+body, mdl.styles.hubWidth, true)
+
+	var hints []hint
+
+	if state.editing {
+		hints = []hint{
+			bindingHint(mdl.keys.Select, "apply"),
+			bindingHint(mdl.keys.Back, "cancel"),
+		}
+	} else {
+		hints = []hint{
+			bindingHint(mdl.keys.Search, "filter"),
+			bindingHint(mdl.keys.Tab, "status"),
+			navigationHint(mdl.keys.Up, mdl.keys.Down, "navigate"),
+			bindingHint(mdl.keys.Select, "replay"),
+		}
+
+		if job := mdl.selectedHistoryJob(); job != nil && job.Status == transcript.JobFailed {
+			hints = append(hints, bindingHint(mdl.keys.Retry, "requeue"))
+		}
+
+		backDesc := "back"
+		if len(mdl.screenStack) == 0 {
+			backDesc = "quit"
+		}
+
+		hints = append(hints, bindingHint(mdl.keys.Back, backDesc))
+	}
+
+	footer := renderKeyHints(&mdl.styles, hints)
+
+	content := lipgloss.JoinVertical(lipgloss.Center, crumbs, "", panel, "", footer)
+
+	return lipgloss.Place(
+		mdl.width, mdl.height,
+		lipgloss.Center, lipgloss.Center,
+		content,
+	)
+}
+
+// renderHistoryJobRows renders the window of matching jobs around the cursor.
+func renderHistoryJobRows(mdl *model, width int) string {
+	state := &mdl.historyJobs
+
+	startIdx := 0
+	if state.cursor >= historyJobsVisibleItems {
+		startIdx = state.cursor - historyJobsVisibleItems + 1
+	}
+
+	endIdx := min(startIdx+historyJobsVisibleItems, len(state.matches))
+
+	rows := make([]string, 0, endIdx-startIdx)
+
+	for idx := startIdx; idx < endIdx; idx++ {
+		rows = append(rows, renderHistoryJobRow(mdl, idx, &state.jobs[state.matches[idx]], width))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+// renderHistoryJobRow renders a single job row in the history browser.
+func renderHistoryJobRow(mdl *model, idx int, job *transcript.JobEntry, width int) string {
+	prefix := cursorBlank
+	if idx == mdl.historyJobs.cursor {
+		prefix = cursorActive
+	}
+
+	id := render.PadRightVisible(render.TruncateVisible(job.JobID, historyJobIDWidth), historyJobIDWidth)
+	dateStr := job.StartedAt.Local().Format("Jan 02, 15:04")
+	durStr := render.PadRightVisible(render.FormatDuration(job.Duration), historyJobDurationWidth)
+
+	label := job.Name
+	if label == "" {
+		label = job.QueueID
+	}
+
+	row := prefix +
+		historyJobStatusDot(mdl, job.Status) + " " +
+		mdl.styles.progressText.Render(id) + "  " +
+		mdl.styles.placeholder.Render(dateStr) + "  " +
+		mdl.styles.placeholder.Render(durStr)
+
+	if room := width - lipgloss.Width(row) - 2; label != "" && room > 0 {
+		row += "  " + mdl.styles.placeholder.Render(render.TruncateVisibleTail(label, room, "..."))
+	}
+
+	return row
+}
+
+// renderHistoryJobPreview renders the selected job's details and the end
+// of its output.
+func renderHistoryJobPreview(mdl *model, job *transcript.JobEntry, width int) string {
+	title := mdl.styles.sectionTitle.Render(job.JobID)
+	if job.Name != "" {
+		title += "  " + mdl.styles.progressText.Render(render.TruncateVisibleTail(job.Name, max(width-len(job.JobID)-2, 1), "..."))
+	}
+
+	details := []string{"session " + job.SessionID}
+	if job.QueueID != "" {
+		details = append(details, "queue "+job.QueueID)
+	}
+
+	if job.Harness != "" {
+		details = append(details, job.Harness)
+	}
+
+	if job.Attempt > 1 {
+		details = append(details, fmt.Sprintf("attempt %d", job.Attempt))
+	}
+
+	when := []string{
+		historyJobStatusDot(mdl, job.Status) + " " + mdl.styles.placeholder.Render(job.Status),
+		mdl.styles.placeholder.Render(job.StartedAt.Local().Format("Jan 02, 15:04:05")),
+		mdl.styles.placeholder.Render(render.FormatDuration(job.Duration)),
+	}
+
+	lines := []string{
+		title,
+		mdl.styles.placeholder.Render(render.TruncateVisibleTail(strings.Join(details, " · "), width, "...")),
+		strings.Join(when, "  "),
+	}
+
+	if job.ErrorCode != "" || job.ErrorMessage != "" {
+		errText := job.ErrorMessage
+		if job.ErrorCode != "" && errText != "" {
+			errText = job.ErrorCode + ": " + errText
+		} else if job.ErrorCode != "" {
+			errText = job.ErrorCode
+		}
+
+		lines = append(lines, mdl.styles.statusError.Render(render.TruncateVisibleTail(errText, width, "...")))
+	}
+
+	output := job.Output
+	if strings.TrimSpace(output) == "" {
+		output = job.Terminal
+	}
+
+	lines = append(lines, "")
+
+	preview := historyPreviewLines(output, width)
+	if len(preview) == 0 {
+		lines = append(lines, mdl.styles.placeholder.Render("No output recorded"))
+	}
+
+	for _, line := range preview {
+		lines = append(lines, mdl.styles.placeholder.Render(line))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// historyPreviewLines returns the last historyJobPreviewLines lines of text,
+// trailing blank lines dropped, each cut to width.
+func historyPreviewLines(text string, width int) []string {
+	all := strings.Split(strings.TrimRight(text, " \t\r\n"), "\n")
+
+	var lines []string
+
+	for idx := len(all) - 1; idx >= 0 && len(lines) < historyJobPreviewLines; idx-- {
+		line := strings.TrimRight(all[idx], " \t\r")
+		if line == "" && len(lines) == 0 {
+			continue
+		}
+
+		lines = append(lines, render.TruncateVisibleTail(line, width, "..."))
+	}
+
+	slices.Reverse(lines)
+
+	return lines
+}
+
+// historyJobStatusDot renders a status dot: green for completed, red for
+// failed, yellow for unfinished.
+func historyJobStatusDot(mdl *model, status string) string {
+	switch status {
+	case transcript.JobCompleted:
+		return mdl.styles.statusOK.Render("\u25CF")
+	case transcript.JobFailed:
+		return mdl.styles.statusError.Render("\u25CF")
+	default:
+		return mdl.styles.statusWarning.Render("\u25CF")
+	}
+}
+
+// historyJobsSummary describes how many jobs pass the filter.
+func historyJobsSummary(state *historyJobsState) string {
+	failed := 0
+
+	for _, idx := range state.matches {
+		if state.jobs[idx].Status == transcript.JobFailed {
+			failed++
+		}
+	}
+
+	summary := fmt.Sprintf("%d jobs", len(state.matches))
+	if len(state.matches) != len(state.jobs) {
+		summary = fmt.Sprintf("%d of %d jobs", len(state.matches), len(state.jobs))
+	}
+
+	if failed > 0 {
+		summary += fmt.Sprintf(", %d failed", failed)
+	}
+
+	return summary
+}
@@ -0,0 +1,46 @@
+package nav
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+// historyJobsLoadedMsg carries the jobs recorded in transcript history.
+type historyJobsLoadedMsg struct {
+	jobs []transcript.JobEntry
+	err  error
+}
+
+// historyJobRequeuedMsg carries the result of requeuing a failed job.
+type historyJobRequeuedMsg struct {
+	jobID string
+	err   error
+}
+
+// cmdLoadHistoryJobs lists the jobs recorded under dir asynchronously.
+func cmdLoadHistoryJobs(dir string) tea.Cmd {
+	return func() tea.Msg {
+		jobs, err := transcript.ListJobs(dir)
+
+		return historyJobsLoadedMsg{
+			jobs: jobs,
+			err:  err,
+		}
+	}
+}
+
+// cmdRequeueJob asks the platform to run a failed job again.
+func cmdRequeueJob(ctx context.Context, c *client.Client, jobID string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := c.RetryJob(navBaseCtx(ctx), jobID)
+
+		return historyJobRequeuedMsg{
+			jobID: jobID,
+			err:   err,
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package nav
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+func testHistoryJobs() []transcript.JobEntry {
+	now := time.Now()
+
+	return []transcript.JobEntry{
+		{SessionID: "s-2", JobReport: transcript.JobReport{
+			JobID: "job-3", Name: "Update docs", QueueID: "q-2", Status: transcript.JobCompleted,
+			StartedAt: now, Duration: 90 * time.Second, Output: "Docs updated",
+		}},
+		{SessionID: "s-1", JobReport: transcript.JobReport{
+			JobID: "job-2", Name: "Fix the build", QueueID: "q-1", Status: transcript.JobFailed,
+			StartedAt: now.Add(-time.Hour), ErrorCode: "execution_error", ErrorMessage: "tests still fail",
+			Terminal: "go test ./...\nFAIL\n\n",
+		}},
+		{SessionID: "s-1", JobReport: transcript.JobReport{
+			JobID: "job-1", QueueID: "q-1", Status: transcript.JobUnfinished, StartedAt: now.Add(-48 * time.Hour),
+		}},
+	}
+}
+
+func testHistoryJobsModel(t *testing.T, seed *HistorySeed) *model {
+	t.Helper()
+
+	mdl := newModel(context.Background(), &Dependencies{InitialHistory: seed})
+
+	return updateModel(mdl, historyJobsLoadedMsg{jobs: testHistoryJobs()})
+}
+
+func TestHistoryJobsSeedStartsBrowser(t *testing.T) {
+	t.Parallel()
+
+	filter, err := transcript.ParseJobFilter("queue:q-1", time.Now())
+	if err != nil {
+		t.Fatalf("ParseJobFilter() error = %v", err)
+	}
+
+	mdl := newModel(context.Background(), &Dependencies{
+		InitialHistory: &HistorySeed{Filter: filter, JobID: "job-1"},
+	})
+
+	if mdl.activeScreen != screenHistoryJobs || !mdl.historyJobs.loading {
+		t.Fatalf("activeScreen = %d, loading = %v; want the loading job browser", mdl.activeScreen, mdl.historyJobs.loading)
+	}
+
+	mdl = updateModel(mdl, historyJobsLoadedMsg{jobs: testHistoryJobs()})
+
+	if len(mdl.historyJobs.matches) != 2 {
+		t.Fatalf("matches = %d, want the 2 jobs on q-1", len(mdl.historyJobs.matches))
+	}
+
+	if job := mdl.selectedHistoryJob(); job == nil || job.JobID != "job-1" {
+		t.Errorf("selected job = %v, want the seeded job-1", job)
+	}
+}
+
+func TestHistoryJobsTabCyclesStatus(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyTab})
+
+	if mdl.historyJobs.filter.Status != transcript.JobFailed || len(mdl.historyJobs.matches) != 1 {
+		t.Fatalf("status = %q, matches = %d; want the failed job only", mdl.historyJobs.filter.Status, len(mdl.historyJobs.matches))
+	}
+
+	for range len(historyStatusCycle) - 1 {
+		mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyTab})
+	}
+
+	if mdl.historyJobs.filter.Status != "" || len(mdl.historyJobs.matches) != 3 {
+		t.Errorf("status = %q, matches = %d; want every job after a full cycle", mdl.historyJobs.filter.Status, len(mdl.historyJobs.matches))
+	}
+}
+
+func TestHistoryJobsFilterInput(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	if !mdl.historyJobs.editing {
+		t.Fatal("editing = false after the search key")
+	}
+
+	// "q" is a quit key everywhere else; here it is typed into the filter.
+	for _, r := range "queue:q-2" {
+		mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if mdl.historyJobs.editing || mdl.historyJobs.filter.QueueID != "q-2" {
+		t.Fatalf("editing = %v, queue = %q; want the queue filter applied", mdl.historyJobs.editing, mdl.historyJobs.filter.QueueID)
+	}
+
+	if len(mdl.historyJobs.matches) != 1 {
+		t.Errorf("matches = %d, want 1", len(mdl.historyJobs.matches))
+	}
+}
+
+func TestHistoryJobsFilterInputRejectsBadQuery(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
+	mdl.historyJobs.filterInput.SetValue("status:lost")
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyEnter})
+
+	if !mdl.historyJobs.editing || mdl.historyJobs.filterErr == "" {
+		t.Fatalf("editing = %v, filterErr = %q; want the input kept open with an error", mdl.historyJobs.editing, mdl.historyJobs.filterErr)
+	}
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyEscape})
+
+	if mdl.historyJobs.editing || mdl.historyJobs.filterInput.Value() != "" {
+		t.Errorf("editing = %v, input = %q; want the edit canceled", mdl.historyJobs.editing, mdl.historyJobs.filterInput.Value())
+	}
+}
+
+func TestHistoryJobsEnterReplays(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyDown})
+
+	_, cmd := mdl.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter returned no command, want tea.Quit")
+	}
+
+	if mdl.result == nil || mdl.result.Action != ActionHistoryReplay {
+		t.Fatalf("result = %+v, want ActionHistoryReplay", mdl.result)
+	}
+
+	if mdl.result.SessionID != "s-1" || mdl.result.JobID != "job-2" {
+		t.Errorf("result = %s/%s, want s-1/job-2", mdl.result.SessionID, mdl.result.JobID)
+	}
+}
+
+func TestHistoryJobsRequeueNeedsFailedJob(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if !mdl.historyJobs.noticeErr || !strings.Contains(mdl.historyJobs.notice, "Only failed jobs") {
+		t.Errorf("notice = %q, want a refusal for a completed job", mdl.historyJobs.notice)
+	}
+
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyDown})
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+
+	if mdl.historyJobs.requeuing || !strings.Contains(mdl.historyJobs.notice, "mush auth login") {
+		t.Errorf("notice = %q, want a sign-in hint without a client", mdl.historyJobs.notice)
+	}
+}
+
+func TestHistoryJobRequeuedMsg(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+	mdl.historyJobs.requeuing = true
+
+	mdl = updateModel(mdl, historyJobRequeuedMsg{jobID: "job-2"})
+
+	if mdl.historyJobs.requeuing || mdl.historyJobs.noticeErr || mdl.historyJobs.notice != "Requeued job job-2" {
+		t.Errorf("requeuing = %v, notice = %q", mdl.historyJobs.requeuing, mdl.historyJobs.notice)
+	}
+}
+
+func TestHistoryJobsEscQuitsWhenStartedThere(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+
+	_, cmd := mdl.Update(tea.KeyMsg{Type: tea.KeyEscape})
+	if cmd == nil {
+		t.Fatal("Esc returned no command, want tea.Quit")
+	}
+
+	if msg := cmd(); msg != (tea.QuitMsg{}) {
+		t.Errorf("Esc command = %T, want tea.QuitMsg", msg)
+	}
+}
+
+func TestHistoryJobsView(t *testing.T) {
+	t.Parallel()
+
+	mdl := testHistoryJobsModel(t, &HistorySeed{})
+	mdl = updateModel(mdl, tea.KeyMsg{Type: tea.KeyDown})
+
+	view := mdl.View()
+
+	for _, want := range []string{"Jobs", "job-3", "job-2", "3 jobs, 1 failed", "session s-1", "execution_error: tests still fail", "FAIL", "requeue"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q", want)
+		}
+	}
+}
+
+func TestHistoryPreviewLines(t *testing.T) {
+	t.Parallel()
+
+	got := historyPreviewLines("1\n2\n3\n4\n5\n6\n7\n\n", 80)
+	if strings.Join(got, ",") != "2,3,4,5,6,7" {
+		t.Errorf("historyPreviewLines() = %q, want the last %d lines", got, historyJobPreviewLines)
+	}
+
+	if got := historyPreviewLines("  \n", 80); len(got) != 0 {
+		t.Errorf("historyPreviewLines(blank) = %q, want none", got)
+	}
+}
@@ -0,0 +1,256 @@
+package nav
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/musher-dev/mush/internal/transcript"
+)
+
+// historyStatusCycle is the order the status filter steps through.
+var historyStatusCycle = []string{"", transcript.JobFailed, transcript.JobCompleted, transcript.JobUnfinished}
+
+// newHistoryJobsState returns the loading state for the job history browser.
+func newHistoryJobsState(spin spinner.Model, seed *HistorySeed) historyJobsState {
+	filterInput := textinput.New()
+	filterInput.Placeholder = "status:failed queue:<id> since:24h"
+	filterInput.CharLimit = 256
+	filterInput.Width = clampHubWidth(defaultWidth) - searchInputWidthOffset
+	filterInput.SetValue(seed.Filter.String())
+
+	return historyJobsState{
+		spinner:     spin,
+		loading:     true,
+		dir:         seed.Dir,
+		selectJobID: seed.JobID,
+		filter:      seed.Filter,
+		filterInput: filterInput,
+	}
+}
+
+// handleHistoryJobsKey processes key events on the job history browser.
+func (m *model) handleHistoryJobsKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		if len(m.screenStack) == 0 {
+			return m, tea.Quit
+		}
+
+		m.popScreen()
+
+		return m, nil
+
+	case key.Matches(msg, m.keys.Search):
+		m.historyJobs.editing = true
+		m.historyJobs.filterErr = ""
+		m.historyJobs.filterInput.Width = m.styles.hubWidth - searchInputWidthOffset
+		m.historyJobs.filterInput.SetValue(m.historyJobs.filter.String())
+		m.historyJobs.filterInput.CursorEnd()
+
+		return m, m.historyJobs.filterInput.Focus()
+
+	case key.Matches(msg, m.keys.Tab):
+		m.historyJobs.filter.Status = nextHistoryStatus(m.historyJobs.filter.Status)
+		m.historyJobs.filterInput.SetValue(m.historyJobs.filter.String())
+		m.applyHistoryFilter()
+
+		return m, nil
+	}
+
+	if m.historyJobs.loading {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keys.Down):
+		if m.historyJobs.cursor < len(m.historyJobs.matches)-1 {
+			m.historyJobs.cursor++
+		}
+
+	case key.Matches(msg, m.keys.Up):
+		if m.historyJobs.cursor > 0 {
+			m.historyJobs.cursor--
+		}
+
+	case key.Matches(msg, m.keys.Select):
+		job := m.selectedHistoryJob()
+		if job == nil {
+			return m, nil
+		}
+
+		m.result = &Result{
+			Action:        ActionHistoryReplay,
+			SessionID:     job.SessionID,
+			JobID:         job.JobID,
+			HistoryFilter: m.historyJobs.filter,
+		}
+
+		return m, tea.Quit
+
+	case key.Matches(msg, m.keys.Retry):
+		return m.requeueHistoryJob()
+	}
+
+	return m, nil
+}
+
+// handleHistoryFilterKey processes key events while the filter input has focus.
+func (m *model) handleHistoryFilterKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Back):
+		m.historyJobs.editing = false
+		m.historyJobs.filterErr = ""
+		m.historyJobs.filterInput.SetValue(m.historyJobs.filter.String())
+		m.historyJobs.filterInput.Blur()
+
+		return m, nil
+
+	case key.Matches(msg, m.keys.Select):
+		filter, err := transcript.ParseJobFilter(m.historyJobs.filterInput.Value(), time.Now())
+		if err != nil {
+			m.historyJobs.filterErr = err.Error()
+
+			return m, nil
+		}
+
+		m.historyJobs.filter = filter
+		m.historyJobs.editing = false
+		m.historyJobs.filterErr = ""
+		m.historyJobs.filterInput.Blur()
+		m.applyHistoryFilter()
+
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+
+	m.historyJobs.filterInput, cmd = m.historyJobs.filterInput.Update(msg)
+
+	return m, cmd
+}
+
+// handleHistoryJobsLoaded processes the result of listing recorded jobs.
+func (m *model) handleHistoryJobsLoaded(msg historyJobsLoadedMsg) (tea.Model, tea.Cmd) {
+	if m.activeScreen != screenHistoryJobs {
+		return m, nil
+	}
+
+	m.historyJobs.loading = false
+
+	if msg.err != nil {
+		m.historyJobs.errorMsg = msg.err.Error()
+
+		return m, nil
+	}
+
+	m.historyJobs.jobs = msg.jobs
+	m.historyJobs.matches = nil
+	m.applyHistoryFilter()
+
+	for idx, jobIdx := range m.historyJobs.matches {
+		if m.historyJobs.jobs[jobIdx].JobID == m.historyJobs.selectJobID {
+			m.historyJobs.cursor = idx
+
+			break
+		}
+	}
+
+	m.historyJobs.selectJobID = ""
+
+	return m, nil
+}
+
+// handleHistoryJobRequeued processes the result of requeuing a job.
+func (m *model) handleHistoryJobRequeued(msg historyJobRequeuedMsg) (tea.Model, tea.Cmd) {
+	m.historyJobs.requeuing = false
+
+	if msg.err != nil {
+		m.historyJobs.notice = "Requeue failed: " + msg.err.Error()
+		m.historyJobs.noticeErr = true
+
+		return m, nil
+	}
+
+	m.historyJobs.notice = "Requeued job " + msg.jobID
+	m.historyJobs.noticeErr = false
+
+	return m, nil
+}
+
+// requeueHistoryJob asks the platform to run the selected failed job again.
+func (m *model) requeueHistoryJob() (tea.Model, tea.Cmd) {
+	job := m.selectedHistoryJob()
+	if job == nil || m.historyJobs.requeuing {
+		return m, nil
+	}
+
+	if job.Status != transcript.JobFailed {
+		m.historyJobs.notice = "Only failed jobs can be requeued"
+		m.historyJobs.noticeErr = true
+
+		return m, nil
+	}
+
+	if m.deps == nil || m.deps.Client == nil || !m.deps.Client.IsAuthenticated() {
+		m.historyJobs.notice = "Run 'mush auth login' to requeue jobs"
+		m.historyJobs.noticeErr = true
+
+		return m, nil
+	}
+
+	m.historyJobs.requeuing = true
+	m.historyJobs.notice = "Requeuing job " + job.JobID + "..."
+	m.historyJobs.noticeErr = false
+
+	return m, cmdRequeueJob(m.ctx, m.deps.Client, job.JobID)
+}
+
+// applyHistoryFilter recomputes the jobs that pass the filter, keeping the
+// selected job selected when it still passes.
+func (m *model) applyHistoryFilter() {
+	var selectedJob, selectedSession string
+	if job := m.selectedHistoryJob(); job != nil {
+		selectedJob, selectedSession = job.JobID, job.SessionID
+	}
+
+	m.historyJobs.matches = m.historyJobs.matches[:0]
+	m.historyJobs.cursor = 0
+
+	for idx := range m.historyJobs.jobs {
+		job := &m.historyJobs.jobs[idx]
+		if !m.historyJobs.filter.Match(job) {
+			continue
+		}
+
+		if job.JobID == selectedJob && job.SessionID == selectedSession {
+			m.historyJobs.cursor = len(m.historyJobs.matches)
+		}
+
+		m.historyJobs.matches = append(m.historyJobs.matches, idx)
+	}
+}
+
+// selectedHistoryJob returns the job under the cursor, or nil when no job
+// passes the filter.
+func (m *model) selectedHistoryJob() *transcript.JobEntry {
+	if m.historyJobs.cursor >= len(m.historyJobs.matches) {
+		return nil
+	}
+
+	return &m.historyJobs.jobs[m.historyJobs.matches[m.historyJobs.cursor]]
+}
+
+// nextHistoryStatus returns the status filter after status in historyStatusCycle.
+func nextHistoryStatus(status string) string {
+	for idx, s := range historyStatusCycle {
+		if s == status {
+			return historyStatusCycle[(idx+1)%len(historyStatusCycle)]
+		}
+	}
+
+	return historyStatusCycle[0]
+}
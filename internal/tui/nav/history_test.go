@@ -460,31 +460,6 @@ func TestHistoryStaleEventsIgnored(t *testing.T) {
 	}
 }
 
-func TestFormatDuration(t *testing.T) {
-	t.Parallel()
-
-	tests := []struct {
-		d    time.Duration
-		want string
-	}{
-		{500 * time.Millisecond, "<1s"},
-		{5 * time.Second, "5s"},
-		{90 * time.Second, "1m 30s"},
-		{2 * time.Minute, "2m"},
-		{5*time.Minute + 15*time.Second, "5m 15s"},
-		{time.Hour, "1h"},
-		{time.Hour + 30*time.Minute, "1h 30m"},
-		{2*time.Hour + 15*time.Minute, "2h 15m"},
-	}
-
-	for _, test := range tests {
-		got := formatDuration(test.d)
-		if got != test.want {
-			t.Errorf("formatDuration(%v) = %q, want %q", test.d, got, test.want)
-		}
-	}
-}
-
 func TestHistoryListViewAllClosed(t *testing.T) {
 	t.Parallel()
 
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 // cursorActive is the prefix for the currently highlighted menu item.
@@ -392,7 +394,7 @@ func renderPlaceholder(mdl *model) string {
 
 // formatTimeAgo returns a human-friendly relative time string.
 func formatTimeAgo(when time.Time) string {
-	elapsed := time.Since(when)
+	elapsed := render.Age(when, time.Now())
 
 	switch {
 	case elapsed < time.Minute:
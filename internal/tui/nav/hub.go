@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/musher-dev/mush/internal/tui/render"
 )
 
 // renderHubExplore renders the hub exploration screen.
@@ -119,8 +121,8 @@ func renderHubResultItem(mdl *model, idx int) string {
 	summary := b.Summary
 
 	maxSumLen := mdl.styles.hubWidth - hubSummaryTrimOffset
-	if maxSumLen > 0 && len(summary) > maxSumLen {
-		summary = summary[:maxSumLen-ellipsisWidth] + "..."
+	if maxSumLen > 0 {
+		summary = render.TruncateVisibleTail(summary, maxSumLen, "...")
 	}
 
 	line2 := "    " + mdl.styles.placeholder.Render(summary)
@@ -42,6 +42,7 @@ const (
 	screenStatus                      // connectivity diagnostics
 	screenHistory                     // transcript session list
 	screenHistoryDetail               // transcript session detail viewer
+	screenHistoryJobs                 // job history browser with filter and preview
 	screenPlaceholder                 // coming-soon for unimplemented items
 	screenExperimental                // experimental features list
 )
@@ -334,6 +335,25 @@ type historyDetailState struct {
 	errorMsg     string
 }
 
+// historyJobsState holds state for the job history browser screen.
+type historyJobsState struct {
+	spinner     spinner.Model
+	loading     bool
+	dir         string
+	selectJobID string                // job to select once the list loads
+	jobs        []transcript.JobEntry // every recorded job, newest first
+	matches     []int                 // indices into jobs that pass the filter
+	cursor      int                   // index into matches
+	filter      transcript.JobFilter
+	filterInput textinput.Model
+	editing     bool   // true while the filter input has focus
+	filterErr   string // why the edited filter was rejected
+	requeuing   bool
+	notice      string // result of the last requeue
+	noticeErr   bool
+	errorMsg    string
+}
+
 // experimentalPanelState holds state for the experimental panel mini-menu on the home screen.
 type experimentalPanelState struct {
 	items  []menuItem // selectable items (e.g. Start runner, View history)
@@ -429,6 +449,7 @@ type model struct {
 	// History sub-states
 	history       historyListState
 	historyDetail historyDetailState
+	historyJobs   historyJobsState
 
 	// Worker sub-states
 	workerHabitats workerHabitatsState
@@ -488,6 +509,9 @@ func newModel(ctx context.Context, deps *Dependencies) *model {
 	historyDetailSpinner := spinner.New()
 	historyDetailSpinner.Spinner = spinner.Dot
 
+	historyJobsSpinner := spinner.New()
+	historyJobsSpinner.Spinner = spinner.Dot
+
 	myBundlesSpinner := spinner.New()
 	myBundlesSpinner.Spinner = spinner.Dot
 
@@ -556,6 +580,9 @@ func newModel(ctx context.Context, deps *Dependencies) *model {
 		historyDetail: historyDetailState{
 			spinner: historyDetailSpinner,
 		},
+		historyJobs: historyJobsState{
+			spinner: historyJobsSpinner,
+		},
 	}
 
 	// Populate experimental panel items when experimental mode is enabled.
@@ -583,6 +610,12 @@ func newModel(ctx context.Context, deps *Dependencies) *model {
 		mdl.screenStack = []screen{screenHome}
 	}
 
+	// If a history seed is provided, start at the job history browser.
+	if deps != nil && deps.InitialHistory != nil {
+		mdl.historyJobs = newHistoryJobsState(historyJobsSpinner, deps.InitialHistory)
+		mdl.activeScreen = screenHistoryJobs
+	}
+
 	return mdl
 }
 
@@ -658,7 +691,13 @@ func (m *model) Init() tea.Cmd {
 		updateInterval = m.deps.Config.UpdateCheckInterval()
 	}
 
-	return tea.Batch(cmdLoadContext(m.ctx, m.deps), cmdLoadHarnessStatuses(m.ctx), cmdCheckUpdate(m.ctx, updateInterval))
+	cmds := []tea.Cmd{cmdLoadContext(m.ctx, m.deps), cmdLoadHarnessStatuses(m.ctx), cmdCheckUpdate(m.ctx, updateInterval)}
+
+	if m.activeScreen == screenHistoryJobs {
+		cmds = append(cmds, m.historyJobs.spinner.Tick, cmdLoadHistoryJobs(m.historyJobs.dir))
+	}
+
+	return tea.Batch(cmds...)
 }
 
 // Update handles messages and returns the updated model.
@@ -761,6 +800,12 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case historyEventsLoadedMsg:
 		return m.handleHistoryEventsLoaded(&msg)
 
+	case historyJobsLoadedMsg:
+		return m.handleHistoryJobsLoaded(msg)
+
+	case historyJobRequeuedMsg:
+		return m.handleHistoryJobRequeued(msg)
+
 	case statusChecksCompleteMsg:
 		return m.handleStatusChecksComplete(msg)
 
@@ -849,6 +894,14 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, cmd
 		}
 
+		if m.activeScreen == screenHistoryJobs && m.historyJobs.loading {
+			var cmd tea.Cmd
+
+			m.historyJobs.spinner, cmd = m.historyJobs.spinner.Update(msg)
+
+			return m, cmd
+		}
+
 		if m.activeScreen == screenBundleInput && m.myBundles.loading {
 			var cmd tea.Cmd
 
@@ -882,6 +935,12 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // handleKey dispatches key events to the active screen handler.
 func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// The history filter input takes every key but ctrl+c, so quit keys
+	// can be typed into it.
+	if m.activeScreen == screenHistoryJobs && m.historyJobs.editing && msg.Type != tea.KeyCtrlC {
+		return m.handleHistoryFilterKey(msg)
+	}
+
 	// Global quit bindings work on every screen.
 	if key.Matches(msg, m.keys.Quit) {
 		return m, tea.Quit
@@ -926,6 +985,8 @@ func (m *model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleHistoryListKey(msg)
 	case screenHistoryDetail:
 		return m.handleHistoryDetailKey(msg)
+	case screenHistoryJobs:
+		return m.handleHistoryJobsKey(msg)
 	case screenPlaceholder:
 		return m.handlePlaceholderKey(msg)
 	}
@@ -1229,6 +1290,8 @@ func (m *model) View() string {
 		return renderHistoryList(m)
 	case screenHistoryDetail:
 		return renderHistoryDetail(m)
+	case screenHistoryJobs:
+		return renderHistoryJobs(m)
 	case screenPlaceholder:
 		return renderPlaceholder(m)
 	default:
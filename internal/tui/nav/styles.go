@@ -28,11 +28,13 @@ const (
 	minUsableContentWidth    = 20
 	historyIDWidth           = 8
 	historyChromeLines       = 12
-	secondsPerMinute         = 60
+	historyJobIDWidth        = 12
+	historyJobDurationWidth  = 8
+	historyJobsVisibleItems  = 8
+	historyJobPreviewLines   = 6
 	hoursPerDay              = 24
 	hubMaxVisibleItems       = 5
 	hubSummaryTrimOffset     = 12
-	ellipsisWidth            = 3
 	myBundlesPageSize        = 20
 	maxSplitParts            = 2
 )
@@ -0,0 +1,21 @@
+package render
+
+import "fmt"
+
+// FormatBytes renders a byte count as a compact size, such as "512 B",
+// "1.8 KB", or "2.1 MB".
+func FormatBytes(n int64) string {
+	const (
+		kiloByte = 1024
+		megaByte = 1024 * 1024
+	)
+
+	switch {
+	case n >= megaByte:
+		return fmt.Sprintf("%.1f MB", float64(n)/float64(megaByte))
+	case n >= kiloByte:
+		return fmt.Sprintf("%.1f KB", float64(n)/float64(kiloByte))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,61 @@
+package render
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	secondsPerMinute = 60
+	minutesPerHour   = 60
+	hoursPerDay      = 24
+)
+
+// FormatDuration renders a duration as a compact human-friendly string, such
+// as "45s", "5m 15s", "2h 15m", or "3d 4h". Negative durations render as "<1s".
+func FormatDuration(dur time.Duration) string {
+	switch {
+	case dur < time.Second:
+		return "<1s"
+	case dur < time.Minute:
+		return fmt.Sprintf("%ds", int(dur.Seconds()))
+	case dur < time.Hour:
+		return joinUnits(int(dur.Minutes()), "m", int(dur.Seconds())%secondsPerMinute, "s")
+	case dur < hoursPerDay*time.Hour:
+		return joinUnits(int(dur.Hours()), "h", int(dur.Minutes())%minutesPerHour, "m")
+	default:
+		return joinUnits(int(dur.Hours())/hoursPerDay, "d", int(dur.Hours())%hoursPerDay, "h")
+	}
+}
+
+func joinUnits(major int, majorUnit string, minor int, minorUnit string) string {
+	if minor == 0 {
+		return fmt.Sprintf("%d%s", major, majorUnit)
+	}
+
+	return fmt.Sprintf("%d%s %d%s", major, majorUnit, minor, minorUnit)
+}
+
+// Age returns the time elapsed between since and now, never negative.
+//
+// When both values come from time.Now in the same process, the difference uses
+// the monotonic clock, so wall-clock adjustments (NTP steps, manual changes,
+// DST) during a long session cannot produce negative or inflated ages. Avoid
+// calling UTC, Local, In, or Round(0) on such values before this point, since
+// those strip the monotonic reading.
+func Age(since, now time.Time) time.Duration {
+	return max(now.Sub(since), 0)
+}
+
+// FormatTimestamp renders t in local time, with the date only when it is not
+// on the same day as now.
+func FormatTimestamp(t, now time.Time) string {
+	local := t.Local()
+	nowLocal := now.Local()
+
+	if local.YearDay() == nowLocal.YearDay() && local.Year() == nowLocal.Year() {
+		return local.Format("15:04:05")
+	}
+
+	return local.Format("Jan 2 15:04:05")
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-5 * time.Second, "<1s"},
+		{500 * time.Millisecond, "<1s"},
+		{5 * time.Second, "5s"},
+		{90 * time.Second, "1m 30s"},
+		{2 * time.Minute, "2m"},
+		{5*time.Minute + 15*time.Second, "5m 15s"},
+		{time.Hour, "1h"},
+		{time.Hour + 30*time.Minute, "1h 30m"},
+		{2*time.Hour + 15*time.Minute, "2h 15m"},
+		{24 * time.Hour, "1d"},
+		{3*24*time.Hour + 4*time.Hour + 59*time.Minute, "3d 4h"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestAge(t *testing.T) {
+	now := time.Now()
+
+	if got := Age(now.Add(-3*time.Second), now); got != 3*time.Second {
+		t.Fatalf("Age() = %v, want 3s", got)
+	}
+
+	if got := Age(now.Add(time.Minute), now); got != 0 {
+		t.Fatalf("Age() with future since = %v, want 0", got)
+	}
+}
+
+func TestFormatTimestamp(t *testing.T) {
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.Local)
+
+	if got := FormatTimestamp(now.Add(-time.Hour), now); got != "11:00:00" {
+		t.Fatalf("FormatTimestamp(same day) = %q, want 11:00:00", got)
+	}
+
+	if got := FormatTimestamp(now.Add(-36*time.Hour), now); got != "Mar 3 00:00:00" {
+		t.Fatalf("FormatTimestamp(previous day) = %q, want Mar 3 00:00:00", got)
+	}
+}
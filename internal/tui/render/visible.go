@@ -2,6 +2,7 @@ package render
 
 import (
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mattn/go-runewidth"
 
@@ -23,3 +24,79 @@ func PadRightVisible(value string, width int) string {
 
 	return value + strings.Repeat(" ", padding)
 }
+
+// TruncateVisible cuts value to at most width visible cells. Escape
+// sequences are kept, even past the cut, so styles opened before it are
+// still reset.
+func TruncateVisible(value string, width int) string {
+	if VisibleLength(value) <= width {
+		return value
+	}
+
+	var b strings.Builder
+
+	remaining := max(0, width)
+
+	for _, tok := range ansi.Tokenize(value) {
+		if tok.Kind != ansi.KindText {
+			b.WriteString(tok.Raw)
+			continue
+		}
+
+		text := runewidth.Truncate(tok.Raw, remaining, "")
+		remaining -= runewidth.StringWidth(text)
+
+		b.WriteString(text)
+	}
+
+	return b.String()
+}
+
+// TruncateVisibleTail cuts value to at most width visible cells like
+// TruncateVisible, ending it with tail when anything was cut. The tail
+// counts toward width.
+func TruncateVisibleTail(value string, width int, tail string) string {
+	if VisibleLength(value) <= width {
+		return value
+	}
+
+	tailWidth := VisibleLength(tail)
+	if tailWidth > width {
+		return TruncateVisible(tail, width)
+	}
+
+	return TruncateVisible(value, width-tailWidth) + tail
+}
+
+// WrapVisible breaks plain text into lines of at most width cells. Lines
+// break at a space where one fits, otherwise mid-word, but never inside a
+// rune; a character wider than width gets a line of its own. Spaces at a
+// break are dropped and other spacing is kept.
+func WrapVisible(text string, width int) []string {
+	if width <= 0 {
+		return []string{text}
+	}
+
+	var lines []string
+
+	for runewidth.StringWidth(text) > width {
+		cut := runewidth.Truncate(text, width, "")
+		if cut == "" {
+			_, size := utf8.DecodeRuneInString(text)
+			cut = text[:size]
+		}
+
+		if i := strings.LastIndexByte(cut, ' '); i > 0 && !strings.HasPrefix(text[len(cut):], " ") {
+			cut = cut[:i]
+		}
+
+		lines = append(lines, strings.TrimRight(cut, " "))
+		text = strings.TrimLeft(text[len(cut):], " ")
+
+		if text == "" {
+			return lines
+		}
+	}
+
+	return append(lines, text)
+}
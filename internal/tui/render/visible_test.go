@@ -1,6 +1,9 @@
 package render
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestVisibleLength(t *testing.T) {
 	tests := []struct {
@@ -92,3 +95,99 @@ func TestPadRightVisible(t *testing.T) {
 		})
 	}
 }
+
+func TestTruncateVisible(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{
+			name:  "fits",
+			input: "\x1b[1mhi\x1b[0m",
+			width: 5,
+			want:  "\x1b[1mhi\x1b[0m",
+		},
+		{
+			name:  "keeps trailing reset",
+			input: "\x1b[1mhello\x1b[0m world",
+			width: 3,
+			want:  "\x1b[1mhel\x1b[0m",
+		},
+		{
+			name:  "CJK never split",
+			input: "a你好",
+			width: 4,
+			want:  "a你",
+		},
+		{
+			name:  "zero width",
+			input: "abc",
+			width: 0,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TruncateVisible(tt.input, tt.width); got != tt.want {
+				t.Fatalf("TruncateVisible(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncateVisibleTail(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{name: "fits", input: "hello", width: 5, want: "hello"},
+		{name: "ASCII", input: "hello world", width: 8, want: "hello..."},
+		{name: "CJK never split", input: "任务名称很长", width: 8, want: "任务..."},
+		{name: "emoji never split", input: "deploy 🚀🚀🚀", width: 10, want: "deploy ..."},
+		{name: "tail wider than width", input: "hello", width: 2, want: ".."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateVisibleTail(tt.input, tt.width, "...")
+			if got != tt.want {
+				t.Fatalf("TruncateVisibleTail(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+
+			if w := VisibleLength(got); w > tt.width {
+				t.Fatalf("TruncateVisibleTail(%q, %d) width = %d", tt.input, tt.width, w)
+			}
+		})
+	}
+}
+
+func TestWrapVisible(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  []string
+	}{
+		{name: "fits", input: "a  b", width: 10, want: []string{"a  b"}},
+		{name: "breaks at spaces", input: "hello world foo", width: 8, want: []string{"hello", "world", "foo"}},
+		{name: "break on a word boundary", input: "hello world", width: 5, want: []string{"hello", "world"}},
+		{name: "long word split", input: "abcdefgh", width: 3, want: []string{"abc", "def", "gh"}},
+		{name: "CJK never split", input: "你好世界", width: 5, want: []string{"你好", "世界"}},
+		{name: "wide rune wider than width", input: "你好", width: 1, want: []string{"你", "好"}},
+		{name: "empty", input: "", width: 4, want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WrapVisible(tt.input, tt.width)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Fatalf("WrapVisible(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	selfupdate "github.com/creativeprojects/go-selfupdate"
+)
+
+// ReleaseNotesMaxLines bounds how many lines of release notes are printed
+// in the terminal; the rest are left to the release page.
+const ReleaseNotesMaxLines = 30
+
+var (
+	markdownLink    = regexp.MustCompile(`\[([^\]]+)\]\([^)]+\)`)
+	markdownComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+)
+
+// ReleaseNotes holds the notes published with a release.
+type ReleaseNotes struct {
+	Version string
+	URL     string
+	Body    string
+}
+
+// NotesFromRelease returns the notes published with release.
+func NotesFromRelease(release *selfupdate.Release) ReleaseNotes {
+	if release == nil {
+		return ReleaseNotes{}
+	}
+
+	return ReleaseNotes{Version: release.Version(), URL: release.URL, Body: release.ReleaseNotes}
+}
+
+// FetchReleaseNotes looks up the notes for version on GitHub Releases.
+func (u *Updater) FetchReleaseNotes(ctx context.Context, version string) (ReleaseNotes, error) {
+	release, found, err := u.updater.DetectVersion(ctx, selfupdate.ParseSlug(repoSlug), version)
+	if err != nil {
+		return ReleaseNotes{}, fmt.Errorf("detect version %s: %w", version, err)
+	}
+
+	if !found {
+		return ReleaseNotes{}, fmt.Errorf("version %s not found", version)
+	}
+
+	return NotesFromRelease(release), nil
+}
+
+// ReleasePageURL returns the GitHub Releases page for version.
+func ReleasePageURL(version string) string {
+	return "https://github.com/" + repoSlug + "/releases/tag/v" + strings.TrimPrefix(version, "v")
+}
+
+// FormatReleaseNotes turns Markdown release notes into plain terminal lines:
+// headings lose their markers, links keep only their text, HTML comments are
+// dropped, and runs of blank lines are collapsed. At most maxLines lines are
+// returned; truncated reports whether any were cut.
+func FormatReleaseNotes(body string, maxLines int) (lines []string, truncated bool) {
+	body = markdownComment.ReplaceAllString(body, "")
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+
+	blank := true
+
+	for _, raw := range strings.Split(body, "\n") {
+		line := strings.TrimRight(raw, " \t")
+		line = markdownLink.ReplaceAllString(line, "$1")
+
+		if trimmed := strings.TrimLeft(line, "#"); trimmed != line && strings.HasPrefix(trimmed, " ") {
+			line = strings.TrimSpace(trimmed)
+		}
+
+		if line == "" {
+			if !blank {
+				lines = append(lines, "")
+			}
+
+			blank = true
+
+			continue
+		}
+
+		blank = false
+
+		lines = append(lines, line)
+	}
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		return lines[:maxLines], true
+	}
+
+	return lines, false
+}
+
+// NotesPending reports whether release notes for currentVersion have not
+// been shown yet. It is false until a version has been recorded, so a fresh
+// install does not greet the user with notes.
+func (s *State) NotesPending(currentVersion string) bool {
+	if s.NotesShownVersion == "" {
+		return false
+	}
+
+	current, currentOK := parseSemver(currentVersion)
+	shown, shownOK := parseSemver(s.NotesShownVersion)
+
+	return currentOK && shownOK && current.GreaterThan(shown)
+}
+
+// MarkNotesShown records that the notes for version were shown, or that
+// none need to be for this install.
+func MarkNotesShown(version string) error {
+	state, err := LoadState()
+	if err != nil {
+		return err
+	}
+
+	state.NotesShownVersion = version
+
+	return SaveState(state)
+}
@@ -0,0 +1,72 @@
+package update
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatReleaseNotes(t *testing.T) {
+	body := "## What's Changed\r\n\r\n\r\n<!-- generated -->\n* Add `--once` by @dev in [#12](https://github.com/musher-dev/mush/pull/12)\n* Fix #tag parsing\n\n\n**Full Changelog**: v1.1.0...v1.2.0\n\n"
+
+	lines, truncated := FormatReleaseNotes(body, 0)
+
+	want := []string{
+		"What's Changed",
+		"",
+		"* Add `--once` by @dev in #12",
+		"* Fix #tag parsing",
+		"",
+		"**Full Changelog**: v1.1.0...v1.2.0",
+	}
+
+	if truncated || !reflect.DeepEqual(lines, want) {
+		t.Fatalf("FormatReleaseNotes() = %q, %v; want %q", lines, truncated, want)
+	}
+
+	lines, truncated = FormatReleaseNotes(body, 2)
+	if !truncated || len(lines) != 2 {
+		t.Fatalf("FormatReleaseNotes(max 2) = %q, %v; want 2 lines, truncated", lines, truncated)
+	}
+}
+
+func TestState_NotesPending(t *testing.T) {
+	tests := []struct {
+		shown   string
+		current string
+		want    bool
+	}{
+		{shown: "", current: "1.2.0", want: false},
+		{shown: "1.1.0", current: "1.2.0", want: true},
+		{shown: "1.2.0", current: "1.2.0", want: false},
+		{shown: "1.3.0", current: "1.2.0", want: false},
+		{shown: "1.1.0", current: "dev", want: false},
+	}
+
+	for _, tt := range tests {
+		state := &State{NotesShownVersion: tt.shown}
+		if got := state.NotesPending(tt.current); got != tt.want {
+			t.Errorf("NotesPending(shown=%q, current=%q) = %v, want %v", tt.shown, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestSaveCheckResult_KeepsNotesShownVersion(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	if err := MarkNotesShown("1.1.0"); err != nil {
+		t.Fatalf("MarkNotesShown() error = %v", err)
+	}
+
+	if err := SaveCheckResult("1.1.0", "1.2.0", ""); err != nil {
+		t.Fatalf("SaveCheckResult() error = %v", err)
+	}
+
+	state, err := LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+
+	if state.NotesShownVersion != "1.1.0" || state.LatestVersion != "1.2.0" {
+		t.Fatalf("state = %+v, want NotesShownVersion 1.1.0 and LatestVersion 1.2.0", state)
+	}
+}
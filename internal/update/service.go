@@ -47,7 +47,8 @@ func EnsureWritable(ctx InstallContext) (bool, error) {
 	return true, nil
 }
 
-// SaveCheckResult persists the last observed update state with the current install source.
+// SaveCheckResult persists the last observed update state with the current
+// install source. Which release notes were already shown is kept.
 func SaveCheckResult(current, latest, releaseURL string) error {
 	install := CurrentInstallContext()
 
@@ -59,5 +60,9 @@ func SaveCheckResult(current, latest, releaseURL string) error {
 		InstallSource:  string(install.Source),
 	}
 
+	if prev, err := LoadState(); err == nil {
+		state.NotesShownVersion = prev.NotesShownVersion
+	}
+
 	return SaveState(state)
 }
@@ -33,6 +33,10 @@ type State struct {
 
 	InstallSource          string `json:"installSource,omitempty"`
 	AutoApplyBlockedReason string `json:"autoApplyBlockedReason,omitempty"`
+
+	// NotesShownVersion is the newest version whose release notes were
+	// shown, or the version first run after they were introduced.
+	NotesShownVersion string `json:"notesShownVersion,omitempty"`
 }
 
 // statePath returns the path to the state file.
@@ -7,9 +7,11 @@ package update
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
 	selfupdate "github.com/creativeprojects/go-selfupdate"
@@ -43,6 +45,7 @@ type Info struct {
 // Updater manages checking for and applying updates.
 type Updater struct {
 	updater *selfupdate.Updater
+	source  *progressSource
 }
 
 // NewUpdater creates a new Updater configured for GitHub Releases.
@@ -54,8 +57,10 @@ func NewUpdater() (*Updater, error) {
 		return nil, fmt.Errorf("create github source: %w", err)
 	}
 
+	counted := &progressSource{Source: source}
+
 	updater, err := selfupdate.NewUpdater(selfupdate.Config{
-		Source:    source,
+		Source:    counted,
 		Validator: &selfupdate.ChecksumValidator{UniqueFilename: "checksums.txt"},
 		OS:        runtime.GOOS,
 		Arch:      runtime.GOARCH,
@@ -64,7 +69,17 @@ func NewUpdater() (*Updater, error) {
 		return nil, fmt.Errorf("create updater: %w", err)
 	}
 
-	return &Updater{updater: updater}, nil
+	return &Updater{updater: updater, source: counted}, nil
+}
+
+// SetDownloadProgress copies the bytes of the release binary downloaded by
+// Apply into w, for example an output.Progress bar sized to
+// Release.AssetByteSize. A nil w stops reporting.
+func (u *Updater) SetDownloadProgress(w io.Writer) {
+	u.source.mu.Lock()
+	defer u.source.mu.Unlock()
+
+	u.source.progress = w
 }
 
 // CheckLatest checks if a newer version is available.
@@ -146,3 +161,32 @@ func (u *Updater) ApplyVersion(ctx context.Context, version string) (*selfupdate
 
 	return release, nil
 }
+
+// progressSource reports release binary download progress. Checksum and
+// other validation assets are not counted.
+type progressSource struct {
+	selfupdate.Source
+
+	mu       sync.Mutex
+	progress io.Writer
+}
+
+func (s *progressSource) DownloadReleaseAsset(ctx context.Context, rel *selfupdate.Release, assetID int64) (io.ReadCloser, error) {
+	body, err := s.Source.DownloadReleaseAsset(ctx, rel, assetID)
+	if err != nil {
+		return nil, fmt.Errorf("download release asset: %w", err)
+	}
+
+	s.mu.Lock()
+	progress := s.progress
+	s.mu.Unlock()
+
+	if progress == nil || rel == nil || assetID != rel.AssetID {
+		return body, nil
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{Reader: io.TeeReader(body, progress), Closer: body}, nil
+}
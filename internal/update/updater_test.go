@@ -66,8 +66,10 @@ func (a *fakeAsset) GetBrowserDownloadURL() string { return a.url }
 func newTestUpdater(t *testing.T, source selfupdate.Source) *Updater {
 	t.Helper()
 
+	counted := &progressSource{Source: source}
+
 	updater, err := selfupdate.NewUpdater(selfupdate.Config{
-		Source: source,
+		Source: counted,
 		OS:     runtime.GOOS,
 		Arch:   runtime.GOARCH,
 	})
@@ -75,7 +77,7 @@ func newTestUpdater(t *testing.T, source selfupdate.Source) *Updater {
 		t.Fatalf("create test updater: %v", err)
 	}
 
-	return &Updater{updater: updater}
+	return &Updater{updater: updater, source: counted}
 }
 
 func testRelease(version string, withAsset bool) selfupdate.SourceRelease {
@@ -177,3 +179,36 @@ func TestCheckLatestNoMatchingAssets(t *testing.T) {
 		t.Error("expected UpdateAvailable to be false when no matching assets")
 	}
 }
+
+type payloadSource struct {
+	fakeSource
+	payload string
+}
+
+func (f *payloadSource) DownloadReleaseAsset(context.Context, *selfupdate.Release, int64) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.payload)), nil
+}
+
+func TestSetDownloadProgressCountsReleaseBinaryOnly(t *testing.T) {
+	u := newTestUpdater(t, &payloadSource{payload: "binary-bytes"})
+
+	var progress strings.Builder
+	u.SetDownloadProgress(&progress)
+
+	rel := &selfupdate.Release{AssetID: 7, ValidationAssetID: 8}
+
+	for _, assetID := range []int64{rel.AssetID, rel.ValidationAssetID} {
+		body, err := u.source.DownloadReleaseAsset(t.Context(), rel, assetID)
+		if err != nil {
+			t.Fatalf("DownloadReleaseAsset(%d) error = %v", assetID, err)
+		}
+
+		if _, err := io.ReadAll(body); err != nil {
+			t.Fatalf("read asset %d: %v", assetID, err)
+		}
+	}
+
+	if progress.String() != "binary-bytes" {
+		t.Fatalf("progress received %q, want only the release binary", progress.String())
+	}
+}
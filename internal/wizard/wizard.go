@@ -4,20 +4,24 @@
 //  1. Welcome message
 //  2. API key input and validation
 //  3. Habitat selection
-//  4. Credential storage
-//  5. Next steps guidance
+//  4. Harness selection
+//  5. Credential storage
+//  6. Next steps guidance
 package wizard
 
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/musher-dev/mush/internal/auth"
 	"github.com/musher-dev/mush/internal/client"
 	"github.com/musher-dev/mush/internal/config"
+	clierrors "github.com/musher-dev/mush/internal/errors"
 	"github.com/musher-dev/mush/internal/output"
 	"github.com/musher-dev/mush/internal/prompt"
+	"github.com/musher-dev/mush/internal/resolve"
 )
 
 // Wizard handles the initialization flow.
@@ -27,6 +31,8 @@ type Wizard struct {
 	force    bool
 	apiKey   string
 	habitat  string
+
+	harnesses []string
 }
 
 // New creates a new initialization wizard.
@@ -40,6 +46,12 @@ func New(out *output.Writer, force bool, apiKey, habitat string) *Wizard {
 	}
 }
 
+// SetHarnessChoices lists the installed harness types offered in the
+// harness selection step. The step is skipped with fewer than two choices.
+func (w *Wizard) SetHarnessChoices(installed []string) {
+	w.harnesses = installed
+}
+
 // Run executes the initialization wizard.
 func (w *Wizard) Run(ctx context.Context) error {
 	// Welcome
@@ -80,6 +92,9 @@ func (w *Wizard) Run(ctx context.Context) error {
 		w.out.Println()
 	}
 
+	// Open the API connection while the user finds their key.
+	client.StartPrewarm(ctx, cfg.APIURL(), cfg.CACertFile())
+
 	// Get API key
 	w.out.Println("Step 1: Authentication")
 	w.out.Println("----------------------")
@@ -97,14 +112,7 @@ func (w *Wizard) Run(ctx context.Context) error {
 	if apiKey == "" {
 		// Check for non-interactive mode
 		if !w.prompter.CanPrompt() {
-			w.out.Failure("Cannot run init wizard in non-interactive mode")
-			w.out.Println()
-			w.out.Info("Either:")
-			w.out.Print("  1. Run without --no-input flag\n")
-			w.out.Print("  2. Set MUSHER_API_KEY environment variable\n")
-			w.out.Print("  3. Pass --api-key or run 'mush auth login' interactively\n")
-
-			return nil
+			return clierrors.PromptRequired("an API key", "--api-key", "MUSHER_API_KEY")
 		}
 
 		var err error
@@ -196,27 +204,26 @@ func (w *Wizard) Run(ctx context.Context) error {
 	}
 
 	// Select habitat
-	var selected *client.HabitatSummary
+	candidates := habitats
 
 	if w.habitat != "" {
-		for i := range habitats {
-			if habitats[i].ID == w.habitat || habitats[i].Slug == w.habitat {
-				selected = &habitats[i]
-				break
-			}
-		}
-
-		if selected == nil {
+		candidates = resolve.MatchHabitats(habitats, w.habitat)
+		if len(candidates) == 0 {
 			w.out.Warning("Configured habitat %q not found; skipping habitat selection", w.habitat)
 			w.showNextSteps()
 
 			return nil
 		}
-	} else {
-		selected, err = prompt.SelectHabitat(habitats, w.out)
-		if err != nil {
-			return fmt.Errorf("failed to select habitat: %w", err)
-		}
+	}
+
+	resolver := &resolve.Resolver{
+		Lister:   apiClient,
+		Prompter: wizardPrompter{TerminalPrompter: resolve.TerminalPrompter{Out: w.out}, prompter: w.prompter},
+	}
+
+	selected, err := resolver.SelectHabitat(candidates, "")
+	if err != nil {
+		return err
 	}
 
 	// Save habitat to config
@@ -230,6 +237,10 @@ func (w *Wizard) Run(ctx context.Context) error {
 		w.out.Success("Selected habitat: %s (%s)", selected.Name, selected.Slug)
 	}
 
+	if err := w.selectHarnesses(cfg); err != nil {
+		return err
+	}
+
 	// Success
 	w.out.Println()
 	w.out.Success("Mush is ready!")
@@ -238,6 +249,61 @@ func (w *Wizard) Run(ctx context.Context) error {
 	return nil
 }
 
+// selectHarnesses lets the user choose which installed harnesses the worker
+// handles by default and saves the choice as worker.harnesses.
+func (w *Wizard) selectHarnesses(cfg *config.Config) error {
+	if len(w.harnesses) < 2 || !w.prompter.CanPrompt() {
+		return nil
+	}
+
+	w.out.Println()
+	w.out.Println("Step 3: Select Harnesses")
+	w.out.Println("------------------------")
+	w.out.Println("Choose which installed harnesses 'mush worker start' handles by default.")
+	w.out.Println()
+
+	configured := cfg.WorkerHarnesses()
+	options := make([]prompt.SelectOption, 0, len(w.harnesses))
+	preselected := make([]int, 0, len(w.harnesses))
+
+	for i, name := range w.harnesses {
+		options = append(options, prompt.SelectOption{Label: name})
+
+		if len(configured) == 0 || slices.Contains(configured, name) {
+			preselected = append(preselected, i)
+		}
+	}
+
+	indices, err := w.prompter.MultiSelect("Installed harnesses:", options, preselected)
+	if err != nil {
+		if prompt.IsCanceled(err) {
+			w.out.Muted("Harness selection skipped")
+			return nil
+		}
+
+		return fmt.Errorf("failed to select harnesses: %w", err)
+	}
+
+	selected := make([]string, 0, len(indices))
+	for _, idx := range indices {
+		selected = append(selected, w.harnesses[idx])
+	}
+
+	if err := cfg.Set("worker.harnesses", selected); err != nil {
+		w.out.Warning("Failed to save harnesses to config: %s", err.Error())
+		return nil
+	}
+
+	if len(selected) == 0 {
+		w.out.Warning("No harnesses selected; the worker will handle all installed harnesses")
+		return nil
+	}
+
+	w.out.Success("Selected harnesses: %s", strings.Join(selected, ", "))
+
+	return nil
+}
+
 func (w *Wizard) showNextSteps() {
 	w.out.Println()
 	w.out.Println("Next steps:")
@@ -245,3 +311,15 @@ func (w *Wizard) showNextSteps() {
 	w.out.Println("  mush worker start  Start processing jobs")
 	w.out.Println("  mush --help        See all commands")
 }
+
+// wizardPrompter prompts for habitats only when the wizard's prompter can
+// reach an interactive terminal.
+type wizardPrompter struct {
+	resolve.TerminalPrompter
+	prompter *prompt.Prompter
+}
+
+// CanPrompt reports whether the wizard can prompt.
+func (p wizardPrompter) CanPrompt() bool {
+	return p.prompter.CanPrompt()
+}
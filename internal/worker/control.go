@@ -0,0 +1,283 @@
+package worker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// controlTimeout bounds a single request on the control socket.
+const controlTimeout = 5 * time.Second
+
+// Control socket commands.
+const (
+	controlCommandStatus  = "status"
+	controlCommandStop    = "stop"
+	controlCommandObserve = "observe"
+	controlCommandReload  = "reload"
+)
+
+var (
+	// ErrNoWorkerRunning is returned by control requests when no worker is
+	// listening on the control socket.
+	ErrNoWorkerRunning = errors.New("no worker is running")
+
+	// ErrWorkerRunning is returned by ListenControl when another live worker
+	// already owns the control socket.
+	ErrWorkerRunning = errors.New("another worker already owns the control socket")
+)
+
+// ControlStatus describes a running worker, as reported over its control
+// socket to `mush worker status`.
+type ControlStatus struct {
+	PID       int       `json:"pid"`
+	WorkerID  string    `json:"workerId"`
+	HabitatID string    `json:"habitatId"`
+	QueueID   string    `json:"queueId"`
+	Status    string    `json:"status"`
+	JobID     string    `json:"jobId,omitempty"`
+	Completed int       `json:"completed"`
+	Failed    int       `json:"failed"`
+	StartedAt time.Time `json:"startedAt"`
+
+	// Stopping is true once the worker has stopped claiming and is draining.
+	Stopping bool `json:"stopping"`
+}
+
+// ControlHandler answers control requests for a running worker.
+type ControlHandler interface {
+	ControlStatus() ControlStatus
+
+	// RequestStop starts a graceful drain and deregistration. It must not
+	// block until the drain completes.
+	RequestStop()
+
+	// RequestReload re-reads the config as SIGHUP does. It must not block
+	// until the reload completes.
+	RequestReload()
+}
+
+type controlRequest struct {
+	Command string `json:"command"`
+}
+
+type controlResponse struct {
+	Status *ControlStatus `json:"status,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+// ControlServer serves the control socket of a running worker.
+type ControlServer struct {
+	ln      net.Listener
+	handler ControlHandler
+	wg      sync.WaitGroup
+
+	// done is closed by Close to end open observations.
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// ListenControl starts serving handler on the worker control socket. A
+// socket left behind by a worker that exited without cleaning up is
+// replaced.
+func ListenControl(ctx context.Context, handler ControlHandler) (*ControlServer, error) {
+	path, err := paths.WorkerControlSocket()
+	if err != nil {
+		return nil, fmt.Errorf("resolve worker control socket: %w", err)
+	}
+
+	return listenControl(ctx, path, handler)
+}
+
+func listenControl(ctx context.Context, path string, handler ControlHandler) (*ControlServer, error) {
+	if err := safeio.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create worker control directory: %w", err)
+	}
+
+	var lc net.ListenConfig
+
+	ln, err := lc.Listen(ctx, "unix", path)
+	if err != nil {
+		if _, statErr := os.Lstat(path); statErr != nil {
+			return nil, fmt.Errorf("listen on worker control socket: %w", err)
+		}
+
+		// Only a live worker accepts connections; anything else is stale.
+		if conn, dialErr := dialControl(ctx, path); dialErr == nil {
+			_ = conn.Close()
+			return nil, ErrWorkerRunning
+		}
+
+		if removeErr := os.Remove(path); removeErr != nil {
+			return nil, fmt.Errorf("remove stale worker control socket: %w", removeErr)
+		}
+
+		ln, err = lc.Listen(ctx, "unix", path)
+		if err != nil {
+			return nil, fmt.Errorf("listen on worker control socket: %w", err)
+		}
+	}
+
+	if err := os.Chmod(path, 0o600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("restrict worker control socket: %w", err)
+	}
+
+	srv := &ControlServer{ln: ln, handler: handler, done: make(chan struct{})}
+
+	srv.wg.Add(1)
+
+	go func() { defer srv.wg.Done(); srv.serve() }()
+
+	return srv, nil
+}
+
+// Close stops serving, ending any observations, and removes the socket.
+func (s *ControlServer) Close() error {
+	err := s.ln.Close()
+	s.closeOnce.Do(func() { close(s.done) })
+	s.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("close worker control socket: %w", err)
+	}
+
+	return nil
+}
+
+func (s *ControlServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+
+		go func() { defer s.wg.Done(); s.handle(conn) }()
+	}
+}
+
+func (s *ControlServer) handle(conn net.Conn) {
+	defer func() { _ = conn.Close() }()
+
+	_ = conn.SetDeadline(time.Now().Add(controlTimeout))
+
+	var (
+		req  controlRequest
+		resp controlResponse
+	)
+
+	if err := json.NewDecoder(bufio.NewReader(conn)).Decode(&req); err != nil {
+		resp.Error = "malformed request"
+	} else {
+		switch req.Command {
+		case controlCommandObserve:
+			s.observe(conn)
+			return
+		case controlCommandStatus:
+		case controlCommandStop:
+			s.handler.RequestStop()
+		case controlCommandReload:
+			s.handler.RequestReload()
+		default:
+			resp.Error = fmt.Sprintf("unknown command %q", req.Command)
+		}
+	}
+
+	if resp.Error == "" {
+		status := s.handler.ControlStatus()
+		resp.Status = &status
+	}
+
+	_ = json.NewEncoder(conn).Encode(resp)
+}
+
+// QueryWorkerStatus asks the running worker for its status.
+func QueryWorkerStatus(ctx context.Context) (*ControlStatus, error) {
+	return sendControl(ctx, controlCommandStatus)
+}
+
+// StopWorker asks the running worker to stop claiming jobs, finish the
+// in-flight job, and deregister. It returns once the request is accepted,
+// with the worker's status at that point.
+func StopWorker(ctx context.Context) (*ControlStatus, error) {
+	return sendControl(ctx, controlCommandStop)
+}
+
+// ReloadWorker asks the running worker to re-read its config, as SIGHUP
+// does. It returns once the request is accepted, with the worker's status at
+// that point.
+func ReloadWorker(ctx context.Context) (*ControlStatus, error) {
+	return sendControl(ctx, controlCommandReload)
+}
+
+func sendControl(ctx context.Context, command string) (*ControlStatus, error) {
+	path, err := paths.WorkerControlSocket()
+	if err != nil {
+		return nil, fmt.Errorf("resolve worker control socket: %w", err)
+	}
+
+	return requestControl(ctx, path, command)
+}
+
+func requestControl(ctx context.Context, path, command string) (*ControlStatus, error) {
+	ctx, cancel := context.WithTimeout(ctx, controlTimeout)
+	defer cancel()
+
+	conn, err := dialControl(ctx, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNoWorkerRunning
+		}
+
+		return nil, fmt.Errorf("connect to worker control socket: %w", err)
+	}
+
+	defer func() { _ = conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := json.NewEncoder(conn).Encode(controlRequest{Command: command}); err != nil {
+		return nil, fmt.Errorf("send worker control request: %w", err)
+	}
+
+	var resp controlResponse
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("read worker control response: %w", err)
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("worker rejected %s request: %s", command, resp.Error)
+	}
+
+	if resp.Status == nil {
+		return nil, fmt.Errorf("worker sent an empty %s response", command)
+	}
+
+	return resp.Status, nil
+}
+
+func dialControl(ctx context.Context, path string) (net.Conn, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", path, err)
+	}
+
+	return conn, nil
+}
@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+type fakeControlHandler struct {
+	stops   atomic.Int32
+	reloads atomic.Int32
+}
+
+func (h *fakeControlHandler) ControlStatus() ControlStatus {
+	return ControlStatus{
+		PID:       42,
+		WorkerID:  "wrk-1",
+		Status:    "Processing",
+		JobID:     "job-1",
+		Completed: 3,
+		Failed:    1,
+		Stopping:  h.stops.Load() > 0,
+	}
+}
+
+func (h *fakeControlHandler) RequestStop() {
+	h.stops.Add(1)
+}
+
+func (h *fakeControlHandler) RequestReload() {
+	h.reloads.Add(1)
+}
+
+// controlSocketPath returns a socket path short enough for the platform
+// limit on Unix socket paths.
+func controlSocketPath(t *testing.T) string {
+	t.Helper()
+
+	dir, err := os.MkdirTemp("", "mushctl")
+	if err != nil {
+		t.Fatalf("MkdirTemp() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	return filepath.Join(dir, "worker.sock")
+}
+
+func TestControl_StatusAndStop(t *testing.T) {
+	path := controlSocketPath(t)
+	handler := &fakeControlHandler{}
+
+	srv, err := listenControl(t.Context(), path, handler)
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	defer func() { _ = srv.Close() }()
+
+	status, err := requestControl(t.Context(), path, controlCommandStatus)
+	if err != nil {
+		t.Fatalf("status request error = %v", err)
+	}
+
+	if status.WorkerID != "wrk-1" || status.JobID != "job-1" || status.Completed != 3 || status.Failed != 1 || status.Stopping {
+		t.Fatalf("status = %+v, want the handler's running status", status)
+	}
+
+	if _, err := requestControl(t.Context(), path, controlCommandReload); err != nil || handler.reloads.Load() != 1 {
+		t.Fatalf("reload request error = %v, reloads = %d; want one reload", err, handler.reloads.Load())
+	}
+
+	status, err = requestControl(t.Context(), path, controlCommandStop)
+	if err != nil {
+		t.Fatalf("stop request error = %v", err)
+	}
+
+	if handler.stops.Load() != 1 || !status.Stopping {
+		t.Fatalf("after stop: stops = %d, status = %+v; want one stop and Stopping", handler.stops.Load(), status)
+	}
+
+	if _, err := requestControl(t.Context(), path, "reboot"); err == nil {
+		t.Fatal("unknown command should be rejected")
+	}
+}
+
+func TestControl_NoWorkerRunning(t *testing.T) {
+	path := controlSocketPath(t)
+
+	if _, err := requestControl(t.Context(), path, controlCommandStatus); !errors.Is(err, ErrNoWorkerRunning) {
+		t.Fatalf("request without a socket error = %v, want ErrNoWorkerRunning", err)
+	}
+
+	srv, err := listenControl(t.Context(), path, &fakeControlHandler{})
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	if err := srv.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("socket still present after Close: %v", err)
+	}
+}
+
+func TestListenControl_Ownership(t *testing.T) {
+	path := controlSocketPath(t)
+
+	// A socket file nobody listens on is left by a crashed worker.
+	var lc net.ListenConfig
+
+	stale, err := lc.Listen(t.Context(), "unix", path)
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	_ = stale.Close()
+
+	srv, err := listenControl(t.Context(), path, &fakeControlHandler{})
+	if err != nil {
+		t.Fatalf("listenControl() over a stale socket error = %v", err)
+	}
+
+	defer func() { _ = srv.Close() }()
+
+	if _, err := listenControl(t.Context(), path, &fakeControlHandler{}); !errors.Is(err, ErrWorkerRunning) {
+		t.Fatalf("second listenControl() error = %v, want ErrWorkerRunning", err)
+	}
+}
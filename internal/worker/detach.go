@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// WritePIDFile records pid as the process of the detached worker.
+func WritePIDFile(pid int) error {
+	path, err := pidFilePath()
+	if err != nil {
+		return err
+	}
+
+	return writePIDFile(path, pid)
+}
+
+// RemovePIDFile removes the PID file if it records pid, as a detached worker
+// does when it exits. A file recording another process is left alone.
+func RemovePIDFile(pid int) error {
+	path, err := pidFilePath()
+	if err != nil {
+		return err
+	}
+
+	return removePIDFile(path, pid)
+}
+
+// DetachedWorkerPID returns the process ID of the running detached worker,
+// or ErrNoWorkerRunning. A PID file left behind by a worker that was killed
+// is removed.
+func DetachedWorkerPID() (int, error) {
+	path, err := pidFilePath()
+	if err != nil {
+		return 0, err
+	}
+
+	return detachedWorkerPID(path)
+}
+
+// StopDetachedWorker asks the detached worker to drain and exit by signal,
+// for when it is not answering on the control socket, such as while it is
+// still starting up. It returns the worker's process ID, or
+// ErrNoWorkerRunning.
+func StopDetachedWorker() (int, error) {
+	pid, err := DetachedWorkerPID()
+	if err != nil {
+		return 0, err
+	}
+
+	if err := signalStop(pid); err != nil {
+		return 0, fmt.Errorf("signal detached worker %d: %w", pid, err)
+	}
+
+	return pid, nil
+}
+
+func pidFilePath() (string, error) {
+	path, err := paths.WorkerPIDFile()
+	if err != nil {
+		return "", fmt.Errorf("resolve worker PID file: %w", err)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+func writePIDFile(path string, pid int) error {
+	if err := safeio.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create worker PID directory: %w", err)
+	}
+
+	if err := safeio.WriteFile(path, []byte(strconv.Itoa(pid)+"\n"), 0o600); err != nil {
+		return fmt.Errorf("write worker PID file: %w", err)
+	}
+
+	return nil
+}
+
+func readPIDFile(path string) (int, error) {
+	data, exists, err := safeio.ReadFileIfExists(path)
+	if err != nil {
+		return 0, fmt.Errorf("read worker PID file: %w", err)
+	}
+
+	if !exists {
+		return 0, ErrNoWorkerRunning
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return 0, fmt.Errorf("worker PID file %s does not hold a process ID", path)
+	}
+
+	return pid, nil
+}
+
+func removePIDFile(path string, pid int) error {
+	recorded, err := readPIDFile(path)
+	if err != nil || recorded != pid {
+		return nil //nolint:nilerr // nothing of ours to remove
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove worker PID file: %w", err)
+	}
+
+	return nil
+}
+
+func detachedWorkerPID(path string) (int, error) {
+	pid, err := readPIDFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	if !processAlive(pid) {
+		_ = os.Remove(path)
+		return 0, ErrNoWorkerRunning
+	}
+
+	return pid, nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package worker
+
+import "errors"
+
+// processAlive always reports false: workers cannot be detached on this
+// platform.
+func processAlive(int) bool {
+	return false
+}
+
+func signalStop(int) error {
+	return errors.New("stopping a worker by signal is not supported on this platform")
+}
@@ -0,0 +1,59 @@
+//go:build unix
+
+package worker
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetachedWorkerPID(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "run", "worker.pid")
+
+	if _, err := detachedWorkerPID(path); !errors.Is(err, ErrNoWorkerRunning) {
+		t.Fatalf("detachedWorkerPID() without a file error = %v, want ErrNoWorkerRunning", err)
+	}
+
+	if err := writePIDFile(path, os.Getpid()); err != nil {
+		t.Fatalf("writePIDFile() error = %v", err)
+	}
+
+	if pid, err := detachedWorkerPID(path); err != nil || pid != os.Getpid() {
+		t.Fatalf("detachedWorkerPID() = %d, %v; want this process", pid, err)
+	}
+
+	if err := removePIDFile(path, os.Getpid()+1); err != nil {
+		t.Fatalf("removePIDFile() for another process error = %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("PID file removed for another process: %v", err)
+	}
+
+	if err := removePIDFile(path, os.Getpid()); err != nil {
+		t.Fatalf("removePIDFile() error = %v", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("PID file still present after removePIDFile(): %v", err)
+	}
+}
+
+func TestDetachedWorkerPIDRemovesStaleFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "worker.pid")
+
+	// PIDs are capped well below this on every supported platform.
+	if err := writePIDFile(path, 1<<30); err != nil {
+		t.Fatalf("writePIDFile() error = %v", err)
+	}
+
+	if _, err := detachedWorkerPID(path); !errors.Is(err, ErrNoWorkerRunning) {
+		t.Fatalf("detachedWorkerPID() error = %v, want ErrNoWorkerRunning", err)
+	}
+
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("stale PID file not removed: %v", err)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build unix
+
+package worker
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive reports whether a process with pid exists. A process owned by
+// another user still counts.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// signalStop sends SIGTERM, which a headless worker treats as a request to
+// drain and deregister.
+func signalStop(pid int) error {
+	return syscall.Kill(pid, syscall.SIGTERM) //nolint:wrapcheck // wrapped by StopDetachedWorker
+}
@@ -0,0 +1,37 @@
+//go:build windows
+
+package worker
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a process
+// that has not exited.
+const stillActive = 259
+
+// processAlive reports whether a process with pid exists and has not exited.
+// A process owned by another user still counts.
+func processAlive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+
+	defer func() { _ = windows.CloseHandle(handle) }()
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return false
+	}
+
+	return code == stillActive
+}
+
+// signalStop fails: Windows has no signal a detached worker can treat as a
+// request to drain, so it is stopped over the control socket instead.
+func signalStop(int) error {
+	return errors.New("stopping a worker by signal is not supported on Windows; stop it once it answers on the control socket")
+}
@@ -0,0 +1,207 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/musher-dev/mush/internal/paths"
+)
+
+// observeStatusInterval is how often an observer is sent the worker's status.
+const observeStatusInterval = time.Second
+
+// ErrObserveUnsupported is returned when the worker on the control socket
+// cannot be observed.
+var ErrObserveUnsupported = errors.New("this worker does not support observing")
+
+// ObserveFrame is one message of the stream `mush worker observe` reads. The
+// first frame carries the status, the harness terminal's size, and its
+// recent output; later ones carry new output or, every second, the status
+// and size again.
+type ObserveFrame struct {
+	Status *ControlStatus `json:"status,omitempty"`
+	Cols   int            `json:"cols,omitempty"`
+	Rows   int            `json:"rows,omitempty"`
+	Output []byte         `json:"output,omitempty"`
+
+	// Error is set on the last frame when the worker ends the stream, such
+	// as when the observer falls too far behind the harness output.
+	Error string `json:"error,omitempty"`
+}
+
+// OutputSource is implemented by a ControlHandler whose harness terminal
+// can be watched with `mush worker observe`.
+type OutputSource interface {
+	// TerminalSize returns the size of the harness terminal.
+	TerminalSize() (cols, rows int)
+
+	// SubscribeOutput returns the harness's recent output and a channel of
+	// the output it writes from then on. The channel is closed if the
+	// subscriber falls behind; cancel ends the subscription.
+	SubscribeOutput() (recent []byte, output <-chan []byte, cancel func())
+}
+
+// observe streams frames to an observer until it disconnects, the worker
+// ends the observation, or the server closes. Nothing the observer sends
+// after its request is read.
+func (s *ControlServer) observe(conn net.Conn) {
+	enc := json.NewEncoder(conn)
+
+	source, ok := s.handler.(OutputSource)
+	if !ok {
+		_ = enc.Encode(ObserveFrame{Error: ErrObserveUnsupported.Error()})
+		return
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	recent, output, cancel := source.SubscribeOutput()
+	defer cancel()
+
+	// The observer sends nothing more, so a finished read means it left.
+	gone := make(chan struct{})
+
+	go func() {
+		defer close(gone)
+
+		_, _ = io.Copy(io.Discard, conn)
+	}()
+
+	send := func(frame *ObserveFrame) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(controlTimeout))
+		return enc.Encode(frame) == nil
+	}
+
+	statusFrame := func() *ObserveFrame {
+		status := s.handler.ControlStatus()
+		cols, rows := source.TerminalSize()
+
+		return &ObserveFrame{Status: &status, Cols: cols, Rows: rows}
+	}
+
+	first := statusFrame()
+	first.Output = recent
+
+	if !send(first) {
+		return
+	}
+
+	ticker := time.NewTicker(observeStatusInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-gone:
+			return
+		case <-ticker.C:
+			if !send(statusFrame()) {
+				return
+			}
+		case p, ok := <-output:
+			if !ok {
+				send(&ObserveFrame{Error: "observer fell behind the harness output"})
+				return
+			}
+
+			if !send(&ObserveFrame{Output: p}) {
+				return
+			}
+		}
+	}
+}
+
+// Observation is the frame stream of a worker being observed.
+type Observation struct {
+	rc  io.ReadCloser
+	dec *json.Decoder
+}
+
+// ObserveWorker starts observing the worker on the control socket at path,
+// or on this machine's control socket when path is empty.
+func ObserveWorker(ctx context.Context, path string) (*Observation, error) {
+	if path == "" {
+		var err error
+
+		path, err = paths.WorkerControlSocket()
+		if err != nil {
+			return nil, fmt.Errorf("resolve worker control socket: %w", err)
+		}
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, controlTimeout)
+	defer cancel()
+
+	conn, err := dialControl(dialCtx, path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, ErrNoWorkerRunning
+		}
+
+		return nil, fmt.Errorf("connect to worker control socket: %w", err)
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(controlTimeout))
+
+	if err := json.NewEncoder(conn).Encode(controlRequest{Command: controlCommandObserve}); err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("send worker observe request: %w", err)
+	}
+
+	_ = conn.SetWriteDeadline(time.Time{})
+
+	return ReadObservation(conn), nil
+}
+
+// ReadObservation reads an observation's frames from rc, such as the output
+// of `mush worker observe --stream` run on another machine.
+func ReadObservation(rc io.ReadCloser) *Observation {
+	return &Observation{rc: rc, dec: json.NewDecoder(rc)}
+}
+
+// Next returns the next frame. It returns io.EOF when the worker closes the
+// stream, as it does when it exits, and the worker's reason when it ends
+// the stream itself.
+func (o *Observation) Next() (*ObserveFrame, error) {
+	var frame ObserveFrame
+	if err := o.dec.Decode(&frame); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, io.EOF
+		}
+
+		return nil, err //nolint:wrapcheck // io.EOF must reach the caller unwrapped
+	}
+
+	if frame.Error != "" {
+		if frame.Error == ErrObserveUnsupported.Error() {
+			return nil, ErrObserveUnsupported
+		}
+
+		return nil, fmt.Errorf("worker ended the observation: %s", frame.Error)
+	}
+
+	return &frame, nil
+}
+
+// Relay copies the frames not yet read to w unchanged until the worker
+// closes the stream, for `mush worker observe --stream`.
+func (o *Observation) Relay(w io.Writer) error {
+	if _, err := io.Copy(w, io.MultiReader(o.dec.Buffered(), o.rc)); err != nil {
+		return fmt.Errorf("relay worker observation: %w", err)
+	}
+
+	return nil
+}
+
+// Close stops observing.
+func (o *Observation) Close() error {
+	return o.rc.Close() //nolint:wrapcheck // closing a socket or pipe
+}
@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type fakeObservedHandler struct {
+	fakeControlHandler
+
+	output chan []byte
+}
+
+func (h *fakeObservedHandler) TerminalSize() (cols, rows int) {
+	return 100, 30
+}
+
+func (h *fakeObservedHandler) SubscribeOutput() (recent []byte, output <-chan []byte, cancel func()) {
+	return []byte("recent"), h.output, func() {}
+}
+
+func TestObserveWorker_StreamsStatusAndOutput(t *testing.T) {
+	path := controlSocketPath(t)
+	handler := &fakeObservedHandler{output: make(chan []byte, 1)}
+
+	srv, err := listenControl(t.Context(), path, handler)
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	obs, err := ObserveWorker(t.Context(), path)
+	if err != nil {
+		t.Fatalf("ObserveWorker() error = %v", err)
+	}
+
+	defer func() { _ = obs.Close() }()
+
+	first, err := obs.Next()
+	if err != nil {
+		t.Fatalf("first frame error = %v", err)
+	}
+
+	if first.Status == nil || first.Status.JobID != "job-1" || first.Cols != 100 || first.Rows != 30 || string(first.Output) != "recent" {
+		t.Fatalf("first frame = %+v, want status, size, and recent output", first)
+	}
+
+	handler.output <- []byte("live")
+
+	for {
+		frame, err := obs.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+
+		if string(frame.Output) == "live" {
+			break
+		}
+	}
+
+	_ = srv.Close()
+
+	for {
+		if _, err := obs.Next(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("Next() after the worker closed = %v, want io.EOF", err)
+			}
+
+			break
+		}
+	}
+}
+
+func TestObserveWorker_Unsupported(t *testing.T) {
+	path := controlSocketPath(t)
+
+	srv, err := listenControl(t.Context(), path, &fakeControlHandler{})
+	if err != nil {
+		t.Skipf("unix socket not available in this environment: %v", err)
+	}
+
+	defer func() { _ = srv.Close() }()
+
+	obs, err := ObserveWorker(t.Context(), path)
+	if err != nil {
+		t.Fatalf("ObserveWorker() error = %v", err)
+	}
+
+	defer func() { _ = obs.Close() }()
+
+	if _, err := obs.Next(); !errors.Is(err, ErrObserveUnsupported) {
+		t.Fatalf("Next() error = %v, want ErrObserveUnsupported", err)
+	}
+}
+
+func TestObservation_Relay(t *testing.T) {
+	stream := `{"status":{"status":"Ready"},"cols":80,"rows":24}` + "\n" +
+		`{"output":"aGk="}` + "\n"
+
+	obs := ReadObservation(io.NopCloser(strings.NewReader(stream)))
+
+	if _, err := obs.Next(); err != nil {
+		t.Fatalf("Next() error = %v", err)
+	}
+
+	var relayed strings.Builder
+	if err := obs.Relay(&relayed); err != nil {
+		t.Fatalf("Relay() error = %v", err)
+	}
+
+	frame, err := ReadObservation(io.NopCloser(strings.NewReader(relayed.String()))).Next()
+	if err != nil || string(frame.Output) != "hi" {
+		t.Fatalf("relayed %q: frame = %+v, err = %v; want the unread output frame", relayed.String(), frame, err)
+	}
+}
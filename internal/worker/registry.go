@@ -0,0 +1,135 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// Registration records a worker link that has not been deregistered yet.
+// A crash or kill leaves one behind; `mush uninstall` uses them to clean up.
+type Registration struct {
+	APIURL       string    `json:"apiUrl"`
+	WorkerID     string    `json:"workerId"`
+	RegisteredAt time.Time `json:"registeredAt"`
+}
+
+// LingeringRegistrations returns workers registered from this machine that
+// never deregistered, oldest first.
+func LingeringRegistrations() ([]Registration, error) {
+	dir, err := registryDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return listRegistrations(dir)
+}
+
+// ForgetRegistration removes the local record for workerID.
+func ForgetRegistration(workerID string) error {
+	dir, err := registryDir()
+	if err != nil {
+		return err
+	}
+
+	return forgetRegistration(dir, workerID)
+}
+
+func registryDir() (string, error) {
+	dir, err := paths.WorkerRegistryDir()
+	if err != nil {
+		return "", fmt.Errorf("resolve worker registry directory: %w", err)
+	}
+
+	return filepath.Clean(dir), nil
+}
+
+// registrationFile maps a worker ID to its record. IDs come from the
+// platform, so anything that could escape the directory is rejected.
+func registrationFile(dir, workerID string) (string, error) {
+	if workerID == "" || strings.ContainsAny(workerID, `/\`) || workerID == "." || workerID == ".." {
+		return "", fmt.Errorf("invalid worker ID %q", workerID)
+	}
+
+	return filepath.Join(dir, workerID+".json"), nil
+}
+
+func recordRegistration(dir string, reg Registration) error {
+	path, err := registrationFile(dir, reg.WorkerID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(reg)
+	if err != nil {
+		return fmt.Errorf("marshal worker registration: %w", err)
+	}
+
+	if err := safeio.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create worker registry directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write worker registration: %w", err)
+	}
+
+	return nil
+}
+
+func forgetRegistration(dir, workerID string) error {
+	path, err := registrationFile(dir, workerID)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove worker registration: %w", err)
+	}
+
+	return nil
+}
+
+func listRegistrations(dir string) ([]Registration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read worker registry: %w", err)
+	}
+
+	var regs []Registration
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+
+		data, readErr := safeio.ReadFile(filepath.Join(dir, entry.Name()))
+		if readErr != nil {
+			continue
+		}
+
+		var reg Registration
+		if json.Unmarshal(data, &reg) != nil || reg.WorkerID == "" || reg.APIURL == "" {
+			continue
+		}
+
+		regs = append(regs, reg)
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].RegisteredAt.Before(regs[j].RegisteredAt)
+	})
+
+	return regs, nil
+}
@@ -0,0 +1,69 @@
+package worker
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegistry_RecordListForget(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "workers")
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	regs, err := listRegistrations(dir)
+	if err != nil {
+		t.Fatalf("listRegistrations() on missing dir error = %v", err)
+	}
+
+	if len(regs) != 0 {
+		t.Fatalf("listRegistrations() on missing dir = %v, want empty", regs)
+	}
+
+	for i, id := range []string{"wrk-b", "wrk-a"} {
+		reg := Registration{APIURL: "https://api.musher.dev", WorkerID: id, RegisteredAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := recordRegistration(dir, reg); err != nil {
+			t.Fatalf("recordRegistration(%s) error = %v", id, err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "junk.json"), []byte("{"), 0o600); err != nil {
+		t.Fatalf("write junk: %v", err)
+	}
+
+	regs, err = listRegistrations(dir)
+	if err != nil {
+		t.Fatalf("listRegistrations() error = %v", err)
+	}
+
+	if len(regs) != 2 || regs[0].WorkerID != "wrk-b" || regs[1].WorkerID != "wrk-a" {
+		t.Fatalf("listRegistrations() = %+v, want wrk-b then wrk-a", regs)
+	}
+
+	if err := forgetRegistration(dir, "wrk-b"); err != nil {
+		t.Fatalf("forgetRegistration() error = %v", err)
+	}
+
+	if err := forgetRegistration(dir, "wrk-b"); err != nil {
+		t.Fatalf("forgetRegistration() twice error = %v", err)
+	}
+
+	regs, err = listRegistrations(dir)
+	if err != nil {
+		t.Fatalf("listRegistrations() error = %v", err)
+	}
+
+	if len(regs) != 1 || regs[0].WorkerID != "wrk-a" {
+		t.Fatalf("listRegistrations() after forget = %+v, want only wrk-a", regs)
+	}
+}
+
+func TestRegistry_RejectsUnsafeWorkerID(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, id := range []string{"", "..", "../escape", `a\b`} {
+		if err := recordRegistration(dir, Registration{APIURL: "https://api.musher.dev", WorkerID: id}); err == nil {
+			t.Errorf("recordRegistration(%q) error = nil, want rejection", id)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package worker
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/musher-dev/mush/internal/auth"
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// ErrNoRunnerConfigCache is returned when no last-known-good runner config exists.
+var ErrNoRunnerConfigCache = errors.New("no cached runner config")
+
+// CachedRunnerConfig is the last-known-good runner config loaded from disk.
+type CachedRunnerConfig struct {
+	Config  *client.RunnerConfigResponse
+	SavedAt time.Time
+
+	// CredentialsRestored reports whether provider credentials were decrypted.
+	// When false, the config carries MCP endpoints but no usable tokens.
+	CredentialsRestored bool
+}
+
+// runnerConfigCacheFile is the on-disk format. Provider credentials are never
+// written in clear text: they are sealed with a keyring-held key or omitted.
+// CacheKey records which API key saved the entry (see client.CacheKey).
+type runnerConfigCacheFile struct {
+	CacheKey          string                       `json:"cacheKey"`
+	SavedAt           time.Time                    `json:"savedAt"`
+	Config            *client.RunnerConfigResponse `json:"config"`
+	SealedCredentials []byte                       `json:"sealedCredentials,omitempty"`
+}
+
+// SaveRunnerConfigCache persists cfg as the last-known-good runner config for
+// c's API URL and habitatID; an empty habitatID is the organization-wide config.
+// Credentials are encrypted when an OS keyring key is available and dropped otherwise.
+func SaveRunnerConfigCache(c *client.Client, habitatID string, cfg *client.RunnerConfigResponse, now time.Time) error {
+	path, err := runnerConfigCachePath(c.BaseURL(), habitatID)
+	if err != nil {
+		return err
+	}
+
+	key, keyErr := auth.RunnerConfigCacheKey(c.BaseURL())
+	if keyErr != nil {
+		key = nil
+	}
+
+	return saveRunnerConfigCache(path, c.CacheKey(), key, cfg, now)
+}
+
+// LoadRunnerConfigCache returns the cached runner config for c's API URL and
+// habitatID. Each habitat has its own cache, so switching habitats never
+// reuses another habitat's credentials, and an entry saved with a different
+// API key is ignored, so logging in to another organization never reuses the
+// previous one's. It returns ErrNoRunnerConfigCache when nothing usable has
+// been cached.
+func LoadRunnerConfigCache(c *client.Client, habitatID string) (*CachedRunnerConfig, error) {
+	path, err := runnerConfigCachePath(c.BaseURL(), habitatID)
+	if err != nil {
+		return nil, err
+	}
+
+	key, keyErr := auth.RunnerConfigCacheKey(c.BaseURL())
+	if keyErr != nil {
+		key = nil
+	}
+
+	return loadRunnerConfigCache(path, c.CacheKey(), key)
+}
+
+func runnerConfigCachePath(apiURL, habitatID string) (string, error) {
+	hostID := paths.HostIDFromURL(apiURL)
+
+	var (
+		path string
+		err  error
+	)
+
+	if habitatID == "" {
+		path, err = paths.RunnerConfigCacheFile(hostID)
+	} else {
+		path, err = paths.HabitatRunnerConfigCacheFile(hostID, habitatID)
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("resolve runner config cache path: %w", err)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+func saveRunnerConfigCache(path, cacheKey string, key []byte, cfg *client.RunnerConfigResponse, now time.Time) error {
+	if cfg == nil {
+		return fmt.Errorf("runner config is nil")
+	}
+
+	redacted := *cfg
+	redacted.Providers = make(map[string]client.RunnerProviderConfig, len(cfg.Providers))
+	credentials := make(map[string]*client.RunnerProviderCredential)
+
+	for name, provider := range cfg.Providers {
+		if provider.Credential != nil {
+			credentials[name] = provider.Credential
+			provider.Credential = nil
+		}
+
+		redacted.Providers[name] = provider
+	}
+
+	record := runnerConfigCacheFile{
+		CacheKey: cacheKey,
+		SavedAt:  now.UTC(),
+		Config:   &redacted,
+	}
+
+	if len(key) > 0 && len(credentials) > 0 {
+		plaintext, err := json.Marshal(credentials)
+		if err != nil {
+			return fmt.Errorf("marshal runner config credentials: %w", err)
+		}
+
+		sealed, err := sealRunnerConfig(key, plaintext)
+		if err != nil {
+			return err
+		}
+
+		record.SealedCredentials = sealed
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal runner config cache: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := safeio.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create runner config cache directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp runner config cache: %w", err)
+	}
+
+	tmp := tmpFile.Name()
+	if _, writeErr := tmpFile.Write(data); writeErr != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("write temp runner config cache: %w", writeErr)
+	}
+
+	if closeErr := tmpFile.Close(); closeErr != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close temp runner config cache: %w", closeErr)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace runner config cache: %w", err)
+	}
+
+	return nil
+}
+
+func loadRunnerConfigCache(path, cacheKey string, key []byte) (*CachedRunnerConfig, error) {
+	data, err := safeio.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoRunnerConfigCache
+		}
+
+		return nil, fmt.Errorf("read runner config cache: %w", err)
+	}
+
+	var record runnerConfigCacheFile
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("parse runner config cache: %w", err)
+	}
+
+	if record.Config == nil {
+		return nil, fmt.Errorf("parse runner config cache: missing config")
+	}
+
+	if record.CacheKey != cacheKey {
+		return nil, ErrNoRunnerConfigCache
+	}
+
+	cached := &CachedRunnerConfig{
+		Config:  record.Config,
+		SavedAt: record.SavedAt,
+	}
+
+	// A rotated or missing key leaves the endpoints usable without tokens.
+	if len(record.SealedCredentials) > 0 && len(key) > 0 {
+		if plaintext, openErr := openRunnerConfig(key, record.SealedCredentials); openErr == nil {
+			cached.CredentialsRestored = restoreRunnerConfigCredentials(record.Config, plaintext)
+		}
+	}
+
+	return cached, nil
+}
+
+func restoreRunnerConfigCredentials(cfg *client.RunnerConfigResponse, plaintext []byte) bool {
+	var credentials map[string]*client.RunnerProviderCredential
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return false
+	}
+
+	for name, credential := range credentials {
+		provider, ok := cfg.Providers[name]
+		if !ok {
+			continue
+		}
+
+		provider.Credential = credential
+		cfg.Providers[name] = provider
+	}
+
+	return true
+}
+
+func sealRunnerConfig(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newRunnerConfigAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate runner config nonce: %w", err)
+	}
+
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func openRunnerConfig(key, sealed []byte) ([]byte, error) {
+	gcm, err := newRunnerConfigAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, fmt.Errorf("sealed runner config credentials too short")
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt runner config credentials: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+func newRunnerConfigAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("init runner config cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("init runner config cipher: %w", err)
+	}
+
+	return gcm, nil
+}
@@ -0,0 +1,185 @@
+package worker
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/client"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+const testCacheKey = "cache-key-1"
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func testRunnerConfig() *client.RunnerConfigResponse {
+	return &client.RunnerConfigResponse{
+		ConfigVersion:       "v1",
+		OrganizationID:      "org-1",
+		RefreshAfterSeconds: 120,
+		Providers: map[string]client.RunnerProviderConfig{
+			"linear": {
+				Status:     "active",
+				Flags:      client.RunnerProviderFlags{MCP: true},
+				MCP:        &client.RunnerProviderMCP{URL: "https://mcp.linear.app/mcp"},
+				Credential: &client.RunnerProviderCredential{AccessToken: "secret-token", TokenType: "bearer"},
+			},
+		},
+	}
+}
+
+func TestRunnerConfigCache_RoundTripWithKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runner-config", "api.musher.dev.json")
+	key := bytes.Repeat([]byte{0x42}, 32)
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if err := saveRunnerConfigCache(path, testCacheKey, key, testRunnerConfig(), now); err != nil {
+		t.Fatalf("saveRunnerConfigCache() error = %v", err)
+	}
+
+	raw, err := safeio.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read cache file: %v", err)
+	}
+
+	if bytes.Contains(raw, []byte("secret-token")) {
+		t.Fatal("cache file contains clear-text access token")
+	}
+
+	cached, err := loadRunnerConfigCache(path, testCacheKey, key)
+	if err != nil {
+		t.Fatalf("loadRunnerConfigCache() error = %v", err)
+	}
+
+	if !cached.CredentialsRestored {
+		t.Fatal("CredentialsRestored = false, want true")
+	}
+
+	if !cached.SavedAt.Equal(now) {
+		t.Errorf("SavedAt = %v, want %v", cached.SavedAt, now)
+	}
+
+	provider := cached.Config.Providers["linear"]
+	if provider.Credential == nil || provider.Credential.AccessToken != "secret-token" {
+		t.Fatalf("credential not restored: %+v", provider.Credential)
+	}
+
+	if provider.MCP == nil || provider.MCP.URL != "https://mcp.linear.app/mcp" {
+		t.Fatalf("MCP endpoint not restored: %+v", provider.MCP)
+	}
+}
+
+func TestRunnerConfigCache_OmitsCredentialsWithoutKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	if err := saveRunnerConfigCache(path, testCacheKey, nil, testRunnerConfig(), time.Now()); err != nil {
+		t.Fatalf("saveRunnerConfigCache() error = %v", err)
+	}
+
+	cached, err := loadRunnerConfigCache(path, testCacheKey, bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatalf("loadRunnerConfigCache() error = %v", err)
+	}
+
+	if cached.CredentialsRestored {
+		t.Error("CredentialsRestored = true, want false")
+	}
+
+	if cached.Config.Providers["linear"].Credential != nil {
+		t.Error("credential should be omitted when no key is available")
+	}
+}
+
+func TestRunnerConfigCache_WrongKeyDropsCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+
+	if err := saveRunnerConfigCache(path, testCacheKey, bytes.Repeat([]byte{0x01}, 32), testRunnerConfig(), time.Now()); err != nil {
+		t.Fatalf("saveRunnerConfigCache() error = %v", err)
+	}
+
+	cached, err := loadRunnerConfigCache(path, testCacheKey, bytes.Repeat([]byte{0x02}, 32))
+	if err != nil {
+		t.Fatalf("loadRunnerConfigCache() error = %v", err)
+	}
+
+	if cached.CredentialsRestored {
+		t.Error("CredentialsRestored = true with mismatched key")
+	}
+
+	if cached.Config.Providers["linear"].MCP == nil {
+		t.Error("MCP endpoint should survive a key mismatch")
+	}
+}
+
+func TestRunnerConfigCache_Missing(t *testing.T) {
+	_, err := loadRunnerConfigCache(filepath.Join(t.TempDir(), "missing.json"), testCacheKey, nil)
+	if !errors.Is(err, ErrNoRunnerConfigCache) {
+		t.Fatalf("loadRunnerConfigCache() error = %v, want ErrNoRunnerConfigCache", err)
+	}
+}
+
+func TestRunnerConfigCache_OtherAPIKeyIgnored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	if err := saveRunnerConfigCache(path, testCacheKey, key, testRunnerConfig(), time.Now()); err != nil {
+		t.Fatalf("saveRunnerConfigCache() error = %v", err)
+	}
+
+	_, err := loadRunnerConfigCache(path, "cache-key-2", key)
+	if !errors.Is(err, ErrNoRunnerConfigCache) {
+		t.Fatalf("loadRunnerConfigCache(other API key) error = %v, want ErrNoRunnerConfigCache", err)
+	}
+}
+
+func TestRunnerConfigCache_SurvivesOAuthRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body := `{"credentialName":"device-login"}`
+		if r.URL.Path == "/v1/oauth/token" {
+			body = `{"access_token":"access-2","refresh_token":"refresh-2","expires_in":3600}`
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	var renewed *client.Token
+
+	first := client.NewWithHTTPClient("https://api.test", "access-1", &http.Client{Transport: transport})
+	first.SetOAuthToken(&client.Token{AccessToken: "access-1", RefreshToken: "refresh-1", ExpiresAt: time.Now().Add(time.Second), Grant: "grant-1"},
+		func(tok *client.Token) error {
+			renewed = tok
+
+			return nil
+		})
+
+	if _, err := first.ValidateKey(t.Context()); err != nil {
+		t.Fatalf("ValidateKey() error = %v", err)
+	}
+
+	if renewed == nil {
+		t.Fatal("token was not renewed")
+	}
+
+	if err := saveRunnerConfigCache(path, first.CacheKey(), key, testRunnerConfig(), time.Now()); err != nil {
+		t.Fatalf("saveRunnerConfigCache() error = %v", err)
+	}
+
+	// A restart authenticates with the renewed token.
+	restarted := client.New("https://api.test", renewed.AccessToken)
+	restarted.SetOAuthToken(renewed, nil)
+
+	if _, err := loadRunnerConfigCache(path, restarted.CacheKey(), key); err != nil {
+		t.Fatalf("loadRunnerConfigCache() after refresh error = %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// StatusFile is the worker state kept in the status file while a worker
+// runs, for status bars and other tools that would rather poll a file than
+// talk to the control socket.
+type StatusFile struct {
+	ControlStatus
+
+	// JobStartedAt is when the running job started.
+	JobStartedAt *time.Time `json:"jobStartedAt,omitempty"`
+
+	// LastHeartbeat and HeartbeatAgeSeconds describe the running job's last
+	// successful heartbeat.
+	LastHeartbeat       *time.Time `json:"lastHeartbeat,omitempty"`
+	HeartbeatAgeSeconds *int64     `json:"heartbeatAgeSeconds,omitempty"`
+
+	// LastError is the most recent error or warning, if any.
+	LastError string `json:"lastError,omitempty"`
+
+	// UpdatedAt is when the file was written. A worker rewrites it every few
+	// seconds, so an old timestamp means the worker was killed.
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// WriteStatusFile replaces the status file with status.
+func WriteStatusFile(status *StatusFile) error {
+	path, err := statusFilePath()
+	if err != nil {
+		return err
+	}
+
+	return writeStatusFile(path, status)
+}
+
+// RemoveStatusFile removes the status file, as a worker does when it exits.
+func RemoveStatusFile() error {
+	path, err := statusFilePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove worker status file: %w", err)
+	}
+
+	return nil
+}
+
+func statusFilePath() (string, error) {
+	path, err := paths.WorkerStatusFile()
+	if err != nil {
+		return "", fmt.Errorf("resolve worker status file: %w", err)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+// writeStatusFile writes through a temp file and a rename, so a reader never
+// sees a partial file.
+func writeStatusFile(path string, status *StatusFile) error {
+	data, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Errorf("marshal worker status: %w", err)
+	}
+
+	dir := filepath.Dir(path)
+	if err := safeio.MkdirAll(dir, 0o700); err != nil {
+		return fmt.Errorf("create worker status directory: %w", err)
+	}
+
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("create temp worker status file: %w", err)
+	}
+
+	tmp := tmpFile.Name()
+	if _, writeErr := tmpFile.Write(data); writeErr != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(tmp)
+
+		return fmt.Errorf("write temp worker status file: %w", writeErr)
+	}
+
+	if closeErr := tmpFile.Close(); closeErr != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close temp worker status file: %w", closeErr)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("replace worker status file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteStatusFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "worker-status.json")
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	age := int64(12)
+
+	for _, status := range []string{"Connected", "Processing"} {
+		err := writeStatusFile(path, &StatusFile{
+			ControlStatus:       ControlStatus{PID: 42, WorkerID: "wrk-1", Status: status, Completed: 3},
+			HeartbeatAgeSeconds: &age,
+			UpdatedAt:           now,
+		})
+		if err != nil {
+			t.Fatalf("writeStatusFile() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode %q: %v", data, err)
+	}
+
+	if got["status"] != "Processing" || got["workerId"] != "wrk-1" || got["completed"] != 3.0 || got["heartbeatAgeSeconds"] != 12.0 {
+		t.Fatalf("status file = %s, want the last write with flattened fields", data)
+	}
+
+	if _, ok := got["jobStartedAt"]; ok {
+		t.Fatalf("status file = %s, want no jobStartedAt while idle", data)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+
+	if len(entries) != 1 {
+		t.Fatalf("status directory has %d entries, want no temp files left", len(entries))
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("status file mode = %o, want 600", perm)
+	}
+}
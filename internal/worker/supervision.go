@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+	"github.com/musher-dev/mush/internal/paths"
+	"github.com/musher-dev/mush/internal/safeio"
+)
+
+// ErrNoSupervisionRecord is returned when no worker has recorded harness
+// supervision metrics yet.
+var ErrNoSupervisionRecord = errors.New("no harness supervision record")
+
+// SupervisionRecord is the harness process supervision of the most recent
+// worker session, keyed by harness type.
+type SupervisionRecord struct {
+	UpdatedAt time.Time                          `json:"updatedAt"`
+	Harnesses map[string]harnesstype.Supervision `json:"harnesses"`
+}
+
+// SaveSupervision replaces the recorded supervision metrics with harnesses.
+func SaveSupervision(harnesses map[string]harnesstype.Supervision, now time.Time) error {
+	path, err := supervisionPath()
+	if err != nil {
+		return err
+	}
+
+	return saveSupervision(path, SupervisionRecord{UpdatedAt: now.UTC(), Harnesses: harnesses})
+}
+
+// LoadSupervision returns the recorded supervision metrics.
+// It returns ErrNoSupervisionRecord when nothing has been recorded yet.
+func LoadSupervision() (*SupervisionRecord, error) {
+	path, err := supervisionPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return loadSupervision(path)
+}
+
+func supervisionPath() (string, error) {
+	path, err := paths.HarnessSupervisionFile()
+	if err != nil {
+		return "", fmt.Errorf("resolve harness supervision path: %w", err)
+	}
+
+	return filepath.Clean(path), nil
+}
+
+func saveSupervision(path string, record SupervisionRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal harness supervision: %w", err)
+	}
+
+	if err := safeio.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("create harness supervision directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("write harness supervision: %w", err)
+	}
+
+	return nil
+}
+
+func loadSupervision(path string) (*SupervisionRecord, error) {
+	data, err := safeio.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNoSupervisionRecord
+		}
+
+		return nil, fmt.Errorf("read harness supervision: %w", err)
+	}
+
+	var record SupervisionRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("parse harness supervision: %w", err)
+	}
+
+	return &record, nil
+}
@@ -0,0 +1,45 @@
+package worker
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/musher-dev/mush/internal/harness/harnesstype"
+)
+
+func TestSupervision_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "harness-supervision.json")
+
+	if _, err := loadSupervision(path); !errors.Is(err, ErrNoSupervisionRecord) {
+		t.Fatalf("loadSupervision() on missing file error = %v, want ErrNoSupervisionRecord", err)
+	}
+
+	now := time.Date(2026, 3, 4, 5, 6, 7, 0, time.UTC)
+	want := harnesstype.Supervision{
+		PTYStarts:     3,
+		BypassAccepts: 3,
+		ReadyTimeouts: 1,
+		ReadyWaits:    3,
+		ReadyTotal:    9 * time.Second,
+	}
+
+	record := SupervisionRecord{UpdatedAt: now, Harnesses: map[string]harnesstype.Supervision{"claude": want}}
+	if err := saveSupervision(path, record); err != nil {
+		t.Fatalf("saveSupervision() error = %v", err)
+	}
+
+	got, err := loadSupervision(path)
+	if err != nil {
+		t.Fatalf("loadSupervision() error = %v", err)
+	}
+
+	if !got.UpdatedAt.Equal(now) {
+		t.Fatalf("UpdatedAt = %v, want %v", got.UpdatedAt, now)
+	}
+
+	if got.Harnesses["claude"] != want {
+		t.Fatalf("Harnesses[claude] = %+v, want %+v", got.Harnesses["claude"], want)
+	}
+}
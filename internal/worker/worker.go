@@ -73,16 +73,28 @@ func Register(
 
 	span.SetAttributes(attribute.String("worker.id", resp.WorkerID))
 
+	// The record only helps `mush uninstall` clean up after a crash, so a
+	// read-only state directory must not keep the worker from starting.
+	if dir, dirErr := registryDir(); dirErr == nil {
+		_ = recordRegistration(dir, Registration{
+			APIURL:       apiClient.BaseURL(),
+			WorkerID:     resp.WorkerID,
+			RegisteredAt: time.Now().UTC(),
+		})
+	}
+
 	return resp.WorkerID, nil
 }
 
 // StartHeartbeat sends periodic worker heartbeats until the context is canceled.
-// If onError is non-nil, it is called whenever a heartbeat attempt fails.
+// If onResponse is non-nil, it is called with each heartbeat's response, and
+// if onError is non-nil, whenever a heartbeat attempt fails.
 func StartHeartbeat(
 	ctx context.Context,
 	apiClient *client.Client,
 	workerID string,
 	currentJobID func() string,
+	onResponse func(*client.WorkerHeartbeatResponse),
 	onError func(error),
 ) {
 	if workerID == "" {
@@ -104,10 +116,17 @@ func StartHeartbeat(
 					jobID = currentJobID()
 				}
 
-				if _, err := apiClient.HeartbeatWorker(ctx, workerID, jobID); err != nil {
+				resp, err := apiClient.HeartbeatWorker(ctx, workerID, jobID)
+				if err != nil {
 					if onError != nil {
 						onError(err)
 					}
+
+					continue
+				}
+
+				if onResponse != nil {
+					onResponse(resp)
 				}
 			}
 		}
@@ -142,5 +161,7 @@ func Deregister(apiClient *client.Client, workerID string, completed, failed int
 		return fmt.Errorf("deregister worker %s: %w", workerID, err)
 	}
 
+	_ = ForgetRegistration(workerID)
+
 	return nil
 }
@@ -0,0 +1,226 @@
+//go:build unix || windows
+
+// Package workspace keeps warm clones of the repositories jobs run in and
+// hands each job a fresh worktree, so a job does not pay for a full clone.
+package workspace
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/musher-dev/mush/internal/executil"
+	"github.com/musher-dev/mush/internal/paths"
+)
+
+// Cache layout under the workspace cache root:
+//
+//	repos/{urlDigest}.git       — bare mirror clone, fetched before each job
+//	repos/{urlDigest}.git.lock  — lock held while the mirror is updated
+//	worktrees/{name}/           — per-job detached worktree
+const (
+	reposDir     = "repos"
+	worktreesDir = "worktrees"
+)
+
+// ErrRefNotFound is returned when the requested ref does not exist in the repository.
+var ErrRefNotFound = errors.New("ref not found in repository")
+
+// Cache manages mirror clones and job worktrees under a root directory.
+type Cache struct {
+	root string
+}
+
+// Worktree is a checkout created for a single job.
+type Worktree struct {
+	// Path is the worktree directory.
+	Path string
+	// Commit is the commit checked out.
+	Commit string
+
+	mirror string
+}
+
+// NewCache returns the cache under paths.WorkspaceCacheDir.
+func NewCache() (*Cache, error) {
+	root, err := paths.WorkspaceCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("resolve workspace cache: %w", err)
+	}
+
+	return NewCacheAt(root), nil
+}
+
+// NewCacheAt returns a cache rooted at root.
+func NewCacheAt(root string) *Cache {
+	return &Cache{root: root}
+}
+
+// mirrorPath returns the mirror clone directory for a repository URL.
+func (c *Cache) mirrorPath(repoURL string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(repoURL)))
+	return filepath.Join(c.root, reposDir, hex.EncodeToString(sum[:])[:16]+".git")
+}
+
+// Checkout updates (or creates) the mirror of repoURL and adds a detached
+// worktree named name at ref. An empty ref checks out the default branch.
+// A leftover worktree with the same name is replaced.
+func (c *Cache) Checkout(ctx context.Context, repoURL, ref, name string) (*Worktree, error) {
+	if strings.TrimSpace(repoURL) == "" {
+		return nil, fmt.Errorf("repository URL is required")
+	}
+
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, fmt.Errorf("invalid worktree name %q", name)
+	}
+
+	for _, dir := range []string{reposDir, worktreesDir} {
+		if err := os.MkdirAll(filepath.Join(c.root, dir), 0o700); err != nil {
+			return nil, fmt.Errorf("create workspace cache: %w", err)
+		}
+	}
+
+	mirror := c.mirrorPath(repoURL)
+
+	unlock, err := lockFile(mirror + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if err := c.syncMirror(ctx, repoURL, mirror); err != nil {
+		return nil, err
+	}
+
+	target := ref
+	if target == "" {
+		target = "HEAD"
+	}
+
+	commit, err := git(ctx, mirror, "rev-parse", "--verify", "--quiet", target+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", ref, ErrRefNotFound)
+	}
+
+	worktreePath := filepath.Join(c.root, worktreesDir, name)
+	removeWorktree(ctx, mirror, worktreePath)
+
+	if _, err := git(ctx, mirror, "worktree", "add", "--detach", "--quiet", worktreePath, commit); err != nil {
+		return nil, err
+	}
+
+	return &Worktree{Path: worktreePath, Commit: commit, mirror: mirror}, nil
+}
+
+// syncMirror clones repoURL into mirror, or fetches it when already cloned.
+// The clone goes through a temporary directory so an interrupted clone is
+// never mistaken for a warm cache.
+func (c *Cache) syncMirror(ctx context.Context, repoURL, mirror string) error {
+	if _, err := os.Stat(mirror); err == nil {
+		if _, err := git(ctx, mirror, "fetch", "--prune", "--quiet", "origin"); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		_ = os.Chtimes(mirror, now, now)
+
+		return nil
+	}
+
+	staging, err := os.MkdirTemp(filepath.Dir(mirror), filepath.Base(mirror)+".partial.")
+	if err != nil {
+		return fmt.Errorf("create clone staging directory: %w", err)
+	}
+
+	if _, err := git(ctx, "", "clone", "--mirror", "--quiet", repoURL, staging); err != nil {
+		_ = os.RemoveAll(staging)
+		return err
+	}
+
+	if err := os.Rename(staging, mirror); err != nil {
+		_ = os.RemoveAll(staging)
+		return fmt.Errorf("promote repository clone: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes the worktree and unregisters it from the mirror.
+func (w *Worktree) Remove(ctx context.Context) error {
+	unlock, err := lockFile(w.mirror + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	removeWorktree(ctx, w.mirror, w.Path)
+
+	if _, err := os.Stat(w.Path); err == nil {
+		return fmt.Errorf("remove worktree %s: still present", w.Path)
+	}
+
+	return nil
+}
+
+// removeWorktree removes a worktree directory and prunes its registration,
+// tolerating one that git no longer knows about.
+func removeWorktree(ctx context.Context, mirror, worktreePath string) {
+	if _, err := os.Stat(worktreePath); err != nil {
+		return
+	}
+
+	if _, err := git(ctx, mirror, "worktree", "remove", "--force", worktreePath); err != nil {
+		_ = os.RemoveAll(worktreePath)
+		_, _ = git(ctx, mirror, "worktree", "prune")
+	}
+}
+
+// git runs a git command in dir (or the current directory when dir is empty)
+// with prompts disabled, returning trimmed stdout.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	cmd, err := executil.CommandContext(ctx, "git", args...)
+	if err != nil {
+		return "", fmt.Errorf("git: %w", err)
+	}
+
+	// A job must never hang waiting for credentials on the operator's terminal.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return "", fmt.Errorf("git %s: %w", gitSubcommand(args), err)
+		}
+
+		return "", fmt.Errorf("git %s: %s: %w", gitSubcommand(args), msg, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+func gitSubcommand(args []string) string {
+	if len(args) > 2 && args[0] == "-C" {
+		return args[2]
+	}
+
+	if len(args) > 0 {
+		return args[0]
+	}
+
+	return ""
+}
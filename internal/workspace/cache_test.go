@@ -0,0 +1,153 @@
+//go:build unix
+
+package workspace
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newOriginRepo creates a repository with one commit on main and a feature
+// branch, and returns its path for use as a clone URL.
+func newOriginRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.email=t@example.com", "-c", "user.name=t"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q", "-b", "main")
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("main\n"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	run("add", "README.md")
+	run("commit", "-q", "-m", "init")
+	run("checkout", "-q", "-b", "feature")
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("feature\n"), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	run("commit", "-q", "-am", "feature")
+	run("checkout", "-q", "main")
+
+	return dir
+}
+
+func TestCheckout_ClonesThenReusesMirror(t *testing.T) {
+	origin := newOriginRepo(t)
+	cache := NewCacheAt(t.TempDir())
+
+	first, err := cache.Checkout(t.Context(), origin, "", "job-1")
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(first.Path, "README.md"))
+	if err != nil {
+		t.Fatalf("read checkout: %v", err)
+	}
+
+	if string(data) != "main\n" {
+		t.Fatalf("default checkout README = %q, want main", data)
+	}
+
+	mirror := cache.mirrorPath(origin)
+	if _, err := os.Stat(mirror); err != nil {
+		t.Fatalf("mirror not created: %v", err)
+	}
+
+	second, err := cache.Checkout(t.Context(), origin, "feature", "job-2")
+	if err != nil {
+		t.Fatalf("Checkout(feature) error = %v", err)
+	}
+
+	data, err = os.ReadFile(filepath.Join(second.Path, "README.md"))
+	if err != nil {
+		t.Fatalf("read checkout: %v", err)
+	}
+
+	if string(data) != "feature\n" {
+		t.Fatalf("feature checkout README = %q, want feature", data)
+	}
+
+	if err := first.Remove(t.Context()); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if _, err := os.Stat(first.Path); !os.IsNotExist(err) {
+		t.Fatalf("worktree still present after Remove (err = %v)", err)
+	}
+
+	if _, err := os.Stat(second.Path); err != nil {
+		t.Fatalf("removing one worktree affected another: %v", err)
+	}
+}
+
+func TestCheckout_FetchesNewCommits(t *testing.T) {
+	origin := newOriginRepo(t)
+	cache := NewCacheAt(t.TempDir())
+
+	wt, err := cache.Checkout(t.Context(), origin, "main", "job-1")
+	if err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	cmd := exec.Command("git", "-C", origin, "-c", "user.email=t@example.com", "-c", "user.name=t",
+		"commit", "-q", "--allow-empty", "-m", "second")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit: %v\n%s", err, out)
+	}
+
+	// Reusing the job name replaces the leftover worktree.
+	again, err := cache.Checkout(t.Context(), origin, "main", "job-1")
+	if err != nil {
+		t.Fatalf("Checkout() again error = %v", err)
+	}
+
+	if again.Commit == wt.Commit {
+		t.Fatal("second checkout did not pick up the new commit")
+	}
+}
+
+func TestCheckout_Errors(t *testing.T) {
+	origin := newOriginRepo(t)
+	cache := NewCacheAt(t.TempDir())
+
+	if _, err := cache.Checkout(t.Context(), origin, "no-such-branch", "job-1"); !errors.Is(err, ErrRefNotFound) {
+		t.Fatalf("Checkout(missing ref) error = %v, want ErrRefNotFound", err)
+	}
+
+	if _, err := cache.Checkout(t.Context(), origin, "", "../escape"); err == nil {
+		t.Fatal("Checkout() accepted a worktree name with a path separator")
+	}
+
+	_, err := cache.Checkout(t.Context(), filepath.Join(t.TempDir(), "missing"), "", "job-2")
+	if err == nil || !strings.Contains(err.Error(), "git clone") {
+		t.Fatalf("Checkout(missing repo) error = %v, want git clone failure", err)
+	}
+
+	entries, _ := os.ReadDir(filepath.Join(cache.root, reposDir))
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".partial.") {
+			t.Fatalf("failed clone left staging directory %s", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package workspace
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile takes an exclusive lock on path, so workers sharing the cache do
+// not update the same mirror concurrently.
+func lockFile(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600) //nolint:gosec // path is derived from the cache root
+	if err != nil {
+		return nil, fmt.Errorf("open workspace lock: %w", err)
+	}
+
+	handle := windows.Handle(file.Fd())
+	overlapped := new(windows.Overlapped)
+
+	if err := windows.LockFileEx(handle, windows.LOCKFILE_EXCLUSIVE_LOCK, 0, math.MaxUint32, math.MaxUint32, overlapped); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("lock workspace: %w", err)
+	}
+
+	return func() {
+		_ = windows.UnlockFileEx(handle, 0, math.MaxUint32, math.MaxUint32, overlapped)
+		_ = file.Close()
+	}, nil
+}